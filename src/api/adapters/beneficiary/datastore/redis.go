@@ -0,0 +1,165 @@
+package datastore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	internal "github.com/quabynah-bilson/quantia/internal/beneficiary"
+	pkg "github.com/quabynah-bilson/quantia/pkg/beneficiary"
+	"log"
+	"strings"
+	"time"
+)
+
+// RedisBeneficiaryDatabase is the implementation of the beneficiary Database interface for Redis.
+type RedisBeneficiaryDatabase struct {
+	client *redis.Client
+	pkg.Database
+}
+
+// WithRedisBeneficiaryDatabase creates a new RedisBeneficiaryDatabase.
+func WithRedisBeneficiaryDatabase(connectionString string) internal.RepositoryConfiguration {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// connect to the database
+	client := redis.NewClient(&redis.Options{
+		Addr: connectionString,
+		DB:   0,
+	})
+
+	// ping the database to check if the connection is working
+	if err := client.Ping(ctx).Err(); err != nil {
+		log.Printf("error pinging Redis: %v", err)
+		return nil
+	}
+
+	return func(r *internal.Repository) error {
+		r.DB = &RedisBeneficiaryDatabase{client: client}
+		return nil
+	}
+}
+
+// CreateBeneficiary stores a new beneficiary and indexes it under its account and destination.
+func (db *RedisBeneficiaryDatabase) CreateBeneficiary(beneficiary *pkg.Beneficiary) error {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	beneficiaryJSON, err := json.Marshal(beneficiary)
+	if err != nil {
+		return pkg.ErrFailedToSaveBeneficiary
+	}
+
+	// claim the destination first so that the same payee is never saved twice by an account
+	claimed, err := db.client.SetNX(ctx, destinationKey(beneficiary), beneficiary.ID, 0).Result()
+	if err != nil {
+		log.Printf("error claiming beneficiary destination: %v", err)
+		return pkg.ErrFailedToSaveBeneficiary
+	}
+	if !claimed {
+		return pkg.ErrBeneficiaryAlreadyExists
+	}
+
+	pipe := db.client.TxPipeline()
+	pipe.Set(ctx, beneficiaryKey(beneficiary.ID), beneficiaryJSON, 0)
+	pipe.ZAdd(ctx, accountBeneficiariesKey(beneficiary.AccountID), &redis.Z{
+		Score:  float64(beneficiary.CreatedAt.UnixNano()),
+		Member: beneficiary.ID,
+	})
+	if _, err = pipe.Exec(ctx); err != nil {
+		log.Printf("error saving beneficiary: %v", err)
+		db.client.Del(ctx, destinationKey(beneficiary))
+		return pkg.ErrFailedToSaveBeneficiary
+	}
+
+	return nil
+}
+
+// GetBeneficiary gets a beneficiary by ID.
+func (db *RedisBeneficiaryDatabase) GetBeneficiary(id string) (*pkg.Beneficiary, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	value, err := db.client.Get(ctx, beneficiaryKey(id)).Result()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			log.Printf("error getting beneficiary: %v", err)
+		}
+		return nil, pkg.ErrBeneficiaryNotFound
+	}
+
+	var beneficiary pkg.Beneficiary
+	if err := json.Unmarshal([]byte(value), &beneficiary); err != nil {
+		log.Printf("error unmarshalling beneficiary: %v", err)
+		return nil, pkg.ErrBeneficiaryNotFound
+	}
+
+	return &beneficiary, nil
+}
+
+// GetBeneficiaries gets the beneficiaries saved by an account, oldest first.
+func (db *RedisBeneficiaryDatabase) GetBeneficiaries(accountID string) ([]*pkg.Beneficiary, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ids, err := db.client.ZRange(ctx, accountBeneficiariesKey(accountID), 0, -1).Result()
+	if err != nil {
+		log.Printf("error getting beneficiaries: %v", err)
+		return nil, err
+	}
+
+	beneficiaries := make([]*pkg.Beneficiary, 0, len(ids))
+	for _, id := range ids {
+		beneficiary, err := db.GetBeneficiary(id)
+		if err != nil {
+			continue
+		}
+		beneficiaries = append(beneficiaries, beneficiary)
+	}
+
+	return beneficiaries, nil
+}
+
+// DeleteBeneficiary deletes a beneficiary and its indexes.
+func (db *RedisBeneficiaryDatabase) DeleteBeneficiary(id string) error {
+	beneficiary, err := db.GetBeneficiary(id)
+	if err != nil {
+		return err
+	}
+
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	pipe := db.client.TxPipeline()
+	pipe.Del(ctx, beneficiaryKey(id), destinationKey(beneficiary))
+	pipe.ZRem(ctx, accountBeneficiariesKey(beneficiary.AccountID), id)
+	if _, err = pipe.Exec(ctx); err != nil {
+		log.Printf("error deleting beneficiary: %v", err)
+		return pkg.ErrFailedToSaveBeneficiary
+	}
+
+	return nil
+}
+
+// beneficiaryKey returns the key holding the beneficiary with the given ID.
+func beneficiaryKey(id string) string {
+	return "beneficiary:" + id
+}
+
+// accountBeneficiariesKey returns the key of the sorted set of an account's beneficiaries.
+func accountBeneficiariesKey(accountID string) string {
+	return "account:" + accountID + ":beneficiaries"
+}
+
+// destinationKey returns the key claiming a destination for the beneficiary's account.
+func destinationKey(beneficiary *pkg.Beneficiary) string {
+	return strings.Join([]string{
+		"account", beneficiary.AccountID, "beneficiary", string(beneficiary.DestinationType), beneficiary.BankCode, beneficiary.Destination,
+	}, ":")
+}
@@ -0,0 +1,34 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"github.com/quabynah-bilson/quantia/pkg/account"
+	pkg "github.com/quabynah-bilson/quantia/pkg/beneficiary"
+	"log"
+)
+
+// AccountResolver confirms the holders of internal accounts
+type AccountResolver struct {
+	accountRepo account.Repository
+	pkg.NameResolver
+}
+
+// NewAccountResolver creates a new internal account resolver
+func NewAccountResolver(accountRepo account.Repository) *AccountResolver {
+	return &AccountResolver{accountRepo: accountRepo}
+}
+
+// ResolveName returns the name of the holder of the account with the given ID
+func (r *AccountResolver) ResolveName(_ context.Context, destination, _ string) (string, error) {
+	acc, err := r.accountRepo.Find(destination)
+	if err != nil {
+		if errors.Is(err, account.ErrAccountNotFound) || errors.Is(err, account.ErrInvalidID) {
+			return "", pkg.ErrDestinationNotFound
+		}
+		log.Printf("error finding account %s: %v", destination, err)
+		return "", pkg.ErrResolverUnavailable
+	}
+
+	return acc.Username, nil
+}
@@ -0,0 +1,73 @@
+package resolver
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	pkg "github.com/quabynah-bilson/quantia/pkg/beneficiary"
+	"io"
+	"os"
+	"strings"
+)
+
+// DirectoryResolver confirms the holders of bank accounts from a directory of known accounts. It stands in
+// for a bank account verification service until one is integrated.
+type DirectoryResolver struct {
+	names map[string]string
+	pkg.NameResolver
+}
+
+// NewDirectoryResolver creates a new bank account resolver from a map of "bankCode:accountNumber" to holder name
+func NewDirectoryResolver(names map[string]string) *DirectoryResolver {
+	return &DirectoryResolver{names: names}
+}
+
+// LoadDirectoryResolver creates a new bank account resolver from a CSV file with the columns
+// bank_code, account_number and name
+func LoadDirectoryResolver(path string) (*DirectoryResolver, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = 3
+	reader.TrimLeadingSpace = true
+
+	names := make(map[string]string)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// skip the header
+		if record[0] == "bank_code" {
+			continue
+		}
+		names[directoryKey(record[0], record[1])] = record[2]
+	}
+
+	return NewDirectoryResolver(names), nil
+}
+
+// ResolveName returns the name of the holder of the account at the bank
+func (r *DirectoryResolver) ResolveName(_ context.Context, destination, bankCode string) (string, error) {
+	name, ok := r.names[directoryKey(bankCode, destination)]
+	if !ok {
+		return "", pkg.ErrDestinationNotFound
+	}
+
+	return name, nil
+}
+
+// directoryKey returns the key of an account in the directory
+func directoryKey(bankCode, accountNumber string) string {
+	return strings.TrimSpace(bankCode) + ":" + strings.TrimSpace(accountNumber)
+}
@@ -0,0 +1,40 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	pkg "github.com/quabynah-bilson/quantia/pkg/beneficiary"
+	"github.com/quabynah-bilson/quantia/pkg/payment"
+	"log"
+)
+
+// AccountHolderLookup is the interface implemented by providers that expose the names of their account holders
+type AccountHolderLookup interface {
+	// AccountHolderName returns the name registered for a wallet number
+	AccountHolderName(ctx context.Context, msisdn string) (string, error)
+}
+
+// WalletResolver confirms the holders of mobile money wallets through the provider
+type WalletResolver struct {
+	lookup AccountHolderLookup
+	pkg.NameResolver
+}
+
+// NewWalletResolver creates a new mobile wallet resolver
+func NewWalletResolver(lookup AccountHolderLookup) *WalletResolver {
+	return &WalletResolver{lookup: lookup}
+}
+
+// ResolveName returns the name registered for the wallet number
+func (r *WalletResolver) ResolveName(ctx context.Context, destination, _ string) (string, error) {
+	name, err := r.lookup.AccountHolderName(ctx, destination)
+	if err != nil {
+		if errors.Is(err, payment.ErrAccountHolderNotFound) {
+			return "", pkg.ErrDestinationNotFound
+		}
+		log.Printf("error looking up wallet %s: %v", destination, err)
+		return "", pkg.ErrResolverUnavailable
+	}
+
+	return name, nil
+}
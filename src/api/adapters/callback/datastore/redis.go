@@ -0,0 +1,126 @@
+package datastore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	internal "github.com/quabynah-bilson/quantia/internal/callback"
+	pkg "github.com/quabynah-bilson/quantia/pkg/callback"
+	"log"
+	"time"
+)
+
+// RedisCallbackDatabase is the implementation of the callback Database interface for Redis.
+type RedisCallbackDatabase struct {
+	client *redis.Client
+	pkg.Database
+}
+
+// WithRedisCallbackDatabase creates a new RedisCallbackDatabase.
+func WithRedisCallbackDatabase(connectionString string) internal.RepositoryConfiguration {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// connect to the database
+	client := redis.NewClient(&redis.Options{
+		Addr: connectionString,
+		DB:   0,
+	})
+
+	// ping the database to check if the connection is working
+	if err := client.Ping(ctx).Err(); err != nil {
+		log.Printf("error pinging Redis: %v", err)
+		return nil
+	}
+
+	return func(r *internal.Repository) error {
+		r.DB = &RedisCallbackDatabase{client: client}
+		return nil
+	}
+}
+
+// SaveCallback creates or replaces a callback.
+func (db *RedisCallbackDatabase) SaveCallback(callback *pkg.Callback) error {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	callbackJSON, err := json.Marshal(callback)
+	if err != nil {
+		return pkg.ErrFailedToSaveCallback
+	}
+
+	if err = db.client.Set(ctx, callbackKey(callback.ID), callbackJSON, 0).Err(); err != nil {
+		log.Printf("error saving callback: %v", err)
+		return pkg.ErrFailedToSaveCallback
+	}
+
+	return nil
+}
+
+// GetCallback gets a callback by ID.
+func (db *RedisCallbackDatabase) GetCallback(id string) (*pkg.Callback, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	value, err := db.client.Get(ctx, callbackKey(id)).Result()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			log.Printf("error getting callback: %v", err)
+		}
+		return nil, pkg.ErrCallbackNotFound
+	}
+
+	var callback pkg.Callback
+	if err := json.Unmarshal([]byte(value), &callback); err != nil {
+		log.Printf("error unmarshalling callback: %v", err)
+		return nil, pkg.ErrCallbackNotFound
+	}
+
+	return &callback, nil
+}
+
+// ClaimEvent records the callback handling an event, unless another callback already did.
+func (db *RedisCallbackDatabase) ClaimEvent(provider, eventID, callbackID string) error {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	claimed, err := db.client.SetNX(ctx, eventKey(provider, eventID), callbackID, 0).Result()
+	if err != nil {
+		log.Printf("error claiming callback event: %v", err)
+		return pkg.ErrFailedToSaveCallback
+	}
+	if !claimed {
+		return pkg.ErrDuplicateCallback
+	}
+
+	return nil
+}
+
+// ReleaseEvent gives up the claim on an event.
+func (db *RedisCallbackDatabase) ReleaseEvent(provider, eventID string) error {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := db.client.Del(ctx, eventKey(provider, eventID)).Err(); err != nil {
+		log.Printf("error releasing callback event: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// callbackKey returns the key holding the callback with the given ID.
+func callbackKey(id string) string {
+	return "callback:" + id
+}
+
+// eventKey returns the key holding the ID of the callback that claimed an event of a provider.
+func eventKey(provider, eventID string) string {
+	return "callback:event:" + provider + ":" + eventID
+}
@@ -0,0 +1,311 @@
+package datastore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	internal "github.com/quabynah-bilson/quantia/internal/card"
+	pkg "github.com/quabynah-bilson/quantia/pkg/card"
+	"log"
+	"time"
+)
+
+// RedisCardDatabase is the implementation of the card Database interface for Redis.
+type RedisCardDatabase struct {
+	client *redis.Client
+	pkg.Database
+}
+
+// WithRedisCardDatabase creates a new RedisCardDatabase.
+func WithRedisCardDatabase(connectionString string) internal.RepositoryConfiguration {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// connect to the database
+	client := redis.NewClient(&redis.Options{
+		Addr: connectionString,
+		DB:   0,
+	})
+
+	// ping the database to check if the connection is working
+	if err := client.Ping(ctx).Err(); err != nil {
+		log.Printf("error pinging Redis: %v", err)
+		return nil
+	}
+
+	return func(r *internal.Repository) error {
+		r.DB = &RedisCardDatabase{client: client}
+		return nil
+	}
+}
+
+// SaveCard creates or replaces a card and updates its indexes.
+func (db *RedisCardDatabase) SaveCard(card *pkg.Card) error {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cardJSON, err := json.Marshal(card)
+	if err != nil {
+		return pkg.ErrFailedToSaveCard
+	}
+
+	// the record and its indexes change together
+	pipe := db.client.TxPipeline()
+	pipe.Set(ctx, cardKey(card.ID), cardJSON, 0)
+	pipe.Set(ctx, tokenCardKey(card.Token), card.ID, 0)
+	pipe.ZAdd(ctx, accountCardsKey(card.AccountID), &redis.Z{Score: float64(card.CreatedAt.UnixNano()), Member: card.ID})
+	if _, err = pipe.Exec(ctx); err != nil {
+		log.Printf("error saving card: %v", err)
+		return pkg.ErrFailedToSaveCard
+	}
+
+	return nil
+}
+
+// GetCard gets a card by ID.
+func (db *RedisCardDatabase) GetCard(id string) (*pkg.Card, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	value, err := db.client.Get(ctx, cardKey(id)).Result()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			log.Printf("error getting card: %v", err)
+		}
+		return nil, pkg.ErrCardNotFound
+	}
+
+	var card pkg.Card
+	if err := json.Unmarshal([]byte(value), &card); err != nil {
+		log.Printf("error unmarshalling card: %v", err)
+		return nil, pkg.ErrCardNotFound
+	}
+
+	return &card, nil
+}
+
+// GetCardByToken gets the card of a vault token.
+func (db *RedisCardDatabase) GetCardByToken(token string) (*pkg.Card, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	id, err := db.client.Get(ctx, tokenCardKey(token)).Result()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			log.Printf("error getting card by token: %v", err)
+		}
+		return nil, pkg.ErrCardNotFound
+	}
+
+	return db.GetCard(id)
+}
+
+// GetAccountCards gets the cards of an account, oldest first.
+func (db *RedisCardDatabase) GetAccountCards(accountID string) ([]*pkg.Card, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ids, err := db.client.ZRange(ctx, accountCardsKey(accountID), 0, -1).Result()
+	if err != nil {
+		log.Printf("error getting account cards: %v", err)
+		return nil, err
+	}
+
+	cards := make([]*pkg.Card, 0, len(ids))
+	for _, value := range db.getAll(ctx, ids, cardKey) {
+		var card pkg.Card
+		if err := json.Unmarshal([]byte(value), &card); err != nil {
+			log.Printf("error unmarshalling card: %v", err)
+			continue
+		}
+		cards = append(cards, &card)
+	}
+
+	return cards, nil
+}
+
+// SaveAuthorization creates an authorization and indexes it by its card.
+func (db *RedisCardDatabase) SaveAuthorization(authorization *pkg.Authorization) error {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	authorizationJSON, err := json.Marshal(authorization)
+	if err != nil {
+		return pkg.ErrFailedToSaveAuthorization
+	}
+
+	pipe := db.client.TxPipeline()
+	pipe.Set(ctx, authorizationKey(authorization.ID), authorizationJSON, 0)
+	pipe.ZAdd(ctx, cardAuthorizationsKey(authorization.CardID), &redis.Z{Score: float64(authorization.CreatedAt.UnixNano()), Member: authorization.ID})
+	if _, err = pipe.Exec(ctx); err != nil {
+		log.Printf("error saving authorization: %v", err)
+		return pkg.ErrFailedToSaveAuthorization
+	}
+
+	return nil
+}
+
+// GetCardAuthorizations gets the authorizations of a card, newest first.
+func (db *RedisCardDatabase) GetCardAuthorizations(cardID string) ([]*pkg.Authorization, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ids, err := db.client.ZRevRange(ctx, cardAuthorizationsKey(cardID), 0, -1).Result()
+	if err != nil {
+		log.Printf("error getting card authorizations: %v", err)
+		return nil, err
+	}
+
+	authorizations := make([]*pkg.Authorization, 0, len(ids))
+	for _, value := range db.getAll(ctx, ids, authorizationKey) {
+		var authorization pkg.Authorization
+		if err := json.Unmarshal([]byte(value), &authorization); err != nil {
+			log.Printf("error unmarshalling authorization: %v", err)
+			continue
+		}
+		authorizations = append(authorizations, &authorization)
+	}
+
+	return authorizations, nil
+}
+
+// SaveVaultEntry stores a vault entry. The fingerprint is claimed first, so that a card number is stored once.
+func (db *RedisCardDatabase) SaveVaultEntry(entry *pkg.VaultEntry) error {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	entryJSON, err := json.Marshal(entry)
+	if err != nil {
+		return pkg.ErrFailedToSaveSecret
+	}
+
+	claimed, err := db.client.SetNX(ctx, fingerprintKey(entry.Fingerprint), entry.Token, 0).Result()
+	if err != nil {
+		log.Printf("error claiming card fingerprint: %v", err)
+		return pkg.ErrFailedToSaveSecret
+	}
+	if !claimed {
+		return pkg.ErrDuplicatePAN
+	}
+
+	if err = db.client.Set(ctx, vaultKey(entry.Token), entryJSON, 0).Err(); err != nil {
+		log.Printf("error saving vault entry: %v", err)
+
+		// give the fingerprint back so that the card number can be stored again
+		db.client.Del(ctx, fingerprintKey(entry.Fingerprint))
+		return pkg.ErrFailedToSaveSecret
+	}
+
+	return nil
+}
+
+// GetVaultEntry gets the vault entry of a token.
+func (db *RedisCardDatabase) GetVaultEntry(token string) (*pkg.VaultEntry, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	value, err := db.client.Get(ctx, vaultKey(token)).Result()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			log.Printf("error getting vault entry: %v", err)
+		}
+		return nil, pkg.ErrSecretNotFound
+	}
+
+	var entry pkg.VaultEntry
+	if err := json.Unmarshal([]byte(value), &entry); err != nil {
+		log.Printf("error unmarshalling vault entry: %v", err)
+		return nil, pkg.ErrSecretNotFound
+	}
+
+	return &entry, nil
+}
+
+// GetVaultToken gets the token of a PAN fingerprint.
+func (db *RedisCardDatabase) GetVaultToken(fingerprint string) (string, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	token, err := db.client.Get(ctx, fingerprintKey(fingerprint)).Result()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			log.Printf("error getting vault token: %v", err)
+		}
+		return "", pkg.ErrSecretNotFound
+	}
+
+	return token, nil
+}
+
+// getAll gets the values of the records with the given IDs, skipping the missing ones.
+func (db *RedisCardDatabase) getAll(ctx context.Context, ids []string, key func(string) string) []string {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, key(id))
+	}
+
+	values, err := db.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		log.Printf("error getting records: %v", err)
+		return nil
+	}
+
+	found := make([]string, 0, len(values))
+	for _, value := range values {
+		if raw, ok := value.(string); ok {
+			found = append(found, raw)
+		}
+	}
+
+	return found
+}
+
+// cardKey returns the key holding the card with the given ID.
+func cardKey(id string) string {
+	return "card:" + id
+}
+
+// tokenCardKey returns the key holding the ID of the card of a vault token.
+func tokenCardKey(token string) string {
+	return "card:token:" + token
+}
+
+// accountCardsKey returns the key of the sorted set holding the IDs of an account's cards.
+func accountCardsKey(accountID string) string {
+	return "card:account:" + accountID
+}
+
+// authorizationKey returns the key holding the authorization with the given ID.
+func authorizationKey(id string) string {
+	return "card:authorization:" + id
+}
+
+// cardAuthorizationsKey returns the key of the sorted set holding the IDs of a card's authorizations.
+func cardAuthorizationsKey(cardID string) string {
+	return "card:authorizations:" + cardID
+}
+
+// vaultKey returns the key holding the vault entry of a token.
+func vaultKey(token string) string {
+	return "vault:entry:" + token
+}
+
+// fingerprintKey returns the key holding the token of a PAN fingerprint.
+func fingerprintKey(fingerprint string) string {
+	return "vault:fingerprint:" + fingerprint
+}
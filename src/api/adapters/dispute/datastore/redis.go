@@ -0,0 +1,171 @@
+package datastore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	internal "github.com/quabynah-bilson/quantia/internal/dispute"
+	pkg "github.com/quabynah-bilson/quantia/pkg/dispute"
+	"log"
+	"strconv"
+	"time"
+)
+
+// overdueDisputesKey is the sorted set of the disputes awaiting evidence, scored by their deadline
+const overdueDisputesKey = "disputes:awaiting_evidence"
+
+// RedisDisputeDatabase is the implementation of the dispute Database interface for Redis.
+type RedisDisputeDatabase struct {
+	client *redis.Client
+	pkg.Database
+}
+
+// WithRedisDisputeDatabase creates a new RedisDisputeDatabase.
+func WithRedisDisputeDatabase(connectionString string) internal.RepositoryConfiguration {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// connect to the database
+	client := redis.NewClient(&redis.Options{
+		Addr: connectionString,
+		DB:   0,
+	})
+
+	// ping the database to check if the connection is working
+	if err := client.Ping(ctx).Err(); err != nil {
+		log.Printf("error pinging Redis: %v", err)
+		return nil
+	}
+
+	return func(r *internal.Repository) error {
+		r.DB = &RedisDisputeDatabase{client: client}
+		return nil
+	}
+}
+
+// SaveDispute creates or replaces a dispute and updates its indexes.
+func (db *RedisDisputeDatabase) SaveDispute(dispute *pkg.Dispute) error {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	disputeJSON, err := json.Marshal(dispute)
+	if err != nil {
+		return pkg.ErrFailedToSaveDispute
+	}
+
+	// the record and its indexes change together
+	pipe := db.client.TxPipeline()
+	pipe.Set(ctx, disputeKey(dispute.ID), disputeJSON, 0)
+	pipe.ZAdd(ctx, transactionDisputesKey(dispute.TransactionID), &redis.Z{Score: float64(dispute.CreatedAt.UnixNano()), Member: dispute.ID})
+	if dispute.AwaitsEvidence() {
+		pipe.ZAdd(ctx, overdueDisputesKey, &redis.Z{Score: float64(dispute.DueBy.Unix()), Member: dispute.ID})
+	} else {
+		pipe.ZRem(ctx, overdueDisputesKey, dispute.ID)
+	}
+	if _, err = pipe.Exec(ctx); err != nil {
+		log.Printf("error saving dispute: %v", err)
+		return pkg.ErrFailedToSaveDispute
+	}
+
+	return nil
+}
+
+// GetDispute gets a dispute by ID.
+func (db *RedisDisputeDatabase) GetDispute(id string) (*pkg.Dispute, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	value, err := db.client.Get(ctx, disputeKey(id)).Result()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			log.Printf("error getting dispute: %v", err)
+		}
+		return nil, pkg.ErrDisputeNotFound
+	}
+
+	var dispute pkg.Dispute
+	if err := json.Unmarshal([]byte(value), &dispute); err != nil {
+		log.Printf("error unmarshalling dispute: %v", err)
+		return nil, pkg.ErrDisputeNotFound
+	}
+
+	return &dispute, nil
+}
+
+// GetTransactionDisputes gets the disputes of a transaction, oldest first.
+func (db *RedisDisputeDatabase) GetTransactionDisputes(transactionID string) ([]*pkg.Dispute, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ids, err := db.client.ZRange(ctx, transactionDisputesKey(transactionID), 0, -1).Result()
+	if err != nil {
+		log.Printf("error getting transaction disputes: %v", err)
+		return nil, err
+	}
+
+	if len(ids) == 0 {
+		return []*pkg.Dispute{}, nil
+	}
+
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, disputeKey(id))
+	}
+
+	values, err := db.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		log.Printf("error getting transaction disputes: %v", err)
+		return nil, err
+	}
+
+	disputes := make([]*pkg.Dispute, 0, len(values))
+	for _, value := range values {
+		raw, ok := value.(string)
+		if !ok {
+			continue
+		}
+
+		var dispute pkg.Dispute
+		if err := json.Unmarshal([]byte(raw), &dispute); err != nil {
+			log.Printf("error unmarshalling dispute: %v", err)
+			continue
+		}
+		disputes = append(disputes, &dispute)
+	}
+
+	return disputes, nil
+}
+
+// GetOverdueDisputes gets the IDs of the disputes awaiting evidence past their deadline, earliest first.
+func (db *RedisDisputeDatabase) GetOverdueDisputes(now time.Time, limit int) ([]string, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ids, err := db.client.ZRangeByScore(ctx, overdueDisputesKey, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(now.Unix(), 10),
+		Count: int64(limit),
+	}).Result()
+	if err != nil {
+		log.Printf("error getting overdue disputes: %v", err)
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// disputeKey returns the key holding the dispute with the given ID.
+func disputeKey(id string) string {
+	return "dispute:" + id
+}
+
+// transactionDisputesKey returns the key of the sorted set holding the IDs of a transaction's disputes.
+func transactionDisputesKey(transactionID string) string {
+	return "dispute:transaction:" + transactionID
+}
@@ -0,0 +1,118 @@
+package datastore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	internal "github.com/quabynah-bilson/quantia/internal/escrow"
+	pkg "github.com/quabynah-bilson/quantia/pkg/escrow"
+	"log"
+	"time"
+)
+
+// RedisEscrowDatabase is the implementation of the escrow Database interface for Redis.
+type RedisEscrowDatabase struct {
+	client *redis.Client
+	pkg.Database
+}
+
+// WithRedisEscrowDatabase creates a new RedisEscrowDatabase.
+func WithRedisEscrowDatabase(connectionString string) internal.RepositoryConfiguration {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// connect to the database
+	client := redis.NewClient(&redis.Options{
+		Addr: connectionString,
+		DB:   0,
+	})
+
+	// ping the database to check if the connection is working
+	if err := client.Ping(ctx).Err(); err != nil {
+		log.Printf("error pinging Redis: %v", err)
+		return nil
+	}
+
+	return func(r *internal.Repository) error {
+		r.DB = &RedisEscrowDatabase{client: client}
+		return nil
+	}
+}
+
+// SaveEscrow creates or replaces an escrow and indexes it by the transaction funding it.
+func (db *RedisEscrowDatabase) SaveEscrow(escrow *pkg.Escrow) error {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	escrowJSON, err := json.Marshal(escrow)
+	if err != nil {
+		return pkg.ErrFailedToSaveEscrow
+	}
+
+	// the record and its index change together
+	pipe := db.client.TxPipeline()
+	pipe.Set(ctx, escrowKey(escrow.ID), escrowJSON, 0)
+	if escrow.TransactionID != "" {
+		pipe.Set(ctx, transactionKey(escrow.TransactionID), escrow.ID, 0)
+	}
+	if _, err = pipe.Exec(ctx); err != nil {
+		log.Printf("error saving escrow: %v", err)
+		return pkg.ErrFailedToSaveEscrow
+	}
+
+	return nil
+}
+
+// GetEscrow gets an escrow by ID.
+func (db *RedisEscrowDatabase) GetEscrow(id string) (*pkg.Escrow, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	value, err := db.client.Get(ctx, escrowKey(id)).Result()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			log.Printf("error getting escrow: %v", err)
+		}
+		return nil, pkg.ErrEscrowNotFound
+	}
+
+	var escrow pkg.Escrow
+	if err := json.Unmarshal([]byte(value), &escrow); err != nil {
+		log.Printf("error unmarshalling escrow: %v", err)
+		return nil, pkg.ErrEscrowNotFound
+	}
+
+	return &escrow, nil
+}
+
+// GetTransactionEscrow gets the escrow funded by a transaction.
+func (db *RedisEscrowDatabase) GetTransactionEscrow(transactionID string) (*pkg.Escrow, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	id, err := db.client.Get(ctx, transactionKey(transactionID)).Result()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			log.Printf("error getting transaction escrow: %v", err)
+			return nil, err
+		}
+		return nil, pkg.ErrEscrowNotFound
+	}
+
+	return db.GetEscrow(id)
+}
+
+// escrowKey returns the key holding the escrow with the given ID.
+func escrowKey(id string) string {
+	return "escrow:" + id
+}
+
+// transactionKey returns the key holding the ID of the escrow funded by a transaction.
+func transactionKey(transactionID string) string {
+	return "escrow:transaction:" + transactionID
+}
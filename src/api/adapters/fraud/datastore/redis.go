@@ -0,0 +1,218 @@
+package datastore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	internal "github.com/quabynah-bilson/quantia/internal/fraud"
+	pkg "github.com/quabynah-bilson/quantia/pkg/fraud"
+	"log"
+	"strconv"
+	"time"
+)
+
+// attemptRetention is how long attempts are kept for the rapid succession rule
+const attemptRetention = 24 * time.Hour
+
+// reviewQueueKey is the key of the sorted set of transactions awaiting review, scored by creation time
+const reviewQueueKey = "fraud:reviews"
+
+// RedisFraudDatabase is the implementation of the fraud Database interface for Redis.
+type RedisFraudDatabase struct {
+	client *redis.Client
+	pkg.Database
+}
+
+// WithRedisFraudDatabase creates a new RedisFraudDatabase.
+func WithRedisFraudDatabase(connectionString string) internal.RepositoryConfiguration {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// connect to the database
+	client := redis.NewClient(&redis.Options{
+		Addr: connectionString,
+		DB:   0,
+	})
+
+	// ping the database to check if the connection is working
+	if err := client.Ping(ctx).Err(); err != nil {
+		log.Printf("error pinging Redis: %v", err)
+		return nil
+	}
+
+	return func(r *internal.Repository) error {
+		r.DB = &RedisFraudDatabase{client: client}
+		return nil
+	}
+}
+
+// GetProfile gets the history of a subject, counting the payments attempted since the given time.
+func (db *RedisFraudDatabase) GetProfile(subject string, since time.Time) (*pkg.Profile, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	pipe := db.client.Pipeline()
+	devices := pipe.SMembers(ctx, profileKey(subject, "devices"))
+	beneficiaries := pipe.SMembers(ctx, profileKey(subject, "beneficiaries"))
+	countries := pipe.SMembers(ctx, profileKey(subject, "countries"))
+	amounts := pipe.LRange(ctx, profileKey(subject, "amounts"), 0, pkg.MaxProfileAmounts-1)
+	attempts := pipe.ZCount(ctx, profileKey(subject, "attempts"), strconv.FormatInt(since.UnixNano(), 10), "+inf")
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		log.Printf("error getting fraud profile: %v", err)
+		return nil, err
+	}
+
+	profile := &pkg.Profile{
+		Devices:       devices.Val(),
+		Beneficiaries: beneficiaries.Val(),
+		Countries:     countries.Val(),
+		Attempts:      int(attempts.Val()),
+	}
+	for _, value := range amounts.Val() {
+		if amount, err := strconv.ParseFloat(value, 32); err == nil {
+			profile.Amounts = append(profile.Amounts, float32(amount))
+		}
+	}
+
+	return profile, nil
+}
+
+// RecordAttempt records a payment attempted by a subject, forgetting the attempts too old to matter.
+func (db *RedisFraudDatabase) RecordAttempt(subject string, at time.Time) error {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	key := profileKey(subject, "attempts")
+	_, err := db.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		// members are unique so that attempts made at the same instant are all counted
+		pipe.ZAdd(ctx, key, &redis.Z{Score: float64(at.UnixNano()), Member: uuid.NewString()})
+		pipe.ZRemRangeByScore(ctx, key, "-inf", strconv.FormatInt(at.Add(-attemptRetention).UnixNano(), 10))
+		pipe.Expire(ctx, key, attemptRetention)
+		return nil
+	})
+	if err != nil {
+		log.Printf("error recording payment attempt: %v", err)
+		return pkg.ErrFailedToSaveAssessment
+	}
+
+	return nil
+}
+
+// LearnProfile adds the device, beneficiary, country and amount of a trusted payment to the subject's history.
+func (db *RedisFraudDatabase) LearnProfile(signals pkg.Signals) error {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	subject := signals.Subject
+	_, err := db.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		if signals.DeviceID != "" {
+			pipe.SAdd(ctx, profileKey(subject, "devices"), signals.DeviceID)
+		}
+		if signals.Beneficiary != "" {
+			pipe.SAdd(ctx, profileKey(subject, "beneficiaries"), signals.Beneficiary)
+		}
+		if signals.IPCountry != "" {
+			pipe.SAdd(ctx, profileKey(subject, "countries"), signals.IPCountry)
+		}
+		pipe.LPush(ctx, profileKey(subject, "amounts"), strconv.FormatFloat(float64(signals.Amount), 'f', 2, 32))
+		pipe.LTrim(ctx, profileKey(subject, "amounts"), 0, pkg.MaxProfileAmounts-1)
+		return nil
+	})
+	if err != nil {
+		log.Printf("error learning fraud profile: %v", err)
+		return pkg.ErrFailedToSaveAssessment
+	}
+
+	return nil
+}
+
+// SaveAssessment saves an assessment, adding it to the review queue while it awaits review.
+func (db *RedisFraudDatabase) SaveAssessment(assessment *pkg.Assessment) error {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	assessmentJSON, err := json.Marshal(assessment)
+	if err != nil {
+		return pkg.ErrFailedToSaveAssessment
+	}
+
+	_, err = db.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, assessmentKey(assessment.TransactionID), assessmentJSON, 0)
+		if assessment.IsAwaitingReview() {
+			pipe.ZAdd(ctx, reviewQueueKey, &redis.Z{Score: float64(assessment.CreatedAt.UnixNano()), Member: assessment.TransactionID})
+		} else {
+			pipe.ZRem(ctx, reviewQueueKey, assessment.TransactionID)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("error saving fraud assessment: %v", err)
+		return pkg.ErrFailedToSaveAssessment
+	}
+
+	return nil
+}
+
+// GetAssessment gets the assessment of a transaction.
+func (db *RedisFraudDatabase) GetAssessment(transactionID string) (*pkg.Assessment, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	value, err := db.client.Get(ctx, assessmentKey(transactionID)).Result()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			log.Printf("error getting fraud assessment: %v", err)
+		}
+		return nil, pkg.ErrAssessmentNotFound
+	}
+
+	var assessment pkg.Assessment
+	if err := json.Unmarshal([]byte(value), &assessment); err != nil {
+		log.Printf("error unmarshalling fraud assessment: %v", err)
+		return nil, pkg.ErrAssessmentNotFound
+	}
+
+	return &assessment, nil
+}
+
+// GetReviewQueue gets the assessments awaiting review, oldest first.
+func (db *RedisFraudDatabase) GetReviewQueue() ([]*pkg.Assessment, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ids, err := db.client.ZRange(ctx, reviewQueueKey, 0, -1).Result()
+	if err != nil {
+		log.Printf("error getting review queue: %v", err)
+		return nil, err
+	}
+
+	assessments := make([]*pkg.Assessment, 0, len(ids))
+	for _, id := range ids {
+		assessment, err := db.GetAssessment(id)
+		if err != nil {
+			continue
+		}
+		assessments = append(assessments, assessment)
+	}
+
+	return assessments, nil
+}
+
+// profileKey returns the key holding part of the history of a subject.
+func profileKey(subject, part string) string {
+	return "fraud:" + subject + ":" + part
+}
+
+// assessmentKey returns the key holding the assessment of a transaction.
+func assessmentKey(transactionID string) string {
+	return "fraud:assessment:" + transactionID
+}
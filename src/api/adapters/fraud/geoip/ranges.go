@@ -0,0 +1,99 @@
+package geoip
+
+import (
+	"encoding/csv"
+	"errors"
+	pkg "github.com/quabynah-bilson/quantia/pkg/fraud"
+	"io"
+	"net"
+	"os"
+	"strings"
+)
+
+// RangeLocator locates IP addresses from a list of networks and their countries. It stands in for a
+// geolocation service until one is integrated.
+type RangeLocator struct {
+	ranges []countryRange
+	pkg.GeoLocator
+}
+
+// countryRange is a network located in a country
+type countryRange struct {
+	network *net.IPNet
+	country string
+}
+
+// NewRangeLocator creates a new locator from a map of CIDR networks to ISO 3166 alpha-2 country codes
+func NewRangeLocator(networks map[string]string) (*RangeLocator, error) {
+	locator := &RangeLocator{}
+	for cidr, country := range networks {
+		if err := locator.add(cidr, country); err != nil {
+			return nil, err
+		}
+	}
+
+	return locator, nil
+}
+
+// LoadRangeLocator creates a new locator from a CSV file with the columns network (in CIDR notation) and country
+func LoadRangeLocator(path string) (*RangeLocator, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+
+	locator := &RangeLocator{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// skip the header
+		if record[0] == "network" {
+			continue
+		}
+		if err = locator.add(record[0], record[1]); err != nil {
+			return nil, err
+		}
+	}
+
+	return locator, nil
+}
+
+// Country returns the country of the first network containing the IP address, empty when none does
+func (l *RangeLocator) Country(ip string) (string, error) {
+	address := net.ParseIP(strings.TrimSpace(ip))
+	if address == nil {
+		return "", nil
+	}
+
+	for _, r := range l.ranges {
+		if r.network.Contains(address) {
+			return r.country, nil
+		}
+	}
+
+	return "", nil
+}
+
+// add adds a network located in a country
+func (l *RangeLocator) add(cidr, country string) error {
+	_, network, err := net.ParseCIDR(strings.TrimSpace(cidr))
+	if err != nil {
+		return err
+	}
+
+	l.ranges = append(l.ranges, countryRange{network: network, country: strings.ToUpper(strings.TrimSpace(country))})
+	return nil
+}
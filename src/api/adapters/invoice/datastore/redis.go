@@ -0,0 +1,216 @@
+package datastore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	internal "github.com/quabynah-bilson/quantia/internal/invoice"
+	pkg "github.com/quabynah-bilson/quantia/pkg/invoice"
+	"log"
+	"time"
+)
+
+// RedisInvoiceDatabase is the implementation of the invoice Database interface for Redis.
+type RedisInvoiceDatabase struct {
+	client *redis.Client
+	pkg.Database
+}
+
+// WithRedisInvoiceDatabase creates a new RedisInvoiceDatabase.
+func WithRedisInvoiceDatabase(connectionString string) internal.RepositoryConfiguration {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// connect to the database
+	client := redis.NewClient(&redis.Options{
+		Addr: connectionString,
+		DB:   0,
+	})
+
+	// ping the database to check if the connection is working
+	if err := client.Ping(ctx).Err(); err != nil {
+		log.Printf("error pinging Redis: %v", err)
+		return nil
+	}
+
+	return func(r *internal.Repository) error {
+		r.DB = &RedisInvoiceDatabase{client: client}
+		return nil
+	}
+}
+
+// SaveInvoice creates or replaces an invoice.
+func (db *RedisInvoiceDatabase) SaveInvoice(invoice *pkg.Invoice) error {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	invoiceJSON, err := json.Marshal(invoice)
+	if err != nil {
+		return pkg.ErrFailedToSaveInvoice
+	}
+
+	if err = db.client.Set(ctx, invoiceKey(invoice.ID), invoiceJSON, 0).Err(); err != nil {
+		log.Printf("error saving invoice: %v", err)
+		return pkg.ErrFailedToSaveInvoice
+	}
+
+	return nil
+}
+
+// GetInvoice gets an invoice by ID.
+func (db *RedisInvoiceDatabase) GetInvoice(id string) (*pkg.Invoice, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	value, err := db.client.Get(ctx, invoiceKey(id)).Result()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			log.Printf("error getting invoice: %v", err)
+		}
+		return nil, pkg.ErrInvoiceNotFound
+	}
+
+	var invoice pkg.Invoice
+	if err := json.Unmarshal([]byte(value), &invoice); err != nil {
+		log.Printf("error unmarshalling invoice: %v", err)
+		return nil, pkg.ErrInvoiceNotFound
+	}
+
+	return &invoice, nil
+}
+
+// SaveLink creates or replaces a payment link.
+func (db *RedisInvoiceDatabase) SaveLink(link *pkg.Link) error {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	linkJSON, err := json.Marshal(link)
+	if err != nil {
+		return pkg.ErrFailedToSaveInvoice
+	}
+
+	if err = db.client.Set(ctx, linkKey(link.ID), linkJSON, 0).Err(); err != nil {
+		log.Printf("error saving payment link: %v", err)
+		return pkg.ErrFailedToSaveInvoice
+	}
+
+	return nil
+}
+
+// GetLink gets a payment link by ID.
+func (db *RedisInvoiceDatabase) GetLink(id string) (*pkg.Link, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	value, err := db.client.Get(ctx, linkKey(id)).Result()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			log.Printf("error getting payment link: %v", err)
+		}
+		return nil, pkg.ErrLinkNotFound
+	}
+
+	var link pkg.Link
+	if err := json.Unmarshal([]byte(value), &link); err != nil {
+		log.Printf("error unmarshalling payment link: %v", err)
+		return nil, pkg.ErrLinkNotFound
+	}
+
+	return &link, nil
+}
+
+// SavePayment creates or replaces the record of a payment made through a link and lists it under the link.
+func (db *RedisInvoiceDatabase) SavePayment(payment *pkg.Payment) error {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	paymentJSON, err := json.Marshal(payment)
+	if err != nil {
+		return pkg.ErrFailedToSaveInvoice
+	}
+
+	// the record and its index change together
+	pipe := db.client.TxPipeline()
+	pipe.Set(ctx, paymentKey(payment.TransactionID), paymentJSON, 0)
+	pipe.ZAdd(ctx, linkPaymentsKey(payment.LinkID), &redis.Z{Score: float64(payment.CreatedAt.UnixNano()), Member: payment.TransactionID})
+	if _, err = pipe.Exec(ctx); err != nil {
+		log.Printf("error saving payment link payment: %v", err)
+		return pkg.ErrFailedToSaveInvoice
+	}
+
+	return nil
+}
+
+// GetPayment gets the record of a payment by its transaction ID.
+func (db *RedisInvoiceDatabase) GetPayment(transactionID string) (*pkg.Payment, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	value, err := db.client.Get(ctx, paymentKey(transactionID)).Result()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			log.Printf("error getting payment link payment: %v", err)
+		}
+		return nil, pkg.ErrPaymentNotFound
+	}
+
+	var payment pkg.Payment
+	if err := json.Unmarshal([]byte(value), &payment); err != nil {
+		log.Printf("error unmarshalling payment link payment: %v", err)
+		return nil, pkg.ErrPaymentNotFound
+	}
+
+	return &payment, nil
+}
+
+// GetLinkPayments gets the payments made through a link, oldest first.
+func (db *RedisInvoiceDatabase) GetLinkPayments(linkID string) ([]*pkg.Payment, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ids, err := db.client.ZRange(ctx, linkPaymentsKey(linkID), 0, -1).Result()
+	if err != nil {
+		log.Printf("error getting payment link payments: %v", err)
+		return nil, err
+	}
+
+	payments := make([]*pkg.Payment, 0, len(ids))
+	for _, id := range ids {
+		payment, err := db.GetPayment(id)
+		if err != nil {
+			continue
+		}
+		payments = append(payments, payment)
+	}
+
+	return payments, nil
+}
+
+// invoiceKey returns the key holding the invoice with the given ID.
+func invoiceKey(id string) string {
+	return "invoice:" + id
+}
+
+// linkKey returns the key holding the payment link with the given ID.
+func linkKey(id string) string {
+	return "payment_link:" + id
+}
+
+// paymentKey returns the key holding the record of the payment made by the given transaction.
+func paymentKey(transactionID string) string {
+	return "payment_link:payment:" + transactionID
+}
+
+// linkPaymentsKey returns the key of the sorted set of the transactions made through a link, scored by creation time.
+func linkPaymentsKey(linkID string) string {
+	return linkKey(linkID) + ":payments"
+}
@@ -0,0 +1,386 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5"
+	internalLedger "github.com/quabynah-bilson/quantia/internal/ledger"
+	"github.com/quabynah-bilson/quantia/migrations"
+	pkgLedger "github.com/quabynah-bilson/quantia/pkg/ledger"
+	"log"
+	"sync"
+	"time"
+)
+
+// LedgerPostgresDatabase is the struct that wraps the basic ledger database operations for PostgreSQL.
+type LedgerPostgresDatabase struct {
+	// mu serializes access to conn, which is not safe for concurrent use
+	mu   sync.Mutex
+	conn *pgx.Conn
+	pkgLedger.Database
+}
+
+// WithPostgresLedgerDatabase creates a new RepositoryConfiguration for PostgreSQL.
+func WithPostgresLedgerDatabase(connectionString string) internalLedger.RepositoryConfiguration {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// connect to the database
+	conn, err := pgx.Connect(ctx, connectionString)
+	if err != nil {
+		log.Printf("error connecting to database: %v", err)
+		return nil
+	}
+
+	// ping the database to ensure that the connection is alive
+	if err := conn.Ping(ctx); err != nil {
+		log.Printf("error pinging database: %v", err)
+		return nil
+	}
+
+	// perform migrations
+	errChan := make(chan error)
+	go migrations.PerformMigrations(conn, errChan)
+	if err = <-errChan; err != nil {
+		log.Printf("error performing migrations: %v", err)
+		return nil
+	}
+
+	return func(r *internalLedger.Repository) error {
+		r.DB = &LedgerPostgresDatabase{conn: conn}
+		return nil
+	}
+}
+
+// PostEntries stores the entries of a posting atomically.
+func (d *LedgerPostgresDatabase) PostEntries(entries []*pkgLedger.Entry) error {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	tx, err := d.conn.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	if err != nil {
+		log.Printf("error starting ledger transaction: %v", err)
+		return pkgLedger.ErrFailedToPostEntries
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err = insertEntries(ctx, tx, entries); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Printf("error committing ledger entries: %v", err)
+		return pkgLedger.ErrFailedToPostEntries
+	}
+
+	return nil
+}
+
+// GetBalance computes the current and available balance of an account, with its overdraft limit.
+func (d *LedgerPostgresDatabase) GetBalance(accountID string) (*pkgLedger.Balance, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return getBalance(ctx, d.conn, accountID)
+}
+
+// SetOverdraftLimit creates or replaces the overdraft limit of an account.
+func (d *LedgerPostgresDatabase) SetOverdraftLimit(accountID string, limit float32) error {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, err := d.conn.Exec(ctx,
+		"INSERT INTO ledger_overdrafts (account_id, overdraft_limit, updated_at) VALUES ($1, $2, $3) ON CONFLICT (account_id) DO UPDATE SET overdraft_limit = EXCLUDED.overdraft_limit, updated_at = EXCLUDED.updated_at",
+		accountID, limit, time.Now().UTC()); err != nil {
+		log.Printf("error saving overdraft limit: %v", err)
+		return pkgLedger.ErrFailedToSaveOverdraftLimit
+	}
+
+	return nil
+}
+
+// PlaceHold stores an active hold if the account's available balance and overdraft limit cover it.
+func (d *LedgerPostgresDatabase) PlaceHold(hold *pkgLedger.Hold) error {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	tx, err := d.conn.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	if err != nil {
+		log.Printf("error starting ledger transaction: %v", err)
+		return pkgLedger.ErrFailedToSaveHold
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	balance, err := getBalance(ctx, tx, hold.AccountID)
+	if err != nil {
+		return err
+	}
+	if balance.Spendable() < hold.Amount {
+		return pkgLedger.ErrInsufficientFunds
+	}
+
+	if _, err = tx.Exec(ctx,
+		"INSERT INTO ledger_holds (id, account_id, credit_account_id, amount, captured_amount, status, expires_at, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)",
+		hold.ID, hold.AccountID, hold.CreditAccountID, hold.Amount, hold.CapturedAmount, string(hold.Status), hold.ExpiresAt, hold.CreatedAt); err != nil {
+		log.Printf("error placing hold: %v", err)
+		return pkgLedger.ErrFailedToSaveHold
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Printf("error committing hold: %v", err)
+		return pkgLedger.ErrFailedToSaveHold
+	}
+
+	return nil
+}
+
+// GetHold gets a hold by ID.
+func (d *LedgerPostgresDatabase) GetHold(id string) (*pkgLedger.Hold, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return scanHold(d.conn.QueryRow(ctx, "SELECT "+holdColumns+" FROM ledger_holds WHERE id = $1", id))
+}
+
+// CaptureHold marks an active hold as captured and stores the entries settling it, atomically.
+func (d *LedgerPostgresDatabase) CaptureHold(id string, amount float32, entries []*pkgLedger.Entry) (*pkgLedger.Hold, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	tx, err := d.conn.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	if err != nil {
+		log.Printf("error starting ledger transaction: %v", err)
+		return nil, pkgLedger.ErrFailedToSaveHold
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// only an active, unexpired hold can be captured
+	hold, err := scanHold(tx.QueryRow(ctx,
+		"UPDATE ledger_holds SET status = $2, captured_amount = $3 WHERE id = $1 AND status = 'active' AND expires_at > NOW() AT TIME ZONE 'UTC' RETURNING "+holdColumns,
+		id, string(pkgLedger.HoldStatusCaptured), amount))
+	if err != nil {
+		return nil, err
+	}
+
+	if err = insertEntries(ctx, tx, entries); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Printf("error committing capture: %v", err)
+		return nil, pkgLedger.ErrFailedToSaveHold
+	}
+
+	return hold, nil
+}
+
+// ReleaseHold moves an active hold to the given status.
+func (d *LedgerPostgresDatabase) ReleaseHold(id string, status pkgLedger.HoldStatus) (*pkgLedger.Hold, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return scanHold(d.conn.QueryRow(ctx,
+		"UPDATE ledger_holds SET status = $2 WHERE id = $1 AND status = 'active' RETURNING "+holdColumns,
+		id, string(status)))
+}
+
+// GetExpiredHolds gets the active holds that expired at or before the given time.
+func (d *LedgerPostgresDatabase) GetExpiredHolds(now time.Time) ([]*pkgLedger.Hold, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	rows, err := d.conn.Query(ctx, "SELECT "+holdColumns+" FROM ledger_holds WHERE status = 'active' AND expires_at <= $1 ORDER BY expires_at", now.UTC())
+	if err != nil {
+		log.Printf("error getting expired holds: %v", err)
+		return nil, pkgLedger.ErrFailedToGetBalance
+	}
+	defer rows.Close()
+
+	holds := make([]*pkgLedger.Hold, 0)
+	for rows.Next() {
+		hold, err := scanHold(rows)
+		if err != nil {
+			return nil, err
+		}
+		holds = append(holds, hold)
+	}
+
+	return holds, rows.Err()
+}
+
+// querier is implemented by both connections and transactions.
+type querier interface {
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+// holdColumns are the columns read by scanHold, in order.
+const holdColumns = "id, account_id, credit_account_id, amount::float8, captured_amount::float8, status, expires_at, created_at"
+
+// getBalance computes the posted balance of an account and deducts its active holds.
+func getBalance(ctx context.Context, q querier, accountID string) (*pkgLedger.Balance, error) {
+	var current, held, overdraftLimit float64
+	if err := q.QueryRow(ctx,
+		`SELECT
+			COALESCE((SELECT SUM(CASE WHEN entry_type = 'credit' THEN amount ELSE -amount END) FROM ledger_entries WHERE account_id = $1), 0)::float8,
+			COALESCE((SELECT SUM(amount) FROM ledger_holds WHERE account_id = $1 AND status = 'active' AND expires_at > NOW() AT TIME ZONE 'UTC'), 0)::float8,
+			COALESCE((SELECT overdraft_limit FROM ledger_overdrafts WHERE account_id = $1), 0)::float8`,
+		accountID).Scan(&current, &held, &overdraftLimit); err != nil {
+		log.Printf("error getting balance: %v", err)
+		return nil, pkgLedger.ErrFailedToGetBalance
+	}
+
+	return &pkgLedger.Balance{
+		AccountID:      accountID,
+		Current:        float32(current),
+		Available:      float32(current - held),
+		OverdraftLimit: float32(overdraftLimit),
+	}, nil
+}
+
+// insertEntries stores the entries of a posting in the given transaction. A reference is only ever posted once:
+// the unique index on (reference, account_id, entry_type) refuses the entries of a posting made again.
+func insertEntries(ctx context.Context, tx pgx.Tx, entries []*pkgLedger.Entry) error {
+	for _, entry := range entries {
+		tag, err := tx.Exec(ctx,
+			"INSERT INTO ledger_entries (id, account_id, reference, entry_type, amount, description, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7) ON CONFLICT (reference, account_id, entry_type) DO NOTHING",
+			entry.ID, entry.AccountID, entry.Reference, string(entry.Type), entry.Amount, entry.Description, entry.CreatedAt)
+		if err != nil {
+			log.Printf("error posting ledger entry: %v", err)
+			return pkgLedger.ErrFailedToPostEntries
+		}
+		if tag.RowsAffected() == 0 {
+			return pkgLedger.ErrEntriesAlreadyPosted
+		}
+	}
+
+	return nil
+}
+
+// scanHold reads a hold from a row of holdColumns.
+func scanHold(row pgx.Row) (*pkgLedger.Hold, error) {
+	var (
+		hold                   pkgLedger.Hold
+		status                 string
+		amount, capturedAmount float64
+	)
+	if err := row.Scan(&hold.ID, &hold.AccountID, &hold.CreditAccountID, &amount, &capturedAmount, &status, &hold.ExpiresAt, &hold.CreatedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pkgLedger.ErrHoldNotFound
+		}
+		log.Printf("error reading hold: %v", err)
+		return nil, pkgLedger.ErrFailedToSaveHold
+	}
+
+	hold.Status = pkgLedger.HoldStatus(status)
+	hold.Amount, hold.CapturedAmount = float32(amount), float32(capturedAmount)
+	return &hold, nil
+}
+
+// GetEntries gets the entries of an account, oldest first.
+func (d *LedgerPostgresDatabase) GetEntries(accountID string) ([]*pkgLedger.Entry, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	rows, err := d.conn.Query(ctx,
+		"SELECT id::text, account_id, reference, entry_type, amount::float8, description, created_at FROM ledger_entries WHERE account_id = $1 ORDER BY created_at, id",
+		accountID)
+	if err != nil {
+		log.Printf("error getting ledger entries: %v", err)
+		return nil, pkgLedger.ErrFailedToGetBalance
+	}
+	defer rows.Close()
+
+	entries := make([]*pkgLedger.Entry, 0)
+	for rows.Next() {
+		var (
+			entry     pkgLedger.Entry
+			entryType string
+			amount    float64
+		)
+		if err = rows.Scan(&entry.ID, &entry.AccountID, &entry.Reference, &entryType, &amount, &entry.Description, &entry.CreatedAt); err != nil {
+			log.Printf("error scanning ledger entry: %v", err)
+			return nil, pkgLedger.ErrFailedToGetBalance
+		}
+		entry.Type = pkgLedger.EntryType(entryType)
+		entry.Amount = float32(amount)
+		entries = append(entries, &entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Printf("error reading ledger entries: %v", err)
+		return nil, pkgLedger.ErrFailedToGetBalance
+	}
+
+	return entries, nil
+}
+
+// GetAccountIDs gets the IDs of the accounts that have entries, in order.
+func (d *LedgerPostgresDatabase) GetAccountIDs() ([]string, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	rows, err := d.conn.Query(ctx, "SELECT DISTINCT account_id FROM ledger_entries ORDER BY account_id")
+	if err != nil {
+		log.Printf("error getting ledger accounts: %v", err)
+		return nil, pkgLedger.ErrFailedToGetBalance
+	}
+	defer rows.Close()
+
+	accountIDs := make([]string, 0)
+	for rows.Next() {
+		var accountID string
+		if err = rows.Scan(&accountID); err != nil {
+			log.Printf("error scanning ledger account: %v", err)
+			return nil, pkgLedger.ErrFailedToGetBalance
+		}
+		accountIDs = append(accountIDs, accountID)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Printf("error reading ledger accounts: %v", err)
+		return nil, pkgLedger.ErrFailedToGetBalance
+	}
+
+	return accountIDs, nil
+}
@@ -0,0 +1,201 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	internal "github.com/quabynah-bilson/quantia/internal/limit"
+	pkg "github.com/quabynah-bilson/quantia/pkg/limit"
+	"log"
+	"strconv"
+	"time"
+)
+
+// maxReserveAttempts is how many times a reservation is retried when concurrent transactions change the usage
+const maxReserveAttempts = 10
+
+// usageRetention is how long usage is kept after its window has ended
+const usageRetention = 24 * time.Hour
+
+// RedisLimitDatabase is the implementation of the limit Database interface for Redis.
+type RedisLimitDatabase struct {
+	client *redis.Client
+	pkg.Database
+}
+
+// WithRedisLimitDatabase creates a new RedisLimitDatabase.
+func WithRedisLimitDatabase(connectionString string) internal.RepositoryConfiguration {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// connect to the database
+	client := redis.NewClient(&redis.Options{
+		Addr: connectionString,
+		DB:   0,
+	})
+
+	// ping the database to check if the connection is working
+	if err := client.Ping(ctx).Err(); err != nil {
+		log.Printf("error pinging Redis: %v", err)
+		return nil
+	}
+
+	return func(r *internal.Repository) error {
+		r.DB = &RedisLimitDatabase{client: client}
+		return nil
+	}
+}
+
+// GetTier gets the tier of a subject, empty when none was set.
+func (db *RedisLimitDatabase) GetTier(subject string) (pkg.Tier, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tier, err := db.client.Get(ctx, tierKey(subject)).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	if err != nil {
+		log.Printf("error getting tier: %v", err)
+		return "", pkg.ErrFailedToRecordUsage
+	}
+
+	return pkg.Tier(tier), nil
+}
+
+// SetTier sets the tier of a subject.
+func (db *RedisLimitDatabase) SetTier(subject string, tier pkg.Tier) error {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := db.client.Set(ctx, tierKey(subject), string(tier), 0).Err(); err != nil {
+		log.Printf("error setting tier: %v", err)
+		return pkg.ErrFailedToRecordUsage
+	}
+
+	return nil
+}
+
+// GetUsage gets what a subject has spent in the day and month of the given time.
+func (db *RedisLimitDatabase) GetUsage(subject string, now time.Time) (*pkg.Usage, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return readUsage(ctx, db.client, dayKey(subject, now), monthKey(subject, now))
+}
+
+// ReserveUsage atomically checks the amount against the limits and records it. The usage keys are watched,
+// so that a concurrent reservation makes the transaction fail and the check run again on fresh usage.
+func (db *RedisLimitDatabase) ReserveUsage(subject string, amount float32, limits pkg.Limits, now time.Time) error {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	day, month := dayKey(subject, now), monthKey(subject, now)
+	reserve := func(tx *redis.Tx) error {
+		usage, err := readUsage(ctx, tx, day, month)
+		if err != nil {
+			return err
+		}
+
+		if err = limits.Check(*usage, amount, now); err != nil {
+			return err
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			record(ctx, pipe, day, amount, 1, pkg.DayResetAt(now))
+			record(ctx, pipe, month, amount, 1, pkg.MonthResetAt(now))
+			return nil
+		})
+		return err
+	}
+
+	for attempt := 0; attempt < maxReserveAttempts; attempt++ {
+		err := db.client.Watch(ctx, reserve, day, month)
+		if errors.Is(err, redis.TxFailedErr) {
+			continue
+		}
+
+		var exceeded *pkg.ExceededError
+		if err != nil && !errors.As(err, &exceeded) {
+			log.Printf("error reserving usage: %v", err)
+			return pkg.ErrFailedToRecordUsage
+		}
+
+		return err
+	}
+
+	return pkg.ErrFailedToRecordUsage
+}
+
+// ReleaseUsage gives back a reservation.
+func (db *RedisLimitDatabase) ReleaseUsage(reservation *pkg.Reservation) error {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := db.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		record(ctx, pipe, dayKey(reservation.Subject, reservation.At), -reservation.Amount, -1, pkg.DayResetAt(reservation.At))
+		record(ctx, pipe, monthKey(reservation.Subject, reservation.At), -reservation.Amount, -1, pkg.MonthResetAt(reservation.At))
+		return nil
+	})
+	if err != nil {
+		log.Printf("error releasing usage: %v", err)
+		return pkg.ErrFailedToRecordUsage
+	}
+
+	return nil
+}
+
+// readUsage reads the usage of a day and a month
+func readUsage(ctx context.Context, client redis.Cmdable, day, month string) (*pkg.Usage, error) {
+	dayUsage, err := client.HGetAll(ctx, day).Result()
+	if err != nil {
+		return nil, err
+	}
+	monthUsage, err := client.HGetAll(ctx, month).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	usage := &pkg.Usage{}
+	usage.DailyAmount, usage.DailyCount = parseUsage(dayUsage)
+	usage.MonthlyAmount, usage.MonthlyCount = parseUsage(monthUsage)
+
+	return usage, nil
+}
+
+// parseUsage reads the amount and count of a usage hash. Releases that crossed a window boundary may leave
+// them negative, so they are floored at zero.
+func parseUsage(values map[string]string) (float32, int) {
+	amount, _ := strconv.ParseFloat(values["amount"], 32)
+	count, _ := strconv.Atoi(values["count"])
+
+	return float32(max(amount, 0)), max(count, 0)
+}
+
+// record adds an amount and a count to a usage hash that expires after its window
+func record(ctx context.Context, pipe redis.Pipeliner, key string, amount float32, count int64, resetAt time.Time) {
+	pipe.HIncrByFloat(ctx, key, "amount", float64(amount))
+	pipe.HIncrBy(ctx, key, "count", count)
+	pipe.ExpireAt(ctx, key, resetAt.Add(usageRetention))
+}
+
+// tierKey returns the key holding the tier of a subject.
+func tierKey(subject string) string {
+	return "limits:" + subject + ":tier"
+}
+
+// dayKey returns the key of the usage hash of a subject's day.
+func dayKey(subject string, t time.Time) string {
+	return "limits:" + subject + ":day:" + pkg.DayWindow(t)
+}
+
+// monthKey returns the key of the usage hash of a subject's month.
+func monthKey(subject string, t time.Time) string {
+	return "limits:" + subject + ":month:" + pkg.MonthWindow(t)
+}
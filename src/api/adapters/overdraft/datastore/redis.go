@@ -0,0 +1,141 @@
+package datastore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	internal "github.com/quabynah-bilson/quantia/internal/overdraft"
+	pkg "github.com/quabynah-bilson/quantia/pkg/overdraft"
+	"log"
+	"time"
+)
+
+// facilitiesKey is the key of the sorted set holding the account IDs of every facility, by creation time
+const facilitiesKey = "overdrafts"
+
+// RedisOverdraftDatabase is the implementation of the overdraft Database interface for Redis.
+type RedisOverdraftDatabase struct {
+	client *redis.Client
+	pkg.Database
+}
+
+// WithRedisOverdraftDatabase creates a new RedisOverdraftDatabase.
+func WithRedisOverdraftDatabase(connectionString string) internal.RepositoryConfiguration {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// connect to the database
+	client := redis.NewClient(&redis.Options{
+		Addr: connectionString,
+		DB:   0,
+	})
+
+	// ping the database to check if the connection is working
+	if err := client.Ping(ctx).Err(); err != nil {
+		log.Printf("error pinging Redis: %v", err)
+		return nil
+	}
+
+	return func(r *internal.Repository) error {
+		r.DB = &RedisOverdraftDatabase{client: client}
+		return nil
+	}
+}
+
+// SaveFacility creates or replaces the overdraft facility of an account.
+func (db *RedisOverdraftDatabase) SaveFacility(facility *pkg.Facility) error {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	facilityJSON, err := json.Marshal(facility)
+	if err != nil {
+		return pkg.ErrFailedToSaveFacility
+	}
+
+	// the record and its index change together
+	pipe := db.client.TxPipeline()
+	pipe.Set(ctx, facilityKey(facility.AccountID), facilityJSON, 0)
+	pipe.ZAdd(ctx, facilitiesKey, &redis.Z{Score: float64(facility.CreatedAt.UnixNano()), Member: facility.AccountID})
+	if _, err = pipe.Exec(ctx); err != nil {
+		log.Printf("error saving overdraft: %v", err)
+		return pkg.ErrFailedToSaveFacility
+	}
+
+	return nil
+}
+
+// GetFacility gets the overdraft facility of an account.
+func (db *RedisOverdraftDatabase) GetFacility(accountID string) (*pkg.Facility, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	value, err := db.client.Get(ctx, facilityKey(accountID)).Result()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			log.Printf("error getting overdraft: %v", err)
+		}
+		return nil, pkg.ErrFacilityNotFound
+	}
+
+	var facility pkg.Facility
+	if err := json.Unmarshal([]byte(value), &facility); err != nil {
+		log.Printf("error unmarshalling overdraft: %v", err)
+		return nil, pkg.ErrFacilityNotFound
+	}
+
+	return &facility, nil
+}
+
+// GetFacilities gets every overdraft facility, oldest first.
+func (db *RedisOverdraftDatabase) GetFacilities() ([]*pkg.Facility, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	accountIDs, err := db.client.ZRange(ctx, facilitiesKey, 0, -1).Result()
+	if err != nil {
+		log.Printf("error getting overdrafts: %v", err)
+		return nil, err
+	}
+
+	if len(accountIDs) == 0 {
+		return []*pkg.Facility{}, nil
+	}
+
+	keys := make([]string, 0, len(accountIDs))
+	for _, accountID := range accountIDs {
+		keys = append(keys, facilityKey(accountID))
+	}
+
+	values, err := db.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		log.Printf("error getting overdrafts: %v", err)
+		return nil, err
+	}
+
+	facilities := make([]*pkg.Facility, 0, len(values))
+	for _, value := range values {
+		raw, ok := value.(string)
+		if !ok {
+			continue
+		}
+
+		var facility pkg.Facility
+		if err := json.Unmarshal([]byte(raw), &facility); err != nil {
+			log.Printf("error unmarshalling overdraft: %v", err)
+			continue
+		}
+		facilities = append(facilities, &facility)
+	}
+
+	return facilities, nil
+}
+
+// facilityKey returns the key holding the overdraft facility of an account.
+func facilityKey(accountID string) string {
+	return "overdraft:" + accountID
+}
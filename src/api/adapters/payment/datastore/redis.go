@@ -3,22 +3,37 @@ package datastore
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"github.com/go-redis/redis/v8"
 	"github.com/google/uuid"
 	internal "github.com/quabynah-bilson/quantia/internal/payment"
+	"github.com/quabynah-bilson/quantia/pkg/event"
 	pkg "github.com/quabynah-bilson/quantia/pkg/payment"
 	"log"
 	"time"
 )
 
+// reserveRefundScript adds ARGV[1] to the refunded total of a transaction unless it would exceed ARGV[2].
+// Running it as a script makes the check and the increment atomic across concurrent refund requests.
+var reserveRefundScript = redis.NewScript(`
+local reserved = tonumber(redis.call("GET", KEYS[1]) or "0")
+if reserved + tonumber(ARGV[1]) > tonumber(ARGV[2]) + 0.001 then
+	return 0
+end
+redis.call("INCRBYFLOAT", KEYS[1], ARGV[1])
+return 1
+`)
+
 // RedisPaymentDatabase is the implementation of the PaymentDatabase interface for Redis.
 type RedisPaymentDatabase struct {
-	client *redis.Client
+	client  *redis.Client
+	channel string
 	pkg.Database
 }
 
 // WithRedisPaymentDatabase creates a new RedisPaymentDatabase.
-func WithRedisPaymentDatabase(connectionString string) internal.RepositoryConfiguration {
+// Webhooks are published to the given channel for the webhook worker to deliver.
+func WithRedisPaymentDatabase(connectionString, channel string) internal.RepositoryConfiguration {
 	// set a timeout of 5 seconds
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -37,46 +52,211 @@ func WithRedisPaymentDatabase(connectionString string) internal.RepositoryConfig
 
 	return func(r *internal.Repository) error {
 		r.DB = &RedisPaymentDatabase{
-			client: client,
+			client:  client,
+			channel: channel,
 		}
 
 		return nil
 	}
 }
 
-// SendWebhook sends a webhook to the given URL.
+// SendWebhook creates a pending transaction and queues its payment.created webhook for the given URL.
 func (db *RedisPaymentDatabase) SendWebhook(amount float32, url string) (*pkg.Transaction, error) {
-	// set context in background
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
 	// create a new transaction
 	transaction := &pkg.Transaction{
-		ID:     uuid.New().String(),
-		Amount: amount,
-		Status: pkg.TransactionStatusPending,
+		ID:        uuid.New().String(),
+		Amount:    amount,
+		Status:    pkg.TransactionStatusPending,
+		Url:       url,
+		CreatedAt: time.Now().UTC(),
 	}
 
-	// marshal the transaction
-	transactionJSON, err := marshalToJson(transaction)
+	// persist the transaction
+	if err := db.SaveTransaction(transaction); err != nil {
+		return nil, err
+	}
+
+	// create the payment.created event for the worker
+	envelope, err := event.New(event.TypePaymentCreated, &event.PaymentData{
+		TransactionID: transaction.ID,
+		Amount:        transaction.Amount,
+		Status:        string(transaction.Status),
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	// send the transaction to the given URL
-	if err = db.client.Publish(ctx, url, transactionJSON).Err(); err != nil {
+	if err = db.PublishEvent(url, envelope); err != nil {
 		return nil, err
 	}
 
 	return transaction, nil
 }
 
-// SubscribeToWebhook subscribes to the given webhook URL.
-func (db *RedisPaymentDatabase) SubscribeToWebhook(url string, queue chan *pkg.WebhookPayload) error {
+// PublishEvent publishes an event for the given URL on the webhook channel.
+func (db *RedisPaymentDatabase) PublishEvent(url string, envelope *event.Envelope) error {
 	// set context in background
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// marshal the payload
+	payloadJSON, err := marshalToJson(pkg.NewWebhookPayload(url, envelope))
+	if err != nil {
+		return err
+	}
+
+	// publish the payload to the webhook channel
+	return db.client.Publish(ctx, db.channel, payloadJSON).Err()
+}
+
+// SaveTransaction creates or replaces the given transaction.
+func (db *RedisPaymentDatabase) SaveTransaction(transaction *pkg.Transaction) error {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	transactionJSON, err := marshalToJson(transaction)
+	if err != nil {
+		return err
+	}
+
+	if err = db.client.Set(ctx, transactionKey(transaction.ID), transactionJSON, 0).Err(); err != nil {
+		log.Printf("error saving transaction: %v", err)
+		return pkg.ErrFailedToSaveTransaction
+	}
+
+	return nil
+}
+
+// GetTransaction gets a transaction by ID.
+func (db *RedisPaymentDatabase) GetTransaction(id string) (*pkg.Transaction, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	value, err := db.client.Get(ctx, transactionKey(id)).Result()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			log.Printf("error getting transaction: %v", err)
+		}
+		return nil, pkg.ErrTransactionNotFound
+	}
+
+	var transaction pkg.Transaction
+	if err := json.Unmarshal([]byte(value), &transaction); err != nil {
+		log.Printf("error unmarshalling transaction: %v", err)
+		return nil, pkg.ErrTransactionNotFound
+	}
+
+	return &transaction, nil
+}
+
+// SaveRefund creates or replaces the given refund and indexes it under its transaction.
+func (db *RedisPaymentDatabase) SaveRefund(refund *pkg.Refund) error {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	refundJSON, err := marshalToJson(refund)
+	if err != nil {
+		return err
+	}
+
+	// the index is a sorted set so that refunds are listed in creation order
+	pipe := db.client.TxPipeline()
+	pipe.Set(ctx, refundKey(refund.ID), refundJSON, 0)
+	pipe.ZAddNX(ctx, transactionRefundsKey(refund.TransactionID), &redis.Z{Score: float64(refund.CreatedAt.UnixNano()), Member: refund.ID})
+	if _, err = pipe.Exec(ctx); err != nil {
+		log.Printf("error saving refund: %v", err)
+		return pkg.ErrFailedToSaveRefund
+	}
+
+	return nil
+}
+
+// GetRefund gets a refund by ID.
+func (db *RedisPaymentDatabase) GetRefund(id string) (*pkg.Refund, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	value, err := db.client.Get(ctx, refundKey(id)).Result()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			log.Printf("error getting refund: %v", err)
+		}
+		return nil, pkg.ErrRefundNotFound
+	}
+
+	var refund pkg.Refund
+	if err := json.Unmarshal([]byte(value), &refund); err != nil {
+		log.Printf("error unmarshalling refund: %v", err)
+		return nil, pkg.ErrRefundNotFound
+	}
+
+	return &refund, nil
+}
+
+// GetRefunds gets the refunds of a transaction, oldest first.
+func (db *RedisPaymentDatabase) GetRefunds(transactionID string) ([]*pkg.Refund, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ids, err := db.client.ZRange(ctx, transactionRefundsKey(transactionID), 0, -1).Result()
+	if err != nil {
+		log.Printf("error listing refunds: %v", err)
+		return nil, pkg.ErrRefundNotFound
+	}
+
+	refunds := make([]*pkg.Refund, 0, len(ids))
+	for _, id := range ids {
+		refund, err := db.GetRefund(id)
+		if err != nil {
+			return nil, err
+		}
+		refunds = append(refunds, refund)
+	}
+
+	return refunds, nil
+}
+
+// ReserveRefund atomically adds an amount to the refunds of a transaction without going over the limit.
+func (db *RedisPaymentDatabase) ReserveRefund(transactionID string, amount, limit float32) error {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	reserved, err := reserveRefundScript.Run(ctx, db.client, []string{refundedAmountKey(transactionID)}, amount, limit).Int()
+	if err != nil {
+		log.Printf("error reserving refund: %v", err)
+		return pkg.ErrFailedToSaveRefund
+	}
+
+	if reserved == 0 {
+		return pkg.ErrRefundExceedsAmount
+	}
+
+	return nil
+}
+
+// ReleaseRefund gives back an amount reserved by ReserveRefund.
+func (db *RedisPaymentDatabase) ReleaseRefund(transactionID string, amount float32) error {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := db.client.IncrByFloat(ctx, refundedAmountKey(transactionID), -float64(amount)).Err(); err != nil {
+		log.Printf("error releasing refund: %v", err)
+		return pkg.ErrFailedToSaveRefund
+	}
+
+	return nil
+}
+
+// SubscribeToWebhook subscribes to the given webhook URL until the context is cancelled.
+// The subscription blocks while the queue is full, applying backpressure to the channel.
+func (db *RedisPaymentDatabase) SubscribeToWebhook(ctx context.Context, url string, queue chan *pkg.WebhookPayload) error {
 	// subscribe to the webhook
 	pubSub := db.client.Subscribe(ctx, url)
 
@@ -89,6 +269,11 @@ func (db *RedisPaymentDatabase) SubscribeToWebhook(url string, queue chan *pkg.W
 	for {
 		msg, err := pubSub.ReceiveMessage(ctx)
 		if err != nil {
+			// stop consuming once the worker is shutting down
+			if ctx.Err() != nil {
+				return nil
+			}
+
 			log.Printf("error receiving message from webhook: %v", err)
 			return pkg.ErrFailedToSubscribeToWebhook
 		}
@@ -99,18 +284,100 @@ func (db *RedisPaymentDatabase) SubscribeToWebhook(url string, queue chan *pkg.W
 			continue
 		}
 
-		// send the payload to the queue
-		queue <- payload
+		// send the payload to the queue, waiting for capacity if it is full
+		select {
+		case queue <- payload:
+			continue
+		default:
+			log.Printf("webhook queue is full, waiting to enqueue transaction %s", payload.ID)
+		}
+
+		select {
+		case queue <- payload:
+		case <-ctx.Done():
+			// keep the received payload for the next run
+			return db.CheckpointWebhook(payload)
+		}
 	}
 }
 
-// marshalToJson converts the given transaction to JSON.
-func marshalToJson(transaction *pkg.Transaction) (interface{}, error) {
-	// convert the transaction to JSON
-	transactionJSON, err := json.Marshal(transaction)
+// CheckpointWebhook saves the given webhook in a list for delivery after a restart.
+func (db *RedisPaymentDatabase) CheckpointWebhook(payload *pkg.WebhookPayload) error {
+	// set a timeout of 5 seconds (the caller's context may already be cancelled)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	payloadJSON, err := marshalToJson(payload)
+	if err != nil {
+		return err
+	}
+
+	if err = db.client.RPush(ctx, db.checkpointKey(), payloadJSON).Err(); err != nil {
+		log.Printf("error checkpointing webhook: %v", err)
+		return pkg.ErrFailedToCheckpointWebhook
+	}
+
+	return nil
+}
+
+// RestoreWebhooks removes and returns all checkpointed webhooks.
+func (db *RedisPaymentDatabase) RestoreWebhooks() ([]*pkg.WebhookPayload, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var payloads []*pkg.WebhookPayload
+	for {
+		value, err := db.client.LPop(ctx, db.checkpointKey()).Result()
+		if errors.Is(err, redis.Nil) {
+			return payloads, nil
+		}
+		if err != nil {
+			log.Printf("error restoring webhooks: %v", err)
+			return payloads, err
+		}
+
+		var payload *pkg.WebhookPayload
+		if err := json.Unmarshal([]byte(value), &payload); err != nil {
+			log.Printf("error unmarshalling checkpointed webhook: %v", err)
+			continue
+		}
+		payloads = append(payloads, payload)
+	}
+}
+
+// transactionKey returns the key holding the transaction with the given ID.
+func transactionKey(id string) string {
+	return "transaction:" + id
+}
+
+// refundKey returns the key holding the refund with the given ID.
+func refundKey(id string) string {
+	return "refund:" + id
+}
+
+// transactionRefundsKey returns the key of the sorted set indexing the refunds of a transaction.
+func transactionRefundsKey(transactionID string) string {
+	return transactionKey(transactionID) + ":refunds"
+}
+
+// refundedAmountKey returns the key holding the amount reserved by the refunds of a transaction.
+func refundedAmountKey(transactionID string) string {
+	return transactionKey(transactionID) + ":refunded"
+}
+
+// checkpointKey returns the key of the list holding checkpointed webhooks.
+func (db *RedisPaymentDatabase) checkpointKey() string {
+	return db.channel + ":checkpoint"
+}
+
+// marshalToJson converts the given value to JSON.
+func marshalToJson(value interface{}) (interface{}, error) {
+	// convert the value to JSON
+	valueJSON, err := json.Marshal(value)
 	if err != nil {
 		return nil, pkg.ErrFailedToMarshalTransaction
 	}
 
-	return transactionJSON, nil
+	return valueJSON, nil
 }
@@ -0,0 +1,468 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	pkg "github.com/quabynah-bilson/quantia/pkg/payment"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// momo product names, used in the API paths
+const (
+	momoCollection   = "collection"
+	momoDisbursement = "disbursement"
+)
+
+// MoMoCredentials are the credentials of one mobile money product (collection or disbursement)
+type MoMoCredentials struct {
+	SubscriptionKey string
+	APIUser         string
+	APIKey          string
+}
+
+// MoMoConfig configures the mobile money provider
+type MoMoConfig struct {
+	// BaseURL is the API root, e.g. https://sandbox.momodeveloper.mtn.com
+	BaseURL string
+
+	// TargetEnvironment is sent as X-Target-Environment (e.g. "sandbox" or "mtnghana")
+	TargetEnvironment string
+
+	// Currency of every request (e.g. "GHS"; the sandbox only accepts "EUR")
+	Currency string
+
+	// CallbackURL receives the asynchronous results of collections and disbursements
+	CallbackURL string
+
+	Collection   MoMoCredentials
+	Disbursement MoMoCredentials
+
+	// Timeout bounds every API call (defaults to 30 seconds)
+	Timeout time.Duration
+}
+
+// MoMoProvider is a mobile money provider supporting request-to-pay collections and disbursements.
+// Collections are settled as soon as the payer approves them, so an approved collection is reported as captured.
+type MoMoProvider struct {
+	config MoMoConfig
+	client *http.Client
+	mu     sync.Mutex
+	tokens map[string]momoToken
+	pkg.PaymentProvider
+	pkg.PayoutProvider
+	pkg.CallbackParser
+}
+
+// momoToken is a cached OAuth access token
+type momoToken struct {
+	value     string
+	expiresAt time.Time
+}
+
+// momoParty identifies a payer or payee
+type momoParty struct {
+	PartyIDType string `json:"partyIdType"`
+	PartyID     string `json:"partyId"`
+}
+
+// momoRequest is the body of request-to-pay and transfer requests
+type momoRequest struct {
+	Amount       string     `json:"amount"`
+	Currency     string     `json:"currency"`
+	ExternalID   string     `json:"externalId"`
+	Payer        *momoParty `json:"payer,omitempty"`
+	Payee        *momoParty `json:"payee,omitempty"`
+	PayerMessage string     `json:"payerMessage"`
+	PayeeNote    string     `json:"payeeNote"`
+}
+
+// momoStatus is the body of status responses and callbacks
+type momoStatus struct {
+	ReferenceID            string      `json:"referenceId,omitempty"`
+	Amount                 string      `json:"amount"`
+	Currency               string      `json:"currency"`
+	FinancialTransactionID string      `json:"financialTransactionId"`
+	ExternalID             string      `json:"externalId"`
+	Payer                  *momoParty  `json:"payer,omitempty"`
+	Payee                  *momoParty  `json:"payee,omitempty"`
+	Status                 string      `json:"status"`
+	Reason                 interface{} `json:"reason,omitempty"`
+}
+
+// NewMoMoProvider creates a new mobile money provider
+func NewMoMoProvider(config MoMoConfig) *MoMoProvider {
+	if config.Timeout == 0 {
+		config.Timeout = 30 * time.Second
+	}
+	config.BaseURL = strings.TrimRight(config.BaseURL, "/")
+
+	return &MoMoProvider{
+		config: config,
+		client: &http.Client{Timeout: config.Timeout},
+		tokens: make(map[string]momoToken),
+	}
+}
+
+// Authorize sends a request-to-pay to the payer's wallet (req.Source is the payer's MSISDN).
+// The result is pending until the payer approves or rejects it on their phone.
+func (p *MoMoProvider) Authorize(ctx context.Context, req *pkg.AuthorizeRequest) (*pkg.ProviderResult, error) {
+	referenceID := uuid.NewString()
+	body := &momoRequest{
+		Amount:       formatMoMoAmount(req.Amount),
+		Currency:     p.config.Currency,
+		ExternalID:   req.Reference,
+		Payer:        &momoParty{PartyIDType: "MSISDN", PartyID: req.Source},
+		PayerMessage: "Quantia payment",
+		PayeeNote:    req.Reference,
+	}
+
+	if err := p.post(ctx, momoCollection, "/collection/v1_0/requesttopay", referenceID, body); err != nil {
+		return nil, err
+	}
+
+	return &pkg.ProviderResult{
+		Reference:         req.Reference,
+		ProviderReference: referenceID,
+		Status:            pkg.ProviderStatusPending,
+		AuthorizedAmount:  req.Amount,
+	}, nil
+}
+
+// Capture confirms an approved collection. Mobile money collections cannot be partially captured.
+func (p *MoMoProvider) Capture(ctx context.Context, providerReference string, amount float32) (*pkg.ProviderResult, error) {
+	result, err := p.Status(ctx, providerReference)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Status != pkg.ProviderStatusCaptured || toMinorUnits(amount) != toMinorUnits(result.CapturedAmount) {
+		return nil, pkg.ErrInvalidProviderOperation
+	}
+
+	return result, nil
+}
+
+// Void is not supported: a request-to-pay cannot be withdrawn once sent
+func (p *MoMoProvider) Void(context.Context, string) (*pkg.ProviderResult, error) {
+	return nil, pkg.ErrInvalidProviderOperation
+}
+
+// Refund sends the amount back to the payer of an approved collection as a disbursement.
+// The result is pending until the disbursement completes, and its callback carries the refund's reference.
+func (p *MoMoProvider) Refund(ctx context.Context, req *pkg.RefundRequest) (*pkg.ProviderResult, error) {
+	collection, err := p.getStatus(ctx, momoCollection, "/collection/v1_0/requesttopay/", req.ProviderReference)
+	if err != nil {
+		return nil, err
+	}
+
+	original := collection.toResult(pkg.ProviderStatusCaptured)
+	if original.Status != pkg.ProviderStatusCaptured || collection.Payer == nil || req.Amount <= 0 || toMinorUnits(req.Amount) > toMinorUnits(original.CapturedAmount) {
+		return nil, pkg.ErrInvalidProviderOperation
+	}
+
+	refund, err := p.Disburse(ctx, &pkg.DisbursementRequest{
+		Reference:   req.Reference,
+		Amount:      req.Amount,
+		Destination: collection.Payer.PartyID,
+		Note:        "Quantia refund",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	refund.CapturedAmount = original.CapturedAmount
+	refund.RefundedAmount = req.Amount
+	return refund, nil
+}
+
+// Status polls the state of a collection
+func (p *MoMoProvider) Status(ctx context.Context, providerReference string) (*pkg.ProviderResult, error) {
+	status, err := p.getStatus(ctx, momoCollection, "/collection/v1_0/requesttopay/", providerReference)
+	if err != nil {
+		return nil, err
+	}
+
+	status.ReferenceID = providerReference
+	return status.toResult(pkg.ProviderStatusCaptured), nil
+}
+
+// Disburse transfers the amount to the payee's wallet (req.Destination is the payee's MSISDN)
+func (p *MoMoProvider) Disburse(ctx context.Context, req *pkg.DisbursementRequest) (*pkg.ProviderResult, error) {
+	referenceID := uuid.NewString()
+	body := &momoRequest{
+		Amount:       formatMoMoAmount(req.Amount),
+		Currency:     p.config.Currency,
+		ExternalID:   req.Reference,
+		Payee:        &momoParty{PartyIDType: "MSISDN", PartyID: req.Destination},
+		PayerMessage: req.Note,
+		PayeeNote:    req.Note,
+	}
+
+	if err := p.post(ctx, momoDisbursement, "/disbursement/v1_0/transfer", referenceID, body); err != nil {
+		return nil, err
+	}
+
+	return &pkg.ProviderResult{
+		Reference:         req.Reference,
+		ProviderReference: referenceID,
+		Status:            pkg.ProviderStatusPending,
+	}, nil
+}
+
+// DisbursementStatus polls the state of a disbursement
+func (p *MoMoProvider) DisbursementStatus(ctx context.Context, providerReference string) (*pkg.ProviderResult, error) {
+	status, err := p.getStatus(ctx, momoDisbursement, "/disbursement/v1_0/transfer/", providerReference)
+	if err != nil {
+		return nil, err
+	}
+
+	status.ReferenceID = providerReference
+	return status.toResult(pkg.ProviderStatusDisbursed), nil
+}
+
+// AccountHolderName returns the name registered for a wallet number, used to confirm payees before paying them
+func (p *MoMoProvider) AccountHolderName(ctx context.Context, msisdn string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.config.BaseURL+"/disbursement/v1_0/accountholder/msisdn/"+url.PathEscape(msisdn)+"/basicuserinfo", nil)
+	if err != nil {
+		return "", pkg.ErrProviderUnavailable
+	}
+
+	resp, err := p.do(ctx, momoDisbursement, req)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return "", pkg.ErrAccountHolderNotFound
+	case resp.StatusCode != http.StatusOK:
+		log.Printf("mobile money account holder request failed with status %d", resp.StatusCode)
+		return "", pkg.ErrProviderUnavailable
+	}
+
+	var info struct {
+		Name       string `json:"name"`
+		GivenName  string `json:"given_name"`
+		FamilyName string `json:"family_name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return "", pkg.ErrProviderUnavailable
+	}
+
+	if name := strings.TrimSpace(info.GivenName + " " + info.FamilyName); name != "" {
+		return name, nil
+	}
+	if info.Name == "" {
+		return "", pkg.ErrAccountHolderNotFound
+	}
+
+	return info.Name, nil
+}
+
+// ParseCallback converts a collection or disbursement callback into a provider result.
+// The callback body has the same shape as a status response.
+func (p *MoMoProvider) ParseCallback(body []byte) (*pkg.ProviderResult, error) {
+	var status momoStatus
+	if err := json.Unmarshal(body, &status); err != nil || status.ExternalID == "" || status.Status == "" {
+		return nil, pkg.ErrInvalidCallback
+	}
+
+	// a callback with a payee is the result of a disbursement
+	if status.Payee != nil {
+		return status.toResult(pkg.ProviderStatusDisbursed), nil
+	}
+
+	return status.toResult(pkg.ProviderStatusCaptured), nil
+}
+
+// toResult maps a mobile money status to a provider result, using success for SUCCESSFUL
+func (s *momoStatus) toResult(success pkg.ProviderStatus) *pkg.ProviderResult {
+	amount, _ := strconv.ParseFloat(s.Amount, 32)
+	result := &pkg.ProviderResult{
+		Reference:         s.ExternalID,
+		ProviderReference: s.ReferenceID,
+		AuthorizedAmount:  float32(amount),
+	}
+
+	switch s.Status {
+	case "SUCCESSFUL":
+		result.Status = success
+		if success == pkg.ProviderStatusCaptured {
+			result.CapturedAmount = float32(amount)
+		}
+	case "PENDING":
+		result.Status = pkg.ProviderStatusPending
+	default:
+		// FAILED, REJECTED, TIMEOUT...
+		result.Status, result.DeclineReason = pkg.ProviderStatusDeclined, strings.ToLower(s.Status)
+		if reason := fmt.Sprint(s.Reason); s.Reason != nil && reason != "" {
+			result.DeclineReason = reason
+		}
+	}
+
+	return result
+}
+
+// post sends a request to a product endpoint, identified by the given reference ID
+func (p *MoMoProvider) post(ctx context.Context, product, path, referenceID string, body interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return pkg.ErrInvalidProviderOperation
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.config.BaseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return pkg.ErrProviderUnavailable
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Reference-Id", referenceID)
+	if p.config.CallbackURL != "" {
+		req.Header.Set("X-Callback-Url", p.config.CallbackURL)
+	}
+
+	resp, err := p.do(ctx, product, req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusAccepted {
+		log.Printf("mobile money %s request rejected with status %d", product, resp.StatusCode)
+		return pkg.ErrProviderUnavailable
+	}
+
+	return nil
+}
+
+// getStatus reads the status of a request-to-pay or transfer
+func (p *MoMoProvider) getStatus(ctx context.Context, product, path, referenceID string) (*momoStatus, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.config.BaseURL+path+referenceID, nil)
+	if err != nil {
+		return nil, pkg.ErrProviderUnavailable
+	}
+
+	resp, err := p.do(ctx, product, req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, pkg.ErrProviderReferenceNotFound
+	case resp.StatusCode != http.StatusOK:
+		log.Printf("mobile money %s status request failed with status %d", product, resp.StatusCode)
+		return nil, pkg.ErrProviderUnavailable
+	}
+
+	var status momoStatus
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, pkg.ErrProviderUnavailable
+	}
+	status.ReferenceID = referenceID
+
+	return &status, nil
+}
+
+// do authenticates and sends a request for the given product
+func (p *MoMoProvider) do(ctx context.Context, product string, req *http.Request) (*http.Response, error) {
+	token, err := p.accessToken(ctx, product)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("X-Target-Environment", p.config.TargetEnvironment)
+	req.Header.Set("Ocp-Apim-Subscription-Key", p.credentials(product).SubscriptionKey)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		log.Printf("error calling mobile money %s API: %v", product, err)
+		if ctx.Err() != nil {
+			return nil, pkg.ErrProviderTimeout
+		}
+		return nil, pkg.ErrProviderUnavailable
+	}
+
+	return resp, nil
+}
+
+// accessToken returns a cached access token for the product, requesting a new one when it expires
+func (p *MoMoProvider) accessToken(ctx context.Context, product string) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if token, ok := p.tokens[product]; ok && time.Now().Before(token.expiresAt) {
+		return token.value, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/%s/token/", p.config.BaseURL, product), nil)
+	if err != nil {
+		return "", pkg.ErrProviderUnavailable
+	}
+	credentials := p.credentials(product)
+	req.SetBasicAuth(credentials.APIUser, credentials.APIKey)
+	req.Header.Set("Ocp-Apim-Subscription-Key", credentials.SubscriptionKey)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		log.Printf("error requesting mobile money %s token: %v", product, err)
+		return "", pkg.ErrProviderUnavailable
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	var body struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if resp.StatusCode != http.StatusOK || json.NewDecoder(resp.Body).Decode(&body) != nil || body.AccessToken == "" {
+		log.Printf("mobile money %s token request failed with status %d", product, resp.StatusCode)
+		return "", pkg.ErrProviderUnavailable
+	}
+
+	// refresh a minute early so that a token never expires mid-request
+	p.tokens[product] = momoToken{
+		value:     body.AccessToken,
+		expiresAt: time.Now().Add(time.Duration(body.ExpiresIn)*time.Second - time.Minute),
+	}
+
+	return body.AccessToken, nil
+}
+
+// credentials returns the credentials of the given product
+func (p *MoMoProvider) credentials(product string) MoMoCredentials {
+	if product == momoDisbursement {
+		return p.config.Disbursement
+	}
+
+	return p.config.Collection
+}
+
+// formatMoMoAmount formats an amount the way the mobile money API expects it
+func formatMoMoAmount(amount float32) string {
+	return strconv.FormatFloat(float64(amount), 'f', -1, 32)
+}
+
+// toMinorUnits converts an amount to cents
+func toMinorUnits(amount float32) int64 {
+	return int64(math.Round(float64(amount) * 100))
+}
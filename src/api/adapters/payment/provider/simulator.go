@@ -0,0 +1,295 @@
+package provider
+
+import (
+	"context"
+	"github.com/google/uuid"
+	pkg "github.com/quabynah-bilson/quantia/pkg/payment"
+	"sync"
+	"time"
+)
+
+// Behaviour is the type that represents how the simulator answers an authorization
+type Behaviour string
+
+const (
+	// BehaviourSucceed authorizes every payment immediately
+	BehaviourSucceed Behaviour = "succeed"
+
+	// BehaviourDecline declines every payment
+	BehaviourDecline Behaviour = "decline"
+
+	// BehaviourTimeout never answers, so the caller's context (or Timeout) expires
+	BehaviourTimeout Behaviour = "timeout"
+
+	// BehaviourAsync answers pending and reports the AsyncOutcome through the callback after AsyncDelay
+	BehaviourAsync Behaviour = "async"
+)
+
+// SimulatorConfig configures the simulator
+type SimulatorConfig struct {
+	// Behaviour is the default behaviour of authorizations
+	Behaviour Behaviour
+
+	// Sources overrides the behaviour for specific payer instruments (e.g. a test card token that always declines)
+	Sources map[string]Behaviour
+
+	// Latency is added to every call
+	Latency time.Duration
+
+	// Timeout bounds BehaviourTimeout when the caller's context has no deadline
+	Timeout time.Duration
+
+	// AsyncDelay is how long an asynchronous authorization stays pending
+	AsyncDelay time.Duration
+
+	// AsyncOutcome is the final behaviour (succeed or decline) of asynchronous authorizations
+	AsyncOutcome Behaviour
+}
+
+// Simulator is an in-process payment and payout provider for development and tests
+type Simulator struct {
+	config   SimulatorConfig
+	mu       sync.Mutex
+	payments map[string]*pkg.ProviderResult
+	callback func(result *pkg.ProviderResult)
+	pkg.PaymentProvider
+	pkg.PayoutProvider
+}
+
+// NewSimulator creates a new payment provider simulator
+func NewSimulator(config SimulatorConfig) *Simulator {
+	if config.Behaviour == "" {
+		config.Behaviour = BehaviourSucceed
+	}
+	if config.AsyncOutcome == "" {
+		config.AsyncOutcome = BehaviourSucceed
+	}
+	if config.Timeout == 0 {
+		config.Timeout = 30 * time.Second
+	}
+
+	return &Simulator{
+		config:   config,
+		payments: make(map[string]*pkg.ProviderResult),
+	}
+}
+
+// OnResult registers the function called when an asynchronous authorization or disbursement completes
+func (s *Simulator) OnResult(callback func(result *pkg.ProviderResult)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.callback = callback
+}
+
+// Authorize reserves the amount according to the configured behaviour
+func (s *Simulator) Authorize(ctx context.Context, req *pkg.AuthorizeRequest) (*pkg.ProviderResult, error) {
+	if err := s.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	behaviour := s.config.Behaviour
+	if override, ok := s.config.Sources[req.Source]; ok {
+		behaviour = override
+	}
+
+	result := &pkg.ProviderResult{
+		Reference:         req.Reference,
+		ProviderReference: "sim_" + uuid.NewString(),
+		AuthorizedAmount:  req.Amount,
+	}
+
+	switch behaviour {
+	case BehaviourTimeout:
+		return nil, s.hang(ctx)
+	case BehaviourDecline:
+		result.Status, result.DeclineReason = pkg.ProviderStatusDeclined, "insufficient_funds"
+	case BehaviourAsync:
+		result.Status = pkg.ProviderStatusPending
+	default:
+		result.Status = pkg.ProviderStatusAuthorized
+	}
+
+	s.mu.Lock()
+	s.payments[result.ProviderReference] = result
+	copied := *result
+	s.mu.Unlock()
+
+	if copied.Status == pkg.ProviderStatusPending {
+		time.AfterFunc(s.config.AsyncDelay, func() { s.complete(copied.ProviderReference, pkg.ProviderStatusAuthorized) })
+	}
+
+	if copied.Status == pkg.ProviderStatusDeclined {
+		return &copied, pkg.ErrPaymentDeclined
+	}
+
+	return &copied, nil
+}
+
+// Disburse sends the amount to the destination according to the configured behaviour. Sources overrides
+// the behaviour for specific destinations.
+func (s *Simulator) Disburse(ctx context.Context, req *pkg.DisbursementRequest) (*pkg.ProviderResult, error) {
+	if err := s.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	behaviour := s.config.Behaviour
+	if override, ok := s.config.Sources[req.Destination]; ok {
+		behaviour = override
+	}
+
+	result := &pkg.ProviderResult{
+		Reference:         req.Reference,
+		ProviderReference: "sim_" + uuid.NewString(),
+		AuthorizedAmount:  req.Amount,
+	}
+
+	switch behaviour {
+	case BehaviourTimeout:
+		return nil, s.hang(ctx)
+	case BehaviourDecline:
+		result.Status, result.DeclineReason = pkg.ProviderStatusDeclined, "payee_not_found"
+	case BehaviourAsync:
+		result.Status = pkg.ProviderStatusPending
+	default:
+		result.Status = pkg.ProviderStatusDisbursed
+	}
+
+	s.mu.Lock()
+	s.payments[result.ProviderReference] = result
+	copied := *result
+	s.mu.Unlock()
+
+	if copied.Status == pkg.ProviderStatusPending {
+		time.AfterFunc(s.config.AsyncDelay, func() { s.complete(copied.ProviderReference, pkg.ProviderStatusDisbursed) })
+	}
+
+	if copied.Status == pkg.ProviderStatusDeclined {
+		return &copied, pkg.ErrDisbursementDeclined
+	}
+
+	return &copied, nil
+}
+
+// DisbursementStatus returns the current state of the disbursement
+func (s *Simulator) DisbursementStatus(ctx context.Context, providerReference string) (*pkg.ProviderResult, error) {
+	return s.Status(ctx, providerReference)
+}
+
+// Capture settles all or part of the authorized amount
+func (s *Simulator) Capture(ctx context.Context, providerReference string, amount float32) (*pkg.ProviderResult, error) {
+	return s.update(ctx, providerReference, func(p *pkg.ProviderResult) error {
+		if p.Status != pkg.ProviderStatusAuthorized || amount <= 0 || amount > p.AuthorizedAmount {
+			return pkg.ErrInvalidProviderOperation
+		}
+		p.Status, p.CapturedAmount = pkg.ProviderStatusCaptured, amount
+		return nil
+	})
+}
+
+// Void releases an authorization that has not been captured
+func (s *Simulator) Void(ctx context.Context, providerReference string) (*pkg.ProviderResult, error) {
+	return s.update(ctx, providerReference, func(p *pkg.ProviderResult) error {
+		if p.Status != pkg.ProviderStatusAuthorized {
+			return pkg.ErrInvalidProviderOperation
+		}
+		p.Status = pkg.ProviderStatusVoided
+		return nil
+	})
+}
+
+// Refund returns all or part of the captured amount. The result carries the refund's reference.
+func (s *Simulator) Refund(ctx context.Context, req *pkg.RefundRequest) (*pkg.ProviderResult, error) {
+	result, err := s.update(ctx, req.ProviderReference, func(p *pkg.ProviderResult) error {
+		refundable := p.Status == pkg.ProviderStatusCaptured || p.Status == pkg.ProviderStatusRefunded
+		if !refundable || req.Amount <= 0 || p.RefundedAmount+req.Amount > p.CapturedAmount {
+			return pkg.ErrInvalidProviderOperation
+		}
+		p.Status, p.RefundedAmount = pkg.ProviderStatusRefunded, p.RefundedAmount+req.Amount
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.Reference = req.Reference
+	return result, nil
+}
+
+// Status returns the current state of the payment
+func (s *Simulator) Status(ctx context.Context, providerReference string) (*pkg.ProviderResult, error) {
+	return s.update(ctx, providerReference, func(*pkg.ProviderResult) error { return nil })
+}
+
+// update applies a state change to a known payment and returns a copy of the result
+func (s *Simulator) update(ctx context.Context, providerReference string, apply func(p *pkg.ProviderResult) error) (*pkg.ProviderResult, error) {
+	if err := s.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.payments[providerReference]
+	if !ok {
+		return nil, pkg.ErrProviderReferenceNotFound
+	}
+
+	if err := apply(p); err != nil {
+		return nil, err
+	}
+
+	copied := *p
+	return &copied, nil
+}
+
+// complete resolves a pending authorization or disbursement with the configured outcome (success when it
+// succeeds) and notifies the callback
+func (s *Simulator) complete(providerReference string, success pkg.ProviderStatus) {
+	s.mu.Lock()
+	p, ok := s.payments[providerReference]
+	if !ok || p.Status != pkg.ProviderStatusPending {
+		s.mu.Unlock()
+		return
+	}
+
+	if s.config.AsyncOutcome == BehaviourDecline {
+		p.Status, p.DeclineReason = pkg.ProviderStatusDeclined, "insufficient_funds"
+	} else {
+		p.Status = success
+	}
+	copied, callback := *p, s.callback
+	s.mu.Unlock()
+
+	if callback != nil {
+		callback(&copied)
+	}
+}
+
+// hang never answers, until the caller's context or the configured timeout expires
+func (s *Simulator) hang(ctx context.Context) error {
+	timer := time.NewTimer(s.config.Timeout)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+
+	return pkg.ErrProviderTimeout
+}
+
+// wait simulates network latency
+func (s *Simulator) wait(ctx context.Context) error {
+	if s.config.Latency == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(s.config.Latency)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return pkg.ErrProviderTimeout
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,254 @@
+package datastore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	internal "github.com/quabynah-bilson/quantia/internal/payout"
+	pkg "github.com/quabynah-bilson/quantia/pkg/payout"
+	"log"
+	"time"
+)
+
+// queuedBatchesKey is the sorted set of the batches waiting for the payout worker, scored by approval time
+const queuedBatchesKey = "payout_batches:queued"
+
+// RedisPayoutDatabase is the implementation of the payout Database interface for Redis.
+type RedisPayoutDatabase struct {
+	client *redis.Client
+	pkg.Database
+}
+
+// WithRedisPayoutDatabase creates a new RedisPayoutDatabase.
+func WithRedisPayoutDatabase(connectionString string) internal.RepositoryConfiguration {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// connect to the database
+	client := redis.NewClient(&redis.Options{
+		Addr: connectionString,
+		DB:   0,
+	})
+
+	// ping the database to check if the connection is working
+	if err := client.Ping(ctx).Err(); err != nil {
+		log.Printf("error pinging Redis: %v", err)
+		return nil
+	}
+
+	return func(r *internal.Repository) error {
+		r.DB = &RedisPayoutDatabase{client: client}
+		return nil
+	}
+}
+
+// SaveBatch creates or replaces a batch and updates the worker queue.
+func (db *RedisPayoutDatabase) SaveBatch(batch *pkg.Batch) error {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	batchJSON, err := json.Marshal(batch)
+	if err != nil {
+		return pkg.ErrFailedToSaveBatch
+	}
+
+	// the record and its queue entry change together
+	pipe := db.client.TxPipeline()
+	pipe.Set(ctx, batchKey(batch.ID), batchJSON, 0)
+	if (batch.Status == pkg.BatchStatusApproved || batch.Status == pkg.BatchStatusProcessing) && batch.ApprovedAt != nil {
+		pipe.ZAdd(ctx, queuedBatchesKey, &redis.Z{Score: float64(batch.ApprovedAt.UnixNano()), Member: batch.ID})
+	} else {
+		pipe.ZRem(ctx, queuedBatchesKey, batch.ID)
+	}
+	if _, err = pipe.Exec(ctx); err != nil {
+		log.Printf("error saving payout batch: %v", err)
+		return pkg.ErrFailedToSaveBatch
+	}
+
+	return nil
+}
+
+// GetBatch gets a batch by ID.
+func (db *RedisPayoutDatabase) GetBatch(id string) (*pkg.Batch, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	value, err := db.client.Get(ctx, batchKey(id)).Result()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			log.Printf("error getting payout batch: %v", err)
+		}
+		return nil, pkg.ErrBatchNotFound
+	}
+
+	var batch pkg.Batch
+	if err := json.Unmarshal([]byte(value), &batch); err != nil {
+		log.Printf("error unmarshalling payout batch: %v", err)
+		return nil, pkg.ErrBatchNotFound
+	}
+
+	return &batch, nil
+}
+
+// GetQueuedBatches gets the IDs of the approved and processing batches, oldest approval first.
+func (db *RedisPayoutDatabase) GetQueuedBatches(limit int) ([]string, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ids, err := db.client.ZRange(ctx, queuedBatchesKey, 0, int64(limit)-1).Result()
+	if err != nil {
+		log.Printf("error getting queued payout batches: %v", err)
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// CreateItems stores the items of a new batch and lists them in upload order.
+func (db *RedisPayoutDatabase) CreateItems(items []*pkg.Item) error {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	pipe := db.client.TxPipeline()
+	for _, item := range items {
+		itemJSON, err := json.Marshal(item)
+		if err != nil {
+			return pkg.ErrFailedToSaveBatch
+		}
+		pipe.Set(ctx, itemKey(item.ID), itemJSON, 0)
+		pipe.RPush(ctx, batchItemsKey(item.BatchID), item.ID)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("error saving payout items: %v", err)
+		return pkg.ErrFailedToSaveBatch
+	}
+
+	return nil
+}
+
+// SaveItem replaces an item.
+func (db *RedisPayoutDatabase) SaveItem(item *pkg.Item) error {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	itemJSON, err := json.Marshal(item)
+	if err != nil {
+		return pkg.ErrFailedToSaveBatch
+	}
+
+	if err = db.client.Set(ctx, itemKey(item.ID), itemJSON, 0).Err(); err != nil {
+		log.Printf("error saving payout item: %v", err)
+		return pkg.ErrFailedToSaveBatch
+	}
+
+	return nil
+}
+
+// GetItem gets an item by ID.
+func (db *RedisPayoutDatabase) GetItem(id string) (*pkg.Item, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	value, err := db.client.Get(ctx, itemKey(id)).Result()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			log.Printf("error getting payout item: %v", err)
+		}
+		return nil, pkg.ErrItemNotFound
+	}
+
+	var item pkg.Item
+	if err := json.Unmarshal([]byte(value), &item); err != nil {
+		log.Printf("error unmarshalling payout item: %v", err)
+		return nil, pkg.ErrItemNotFound
+	}
+
+	return &item, nil
+}
+
+// GetBatchItems gets the items of a batch, in upload order.
+func (db *RedisPayoutDatabase) GetBatchItems(batchID string) ([]*pkg.Item, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ids, err := db.client.LRange(ctx, batchItemsKey(batchID), 0, -1).Result()
+	if err != nil {
+		log.Printf("error getting payout items: %v", err)
+		return nil, err
+	}
+
+	if len(ids) == 0 {
+		return []*pkg.Item{}, nil
+	}
+
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, itemKey(id))
+	}
+
+	values, err := db.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		log.Printf("error getting payout items: %v", err)
+		return nil, err
+	}
+
+	items := make([]*pkg.Item, 0, len(values))
+	for _, value := range values {
+		raw, ok := value.(string)
+		if !ok {
+			continue
+		}
+
+		var item pkg.Item
+		if err := json.Unmarshal([]byte(raw), &item); err != nil {
+			log.Printf("error unmarshalling payout item: %v", err)
+			continue
+		}
+		items = append(items, &item)
+	}
+
+	return items, nil
+}
+
+// ClaimItem marks an item as taken, failing if it already was.
+func (db *RedisPayoutDatabase) ClaimItem(id string) error {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	claimed, err := db.client.SetNX(ctx, itemKey(id)+":claim", time.Now().UTC().Format(time.RFC3339), 0).Result()
+	if err != nil {
+		log.Printf("error claiming payout item: %v", err)
+		return err
+	}
+
+	if !claimed {
+		return pkg.ErrItemAlreadyClaimed
+	}
+
+	return nil
+}
+
+// batchKey returns the key holding the batch with the given ID.
+func batchKey(id string) string {
+	return "payout_batch:" + id
+}
+
+// batchItemsKey returns the key of the list holding the IDs of a batch's items.
+func batchItemsKey(batchID string) string {
+	return batchKey(batchID) + ":items"
+}
+
+// itemKey returns the key holding the payout item with the given ID.
+func itemKey(id string) string {
+	return "payout_item:" + id
+}
@@ -0,0 +1,406 @@
+package datastore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	internal "github.com/quabynah-bilson/quantia/internal/product"
+	pkg "github.com/quabynah-bilson/quantia/pkg/product"
+	"log"
+	"strconv"
+	"time"
+)
+
+const (
+	// productsKey is the sorted set of the product IDs, scored by their creation time
+	productsKey = "products"
+
+	// assignmentsKey is the sorted set of the accounts holding a product
+	assignmentsKey = "product:accounts"
+
+	// lastCompletedDayKey holds the latest business date whose end-of-day batch completed
+	lastCompletedDayKey = "eod:last_completed"
+)
+
+// RedisProductDatabase is the implementation of the product Database interface for Redis.
+type RedisProductDatabase struct {
+	client *redis.Client
+	pkg.Database
+}
+
+// WithRedisProductDatabase creates a new RedisProductDatabase.
+func WithRedisProductDatabase(connectionString string) internal.RepositoryConfiguration {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// connect to the database
+	client := redis.NewClient(&redis.Options{
+		Addr: connectionString,
+		DB:   0,
+	})
+
+	// ping the database to check if the connection is working
+	if err := client.Ping(ctx).Err(); err != nil {
+		log.Printf("error pinging Redis: %v", err)
+		return nil
+	}
+
+	return func(r *internal.Repository) error {
+		r.DB = &RedisProductDatabase{client: client}
+		return nil
+	}
+}
+
+// SaveProduct creates or replaces a product.
+func (db *RedisProductDatabase) SaveProduct(product *pkg.Product) error {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	productJSON, err := json.Marshal(product)
+	if err != nil {
+		return pkg.ErrFailedToSaveProduct
+	}
+
+	// the record and its index change together
+	pipe := db.client.TxPipeline()
+	pipe.Set(ctx, productKey(product.ID), productJSON, 0)
+	pipe.ZAdd(ctx, productsKey, &redis.Z{Score: float64(product.CreatedAt.UnixNano()), Member: product.ID})
+	if _, err = pipe.Exec(ctx); err != nil {
+		log.Printf("error saving product: %v", err)
+		return pkg.ErrFailedToSaveProduct
+	}
+
+	return nil
+}
+
+// GetProduct gets a product by ID.
+func (db *RedisProductDatabase) GetProduct(id string) (*pkg.Product, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	value, err := db.client.Get(ctx, productKey(id)).Result()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			log.Printf("error getting product: %v", err)
+		}
+		return nil, pkg.ErrProductNotFound
+	}
+
+	var product pkg.Product
+	if err := json.Unmarshal([]byte(value), &product); err != nil {
+		log.Printf("error unmarshalling product: %v", err)
+		return nil, pkg.ErrProductNotFound
+	}
+
+	return &product, nil
+}
+
+// GetProducts gets every product, oldest first.
+func (db *RedisProductDatabase) GetProducts() ([]*pkg.Product, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ids, err := db.client.ZRange(ctx, productsKey, 0, -1).Result()
+	if err != nil {
+		log.Printf("error getting products: %v", err)
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, productKey(id))
+	}
+
+	products := make([]*pkg.Product, 0, len(ids))
+	err = db.getAll(ctx, keys, func(raw []byte) error {
+		var product pkg.Product
+		if err := json.Unmarshal(raw, &product); err != nil {
+			return err
+		}
+		products = append(products, &product)
+		return nil
+	})
+
+	return products, err
+}
+
+// SaveAssignment creates or replaces the product of an account.
+func (db *RedisProductDatabase) SaveAssignment(assignment *pkg.Assignment) error {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	assignmentJSON, err := json.Marshal(assignment)
+	if err != nil {
+		return pkg.ErrFailedToSaveProduct
+	}
+
+	// the record and its index change together. Accounts are scored equally so that they are kept in order.
+	pipe := db.client.TxPipeline()
+	pipe.Set(ctx, assignmentKey(assignment.AccountID), assignmentJSON, 0)
+	pipe.ZAdd(ctx, assignmentsKey, &redis.Z{Score: 0, Member: assignment.AccountID})
+	if _, err = pipe.Exec(ctx); err != nil {
+		log.Printf("error saving product assignment: %v", err)
+		return pkg.ErrFailedToSaveProduct
+	}
+
+	return nil
+}
+
+// GetAssignment gets the product of an account.
+func (db *RedisProductDatabase) GetAssignment(accountID string) (*pkg.Assignment, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	value, err := db.client.Get(ctx, assignmentKey(accountID)).Result()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			log.Printf("error getting product assignment: %v", err)
+		}
+		return nil, pkg.ErrAssignmentNotFound
+	}
+
+	var assignment pkg.Assignment
+	if err := json.Unmarshal([]byte(value), &assignment); err != nil {
+		log.Printf("error unmarshalling product assignment: %v", err)
+		return nil, pkg.ErrAssignmentNotFound
+	}
+
+	return &assignment, nil
+}
+
+// GetAssignments gets the products of every account, in account order.
+func (db *RedisProductDatabase) GetAssignments() ([]*pkg.Assignment, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	accountIDs, err := db.client.ZRange(ctx, assignmentsKey, 0, -1).Result()
+	if err != nil {
+		log.Printf("error getting product assignments: %v", err)
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(accountIDs))
+	for _, accountID := range accountIDs {
+		keys = append(keys, assignmentKey(accountID))
+	}
+
+	assignments := make([]*pkg.Assignment, 0, len(accountIDs))
+	err = db.getAll(ctx, keys, func(raw []byte) error {
+		var assignment pkg.Assignment
+		if err := json.Unmarshal(raw, &assignment); err != nil {
+			return err
+		}
+		assignments = append(assignments, &assignment)
+		return nil
+	})
+
+	return assignments, err
+}
+
+// SaveAccrual stores the accrual of an account for a business date, once.
+func (db *RedisProductDatabase) SaveAccrual(accrual *pkg.Accrual) error {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	date, err := time.Parse(pkg.DateLayout, accrual.Date)
+	if err != nil {
+		return pkg.ErrFailedToSaveAccrual
+	}
+
+	accrualJSON, err := json.Marshal(accrual)
+	if err != nil {
+		return pkg.ErrFailedToSaveAccrual
+	}
+
+	claimed, err := db.client.SetNX(ctx, accrualKey(accrual.AccountID, accrual.Date), accrualJSON, 0).Result()
+	if err != nil {
+		log.Printf("error saving accrual: %v", err)
+		return pkg.ErrFailedToSaveAccrual
+	}
+	if !claimed {
+		return pkg.ErrAccrualAlreadyRecorded
+	}
+
+	if err = db.client.ZAdd(ctx, accountAccrualsKey(accrual.AccountID), &redis.Z{Score: float64(date.Unix()), Member: accrual.Date}).Err(); err != nil {
+		log.Printf("error indexing accrual: %v", err)
+
+		// give the date back so that the accrual can be recorded again
+		db.client.Del(ctx, accrualKey(accrual.AccountID, accrual.Date))
+		return pkg.ErrFailedToSaveAccrual
+	}
+
+	return nil
+}
+
+// GetAccruals gets the accruals of an account between two business dates, both included, oldest first.
+func (db *RedisProductDatabase) GetAccruals(accountID, from, to string) ([]*pkg.Accrual, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	start, err := time.Parse(pkg.DateLayout, from)
+	if err != nil {
+		return nil, err
+	}
+	end, err := time.Parse(pkg.DateLayout, to)
+	if err != nil {
+		return nil, err
+	}
+
+	dates, err := db.client.ZRangeByScore(ctx, accountAccrualsKey(accountID), &redis.ZRangeBy{
+		Min: strconv.FormatInt(start.Unix(), 10),
+		Max: strconv.FormatInt(end.Unix(), 10),
+	}).Result()
+	if err != nil {
+		log.Printf("error getting accruals: %v", err)
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(dates))
+	for _, date := range dates {
+		keys = append(keys, accrualKey(accountID, date))
+	}
+
+	accruals := make([]*pkg.Accrual, 0, len(dates))
+	err = db.getAll(ctx, keys, func(raw []byte) error {
+		var accrual pkg.Accrual
+		if err := json.Unmarshal(raw, &accrual); err != nil {
+			return err
+		}
+		accruals = append(accruals, &accrual)
+		return nil
+	})
+
+	return accruals, err
+}
+
+// SaveBusinessDay creates or replaces the outcome of the end-of-day batch of a business date.
+func (db *RedisProductDatabase) SaveBusinessDay(day *pkg.BusinessDay) error {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	dayJSON, err := json.Marshal(day)
+	if err != nil {
+		return pkg.ErrFailedToSaveAccrual
+	}
+
+	// an earlier date run again does not move the latest completed date back
+	last, err := db.client.Get(ctx, lastCompletedDayKey).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		log.Printf("error getting last business date: %v", err)
+		return pkg.ErrFailedToSaveAccrual
+	}
+
+	pipe := db.client.TxPipeline()
+	pipe.Set(ctx, businessDayKey(day.Date), dayJSON, 0)
+	if day.Status == pkg.BusinessDayCompleted && day.Date > last {
+		pipe.Set(ctx, lastCompletedDayKey, day.Date, 0)
+	}
+	if _, err = pipe.Exec(ctx); err != nil {
+		log.Printf("error saving business date: %v", err)
+		return pkg.ErrFailedToSaveAccrual
+	}
+
+	return nil
+}
+
+// GetBusinessDay gets the outcome of the end-of-day batch of a business date.
+func (db *RedisProductDatabase) GetBusinessDay(date string) (*pkg.BusinessDay, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	value, err := db.client.Get(ctx, businessDayKey(date)).Result()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			log.Printf("error getting business date: %v", err)
+		}
+		return nil, pkg.ErrBusinessDayNotFound
+	}
+
+	var day pkg.BusinessDay
+	if err := json.Unmarshal([]byte(value), &day); err != nil {
+		log.Printf("error unmarshalling business date: %v", err)
+		return nil, pkg.ErrBusinessDayNotFound
+	}
+
+	return &day, nil
+}
+
+// GetLastCompletedDay gets the latest completed business date.
+func (db *RedisProductDatabase) GetLastCompletedDay() (string, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	date, err := db.client.Get(ctx, lastCompletedDayKey).Result()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			log.Printf("error getting last business date: %v", err)
+		}
+		return "", pkg.ErrBusinessDayNotFound
+	}
+
+	return date, nil
+}
+
+// getAll reads the JSON values of keys, skipping the missing ones
+func (db *RedisProductDatabase) getAll(ctx context.Context, keys []string, read func(raw []byte) error) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	values, err := db.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		log.Printf("error getting records: %v", err)
+		return err
+	}
+
+	for _, value := range values {
+		raw, ok := value.(string)
+		if !ok {
+			continue
+		}
+
+		if err := read([]byte(raw)); err != nil {
+			log.Printf("error unmarshalling record: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// productKey returns the key holding the product with the given ID.
+func productKey(id string) string {
+	return "product:" + id
+}
+
+// assignmentKey returns the key holding the product assignment of an account.
+func assignmentKey(accountID string) string {
+	return "product:account:" + accountID
+}
+
+// accrualKey returns the key holding the accrual of an account for a business date.
+func accrualKey(accountID, date string) string {
+	return "accrual:" + accountID + ":" + date
+}
+
+// accountAccrualsKey returns the key of the sorted set holding the business dates an account accrued interest on.
+func accountAccrualsKey(accountID string) string {
+	return "accrual:account:" + accountID
+}
+
+// businessDayKey returns the key holding the outcome of the end-of-day batch of a business date.
+func businessDayKey(date string) string {
+	return "eod:" + date
+}
@@ -0,0 +1,164 @@
+package datastore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	internal "github.com/quabynah-bilson/quantia/internal/reconciliation"
+	pkg "github.com/quabynah-bilson/quantia/pkg/reconciliation"
+	"log"
+	"time"
+)
+
+// RedisReconciliationDatabase is the implementation of the reconciliation Database interface for Redis.
+type RedisReconciliationDatabase struct {
+	client *redis.Client
+	pkg.Database
+}
+
+// WithRedisReconciliationDatabase creates a new RedisReconciliationDatabase.
+func WithRedisReconciliationDatabase(connectionString string) internal.RepositoryConfiguration {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// connect to the database
+	client := redis.NewClient(&redis.Options{
+		Addr: connectionString,
+		DB:   0,
+	})
+
+	// ping the database to check if the connection is working
+	if err := client.Ping(ctx).Err(); err != nil {
+		log.Printf("error pinging Redis: %v", err)
+		return nil
+	}
+
+	return func(r *internal.Repository) error {
+		r.DB = &RedisReconciliationDatabase{client: client}
+		return nil
+	}
+}
+
+// SaveStatement stores a new statement with its items. Items never change once matched, so they are kept in
+// the lists of their statement and of their business date rather than under keys of their own.
+func (db *RedisReconciliationDatabase) SaveStatement(statement *pkg.Statement, items []*pkg.Item) error {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	statementJSON, err := json.Marshal(statement)
+	if err != nil {
+		return pkg.ErrFailedToSaveStatement
+	}
+
+	// claim the checksum first, so that a file imported twice at once is stored once
+	claimed, err := db.client.SetNX(ctx, checksumKey(statement.Checksum), statement.ID, 0).Result()
+	if err != nil {
+		log.Printf("error claiming statement checksum: %v", err)
+		return pkg.ErrFailedToSaveStatement
+	}
+	if !claimed {
+		return pkg.ErrStatementAlreadyImported
+	}
+
+	// the statement and its items are stored together
+	pipe := db.client.TxPipeline()
+	pipe.Set(ctx, statementKey(statement.ID), statementJSON, 0)
+	for _, item := range items {
+		itemJSON, err := json.Marshal(item)
+		if err != nil {
+			db.client.Del(ctx, checksumKey(statement.Checksum))
+			return pkg.ErrFailedToSaveStatement
+		}
+		pipe.RPush(ctx, statementItemsKey(statement.ID), itemJSON)
+		pipe.RPush(ctx, dateItemsKey(item.Date), itemJSON)
+	}
+	if _, err = pipe.Exec(ctx); err != nil {
+		log.Printf("error saving statement: %v", err)
+		// give the checksum back so that the file can be imported again
+		db.client.Del(ctx, checksumKey(statement.Checksum))
+		return pkg.ErrFailedToSaveStatement
+	}
+
+	return nil
+}
+
+// GetStatement gets a statement by ID.
+func (db *RedisReconciliationDatabase) GetStatement(id string) (*pkg.Statement, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	value, err := db.client.Get(ctx, statementKey(id)).Result()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			log.Printf("error getting statement: %v", err)
+		}
+		return nil, pkg.ErrStatementNotFound
+	}
+
+	var statement pkg.Statement
+	if err := json.Unmarshal([]byte(value), &statement); err != nil {
+		log.Printf("error unmarshalling statement: %v", err)
+		return nil, pkg.ErrStatementNotFound
+	}
+
+	return &statement, nil
+}
+
+// GetStatementItems gets the items of a statement, in statement order.
+func (db *RedisReconciliationDatabase) GetStatementItems(statementID string) ([]*pkg.Item, error) {
+	return db.getItems(statementItemsKey(statementID))
+}
+
+// GetDateItems gets the items of every statement falling on a business date, in import order.
+func (db *RedisReconciliationDatabase) GetDateItems(date string) ([]*pkg.Item, error) {
+	return db.getItems(dateItemsKey(date))
+}
+
+// getItems reads the items kept in a list.
+func (db *RedisReconciliationDatabase) getItems(key string) ([]*pkg.Item, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	values, err := db.client.LRange(ctx, key, 0, -1).Result()
+	if err != nil {
+		log.Printf("error getting reconciliation items: %v", err)
+		return nil, err
+	}
+
+	items := make([]*pkg.Item, 0, len(values))
+	for _, value := range values {
+		var item pkg.Item
+		if err := json.Unmarshal([]byte(value), &item); err != nil {
+			log.Printf("error unmarshalling reconciliation item: %v", err)
+			continue
+		}
+		items = append(items, &item)
+	}
+
+	return items, nil
+}
+
+// statementKey returns the key holding the statement with the given ID.
+func statementKey(id string) string {
+	return "statement:" + id
+}
+
+// statementItemsKey returns the key listing the items of a statement.
+func statementItemsKey(statementID string) string {
+	return "statement:" + statementID + ":items"
+}
+
+// checksumKey returns the key holding the ID of the statement imported from a file.
+func checksumKey(checksum string) string {
+	return "statement:checksum:" + checksum
+}
+
+// dateItemsKey returns the key listing the items falling on a business date.
+func dateItemsKey(date string) string {
+	return "reconciliation:date:" + date
+}
@@ -0,0 +1,193 @@
+package datastore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	internal "github.com/quabynah-bilson/quantia/internal/schedule"
+	pkg "github.com/quabynah-bilson/quantia/pkg/schedule"
+	"log"
+	"strconv"
+	"time"
+)
+
+// dueSchedulesKey is the sorted set of active schedules, scored by their next run time
+const dueSchedulesKey = "schedules:due"
+
+// RedisScheduleDatabase is the implementation of the schedule Database interface for Redis.
+type RedisScheduleDatabase struct {
+	client *redis.Client
+	pkg.Database
+}
+
+// WithRedisScheduleDatabase creates a new RedisScheduleDatabase.
+func WithRedisScheduleDatabase(connectionString string) internal.RepositoryConfiguration {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// connect to the database
+	client := redis.NewClient(&redis.Options{
+		Addr: connectionString,
+		DB:   0,
+	})
+
+	// ping the database to check if the connection is working
+	if err := client.Ping(ctx).Err(); err != nil {
+		log.Printf("error pinging Redis: %v", err)
+		return nil
+	}
+
+	return func(r *internal.Repository) error {
+		r.DB = &RedisScheduleDatabase{client: client}
+		return nil
+	}
+}
+
+// SaveSchedule creates or replaces a scheduled payment and updates the due index.
+func (db *RedisScheduleDatabase) SaveSchedule(schedule *pkg.ScheduledPayment) error {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	scheduleJSON, err := json.Marshal(schedule)
+	if err != nil {
+		return pkg.ErrFailedToSaveSchedule
+	}
+
+	// the record and its index change together
+	pipe := db.client.TxPipeline()
+	pipe.Set(ctx, scheduleKey(schedule.ID), scheduleJSON, 0)
+	if schedule.Status == pkg.StatusActive {
+		pipe.ZAdd(ctx, dueSchedulesKey, &redis.Z{Score: float64(schedule.NextRunAt.Unix()), Member: schedule.ID})
+	} else {
+		pipe.ZRem(ctx, dueSchedulesKey, schedule.ID)
+	}
+	if _, err = pipe.Exec(ctx); err != nil {
+		log.Printf("error saving schedule: %v", err)
+		return pkg.ErrFailedToSaveSchedule
+	}
+
+	return nil
+}
+
+// GetSchedule gets a scheduled payment by ID.
+func (db *RedisScheduleDatabase) GetSchedule(id string) (*pkg.ScheduledPayment, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	value, err := db.client.Get(ctx, scheduleKey(id)).Result()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			log.Printf("error getting schedule: %v", err)
+		}
+		return nil, pkg.ErrScheduleNotFound
+	}
+
+	var schedule pkg.ScheduledPayment
+	if err := json.Unmarshal([]byte(value), &schedule); err != nil {
+		log.Printf("error unmarshalling schedule: %v", err)
+		return nil, pkg.ErrScheduleNotFound
+	}
+
+	return &schedule, nil
+}
+
+// GetDueSchedules gets the IDs of the active schedules whose next run is at or before the given time.
+func (db *RedisScheduleDatabase) GetDueSchedules(now time.Time, limit int) ([]string, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ids, err := db.client.ZRangeByScore(ctx, dueSchedulesKey, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(now.Unix(), 10),
+		Count: int64(limit),
+	}).Result()
+	if err != nil {
+		log.Printf("error getting due schedules: %v", err)
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// ClaimOccurrence marks an occurrence as taken, failing if it already was.
+func (db *RedisScheduleDatabase) ClaimOccurrence(id string, occurrence int) error {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	claimed, err := db.client.SetNX(ctx, occurrenceKey(id, occurrence), time.Now().UTC().Format(time.RFC3339), 0).Result()
+	if err != nil {
+		log.Printf("error claiming occurrence: %v", err)
+		return err
+	}
+
+	if !claimed {
+		return pkg.ErrOccurrenceAlreadyClaimed
+	}
+
+	return nil
+}
+
+// SaveRun records the outcome of an occurrence.
+func (db *RedisScheduleDatabase) SaveRun(run *pkg.Run) error {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	runJSON, err := json.Marshal(run)
+	if err != nil {
+		return pkg.ErrFailedToSaveSchedule
+	}
+
+	if err = db.client.RPush(ctx, runsKey(run.ScheduleID), runJSON).Err(); err != nil {
+		log.Printf("error saving run: %v", err)
+		return pkg.ErrFailedToSaveSchedule
+	}
+
+	return nil
+}
+
+// GetRuns gets the outcomes of a schedule's occurrences, oldest first.
+func (db *RedisScheduleDatabase) GetRuns(id string) ([]*pkg.Run, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	values, err := db.client.LRange(ctx, runsKey(id), 0, -1).Result()
+	if err != nil {
+		log.Printf("error getting runs: %v", err)
+		return nil, err
+	}
+
+	runs := make([]*pkg.Run, 0, len(values))
+	for _, value := range values {
+		var run pkg.Run
+		if err := json.Unmarshal([]byte(value), &run); err != nil {
+			log.Printf("error unmarshalling run: %v", err)
+			continue
+		}
+		runs = append(runs, &run)
+	}
+
+	return runs, nil
+}
+
+// scheduleKey returns the key holding the schedule with the given ID.
+func scheduleKey(id string) string {
+	return "schedule:" + id
+}
+
+// occurrenceKey returns the key claiming an occurrence of a schedule.
+func occurrenceKey(id string, occurrence int) string {
+	return scheduleKey(id) + ":occurrence:" + strconv.Itoa(occurrence)
+}
+
+// runsKey returns the key of the list holding the runs of a schedule.
+func runsKey(id string) string {
+	return scheduleKey(id) + ":runs"
+}
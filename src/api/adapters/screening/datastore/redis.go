@@ -0,0 +1,149 @@
+package datastore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	internal "github.com/quabynah-bilson/quantia/internal/screening"
+	pkg "github.com/quabynah-bilson/quantia/pkg/screening"
+	"log"
+	"time"
+)
+
+// reviewQueueKey is the key of the sorted set of screenings awaiting review, scored by creation time
+const reviewQueueKey = "screening:reviews"
+
+// RedisScreeningDatabase is the implementation of the screening Database interface for Redis.
+type RedisScreeningDatabase struct {
+	client *redis.Client
+	pkg.Database
+}
+
+// WithRedisScreeningDatabase creates a new RedisScreeningDatabase.
+func WithRedisScreeningDatabase(connectionString string) internal.RepositoryConfiguration {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// connect to the database
+	client := redis.NewClient(&redis.Options{
+		Addr: connectionString,
+		DB:   0,
+	})
+
+	// ping the database to check if the connection is working
+	if err := client.Ping(ctx).Err(); err != nil {
+		log.Printf("error pinging Redis: %v", err)
+		return nil
+	}
+
+	return func(r *internal.Repository) error {
+		r.DB = &RedisScreeningDatabase{client: client}
+		return nil
+	}
+}
+
+// SaveScreening saves a screening as the latest of its party, adding it to the review queue while it awaits review.
+func (db *RedisScreeningDatabase) SaveScreening(screening *pkg.Screening) error {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	screeningJSON, err := json.Marshal(screening)
+	if err != nil {
+		return pkg.ErrFailedToSaveScreening
+	}
+
+	_, err = db.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, screeningKey(screening.ID), screeningJSON, 0)
+		pipe.Set(ctx, partyKey(screening.PartyType, screening.PartyID), screening.ID, 0)
+		if screening.IsAwaitingReview() {
+			pipe.ZAdd(ctx, reviewQueueKey, &redis.Z{Score: float64(screening.CreatedAt.UnixNano()), Member: screening.ID})
+		} else {
+			pipe.ZRem(ctx, reviewQueueKey, screening.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("error saving screening: %v", err)
+		return pkg.ErrFailedToSaveScreening
+	}
+
+	return nil
+}
+
+// GetScreening gets a screening by ID.
+func (db *RedisScreeningDatabase) GetScreening(id string) (*pkg.Screening, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	value, err := db.client.Get(ctx, screeningKey(id)).Result()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			log.Printf("error getting screening: %v", err)
+		}
+		return nil, pkg.ErrScreeningNotFound
+	}
+
+	var screening pkg.Screening
+	if err := json.Unmarshal([]byte(value), &screening); err != nil {
+		log.Printf("error unmarshalling screening: %v", err)
+		return nil, pkg.ErrScreeningNotFound
+	}
+
+	return &screening, nil
+}
+
+// GetPartyScreening gets the latest screening of a party.
+func (db *RedisScreeningDatabase) GetPartyScreening(partyType pkg.PartyType, partyID string) (*pkg.Screening, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	id, err := db.client.Get(ctx, partyKey(partyType, partyID)).Result()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			log.Printf("error getting party screening: %v", err)
+			return nil, err
+		}
+		return nil, pkg.ErrScreeningNotFound
+	}
+
+	return db.GetScreening(id)
+}
+
+// GetReviewQueue gets the screenings awaiting review, oldest first.
+func (db *RedisScreeningDatabase) GetReviewQueue() ([]*pkg.Screening, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ids, err := db.client.ZRange(ctx, reviewQueueKey, 0, -1).Result()
+	if err != nil {
+		log.Printf("error getting review queue: %v", err)
+		return nil, err
+	}
+
+	screenings := make([]*pkg.Screening, 0, len(ids))
+	for _, id := range ids {
+		screening, err := db.GetScreening(id)
+		if err != nil {
+			continue
+		}
+		screenings = append(screenings, screening)
+	}
+
+	return screenings, nil
+}
+
+// screeningKey returns the key holding the screening with the given ID.
+func screeningKey(id string) string {
+	return "screening:" + id
+}
+
+// partyKey returns the key holding the ID of the latest screening of a party.
+func partyKey(partyType pkg.PartyType, partyID string) string {
+	return "screening:party:" + string(partyType) + ":" + partyID
+}
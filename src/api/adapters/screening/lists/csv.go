@@ -0,0 +1,64 @@
+package lists
+
+import (
+	"encoding/csv"
+	"errors"
+	pkg "github.com/quabynah-bilson/quantia/pkg/screening"
+	"io"
+	"os"
+	"strings"
+)
+
+// LoadCSV reads a list from a CSV file with the columns id, name, aliases, type and program. Aliases are
+// separated by semicolons; the last three columns may be empty.
+func LoadCSV(path, listName string) ([]pkg.Entry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = 5
+	reader.TrimLeadingSpace = true
+
+	var entries []pkg.Entry
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// skip the header
+		if record[0] == "id" {
+			continue
+		}
+
+		entries = append(entries, pkg.Entry{
+			ID:      strings.TrimSpace(record[0]),
+			List:    listName,
+			Name:    strings.TrimSpace(record[1]),
+			Aliases: splitAliases(record[2]),
+			Type:    strings.TrimSpace(record[3]),
+			Program: strings.TrimSpace(record[4]),
+		})
+	}
+
+	return entries, nil
+}
+
+// splitAliases splits the semicolon-separated aliases of an entry
+func splitAliases(value string) []string {
+	var aliases []string
+	for _, alias := range strings.Split(value, ";") {
+		if alias = strings.TrimSpace(alias); alias != "" {
+			aliases = append(aliases, alias)
+		}
+	}
+	return aliases
+}
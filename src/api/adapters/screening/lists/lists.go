@@ -0,0 +1,30 @@
+// Package lists reads sanctions lists from the files published by the list owners.
+package lists
+
+import (
+	"errors"
+	pkg "github.com/quabynah-bilson/quantia/pkg/screening"
+	"path/filepath"
+	"strings"
+)
+
+// ErrUnsupportedFormat is the error returned when a list file is neither CSV nor XML
+var ErrUnsupportedFormat = errors.New("unsupported sanctions list format")
+
+// Load reads the entries of a list file, choosing the format from the file extension. The entries are
+// tagged with the list name, which defaults to the file name without its extension.
+func Load(path, listName string) ([]pkg.Entry, error) {
+	extension := strings.ToLower(filepath.Ext(path))
+	if listName == "" {
+		listName = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+
+	switch extension {
+	case ".csv":
+		return LoadCSV(path, listName)
+	case ".xml":
+		return LoadXML(path, listName)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
@@ -0,0 +1,122 @@
+package lists
+
+import (
+	"encoding/xml"
+	pkg "github.com/quabynah-bilson/quantia/pkg/screening"
+	"os"
+	"strings"
+)
+
+// xmlList holds the entries of the two supported XML layouts: the OFAC SDN list (sdnList) and the UN
+// Security Council consolidated list (CONSOLIDATED_LIST)
+type xmlList struct {
+	SDNEntries  []sdnEntry `xml:"sdnEntry"`
+	Individuals []unEntry  `xml:"INDIVIDUALS>INDIVIDUAL"`
+	Entities    []unEntry  `xml:"ENTITIES>ENTITY"`
+}
+
+// sdnEntry is an entry of the OFAC SDN list
+type sdnEntry struct {
+	UID       string   `xml:"uid"`
+	FirstName string   `xml:"firstName"`
+	LastName  string   `xml:"lastName"`
+	Type      string   `xml:"sdnType"`
+	Programs  []string `xml:"programList>program"`
+	AKAs      []struct {
+		FirstName string `xml:"firstName"`
+		LastName  string `xml:"lastName"`
+	} `xml:"akaList>aka"`
+}
+
+// unEntry is an individual or entity of the UN consolidated list
+type unEntry struct {
+	Reference  string `xml:"REFERENCE_NUMBER"`
+	DataID     string `xml:"DATAID"`
+	FirstName  string `xml:"FIRST_NAME"`
+	SecondName string `xml:"SECOND_NAME"`
+	ThirdName  string `xml:"THIRD_NAME"`
+	FourthName string `xml:"FOURTH_NAME"`
+	ListType   string `xml:"UN_LIST_TYPE"`
+	Aliases    []struct {
+		Name string `xml:"ALIAS_NAME"`
+	} `xml:"INDIVIDUAL_ALIAS"`
+	EntityAliases []struct {
+		Name string `xml:"ALIAS_NAME"`
+	} `xml:"ENTITY_ALIAS"`
+}
+
+// LoadXML reads a list from an OFAC SDN or UN consolidated list XML file
+func LoadXML(path, listName string) ([]pkg.Entry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var list xmlList
+	if err := xml.Unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+
+	var entries []pkg.Entry
+	for _, e := range list.SDNEntries {
+		entry := pkg.Entry{
+			ID:      e.UID,
+			List:    listName,
+			Name:    joinName(e.FirstName, e.LastName),
+			Type:    strings.TrimSpace(e.Type),
+			Program: strings.Join(e.Programs, ", "),
+		}
+		for _, aka := range e.AKAs {
+			entry.Aliases = appendAlias(entry.Aliases, joinName(aka.FirstName, aka.LastName))
+		}
+		entries = append(entries, entry)
+	}
+
+	for _, group := range []struct {
+		entries []unEntry
+		kind    string
+	}{{list.Individuals, "Individual"}, {list.Entities, "Entity"}} {
+		for _, e := range group.entries {
+			id := strings.TrimSpace(e.Reference)
+			if id == "" {
+				id = strings.TrimSpace(e.DataID)
+			}
+
+			entry := pkg.Entry{
+				ID:      id,
+				List:    listName,
+				Name:    joinName(e.FirstName, e.SecondName, e.ThirdName, e.FourthName),
+				Type:    group.kind,
+				Program: strings.TrimSpace(e.ListType),
+			}
+			for _, alias := range e.Aliases {
+				entry.Aliases = appendAlias(entry.Aliases, alias.Name)
+			}
+			for _, alias := range e.EntityAliases {
+				entry.Aliases = appendAlias(entry.Aliases, alias.Name)
+			}
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, nil
+}
+
+// joinName joins the non-empty parts of a name
+func joinName(parts ...string) string {
+	var name []string
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			name = append(name, part)
+		}
+	}
+	return strings.Join(name, " ")
+}
+
+// appendAlias appends a non-empty alias
+func appendAlias(aliases []string, alias string) []string {
+	if alias = strings.TrimSpace(alias); alias != "" {
+		aliases = append(aliases, alias)
+	}
+	return aliases
+}
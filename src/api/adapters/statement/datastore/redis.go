@@ -0,0 +1,177 @@
+package datastore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	internal "github.com/quabynah-bilson/quantia/internal/statement"
+	pkg "github.com/quabynah-bilson/quantia/pkg/statement"
+	"log"
+	"time"
+)
+
+// RedisStatementDatabase is the implementation of the statement Database interface for Redis.
+type RedisStatementDatabase struct {
+	client *redis.Client
+	pkg.Database
+}
+
+// WithRedisStatementDatabase creates a new RedisStatementDatabase.
+func WithRedisStatementDatabase(connectionString string) internal.RepositoryConfiguration {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// connect to the database
+	client := redis.NewClient(&redis.Options{
+		Addr: connectionString,
+		DB:   0,
+	})
+
+	// ping the database to check if the connection is working
+	if err := client.Ping(ctx).Err(); err != nil {
+		log.Printf("error pinging Redis: %v", err)
+		return nil
+	}
+
+	return func(r *internal.Repository) error {
+		r.DB = &RedisStatementDatabase{client: client}
+		return nil
+	}
+}
+
+// SaveStatement stores a new monthly statement, claiming its account's period first.
+func (db *RedisStatementDatabase) SaveStatement(statement *pkg.Statement) error {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	statementJSON, err := json.Marshal(statement)
+	if err != nil {
+		return pkg.ErrFailedToSaveStatement
+	}
+
+	claimed, err := db.client.SetNX(ctx, periodKey(statement.AccountID, statement.Period), statement.ID, 0).Result()
+	if err != nil {
+		log.Printf("error claiming statement period: %v", err)
+		return pkg.ErrFailedToSaveStatement
+	}
+	if !claimed {
+		return pkg.ErrStatementAlreadyGenerated
+	}
+
+	// the record and its index change together
+	pipe := db.client.TxPipeline()
+	pipe.Set(ctx, statementKey(statement.ID), statementJSON, 0)
+	pipe.ZAdd(ctx, accountStatementsKey(statement.AccountID), &redis.Z{Score: float64(statement.From.Unix()), Member: statement.ID})
+	if _, err = pipe.Exec(ctx); err != nil {
+		log.Printf("error saving statement: %v", err)
+
+		// give the period back so that the statement can be generated again
+		db.client.Del(ctx, periodKey(statement.AccountID, statement.Period))
+		return pkg.ErrFailedToSaveStatement
+	}
+
+	return nil
+}
+
+// GetStatement gets a statement by ID.
+func (db *RedisStatementDatabase) GetStatement(id string) (*pkg.Statement, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	value, err := db.client.Get(ctx, statementKey(id)).Result()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			log.Printf("error getting statement: %v", err)
+		}
+		return nil, pkg.ErrStatementNotFound
+	}
+
+	var statement pkg.Statement
+	if err := json.Unmarshal([]byte(value), &statement); err != nil {
+		log.Printf("error unmarshalling statement: %v", err)
+		return nil, pkg.ErrStatementNotFound
+	}
+
+	return &statement, nil
+}
+
+// GetPeriodStatement gets the statement of an account for a period.
+func (db *RedisStatementDatabase) GetPeriodStatement(accountID, period string) (*pkg.Statement, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	id, err := db.client.Get(ctx, periodKey(accountID, period)).Result()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			log.Printf("error getting statement period: %v", err)
+		}
+		return nil, pkg.ErrStatementNotFound
+	}
+
+	return db.GetStatement(id)
+}
+
+// GetAccountStatements gets the statements of an account, oldest period first.
+func (db *RedisStatementDatabase) GetAccountStatements(accountID string) ([]*pkg.Statement, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ids, err := db.client.ZRange(ctx, accountStatementsKey(accountID), 0, -1).Result()
+	if err != nil {
+		log.Printf("error getting account statements: %v", err)
+		return nil, err
+	}
+
+	if len(ids) == 0 {
+		return []*pkg.Statement{}, nil
+	}
+
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, statementKey(id))
+	}
+
+	values, err := db.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		log.Printf("error getting account statements: %v", err)
+		return nil, err
+	}
+
+	statements := make([]*pkg.Statement, 0, len(values))
+	for _, value := range values {
+		raw, ok := value.(string)
+		if !ok {
+			continue
+		}
+
+		var statement pkg.Statement
+		if err := json.Unmarshal([]byte(raw), &statement); err != nil {
+			log.Printf("error unmarshalling statement: %v", err)
+			continue
+		}
+		statements = append(statements, &statement)
+	}
+
+	return statements, nil
+}
+
+// statementKey returns the key holding the statement with the given ID.
+func statementKey(id string) string {
+	return "statement:" + id
+}
+
+// periodKey returns the key holding the ID of an account's statement for a period.
+func periodKey(accountID, period string) string {
+	return "statement:period:" + accountID + ":" + period
+}
+
+// accountStatementsKey returns the key of the sorted set holding the IDs of an account's statements.
+func accountStatementsKey(accountID string) string {
+	return "statement:account:" + accountID
+}
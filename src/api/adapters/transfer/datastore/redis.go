@@ -0,0 +1,146 @@
+package datastore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	internal "github.com/quabynah-bilson/quantia/internal/transfer"
+	pkg "github.com/quabynah-bilson/quantia/pkg/transfer"
+	"log"
+	"time"
+)
+
+// dateLayout is the layout of the business dates transfers are indexed by
+const dateLayout = "2006-01-02"
+
+// RedisTransferDatabase is the implementation of the transfer Database interface for Redis.
+type RedisTransferDatabase struct {
+	client *redis.Client
+	pkg.Database
+}
+
+// WithRedisTransferDatabase creates a new RedisTransferDatabase.
+func WithRedisTransferDatabase(connectionString string) internal.RepositoryConfiguration {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// connect to the database
+	client := redis.NewClient(&redis.Options{
+		Addr: connectionString,
+		DB:   0,
+	})
+
+	// ping the database to check if the connection is working
+	if err := client.Ping(ctx).Err(); err != nil {
+		log.Printf("error pinging Redis: %v", err)
+		return nil
+	}
+
+	return func(r *internal.Repository) error {
+		r.DB = &RedisTransferDatabase{client: client}
+		return nil
+	}
+}
+
+// SaveTransfer creates or replaces a transfer and indexes it by the day it was made.
+func (db *RedisTransferDatabase) SaveTransfer(transfer *pkg.Transfer) error {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	transferJSON, err := json.Marshal(transfer)
+	if err != nil {
+		return pkg.ErrFailedToSaveTransfer
+	}
+
+	// the record and its date index change together
+	pipe := db.client.TxPipeline()
+	pipe.Set(ctx, transferKey(transfer.ID), transferJSON, 0)
+	pipe.ZAdd(ctx, dateTransfersKey(transfer.CreatedAt.UTC().Format(dateLayout)), &redis.Z{Score: float64(transfer.CreatedAt.UnixNano()), Member: transfer.ID})
+	if _, err = pipe.Exec(ctx); err != nil {
+		log.Printf("error saving transfer: %v", err)
+		return pkg.ErrFailedToSaveTransfer
+	}
+
+	return nil
+}
+
+// GetTransfer gets a transfer by ID.
+func (db *RedisTransferDatabase) GetTransfer(id string) (*pkg.Transfer, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	value, err := db.client.Get(ctx, transferKey(id)).Result()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			log.Printf("error getting transfer: %v", err)
+		}
+		return nil, pkg.ErrTransferNotFound
+	}
+
+	var transfer pkg.Transfer
+	if err := json.Unmarshal([]byte(value), &transfer); err != nil {
+		log.Printf("error unmarshalling transfer: %v", err)
+		return nil, pkg.ErrTransferNotFound
+	}
+
+	return &transfer, nil
+}
+
+// GetDateTransfers gets the transfers made on a business date, oldest first.
+func (db *RedisTransferDatabase) GetDateTransfers(date string) ([]*pkg.Transfer, error) {
+	// set a timeout of 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ids, err := db.client.ZRange(ctx, dateTransfersKey(date), 0, -1).Result()
+	if err != nil {
+		log.Printf("error getting transfers of %s: %v", date, err)
+		return nil, err
+	}
+
+	if len(ids) == 0 {
+		return []*pkg.Transfer{}, nil
+	}
+
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, transferKey(id))
+	}
+
+	values, err := db.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		log.Printf("error getting transfers of %s: %v", date, err)
+		return nil, err
+	}
+
+	transfers := make([]*pkg.Transfer, 0, len(values))
+	for _, value := range values {
+		raw, ok := value.(string)
+		if !ok {
+			continue
+		}
+
+		var transfer pkg.Transfer
+		if err := json.Unmarshal([]byte(raw), &transfer); err != nil {
+			log.Printf("error unmarshalling transfer: %v", err)
+			continue
+		}
+		transfers = append(transfers, &transfer)
+	}
+
+	return transfers, nil
+}
+
+// transferKey returns the key holding the transfer with the given ID.
+func transferKey(id string) string {
+	return "transfer:" + id
+}
+
+// dateTransfersKey returns the key of the sorted set holding the IDs of the transfers made on a business date.
+func dateTransfersKey(date string) string {
+	return "transfer:date:" + date
+}
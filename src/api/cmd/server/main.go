@@ -1,10 +1,16 @@
 package main
 
 import (
+	"context"
 	"github.com/joho/godotenv"
 	"github.com/quabynah-bilson/quantia/interfaces/http"
+	"github.com/quabynah-bilson/quantia/interfaces/jobs"
 	"github.com/quabynah-bilson/quantia/interfaces/webhook"
 	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 )
 
 // entry point of the application
@@ -14,9 +20,29 @@ func main() {
 		log.Fatalf("Error loading .env file: %v", err)
 	}
 
+	// cancel the context on SIGINT or SIGTERM to shut down gracefully
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	// Start the auth server
-	go http.StartAuthServer()
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		http.StartAuthServer(ctx)
+	}()
+
+	// Start the background jobs
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		jobs.StartJobs(ctx)
+	}()
 
 	// Start the webhook worker
-	webhook.StartWebhookWorker()
+	webhook.StartWebhookWorker(ctx)
+
+	// wait for the http server and the background jobs to shut down
+	wg.Wait()
+	log.Println("shutdown complete")
 }
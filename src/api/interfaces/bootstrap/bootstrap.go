@@ -0,0 +1,635 @@
+// Package bootstrap builds the repositories and use cases shared by the http server and the background jobs.
+package bootstrap
+
+import (
+	"encoding/hex"
+	accountAdapter "github.com/quabynah-bilson/quantia/adapters/account/datastore"
+	beneficiaryAdapter "github.com/quabynah-bilson/quantia/adapters/beneficiary/datastore"
+	"github.com/quabynah-bilson/quantia/adapters/beneficiary/resolver"
+	callbackAdapter "github.com/quabynah-bilson/quantia/adapters/callback/datastore"
+	cardAdapter "github.com/quabynah-bilson/quantia/adapters/card/datastore"
+	disputeAdapter "github.com/quabynah-bilson/quantia/adapters/dispute/datastore"
+	escrowAdapter "github.com/quabynah-bilson/quantia/adapters/escrow/datastore"
+	fraudAdapter "github.com/quabynah-bilson/quantia/adapters/fraud/datastore"
+	"github.com/quabynah-bilson/quantia/adapters/fraud/geoip"
+	invoiceAdapter "github.com/quabynah-bilson/quantia/adapters/invoice/datastore"
+	ledgerAdapter "github.com/quabynah-bilson/quantia/adapters/ledger/datastore"
+	limitAdapter "github.com/quabynah-bilson/quantia/adapters/limit/datastore"
+	overdraftAdapter "github.com/quabynah-bilson/quantia/adapters/overdraft/datastore"
+	paymentAdapter "github.com/quabynah-bilson/quantia/adapters/payment/datastore"
+	"github.com/quabynah-bilson/quantia/adapters/payment/provider"
+	payoutAdapter "github.com/quabynah-bilson/quantia/adapters/payout/datastore"
+	productAdapter "github.com/quabynah-bilson/quantia/adapters/product/datastore"
+	reconciliationAdapter "github.com/quabynah-bilson/quantia/adapters/reconciliation/datastore"
+	scheduleAdapter "github.com/quabynah-bilson/quantia/adapters/schedule/datastore"
+	screeningAdapter "github.com/quabynah-bilson/quantia/adapters/screening/datastore"
+	"github.com/quabynah-bilson/quantia/adapters/screening/lists"
+	statementAdapter "github.com/quabynah-bilson/quantia/adapters/statement/datastore"
+	transferAdapter "github.com/quabynah-bilson/quantia/adapters/transfer/datastore"
+	"github.com/quabynah-bilson/quantia/internal/account"
+	"github.com/quabynah-bilson/quantia/internal/beneficiary"
+	"github.com/quabynah-bilson/quantia/internal/callback"
+	"github.com/quabynah-bilson/quantia/internal/card"
+	"github.com/quabynah-bilson/quantia/internal/dispute"
+	"github.com/quabynah-bilson/quantia/internal/escrow"
+	"github.com/quabynah-bilson/quantia/internal/fraud"
+	"github.com/quabynah-bilson/quantia/internal/invoice"
+	"github.com/quabynah-bilson/quantia/internal/ledger"
+	"github.com/quabynah-bilson/quantia/internal/limit"
+	"github.com/quabynah-bilson/quantia/internal/netguard"
+	"github.com/quabynah-bilson/quantia/internal/overdraft"
+	"github.com/quabynah-bilson/quantia/internal/payment"
+	"github.com/quabynah-bilson/quantia/internal/payout"
+	"github.com/quabynah-bilson/quantia/internal/product"
+	"github.com/quabynah-bilson/quantia/internal/reconciliation"
+	"github.com/quabynah-bilson/quantia/internal/schedule"
+	"github.com/quabynah-bilson/quantia/internal/screening"
+	"github.com/quabynah-bilson/quantia/internal/statement"
+	"github.com/quabynah-bilson/quantia/internal/transfer"
+	"github.com/quabynah-bilson/quantia/pkg"
+	accountPkg "github.com/quabynah-bilson/quantia/pkg/account"
+	beneficiaryPkg "github.com/quabynah-bilson/quantia/pkg/beneficiary"
+	callbackPkg "github.com/quabynah-bilson/quantia/pkg/callback"
+	cardPkg "github.com/quabynah-bilson/quantia/pkg/card"
+	fraudPkg "github.com/quabynah-bilson/quantia/pkg/fraud"
+	"github.com/quabynah-bilson/quantia/pkg/iso20022"
+	ledgerPkg "github.com/quabynah-bilson/quantia/pkg/ledger"
+	limitPkg "github.com/quabynah-bilson/quantia/pkg/limit"
+	paymentPkg "github.com/quabynah-bilson/quantia/pkg/payment"
+	payoutPkg "github.com/quabynah-bilson/quantia/pkg/payout"
+	reconciliationPkg "github.com/quabynah-bilson/quantia/pkg/reconciliation"
+	screeningPkg "github.com/quabynah-bilson/quantia/pkg/screening"
+	transferPkg "github.com/quabynah-bilson/quantia/pkg/transfer"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultBeneficiaryCoolingOff is how long new beneficiaries cannot receive large amounts when BENEFICIARY_COOLING_OFF is not set
+	defaultBeneficiaryCoolingOff = 24 * time.Hour
+
+	// defaultBeneficiaryLargeAmount is the smallest amount refused to new beneficiaries when BENEFICIARY_LARGE_AMOUNT is not set
+	defaultBeneficiaryLargeAmount = 1000
+
+	// defaultCallbackSignatureHeader is the header carrying callback signatures when PAYMENT_CALLBACK_SIGNATURE_HEADER is not set
+	defaultCallbackSignatureHeader = "X-Callback-Signature"
+)
+
+// NewLedgerRepository is a function that sets up the ledger repository
+func NewLedgerRepository() ledgerPkg.Repository {
+	// create a new ledger repository (with a database configuration)
+	return ledger.NewRepository(
+		ledgerAdapter.WithPostgresLedgerDatabase(os.Getenv("POSTGRES_URI")),
+	)
+}
+
+// NewPaymentRepository is a function that sets up the payment repository
+func NewPaymentRepository() paymentPkg.Repository {
+	// create a new payment repository (with a database configuration)
+	return payment.NewRepository(
+		paymentAdapter.WithRedisPaymentDatabase(os.Getenv("REDIS_URI"), os.Getenv("WEBHOOK_ADDRESS")),
+	)
+}
+
+// NewPaymentProvider is a function that sets up the payment provider: mobile money when it is configured,
+// otherwise the in-process simulator
+func NewPaymentProvider() paymentPkg.PaymentProvider {
+	if os.Getenv("PAYMENT_PROVIDER") == "momo" {
+		return provider.NewMoMoProvider(provider.MoMoConfig{
+			BaseURL:           os.Getenv("MOMO_BASE_URL"),
+			TargetEnvironment: os.Getenv("MOMO_TARGET_ENVIRONMENT"),
+			Currency:          os.Getenv("MOMO_CURRENCY"),
+			CallbackURL:       os.Getenv("MOMO_CALLBACK_URL"),
+			Collection: provider.MoMoCredentials{
+				SubscriptionKey: os.Getenv("MOMO_COLLECTION_SUBSCRIPTION_KEY"),
+				APIUser:         os.Getenv("MOMO_COLLECTION_API_USER"),
+				APIKey:          os.Getenv("MOMO_COLLECTION_API_KEY"),
+			},
+			Disbursement: provider.MoMoCredentials{
+				SubscriptionKey: os.Getenv("MOMO_DISBURSEMENT_SUBSCRIPTION_KEY"),
+				APIUser:         os.Getenv("MOMO_DISBURSEMENT_API_USER"),
+				APIKey:          os.Getenv("MOMO_DISBURSEMENT_API_KEY"),
+			},
+		})
+	}
+
+	// the simulator reports its asynchronous results through OnResult (see NewPaymentUseCase)
+	return provider.NewSimulator(provider.SimulatorConfig{
+		Behaviour:    provider.Behaviour(os.Getenv("PAYMENT_SIMULATOR_BEHAVIOUR")),
+		AsyncDelay:   5 * time.Second,
+		AsyncOutcome: provider.Behaviour(os.Getenv("PAYMENT_SIMULATOR_ASYNC_OUTCOME")),
+	})
+}
+
+// NewPaymentUseCase is a function that sets up the payment use case
+func NewPaymentUseCase(paymentRepo paymentPkg.Repository, ledgerRepo ledgerPkg.Repository, paymentProvider paymentPkg.PaymentProvider, limitUseCase *pkg.LimitUseCase, fraudUseCase *pkg.FraudUseCase) *pkg.PaymentUseCase {
+	// create a guard for merchant-supplied URLs
+	urlGuard := netguard.NewGuard(netguard.ConfigFromEnv())
+
+	// create a new payment use case whose payments count against the limits of their source and pass the fraud checks
+	paymentUseCase := pkg.NewPaymentUseCase(paymentRepo, ledgerRepo, urlGuard, paymentProvider)
+	paymentUseCase.SetLimits(limitUseCase)
+	paymentUseCase.SetFraud(fraudUseCase)
+
+	// complete pending payments when the simulator reports back (live providers send callbacks instead)
+	if simulator, ok := paymentProvider.(*provider.Simulator); ok {
+		simulator.OnResult(func(result *paymentPkg.ProviderResult) {
+			if _, err := paymentUseCase.HandleProviderResult(result); err != nil {
+				log.Printf("failed to handle provider result for %s: %v", result.Reference, err)
+			}
+		})
+	}
+
+	return paymentUseCase
+}
+
+// NewCallbackUseCase is a function that sets up the inbound webhook use case. The configured provider's
+// callbacks are accepted at its PAYMENT_PROVIDER name once PAYMENT_CALLBACK_SECRET is set; they must carry the
+// HMAC-SHA256 of their body in PAYMENT_CALLBACK_SIGNATURE_HEADER, and their event ID in
+// PAYMENT_CALLBACK_EVENT_HEADER when the provider sends one.
+func NewCallbackUseCase(paymentUseCase *pkg.PaymentUseCase, paymentProvider paymentPkg.PaymentProvider) *pkg.CallbackUseCase {
+	// create a new callback repository (with a database configuration)
+	callbackRepo := callback.NewRepository(
+		callbackAdapter.WithRedisCallbackDatabase(os.Getenv("REDIS_URI")),
+	)
+
+	callbackUseCase := pkg.NewCallbackUseCase(callbackRepo, paymentUseCase)
+
+	parser, ok := paymentProvider.(paymentPkg.CallbackParser)
+	secret := os.Getenv("PAYMENT_CALLBACK_SECRET")
+	if !ok || secret == "" {
+		// unsigned callbacks are never accepted
+		return callbackUseCase
+	}
+
+	header := os.Getenv("PAYMENT_CALLBACK_SIGNATURE_HEADER")
+	if header == "" {
+		header = defaultCallbackSignatureHeader
+	}
+
+	callbackUseCase.Register(pkg.CallbackProvider{
+		Name:          os.Getenv("PAYMENT_PROVIDER"),
+		Parser:        parser,
+		Verifier:      &callbackPkg.HMACVerifier{Header: header, Secret: []byte(secret)},
+		EventIDHeader: os.Getenv("PAYMENT_CALLBACK_EVENT_HEADER"),
+	})
+
+	return callbackUseCase
+}
+
+// NewScheduleUseCase is a function that sets up the scheduled payment use case
+func NewScheduleUseCase(paymentRepo paymentPkg.Repository, paymentUseCase *pkg.PaymentUseCase) *pkg.ScheduleUseCase {
+	// create a new schedule repository (with a database configuration)
+	scheduleRepo := schedule.NewRepository(
+		scheduleAdapter.WithRedisScheduleDatabase(os.Getenv("REDIS_URI")),
+	)
+
+	return pkg.NewScheduleUseCase(scheduleRepo, paymentRepo, paymentUseCase)
+}
+
+// NewInvoiceUseCase is a function that sets up the invoice and payment link use case. Invoices are issued in
+// the currencies of INVOICE_CURRENCIES, or in the provider's MOMO_CURRENCY when it is not set.
+func NewInvoiceUseCase(paymentUseCase *pkg.PaymentUseCase) *pkg.InvoiceUseCase {
+	// create a new invoice repository (with a database configuration)
+	invoiceRepo := invoice.NewRepository(
+		invoiceAdapter.WithRedisInvoiceDatabase(os.Getenv("REDIS_URI")),
+	)
+
+	currencies := os.Getenv("INVOICE_CURRENCIES")
+	if currencies == "" {
+		currencies = os.Getenv("MOMO_CURRENCY")
+	}
+
+	var config pkg.InvoiceConfig
+	for _, currency := range strings.Split(currencies, ",") {
+		if currency = strings.TrimSpace(currency); currency != "" {
+			config.Currencies = append(config.Currencies, currency)
+		}
+	}
+
+	return pkg.NewInvoiceUseCase(invoiceRepo, paymentUseCase, config)
+}
+
+// NewEscrowUseCase is a function that sets up the escrow use case. Escrow accounts live in the shared ledger.
+func NewEscrowUseCase(ledgerRepo ledgerPkg.Repository, paymentUseCase *pkg.PaymentUseCase) *pkg.EscrowUseCase {
+	// create a new escrow repository (with a database configuration)
+	escrowRepo := escrow.NewRepository(
+		escrowAdapter.WithRedisEscrowDatabase(os.Getenv("REDIS_URI")),
+	)
+
+	return pkg.NewEscrowUseCase(escrowRepo, ledgerRepo, paymentUseCase)
+}
+
+// NewReconciliationUseCase is a function that sets up the reconciliation use case. Settled amounts may be
+// RECONCILIATION_TOLERANCE away from ours, or RECONCILIATION_TOLERANCE_PERCENT of ours when that is larger.
+func NewReconciliationUseCase(paymentRepo paymentPkg.Repository) *pkg.ReconciliationUseCase {
+	// create a new reconciliation repository (with a database configuration)
+	reconciliationRepo := reconciliation.NewRepository(
+		reconciliationAdapter.WithRedisReconciliationDatabase(os.Getenv("REDIS_URI")),
+	)
+
+	return pkg.NewReconciliationUseCase(reconciliationRepo, paymentRepo, pkg.ReconciliationConfig{
+		Tolerance: reconciliationPkg.Tolerance{
+			Amount:  getEnvAmount("RECONCILIATION_TOLERANCE", 0),
+			Percent: getEnvAmount("RECONCILIATION_TOLERANCE_PERCENT", 0),
+		},
+	})
+}
+
+// NewDisputeUseCase is a function that sets up the dispute use case. Disputed amounts are held for
+// DISPUTE_HOLD_EXPIRY and merchants have DISPUTE_EVIDENCE_WINDOW to submit evidence when no deadline is given.
+func NewDisputeUseCase(ledgerRepo ledgerPkg.Repository, paymentRepo paymentPkg.Repository) *pkg.DisputeUseCase {
+	// create a new dispute repository (with a database configuration)
+	disputeRepo := dispute.NewRepository(
+		disputeAdapter.WithRedisDisputeDatabase(os.Getenv("REDIS_URI")),
+	)
+
+	return pkg.NewDisputeUseCase(disputeRepo, ledgerRepo, paymentRepo, pkg.DisputeConfig{
+		HoldExpiry:     GetEnvDuration("DISPUTE_HOLD_EXPIRY", 0),
+		EvidenceWindow: GetEnvDuration("DISPUTE_EVIDENCE_WINDOW", 0),
+	})
+}
+
+// NewStatementUseCase is a function that sets up the account statement use case. Balances are shown in
+// STATEMENT_CURRENCY (MOMO_CURRENCY when it is not set) and statements are issued under STATEMENT_INSTITUTION.
+func NewStatementUseCase(ledgerRepo ledgerPkg.Repository) *pkg.StatementUseCase {
+	// create a new statement repository (with a database configuration)
+	statementRepo := statement.NewRepository(
+		statementAdapter.WithRedisStatementDatabase(os.Getenv("REDIS_URI")),
+	)
+
+	currency := os.Getenv("STATEMENT_CURRENCY")
+	if currency == "" {
+		currency = os.Getenv("MOMO_CURRENCY")
+	}
+
+	return pkg.NewStatementUseCase(statementRepo, ledgerRepo, pkg.StatementConfig{
+		Currency:    currency,
+		Institution: os.Getenv("STATEMENT_INSTITUTION"),
+		MaxPeriod:   GetEnvDuration("STATEMENT_MAX_PERIOD", 0),
+	})
+}
+
+// NewProductUseCase is a function that sets up the account product use case. The end-of-day batch charges
+// transfer fees from the transfers of each business date and runs at most EOD_MAX_CATCH_UP_DAYS missed dates at
+// once. Accounts moved to another product keep an overdraft within its maximum.
+func NewProductUseCase(ledgerRepo ledgerPkg.Repository, overdraftUseCase *pkg.OverdraftUseCase) *pkg.ProductUseCase {
+	// create a new product repository (with a database configuration)
+	productRepo := product.NewRepository(
+		productAdapter.WithRedisProductDatabase(os.Getenv("REDIS_URI")),
+	)
+
+	// the fees of transfers are charged from the transfer repository
+	transferRepo := transfer.NewRepository(
+		transferAdapter.WithRedisTransferDatabase(os.Getenv("REDIS_URI")),
+	)
+
+	productUseCase := pkg.NewProductUseCase(productRepo, ledgerRepo, transferRepo, pkg.ProductConfig{
+		MaxCatchUpDays: getEnvInt("EOD_MAX_CATCH_UP_DAYS", 0),
+	})
+	productUseCase.SetOverdrafts(overdraftUseCase)
+
+	return productUseCase
+}
+
+// NewOverdraftUseCase is a function that sets up the overdraft use case. Limits are approved within the
+// maximum of the account's product, and accounts entering or leaving their overdraft are notified through the
+// payment repository's event queue.
+func NewOverdraftUseCase(ledgerRepo ledgerPkg.Repository, paymentRepo paymentPkg.Repository) *pkg.OverdraftUseCase {
+	// create a new overdraft repository (with a database configuration)
+	overdraftRepo := overdraft.NewRepository(
+		overdraftAdapter.WithRedisOverdraftDatabase(os.Getenv("REDIS_URI")),
+	)
+
+	// the maximum limits come from the products of the accounts
+	productRepo := product.NewRepository(
+		productAdapter.WithRedisProductDatabase(os.Getenv("REDIS_URI")),
+	)
+
+	return pkg.NewOverdraftUseCase(overdraftRepo, productRepo, ledgerRepo, paymentRepo)
+}
+
+// NewCardUseCase is a function that sets up the virtual card use case. Card numbers are sealed in the vault
+// with the 32 bytes CARD_VAULT_KEY (hex encoded) and issued in the CARD_BIN_RANGE (e.g. 400000-400099). Cards
+// are frozen after CARD_MAX_FAILED_ATTEMPTS charges in a row with wrong details, and are off (nil) when no
+// vault key is configured.
+func NewCardUseCase(ledgerRepo ledgerPkg.Repository) *pkg.CardUseCase {
+	rawKey := os.Getenv("CARD_VAULT_KEY")
+	if rawKey == "" {
+		return nil
+	}
+
+	// create a new card repository (with a database configuration), which also stores the vault
+	cardRepo := card.NewRepository(
+		cardAdapter.WithRedisCardDatabase(os.Getenv("REDIS_URI")),
+	)
+
+	key, err := hex.DecodeString(rawKey)
+	if err != nil {
+		log.Fatalf("failed to decode the card vault key: %v", err)
+	}
+	vault, err := cardPkg.NewVault(cardRepo, key)
+	if err != nil {
+		log.Fatalf("failed to open the card vault: %v", err)
+	}
+
+	var bins cardPkg.BINRange
+	if raw := os.Getenv("CARD_BIN_RANGE"); raw != "" {
+		if bins, err = cardPkg.ParseBINRange(raw); err != nil {
+			log.Fatalf("failed to parse the card BIN range %s: %v", raw, err)
+		}
+	}
+
+	// the spend controls of cards are counted by the limit repository
+	limitRepo := limit.NewRepository(
+		limitAdapter.WithRedisLimitDatabase(os.Getenv("REDIS_URI")),
+	)
+
+	return pkg.NewCardUseCase(cardRepo, ledgerRepo, limitRepo, vault, pkg.CardConfig{
+		BINs:              bins,
+		PANLength:         getEnvInt("CARD_PAN_LENGTH", 0),
+		ValidityYears:     getEnvInt("CARD_VALIDITY_YEARS", 0),
+		MaxFailedAttempts: getEnvInt("CARD_MAX_FAILED_ATTEMPTS", 0),
+	})
+}
+
+// NewAccountRoles is a function that reads the roles of the operator accounts from ACCOUNT_ROLES, a
+// comma-separated list of account IDs and their role as id=role (admin or analyst)
+func NewAccountRoles() map[string]pkg.Role {
+	roles := make(map[string]pkg.Role)
+	for _, account := range parseNamedValues(os.Getenv("ACCOUNT_ROLES")) {
+		switch role := pkg.Role(account.value); role {
+		case pkg.RoleAdmin, pkg.RoleAnalyst:
+			roles[account.name] = role
+		default:
+			log.Printf("ignoring unknown role %q of account %s", account.value, account.name)
+		}
+	}
+
+	return roles
+}
+
+// NewMerchantAccounts is a function that reads the merchants accounts act for from MERCHANT_ACCOUNTS, a
+// comma-separated list of account IDs and the URL their merchant's payments notify as id=url
+func NewMerchantAccounts() map[string]string {
+	merchants := make(map[string]string)
+	for _, account := range parseNamedValues(os.Getenv("MERCHANT_ACCOUNTS")) {
+		if account.name == "" || account.value == "" {
+			log.Printf("ignoring merchant account %q without an account ID or URL", account.name+"="+account.value)
+			continue
+		}
+		merchants[account.name] = account.value
+	}
+
+	return merchants
+}
+
+// NewAccountRepository is a function that sets up the account repository
+func NewAccountRepository() accountPkg.Repository {
+	// create a new password helper utility
+	pwHelper := account.NewBcryptPasswordHelper()
+
+	// use the password helper utility to create a new account repository (with a database configuration)
+	return account.NewRepository(
+		//accountAdapter.WithMongoAccountDatabase(os.Getenv("MONGO_URI"), pwHelper),
+		accountAdapter.WithPostgresAccountDatabase(os.Getenv("POSTGRES_URI"), pwHelper),
+	)
+}
+
+// NewBeneficiaryUseCase is a function that sets up the beneficiary use case
+func NewBeneficiaryUseCase(accountRepo accountPkg.Repository, paymentProvider paymentPkg.PaymentProvider, screeningUseCase *pkg.ScreeningUseCase) *pkg.BeneficiaryUseCase {
+	// create a new beneficiary repository (with a database configuration)
+	beneficiaryRepo := beneficiary.NewRepository(
+		beneficiaryAdapter.WithRedisBeneficiaryDatabase(os.Getenv("REDIS_URI")),
+	)
+
+	// names are confirmed against our accounts, the provider's wallets and the bank directory when one is configured
+	resolvers := map[beneficiaryPkg.DestinationType]beneficiaryPkg.NameResolver{
+		beneficiaryPkg.DestinationInternalAccount: resolver.NewAccountResolver(accountRepo),
+	}
+	if lookup, ok := paymentProvider.(resolver.AccountHolderLookup); ok {
+		resolvers[beneficiaryPkg.DestinationMobileWallet] = resolver.NewWalletResolver(lookup)
+	}
+	if path := os.Getenv("BANK_DIRECTORY_FILE"); path != "" {
+		directory, err := resolver.LoadDirectoryResolver(path)
+		if err != nil {
+			log.Printf("failed to load bank directory %s: %v", path, err)
+		} else {
+			resolvers[beneficiaryPkg.DestinationBankAccount] = directory
+		}
+	}
+
+	beneficiaryUseCase := pkg.NewBeneficiaryUseCase(beneficiaryRepo, resolvers, pkg.BeneficiaryConfig{
+		CoolingOff:  GetEnvDuration("BENEFICIARY_COOLING_OFF", defaultBeneficiaryCoolingOff),
+		LargeAmount: getEnvAmount("BENEFICIARY_LARGE_AMOUNT", defaultBeneficiaryLargeAmount),
+	})
+	beneficiaryUseCase.SetScreening(screeningUseCase)
+
+	return beneficiaryUseCase
+}
+
+// NewTransferUseCase is a function that sets up the transfer use case. Transfer results reported by the
+// provider are routed to it by the payment use case, and the overdrafts of its accounts are checked as it moves money.
+// Completed transfers are notified through the payment use case's event queue. Transfers to bank accounts are exported for the partner
+// bank from ISO20022_DEBTOR_ACCOUNT (IBAN or account number) at ISO20022_DEBTOR_AGENT (BIC or bank code).
+func NewTransferUseCase(ledgerRepo ledgerPkg.Repository, beneficiaryUseCase *pkg.BeneficiaryUseCase, limitUseCase *pkg.LimitUseCase, screeningUseCase *pkg.ScreeningUseCase, paymentProvider paymentPkg.PaymentProvider, paymentUseCase *pkg.PaymentUseCase, overdraftUseCase *pkg.OverdraftUseCase) *pkg.TransferUseCase {
+	// create a new transfer repository (with a database configuration)
+	transferRepo := transfer.NewRepository(
+		transferAdapter.WithRedisTransferDatabase(os.Getenv("REDIS_URI")),
+	)
+
+	// external transfers need a provider that can disburse
+	payouts, _ := paymentProvider.(paymentPkg.PayoutProvider)
+	transferUseCase := pkg.NewTransferUseCase(transferRepo, ledgerRepo, beneficiaryUseCase, payouts)
+	transferUseCase.SetLimits(limitUseCase)
+	transferUseCase.SetScreening(screeningUseCase)
+	transferUseCase.SetOverdrafts(overdraftUseCase)
+	transferUseCase.SetNotifications(paymentUseCase)
+	paymentUseCase.RouteResults(transferPkg.IsTransferReference, transferUseCase.HandleProviderResult)
+
+	// transfers to bank accounts are exported as pain.001 files when the partner bank account is configured
+	if account := os.Getenv("ISO20022_DEBTOR_ACCOUNT"); account != "" {
+		currency := os.Getenv("ISO20022_CURRENCY")
+		if currency == "" {
+			currency = os.Getenv("MOMO_CURRENCY")
+		}
+
+		transferUseCase.SetBankExport(&pkg.BankExportConfig{
+			Debtor: iso20022.Debtor{
+				Name:    os.Getenv("ISO20022_DEBTOR_NAME"),
+				Account: account,
+				Agent:   os.Getenv("ISO20022_DEBTOR_AGENT"),
+			},
+			Currency: currency,
+		})
+	}
+
+	return transferUseCase
+}
+
+// NewPayoutUseCase is a function that sets up the bulk payout use case. Payout item results reported by the
+// provider are routed to it by the payment use case.
+func NewPayoutUseCase(ledgerRepo ledgerPkg.Repository, screeningUseCase *pkg.ScreeningUseCase, paymentProvider paymentPkg.PaymentProvider, paymentUseCase *pkg.PaymentUseCase) *pkg.PayoutUseCase {
+	// create a new payout repository (with a database configuration)
+	payoutRepo := payout.NewRepository(
+		payoutAdapter.WithRedisPayoutDatabase(os.Getenv("REDIS_URI")),
+	)
+
+	// only rows to internal accounts can be paid when the provider cannot disburse
+	payouts, _ := paymentProvider.(paymentPkg.PayoutProvider)
+	payoutUseCase := pkg.NewPayoutUseCase(payoutRepo, ledgerRepo, payouts, pkg.PayoutConfig{
+		MaxItems: getEnvInt("PAYOUT_BATCH_MAX_ITEMS", 0),
+	})
+	payoutUseCase.SetScreening(screeningUseCase)
+	paymentUseCase.RouteResults(payoutPkg.IsItemReference, payoutUseCase.HandleProviderResult)
+
+	return payoutUseCase
+}
+
+// NewLimitUseCase is a function that sets up the transaction limit use case
+func NewLimitUseCase() *pkg.LimitUseCase {
+	// create a new limit repository (with a database configuration)
+	limitRepo := limit.NewRepository(
+		limitAdapter.WithRedisLimitDatabase(os.Getenv("REDIS_URI")),
+	)
+
+	// the tiers come from LIMITS_CONFIG_FILE, or the built-in defaults
+	config := limitPkg.DefaultConfig()
+	if path := os.Getenv("LIMITS_CONFIG_FILE"); path != "" {
+		loaded, err := limitPkg.LoadConfig(path)
+		if err != nil {
+			log.Fatalf("failed to load limits configuration %s: %v", path, err)
+		}
+		config = loaded
+	}
+
+	return pkg.NewLimitUseCase(limitRepo, config)
+}
+
+// NewFraudUseCase is a function that sets up the fraud scoring use case
+func NewFraudUseCase() *pkg.FraudUseCase {
+	// create a new fraud repository (with a database configuration)
+	fraudRepo := fraud.NewRepository(
+		fraudAdapter.WithRedisFraudDatabase(os.Getenv("REDIS_URI")),
+	)
+
+	// the rules come from FRAUD_RULES_FILE, or the built-in defaults
+	config := fraudPkg.DefaultConfig()
+	if path := os.Getenv("FRAUD_RULES_FILE"); path != "" {
+		loaded, err := fraudPkg.LoadConfig(path)
+		if err != nil {
+			log.Fatalf("failed to load fraud rules %s: %v", path, err)
+		}
+		config = loaded
+	}
+
+	// IP addresses are located from GEOIP_RANGES_FILE when it is set, otherwise the geo mismatch rule is off
+	var geo fraudPkg.GeoLocator
+	if path := os.Getenv("GEOIP_RANGES_FILE"); path != "" {
+		locator, err := geoip.LoadRangeLocator(path)
+		if err != nil {
+			log.Printf("failed to load IP ranges %s: %v", path, err)
+		} else {
+			geo = locator
+		}
+	}
+
+	return pkg.NewFraudUseCase(fraudRepo, config, geo)
+}
+
+// NewScreeningUseCase is a function that sets up the sanctions screening use case from the lists in
+// SANCTIONS_LISTS, a comma-separated list of files optionally named as name=path. Screening is off (nil)
+// when no list is configured.
+func NewScreeningUseCase() *pkg.ScreeningUseCase {
+	files := os.Getenv("SANCTIONS_LISTS")
+	if files == "" {
+		return nil
+	}
+
+	var entries [][]screeningPkg.Entry
+	for _, file := range parseNamedValues(files) {
+		loaded, err := lists.Load(file.value, file.name)
+		if err != nil {
+			log.Fatalf("failed to load sanctions list %s: %v", file.value, err)
+		}
+		entries = append(entries, loaded)
+	}
+
+	// names must score SANCTIONS_THRESHOLD (or the list's threshold in SANCTIONS_LIST_THRESHOLDS) to be a hit
+	config := screeningPkg.Config{Threshold: screeningPkg.DefaultThreshold, ListThresholds: make(map[string]float64)}
+	if threshold, err := strconv.ParseFloat(os.Getenv("SANCTIONS_THRESHOLD"), 64); err == nil && threshold > 0 && threshold <= 1 {
+		config.Threshold = threshold
+	}
+	for _, list := range parseNamedValues(os.Getenv("SANCTIONS_LIST_THRESHOLDS")) {
+		if threshold, err := strconv.ParseFloat(list.value, 64); err == nil && threshold > 0 && threshold <= 1 {
+			config.ListThresholds[list.name] = threshold
+		}
+	}
+
+	watchlist := screeningPkg.NewWatchlist(config, entries...)
+	log.Printf("screening against %d sanctions list entries", watchlist.Len())
+
+	// create a new screening repository (with a database configuration)
+	screeningRepo := screening.NewRepository(
+		screeningAdapter.WithRedisScreeningDatabase(os.Getenv("REDIS_URI")),
+	)
+
+	return pkg.NewScreeningUseCase(screeningRepo, watchlist)
+}
+
+// namedValue is a value of an environment variable, optionally named as name=value
+type namedValue struct {
+	name, value string
+}
+
+// parseNamedValues parses a comma-separated list of values optionally named as name=value. Values without
+// a name have an empty name, so that list files can default to their file name.
+func parseNamedValues(raw string) []namedValue {
+	var values []namedValue
+	for _, pair := range strings.Split(raw, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+
+		name, value, found := strings.Cut(pair, "=")
+		if !found {
+			name, value = "", name
+		}
+		values = append(values, namedValue{name: strings.TrimSpace(name), value: strings.TrimSpace(value)})
+	}
+
+	return values
+}
+
+// GetEnvDuration reads a positive duration (e.g. "30s") from the environment, falling back to the given default
+func GetEnvDuration(key string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+
+	return value
+}
+
+// getEnvAmount reads a positive amount from the environment, falling back to the given default
+func getEnvAmount(key string, fallback float32) float32 {
+	value, err := strconv.ParseFloat(os.Getenv(key), 32)
+	if err != nil || value <= 0 {
+		return fallback
+	}
+
+	return float32(value)
+}
+
+// getEnvInt reads a positive integer from the environment, falling back when it is unset or invalid
+func getEnvInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+
+	return value
+}
@@ -5,6 +5,22 @@ import (
 	"github.com/quabynah-bilson/quantia/interfaces/http/models"
 	"github.com/quabynah-bilson/quantia/pkg"
 	"net/http"
+	"strings"
+)
+
+// AccountIDHeader is the header that names the account a bearer token was issued to
+const AccountIDHeader = "X-Account-ID"
+
+const (
+	// accountIDKey is the context key RequireAuth stores the authenticated account ID under
+	accountIDKey = "account_id"
+
+	// adminKey is the context key RequireAuth stores whether the authenticated account is an admin under
+	adminKey = "admin"
+
+	// merchantKey is the context key RequireAuth stores the ledger account of the authenticated account's
+	// merchant under
+	merchantKey = "merchant"
 )
 
 // AuthHandler is a struct that holds the dependencies for the auth handlers
@@ -22,7 +38,7 @@ func NewAuthHandler(useCase *pkg.AuthUseCase) *AuthHandler {
 func (h *AuthHandler) RegisterHandler(c *gin.Context) {
 	// parse the request body into the RegistrationRequest struct.
 	// if there is an error, return a 400 Bad Request error
-	var regReq models.AuthenticationRequest
+	var regReq models.RegistrationRequest
 	if err := c.ShouldBindJSON(&regReq); err != nil {
 		c.JSON(http.StatusBadRequest, &models.APIResponse{Error: &models.APIError{
 			Message: err.Error(),
@@ -32,7 +48,7 @@ func (h *AuthHandler) RegisterHandler(c *gin.Context) {
 	}
 
 	// call the use case to register the user
-	authToken, err := h.useCase.Register(regReq.Username, regReq.Password)
+	authToken, err := h.useCase.Register(regReq.Username, regReq.Password, regReq.Name)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, &models.APIResponse{Error: &models.APIError{
 			Message: err.Error(),
@@ -126,3 +142,74 @@ func (h *AuthHandler) LogoutHandler(c *gin.Context) {
 		Message: "Successfully logged out",
 	})
 }
+
+// RequireAuth is a function that returns a middleware rejecting requests without a valid bearer token for the
+// account in the AccountIDHeader, and, when roles are given, requests of accounts holding none of them
+func RequireAuth(useCase *pkg.AuthUseCase, roles ...pkg.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authToken := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		accountID := c.GetHeader(AccountIDHeader)
+		if authToken == "" || accountID == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, &models.APIResponse{Error: &models.APIError{
+				Message: "No authorization token provided",
+				Code:    http.StatusUnauthorized}},
+			)
+			return
+		}
+
+		if err := useCase.ValidateToken(authToken, accountID); err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, &models.APIResponse{Error: &models.APIError{
+				Message: err.Error(),
+				Code:    http.StatusUnauthorized}},
+			)
+			return
+		}
+
+		if len(roles) > 0 {
+			if err := useCase.CheckRole(accountID, roles...); err != nil {
+				c.AbortWithStatusJSON(http.StatusForbidden, &models.APIResponse{Error: &models.APIError{
+					Message: err.Error(),
+					Code:    http.StatusForbidden}},
+				)
+				return
+			}
+		}
+
+		c.Set(accountIDKey, accountID)
+		c.Set(adminKey, useCase.CheckRole(accountID, pkg.RoleAdmin) == nil)
+		c.Set(merchantKey, useCase.MerchantAccount(accountID))
+		c.Next()
+	}
+}
+
+// authenticatedAccount returns the ID of the account RequireAuth authenticated the request for
+func authenticatedAccount(c *gin.Context) string {
+	return c.GetString(accountIDKey)
+}
+
+// requireAccount reports whether the authenticated account, or the ledger account of the merchant it acts
+// for, is one of the given accounts, writing a 403 Forbidden error when it is not
+func requireAccount(c *gin.Context, accountIDs ...string) bool {
+	caller, merchant := authenticatedAccount(c), c.GetString(merchantKey)
+	for _, accountID := range accountIDs {
+		if accountID != "" && (accountID == caller || accountID == merchant) {
+			return true
+		}
+	}
+
+	c.AbortWithStatusJSON(http.StatusForbidden, &models.APIResponse{Error: &models.APIError{
+		Message: pkg.ErrAccountNotOwned.Error(),
+		Code:    http.StatusForbidden}},
+	)
+	return false
+}
+
+// requireAccountOrAdmin reports whether the authenticated account is one of the given accounts or an admin,
+// writing a 403 Forbidden error when it is neither
+func requireAccountOrAdmin(c *gin.Context, accountIDs ...string) bool {
+	if c.GetBool(adminKey) {
+		return true
+	}
+
+	return requireAccount(c, accountIDs...)
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/quabynah-bilson/quantia/interfaces/http/models"
+	"github.com/quabynah-bilson/quantia/pkg"
+	"github.com/quabynah-bilson/quantia/pkg/beneficiary"
+	"net/http"
+)
+
+// BeneficiaryHandler is a struct that holds the dependencies for the beneficiary handlers
+type BeneficiaryHandler struct {
+	useCase *pkg.BeneficiaryUseCase
+}
+
+// NewBeneficiaryHandler is a function that creates a new beneficiary handler
+func NewBeneficiaryHandler(useCase *pkg.BeneficiaryUseCase) *BeneficiaryHandler {
+	return &BeneficiaryHandler{useCase: useCase}
+}
+
+// CreateBeneficiaryHandler is a function that confirms and saves a beneficiary
+func (h *BeneficiaryHandler) CreateBeneficiaryHandler(c *gin.Context) {
+	// parse the request body into the CreateBeneficiaryRequest struct.
+	// if there is an error, return a 400 Bad Request error
+	var beneficiaryReq models.CreateBeneficiaryRequest
+	if err := c.ShouldBindJSON(&beneficiaryReq); err != nil {
+		c.JSON(http.StatusBadRequest, &models.APIResponse{Error: &models.APIError{
+			Message: err.Error(),
+			Code:    http.StatusBadRequest}},
+		)
+		return
+	}
+
+	// call the use case to save the beneficiary of the caller's account
+	b, err := h.useCase.AddBeneficiary(authenticatedAccount(c), beneficiaryReq.Nickname, beneficiaryReq.DestinationType,
+		beneficiaryReq.Destination, beneficiaryReq.BankCode, beneficiaryReq.Name)
+	if err != nil {
+		writeBeneficiaryError(c, err)
+		return
+	}
+
+	// return a 201 Created response
+	c.JSON(http.StatusCreated, &models.APIResponse{
+		Success: true,
+		Message: "Beneficiary saved",
+		Data:    &models.BeneficiaryResponse{Beneficiary: b},
+	})
+}
+
+// GetBeneficiariesHandler is a function that lists the beneficiaries of the authenticated account
+func (h *BeneficiaryHandler) GetBeneficiariesHandler(c *gin.Context) {
+	beneficiaries, err := h.useCase.GetBeneficiaries(authenticatedAccount(c))
+	if err != nil {
+		writeBeneficiaryError(c, err)
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Data:    &models.BeneficiariesResponse{Beneficiaries: beneficiaries},
+	})
+}
+
+// GetBeneficiaryHandler is a function that returns a beneficiary of the authenticated account
+func (h *BeneficiaryHandler) GetBeneficiaryHandler(c *gin.Context) {
+	b, err := h.useCase.GetBeneficiary(authenticatedAccount(c), c.Param("id"))
+	if err != nil {
+		writeBeneficiaryError(c, err)
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Data:    &models.BeneficiaryResponse{Beneficiary: b},
+	})
+}
+
+// DeleteBeneficiaryHandler is a function that removes a beneficiary of the authenticated account
+func (h *BeneficiaryHandler) DeleteBeneficiaryHandler(c *gin.Context) {
+	if err := h.useCase.RemoveBeneficiary(authenticatedAccount(c), c.Param("id")); err != nil {
+		writeBeneficiaryError(c, err)
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Message: "Beneficiary removed",
+	})
+}
+
+// writeBeneficiaryError maps a beneficiary error to its status code
+func writeBeneficiaryError(c *gin.Context, err error) {
+	code := http.StatusBadRequest
+	switch {
+	case errors.Is(err, beneficiary.ErrBeneficiaryNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, beneficiary.ErrBeneficiaryAlreadyExists):
+		code = http.StatusConflict
+	case errors.Is(err, beneficiary.ErrDestinationNotFound), errors.Is(err, beneficiary.ErrNameMismatch):
+		code = http.StatusUnprocessableEntity
+	case errors.Is(err, beneficiary.ErrResolverUnavailable):
+		code = http.StatusServiceUnavailable
+	}
+
+	c.JSON(code, &models.APIResponse{Error: &models.APIError{
+		Message: err.Error(),
+		Code:    code}},
+	)
+}
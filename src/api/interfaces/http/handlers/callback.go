@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/quabynah-bilson/quantia/interfaces/http/models"
+	"github.com/quabynah-bilson/quantia/pkg"
+	"github.com/quabynah-bilson/quantia/pkg/callback"
+	"github.com/quabynah-bilson/quantia/pkg/payment"
+	"io"
+	"net/http"
+)
+
+// CallbackHandler is a struct that holds the dependencies for the inbound webhook handlers
+type CallbackHandler struct {
+	useCase *pkg.CallbackUseCase
+}
+
+// NewCallbackHandler is a function that creates a new inbound webhook handler
+func NewCallbackHandler(useCase *pkg.CallbackUseCase) *CallbackHandler {
+	return &CallbackHandler{useCase: useCase}
+}
+
+// ReceiveCallbackHandler is a function that receives a signed callback from the provider named in the path
+func (h *CallbackHandler) ReceiveCallbackHandler(c *gin.Context) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		writeCallbackError(c, payment.ErrInvalidCallback)
+		return
+	}
+
+	cb, err := h.useCase.Receive(c.Param("provider"), c.Request.Header, body)
+	if errors.Is(err, callback.ErrDuplicateCallback) {
+		// return a 200 OK response so that the provider stops retrying
+		c.JSON(http.StatusOK, &models.APIResponse{Success: true, Message: "Callback already received"})
+		return
+	}
+	if err != nil {
+		writeCallbackError(c, err)
+		return
+	}
+
+	// return a 200 OK response so that the provider stops retrying
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Data:    &models.CallbackResponse{Callback: cb},
+	})
+}
+
+// GetCallbackHandler is a function that returns a received callback, with its raw payload
+func (h *CallbackHandler) GetCallbackHandler(c *gin.Context) {
+	cb, err := h.useCase.GetCallback(c.Param("id"))
+	if err != nil {
+		writeCallbackError(c, err)
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Data:    &models.CallbackResponse{Callback: cb},
+	})
+}
+
+// writeCallbackError maps a callback error to its status code. Callbacks that could not be applied get a
+// server error, so that the provider delivers them again.
+func writeCallbackError(c *gin.Context, err error) {
+	code := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, pkg.ErrUnknownCallbackProvider), errors.Is(err, callback.ErrCallbackNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, callback.ErrInvalidSignature):
+		code = http.StatusUnauthorized
+	case errors.Is(err, payment.ErrInvalidCallback):
+		code = http.StatusBadRequest
+	}
+
+	c.JSON(code, &models.APIResponse{Error: &models.APIError{
+		Message: err.Error(),
+		Code:    code}},
+	)
+}
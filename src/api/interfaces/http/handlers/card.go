@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/quabynah-bilson/quantia/interfaces/http/models"
+	"github.com/quabynah-bilson/quantia/pkg"
+	"github.com/quabynah-bilson/quantia/pkg/card"
+	"net/http"
+)
+
+// CardHandler is a struct that holds the dependencies for the card handlers
+type CardHandler struct {
+	useCase *pkg.CardUseCase
+}
+
+// NewCardHandler is a function that creates a new card handler
+func NewCardHandler(useCase *pkg.CardUseCase) *CardHandler {
+	return &CardHandler{useCase: useCase}
+}
+
+// IssueCardHandler is a function that issues a virtual card linked to an account
+func (h *CardHandler) IssueCardHandler(c *gin.Context) {
+	// parse the request body into the IssueCardRequest struct.
+	// if there is an error, return a 400 Bad Request error
+	var cardReq models.IssueCardRequest
+	if !bindJSON(c, &cardReq) {
+		return
+	}
+
+	// cards may only be issued on the caller's own account
+	if !requireAccount(c, cardReq.AccountID) {
+		return
+	}
+
+	issued, err := h.useCase.Issue(cardReq.AccountID, cardReq.Controls)
+	if err != nil {
+		writeCardError(c, err)
+		return
+	}
+
+	// return a 201 Created response. The PAN and CVV are not shown again.
+	c.JSON(http.StatusCreated, &models.APIResponse{
+		Success: true,
+		Message: "Card issued",
+		Data:    &models.IssuedCardResponse{IssuedCard: issued},
+	})
+}
+
+// GetCardHandler is a function that returns a card
+func (h *CardHandler) GetCardHandler(c *gin.Context) {
+	cd, ok := h.card(c)
+	if !ok {
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Data:    &models.CardResponse{Card: cd},
+	})
+}
+
+// GetAccountCardsHandler is a function that returns the cards of the account_id query parameter
+func (h *CardHandler) GetAccountCardsHandler(c *gin.Context) {
+	if !requireAccount(c, c.Query("account_id")) {
+		return
+	}
+
+	cards, err := h.useCase.GetAccountCards(c.Query("account_id"))
+	if err != nil {
+		writeCardError(c, err)
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Data:    &models.CardsResponse{Cards: cards},
+	})
+}
+
+// FreezeCardHandler is a function that freezes a card
+func (h *CardHandler) FreezeCardHandler(c *gin.Context) {
+	if _, ok := h.card(c); !ok {
+		return
+	}
+
+	cd, err := h.useCase.Freeze(c.Param("id"))
+	if err != nil {
+		writeCardError(c, err)
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Message: "Card frozen",
+		Data:    &models.CardResponse{Card: cd},
+	})
+}
+
+// UnfreezeCardHandler is a function that unfreezes a card
+func (h *CardHandler) UnfreezeCardHandler(c *gin.Context) {
+	if _, ok := h.card(c); !ok {
+		return
+	}
+
+	cd, err := h.useCase.Unfreeze(c.Param("id"))
+	if err != nil {
+		writeCardError(c, err)
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Message: "Card unfrozen",
+		Data:    &models.CardResponse{Card: cd},
+	})
+}
+
+// SetControlsHandler is a function that replaces the spend controls of a card
+func (h *CardHandler) SetControlsHandler(c *gin.Context) {
+	if _, ok := h.card(c); !ok {
+		return
+	}
+
+	var controls card.Controls
+	if !bindJSON(c, &controls) {
+		return
+	}
+
+	cd, err := h.useCase.SetControls(c.Param("id"), controls)
+	if err != nil {
+		writeCardError(c, err)
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Message: "Spend controls updated",
+		Data:    &models.CardResponse{Card: cd},
+	})
+}
+
+// GetAuthorizationsHandler is a function that returns the authorizations of a card
+func (h *CardHandler) GetAuthorizationsHandler(c *gin.Context) {
+	if _, ok := h.card(c); !ok {
+		return
+	}
+
+	authorizations, err := h.useCase.GetAuthorizations(c.Param("id"))
+	if err != nil {
+		writeCardError(c, err)
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Data:    &models.AuthorizationsResponse{Authorizations: authorizations},
+	})
+}
+
+// AuthorizeHandler is a function that charges a card for a merchant and debits its account
+func (h *CardHandler) AuthorizeHandler(c *gin.Context) {
+	var authorizationReq card.AuthorizationRequest
+	if !bindJSON(c, &authorizationReq) {
+		return
+	}
+
+	a, err := h.useCase.Authorize(&authorizationReq)
+	if err != nil && a == nil {
+		writeCardError(c, err)
+		return
+	}
+	if err != nil {
+		// the charge was declined and recorded with its reason
+		c.JSON(http.StatusPaymentRequired, &models.APIResponse{
+			Error: &models.APIError{Message: err.Error(), Code: http.StatusPaymentRequired},
+			Data:  &models.AuthorizationResponse{Authorization: a},
+		})
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Message: "Authorization approved",
+		Data:    &models.AuthorizationResponse{Authorization: a},
+	})
+}
+
+// card returns the card in the path when it is linked to the caller's account, writing an error otherwise
+func (h *CardHandler) card(c *gin.Context) (*card.Card, bool) {
+	cd, err := h.useCase.GetCard(c.Param("id"))
+	if err != nil {
+		writeCardError(c, err)
+		return nil, false
+	}
+
+	return cd, requireAccount(c, cd.AccountID)
+}
+
+// writeCardError maps a card error to its status code
+func writeCardError(c *gin.Context, err error) {
+	code := http.StatusBadRequest
+	switch {
+	case errors.Is(err, card.ErrCardNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, card.ErrFailedToSaveCard), errors.Is(err, card.ErrFailedToSaveSecret):
+		code = http.StatusInternalServerError
+	}
+
+	c.JSON(code, &models.APIResponse{Error: &models.APIError{
+		Message: err.Error(),
+		Code:    code}},
+	)
+}
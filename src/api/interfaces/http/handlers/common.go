@@ -0,0 +1,20 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/quabynah-bilson/quantia/interfaces/http/models"
+	"net/http"
+)
+
+// bindJSON parses the JSON request body into req, writing a 400 Bad Request error when it is invalid
+func bindJSON(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, &models.APIResponse{Error: &models.APIError{
+			Message: err.Error(),
+			Code:    http.StatusBadRequest}},
+		)
+		return false
+	}
+
+	return true
+}
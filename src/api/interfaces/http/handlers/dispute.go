@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/quabynah-bilson/quantia/interfaces/http/models"
+	"github.com/quabynah-bilson/quantia/pkg"
+	"github.com/quabynah-bilson/quantia/pkg/dispute"
+	"github.com/quabynah-bilson/quantia/pkg/payment"
+	"net/http"
+)
+
+// DisputeHandler is a struct that holds the dependencies for the dispute handlers
+type DisputeHandler struct {
+	useCase *pkg.DisputeUseCase
+}
+
+// NewDisputeHandler is a function that creates a new dispute handler
+func NewDisputeHandler(useCase *pkg.DisputeUseCase) *DisputeHandler {
+	return &DisputeHandler{useCase: useCase}
+}
+
+// OpenDisputeHandler is a function that opens a dispute on a payment and holds the disputed amount
+func (h *DisputeHandler) OpenDisputeHandler(c *gin.Context) {
+	// parse the request body into the OpenDisputeRequest struct.
+	// if there is an error, return a 400 Bad Request error
+	var disputeReq models.OpenDisputeRequest
+	if !bindJSON(c, &disputeReq) {
+		return
+	}
+
+	d, err := h.useCase.OpenDispute(disputeReq.TransactionID, disputeReq.Amount, disputeReq.Reason, disputeReq.ProviderReference)
+	if err != nil {
+		writeDisputeError(c, err)
+		return
+	}
+
+	// return a 201 Created response
+	c.JSON(http.StatusCreated, &models.APIResponse{
+		Success: true,
+		Message: "Dispute opened",
+		Data:    &models.DisputeResponse{Dispute: d},
+	})
+}
+
+// GetDisputeHandler is a function that returns a dispute
+func (h *DisputeHandler) GetDisputeHandler(c *gin.Context) {
+	d, err := h.useCase.GetDispute(c.Param("id"))
+	if err != nil {
+		writeDisputeError(c, err)
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Data:    &models.DisputeResponse{Dispute: d},
+	})
+}
+
+// GetTransactionDisputesHandler is a function that returns the disputes of the transaction_id query parameter
+func (h *DisputeHandler) GetTransactionDisputesHandler(c *gin.Context) {
+	disputes, err := h.useCase.GetTransactionDisputes(c.Query("transaction_id"))
+	if err != nil {
+		writeDisputeError(c, err)
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Data:    &models.DisputesResponse{Disputes: disputes},
+	})
+}
+
+// RequireEvidenceHandler is a function that asks the merchant for evidence by a deadline
+func (h *DisputeHandler) RequireEvidenceHandler(c *gin.Context) {
+	var evidenceReq models.RequireEvidenceRequest
+	if c.Request.ContentLength != 0 && !bindJSON(c, &evidenceReq) {
+		return
+	}
+
+	d, err := h.useCase.RequireEvidence(c.Param("id"), evidenceReq.DueBy)
+	if err != nil {
+		writeDisputeError(c, err)
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Message: "Evidence required",
+		Data:    &models.DisputeResponse{Dispute: d},
+	})
+}
+
+// AddEvidenceHandler is a function that records the metadata of a file submitted as evidence
+func (h *DisputeHandler) AddEvidenceHandler(c *gin.Context) {
+	// parse the request body into the Evidence struct.
+	// if there is an error, return a 400 Bad Request error
+	var evidence dispute.Evidence
+	if !bindJSON(c, &evidence) {
+		return
+	}
+
+	d, err := h.useCase.AddEvidence(c.Param("id"), evidence)
+	if err != nil {
+		writeDisputeError(c, err)
+		return
+	}
+
+	// return a 201 Created response
+	c.JSON(http.StatusCreated, &models.APIResponse{
+		Success: true,
+		Message: "Evidence added",
+		Data:    &models.DisputeResponse{Dispute: d},
+	})
+}
+
+// ResolveDisputeHandler is a function that records the decision on a dispute
+func (h *DisputeHandler) ResolveDisputeHandler(c *gin.Context) {
+	// parse the request body into the ResolveDisputeRequest struct.
+	// if there is an error, return a 400 Bad Request error
+	var resolveReq models.ResolveDisputeRequest
+	if !bindJSON(c, &resolveReq) {
+		return
+	}
+
+	d, err := h.useCase.Resolve(c.Param("id"), resolveReq.Outcome, resolveReq.Reason)
+	if err != nil {
+		writeDisputeError(c, err)
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Message: "Dispute " + string(d.Status),
+		Data:    &models.DisputeResponse{Dispute: d},
+	})
+}
+
+// writeDisputeError maps a dispute error to its status code
+func writeDisputeError(c *gin.Context, err error) {
+	code := http.StatusBadRequest
+	switch {
+	case errors.Is(err, dispute.ErrDisputeNotFound), errors.Is(err, payment.ErrTransactionNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, pkg.ErrDisputeExists), errors.Is(err, pkg.ErrInvalidDisputeState), errors.Is(err, pkg.ErrEvidenceDeadlinePassed):
+		code = http.StatusConflict
+	case errors.Is(err, dispute.ErrFailedToSaveDispute):
+		code = http.StatusInternalServerError
+	}
+
+	c.JSON(code, &models.APIResponse{Error: &models.APIError{
+		Message: err.Error(),
+		Code:    code}},
+	)
+}
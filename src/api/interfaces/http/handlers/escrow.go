@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/quabynah-bilson/quantia/interfaces/http/models"
+	"github.com/quabynah-bilson/quantia/pkg"
+	"github.com/quabynah-bilson/quantia/pkg/escrow"
+	"github.com/quabynah-bilson/quantia/pkg/fraud"
+	"github.com/quabynah-bilson/quantia/pkg/ledger"
+	"net/http"
+)
+
+// EscrowHandler is a struct that holds the dependencies for the escrow handlers
+type EscrowHandler struct {
+	useCase *pkg.EscrowUseCase
+}
+
+// NewEscrowHandler is a function that creates a new escrow handler
+func NewEscrowHandler(useCase *pkg.EscrowUseCase) *EscrowHandler {
+	return &EscrowHandler{useCase: useCase}
+}
+
+// CreateEscrowHandler is a function that takes a payment of the authenticated account into escrow
+func (h *EscrowHandler) CreateEscrowHandler(c *gin.Context) {
+	// parse the request body into the CreateEscrowRequest struct.
+	// if there is an error, return a 400 Bad Request error
+	var escrowReq models.CreateEscrowRequest
+	if !bindJSON(c, &escrowReq) {
+		return
+	}
+
+	// call the use case to create the escrow
+	origin := &fraud.Origin{DeviceID: escrowReq.DeviceID, IPAddress: c.ClientIP(), Country: escrowReq.Country}
+	e, err := h.useCase.CreateEscrow(authenticatedAccount(c), escrowReq.Amount, escrowReq.Url, escrowReq.Source, escrowReq.Split, origin)
+	if err != nil && e == nil {
+		writeEscrowError(c, err)
+		return
+	}
+	if err != nil {
+		// the payment was made but declined or blocked, so the escrow failed
+		code := http.StatusPaymentRequired
+		if errors.Is(err, fraud.ErrPaymentBlocked) {
+			code = http.StatusForbidden
+		}
+		c.JSON(code, &models.APIResponse{
+			Error: &models.APIError{Message: err.Error(), Code: code},
+			Data:  &models.EscrowResponse{Escrow: e},
+		})
+		return
+	}
+
+	// the escrow is funded once the payment succeeds, so return a 202 Accepted response
+	c.JSON(http.StatusAccepted, &models.APIResponse{
+		Success: true,
+		Message: "Escrow payment accepted for processing",
+		Data:    &models.EscrowResponse{Escrow: e},
+	})
+}
+
+// GetEscrowHandler is a function that returns an escrow
+func (h *EscrowHandler) GetEscrowHandler(c *gin.Context) {
+	e, ok := h.escrow(c)
+	if !ok {
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Data:    &models.EscrowResponse{Escrow: e},
+	})
+}
+
+// ReleaseEscrowHandler is a function that pays an escrow out to its parties
+func (h *EscrowHandler) ReleaseEscrowHandler(c *gin.Context) {
+	if _, ok := h.escrow(c); !ok {
+		return
+	}
+
+	e, err := h.useCase.Release(c.Param("id"))
+	if err != nil {
+		writeEscrowError(c, err)
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Message: "Escrow released",
+		Data:    &models.EscrowResponse{Escrow: e},
+	})
+}
+
+// DisputeEscrowHandler is a function that holds an escrow while its delivery is contested
+func (h *EscrowHandler) DisputeEscrowHandler(c *gin.Context) {
+	if _, ok := h.escrow(c); !ok {
+		return
+	}
+
+	var reasonReq models.EscrowReasonRequest
+	if c.Request.ContentLength != 0 && !bindJSON(c, &reasonReq) {
+		return
+	}
+
+	e, err := h.useCase.Dispute(c.Param("id"), reasonReq.Reason)
+	if err != nil {
+		writeEscrowError(c, err)
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Message: "Escrow disputed",
+		Data:    &models.EscrowResponse{Escrow: e},
+	})
+}
+
+// RefundEscrowHandler is a function that returns an escrow to the payer
+func (h *EscrowHandler) RefundEscrowHandler(c *gin.Context) {
+	if _, ok := h.escrow(c); !ok {
+		return
+	}
+
+	var reasonReq models.EscrowReasonRequest
+	if c.Request.ContentLength != 0 && !bindJSON(c, &reasonReq) {
+		return
+	}
+
+	e, err := h.useCase.Refund(c.Param("id"), reasonReq.Reason)
+	if err != nil {
+		writeEscrowError(c, err)
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Message: "Escrow refunded",
+		Data:    &models.EscrowResponse{Escrow: e},
+	})
+}
+
+// escrow returns the escrow in the path when the caller is its payer, its merchant or an admin, writing an
+// error otherwise
+func (h *EscrowHandler) escrow(c *gin.Context) (*escrow.Escrow, bool) {
+	e, err := h.useCase.GetEscrow(c.Param("id"))
+	if err != nil {
+		writeEscrowError(c, err)
+		return nil, false
+	}
+
+	return e, requireAccountOrAdmin(c, e.AccountID, ledger.MerchantAccountID(e.Url))
+}
+
+// writeEscrowError maps an escrow error to its status code
+func writeEscrowError(c *gin.Context, err error) {
+	code := http.StatusBadRequest
+	switch {
+	case errors.Is(err, escrow.ErrEscrowNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, pkg.ErrInvalidEscrowState):
+		code = http.StatusConflict
+	default:
+		// invalid splits, and payments and refunds refused by the payment use case
+		writePaymentError(c, nil, err)
+		return
+	}
+
+	c.JSON(code, &models.APIResponse{Error: &models.APIError{
+		Message: err.Error(),
+		Code:    code}},
+	)
+}
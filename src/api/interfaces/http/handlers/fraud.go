@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/quabynah-bilson/quantia/interfaces/http/models"
+	"github.com/quabynah-bilson/quantia/pkg"
+	"github.com/quabynah-bilson/quantia/pkg/fraud"
+	"net/http"
+)
+
+// FraudHandler is a struct that holds the dependencies for the fraud review handlers
+type FraudHandler struct {
+	useCase *pkg.FraudUseCase
+}
+
+// NewFraudHandler is a function that creates a new fraud handler
+func NewFraudHandler(useCase *pkg.FraudUseCase) *FraudHandler {
+	return &FraudHandler{useCase: useCase}
+}
+
+// GetReviewQueueHandler is a function that lists the payments held for review, oldest first
+func (h *FraudHandler) GetReviewQueueHandler(c *gin.Context) {
+	assessments, err := h.useCase.GetReviewQueue()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.APIResponse{Error: &models.APIError{
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError}},
+		)
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Data:    &models.ReviewQueueResponse{Assessments: assessments},
+	})
+}
+
+// GetAssessmentHandler is a function that returns the fraud assessment of a transaction
+func (h *FraudHandler) GetAssessmentHandler(c *gin.Context) {
+	assessment, err := h.useCase.GetAssessment(c.Param("id"))
+	if err != nil {
+		writeFraudError(c, err)
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Data:    &models.AssessmentResponse{Assessment: assessment},
+	})
+}
+
+// ApproveHandler is a function that lets a held payment through to the provider
+func (h *FraudHandler) ApproveHandler(c *gin.Context) {
+	h.review(c, true, "Payment approved")
+}
+
+// RejectHandler is a function that fails a held payment
+func (h *FraudHandler) RejectHandler(c *gin.Context) {
+	h.review(c, false, "Payment rejected")
+}
+
+// review records an analyst's decision on the held payment in the path
+func (h *FraudHandler) review(c *gin.Context, approve bool, message string) {
+	// parse the request body into the ReviewRequest struct.
+	// if there is an error, return a 400 Bad Request error
+	var reviewReq models.ReviewRequest
+	if err := c.ShouldBindJSON(&reviewReq); err != nil {
+		c.JSON(http.StatusBadRequest, &models.APIResponse{Error: &models.APIError{
+			Message: err.Error(),
+			Code:    http.StatusBadRequest}},
+		)
+		return
+	}
+
+	// the decision is recorded even if resuming the payment fails, so it is returned either way
+	assessment, err := h.useCase.Review(c.Param("id"), approve, reviewReq.Reviewer, reviewReq.Note)
+	if err != nil && assessment == nil {
+		writeFraudError(c, err)
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Message: message,
+		Data:    &models.AssessmentResponse{Assessment: assessment},
+	})
+}
+
+// writeFraudError writes the response of a failed fraud review operation
+func writeFraudError(c *gin.Context, err error) {
+	code := http.StatusBadRequest
+	switch {
+	case errors.Is(err, fraud.ErrAssessmentNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, pkg.ErrNotAwaitingReview):
+		code = http.StatusConflict
+	}
+
+	c.JSON(code, &models.APIResponse{Error: &models.APIError{
+		Message: err.Error(),
+		Code:    code}},
+	)
+}
@@ -0,0 +1,257 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/quabynah-bilson/quantia/interfaces/http/models"
+	"github.com/quabynah-bilson/quantia/pkg"
+	"github.com/quabynah-bilson/quantia/pkg/fraud"
+	"github.com/quabynah-bilson/quantia/pkg/invoice"
+	"github.com/quabynah-bilson/quantia/pkg/payment"
+	"net/http"
+	"time"
+)
+
+// InvoiceHandler is a struct that holds the dependencies for the invoice, payment link and checkout handlers
+type InvoiceHandler struct {
+	useCase *pkg.InvoiceUseCase
+}
+
+// NewInvoiceHandler is a function that creates a new invoice handler
+func NewInvoiceHandler(useCase *pkg.InvoiceUseCase) *InvoiceHandler {
+	return &InvoiceHandler{useCase: useCase}
+}
+
+// CreateInvoiceHandler is a function that creates an invoice
+func (h *InvoiceHandler) CreateInvoiceHandler(c *gin.Context) {
+	// parse the request body into the CreateInvoiceRequest struct.
+	// if there is an error, return a 400 Bad Request error
+	var invoiceReq models.CreateInvoiceRequest
+	if !bindJSON(c, &invoiceReq) {
+		return
+	}
+
+	// call the use case to create the invoice
+	inv, err := h.useCase.CreateInvoice(invoiceReq.Url, invoiceReq.Description, invoiceReq.Currency, invoiceReq.LineItems, invoiceReq.DueDate)
+	if err != nil {
+		writeInvoiceError(c, err)
+		return
+	}
+
+	// return a 201 Created response
+	c.JSON(http.StatusCreated, &models.APIResponse{
+		Success: true,
+		Message: "Invoice created",
+		Data:    &models.InvoiceResponse{Invoice: inv},
+	})
+}
+
+// GetInvoiceHandler is a function that returns an invoice
+func (h *InvoiceHandler) GetInvoiceHandler(c *gin.Context) {
+	inv, err := h.useCase.GetInvoice(c.Param("id"))
+	if err != nil {
+		writeInvoiceError(c, err)
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Data:    &models.InvoiceResponse{Invoice: inv, Overdue: inv.IsOverdue(time.Now())},
+	})
+}
+
+// VoidInvoiceHandler is a function that cancels an open invoice
+func (h *InvoiceHandler) VoidInvoiceHandler(c *gin.Context) {
+	inv, err := h.useCase.VoidInvoice(c.Param("id"))
+	if err != nil {
+		writeInvoiceError(c, err)
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Message: "Invoice voided",
+		Data:    &models.InvoiceResponse{Invoice: inv},
+	})
+}
+
+// CreateInvoiceLinkHandler is a function that creates a payment link for an invoice
+func (h *InvoiceHandler) CreateInvoiceLinkHandler(c *gin.Context) {
+	// the body is optional: without an expiry the link is valid until the invoice is paid or voided
+	var linkReq models.CreateInvoiceLinkRequest
+	if c.Request.ContentLength != 0 && !bindJSON(c, &linkReq) {
+		return
+	}
+
+	link, err := h.useCase.CreateInvoiceLink(c.Param("id"), linkReq.ExpiresAt)
+	if err != nil {
+		writeInvoiceError(c, err)
+		return
+	}
+
+	// return a 201 Created response
+	c.JSON(http.StatusCreated, &models.APIResponse{
+		Success: true,
+		Message: "Payment link created",
+		Data:    &models.LinkResponse{Link: link},
+	})
+}
+
+// CreateLinkHandler is a function that creates a payment link for a fixed amount
+func (h *InvoiceHandler) CreateLinkHandler(c *gin.Context) {
+	// parse the request body into the CreateLinkRequest struct.
+	// if there is an error, return a 400 Bad Request error
+	var linkReq models.CreateLinkRequest
+	if !bindJSON(c, &linkReq) {
+		return
+	}
+
+	link, err := h.useCase.CreateLink(linkReq.Url, linkReq.Description, linkReq.Amount, linkReq.Currency, linkReq.MultiUse, linkReq.ExpiresAt)
+	if err != nil {
+		writeInvoiceError(c, err)
+		return
+	}
+
+	// return a 201 Created response
+	c.JSON(http.StatusCreated, &models.APIResponse{
+		Success: true,
+		Message: "Payment link created",
+		Data:    &models.LinkResponse{Link: link},
+	})
+}
+
+// GetLinkHandler is a function that returns a payment link
+func (h *InvoiceHandler) GetLinkHandler(c *gin.Context) {
+	link, err := h.useCase.GetLink(c.Param("id"))
+	if err != nil {
+		writeInvoiceError(c, err)
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Data:    &models.LinkResponse{Link: link},
+	})
+}
+
+// GetLinkPaymentsHandler is a function that lists the payments made through a payment link
+func (h *InvoiceHandler) GetLinkPaymentsHandler(c *gin.Context) {
+	payments, err := h.useCase.GetLinkPayments(c.Param("id"))
+	if err != nil {
+		writeInvoiceError(c, err)
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Data:    &models.LinkPaymentsResponse{Payments: payments},
+	})
+}
+
+// DisableLinkHandler is a function that turns off a payment link
+func (h *InvoiceHandler) DisableLinkHandler(c *gin.Context) {
+	link, err := h.useCase.DisableLink(c.Param("id"))
+	if err != nil {
+		writeInvoiceError(c, err)
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Message: "Payment link disabled",
+		Data:    &models.LinkResponse{Link: link},
+	})
+}
+
+// CheckoutHandler is a function that shows payers what they are about to pay
+func (h *InvoiceHandler) CheckoutHandler(c *gin.Context) {
+	link, err := h.useCase.GetLink(c.Param("id"))
+	if err != nil {
+		writeInvoiceError(c, err)
+		return
+	}
+
+	checkout := &models.CheckoutResponse{
+		LinkID:      link.ID,
+		Description: link.Description,
+		Amount:      link.Amount,
+		Currency:    link.Currency,
+		Status:      link.Status,
+		ExpiresAt:   link.ExpiresAt,
+		InvoiceID:   link.InvoiceID,
+	}
+	if link.InvoiceID != "" {
+		inv, err := h.useCase.GetInvoice(link.InvoiceID)
+		if err != nil {
+			writeInvoiceError(c, err)
+			return
+		}
+		checkout.LineItems, checkout.DueDate = inv.LineItems, &inv.DueDate
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Data:    checkout,
+	})
+}
+
+// PayLinkHandler is a function that pays a payment link
+func (h *InvoiceHandler) PayLinkHandler(c *gin.Context) {
+	// parse the request body into the PayLinkRequest struct.
+	// if there is an error, return a 400 Bad Request error
+	var payReq models.PayLinkRequest
+	if !bindJSON(c, &payReq) {
+		return
+	}
+
+	// call the use case to pay the link
+	origin := &fraud.Origin{DeviceID: payReq.DeviceID, IPAddress: c.ClientIP(), Country: payReq.Country}
+	transaction, err := h.useCase.PayLink(c.Param("id"), payReq.Source, origin)
+	if err != nil && transaction == nil {
+		writeInvoiceError(c, err)
+		return
+	}
+	if err != nil {
+		writePaymentError(c, transaction, err)
+		return
+	}
+
+	// the merchant is notified once the payment succeeds, so return a 202 Accepted response
+	message := "Payment accepted for processing"
+	if transaction.Status == payment.TransactionStatusInReview {
+		message = "Payment held for review"
+	}
+	c.JSON(http.StatusAccepted, &models.APIResponse{
+		Success: true,
+		Message: message,
+		Data:    &models.MakePaymentResponse{Transaction: transaction},
+	})
+}
+
+// writeInvoiceError maps an invoice or payment link error to its status code
+func writeInvoiceError(c *gin.Context, err error) {
+	code := http.StatusBadRequest
+	switch {
+	case errors.Is(err, invoice.ErrInvoiceNotFound), errors.Is(err, invoice.ErrLinkNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, pkg.ErrInvalidInvoiceState), errors.Is(err, invoice.ErrInvoiceNotPayable), errors.Is(err, invoice.ErrLinkNotActive):
+		code = http.StatusConflict
+	case errors.Is(err, invoice.ErrLinkExpired):
+		code = http.StatusGone
+	default:
+		// payments refused before anything was charged, e.g. by the transaction limits
+		writePaymentError(c, nil, err)
+		return
+	}
+
+	c.JSON(code, &models.APIResponse{Error: &models.APIError{
+		Message: err.Error(),
+		Code:    code}},
+	)
+}
@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/quabynah-bilson/quantia/interfaces/http/models"
+	"github.com/quabynah-bilson/quantia/pkg"
+	"github.com/quabynah-bilson/quantia/pkg/ledger"
+	"net/http"
+	"time"
+)
+
+// LedgerHandler is a struct that holds the dependencies for the balance and hold handlers
+type LedgerHandler struct {
+	useCase *pkg.LedgerUseCase
+}
+
+// NewLedgerHandler is a function that creates a new ledger handler
+func NewLedgerHandler(useCase *pkg.LedgerUseCase) *LedgerHandler {
+	return &LedgerHandler{useCase: useCase}
+}
+
+// GetBalanceHandler is a function that returns the current and available balance of an account
+func (h *LedgerHandler) GetBalanceHandler(c *gin.Context) {
+	if !requireAccount(c, c.Param("id")) {
+		return
+	}
+
+	balance, err := h.useCase.GetBalance(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &models.APIResponse{Error: &models.APIError{
+			Message: err.Error(),
+			Code:    http.StatusBadRequest}},
+		)
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Data:    &models.BalanceResponse{Balance: balance},
+	})
+}
+
+// AuthorizeHandler is a function that places a hold on an account
+func (h *LedgerHandler) AuthorizeHandler(c *gin.Context) {
+	// parse the request body into the AuthorizeRequest struct.
+	// if there is an error, return a 400 Bad Request error
+	var authorizeReq models.AuthorizeRequest
+	if err := c.ShouldBindJSON(&authorizeReq); err != nil {
+		c.JSON(http.StatusBadRequest, &models.APIResponse{Error: &models.APIError{
+			Message: err.Error(),
+			Code:    http.StatusBadRequest}},
+		)
+		return
+	}
+
+	// holds may only be placed on the caller's own account
+	if !requireAccount(c, authorizeReq.AccountID) {
+		return
+	}
+
+	// call the use case to place the hold
+	hold, err := h.useCase.Authorize(authorizeReq.AccountID, authorizeReq.CreditAccountID, authorizeReq.Amount, time.Duration(authorizeReq.ExpiresIn)*time.Second)
+	if err != nil {
+		writeHoldError(c, err)
+		return
+	}
+
+	// return a 201 Created response
+	c.JSON(http.StatusCreated, &models.APIResponse{
+		Success: true,
+		Message: "Funds reserved",
+		Data:    &models.HoldResponse{Hold: hold},
+	})
+}
+
+// GetHoldHandler is a function that returns a hold
+func (h *LedgerHandler) GetHoldHandler(c *gin.Context) {
+	hold, err := h.useCase.GetHold(c.Param("id"))
+	if err != nil {
+		writeHoldError(c, err)
+		return
+	}
+
+	// holds may only be read by the accounts on either side of them
+	if !requireAccount(c, hold.AccountID, hold.CreditAccountID) {
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Data:    &models.HoldResponse{Hold: hold},
+	})
+}
+
+// CaptureHandler is a function that settles all or part of a hold
+func (h *LedgerHandler) CaptureHandler(c *gin.Context) {
+	// the body is optional: an empty body captures the full hold
+	var captureReq models.CaptureRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&captureReq); err != nil {
+			c.JSON(http.StatusBadRequest, &models.APIResponse{Error: &models.APIError{
+				Message: err.Error(),
+				Code:    http.StatusBadRequest}},
+			)
+			return
+		}
+	}
+
+	// holds may only be captured by the account they were placed on
+	if !h.requireHoldAccount(c) {
+		return
+	}
+
+	// call the use case to capture the hold
+	hold, err := h.useCase.Capture(c.Param("id"), captureReq.Amount)
+	if err != nil {
+		writeHoldError(c, err)
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Message: "Hold captured",
+		Data:    &models.HoldResponse{Hold: hold},
+	})
+}
+
+// VoidHandler is a function that releases a hold
+func (h *LedgerHandler) VoidHandler(c *gin.Context) {
+	// holds may only be voided by the account they were placed on
+	if !h.requireHoldAccount(c) {
+		return
+	}
+
+	hold, err := h.useCase.Void(c.Param("id"))
+	if err != nil {
+		writeHoldError(c, err)
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Message: "Hold voided",
+		Data:    &models.HoldResponse{Hold: hold},
+	})
+}
+
+// requireHoldAccount reports whether the hold in the path was placed on the caller's account, writing an error
+// when it was not
+func (h *LedgerHandler) requireHoldAccount(c *gin.Context) bool {
+	hold, err := h.useCase.GetHold(c.Param("id"))
+	if err != nil {
+		writeHoldError(c, err)
+		return false
+	}
+
+	return requireAccount(c, hold.AccountID)
+}
+
+// writeHoldError maps a hold error to its status code
+func writeHoldError(c *gin.Context, err error) {
+	code := http.StatusBadRequest
+	switch {
+	case errors.Is(err, ledger.ErrHoldNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, ledger.ErrHoldNotActive):
+		code = http.StatusConflict
+	case errors.Is(err, ledger.ErrInsufficientFunds):
+		code = http.StatusPaymentRequired
+	}
+
+	c.JSON(code, &models.APIResponse{Error: &models.APIError{
+		Message: err.Error(),
+		Code:    code}},
+	)
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/quabynah-bilson/quantia/interfaces/http/models"
+	"github.com/quabynah-bilson/quantia/pkg"
+	"github.com/quabynah-bilson/quantia/pkg/limit"
+	"net/http"
+)
+
+// LimitHandler is a struct that holds the dependencies for the transaction limit handlers
+type LimitHandler struct {
+	useCase *pkg.LimitUseCase
+}
+
+// NewLimitHandler is a function that creates a new limit handler
+func NewLimitHandler(useCase *pkg.LimitUseCase) *LimitHandler {
+	return &LimitHandler{useCase: useCase}
+}
+
+// GetLimitsHandler is a function that returns the limits, usage and reset times of an account
+func (h *LimitHandler) GetLimitsHandler(c *gin.Context) {
+	status, err := h.useCase.GetLimits(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &models.APIResponse{Error: &models.APIError{
+			Message: err.Error(),
+			Code:    http.StatusBadRequest}},
+		)
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Data:    &models.LimitsResponse{Limits: status},
+	})
+}
+
+// SetTierHandler is a function that moves an account to another limit tier
+func (h *LimitHandler) SetTierHandler(c *gin.Context) {
+	// parse the request body into the SetTierRequest struct.
+	// if there is an error, return a 400 Bad Request error
+	var tierReq models.SetTierRequest
+	if err := c.ShouldBindJSON(&tierReq); err != nil {
+		c.JSON(http.StatusBadRequest, &models.APIResponse{Error: &models.APIError{
+			Message: err.Error(),
+			Code:    http.StatusBadRequest}},
+		)
+		return
+	}
+
+	status, err := h.useCase.SetTier(c.Param("id"), tierReq.Tier)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &models.APIResponse{Error: &models.APIError{
+			Message: err.Error(),
+			Code:    http.StatusBadRequest}},
+		)
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Message: "Limit tier updated",
+		Data:    &models.LimitsResponse{Limits: status},
+	})
+}
+
+// writeLimitError writes a 429 Too Many Requests response with the remaining allowance and reset time
+// if err is a limit-exceeded error, and reports whether it did
+func writeLimitError(c *gin.Context, err error) bool {
+	var exceeded *limit.ExceededError
+	if !errors.As(err, &exceeded) {
+		return false
+	}
+
+	c.JSON(http.StatusTooManyRequests, &models.APIResponse{
+		Error: &models.APIError{
+			Message: err.Error(),
+			Code:    http.StatusTooManyRequests},
+		Data: &models.LimitExceededResponse{Limit: exceeded},
+	})
+	return true
+}
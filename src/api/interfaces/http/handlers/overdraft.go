@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/quabynah-bilson/quantia/interfaces/http/models"
+	"github.com/quabynah-bilson/quantia/pkg"
+	"github.com/quabynah-bilson/quantia/pkg/ledger"
+	"github.com/quabynah-bilson/quantia/pkg/overdraft"
+	"github.com/quabynah-bilson/quantia/pkg/product"
+	"net/http"
+)
+
+// OverdraftHandler is a struct that holds the dependencies for the overdraft handlers
+type OverdraftHandler struct {
+	useCase *pkg.OverdraftUseCase
+}
+
+// NewOverdraftHandler is a function that creates a new overdraft handler
+func NewOverdraftHandler(useCase *pkg.OverdraftUseCase) *OverdraftHandler {
+	return &OverdraftHandler{useCase: useCase}
+}
+
+// GetOverdraftHandler is a function that returns the overdraft of an account
+func (h *OverdraftHandler) GetOverdraftHandler(c *gin.Context) {
+	facility, err := h.useCase.GetFacility(c.Param("id"))
+	if err != nil {
+		writeOverdraftError(c, err)
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Data:    &models.OverdraftResponse{Overdraft: facility},
+	})
+}
+
+// SetOverdraftHandler is a function that approves the overdraft limit of an account
+func (h *OverdraftHandler) SetOverdraftHandler(c *gin.Context) {
+	// parse the request body into the OverdraftRequest struct.
+	// if there is an error, return a 400 Bad Request error
+	var overdraftReq models.OverdraftRequest
+	if !bindJSON(c, &overdraftReq) {
+		return
+	}
+
+	facility, err := h.useCase.SetLimit(c.Param("id"), overdraftReq.Limit, overdraftReq.Url)
+	if err != nil {
+		writeOverdraftError(c, err)
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Message: "Overdraft limit updated",
+		Data:    &models.OverdraftResponse{Overdraft: facility},
+	})
+}
+
+// writeOverdraftError maps an overdraft error to its status code
+func writeOverdraftError(c *gin.Context, err error) {
+	code := http.StatusBadRequest
+	switch {
+	case errors.Is(err, overdraft.ErrFacilityNotFound), errors.Is(err, product.ErrAssignmentNotFound), errors.Is(err, product.ErrProductNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, pkg.ErrOverdraftNotAllowed), errors.Is(err, pkg.ErrOverdraftLimitTooHigh):
+		code = http.StatusUnprocessableEntity
+	case errors.Is(err, pkg.ErrOverdraftLimitInUse):
+		code = http.StatusConflict
+	case errors.Is(err, overdraft.ErrFailedToSaveFacility), errors.Is(err, ledger.ErrFailedToSaveOverdraftLimit), errors.Is(err, ledger.ErrFailedToGetBalance):
+		code = http.StatusInternalServerError
+	}
+
+	c.JSON(code, &models.APIResponse{Error: &models.APIError{
+		Message: err.Error(),
+		Code:    code}},
+	)
+}
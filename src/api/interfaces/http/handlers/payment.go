@@ -1,14 +1,14 @@
 package handlers
 
 import (
-	"bytes"
-	"encoding/json"
+	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/quabynah-bilson/quantia/interfaces/http/models"
 	"github.com/quabynah-bilson/quantia/pkg"
+	"github.com/quabynah-bilson/quantia/pkg/fraud"
+	"github.com/quabynah-bilson/quantia/pkg/ledger"
 	"github.com/quabynah-bilson/quantia/pkg/payment"
 	"net/http"
-	"time"
 )
 
 // PaymentHandler is a struct that holds the dependencies for the payment handlers
@@ -35,42 +35,84 @@ func (h *PaymentHandler) PayHandler(c *gin.Context) {
 	}
 
 	// call the use case to make the payment
-	transaction, err := h.useCase.MakePayment(paymentReq.Amount, paymentReq.Url)
+	origin := &fraud.Origin{DeviceID: paymentReq.DeviceID, IPAddress: c.ClientIP(), Country: paymentReq.Country}
+	transaction, err := h.useCase.MakePayment(paymentReq.Amount, paymentReq.Url, paymentReq.Source, origin)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, &models.APIResponse{Error: &models.APIError{
-			Message: err.Error(),
-			Code:    http.StatusBadRequest}},
-		)
+		writePaymentError(c, transaction, err)
 		return
 	}
 
-	// create http client
-	httpClient := &http.Client{}
-
-	// create a new webhook payload
-	webhookPayload := &payment.WebhookPayload{
-		ID:     transaction.ID,
-		Status: transaction.Status,
-		Url:    paymentReq.Url,
-		Amount: transaction.Amount,
-		Data: payment.WebhookPayloadData{
-			TransactionID: transaction.ID,
-			Date:          time.Now().String(),
+	// the webhooks are delivered by the webhook worker, so return a 202 Accepted response
+	message := "Payment accepted for processing"
+	if transaction.Status == payment.TransactionStatusInReview {
+		message = "Payment held for review"
+	}
+	c.JSON(http.StatusAccepted, &models.APIResponse{
+		Success: true,
+		Message: message,
+		Data: &models.MakePaymentResponse{
+			Transaction: transaction,
 		},
+	})
+}
+
+// writePaymentError maps the error of a payment attempt to its status code. Blocked and declined payments
+// return their failed transaction.
+func writePaymentError(c *gin.Context, transaction *payment.Transaction, err error) {
+	if writeLimitError(c, err) {
+		return
+	}
+
+	code := http.StatusBadRequest
+	switch {
+	case errors.Is(err, fraud.ErrPaymentBlocked):
+		code = http.StatusForbidden
+	case errors.Is(err, payment.ErrPaymentDeclined):
+		code = http.StatusPaymentRequired
+	default:
+		transaction = nil
 	}
 
-	// create new request
-	payload, err := marshalWebhookPayload(webhookPayload)
+	response := &models.APIResponse{Error: &models.APIError{
+		Message: err.Error(),
+		Code:    code},
+	}
+	if transaction != nil {
+		response.Data = &models.MakePaymentResponse{Transaction: transaction}
+	}
+	c.JSON(code, response)
+}
+
+// GetPaymentHandler is a function that returns the current state of a payment
+func (h *PaymentHandler) GetPaymentHandler(c *gin.Context) {
+	transaction, err := h.useCase.GetPayment(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, &models.APIResponse{Error: &models.APIError{
+		c.JSON(http.StatusNotFound, &models.APIResponse{Error: &models.APIError{
 			Message: err.Error(),
-			Code:    http.StatusBadRequest}},
+			Code:    http.StatusNotFound}},
 		)
 		return
 	}
 
-	req, err := http.NewRequest(http.MethodPost, paymentReq.Url, bytes.NewBuffer(payload))
-	if err != nil {
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Data: &models.MakePaymentResponse{
+			Transaction: transaction,
+		},
+	})
+}
+
+// RefundHandler is a function that refunds all or part of a payment, on behalf of its merchant or an admin
+func (h *PaymentHandler) RefundHandler(c *gin.Context) {
+	if !h.requireMerchant(c) {
+		return
+	}
+
+	// parse the request body into the RefundPaymentRequest struct.
+	// if there is an error, return a 400 Bad Request error
+	var refundReq models.RefundPaymentRequest
+	if err := c.ShouldBindJSON(&refundReq); err != nil {
 		c.JSON(http.StatusBadRequest, &models.APIResponse{Error: &models.APIError{
 			Message: err.Error(),
 			Code:    http.StatusBadRequest}},
@@ -78,33 +120,72 @@ func (h *PaymentHandler) PayHandler(c *gin.Context) {
 		return
 	}
 
-	// set the content type header
-	req.Header.Set("Content-Type", "application/json")
+	// call the use case to refund the payment
+	refund, err := h.useCase.RefundPayment(c.Param("id"), refundReq.Amount, refundReq.Reason)
+	if err != nil {
+		code := http.StatusBadRequest
+		switch {
+		case errors.Is(err, payment.ErrTransactionNotFound):
+			code = http.StatusNotFound
+		case errors.Is(err, payment.ErrRefundExceedsAmount), errors.Is(err, pkg.ErrPaymentNotRefundable):
+			code = http.StatusConflict
+		case errors.Is(err, payment.ErrRefundDeclined):
+			code = http.StatusPaymentRequired
+		}
+
+		response := &models.APIResponse{Error: &models.APIError{Message: err.Error(), Code: code}}
+		if refund != nil {
+			response.Data = &models.RefundResponse{Refund: refund}
+		}
+		c.JSON(code, response)
+		return
+	}
+
+	// pending refunds complete asynchronously, so return a 202 Accepted response for them
+	code, message := http.StatusCreated, "Refund succeeded"
+	if refund.Status == payment.RefundStatusPending {
+		code, message = http.StatusAccepted, "Refund accepted for processing"
+	}
+	c.JSON(code, &models.APIResponse{
+		Success: true,
+		Message: message,
+		Data:    &models.RefundResponse{Refund: refund},
+	})
+}
 
-	// make the request
-	resp, err := httpClient.Do(req)
+// GetRefundsHandler is a function that lists the refunds of a payment to its merchant or an admin
+func (h *PaymentHandler) GetRefundsHandler(c *gin.Context) {
+	if !h.requireMerchant(c) {
+		return
+	}
+
+	refunds, err := h.useCase.GetRefunds(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, &models.APIResponse{Error: &models.APIError{
+		c.JSON(http.StatusNotFound, &models.APIResponse{Error: &models.APIError{
 			Message: err.Error(),
-			Code:    http.StatusBadRequest}},
+			Code:    http.StatusNotFound}},
 		)
 		return
 	}
-	defer func() {
-		_ = resp.Body.Close()
-	}()
 
-	// return a 201 Created response
-	c.JSON(http.StatusCreated, &models.APIResponse{
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
 		Success: true,
-		Message: "Successfully made payment",
-		Data: &models.MakePaymentResponse{
-			Transaction: transaction,
-		},
+		Data:    &models.RefundsResponse{Refunds: refunds},
 	})
 }
 
-// marshalWebhookPayload is a function that marshals a webhook payload into a JSON
-func marshalWebhookPayload(payload *payment.WebhookPayload) ([]byte, error) {
-	return json.Marshal(payload)
+// requireMerchant reports whether the payment in the path was made to the merchant the caller acts for, or
+// the caller is an admin, writing an error otherwise
+func (h *PaymentHandler) requireMerchant(c *gin.Context) bool {
+	transaction, err := h.useCase.GetPayment(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, &models.APIResponse{Error: &models.APIError{
+			Message: err.Error(),
+			Code:    http.StatusNotFound}},
+		)
+		return false
+	}
+
+	return requireAccountOrAdmin(c, ledger.MerchantAccountID(transaction.Url))
 }
@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/quabynah-bilson/quantia/interfaces/http/models"
+	"github.com/quabynah-bilson/quantia/pkg"
+	"github.com/quabynah-bilson/quantia/pkg/ledger"
+	"github.com/quabynah-bilson/quantia/pkg/payout"
+	"github.com/quabynah-bilson/quantia/pkg/screening"
+	"net/http"
+)
+
+// PayoutHandler is a struct that holds the dependencies for the payout batch handlers
+type PayoutHandler struct {
+	useCase *pkg.PayoutUseCase
+}
+
+// NewPayoutHandler is a function that creates a new payout handler
+func NewPayoutHandler(useCase *pkg.PayoutUseCase) *PayoutHandler {
+	return &PayoutHandler{useCase: useCase}
+}
+
+// CreateBatchHandler is a function that uploads a payout batch as a CSV file or a JSON list. The batch is paid
+// from, and created by, the authenticated account.
+func (h *PayoutHandler) CreateBatchHandler(c *gin.Context) {
+	var batchReq models.CreatePayoutBatchRequest
+	var parseReport []payout.RowError
+	if c.ContentType() == "text/csv" {
+		instructions, report, err := payout.ParseCSV(c.Request.Body)
+		if err != nil {
+			writePayoutError(c, err)
+			return
+		}
+		batchReq = models.CreatePayoutBatchRequest{Items: instructions}
+		parseReport = report
+	} else if !bindJSON(c, &batchReq) {
+		return
+	}
+
+	// rows that could not be read are reported with the problems of the others, and nothing is stored
+	accountID := authenticatedAccount(c)
+	var batch *payout.Batch
+	var report []payout.RowError
+	var err error
+	if len(parseReport) > 0 {
+		report, err = payout.MergeReports(parseReport, h.useCase.ValidateBatch(accountID, batchReq.Items)), pkg.ErrInvalidBatch
+	} else {
+		batch, report, err = h.useCase.CreateBatch(accountID, accountID, batchReq.Items)
+	}
+	if len(report) > 0 {
+		c.JSON(http.StatusUnprocessableEntity, &models.APIResponse{
+			Error: &models.APIError{Message: err.Error(), Code: http.StatusUnprocessableEntity},
+			Data:  &models.PayoutReportResponse{Errors: report},
+		})
+		return
+	}
+	if err != nil {
+		writePayoutError(c, err)
+		return
+	}
+
+	// return a 201 Created response
+	c.JSON(http.StatusCreated, &models.APIResponse{
+		Success: true,
+		Message: "Payout batch awaiting approval",
+		Data:    &models.PayoutBatchResponse{Batch: batch},
+	})
+}
+
+// GetBatchHandler is a function that returns a payout batch
+func (h *PayoutHandler) GetBatchHandler(c *gin.Context) {
+	batch, ok := h.batch(c)
+	if !ok {
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Data:    &models.PayoutBatchResponse{Batch: batch},
+	})
+}
+
+// GetItemsHandler is a function that returns the items of a payout batch and their status
+func (h *PayoutHandler) GetItemsHandler(c *gin.Context) {
+	if _, ok := h.batch(c); !ok {
+		return
+	}
+
+	items, err := h.useCase.GetItems(c.Param("id"))
+	if err != nil {
+		writePayoutError(c, err)
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Data:    &models.PayoutItemsResponse{Items: items},
+	})
+}
+
+// GetResultsHandler is a function that downloads the items of a payout batch and their status as a CSV file
+func (h *PayoutHandler) GetResultsHandler(c *gin.Context) {
+	if _, ok := h.batch(c); !ok {
+		return
+	}
+
+	items, err := h.useCase.GetItems(c.Param("id"))
+	if err != nil {
+		writePayoutError(c, err)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err = payout.WriteResults(&buf, items); err != nil {
+		writePayoutError(c, err)
+		return
+	}
+
+	// return a 200 OK response with the file as an attachment
+	c.Header("Content-Disposition", `attachment; filename="`+c.Param("id")+`-results.csv"`)
+	c.Data(http.StatusOK, "text/csv", buf.Bytes())
+}
+
+// ApproveBatchHandler is a function that approves a payout batch for payment on behalf of the authenticated
+// account, which must not be the one that created it
+func (h *PayoutHandler) ApproveBatchHandler(c *gin.Context) {
+	batch, err := h.useCase.Approve(c.Param("id"), authenticatedAccount(c))
+	if err != nil {
+		writePayoutError(c, err)
+		return
+	}
+
+	// the items are paid by the payout worker, so return a 202 Accepted response
+	c.JSON(http.StatusAccepted, &models.APIResponse{
+		Success: true,
+		Message: "Payout batch approved",
+		Data:    &models.PayoutBatchResponse{Batch: batch},
+	})
+}
+
+// RejectBatchHandler is a function that rejects a payout batch on behalf of the authenticated account
+func (h *PayoutHandler) RejectBatchHandler(c *gin.Context) {
+	// the body is optional: it only gives the reason of the rejection
+	var rejectReq models.RejectPayoutBatchRequest
+	if c.Request.ContentLength != 0 && !bindJSON(c, &rejectReq) {
+		return
+	}
+
+	batch, err := h.useCase.Reject(c.Param("id"), authenticatedAccount(c), rejectReq.Reason)
+	if err != nil {
+		writePayoutError(c, err)
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Message: "Payout batch rejected",
+		Data:    &models.PayoutBatchResponse{Batch: batch},
+	})
+}
+
+// batch returns the payout batch in the path when it is paid from the caller's account or the caller is an
+// admin, writing an error otherwise
+func (h *PayoutHandler) batch(c *gin.Context) (*payout.Batch, bool) {
+	batch, err := h.useCase.GetBatch(c.Param("id"))
+	if err != nil {
+		writePayoutError(c, err)
+		return nil, false
+	}
+
+	return batch, requireAccountOrAdmin(c, batch.AccountID)
+}
+
+// writePayoutError maps a payout error to its status code
+func writePayoutError(c *gin.Context, err error) {
+	code := http.StatusBadRequest
+	switch {
+	case errors.Is(err, payout.ErrBatchNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, pkg.ErrInvalidBatchState):
+		code = http.StatusConflict
+	case errors.Is(err, screening.ErrScreeningPending), errors.Is(err, screening.ErrSanctionsMatch):
+		code = http.StatusForbidden
+	case errors.Is(err, ledger.ErrInsufficientFunds):
+		code = http.StatusPaymentRequired
+	case errors.Is(err, payout.ErrInvalidFile):
+		code = http.StatusUnprocessableEntity
+	}
+
+	c.JSON(code, &models.APIResponse{Error: &models.APIError{
+		Message: err.Error(),
+		Code:    code}},
+	)
+}
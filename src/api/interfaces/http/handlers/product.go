@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/quabynah-bilson/quantia/interfaces/http/models"
+	"github.com/quabynah-bilson/quantia/pkg"
+	"github.com/quabynah-bilson/quantia/pkg/product"
+	"net/http"
+)
+
+// ProductHandler is a struct that holds the dependencies for the account product handlers
+type ProductHandler struct {
+	useCase *pkg.ProductUseCase
+}
+
+// NewProductHandler is a function that creates a new product handler
+func NewProductHandler(useCase *pkg.ProductUseCase) *ProductHandler {
+	return &ProductHandler{useCase: useCase}
+}
+
+// CreateProductHandler is a function that creates an account product
+func (h *ProductHandler) CreateProductHandler(c *gin.Context) {
+	// parse the request body into the ProductRequest struct.
+	// if there is an error, return a 400 Bad Request error
+	var productReq models.ProductRequest
+	if !bindJSON(c, &productReq) {
+		return
+	}
+
+	p, err := h.useCase.CreateProduct(productConfig(productReq))
+	if err != nil {
+		writeProductError(c, err)
+		return
+	}
+
+	// return a 201 Created response
+	c.JSON(http.StatusCreated, &models.APIResponse{
+		Success: true,
+		Message: "Product created",
+		Data:    &models.ProductResponse{Product: p},
+	})
+}
+
+// GetProductsHandler is a function that returns every account product
+func (h *ProductHandler) GetProductsHandler(c *gin.Context) {
+	products, err := h.useCase.GetProducts()
+	if err != nil {
+		writeProductError(c, err)
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Data:    &models.ProductsResponse{Products: products},
+	})
+}
+
+// GetProductHandler is a function that returns an account product
+func (h *ProductHandler) GetProductHandler(c *gin.Context) {
+	p, err := h.useCase.GetProduct(c.Param("id"))
+	if err != nil {
+		writeProductError(c, err)
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Data:    &models.ProductResponse{Product: p},
+	})
+}
+
+// UpdateProductHandler is a function that replaces the configuration of an account product
+func (h *ProductHandler) UpdateProductHandler(c *gin.Context) {
+	// parse the request body into the ProductRequest struct.
+	// if there is an error, return a 400 Bad Request error
+	var productReq models.ProductRequest
+	if !bindJSON(c, &productReq) {
+		return
+	}
+
+	p, err := h.useCase.UpdateProduct(c.Param("id"), productConfig(productReq))
+	if err != nil {
+		writeProductError(c, err)
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Message: "Product updated",
+		Data:    &models.ProductResponse{Product: p},
+	})
+}
+
+// AssignProductHandler is a function that moves an account to a product from today
+func (h *ProductHandler) AssignProductHandler(c *gin.Context) {
+	// parse the request body into the AssignProductRequest struct.
+	// if there is an error, return a 400 Bad Request error
+	var assignReq models.AssignProductRequest
+	if !bindJSON(c, &assignReq) {
+		return
+	}
+
+	assignment, err := h.useCase.AssignProduct(c.Param("id"), assignReq.ProductID)
+	if err != nil {
+		writeProductError(c, err)
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Message: "Product assigned",
+		Data:    &models.AssignmentResponse{Assignment: assignment},
+	})
+}
+
+// GetAccountProductHandler is a function that returns the product an account holds
+func (h *ProductHandler) GetAccountProductHandler(c *gin.Context) {
+	assignment, err := h.useCase.GetAccountProduct(c.Param("id"))
+	if err != nil {
+		writeProductError(c, err)
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Data:    &models.AssignmentResponse{Assignment: assignment},
+	})
+}
+
+// GetAccrualsHandler is a function that returns the interest accrued by an account between the from and to
+// query parameters
+func (h *ProductHandler) GetAccrualsHandler(c *gin.Context) {
+	accruals, err := h.useCase.GetAccruals(c.Param("id"), c.Query("from"), c.Query("to"))
+	if err != nil {
+		writeProductError(c, err)
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Data:    &models.AccrualsResponse{Accruals: accruals},
+	})
+}
+
+// GetBusinessDayHandler is a function that returns the outcome of the end-of-day batch of a business date
+func (h *ProductHandler) GetBusinessDayHandler(c *gin.Context) {
+	day, err := h.useCase.GetBusinessDay(c.Param("date"))
+	if err != nil {
+		writeProductError(c, err)
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Data:    &models.BusinessDayResponse{BusinessDay: day},
+	})
+}
+
+// RunEndOfDayHandler is a function that runs, or finishes, the end-of-day batch of a business date
+func (h *ProductHandler) RunEndOfDayHandler(c *gin.Context) {
+	day, err := h.useCase.RunEndOfDay(c.Param("date"))
+	if err != nil {
+		writeProductError(c, err)
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Message: "Business date " + string(day.Status),
+		Data:    &models.BusinessDayResponse{BusinessDay: day},
+	})
+}
+
+// productConfig returns the product configuration of a request
+func productConfig(req models.ProductRequest) product.Product {
+	return product.Product{
+		Name:          req.Name,
+		Type:          req.Type,
+		InterestTiers: req.InterestTiers,
+		DayCount:      req.DayCount,
+		Overdraft:     req.Overdraft,
+		Fees:          req.Fees,
+	}
+}
+
+// writeProductError maps a product or end-of-day error to its status code
+func writeProductError(c *gin.Context, err error) {
+	code := http.StatusBadRequest
+	switch {
+	case errors.Is(err, product.ErrProductNotFound), errors.Is(err, product.ErrAssignmentNotFound), errors.Is(err, product.ErrBusinessDayNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, pkg.ErrInvalidProduct):
+		code = http.StatusUnprocessableEntity
+	case errors.Is(err, product.ErrFailedToSaveProduct), errors.Is(err, product.ErrFailedToSaveAccrual):
+		code = http.StatusInternalServerError
+	}
+
+	c.JSON(code, &models.APIResponse{Error: &models.APIError{
+		Message: err.Error(),
+		Code:    code}},
+	)
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/quabynah-bilson/quantia/interfaces/http/models"
+	"github.com/quabynah-bilson/quantia/pkg"
+	"github.com/quabynah-bilson/quantia/pkg/iso20022"
+	"github.com/quabynah-bilson/quantia/pkg/reconciliation"
+	"io"
+	"net/http"
+)
+
+// ReconciliationHandler is a struct that holds the dependencies for the reconciliation handlers
+type ReconciliationHandler struct {
+	useCase *pkg.ReconciliationUseCase
+}
+
+// NewReconciliationHandler is a function that creates a new reconciliation handler
+func NewReconciliationHandler(useCase *pkg.ReconciliationUseCase) *ReconciliationHandler {
+	return &ReconciliationHandler{useCase: useCase}
+}
+
+// ImportStatementHandler is a function that imports a statement, named by the source query parameter, and
+// reconciles its entries. Statements are CSV files unless format is camt053 (an ISO 20022 bank statement).
+func (h *ReconciliationHandler) ImportStatementHandler(c *gin.Context) {
+	content, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		writeReconciliationError(c, reconciliation.ErrInvalidStatement)
+		return
+	}
+
+	var statement *reconciliation.Statement
+	switch c.Query("format") {
+	case "", "csv":
+		statement, err = h.useCase.ImportCSV(c.Query("source"), content)
+	case "camt053":
+		statement, err = h.useCase.ImportCamt053(c.Query("source"), content)
+	default:
+		err = pkg.ErrUnsupportedStatementFormat
+	}
+	if err != nil {
+		writeReconciliationError(c, err)
+		return
+	}
+
+	// return a 201 Created response
+	c.JSON(http.StatusCreated, &models.APIResponse{
+		Success: true,
+		Message: "Statement reconciled",
+		Data:    &models.StatementResponse{Statement: statement},
+	})
+}
+
+// GetStatementHandler is a function that returns a statement and its totals
+func (h *ReconciliationHandler) GetStatementHandler(c *gin.Context) {
+	statement, err := h.useCase.GetStatement(c.Param("id"))
+	if err != nil {
+		writeReconciliationError(c, err)
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Data:    &models.StatementResponse{Statement: statement},
+	})
+}
+
+// GetStatementItemsHandler is a function that returns the reconciled entries of a statement, filtered by the
+// status query parameter when it is set
+func (h *ReconciliationHandler) GetStatementItemsHandler(c *gin.Context) {
+	items, err := h.useCase.GetItems(c.Param("id"), reconciliation.ItemStatus(c.Query("status")))
+	if err != nil {
+		writeReconciliationError(c, err)
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Data:    &models.StatementItemsResponse{Items: items},
+	})
+}
+
+// GetSettlementReportsHandler is a function that returns the settlement reports of the date query parameter,
+// for every merchant or the one given, as JSON or as a CSV file when format is csv
+func (h *ReconciliationHandler) GetSettlementReportsHandler(c *gin.Context) {
+	date := c.Query("date")
+	reports, err := h.useCase.SettlementReports(date, c.Query("merchant"))
+	if err != nil {
+		writeReconciliationError(c, err)
+		return
+	}
+
+	if c.Query("format") == "csv" {
+		var buf bytes.Buffer
+		if err = reconciliation.WriteReports(&buf, reports); err != nil {
+			writeReconciliationError(c, err)
+			return
+		}
+
+		// return a 200 OK response with the file as an attachment
+		c.Header("Content-Disposition", `attachment; filename="settlement-`+date+`.csv"`)
+		c.Data(http.StatusOK, "text/csv", buf.Bytes())
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Data:    &models.SettlementReportsResponse{Reports: reports},
+	})
+}
+
+// writeReconciliationError maps a reconciliation error to its status code
+func writeReconciliationError(c *gin.Context, err error) {
+	code := http.StatusBadRequest
+	switch {
+	case errors.Is(err, reconciliation.ErrStatementNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, reconciliation.ErrStatementAlreadyImported):
+		code = http.StatusConflict
+	case errors.Is(err, reconciliation.ErrInvalidStatement), errors.Is(err, iso20022.ErrInvalidDocument):
+		code = http.StatusUnprocessableEntity
+	case errors.Is(err, reconciliation.ErrFailedToSaveStatement):
+		code = http.StatusInternalServerError
+	}
+
+	c.JSON(code, &models.APIResponse{Error: &models.APIError{
+		Message: err.Error(),
+		Code:    code}},
+	)
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/quabynah-bilson/quantia/interfaces/http/models"
+	"github.com/quabynah-bilson/quantia/pkg"
+	"github.com/quabynah-bilson/quantia/pkg/ledger"
+	"github.com/quabynah-bilson/quantia/pkg/schedule"
+	"net/http"
+)
+
+// ScheduleHandler is a struct that holds the dependencies for the scheduled payment handlers
+type ScheduleHandler struct {
+	useCase *pkg.ScheduleUseCase
+}
+
+// NewScheduleHandler is a function that creates a new scheduled payment handler
+func NewScheduleHandler(useCase *pkg.ScheduleUseCase) *ScheduleHandler {
+	return &ScheduleHandler{useCase: useCase}
+}
+
+// CreateScheduleHandler is a function that creates a scheduled payment
+func (h *ScheduleHandler) CreateScheduleHandler(c *gin.Context) {
+	// parse the request body into the CreateScheduleRequest struct.
+	// if there is an error, return a 400 Bad Request error
+	var scheduleReq models.CreateScheduleRequest
+	if err := c.ShouldBindJSON(&scheduleReq); err != nil {
+		c.JSON(http.StatusBadRequest, &models.APIResponse{Error: &models.APIError{
+			Message: err.Error(),
+			Code:    http.StatusBadRequest}},
+		)
+		return
+	}
+
+	// call the use case to create the schedule
+	s, err := h.useCase.CreateSchedule(authenticatedAccount(c), scheduleReq.Amount, scheduleReq.Url, scheduleReq.Source, scheduleReq.Frequency, scheduleReq.StartAt, scheduleReq.EndAt, scheduleReq.Count)
+	if err != nil {
+		writeScheduleError(c, err)
+		return
+	}
+
+	// return a 201 Created response
+	c.JSON(http.StatusCreated, &models.APIResponse{
+		Success: true,
+		Message: "Payment scheduled",
+		Data:    &models.ScheduleResponse{Schedule: s},
+	})
+}
+
+// GetScheduleHandler is a function that returns a scheduled payment
+func (h *ScheduleHandler) GetScheduleHandler(c *gin.Context) {
+	s, ok := h.schedule(c)
+	if !ok {
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Data:    &models.ScheduleResponse{Schedule: s},
+	})
+}
+
+// GetRunsHandler is a function that lists the outcomes of a scheduled payment's occurrences
+func (h *ScheduleHandler) GetRunsHandler(c *gin.Context) {
+	s, ok := h.schedule(c)
+	if !ok {
+		return
+	}
+
+	runs, err := h.useCase.GetRuns(s.ID)
+	if err != nil {
+		writeScheduleError(c, err)
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Data:    &models.ScheduleRunsResponse{Runs: runs},
+	})
+}
+
+// SkipHandler is a function that skips the next occurrence of a scheduled payment
+func (h *ScheduleHandler) SkipHandler(c *gin.Context) {
+	h.change(c, h.useCase.Skip, "Next occurrence skipped")
+}
+
+// PauseHandler is a function that pauses a scheduled payment
+func (h *ScheduleHandler) PauseHandler(c *gin.Context) {
+	h.change(c, h.useCase.Pause, "Scheduled payment paused")
+}
+
+// ResumeHandler is a function that resumes a paused scheduled payment
+func (h *ScheduleHandler) ResumeHandler(c *gin.Context) {
+	h.change(c, h.useCase.Resume, "Scheduled payment resumed")
+}
+
+// CancelHandler is a function that cancels a scheduled payment
+func (h *ScheduleHandler) CancelHandler(c *gin.Context) {
+	h.change(c, h.useCase.Cancel, "Scheduled payment cancelled")
+}
+
+// change applies a state change to the scheduled payment in the path
+func (h *ScheduleHandler) change(c *gin.Context, apply func(id string) (*schedule.ScheduledPayment, error), message string) {
+	current, ok := h.schedule(c)
+	if !ok {
+		return
+	}
+
+	s, err := apply(current.ID)
+	if err != nil {
+		writeScheduleError(c, err)
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Message: message,
+		Data:    &models.ScheduleResponse{Schedule: s},
+	})
+}
+
+// schedule returns the scheduled payment in the path when the caller is its payer, its merchant or an admin,
+// writing an error otherwise
+func (h *ScheduleHandler) schedule(c *gin.Context) (*schedule.ScheduledPayment, bool) {
+	s, err := h.useCase.GetSchedule(c.Param("id"))
+	if err != nil {
+		writeScheduleError(c, err)
+		return nil, false
+	}
+
+	return s, requireAccountOrAdmin(c, s.AccountID, ledger.MerchantAccountID(s.Url))
+}
+
+// writeScheduleError maps a scheduled payment error to its status code
+func writeScheduleError(c *gin.Context, err error) {
+	code := http.StatusBadRequest
+	switch {
+	case errors.Is(err, schedule.ErrScheduleNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, pkg.ErrInvalidScheduleState):
+		code = http.StatusConflict
+	}
+
+	c.JSON(code, &models.APIResponse{Error: &models.APIError{
+		Message: err.Error(),
+		Code:    code}},
+	)
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/quabynah-bilson/quantia/interfaces/http/models"
+	"github.com/quabynah-bilson/quantia/pkg"
+	"github.com/quabynah-bilson/quantia/pkg/screening"
+	"net/http"
+)
+
+// ScreeningHandler is a struct that holds the dependencies for the sanctions screening handlers
+type ScreeningHandler struct {
+	useCase *pkg.ScreeningUseCase
+}
+
+// NewScreeningHandler is a function that creates a new screening handler
+func NewScreeningHandler(useCase *pkg.ScreeningUseCase) *ScreeningHandler {
+	return &ScreeningHandler{useCase: useCase}
+}
+
+// GetPartyScreeningHandler is a function that returns the latest screening of the party in the query
+func (h *ScreeningHandler) GetPartyScreeningHandler(c *gin.Context) {
+	s, err := h.useCase.GetPartyScreening(screening.PartyType(c.Query("party_type")), c.Query("party_id"))
+	if err != nil {
+		writeScreeningError(c, err)
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Data:    &models.ScreeningResponse{Screening: s},
+	})
+}
+
+// GetScreeningHandler is a function that returns a screening and its hits
+func (h *ScreeningHandler) GetScreeningHandler(c *gin.Context) {
+	s, err := h.useCase.GetScreening(c.Param("id"))
+	if err != nil {
+		writeScreeningError(c, err)
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Data:    &models.ScreeningResponse{Screening: s},
+	})
+}
+
+// GetReviewQueueHandler is a function that lists the screenings held for review, oldest first
+func (h *ScreeningHandler) GetReviewQueueHandler(c *gin.Context) {
+	screenings, err := h.useCase.GetReviewQueue()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.APIResponse{Error: &models.APIError{
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError}},
+		)
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Data:    &models.ScreeningsResponse{Screenings: screenings},
+	})
+}
+
+// ConfirmHandler is a function that confirms the party of a screening as a sanctions match
+func (h *ScreeningHandler) ConfirmHandler(c *gin.Context) {
+	h.review(c, true, "Sanctions match confirmed")
+}
+
+// DismissHandler is a function that dismisses the hits of a screening as false positives
+func (h *ScreeningHandler) DismissHandler(c *gin.Context) {
+	h.review(c, false, "Sanctions hits dismissed")
+}
+
+// review records an analyst's decision on the screening in the path
+func (h *ScreeningHandler) review(c *gin.Context, confirm bool, message string) {
+	// parse the request body into the ReviewRequest struct.
+	// if there is an error, return a 400 Bad Request error
+	var reviewReq models.ReviewRequest
+	if err := c.ShouldBindJSON(&reviewReq); err != nil {
+		c.JSON(http.StatusBadRequest, &models.APIResponse{Error: &models.APIError{
+			Message: err.Error(),
+			Code:    http.StatusBadRequest}},
+		)
+		return
+	}
+
+	s, err := h.useCase.Review(c.Param("id"), confirm, reviewReq.Reviewer, reviewReq.Note)
+	if err != nil {
+		writeScreeningError(c, err)
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Message: message,
+		Data:    &models.ScreeningResponse{Screening: s},
+	})
+}
+
+// writeScreeningError writes the response of a failed screening operation
+func writeScreeningError(c *gin.Context, err error) {
+	code := http.StatusBadRequest
+	switch {
+	case errors.Is(err, screening.ErrScreeningNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, pkg.ErrScreeningNotAwaitingReview):
+		code = http.StatusConflict
+	}
+
+	c.JSON(code, &models.APIResponse{Error: &models.APIError{
+		Message: err.Error(),
+		Code:    code}},
+	)
+}
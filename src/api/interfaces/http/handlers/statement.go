@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/quabynah-bilson/quantia/interfaces/http/models"
+	"github.com/quabynah-bilson/quantia/pkg"
+	"github.com/quabynah-bilson/quantia/pkg/statement"
+	"net/http"
+)
+
+// StatementHandler is a struct that holds the dependencies for the account statement handlers
+type StatementHandler struct {
+	useCase *pkg.StatementUseCase
+}
+
+// NewStatementHandler is a function that creates a new statement handler
+func NewStatementHandler(useCase *pkg.StatementUseCase) *StatementHandler {
+	return &StatementHandler{useCase: useCase}
+}
+
+// GetStatementHandler is a function that returns the statement of an account between the from and to query
+// parameters, as JSON or as a file when format is csv, ofx or pdf
+func (h *StatementHandler) GetStatementHandler(c *gin.Context) {
+	if !requireAccount(c, c.Param("id")) {
+		return
+	}
+
+	s, err := h.useCase.Generate(c.Param("id"), c.Query("from"), c.Query("to"))
+	if err != nil {
+		writeStatementError(c, err)
+		return
+	}
+
+	h.writeStatement(c, s)
+}
+
+// GetMonthlyStatementsHandler is a function that returns the summaries of the stored monthly statements of an account
+func (h *StatementHandler) GetMonthlyStatementsHandler(c *gin.Context) {
+	if !requireAccount(c, c.Param("id")) {
+		return
+	}
+
+	statements, err := h.useCase.GetMonthlyStatements(c.Param("id"))
+	if err != nil {
+		writeStatementError(c, err)
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Data:    &models.AccountStatementsResponse{Statements: statements},
+	})
+}
+
+// GetMonthlyStatementHandler is a function that returns the stored statement of an account for a month, as
+// JSON or as a file when format is csv, ofx or pdf
+func (h *StatementHandler) GetMonthlyStatementHandler(c *gin.Context) {
+	if !requireAccount(c, c.Param("id")) {
+		return
+	}
+
+	s, err := h.useCase.GetMonthlyStatement(c.Param("id"), c.Param("period"))
+	if err != nil {
+		writeStatementError(c, err)
+		return
+	}
+
+	h.writeStatement(c, s)
+}
+
+// writeStatement responds with a statement as JSON, or as an attachment in the format query parameter
+func (h *StatementHandler) writeStatement(c *gin.Context, s *statement.Statement) {
+	if c.Query("format") == "" || c.Query("format") == "json" {
+		// return a 200 OK response
+		c.JSON(http.StatusOK, &models.APIResponse{
+			Success: true,
+			Data:    &models.AccountStatementResponse{Statement: s},
+		})
+		return
+	}
+
+	format, err := statement.ParseFormat(c.Query("format"))
+	if err != nil {
+		writeStatementError(c, err)
+		return
+	}
+
+	data, err := h.useCase.Export(s, format)
+	if err != nil {
+		writeStatementError(c, err)
+		return
+	}
+
+	// return a 200 OK response with the file as an attachment
+	c.Header("Content-Disposition", `attachment; filename="`+s.FileName(format)+`"`)
+	c.Data(http.StatusOK, format.ContentType(), data)
+}
+
+// writeStatementError maps a statement error to its status code
+func writeStatementError(c *gin.Context, err error) {
+	code := http.StatusBadRequest
+	switch {
+	case errors.Is(err, statement.ErrStatementNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, statement.ErrFailedToSaveStatement):
+		code = http.StatusInternalServerError
+	}
+
+	c.JSON(code, &models.APIResponse{Error: &models.APIError{
+		Message: err.Error(),
+		Code:    code}},
+	)
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/quabynah-bilson/quantia/interfaces/http/models"
+	"github.com/quabynah-bilson/quantia/pkg"
+	"github.com/quabynah-bilson/quantia/pkg/beneficiary"
+	"github.com/quabynah-bilson/quantia/pkg/iso20022"
+	"github.com/quabynah-bilson/quantia/pkg/ledger"
+	"github.com/quabynah-bilson/quantia/pkg/payment"
+	"github.com/quabynah-bilson/quantia/pkg/screening"
+	"github.com/quabynah-bilson/quantia/pkg/transfer"
+	"net/http"
+)
+
+// TransferHandler is a struct that holds the dependencies for the transfer handlers
+type TransferHandler struct {
+	useCase *pkg.TransferUseCase
+}
+
+// NewTransferHandler is a function that creates a new transfer handler
+func NewTransferHandler(useCase *pkg.TransferUseCase) *TransferHandler {
+	return &TransferHandler{useCase: useCase}
+}
+
+// TransferHandler is a function that sends money to a beneficiary
+func (h *TransferHandler) TransferHandler(c *gin.Context) {
+	// parse the request body into the TransferRequest struct.
+	// if there is an error, return a 400 Bad Request error
+	var transferReq models.TransferRequest
+	if err := c.ShouldBindJSON(&transferReq); err != nil {
+		c.JSON(http.StatusBadRequest, &models.APIResponse{Error: &models.APIError{
+			Message: err.Error(),
+			Code:    http.StatusBadRequest}},
+		)
+		return
+	}
+
+	// call the use case to make the transfer from the caller's account
+	t, err := h.useCase.Transfer(authenticatedAccount(c), transferReq.BeneficiaryID, transferReq.Amount, transferReq.Note, transferReq.Url)
+	if err != nil {
+		writeTransferError(c, err)
+		return
+	}
+
+	// pending transfers are accepted; the provider confirms them later
+	code, message := http.StatusCreated, "Transfer completed"
+	if t.Status == transfer.StatusPending {
+		code, message = http.StatusAccepted, "Transfer is being processed"
+	}
+
+	c.JSON(code, &models.APIResponse{
+		Success: true,
+		Message: message,
+		Data:    &models.TransferResponse{Transfer: t},
+	})
+}
+
+// GetTransferHandler is a function that returns a transfer
+func (h *TransferHandler) GetTransferHandler(c *gin.Context) {
+	t, err := h.useCase.GetTransfer(c.Param("id"))
+	if err != nil {
+		writeTransferError(c, err)
+		return
+	}
+
+	// transfers may only be read by the account that made them
+	if !requireAccount(c, t.AccountID) {
+		return
+	}
+
+	// return a 200 OK response
+	c.JSON(http.StatusOK, &models.APIResponse{
+		Success: true,
+		Data:    &models.TransferResponse{Transfer: t},
+	})
+}
+
+// ExportPain001Handler is a function that returns the transfers to bank accounts made on the date query
+// parameter as an ISO 20022 pain.001 file for the partner bank
+func (h *TransferHandler) ExportPain001Handler(c *gin.Context) {
+	date := c.Query("date")
+	data, err := h.useCase.ExportPain001(date)
+	if err != nil {
+		writeTransferError(c, err)
+		return
+	}
+
+	// return a 200 OK response with the file as an attachment
+	c.Header("Content-Disposition", `attachment; filename="pain001-`+date+`.xml"`)
+	c.Data(http.StatusOK, "application/xml", data)
+}
+
+// writeTransferError maps a transfer error to its status code
+func writeTransferError(c *gin.Context, err error) {
+	if writeLimitError(c, err) {
+		return
+	}
+
+	code := http.StatusBadRequest
+	switch {
+	case errors.Is(err, transfer.ErrTransferNotFound), errors.Is(err, beneficiary.ErrBeneficiaryNotFound), errors.Is(err, pkg.ErrNoTransfersToExport):
+		code = http.StatusNotFound
+	case errors.Is(err, pkg.ErrBeneficiaryCoolingOff), errors.Is(err, screening.ErrScreeningPending), errors.Is(err, screening.ErrSanctionsMatch):
+		code = http.StatusForbidden
+	case errors.Is(err, ledger.ErrInsufficientFunds), errors.Is(err, payment.ErrDisbursementDeclined):
+		code = http.StatusPaymentRequired
+	case errors.Is(err, pkg.ErrPayoutsNotSupported), errors.Is(err, pkg.ErrTransferNotificationsNotSupported), errors.Is(err, pkg.ErrBankExportNotConfigured), errors.Is(err, iso20022.ErrInvalidDocument):
+		code = http.StatusUnprocessableEntity
+	}
+
+	c.JSON(code, &models.APIResponse{Error: &models.APIError{
+		Message: err.Error(),
+		Code:    code}},
+	)
+}
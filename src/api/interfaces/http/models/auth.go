@@ -6,6 +6,15 @@ type AuthenticationRequest struct {
 	Password string `json:"password"`
 }
 
+// RegistrationRequest represents the JSON structure expected for registration requests.
+type RegistrationRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+
+	// Name is the user's full name, which is screened against the sanctions lists
+	Name string `json:"name,omitempty"`
+}
+
 // AuthenticationResponse represents the JSON structure returned for authentication requests.
 type AuthenticationResponse struct {
 	ID          int    `json:"account_id,omitempty"`
@@ -0,0 +1,27 @@
+package models
+
+import "github.com/quabynah-bilson/quantia/pkg/beneficiary"
+
+// CreateBeneficiaryRequest represents the JSON structure expected to save a beneficiary of the authenticated
+// account.
+type CreateBeneficiaryRequest struct {
+	Nickname        string                      `json:"nickname"`
+	DestinationType beneficiary.DestinationType `json:"destination_type"`
+
+	// Destination is the account ID, bank account number or wallet number of the payee
+	Destination string `json:"destination"`
+	BankCode    string `json:"bank_code,omitempty"`
+
+	// Name is the payee's name, confirmed against the destination before the beneficiary is saved
+	Name string `json:"name"`
+}
+
+// BeneficiaryResponse represents the JSON structure returned for beneficiary requests.
+type BeneficiaryResponse struct {
+	Beneficiary *beneficiary.Beneficiary `json:"beneficiary"`
+}
+
+// BeneficiariesResponse represents the JSON structure returned when listing the beneficiaries of an account.
+type BeneficiariesResponse struct {
+	Beneficiaries []*beneficiary.Beneficiary `json:"beneficiaries"`
+}
@@ -0,0 +1,8 @@
+package models
+
+import "github.com/quabynah-bilson/quantia/pkg/callback"
+
+// CallbackResponse represents the JSON structure returned for received provider callbacks.
+type CallbackResponse struct {
+	Callback *callback.Callback `json:"callback"`
+}
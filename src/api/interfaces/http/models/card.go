@@ -0,0 +1,37 @@
+package models
+
+import (
+	"github.com/quabynah-bilson/quantia/pkg"
+	"github.com/quabynah-bilson/quantia/pkg/card"
+)
+
+// IssueCardRequest represents the JSON structure expected to issue a virtual card on an account.
+type IssueCardRequest struct {
+	AccountID string        `json:"account_id"`
+	Controls  card.Controls `json:"controls"`
+}
+
+// IssuedCardResponse represents the JSON structure returned when a card is issued, with its PAN and CVV.
+type IssuedCardResponse struct {
+	IssuedCard *pkg.IssuedCard `json:"issued_card"`
+}
+
+// CardResponse represents the JSON structure returned for card requests.
+type CardResponse struct {
+	Card *card.Card `json:"card"`
+}
+
+// CardsResponse represents the JSON structure returned for the cards of an account.
+type CardsResponse struct {
+	Cards []*card.Card `json:"cards"`
+}
+
+// AuthorizationResponse represents the JSON structure returned for an authorization, approved or declined.
+type AuthorizationResponse struct {
+	Authorization *card.Authorization `json:"authorization"`
+}
+
+// AuthorizationsResponse represents the JSON structure returned for the authorizations of a card.
+type AuthorizationsResponse struct {
+	Authorizations []*card.Authorization `json:"authorizations"`
+}
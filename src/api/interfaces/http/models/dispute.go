@@ -0,0 +1,39 @@
+package models
+
+import (
+	"github.com/quabynah-bilson/quantia/pkg/dispute"
+	"time"
+)
+
+// OpenDisputeRequest represents the JSON structure expected to open a dispute on a payment.
+type OpenDisputeRequest struct {
+	TransactionID string  `json:"transaction_id"`
+	Amount        float32 `json:"amount"`
+	Reason        string  `json:"reason"`
+
+	// ProviderReference is the card scheme's or provider's ID for the case
+	ProviderReference string `json:"provider_reference,omitempty"`
+}
+
+// RequireEvidenceRequest represents the JSON structure expected to ask the merchant for evidence. The
+// deadline defaults to the configured evidence window.
+type RequireEvidenceRequest struct {
+	DueBy time.Time `json:"due_by,omitempty"`
+}
+
+// ResolveDisputeRequest represents the JSON structure expected to record the decision on a dispute.
+type ResolveDisputeRequest struct {
+	// Outcome is won or lost
+	Outcome dispute.Status `json:"outcome"`
+	Reason  string         `json:"reason,omitempty"`
+}
+
+// DisputeResponse represents the JSON structure returned for dispute requests.
+type DisputeResponse struct {
+	Dispute *dispute.Dispute `json:"dispute"`
+}
+
+// DisputesResponse represents the JSON structure returned for the disputes of a transaction.
+type DisputesResponse struct {
+	Disputes []*dispute.Dispute `json:"disputes"`
+}
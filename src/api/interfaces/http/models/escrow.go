@@ -0,0 +1,26 @@
+package models
+
+import "github.com/quabynah-bilson/quantia/pkg/escrow"
+
+// CreateEscrowRequest represents the JSON structure expected to take a payment into escrow.
+type CreateEscrowRequest struct {
+	// Url is the platform taking the payment, which receives the fee
+	Url    string       `json:"url"`
+	Amount float32      `json:"amount"`
+	Source string       `json:"source"`
+	Split  escrow.Split `json:"split"`
+
+	// DeviceID and Country (ISO 3166 alpha-2) tell the fraud checks where the payer is paying from
+	DeviceID string `json:"device_id,omitempty"`
+	Country  string `json:"country,omitempty"`
+}
+
+// EscrowReasonRequest represents the JSON structure expected to dispute or refund an escrow.
+type EscrowReasonRequest struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+// EscrowResponse represents the JSON structure returned for escrow requests.
+type EscrowResponse struct {
+	Escrow *escrow.Escrow `json:"escrow"`
+}
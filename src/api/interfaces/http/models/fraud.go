@@ -0,0 +1,19 @@
+package models
+
+import "github.com/quabynah-bilson/quantia/pkg/fraud"
+
+// ReviewRequest represents the JSON structure expected for an analyst's decision on a held payment.
+type ReviewRequest struct {
+	Reviewer string `json:"reviewer"`
+	Note     string `json:"note,omitempty"`
+}
+
+// AssessmentResponse represents the JSON structure returned for fraud assessment requests.
+type AssessmentResponse struct {
+	Assessment *fraud.Assessment `json:"assessment"`
+}
+
+// ReviewQueueResponse represents the JSON structure returned when listing the payments held for review.
+type ReviewQueueResponse struct {
+	Assessments []*fraud.Assessment `json:"assessments"`
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"github.com/quabynah-bilson/quantia/pkg/invoice"
+	"time"
+)
+
+// CreateInvoiceRequest represents the JSON structure expected to create an invoice.
+type CreateInvoiceRequest struct {
+	Url         string             `json:"url"`
+	Description string             `json:"description,omitempty"`
+	Currency    string             `json:"currency"`
+	LineItems   []invoice.LineItem `json:"line_items"`
+	DueDate     time.Time          `json:"due_date"`
+}
+
+// InvoiceResponse represents the JSON structure returned for invoice requests.
+type InvoiceResponse struct {
+	Invoice *invoice.Invoice `json:"invoice"`
+	Overdue bool             `json:"overdue"`
+}
+
+// CreateInvoiceLinkRequest represents the JSON structure expected to create a payment link for an invoice.
+type CreateInvoiceLinkRequest struct {
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// CreateLinkRequest represents the JSON structure expected to create a payment link for a fixed amount.
+type CreateLinkRequest struct {
+	Url         string     `json:"url"`
+	Description string     `json:"description,omitempty"`
+	Amount      float32    `json:"amount"`
+	Currency    string     `json:"currency"`
+	MultiUse    bool       `json:"multi_use,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+}
+
+// LinkResponse represents the JSON structure returned for payment link requests.
+type LinkResponse struct {
+	Link *invoice.Link `json:"link"`
+}
+
+// LinkPaymentsResponse represents the JSON structure returned when listing the payments made through a link.
+type LinkPaymentsResponse struct {
+	Payments []*invoice.Payment `json:"payments"`
+}
+
+// PayLinkRequest represents the JSON structure expected to pay a payment link.
+type PayLinkRequest struct {
+	// Source is the payer's instrument, e.g. a mobile money number
+	Source string `json:"source"`
+
+	// DeviceID and Country (ISO 3166 alpha-2) tell the fraud checks where the payer is paying from
+	DeviceID string `json:"device_id,omitempty"`
+	Country  string `json:"country,omitempty"`
+}
+
+// CheckoutResponse represents the JSON structure shown to payers before they pay a link. It leaves out
+// the merchant's endpoint.
+type CheckoutResponse struct {
+	LinkID      string             `json:"link_id"`
+	Description string             `json:"description,omitempty"`
+	Amount      float32            `json:"amount"`
+	Currency    string             `json:"currency"`
+	Status      invoice.LinkStatus `json:"status"`
+	ExpiresAt   *time.Time         `json:"expires_at,omitempty"`
+
+	// the invoice paid through the link, if any
+	InvoiceID string             `json:"invoice_id,omitempty"`
+	LineItems []invoice.LineItem `json:"line_items,omitempty"`
+	DueDate   *time.Time         `json:"due_date,omitempty"`
+}
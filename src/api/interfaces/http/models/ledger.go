@@ -0,0 +1,29 @@
+package models
+
+import "github.com/quabynah-bilson/quantia/pkg/ledger"
+
+// BalanceResponse represents the JSON structure returned for balance requests.
+type BalanceResponse struct {
+	Balance *ledger.Balance `json:"balance"`
+}
+
+// AuthorizeRequest represents the JSON structure expected for hold requests.
+type AuthorizeRequest struct {
+	AccountID       string  `json:"account_id"`
+	CreditAccountID string  `json:"credit_account_id"`
+	Amount          float32 `json:"amount"`
+
+	// ExpiresIn is the number of seconds the hold lasts, the default hold expiry when omitted
+	ExpiresIn int64 `json:"expires_in,omitempty"`
+}
+
+// CaptureRequest represents the JSON structure expected for capture requests.
+type CaptureRequest struct {
+	// Amount is the amount to capture, the full hold when omitted
+	Amount float32 `json:"amount,omitempty"`
+}
+
+// HoldResponse represents the JSON structure returned for hold requests.
+type HoldResponse struct {
+	Hold *ledger.Hold `json:"hold"`
+}
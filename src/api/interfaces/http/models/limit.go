@@ -0,0 +1,21 @@
+package models
+
+import (
+	"github.com/quabynah-bilson/quantia/pkg"
+	"github.com/quabynah-bilson/quantia/pkg/limit"
+)
+
+// SetTierRequest represents the JSON structure expected to move an account to another limit tier.
+type SetTierRequest struct {
+	Tier limit.Tier `json:"tier"`
+}
+
+// LimitsResponse represents the JSON structure returned for limit requests.
+type LimitsResponse struct {
+	Limits *pkg.LimitStatus `json:"limits"`
+}
+
+// LimitExceededResponse represents the JSON structure returned with limit-exceeded errors.
+type LimitExceededResponse struct {
+	Limit *limit.ExceededError `json:"limit"`
+}
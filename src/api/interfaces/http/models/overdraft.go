@@ -0,0 +1,14 @@
+package models
+
+import "github.com/quabynah-bilson/quantia/pkg/overdraft"
+
+// OverdraftRequest represents the JSON structure expected to approve the overdraft limit of an account.
+type OverdraftRequest struct {
+	Limit float32 `json:"limit"`
+	Url   string  `json:"url"`
+}
+
+// OverdraftResponse represents the JSON structure returned for the overdraft of an account.
+type OverdraftResponse struct {
+	Overdraft *overdraft.Facility `json:"overdraft"`
+}
@@ -6,6 +6,13 @@ import "github.com/quabynah-bilson/quantia/pkg/payment"
 type MakePaymentRequest struct {
 	Amount float32 `json:"amount"`
 	Url    string  `json:"url"`
+
+	// Source is the payer's instrument, e.g. a mobile money number
+	Source string `json:"source,omitempty"`
+
+	// DeviceID and Country (ISO 3166 alpha-2) tell the fraud checks where the payer is paying from
+	DeviceID string `json:"device_id,omitempty"`
+	Country  string `json:"country,omitempty"`
 }
 
 // MakePaymentResponse represents the JSON structure returned for payment requests.
@@ -13,6 +20,22 @@ type MakePaymentResponse struct {
 	Transaction *payment.Transaction `json:"transaction"`
 }
 
+// RefundPaymentRequest represents the JSON structure expected for refund requests.
+type RefundPaymentRequest struct {
+	Amount float32 `json:"amount"`
+	Reason string  `json:"reason,omitempty"`
+}
+
+// RefundResponse represents the JSON structure returned for refund requests.
+type RefundResponse struct {
+	Refund *payment.Refund `json:"refund"`
+}
+
+// RefundsResponse represents the JSON structure returned when listing the refunds of a payment.
+type RefundsResponse struct {
+	Refunds []*payment.Refund `json:"refunds"`
+}
+
 // WebhookResponse represents the JSON structure returned for webhook subscription requests.
 type WebhookResponse struct {
 	Success     bool                 `json:"success"`
@@ -0,0 +1,29 @@
+package models
+
+import "github.com/quabynah-bilson/quantia/pkg/payout"
+
+// CreatePayoutBatchRequest represents the JSON structure expected to upload a payout batch paid from the
+// authenticated account.
+type CreatePayoutBatchRequest struct {
+	Items []payout.Instruction `json:"items"`
+}
+
+// RejectPayoutBatchRequest represents the JSON structure expected to reject a payout batch.
+type RejectPayoutBatchRequest struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+// PayoutBatchResponse represents the JSON structure returned for payout batch requests.
+type PayoutBatchResponse struct {
+	Batch *payout.Batch `json:"batch"`
+}
+
+// PayoutReportResponse represents the JSON structure returned when the rows of a payout batch are invalid.
+type PayoutReportResponse struct {
+	Errors []payout.RowError `json:"errors"`
+}
+
+// PayoutItemsResponse represents the JSON structure returned for the items of a payout batch.
+type PayoutItemsResponse struct {
+	Items []*payout.Item `json:"items"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import "github.com/quabynah-bilson/quantia/pkg/product"
+
+// ProductRequest represents the JSON structure expected to create or update an account product.
+type ProductRequest struct {
+	Name          string                 `json:"name"`
+	Type          product.Type           `json:"type"`
+	InterestTiers []product.RateTier     `json:"interest_tiers"`
+	DayCount      product.DayCount       `json:"day_count"`
+	Overdraft     product.OverdraftTerms `json:"overdraft"`
+	Fees          product.FeeSchedule    `json:"fees"`
+}
+
+// ProductResponse represents the JSON structure returned for product requests.
+type ProductResponse struct {
+	Product *product.Product `json:"product"`
+}
+
+// ProductsResponse represents the JSON structure returned for the list of products.
+type ProductsResponse struct {
+	Products []*product.Product `json:"products"`
+}
+
+// AssignProductRequest represents the JSON structure expected to move an account to a product.
+type AssignProductRequest struct {
+	ProductID string `json:"product_id"`
+}
+
+// AssignmentResponse represents the JSON structure returned for the product an account holds.
+type AssignmentResponse struct {
+	Assignment *product.Assignment `json:"assignment"`
+}
+
+// AccrualsResponse represents the JSON structure returned for the interest accrued by an account.
+type AccrualsResponse struct {
+	Accruals []*product.Accrual `json:"accruals"`
+}
+
+// BusinessDayResponse represents the JSON structure returned for the end-of-day batch of a business date.
+type BusinessDayResponse struct {
+	BusinessDay *product.BusinessDay `json:"business_day"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import "github.com/quabynah-bilson/quantia/pkg/reconciliation"
+
+// StatementResponse represents the JSON structure returned for statement requests.
+type StatementResponse struct {
+	Statement *reconciliation.Statement `json:"statement"`
+}
+
+// StatementItemsResponse represents the JSON structure returned for the reconciled entries of a statement.
+type StatementItemsResponse struct {
+	Items []*reconciliation.Item `json:"items"`
+}
+
+// SettlementReportsResponse represents the JSON structure returned for the settlement reports of a business date.
+type SettlementReportsResponse struct {
+	Reports []*reconciliation.SettlementReport `json:"reports"`
+}
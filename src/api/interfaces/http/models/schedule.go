@@ -0,0 +1,29 @@
+package models
+
+import (
+	"github.com/quabynah-bilson/quantia/pkg/schedule"
+	"time"
+)
+
+// CreateScheduleRequest represents the JSON structure expected to create a scheduled payment.
+type CreateScheduleRequest struct {
+	Amount    float32            `json:"amount"`
+	Url       string             `json:"url"`
+	Source    string             `json:"source,omitempty"`
+	Frequency schedule.Frequency `json:"frequency"`
+	StartAt   time.Time          `json:"start_at"`
+
+	// EndAt and Count are optional limits; without either the schedule runs until it is cancelled
+	EndAt *time.Time `json:"end_at,omitempty"`
+	Count int        `json:"count,omitempty"`
+}
+
+// ScheduleResponse represents the JSON structure returned for scheduled payment requests.
+type ScheduleResponse struct {
+	Schedule *schedule.ScheduledPayment `json:"schedule"`
+}
+
+// ScheduleRunsResponse represents the JSON structure returned when listing the runs of a scheduled payment.
+type ScheduleRunsResponse struct {
+	Runs []*schedule.Run `json:"runs"`
+}
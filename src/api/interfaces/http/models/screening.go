@@ -0,0 +1,13 @@
+package models
+
+import "github.com/quabynah-bilson/quantia/pkg/screening"
+
+// ScreeningResponse represents the JSON structure returned for sanctions screening requests.
+type ScreeningResponse struct {
+	Screening *screening.Screening `json:"screening"`
+}
+
+// ScreeningsResponse represents the JSON structure returned when listing the screenings held for review.
+type ScreeningsResponse struct {
+	Screenings []*screening.Screening `json:"screenings"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import "github.com/quabynah-bilson/quantia/pkg/statement"
+
+// AccountStatementResponse represents the JSON structure returned for the statement of an account.
+type AccountStatementResponse struct {
+	Statement *statement.Statement `json:"statement"`
+}
+
+// AccountStatementsResponse represents the JSON structure returned for the stored monthly statements of an account.
+type AccountStatementsResponse struct {
+	Statements []*statement.Statement `json:"statements"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import "github.com/quabynah-bilson/quantia/pkg/transfer"
+
+// TransferRequest represents the JSON structure expected to send money to a beneficiary from the
+// authenticated account.
+type TransferRequest struct {
+	BeneficiaryID string  `json:"beneficiary_id"`
+	Amount        float32 `json:"amount"`
+	Note          string  `json:"note,omitempty"`
+
+	// Url is the endpoint notified when the transfer completes, if set
+	Url string `json:"url,omitempty"`
+}
+
+// TransferResponse represents the JSON structure returned for transfer requests.
+type TransferResponse struct {
+	Transfer *transfer.Transfer `json:"transfer"`
+}
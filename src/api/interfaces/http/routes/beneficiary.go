@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/quabynah-bilson/quantia/interfaces/http/handlers"
+	"github.com/quabynah-bilson/quantia/pkg"
+)
+
+// SetupBeneficiaryRoutes is a function that sets up the beneficiary routes
+func SetupBeneficiaryRoutes(router *gin.RouterGroup, beneficiaryUseCase *pkg.BeneficiaryUseCase) {
+	// create a new beneficiary handler
+	beneficiaryHandler := handlers.NewBeneficiaryHandler(beneficiaryUseCase)
+
+	// set up the routes
+	router.POST("", beneficiaryHandler.CreateBeneficiaryHandler)
+	router.GET("", beneficiaryHandler.GetBeneficiariesHandler)
+	router.GET("/:id", beneficiaryHandler.GetBeneficiaryHandler)
+	router.DELETE("/:id", beneficiaryHandler.DeleteBeneficiaryHandler)
+}
@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/quabynah-bilson/quantia/interfaces/http/handlers"
+	"github.com/quabynah-bilson/quantia/pkg"
+)
+
+// SetupCallbackRoutes is a function that sets up the inbound webhook routes of the payment providers
+func SetupCallbackRoutes(router *gin.RouterGroup, callbackUseCase *pkg.CallbackUseCase) {
+	// create a new inbound webhook handler
+	callbackHandler := handlers.NewCallbackHandler(callbackUseCase)
+
+	// set up the routes
+	router.POST("/:provider", callbackHandler.ReceiveCallbackHandler)
+	router.GET("/callbacks/:id", callbackHandler.GetCallbackHandler)
+}
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/quabynah-bilson/quantia/interfaces/http/handlers"
+	"github.com/quabynah-bilson/quantia/pkg"
+)
+
+// SetupCardRoutes is a function that sets up the virtual card routes
+func SetupCardRoutes(router *gin.RouterGroup, cardUseCase *pkg.CardUseCase) {
+	// create a new card handler
+	cardHandler := handlers.NewCardHandler(cardUseCase)
+
+	// set up the routes
+	router.POST("", cardHandler.IssueCardHandler)
+	router.GET("", cardHandler.GetAccountCardsHandler)
+	router.POST("/authorizations", cardHandler.AuthorizeHandler)
+	router.GET("/:id", cardHandler.GetCardHandler)
+	router.POST("/:id/freeze", cardHandler.FreezeCardHandler)
+	router.POST("/:id/unfreeze", cardHandler.UnfreezeCardHandler)
+	router.PUT("/:id/controls", cardHandler.SetControlsHandler)
+	router.GET("/:id/authorizations", cardHandler.GetAuthorizationsHandler)
+}
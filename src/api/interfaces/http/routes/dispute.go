@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/quabynah-bilson/quantia/interfaces/http/handlers"
+	"github.com/quabynah-bilson/quantia/pkg"
+)
+
+// SetupDisputeRoutes is a function that sets up the dispute routes. Evidence is required and disputes are
+// decided behind the reviewer middleware.
+func SetupDisputeRoutes(router *gin.RouterGroup, disputeUseCase *pkg.DisputeUseCase, reviewer gin.HandlerFunc) {
+	// create a new dispute handler
+	disputeHandler := handlers.NewDisputeHandler(disputeUseCase)
+
+	// set up the routes
+	router.POST("", disputeHandler.OpenDisputeHandler)
+	router.GET("", disputeHandler.GetTransactionDisputesHandler)
+	router.GET("/:id", disputeHandler.GetDisputeHandler)
+	router.POST("/:id/evidence-required", reviewer, disputeHandler.RequireEvidenceHandler)
+	router.POST("/:id/evidence", disputeHandler.AddEvidenceHandler)
+	router.POST("/:id/resolve", reviewer, disputeHandler.ResolveDisputeHandler)
+}
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/quabynah-bilson/quantia/interfaces/http/handlers"
+	"github.com/quabynah-bilson/quantia/pkg"
+)
+
+// SetupEscrowRoutes is a function that sets up the escrow routes
+func SetupEscrowRoutes(router *gin.RouterGroup, escrowUseCase *pkg.EscrowUseCase) {
+	// create a new escrow handler
+	escrowHandler := handlers.NewEscrowHandler(escrowUseCase)
+
+	// set up the routes
+	router.POST("", escrowHandler.CreateEscrowHandler)
+	router.GET("/:id", escrowHandler.GetEscrowHandler)
+	router.POST("/:id/release", escrowHandler.ReleaseEscrowHandler)
+	router.POST("/:id/dispute", escrowHandler.DisputeEscrowHandler)
+	router.POST("/:id/refund", escrowHandler.RefundEscrowHandler)
+}
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/quabynah-bilson/quantia/interfaces/http/handlers"
+	"github.com/quabynah-bilson/quantia/pkg"
+)
+
+// SetupFraudRoutes is a function that sets up the fraud assessment and review queue routes
+func SetupFraudRoutes(router *gin.RouterGroup, fraudUseCase *pkg.FraudUseCase) {
+	// create a new fraud handler
+	fraudHandler := handlers.NewFraudHandler(fraudUseCase)
+
+	// set up the routes
+	router.GET("/assessments/:id", fraudHandler.GetAssessmentHandler)
+	router.GET("/reviews", fraudHandler.GetReviewQueueHandler)
+	router.POST("/reviews/:id/approve", fraudHandler.ApproveHandler)
+	router.POST("/reviews/:id/reject", fraudHandler.RejectHandler)
+}
@@ -0,0 +1,41 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/quabynah-bilson/quantia/interfaces/http/handlers"
+	"github.com/quabynah-bilson/quantia/pkg"
+)
+
+// SetupInvoiceRoutes is a function that sets up the invoice routes
+func SetupInvoiceRoutes(router *gin.RouterGroup, invoiceUseCase *pkg.InvoiceUseCase) {
+	// create a new invoice handler
+	invoiceHandler := handlers.NewInvoiceHandler(invoiceUseCase)
+
+	// set up the routes
+	router.POST("", invoiceHandler.CreateInvoiceHandler)
+	router.GET("/:id", invoiceHandler.GetInvoiceHandler)
+	router.POST("/:id/void", invoiceHandler.VoidInvoiceHandler)
+	router.POST("/:id/links", invoiceHandler.CreateInvoiceLinkHandler)
+}
+
+// SetupPaymentLinkRoutes is a function that sets up the payment link routes used by merchants
+func SetupPaymentLinkRoutes(router *gin.RouterGroup, invoiceUseCase *pkg.InvoiceUseCase) {
+	// create a new invoice handler
+	invoiceHandler := handlers.NewInvoiceHandler(invoiceUseCase)
+
+	// set up the routes
+	router.POST("", invoiceHandler.CreateLinkHandler)
+	router.GET("/:id", invoiceHandler.GetLinkHandler)
+	router.GET("/:id/payments", invoiceHandler.GetLinkPaymentsHandler)
+	router.POST("/:id/disable", invoiceHandler.DisableLinkHandler)
+}
+
+// SetupCheckoutRoutes is a function that sets up the public routes payers use to pay payment links
+func SetupCheckoutRoutes(router *gin.RouterGroup, invoiceUseCase *pkg.InvoiceUseCase) {
+	// create a new invoice handler
+	invoiceHandler := handlers.NewInvoiceHandler(invoiceUseCase)
+
+	// set up the routes
+	router.GET("/:id", invoiceHandler.CheckoutHandler)
+	router.POST("/:id", invoiceHandler.PayLinkHandler)
+}
@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/quabynah-bilson/quantia/interfaces/http/handlers"
+	"github.com/quabynah-bilson/quantia/pkg"
+)
+
+// SetupAccountRoutes is a function that sets up the account routes
+func SetupAccountRoutes(router *gin.RouterGroup, ledgerUseCase *pkg.LedgerUseCase) {
+	// create a new ledger handler
+	ledgerHandler := handlers.NewLedgerHandler(ledgerUseCase)
+
+	// set up the routes
+	router.GET("/:id/balance", ledgerHandler.GetBalanceHandler)
+}
+
+// SetupHoldRoutes is a function that sets up the authorization hold routes
+func SetupHoldRoutes(router *gin.RouterGroup, ledgerUseCase *pkg.LedgerUseCase) {
+	// create a new ledger handler
+	ledgerHandler := handlers.NewLedgerHandler(ledgerUseCase)
+
+	// set up the routes
+	router.POST("", ledgerHandler.AuthorizeHandler)
+	router.GET("/:id", ledgerHandler.GetHoldHandler)
+	router.POST("/:id/capture", ledgerHandler.CaptureHandler)
+	router.POST("/:id/void", ledgerHandler.VoidHandler)
+}
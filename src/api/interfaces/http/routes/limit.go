@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/quabynah-bilson/quantia/interfaces/http/handlers"
+	"github.com/quabynah-bilson/quantia/pkg"
+)
+
+// SetupLimitRoutes is a function that sets up the transaction limit routes of the accounts. Tiers are changed
+// behind the admin middleware.
+func SetupLimitRoutes(router *gin.RouterGroup, limitUseCase *pkg.LimitUseCase, admin gin.HandlerFunc) {
+	// create a new limit handler
+	limitHandler := handlers.NewLimitHandler(limitUseCase)
+
+	// set up the routes
+	router.GET("/:id/limits", limitHandler.GetLimitsHandler)
+	router.PUT("/:id/tier", admin, limitHandler.SetTierHandler)
+}
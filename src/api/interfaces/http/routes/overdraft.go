@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/quabynah-bilson/quantia/interfaces/http/handlers"
+	"github.com/quabynah-bilson/quantia/pkg"
+)
+
+// SetupOverdraftRoutes is a function that sets up the overdraft routes of the accounts. Limits are set behind
+// the admin middleware.
+func SetupOverdraftRoutes(router *gin.RouterGroup, overdraftUseCase *pkg.OverdraftUseCase, admin gin.HandlerFunc) {
+	// create a new overdraft handler
+	overdraftHandler := handlers.NewOverdraftHandler(overdraftUseCase)
+
+	// set up the routes
+	router.GET("/:id/overdraft", overdraftHandler.GetOverdraftHandler)
+	router.PUT("/:id/overdraft", admin, overdraftHandler.SetOverdraftHandler)
+}
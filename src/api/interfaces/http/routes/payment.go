@@ -6,11 +6,15 @@ import (
 	"github.com/quabynah-bilson/quantia/pkg"
 )
 
-// SetupPaymentRoutes is a function that sets up the payment routes
-func SetupPaymentRoutes(router *gin.RouterGroup, paymentUseCase *pkg.PaymentUseCase) {
+// SetupPaymentRoutes is a function that sets up the payment routes. Refunds are made and listed behind the
+// auth middleware.
+func SetupPaymentRoutes(router *gin.RouterGroup, paymentUseCase *pkg.PaymentUseCase, authenticated gin.HandlerFunc) {
 	// create a new payment handler
 	pay := handlers.NewPaymentHandler(paymentUseCase)
 
 	// set up the routes
 	router.POST("/pay", pay.PayHandler)
+	router.GET("/:id", pay.GetPaymentHandler)
+	router.POST("/:id/refunds", authenticated, pay.RefundHandler)
+	router.GET("/:id/refunds", authenticated, pay.GetRefundsHandler)
 }
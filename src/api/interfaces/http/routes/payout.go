@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/quabynah-bilson/quantia/interfaces/http/handlers"
+	"github.com/quabynah-bilson/quantia/pkg"
+)
+
+// SetupPayoutRoutes is a function that sets up the payout batch routes. Batches are approved and rejected
+// behind the admin middleware.
+func SetupPayoutRoutes(router *gin.RouterGroup, payoutUseCase *pkg.PayoutUseCase, admin gin.HandlerFunc) {
+	// create a new payout handler
+	payoutHandler := handlers.NewPayoutHandler(payoutUseCase)
+
+	// set up the routes
+	router.POST("/batches", payoutHandler.CreateBatchHandler)
+	router.GET("/batches/:id", payoutHandler.GetBatchHandler)
+	router.GET("/batches/:id/items", payoutHandler.GetItemsHandler)
+	router.GET("/batches/:id/results", payoutHandler.GetResultsHandler)
+	router.POST("/batches/:id/approve", admin, payoutHandler.ApproveBatchHandler)
+	router.POST("/batches/:id/reject", admin, payoutHandler.RejectBatchHandler)
+}
@@ -0,0 +1,42 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/quabynah-bilson/quantia/interfaces/http/handlers"
+	"github.com/quabynah-bilson/quantia/pkg"
+)
+
+// SetupProductRoutes is a function that sets up the account product routes. Products are changed behind the
+// admin middleware.
+func SetupProductRoutes(router *gin.RouterGroup, productUseCase *pkg.ProductUseCase, admin gin.HandlerFunc) {
+	// create a new product handler
+	productHandler := handlers.NewProductHandler(productUseCase)
+
+	// set up the routes
+	router.POST("", admin, productHandler.CreateProductHandler)
+	router.GET("", productHandler.GetProductsHandler)
+	router.GET("/:id", productHandler.GetProductHandler)
+	router.PUT("/:id", admin, productHandler.UpdateProductHandler)
+}
+
+// SetupAccountProductRoutes is a function that sets up the product routes of the accounts. Products are
+// assigned behind the admin middleware.
+func SetupAccountProductRoutes(router *gin.RouterGroup, productUseCase *pkg.ProductUseCase, admin gin.HandlerFunc) {
+	// create a new product handler
+	productHandler := handlers.NewProductHandler(productUseCase)
+
+	// set up the routes
+	router.GET("/:id/product", productHandler.GetAccountProductHandler)
+	router.PUT("/:id/product", admin, productHandler.AssignProductHandler)
+	router.GET("/:id/accruals", productHandler.GetAccrualsHandler)
+}
+
+// SetupBusinessDayRoutes is a function that sets up the end-of-day batch routes
+func SetupBusinessDayRoutes(router *gin.RouterGroup, productUseCase *pkg.ProductUseCase) {
+	// create a new product handler
+	productHandler := handlers.NewProductHandler(productUseCase)
+
+	// set up the routes
+	router.GET("/:date", productHandler.GetBusinessDayHandler)
+	router.POST("/:date/run", productHandler.RunEndOfDayHandler)
+}
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/quabynah-bilson/quantia/interfaces/http/handlers"
+	"github.com/quabynah-bilson/quantia/pkg"
+)
+
+// SetupReconciliationRoutes is a function that sets up the reconciliation routes
+func SetupReconciliationRoutes(router *gin.RouterGroup, reconciliationUseCase *pkg.ReconciliationUseCase) {
+	// create a new reconciliation handler
+	reconciliationHandler := handlers.NewReconciliationHandler(reconciliationUseCase)
+
+	// set up the routes
+	router.POST("/statements", reconciliationHandler.ImportStatementHandler)
+	router.GET("/statements/:id", reconciliationHandler.GetStatementHandler)
+	router.GET("/statements/:id/items", reconciliationHandler.GetStatementItemsHandler)
+	router.GET("/reports", reconciliationHandler.GetSettlementReportsHandler)
+}
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/quabynah-bilson/quantia/interfaces/http/handlers"
+	"github.com/quabynah-bilson/quantia/pkg"
+)
+
+// SetupScheduleRoutes is a function that sets up the scheduled payment routes
+func SetupScheduleRoutes(router *gin.RouterGroup, scheduleUseCase *pkg.ScheduleUseCase) {
+	// create a new schedule handler
+	scheduleHandler := handlers.NewScheduleHandler(scheduleUseCase)
+
+	// set up the routes
+	router.POST("", scheduleHandler.CreateScheduleHandler)
+	router.GET("/:id", scheduleHandler.GetScheduleHandler)
+	router.GET("/:id/runs", scheduleHandler.GetRunsHandler)
+	router.POST("/:id/skip", scheduleHandler.SkipHandler)
+	router.POST("/:id/pause", scheduleHandler.PauseHandler)
+	router.POST("/:id/resume", scheduleHandler.ResumeHandler)
+	router.POST("/:id/cancel", scheduleHandler.CancelHandler)
+}
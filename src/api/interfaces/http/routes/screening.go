@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/quabynah-bilson/quantia/interfaces/http/handlers"
+	"github.com/quabynah-bilson/quantia/pkg"
+)
+
+// SetupScreeningRoutes is a function that sets up the sanctions screening and review queue routes
+func SetupScreeningRoutes(router *gin.RouterGroup, screeningUseCase *pkg.ScreeningUseCase) {
+	// create a new screening handler
+	screeningHandler := handlers.NewScreeningHandler(screeningUseCase)
+
+	// set up the routes
+	router.GET("", screeningHandler.GetPartyScreeningHandler)
+	router.GET("/reviews", screeningHandler.GetReviewQueueHandler)
+	router.GET("/:id", screeningHandler.GetScreeningHandler)
+	router.POST("/:id/confirm", screeningHandler.ConfirmHandler)
+	router.POST("/:id/dismiss", screeningHandler.DismissHandler)
+}
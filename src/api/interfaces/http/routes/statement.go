@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/quabynah-bilson/quantia/interfaces/http/handlers"
+	"github.com/quabynah-bilson/quantia/pkg"
+)
+
+// SetupStatementRoutes is a function that sets up the statement routes of the accounts
+func SetupStatementRoutes(router *gin.RouterGroup, statementUseCase *pkg.StatementUseCase) {
+	// create a new statement handler
+	statementHandler := handlers.NewStatementHandler(statementUseCase)
+
+	// set up the routes
+	router.GET("/:id/statements", statementHandler.GetStatementHandler)
+	router.GET("/:id/statements/monthly", statementHandler.GetMonthlyStatementsHandler)
+	router.GET("/:id/statements/monthly/:period", statementHandler.GetMonthlyStatementHandler)
+}
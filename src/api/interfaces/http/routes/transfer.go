@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/quabynah-bilson/quantia/interfaces/http/handlers"
+	"github.com/quabynah-bilson/quantia/pkg"
+)
+
+// SetupTransferRoutes is a function that sets up the transfer routes. The bank files are exported behind the
+// admin middleware.
+func SetupTransferRoutes(router *gin.RouterGroup, transferUseCase *pkg.TransferUseCase, admin gin.HandlerFunc) {
+	// create a new transfer handler
+	transferHandler := handlers.NewTransferHandler(transferUseCase)
+
+	// set up the routes
+	router.POST("", transferHandler.TransferHandler)
+	router.GET("/exports/pain001", admin, transferHandler.ExportPain001Handler)
+	router.GET("/:id", transferHandler.GetTransferHandler)
+}
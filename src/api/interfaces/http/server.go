@@ -1,22 +1,26 @@
 package http
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
-	accountAdapter "github.com/quabynah-bilson/quantia/adapters/account/datastore"
-	paymentAdapter "github.com/quabynah-bilson/quantia/adapters/payment/datastore"
 	tokenAdapter "github.com/quabynah-bilson/quantia/adapters/token/datastore"
+	"github.com/quabynah-bilson/quantia/interfaces/bootstrap"
+	"github.com/quabynah-bilson/quantia/interfaces/http/handlers"
 	"github.com/quabynah-bilson/quantia/interfaces/http/routes"
-	"github.com/quabynah-bilson/quantia/internal/account"
-	"github.com/quabynah-bilson/quantia/internal/payment"
 	"github.com/quabynah-bilson/quantia/internal/token"
 	"github.com/quabynah-bilson/quantia/pkg"
+	accountPkg "github.com/quabynah-bilson/quantia/pkg/account"
 	"log"
+	nethttp "net/http"
 	"os"
+	"time"
 )
 
-// StartAuthServer is a function that starts the http server for the auth group using the gin framework
-func StartAuthServer() {
+// StartAuthServer is a function that starts the http server for the auth group using the gin framework.
+// The server is shut down gracefully when the context is cancelled.
+func StartAuthServer(ctx context.Context) {
 
 	// create a gin router
 	router := gin.Default()
@@ -26,31 +30,121 @@ func StartAuthServer() {
 	authRoutes := router.Group("/api/v1/auth")
 
 	// register the auth routes
-	routes.SetupAuthRoutes(authRoutes, setupAuth())
+	accountRepo := bootstrap.NewAccountRepository()
+	screeningUseCase := bootstrap.NewScreeningUseCase()
+	authUseCase := setupAuth(accountRepo, screeningUseCase)
+	routes.SetupAuthRoutes(authRoutes, authUseCase)
+
+	// the protected routes require a valid bearer token for the account that sends them, the review routes an
+	// operator account and the configuration routes an admin account
+	authenticated := handlers.RequireAuth(authUseCase)
+	reviewers := handlers.RequireAuth(authUseCase, pkg.RoleAdmin, pkg.RoleAnalyst)
+	admins := handlers.RequireAuth(authUseCase, pkg.RoleAdmin)
+
+	// the repositories and the provider are shared by the payment, ledger, schedule and transfer use cases
+	ledgerRepo := bootstrap.NewLedgerRepository()
+	paymentRepo := bootstrap.NewPaymentRepository()
+	paymentProvider := bootstrap.NewPaymentProvider()
+	limitUseCase := bootstrap.NewLimitUseCase()
+	fraudUseCase := bootstrap.NewFraudUseCase()
+	paymentUseCase := bootstrap.NewPaymentUseCase(paymentRepo, ledgerRepo, paymentProvider, limitUseCase, fraudUseCase)
 
 	// create a group for the payment routes
 	paymentRoutes := router.Group("/api/v1/payments")
 
 	// register the payment routes
-	routes.SetupPaymentRoutes(paymentRoutes, setupPayment())
+	routes.SetupPaymentRoutes(paymentRoutes, paymentUseCase, authenticated)
+
+	// register the inbound webhook routes, where the provider's signed callbacks complete payments
+	routes.SetupCallbackRoutes(router.Group("/api/v1/webhooks"), bootstrap.NewCallbackUseCase(paymentUseCase, paymentProvider))
+
+	// register the fraud review routes (held payments are resumed by the payment use case)
+	routes.SetupFraudRoutes(router.Group("/api/v1/fraud", reviewers), fraudUseCase)
+
+	// register the scheduled payment routes (the occurrences are run by the background jobs)
+	routes.SetupScheduleRoutes(router.Group("/api/v1/schedules", authenticated), bootstrap.NewScheduleUseCase(paymentRepo, paymentUseCase))
+
+	// register the invoice and payment link routes, and the public routes that pay the links
+	invoiceUseCase := bootstrap.NewInvoiceUseCase(paymentUseCase)
+	routes.SetupInvoiceRoutes(router.Group("/api/v1/invoices"), invoiceUseCase)
+	routes.SetupPaymentLinkRoutes(router.Group("/api/v1/links"), invoiceUseCase)
+	routes.SetupCheckoutRoutes(router.Group("/api/v1/pay"), invoiceUseCase)
+
+	// register the escrow routes (escrows are funded as their payments complete)
+	routes.SetupEscrowRoutes(router.Group("/api/v1/escrows", authenticated), bootstrap.NewEscrowUseCase(ledgerRepo, paymentUseCase))
+
+	// register the dispute routes (disputes past their evidence deadline are decided by the background jobs)
+	routes.SetupDisputeRoutes(router.Group("/api/v1/disputes"), bootstrap.NewDisputeUseCase(ledgerRepo, paymentRepo), reviewers)
+
+	// register the account, statement and hold routes (monthly statements are generated by the background jobs).
+	// Balances and statements are only shown to the account they belong to.
+	ledgerUseCase := pkg.NewLedgerUseCase(ledgerRepo)
+	accountRoutes := router.Group("/api/v1/accounts")
+	protectedAccountRoutes := router.Group("/api/v1/accounts", authenticated)
+	routes.SetupAccountRoutes(protectedAccountRoutes, ledgerUseCase)
+	routes.SetupLimitRoutes(accountRoutes, limitUseCase, admins)
+	routes.SetupStatementRoutes(protectedAccountRoutes, bootstrap.NewStatementUseCase(ledgerRepo))
+	routes.SetupHoldRoutes(router.Group("/api/v1/holds", authenticated), ledgerUseCase)
+
+	// register the product, account product, overdraft and business date routes (business dates are run and
+	// overdrafts are checked by the background jobs)
+	overdraftUseCase := bootstrap.NewOverdraftUseCase(ledgerRepo, paymentRepo)
+	productUseCase := bootstrap.NewProductUseCase(ledgerRepo, overdraftUseCase)
+	routes.SetupProductRoutes(router.Group("/api/v1/products", authenticated), productUseCase, admins)
+	routes.SetupAccountProductRoutes(protectedAccountRoutes, productUseCase, admins)
+	routes.SetupOverdraftRoutes(protectedAccountRoutes, overdraftUseCase, admins)
+	routes.SetupBusinessDayRoutes(router.Group("/api/v1/business-days", admins), productUseCase)
+
+	// register the beneficiary and transfer routes
+	beneficiaryUseCase := bootstrap.NewBeneficiaryUseCase(accountRepo, paymentProvider, screeningUseCase)
+	routes.SetupBeneficiaryRoutes(router.Group("/api/v1/beneficiaries", authenticated), beneficiaryUseCase)
+	routes.SetupTransferRoutes(router.Group("/api/v1/transfers", authenticated), bootstrap.NewTransferUseCase(ledgerRepo, beneficiaryUseCase, limitUseCase, screeningUseCase, paymentProvider, paymentUseCase, overdraftUseCase), admins)
+
+	// register the bulk payout routes (approved batches are paid by the background jobs)
+	routes.SetupPayoutRoutes(router.Group("/api/v1/payouts", authenticated), bootstrap.NewPayoutUseCase(ledgerRepo, screeningUseCase, paymentProvider, paymentUseCase), admins)
+
+	// register the virtual card routes when the card vault is configured
+	if cardUseCase := bootstrap.NewCardUseCase(ledgerRepo); cardUseCase != nil {
+		routes.SetupCardRoutes(router.Group("/api/v1/cards", authenticated), cardUseCase)
+	}
+
+	// register the reconciliation routes, where operators import and review settlement statements
+	routes.SetupReconciliationRoutes(router.Group("/api/v1/reconciliation", reviewers), bootstrap.NewReconciliationUseCase(paymentRepo))
+
+	// register the sanctions screening routes when screening is enabled
+	if screeningUseCase != nil {
+		routes.SetupScreeningRoutes(router.Group("/api/v1/screenings", reviewers), screeningUseCase)
+	}
 
 	// start the server
-	if err := router.Run(fmt.Sprintf(":%s", os.Getenv("HTTP_PORT"))); err != nil {
-		log.Fatalf("failed to start server: %v", err)
+	server := &nethttp.Server{
+		Addr:    fmt.Sprintf(":%s", os.Getenv("HTTP_PORT")),
+		Handler: router,
 	}
-}
 
-// setupAuth is a function that sets up the auth use case
-func setupAuth() *pkg.AuthUseCase {
-	// create a new password helper utility
-	pwHelper := account.NewBcryptPasswordHelper()
+	shutdownDone := make(chan struct{})
+	go func() {
+		defer close(shutdownDone)
+		<-ctx.Done()
 
-	// use the password helper utility to create a new account repository (with a database configuration)
-	accountRepo := account.NewRepository(
-		//accountAdapter.WithMongoAccountDatabase(os.Getenv("MONGO_URI"), pwHelper),
-		accountAdapter.WithPostgresAccountDatabase(os.Getenv("POSTGRES_URI"), pwHelper),
-	)
+		// give in-flight requests 10 seconds to complete
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Printf("failed to shut down server: %v", err)
+		}
+	}()
 
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, nethttp.ErrServerClosed) {
+		log.Fatalf("failed to start server: %v", err)
+	}
+
+	// wait for in-flight requests to complete
+	<-shutdownDone
+}
+
+// setupAuth is a function that sets up the auth use case, screening new users when screening is enabled
+func setupAuth(accountRepo accountPkg.Repository, screeningUseCase *pkg.ScreeningUseCase) *pkg.AuthUseCase {
 	// create a new token repository (with a database configuration)
 	tokenRepo := token.NewRepository(
 		tokenAdapter.WithRedisTokenDatabase(os.Getenv("REDIS_URI")),
@@ -58,19 +152,9 @@ func setupAuth() *pkg.AuthUseCase {
 
 	// create a new auth use case
 	authUseCase := pkg.NewAuthUseCase(accountRepo, tokenRepo)
+	authUseCase.SetScreening(screeningUseCase)
+	authUseCase.SetRoles(bootstrap.NewAccountRoles())
+	authUseCase.SetMerchants(bootstrap.NewMerchantAccounts())
 
 	return authUseCase
 }
-
-// setupPayment is a function that sets up the payment use case
-func setupPayment() *pkg.PaymentUseCase {
-	// create a new payment repository (with a database configuration)
-	paymentRepo := payment.NewRepository(
-		paymentAdapter.WithRedisPaymentDatabase(os.Getenv("REDIS_URI")),
-	)
-
-	// create a new payment use case
-	paymentUseCase := pkg.NewPaymentUseCase(paymentRepo)
-
-	return paymentUseCase
-}
@@ -0,0 +1,94 @@
+package jobs
+
+import (
+	"context"
+	"github.com/quabynah-bilson/quantia/interfaces/bootstrap"
+	"github.com/quabynah-bilson/quantia/internal/ledger"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultHoldExpiryInterval is how often expired holds are released when HOLD_EXPIRY_INTERVAL is not set
+	defaultHoldExpiryInterval = time.Minute
+
+	// defaultSchedulerInterval is how often due scheduled payments are run when SCHEDULER_INTERVAL is not set
+	defaultSchedulerInterval = 10 * time.Second
+
+	// defaultPayoutInterval is how often approved payout batches are paid when PAYOUT_INTERVAL is not set
+	defaultPayoutInterval = 10 * time.Second
+
+	// defaultDisputeInterval is how often disputes past their evidence deadline are decided when DISPUTE_INTERVAL is not set
+	defaultDisputeInterval = time.Minute
+
+	// defaultStatementInterval is how often the monthly statements are looked for when STATEMENT_INTERVAL is not set
+	defaultStatementInterval = time.Hour
+
+	// defaultEndOfDayInterval is how often business dates that ended are looked for when EOD_INTERVAL is not set
+	defaultEndOfDayInterval = 10 * time.Minute
+
+	// defaultOverdraftInterval is how often accounts entering or leaving their overdraft are looked for when OVERDRAFT_INTERVAL is not set
+	defaultOverdraftInterval = time.Minute
+)
+
+// StartJobs starts the background jobs. It blocks until the context is cancelled and every job has stopped.
+func StartJobs(ctx context.Context) {
+	ledgerRepo := bootstrap.NewLedgerRepository()
+	paymentRepo := bootstrap.NewPaymentRepository()
+	paymentProvider := bootstrap.NewPaymentProvider()
+	paymentUseCase := bootstrap.NewPaymentUseCase(paymentRepo, ledgerRepo, paymentProvider, bootstrap.NewLimitUseCase(), bootstrap.NewFraudUseCase())
+	overdraftUseCase := bootstrap.NewOverdraftUseCase(ledgerRepo, paymentRepo)
+
+	var wg sync.WaitGroup
+
+	// release the holds that were not captured in time
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ledger.ExpireHolds(ctx, ledgerRepo, bootstrap.GetEnvDuration("HOLD_EXPIRY_INTERVAL", defaultHoldExpiryInterval))
+	}()
+
+	// run the occurrences of scheduled payments as they fall due
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		bootstrap.NewScheduleUseCase(paymentRepo, paymentUseCase).Run(ctx, bootstrap.GetEnvDuration("SCHEDULER_INTERVAL", defaultSchedulerInterval))
+	}()
+
+	// pay the items of approved payout batches
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		bootstrap.NewPayoutUseCase(ledgerRepo, bootstrap.NewScreeningUseCase(), paymentProvider, paymentUseCase).Run(ctx, bootstrap.GetEnvDuration("PAYOUT_INTERVAL", defaultPayoutInterval))
+	}()
+
+	// lose the disputes whose evidence did not arrive in time
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		bootstrap.NewDisputeUseCase(ledgerRepo, paymentRepo).Run(ctx, bootstrap.GetEnvDuration("DISPUTE_INTERVAL", defaultDisputeInterval))
+	}()
+
+	// store the statements of the month that ended for every account
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		bootstrap.NewStatementUseCase(ledgerRepo).Run(ctx, bootstrap.GetEnvDuration("STATEMENT_INTERVAL", defaultStatementInterval))
+	}()
+
+	// accrue interest and charge fees for every business date that ended
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		bootstrap.NewProductUseCase(ledgerRepo, overdraftUseCase).Run(ctx, bootstrap.GetEnvDuration("EOD_INTERVAL", defaultEndOfDayInterval))
+	}()
+
+	// notify the accounts that entered or left their overdraft
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		overdraftUseCase.Run(ctx, bootstrap.GetEnvDuration("OVERDRAFT_INTERVAL", defaultOverdraftInterval))
+	}()
+
+	wg.Wait()
+}
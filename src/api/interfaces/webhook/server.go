@@ -1,28 +1,130 @@
 package webhook
 
 import (
+	"context"
 	"github.com/quabynah-bilson/quantia/adapters/payment/datastore"
+	"github.com/quabynah-bilson/quantia/internal/netguard"
 	"github.com/quabynah-bilson/quantia/internal/payment"
+	"github.com/quabynah-bilson/quantia/internal/resilience"
 	paymentPkg "github.com/quabynah-bilson/quantia/pkg/payment"
 	"log"
 	"os"
+	"strconv"
+	"time"
 )
 
-// StartWebhookWorker starts the webhook worker (to process webhooks)
-func StartWebhookWorker() {
+const (
+	// defaultWorkerCount is the number of delivery workers used when WEBHOOK_WORKERS is not set
+	defaultWorkerCount = 10
+
+	// defaultQueueSize is the queue capacity used when WEBHOOK_QUEUE_SIZE is not set
+	defaultQueueSize = 100
+
+	// defaultBreakerThreshold is the number of consecutive failures that opens a merchant's circuit
+	defaultBreakerThreshold = 5
+
+	// defaultBreakerCooldown is how long a merchant's circuit stays open before a probe delivery
+	defaultBreakerCooldown = time.Minute
+)
+
+// StartWebhookWorker starts the webhook worker (to process webhooks).
+// It blocks until the context is cancelled and all in-flight deliveries are drained or checkpointed, and exits
+// the process when the subscription to the payment channel fails.
+func StartWebhookWorker(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	// create a new payment repository (with a database configuration)
-	paymentRepo := payment.NewRepository(datastore.WithRedisPaymentDatabase(os.Getenv("REDIS_URI")))
+	paymentRepo := payment.NewRepository(datastore.WithRedisPaymentDatabase(os.Getenv("REDIS_URI"), os.Getenv("WEBHOOK_ADDRESS")))
+
+	// create a pooled http client for webhook deliveries (each attempt times out after 10 seconds)
+	// that refuses to call private or internal addresses
+	webhookClient := payment.NewHTTPWebhookClient(10*time.Second, netguard.NewGuard(netguard.ConfigFromEnv()))
 
-	// queue for webhooks (buffer 100 webhooks (to avoid blocking the main thread))
-	webhookQueue := make(chan *paymentPkg.WebhookPayload, 100)
+	// bounded queue for webhooks (the subscriber blocks when it is full)
+	webhookQueue := make(chan *paymentPkg.WebhookPayload, getEnvInt("WEBHOOK_QUEUE_SIZE", defaultQueueSize))
 
-	// process webhooks
-	go payment.ProcessWebhooks(paymentRepo, webhookQueue)
+	// process webhooks with a fixed pool of workers
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		payment.ProcessWebhooks(ctx, paymentRepo, webhookClient, webhookQueue, workerConfig())
+	}()
 
-	// subscribe to the payment channel
-	if err := paymentRepo.Subscribe(os.Getenv("WEBHOOK_ADDRESS"), webhookQueue); err != nil {
+	// resume the deliveries checkpointed by the previous run
+	restoreWebhooks(ctx, paymentRepo, webhookQueue)
+
+	// subscribe to the payment channel (blocks until the context is cancelled)
+	err := paymentRepo.Subscribe(ctx, os.Getenv("WEBHOOK_ADDRESS"), webhookQueue)
+
+	// stop the workers and wait for them to drain
+	cancel()
+	<-done
+
+	// without a subscription no webhook is delivered, so the process must not keep running
+	if err != nil {
 		log.Fatalf("failed to subscribe to payment channel: %v", err)
 	}
+}
+
+// restoreWebhooks queues the webhooks that were checkpointed during the last shutdown
+func restoreWebhooks(ctx context.Context, repo *payment.Repository, queue chan *paymentPkg.WebhookPayload) {
+	payloads, err := repo.Restore()
+	if err != nil {
+		log.Printf("failed to restore checkpointed webhooks: %v", err)
+	}
+
+	for i, p := range payloads {
+		select {
+		case queue <- p:
+		case <-ctx.Done():
+			// put back whatever could not be queued
+			for _, remaining := range payloads[i:] {
+				_ = repo.Checkpoint(remaining)
+			}
+			return
+		}
+	}
+
+	if len(payloads) > 0 {
+		log.Printf("restored %d checkpointed webhooks", len(payloads))
+	}
+}
+
+// workerConfig builds the webhook worker configuration from the environment
+func workerConfig() payment.WorkerConfig {
+	policy := resilience.DefaultRetryPolicy()
+	policy.MaxAttempts = getEnvInt("WEBHOOK_MAX_ATTEMPTS", policy.MaxAttempts)
+	policy.InitialBackoff = getEnvDuration("WEBHOOK_INITIAL_BACKOFF", policy.InitialBackoff)
+	policy.MaxBackoff = getEnvDuration("WEBHOOK_MAX_BACKOFF", policy.MaxBackoff)
+	policy.MaxRetryAfter = getEnvDuration("WEBHOOK_MAX_RETRY_AFTER", policy.MaxRetryAfter)
+
+	return payment.WorkerConfig{
+		Workers:     getEnvInt("WEBHOOK_WORKERS", defaultWorkerCount),
+		RetryPolicy: policy,
+		Breakers: resilience.NewCircuitBreakers(
+			getEnvInt("WEBHOOK_BREAKER_THRESHOLD", defaultBreakerThreshold),
+			getEnvDuration("WEBHOOK_BREAKER_COOLDOWN", defaultBreakerCooldown),
+		),
+	}
+}
+
+// getEnvInt reads a positive integer from the environment, falling back to the given default
+func getEnvInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value < 1 {
+		return fallback
+	}
+
+	return value
+}
+
+// getEnvDuration reads a positive duration (e.g. "30s") from the environment, falling back to the given default
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return fallback
+	}
 
-	select {}
+	return value
 }
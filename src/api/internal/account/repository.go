@@ -33,3 +33,8 @@ func (r *Repository) Register(username string, password string) (*account.Accoun
 func (r *Repository) Login(username string, password string) (*account.Account, error) {
 	return r.DB.GetAccountByUsernameAndPassword(username, password)
 }
+
+// Find gets an account by ID.
+func (r *Repository) Find(id string) (*account.Account, error) {
+	return r.DB.GetAccount(id)
+}
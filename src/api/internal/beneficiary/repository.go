@@ -0,0 +1,45 @@
+package beneficiary
+
+import (
+	"github.com/quabynah-bilson/quantia/pkg/beneficiary"
+)
+
+// RepositoryConfiguration is a function that configures a repository
+type RepositoryConfiguration func(*Repository) error
+
+// Repository is the beneficiary repository implementation
+type Repository struct {
+	DB beneficiary.Database
+	beneficiary.Repository
+}
+
+// NewRepository creates a new beneficiary repository
+func NewRepository(configs ...RepositoryConfiguration) *Repository {
+	r := &Repository{}
+
+	for _, config := range configs {
+		_ = config(r)
+	}
+
+	return r
+}
+
+// Create saves a new beneficiary.
+func (r *Repository) Create(b *beneficiary.Beneficiary) error {
+	return r.DB.CreateBeneficiary(b)
+}
+
+// Find gets a beneficiary by ID.
+func (r *Repository) Find(id string) (*beneficiary.Beneficiary, error) {
+	return r.DB.GetBeneficiary(id)
+}
+
+// FindByAccount returns the beneficiaries saved by an account, oldest first.
+func (r *Repository) FindByAccount(accountID string) ([]*beneficiary.Beneficiary, error) {
+	return r.DB.GetBeneficiaries(accountID)
+}
+
+// Delete removes a beneficiary.
+func (r *Repository) Delete(id string) error {
+	return r.DB.DeleteBeneficiary(id)
+}
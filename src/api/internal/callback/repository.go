@@ -0,0 +1,43 @@
+package callback
+
+import "github.com/quabynah-bilson/quantia/pkg/callback"
+
+// RepositoryConfiguration is a function that configures a repository
+type RepositoryConfiguration func(*Repository) error
+
+// Repository is the callback repository implementation
+type Repository struct {
+	DB callback.Database
+	callback.Repository
+}
+
+// NewRepository creates a new callback repository
+func NewRepository(configs ...RepositoryConfiguration) *Repository {
+	r := &Repository{}
+
+	for _, config := range configs {
+		_ = config(r)
+	}
+
+	return r
+}
+
+// Save creates or replaces a callback.
+func (r *Repository) Save(callback *callback.Callback) error {
+	return r.DB.SaveCallback(callback)
+}
+
+// Find gets a callback by ID.
+func (r *Repository) Find(id string) (*callback.Callback, error) {
+	return r.DB.GetCallback(id)
+}
+
+// Claim records that an event of the provider is being handled by the callback.
+func (r *Repository) Claim(provider, eventID, callbackID string) error {
+	return r.DB.ClaimEvent(provider, eventID, callbackID)
+}
+
+// Release gives up the claim on an event.
+func (r *Repository) Release(provider, eventID string) error {
+	return r.DB.ReleaseEvent(provider, eventID)
+}
@@ -0,0 +1,68 @@
+package card
+
+import "github.com/quabynah-bilson/quantia/pkg/card"
+
+// RepositoryConfiguration is a function that configures a repository
+type RepositoryConfiguration func(*Repository) error
+
+// Repository is the card repository implementation
+type Repository struct {
+	DB card.Database
+	card.Repository
+}
+
+// NewRepository creates a new card repository
+func NewRepository(configs ...RepositoryConfiguration) *Repository {
+	r := &Repository{}
+
+	for _, config := range configs {
+		_ = config(r)
+	}
+
+	return r
+}
+
+// Save creates or replaces a card.
+func (r *Repository) Save(card *card.Card) error {
+	return r.DB.SaveCard(card)
+}
+
+// Find gets a card by ID.
+func (r *Repository) Find(id string) (*card.Card, error) {
+	return r.DB.GetCard(id)
+}
+
+// FindByToken gets the card of a vault token.
+func (r *Repository) FindByToken(token string) (*card.Card, error) {
+	return r.DB.GetCardByToken(token)
+}
+
+// FindByAccount gets the cards of an account, oldest first.
+func (r *Repository) FindByAccount(accountID string) ([]*card.Card, error) {
+	return r.DB.GetAccountCards(accountID)
+}
+
+// SaveAuthorization creates an authorization.
+func (r *Repository) SaveAuthorization(authorization *card.Authorization) error {
+	return r.DB.SaveAuthorization(authorization)
+}
+
+// Authorizations gets the authorizations of a card, newest first.
+func (r *Repository) Authorizations(cardID string) ([]*card.Authorization, error) {
+	return r.DB.GetCardAuthorizations(cardID)
+}
+
+// SaveSecret stores a vault entry.
+func (r *Repository) SaveSecret(entry *card.VaultEntry) error {
+	return r.DB.SaveVaultEntry(entry)
+}
+
+// FindSecret gets the vault entry of a token.
+func (r *Repository) FindSecret(token string) (*card.VaultEntry, error) {
+	return r.DB.GetVaultEntry(token)
+}
+
+// FindToken gets the token of a PAN fingerprint.
+func (r *Repository) FindToken(fingerprint string) (string, error) {
+	return r.DB.GetVaultToken(fingerprint)
+}
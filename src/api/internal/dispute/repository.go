@@ -0,0 +1,46 @@
+package dispute
+
+import (
+	"github.com/quabynah-bilson/quantia/pkg/dispute"
+	"time"
+)
+
+// RepositoryConfiguration is a function that configures a repository
+type RepositoryConfiguration func(*Repository) error
+
+// Repository is the dispute repository implementation
+type Repository struct {
+	DB dispute.Database
+	dispute.Repository
+}
+
+// NewRepository creates a new dispute repository
+func NewRepository(configs ...RepositoryConfiguration) *Repository {
+	r := &Repository{}
+
+	for _, config := range configs {
+		_ = config(r)
+	}
+
+	return r
+}
+
+// Save creates or replaces a dispute.
+func (r *Repository) Save(dispute *dispute.Dispute) error {
+	return r.DB.SaveDispute(dispute)
+}
+
+// Find gets a dispute by ID.
+func (r *Repository) Find(id string) (*dispute.Dispute, error) {
+	return r.DB.GetDispute(id)
+}
+
+// FindByTransaction gets the disputes of a transaction, oldest first.
+func (r *Repository) FindByTransaction(transactionID string) ([]*dispute.Dispute, error) {
+	return r.DB.GetTransactionDisputes(transactionID)
+}
+
+// Overdue gets the IDs of the disputes whose evidence deadline has passed.
+func (r *Repository) Overdue(now time.Time, limit int) ([]string, error) {
+	return r.DB.GetOverdueDisputes(now, limit)
+}
@@ -0,0 +1,38 @@
+package escrow
+
+import "github.com/quabynah-bilson/quantia/pkg/escrow"
+
+// RepositoryConfiguration is a function that configures a repository
+type RepositoryConfiguration func(*Repository) error
+
+// Repository is the escrow repository implementation
+type Repository struct {
+	DB escrow.Database
+	escrow.Repository
+}
+
+// NewRepository creates a new escrow repository
+func NewRepository(configs ...RepositoryConfiguration) *Repository {
+	r := &Repository{}
+
+	for _, config := range configs {
+		_ = config(r)
+	}
+
+	return r
+}
+
+// Save creates or replaces an escrow.
+func (r *Repository) Save(e *escrow.Escrow) error {
+	return r.DB.SaveEscrow(e)
+}
+
+// Find gets an escrow by ID.
+func (r *Repository) Find(id string) (*escrow.Escrow, error) {
+	return r.DB.GetEscrow(id)
+}
+
+// FindByTransaction gets the escrow funded by a transaction.
+func (r *Repository) FindByTransaction(transactionID string) (*escrow.Escrow, error) {
+	return r.DB.GetTransactionEscrow(transactionID)
+}
@@ -0,0 +1,56 @@
+package fraud
+
+import (
+	"github.com/quabynah-bilson/quantia/pkg/fraud"
+	"time"
+)
+
+// RepositoryConfiguration is a function that configures a repository
+type RepositoryConfiguration func(*Repository) error
+
+// Repository is the fraud repository implementation
+type Repository struct {
+	DB fraud.Database
+	fraud.Repository
+}
+
+// NewRepository creates a new fraud repository
+func NewRepository(configs ...RepositoryConfiguration) *Repository {
+	r := &Repository{}
+
+	for _, config := range configs {
+		_ = config(r)
+	}
+
+	return r
+}
+
+// Profile returns the history of a subject, counting the payments attempted since the given time.
+func (r *Repository) Profile(subject string, since time.Time) (*fraud.Profile, error) {
+	return r.DB.GetProfile(subject, since)
+}
+
+// RecordAttempt records a payment attempted by a subject.
+func (r *Repository) RecordAttempt(subject string, at time.Time) error {
+	return r.DB.RecordAttempt(subject, at)
+}
+
+// Learn adds a trusted payment to the subject's history.
+func (r *Repository) Learn(signals fraud.Signals) error {
+	return r.DB.LearnProfile(signals)
+}
+
+// Save saves an assessment.
+func (r *Repository) Save(assessment *fraud.Assessment) error {
+	return r.DB.SaveAssessment(assessment)
+}
+
+// Find returns the assessment of a transaction.
+func (r *Repository) Find(transactionID string) (*fraud.Assessment, error) {
+	return r.DB.GetAssessment(transactionID)
+}
+
+// ReviewQueue returns the assessments awaiting review, oldest first.
+func (r *Repository) ReviewQueue() ([]*fraud.Assessment, error) {
+	return r.DB.GetReviewQueue()
+}
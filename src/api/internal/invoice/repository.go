@@ -0,0 +1,58 @@
+package invoice
+
+import "github.com/quabynah-bilson/quantia/pkg/invoice"
+
+// RepositoryConfiguration is a function that configures a repository
+type RepositoryConfiguration func(*Repository) error
+
+// Repository is the invoice and payment link repository implementation
+type Repository struct {
+	DB invoice.Database
+	invoice.Repository
+}
+
+// NewRepository creates a new invoice and payment link repository
+func NewRepository(configs ...RepositoryConfiguration) *Repository {
+	r := &Repository{}
+
+	for _, config := range configs {
+		_ = config(r)
+	}
+
+	return r
+}
+
+// Save creates or replaces an invoice.
+func (r *Repository) Save(inv *invoice.Invoice) error {
+	return r.DB.SaveInvoice(inv)
+}
+
+// Find gets an invoice by ID.
+func (r *Repository) Find(id string) (*invoice.Invoice, error) {
+	return r.DB.GetInvoice(id)
+}
+
+// SaveLink creates or replaces a payment link.
+func (r *Repository) SaveLink(link *invoice.Link) error {
+	return r.DB.SaveLink(link)
+}
+
+// FindLink gets a payment link by ID.
+func (r *Repository) FindLink(id string) (*invoice.Link, error) {
+	return r.DB.GetLink(id)
+}
+
+// Record creates or replaces the record of a payment made through a link.
+func (r *Repository) Record(payment *invoice.Payment) error {
+	return r.DB.SavePayment(payment)
+}
+
+// FindPayment gets the record of a payment by its transaction ID.
+func (r *Repository) FindPayment(transactionID string) (*invoice.Payment, error) {
+	return r.DB.GetPayment(transactionID)
+}
+
+// Payments returns the payments made through a link, oldest first.
+func (r *Repository) Payments(linkID string) ([]*invoice.Payment, error) {
+	return r.DB.GetLinkPayments(linkID)
+}
@@ -0,0 +1,54 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+	"github.com/quabynah-bilson/quantia/pkg/ledger"
+	"log"
+	"time"
+)
+
+// ExpireHolds releases the holds that were not captured in time, checking every interval until the
+// context is cancelled. Expired holds already stop counting against the available balance; releasing
+// them records the final status so they can no longer be captured.
+func ExpireHolds(ctx context.Context, repo ledger.Repository, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		ExpireHoldsOnce(repo, time.Now().UTC())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ExpireHoldsOnce releases the holds that expired at or before now and returns how many were released.
+func ExpireHoldsOnce(repo ledger.Repository, now time.Time) int {
+	holds, err := repo.ExpiredHolds(now)
+	if err != nil {
+		log.Printf("error getting expired holds: %v", err)
+		return 0
+	}
+
+	released := 0
+	for _, hold := range holds {
+		// a hold captured or voided since it was listed is left alone
+		if _, err := repo.Release(hold.ID, ledger.HoldStatusExpired); err != nil {
+			if !errors.Is(err, ledger.ErrHoldNotActive) {
+				log.Printf("error expiring hold %s: %v", hold.ID, err)
+			}
+			continue
+		}
+		released++
+	}
+
+	if released > 0 {
+		log.Printf("expired %d holds", released)
+	}
+
+	return released
+}
@@ -0,0 +1,151 @@
+package ledger
+
+import (
+	"errors"
+	"github.com/quabynah-bilson/quantia/pkg/ledger"
+	"math"
+	"time"
+)
+
+// RepositoryConfiguration is a function that configures a repository
+type RepositoryConfiguration func(*Repository) error
+
+// Repository is the ledger repository implementation
+type Repository struct {
+	DB ledger.Database
+	ledger.Repository
+}
+
+// NewRepository creates a new ledger repository
+func NewRepository(configs ...RepositoryConfiguration) *Repository {
+	r := &Repository{}
+
+	for _, config := range configs {
+		_ = config(r)
+	}
+
+	return r
+}
+
+// Post records a balanced set of entries.
+func (r *Repository) Post(entries ...*ledger.Entry) error {
+	if err := ValidateEntries(entries); err != nil {
+		return err
+	}
+
+	return r.DB.PostEntries(entries)
+}
+
+// Balance returns the balance of an account.
+func (r *Repository) Balance(accountID string) (*ledger.Balance, error) {
+	return r.DB.GetBalance(accountID)
+}
+
+// SetOverdraftLimit sets how far below zero the available balance of an account may go.
+func (r *Repository) SetOverdraftLimit(accountID string, limit float32) error {
+	if accountID == "" {
+		return ledger.ErrInvalidEntry
+	}
+	if limit < 0 {
+		return ledger.ErrInvalidOverdraftLimit
+	}
+
+	return r.DB.SetOverdraftLimit(accountID, limit)
+}
+
+// Entries returns the entries of an account, oldest first.
+func (r *Repository) Entries(accountID string) ([]*ledger.Entry, error) {
+	return r.DB.GetEntries(accountID)
+}
+
+// AccountIDs returns the IDs of the accounts that have entries, in order.
+func (r *Repository) AccountIDs() ([]string, error) {
+	return r.DB.GetAccountIDs()
+}
+
+// Hold reserves funds on an account, reducing its available balance.
+func (r *Repository) Hold(hold *ledger.Hold) error {
+	if hold.AccountID == "" || hold.CreditAccountID == "" || hold.Amount <= 0 {
+		return ledger.ErrInvalidEntry
+	}
+
+	return r.DB.PlaceHold(hold)
+}
+
+// FindHold gets a hold by ID.
+func (r *Repository) FindHold(id string) (*ledger.Hold, error) {
+	return r.DB.GetHold(id)
+}
+
+// Capture settles all or part of an active hold into its credit account. The rest of the hold is released.
+func (r *Repository) Capture(id string, amount float32) (*ledger.Hold, error) {
+	hold, err := r.DB.GetHold(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if hold.Status != ledger.HoldStatusActive || hold.IsExpired(time.Now()) {
+		return nil, ledger.ErrHoldNotActive
+	}
+
+	if amount <= 0 || amount > hold.Amount {
+		return nil, ledger.ErrInvalidCaptureAmount
+	}
+
+	captured, err := r.DB.CaptureHold(id, amount, ledger.NewTransfer(hold.ID, "capture", hold.AccountID, hold.CreditAccountID, amount))
+	if errors.Is(err, ledger.ErrHoldNotFound) {
+		// the hold was released while it was being captured
+		return nil, ledger.ErrHoldNotActive
+	}
+
+	return captured, err
+}
+
+// Release releases an active hold without settling it.
+func (r *Repository) Release(id string, status ledger.HoldStatus) (*ledger.Hold, error) {
+	if _, err := r.DB.GetHold(id); err != nil {
+		return nil, err
+	}
+
+	released, err := r.DB.ReleaseHold(id, status)
+	if errors.Is(err, ledger.ErrHoldNotFound) {
+		return nil, ledger.ErrHoldNotActive
+	}
+
+	return released, err
+}
+
+// ExpiredHolds returns the active holds that expired at or before the given time.
+func (r *Repository) ExpiredHolds(now time.Time) ([]*ledger.Hold, error) {
+	return r.DB.GetExpiredHolds(now)
+}
+
+// ValidateEntries checks that a posting is complete, shares one reference and balances
+func ValidateEntries(entries []*ledger.Entry) error {
+	if len(entries) < 2 {
+		return ledger.ErrUnbalancedEntries
+	}
+
+	var debits, credits float64
+	for _, entry := range entries {
+		if entry.AccountID == "" || entry.Reference == "" || entry.Reference != entries[0].Reference || entry.Amount <= 0 {
+			return ledger.ErrInvalidEntry
+		}
+
+		switch entry.Type {
+		case ledger.EntryTypeDebit:
+			debits += float64(entry.Amount)
+		case ledger.EntryTypeCredit:
+			credits += float64(entry.Amount)
+		default:
+			return ledger.ErrInvalidEntry
+		}
+	}
+
+	// amounts are in major units with two decimals
+	if math.Abs(debits-credits) > 0.005 {
+		return ledger.ErrUnbalancedEntries
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package limit
+
+import (
+	"github.com/quabynah-bilson/quantia/pkg/limit"
+	"time"
+)
+
+// RepositoryConfiguration is a function that configures a repository
+type RepositoryConfiguration func(*Repository) error
+
+// Repository is the limit repository implementation
+type Repository struct {
+	DB limit.Database
+	limit.Repository
+}
+
+// NewRepository creates a new limit repository
+func NewRepository(configs ...RepositoryConfiguration) *Repository {
+	r := &Repository{}
+
+	for _, config := range configs {
+		_ = config(r)
+	}
+
+	return r
+}
+
+// Tier returns the tier of a subject, empty when none was set.
+func (r *Repository) Tier(subject string) (limit.Tier, error) {
+	return r.DB.GetTier(subject)
+}
+
+// SetTier sets the tier of a subject.
+func (r *Repository) SetTier(subject string, tier limit.Tier) error {
+	return r.DB.SetTier(subject, tier)
+}
+
+// Usage returns what a subject has spent in the day and month of the given time.
+func (r *Repository) Usage(subject string, now time.Time) (*limit.Usage, error) {
+	return r.DB.GetUsage(subject, now)
+}
+
+// Reserve atomically checks and records a transaction against the limits.
+func (r *Repository) Reserve(subject string, amount float32, limits limit.Limits, now time.Time) (*limit.Reservation, error) {
+	if err := r.DB.ReserveUsage(subject, amount, limits, now); err != nil {
+		return nil, err
+	}
+
+	return &limit.Reservation{Subject: subject, Amount: amount, At: now}, nil
+}
+
+// Release gives back a reservation.
+func (r *Repository) Release(reservation *limit.Reservation) error {
+	return r.DB.ReleaseUsage(reservation)
+}
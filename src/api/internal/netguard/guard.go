@@ -0,0 +1,193 @@
+package netguard
+
+import (
+	"context"
+	"github.com/quabynah-bilson/quantia/pkg/payment"
+	"log"
+	"net"
+	"net/netip"
+	"net/url"
+	"os"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// blockedPrefixes lists the ranges that are never reachable from merchant URLs, on top of the
+// loopback, private, link-local, multicast and unspecified addresses recognised by net/netip
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),         // "this" network
+	netip.MustParsePrefix("100.64.0.0/10"),     // carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),      // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"),     // benchmarking
+	netip.MustParsePrefix("240.0.0.0/4"),       // reserved, including broadcast
+	netip.MustParsePrefix("64:ff9b::/96"),      // NAT64 (may embed private IPv4 addresses)
+	netip.MustParsePrefix("fd00:ec2::254/128"), // AWS IPv6 metadata endpoint
+}
+
+// Config configures a Guard
+type Config struct {
+	// RequireHTTPS rejects plain http URLs (enabled in production)
+	RequireHTTPS bool
+
+	// AllowedHosts are host names or CIDR ranges that bypass the address checks (for local development)
+	AllowedHosts []string
+}
+
+// ConfigFromEnv reads the guard configuration from the environment.
+// HTTPS is required when APP_ENV is "production"; WEBHOOK_ALLOWED_HOSTS is a comma separated allowlist.
+func ConfigFromEnv() Config {
+	var allowed []string
+	for _, host := range strings.Split(os.Getenv("WEBHOOK_ALLOWED_HOSTS"), ",") {
+		if host = strings.TrimSpace(host); host != "" {
+			allowed = append(allowed, host)
+		}
+	}
+
+	return Config{
+		RequireHTTPS: os.Getenv("APP_ENV") == "production",
+		AllowedHosts: allowed,
+	}
+}
+
+// Guard protects outbound calls to merchant-supplied URLs against server-side request forgery
+type Guard struct {
+	requireHTTPS    bool
+	allowedHosts    map[string]bool
+	allowedPrefixes []netip.Prefix
+	resolver        *net.Resolver
+	payment.URLGuard
+}
+
+// NewGuard creates a new URL guard
+func NewGuard(config Config) *Guard {
+	g := &Guard{
+		requireHTTPS: config.RequireHTTPS,
+		allowedHosts: make(map[string]bool),
+		resolver:     net.DefaultResolver,
+	}
+
+	for _, host := range config.AllowedHosts {
+		if prefix, err := netip.ParsePrefix(host); err == nil {
+			g.allowedPrefixes = append(g.allowedPrefixes, prefix)
+			continue
+		}
+		if addr, err := netip.ParseAddr(host); err == nil {
+			g.allowedPrefixes = append(g.allowedPrefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		g.allowedHosts[strings.ToLower(host)] = true
+	}
+
+	return g
+}
+
+// CheckURL validates the URL's scheme and resolves its host, rejecting it if any address is forbidden
+func (g *Guard) CheckURL(ctx context.Context, rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Hostname() == "" {
+		return payment.ErrUnsafeURL
+	}
+
+	switch parsed.Scheme {
+	case "https":
+	case "http":
+		if g.requireHTTPS {
+			return payment.ErrInsecureURL
+		}
+	default:
+		return payment.ErrUnsafeURL
+	}
+
+	host := strings.ToLower(parsed.Hostname())
+	if g.allowedHosts[host] {
+		return nil
+	}
+
+	// an IP literal does not need to be resolved
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return g.checkAddr(addr)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	addrs, err := g.resolver.LookupNetIP(ctx, "ip", host)
+	if err != nil || len(addrs) == 0 {
+		log.Printf("error resolving webhook host %s: %v", host, err)
+		return payment.ErrUnsafeURL
+	}
+
+	for _, addr := range addrs {
+		if err := g.checkAddr(addr); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DialContext dials the given address, re-checking the resolved IP at connection time so that a
+// host cannot pass CheckURL and then be re-pointed to a forbidden address (DNS rebinding)
+func (g *Guard) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	dialer := &net.Dialer{
+		Timeout:   10 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+
+	if host, _, err := net.SplitHostPort(address); err != nil || !g.allowedHosts[strings.ToLower(host)] {
+		dialer.Control = g.control
+	}
+
+	return dialer.DialContext(ctx, network, address)
+}
+
+// control is called with the resolved address right before a socket connects
+func (g *Guard) control(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return payment.ErrUnsafeURL
+	}
+
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return payment.ErrUnsafeURL
+	}
+
+	return g.checkAddr(addr)
+}
+
+// checkAddr returns an error if the address is forbidden and not explicitly allowed
+func (g *Guard) checkAddr(addr netip.Addr) error {
+	addr = addr.Unmap()
+	for _, prefix := range g.allowedPrefixes {
+		if prefix.Contains(addr) {
+			return nil
+		}
+	}
+
+	if IsForbidden(addr) {
+		log.Printf("blocked webhook destination %s", addr)
+		return payment.ErrUnsafeURL
+	}
+
+	return nil
+}
+
+// IsForbidden reports whether the address is loopback, private, link-local (including cloud
+// metadata endpoints), multicast, unspecified or in another reserved range
+func IsForbidden(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return true
+	}
+
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,38 @@
+package overdraft
+
+import "github.com/quabynah-bilson/quantia/pkg/overdraft"
+
+// RepositoryConfiguration is a function that configures a repository
+type RepositoryConfiguration func(*Repository) error
+
+// Repository is the overdraft repository implementation
+type Repository struct {
+	DB overdraft.Database
+	overdraft.Repository
+}
+
+// NewRepository creates a new overdraft repository
+func NewRepository(configs ...RepositoryConfiguration) *Repository {
+	r := &Repository{}
+
+	for _, config := range configs {
+		_ = config(r)
+	}
+
+	return r
+}
+
+// Save creates or replaces the overdraft facility of an account.
+func (r *Repository) Save(facility *overdraft.Facility) error {
+	return r.DB.SaveFacility(facility)
+}
+
+// Find gets the overdraft facility of an account.
+func (r *Repository) Find(accountID string) (*overdraft.Facility, error) {
+	return r.DB.GetFacility(accountID)
+}
+
+// FindAll gets every overdraft facility, oldest first.
+func (r *Repository) FindAll() ([]*overdraft.Facility, error) {
+	return r.DB.GetFacilities()
+}
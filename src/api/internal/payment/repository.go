@@ -1,6 +1,8 @@
 package payment
 
 import (
+	"context"
+	"github.com/quabynah-bilson/quantia/pkg/event"
 	"github.com/quabynah-bilson/quantia/pkg/payment"
 )
 
@@ -29,7 +31,57 @@ func (r *Repository) Pay(amount float32, url string) (*payment.Transaction, erro
 	return r.DB.SendWebhook(amount, url)
 }
 
-// Subscribe subscribes to a given webhook URL.
-func (r *Repository) Subscribe(url string, queue chan *payment.WebhookPayload) error {
-	return r.DB.SubscribeToWebhook(url, queue)
+// Save creates or replaces a transaction.
+func (r *Repository) Save(transaction *payment.Transaction) error {
+	return r.DB.SaveTransaction(transaction)
+}
+
+// Find gets a transaction by ID.
+func (r *Repository) Find(id string) (*payment.Transaction, error) {
+	return r.DB.GetTransaction(id)
+}
+
+// SaveRefund creates or replaces a refund.
+func (r *Repository) SaveRefund(refund *payment.Refund) error {
+	return r.DB.SaveRefund(refund)
+}
+
+// FindRefund gets a refund by ID.
+func (r *Repository) FindRefund(id string) (*payment.Refund, error) {
+	return r.DB.GetRefund(id)
+}
+
+// FindRefunds gets the refunds of a transaction, oldest first.
+func (r *Repository) FindRefunds(transactionID string) ([]*payment.Refund, error) {
+	return r.DB.GetRefunds(transactionID)
+}
+
+// ReserveRefund reserves an amount of a transaction for a refund, without going over the limit.
+func (r *Repository) ReserveRefund(transactionID string, amount, limit float32) error {
+	return r.DB.ReserveRefund(transactionID, amount, limit)
+}
+
+// ReleaseRefund gives back an amount reserved for a refund that failed.
+func (r *Repository) ReleaseRefund(transactionID string, amount float32) error {
+	return r.DB.ReleaseRefund(transactionID, amount)
+}
+
+// Notify queues an event for delivery to the given URL.
+func (r *Repository) Notify(url string, envelope *event.Envelope) error {
+	return r.DB.PublishEvent(url, envelope)
+}
+
+// Subscribe subscribes to a given webhook URL until the context is cancelled.
+func (r *Repository) Subscribe(ctx context.Context, url string, queue chan *payment.WebhookPayload) error {
+	return r.DB.SubscribeToWebhook(ctx, url, queue)
+}
+
+// Checkpoint saves an undelivered webhook for later delivery.
+func (r *Repository) Checkpoint(payload *payment.WebhookPayload) error {
+	return r.DB.CheckpointWebhook(payload)
+}
+
+// Restore returns the webhooks saved by Checkpoint.
+func (r *Repository) Restore() ([]*payment.WebhookPayload, error) {
+	return r.DB.RestoreWebhooks()
 }
@@ -0,0 +1,105 @@
+package payment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/quabynah-bilson/quantia/internal/netguard"
+	"github.com/quabynah-bilson/quantia/internal/resilience"
+	"github.com/quabynah-bilson/quantia/pkg/payment"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// HTTPWebhookClient implements the WebhookClient interface over HTTP
+type HTTPWebhookClient struct {
+	client  *http.Client
+	guard   *netguard.Guard
+	timeout time.Duration
+	payment.WebhookClient
+}
+
+// NewHTTPWebhookClient creates a new webhook client that reuses pooled connections
+// and bounds every delivery attempt by the given timeout.
+// Destinations are checked by the guard before each attempt and again when connecting.
+func NewHTTPWebhookClient(timeout time.Duration, guard *netguard.Guard) payment.WebhookClient {
+	transport := &http.Transport{
+		// no proxy: the guard must see the address that is actually dialled
+		DialContext:         guard.DialContext,
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 10,
+		IdleConnTimeout:     90 * time.Second,
+		TLSHandshakeTimeout: 10 * time.Second,
+	}
+
+	return &HTTPWebhookClient{
+		client: &http.Client{
+			Transport: transport,
+			// redirects are not followed so that a merchant cannot bounce the request elsewhere
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		guard:   guard,
+		timeout: timeout,
+	}
+}
+
+// Deliver posts the event envelope of the given payload to its destination URL
+func (c *HTTPWebhookClient) Deliver(ctx context.Context, payload *payment.WebhookPayload) error {
+	if payload.Event == nil {
+		return resilience.Permanent(payment.ErrFailedToMarshalTransaction)
+	}
+
+	// bound the attempt by the configured timeout
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	// the destination may have changed since the payment was accepted
+	if err := c.guard.CheckURL(ctx, payload.Url); err != nil {
+		return resilience.Permanent(err)
+	}
+
+	// only the event envelope is sent to the merchant
+	body, err := json.Marshal(payload.Event)
+	if err != nil {
+		return resilience.Permanent(payment.ErrFailedToMarshalTransaction)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, payload.Url, bytes.NewReader(body))
+	if err != nil {
+		log.Printf("error creating webhook request: %v", err)
+		return resilience.Permanent(payment.ErrWebhookDeliveryFailed)
+	}
+
+	// set the content type and event headers (the event ID lets merchants de-duplicate retries)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Quantia-Event-Id", payload.Event.ID)
+	req.Header.Set("X-Quantia-Event-Type", string(payload.Event.Type))
+	req.Header.Set("X-Quantia-Delivery-Attempt", strconv.Itoa(payload.Attempt))
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		log.Printf("error delivering webhook: %v", err)
+		if errors.Is(err, payment.ErrUnsafeURL) {
+			return resilience.Permanent(payment.ErrUnsafeURL)
+		}
+		return payment.ErrWebhookDeliveryFailed
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	// any non-2xx response is treated as a failed delivery
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return &payment.DeliveryError{
+			Status: resp.StatusCode,
+			Delay:  resilience.ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
+	}
+
+	return nil
+}
@@ -1,44 +1,181 @@
 package payment
 
 import (
+	"context"
+	"github.com/quabynah-bilson/quantia/internal/resilience"
 	pkg "github.com/quabynah-bilson/quantia/pkg/payment"
 	"log"
+	"net/url"
+	"sync"
 	"time"
 )
 
-// ProcessWebhooks is a worker that processes webhooks
-func ProcessWebhooks(repo *Repository, webhookQueue chan *pkg.WebhookPayload) {
-	log.Println("starting webhook worker")
-	for payload := range webhookQueue {
-		go func(p *pkg.WebhookPayload) {
-			backoffTime, maxBackoffTime := time.Second, time.Minute
-			retries, maxRetries := 0, 5
-			for {
-				log.Printf("processing transaction %s", p.ID)
-				// process the webhook payload
-				if _, err := repo.Pay(p.Amount, p.Url); err == nil {
-					log.Printf("successfully processed transaction %s", p.ID)
-					break // success
-				}
+// WorkerConfig configures the webhook worker pool
+type WorkerConfig struct {
+	// Workers is the number of concurrent deliveries
+	Workers int
 
-				// compare the retries to the max retries
-				retries++
-				if retries >= maxRetries {
-					log.Printf("max retries reached for transaction %s", p.ID)
-					break // max retries reached
-				}
+	// RetryPolicy decides whether and when failed deliveries are retried
+	RetryPolicy resilience.RetryPolicy
 
-				// retry after backoff time
-				log.Printf("retrying transaction %s in %s", p.ID, backoffTime)
-				time.Sleep(backoffTime)
+	// Breakers holds one circuit breaker per merchant host
+	Breakers *resilience.CircuitBreakers
+}
 
-				// double the backoff time for the next iteration, capped at the max backoff time
-				backoffTime *= 2
-				if backoffTime > maxBackoffTime {
-					backoffTime = maxBackoffTime
+// ProcessWebhooks runs a fixed pool of workers that deliver queued webhooks using the given client.
+// Failed deliveries are re-queued after the retry policy's delay instead of holding a worker, and
+// deliveries to a host whose circuit is open are postponed until the circuit allows a probe.
+// When the context is cancelled, in-flight attempts are allowed to finish and every webhook that
+// has not been delivered yet (queued or waiting for a retry) is checkpointed in the repository.
+// It returns once all workers have stopped.
+func ProcessWebhooks(ctx context.Context, repo pkg.Repository, client pkg.WebhookClient, webhookQueue chan *pkg.WebhookPayload, config WorkerConfig) {
+	if config.Workers < 1 {
+		config.Workers = 1
+	}
+	if config.Breakers == nil {
+		config.Breakers = resilience.NewCircuitBreakers(5, time.Minute)
+	}
+
+	retries := newRetryScheduler(ctx, repo, webhookQueue)
+
+	log.Printf("starting webhook worker with %d workers", config.Workers)
+	var wg sync.WaitGroup
+	for i := 0; i < config.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case p := <-webhookQueue:
+					processWebhook(client, config, retries, p)
 				}
-				log.Printf("backoff time for transaction %s is now %s", p.ID, backoffTime)
 			}
-		}(payload)
+		}()
+	}
+	wg.Wait()
+
+	// checkpoint the webhooks waiting for a retry, then whatever is left in the queue
+	retries.stop()
+	for {
+		select {
+		case p := <-webhookQueue:
+			checkpointWebhook(repo, p)
+		default:
+			log.Println("webhook worker stopped")
+			return
+		}
+	}
+}
+
+// processWebhook makes a single delivery attempt and schedules a retry if it fails
+func processWebhook(client pkg.WebhookClient, config WorkerConfig, retries *retryScheduler, p *pkg.WebhookPayload) {
+	breaker := config.Breakers.Get(destinationHost(p.Url))
+	if wait, ok := breaker.Allow(); !ok {
+		log.Printf("circuit open for event %s, postponing delivery by %s", p.ID, wait)
+		retries.schedule(p, wait)
+		return
+	}
+
+	// deliver the webhook payload (an attempt that has started is not interrupted by shutdown)
+	log.Printf("processing event %s", p.ID)
+	p.Attempt++
+	err := client.Deliver(context.Background(), p)
+	if err == nil {
+		breaker.Success()
+		log.Printf("successfully processed event %s", p.ID)
+		return
+	}
+
+	// only failures that say something about the destination's health count towards its circuit
+	if resilience.IsRetryable(err) {
+		breaker.Failure()
+	} else {
+		breaker.Success()
+	}
+
+	delay, retry := config.RetryPolicy.NextDelay(p.Attempt, err)
+	if !retry {
+		log.Printf("giving up on event %s after %d attempts: %v", p.ID, p.Attempt, err)
+		return
+	}
+
+	log.Printf("retrying event %s in %s: %v", p.ID, delay, err)
+	retries.schedule(p, delay)
+}
+
+// destinationHost returns the host that a webhook is delivered to
+func destinationHost(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
 	}
+
+	return parsed.Host
+}
+
+// checkpointWebhook saves an undelivered webhook so that it is retried on the next start
+func checkpointWebhook(repo pkg.Repository, p *pkg.WebhookPayload) {
+	if err := repo.Checkpoint(p); err != nil {
+		log.Printf("error checkpointing event %s: %v", p.ID, err)
+		return
+	}
+	log.Printf("checkpointed event %s", p.ID)
+}
+
+// retryScheduler re-queues webhooks after a delay without holding a worker while waiting
+type retryScheduler struct {
+	ctx     context.Context
+	repo    pkg.Repository
+	queue   chan *pkg.WebhookPayload
+	mu      sync.Mutex
+	pending map[*pkg.WebhookPayload]*time.Timer
+	wg      sync.WaitGroup
+}
+
+// newRetryScheduler creates a scheduler that re-queues webhooks on the given queue
+func newRetryScheduler(ctx context.Context, repo pkg.Repository, queue chan *pkg.WebhookPayload) *retryScheduler {
+	return &retryScheduler{
+		ctx:     ctx,
+		repo:    repo,
+		queue:   queue,
+		pending: make(map[*pkg.WebhookPayload]*time.Timer),
+	}
+}
+
+// schedule re-queues the webhook once the delay has elapsed
+func (s *retryScheduler) schedule(p *pkg.WebhookPayload, delay time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.wg.Add(1)
+	s.pending[p] = time.AfterFunc(delay, func() {
+		defer s.wg.Done()
+
+		s.mu.Lock()
+		delete(s.pending, p)
+		s.mu.Unlock()
+
+		select {
+		case s.queue <- p:
+		case <-s.ctx.Done():
+			checkpointWebhook(s.repo, p)
+		}
+	})
+}
+
+// stop cancels the pending retries, checkpoints them and waits for the retries already firing
+func (s *retryScheduler) stop() {
+	s.mu.Lock()
+	for p, timer := range s.pending {
+		if timer.Stop() {
+			checkpointWebhook(s.repo, p)
+			s.wg.Done()
+		}
+		delete(s.pending, p)
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
 }
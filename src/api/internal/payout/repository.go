@@ -0,0 +1,63 @@
+package payout
+
+import "github.com/quabynah-bilson/quantia/pkg/payout"
+
+// RepositoryConfiguration is a function that configures a repository
+type RepositoryConfiguration func(*Repository) error
+
+// Repository is the payout repository implementation
+type Repository struct {
+	DB payout.Database
+	payout.Repository
+}
+
+// NewRepository creates a new payout repository
+func NewRepository(configs ...RepositoryConfiguration) *Repository {
+	r := &Repository{}
+
+	for _, config := range configs {
+		_ = config(r)
+	}
+
+	return r
+}
+
+// Save creates or replaces a batch.
+func (r *Repository) Save(batch *payout.Batch) error {
+	return r.DB.SaveBatch(batch)
+}
+
+// Find gets a batch by ID.
+func (r *Repository) Find(id string) (*payout.Batch, error) {
+	return r.DB.GetBatch(id)
+}
+
+// Queued gets the IDs of the batches waiting for the payout worker.
+func (r *Repository) Queued(limit int) ([]string, error) {
+	return r.DB.GetQueuedBatches(limit)
+}
+
+// CreateItems stores the items of a new batch.
+func (r *Repository) CreateItems(items []*payout.Item) error {
+	return r.DB.CreateItems(items)
+}
+
+// SaveItem replaces an item.
+func (r *Repository) SaveItem(item *payout.Item) error {
+	return r.DB.SaveItem(item)
+}
+
+// FindItem gets an item by ID.
+func (r *Repository) FindItem(id string) (*payout.Item, error) {
+	return r.DB.GetItem(id)
+}
+
+// Items gets the items of a batch, in upload order.
+func (r *Repository) Items(batchID string) ([]*payout.Item, error) {
+	return r.DB.GetBatchItems(batchID)
+}
+
+// Claim marks an item as taken before it is paid.
+func (r *Repository) Claim(id string) error {
+	return r.DB.ClaimItem(id)
+}
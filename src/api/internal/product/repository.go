@@ -0,0 +1,78 @@
+package product
+
+import "github.com/quabynah-bilson/quantia/pkg/product"
+
+// RepositoryConfiguration is a function that configures a repository
+type RepositoryConfiguration func(*Repository) error
+
+// Repository is the product repository implementation
+type Repository struct {
+	DB product.Database
+	product.Repository
+}
+
+// NewRepository creates a new product repository
+func NewRepository(configs ...RepositoryConfiguration) *Repository {
+	r := &Repository{}
+
+	for _, config := range configs {
+		_ = config(r)
+	}
+
+	return r
+}
+
+// Save creates or replaces a product.
+func (r *Repository) Save(product *product.Product) error {
+	return r.DB.SaveProduct(product)
+}
+
+// Find gets a product by ID.
+func (r *Repository) Find(id string) (*product.Product, error) {
+	return r.DB.GetProduct(id)
+}
+
+// FindAll gets every product, oldest first.
+func (r *Repository) FindAll() ([]*product.Product, error) {
+	return r.DB.GetProducts()
+}
+
+// Assign creates or replaces the product of an account.
+func (r *Repository) Assign(assignment *product.Assignment) error {
+	return r.DB.SaveAssignment(assignment)
+}
+
+// FindAssignment gets the product of an account.
+func (r *Repository) FindAssignment(accountID string) (*product.Assignment, error) {
+	return r.DB.GetAssignment(accountID)
+}
+
+// Assignments gets the products of every account.
+func (r *Repository) Assignments() ([]*product.Assignment, error) {
+	return r.DB.GetAssignments()
+}
+
+// SaveAccrual stores the accrual of an account for a business date, once.
+func (r *Repository) SaveAccrual(accrual *product.Accrual) error {
+	return r.DB.SaveAccrual(accrual)
+}
+
+// Accruals gets the accruals of an account between two business dates, both included.
+func (r *Repository) Accruals(accountID, from, to string) ([]*product.Accrual, error) {
+	return r.DB.GetAccruals(accountID, from, to)
+}
+
+// SaveBusinessDay creates or replaces the outcome of the end-of-day batch of a business date.
+func (r *Repository) SaveBusinessDay(day *product.BusinessDay) error {
+	return r.DB.SaveBusinessDay(day)
+}
+
+// FindBusinessDay gets the outcome of the end-of-day batch of a business date.
+func (r *Repository) FindBusinessDay(date string) (*product.BusinessDay, error) {
+	return r.DB.GetBusinessDay(date)
+}
+
+// LastCompletedDay gets the latest completed business date.
+func (r *Repository) LastCompletedDay() (string, error) {
+	return r.DB.GetLastCompletedDay()
+}
@@ -0,0 +1,43 @@
+package reconciliation
+
+import "github.com/quabynah-bilson/quantia/pkg/reconciliation"
+
+// RepositoryConfiguration is a function that configures a repository
+type RepositoryConfiguration func(*Repository) error
+
+// Repository is the reconciliation repository implementation
+type Repository struct {
+	DB reconciliation.Database
+	reconciliation.Repository
+}
+
+// NewRepository creates a new reconciliation repository
+func NewRepository(configs ...RepositoryConfiguration) *Repository {
+	r := &Repository{}
+
+	for _, config := range configs {
+		_ = config(r)
+	}
+
+	return r
+}
+
+// Save stores a new statement with its items.
+func (r *Repository) Save(statement *reconciliation.Statement, items []*reconciliation.Item) error {
+	return r.DB.SaveStatement(statement, items)
+}
+
+// Find gets a statement by ID.
+func (r *Repository) Find(id string) (*reconciliation.Statement, error) {
+	return r.DB.GetStatement(id)
+}
+
+// Items gets the items of a statement, in statement order.
+func (r *Repository) Items(statementID string) ([]*reconciliation.Item, error) {
+	return r.DB.GetStatementItems(statementID)
+}
+
+// DateItems gets the items falling on a business date.
+func (r *Repository) DateItems(date string) ([]*reconciliation.Item, error) {
+	return r.DB.GetDateItems(date)
+}
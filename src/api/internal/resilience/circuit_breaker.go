@@ -0,0 +1,129 @@
+package resilience
+
+import (
+	"sync"
+	"time"
+)
+
+// CircuitState is the type that represents the state of a circuit breaker
+type CircuitState string
+
+const (
+	// CircuitClosed lets every call through
+	CircuitClosed CircuitState = "closed"
+
+	// CircuitOpen rejects calls until the cooldown has elapsed
+	CircuitOpen CircuitState = "open"
+
+	// CircuitHalfOpen lets a single probe call through to test the destination
+	CircuitHalfOpen CircuitState = "half-open"
+)
+
+// CircuitBreaker stops calling a destination after consecutive failures
+type CircuitBreaker struct {
+	mu        sync.Mutex
+	state     CircuitState
+	failures  int
+	openedAt  time.Time
+	probing   bool
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+}
+
+// NewCircuitBreaker creates a circuit breaker that opens after threshold consecutive failures
+// and allows a probe call once the cooldown has elapsed
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	if threshold < 1 {
+		threshold = 1
+	}
+
+	return &CircuitBreaker{
+		state:     CircuitClosed,
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+// Allow reports whether a call may proceed. When it may not, it returns how long until the next probe.
+func (b *CircuitBreaker) Allow() (time.Duration, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitOpen:
+		remaining := b.cooldown - b.now().Sub(b.openedAt)
+		if remaining > 0 {
+			return remaining, false
+		}
+		b.state, b.probing = CircuitHalfOpen, true
+		return 0, true
+	case CircuitHalfOpen:
+		if b.probing {
+			return b.cooldown, false
+		}
+		b.probing = true
+		return 0, true
+	}
+
+	return 0, true
+}
+
+// Success records a successful call and closes the circuit
+func (b *CircuitBreaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state, b.failures, b.probing = CircuitClosed, 0, false
+}
+
+// Failure records a failed call, opening the circuit once the threshold is reached or a probe fails
+func (b *CircuitBreaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	if b.state == CircuitHalfOpen || b.failures >= b.threshold {
+		b.state, b.openedAt, b.probing = CircuitOpen, b.now(), false
+	}
+}
+
+// State returns the current state of the circuit
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+// CircuitBreakers keeps one circuit breaker per destination (e.g. per host)
+type CircuitBreakers struct {
+	mu        sync.Mutex
+	breakers  map[string]*CircuitBreaker
+	threshold int
+	cooldown  time.Duration
+}
+
+// NewCircuitBreakers creates a set of per-destination circuit breakers sharing the same settings
+func NewCircuitBreakers(threshold int, cooldown time.Duration) *CircuitBreakers {
+	return &CircuitBreakers{
+		breakers:  make(map[string]*CircuitBreaker),
+		threshold: threshold,
+		cooldown:  cooldown,
+	}
+}
+
+// Get returns the circuit breaker of the given destination, creating it if needed
+func (c *CircuitBreakers) Get(destination string) *CircuitBreaker {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	breaker, ok := c.breakers[destination]
+	if !ok {
+		breaker = NewCircuitBreaker(c.threshold, c.cooldown)
+		c.breakers[destination] = breaker
+	}
+
+	return breaker
+}
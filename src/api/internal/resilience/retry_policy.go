@@ -0,0 +1,163 @@
+package resilience
+
+import (
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// StatusError is implemented by errors that carry the HTTP status of a failed call.
+type StatusError interface {
+	error
+
+	// StatusCode returns the HTTP status code of the response
+	StatusCode() int
+
+	// RetryAfter returns the delay requested by the Retry-After header (zero if absent)
+	RetryAfter() time.Duration
+}
+
+// RetryPolicy describes how failed operations are retried.
+// Delays grow exponentially and are fully jittered: each delay is a random value in [0, min(MaxBackoff, InitialBackoff*2^attempt)).
+type RetryPolicy struct {
+	// InitialBackoff is the upper bound of the first delay
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the exponential growth of the delay
+	MaxBackoff time.Duration
+
+	// MaxAttempts is the total number of attempts, including the first one
+	MaxAttempts int
+
+	// MaxRetryAfter is the longest Retry-After delay that is honoured. Longer requests stop retrying.
+	MaxRetryAfter time.Duration
+
+	// random returns a number in [0, 1). It defaults to math/rand.
+	random func() float64
+}
+
+// DefaultRetryPolicy returns the policy used for webhook deliveries when nothing is configured
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		InitialBackoff: time.Second,
+		MaxBackoff:     time.Minute,
+		MaxAttempts:    5,
+		MaxRetryAfter:  10 * time.Minute,
+	}
+}
+
+// WithRandom returns a copy of the policy using the given random source (for deterministic tests)
+func (p RetryPolicy) WithRandom(random func() float64) RetryPolicy {
+	p.random = random
+	return p
+}
+
+// Backoff returns the jittered delay before the given retry (attempt 1 is the first retry)
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	ceiling := p.InitialBackoff
+	for i := 1; i < attempt && ceiling < p.MaxBackoff; i++ {
+		ceiling *= 2
+	}
+	if ceiling > p.MaxBackoff {
+		ceiling = p.MaxBackoff
+	}
+
+	random := p.random
+	if random == nil {
+		random = rand.Float64
+	}
+
+	return time.Duration(random() * float64(ceiling))
+}
+
+// NextDelay decides whether an operation that failed with err after the given number of attempts
+// should be retried, and if so how long to wait. A Retry-After delay longer than the backoff is respected.
+func (p RetryPolicy) NextDelay(attempts int, err error) (time.Duration, bool) {
+	if attempts >= p.MaxAttempts || !IsRetryable(err) {
+		return 0, false
+	}
+
+	delay := p.Backoff(attempts)
+
+	var statusErr StatusError
+	if errors.As(err, &statusErr) && statusErr.RetryAfter() > 0 {
+		if p.MaxRetryAfter > 0 && statusErr.RetryAfter() > p.MaxRetryAfter {
+			return 0, false
+		}
+		if statusErr.RetryAfter() > delay {
+			delay = statusErr.RetryAfter()
+		}
+	}
+
+	return delay, true
+}
+
+// IsRetryable reports whether an operation that failed with err can be retried.
+// Errors without an HTTP status (timeouts, refused connections) are retryable.
+func IsRetryable(err error) bool {
+	var permanent *permanentError
+	if err == nil || errors.As(err, &permanent) {
+		return false
+	}
+
+	var statusErr StatusError
+	if errors.As(err, &statusErr) {
+		return IsRetryableStatus(statusErr.StatusCode())
+	}
+
+	return true
+}
+
+// IsRetryableStatus reports whether a response with the given status code is worth retrying
+func IsRetryableStatus(code int) bool {
+	switch code {
+	case http.StatusRequestTimeout, http.StatusTooEarly, http.StatusTooManyRequests:
+		return true
+	case http.StatusNotImplemented, http.StatusHTTPVersionNotSupported:
+		return false
+	}
+
+	return code >= http.StatusInternalServerError
+}
+
+// ParseRetryAfter parses a Retry-After header value given either in seconds or as an HTTP date
+func ParseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+
+	return 0
+}
+
+// permanentError marks an error that must not be retried
+type permanentError struct {
+	err error
+}
+
+// Error returns the message of the wrapped error
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent wraps err so that IsRetryable reports false for it
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
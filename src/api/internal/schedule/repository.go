@@ -0,0 +1,56 @@
+package schedule
+
+import (
+	"github.com/quabynah-bilson/quantia/pkg/schedule"
+	"time"
+)
+
+// RepositoryConfiguration is a function that configures a repository
+type RepositoryConfiguration func(*Repository) error
+
+// Repository is the scheduled payment repository implementation
+type Repository struct {
+	DB schedule.Database
+	schedule.Repository
+}
+
+// NewRepository creates a new scheduled payment repository
+func NewRepository(configs ...RepositoryConfiguration) *Repository {
+	r := &Repository{}
+
+	for _, config := range configs {
+		_ = config(r)
+	}
+
+	return r
+}
+
+// Save creates or replaces a scheduled payment.
+func (r *Repository) Save(s *schedule.ScheduledPayment) error {
+	return r.DB.SaveSchedule(s)
+}
+
+// Find gets a scheduled payment by ID.
+func (r *Repository) Find(id string) (*schedule.ScheduledPayment, error) {
+	return r.DB.GetSchedule(id)
+}
+
+// Due returns the IDs of the active schedules that should run at or before the given time.
+func (r *Repository) Due(now time.Time, limit int) ([]string, error) {
+	return r.DB.GetDueSchedules(now, limit)
+}
+
+// Claim takes an occurrence so that no other run executes it.
+func (r *Repository) Claim(id string, occurrence int) error {
+	return r.DB.ClaimOccurrence(id, occurrence)
+}
+
+// Record saves the outcome of an occurrence.
+func (r *Repository) Record(run *schedule.Run) error {
+	return r.DB.SaveRun(run)
+}
+
+// Runs returns the outcomes of a schedule's occurrences, oldest first.
+func (r *Repository) Runs(id string) ([]*schedule.Run, error) {
+	return r.DB.GetRuns(id)
+}
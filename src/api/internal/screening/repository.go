@@ -0,0 +1,43 @@
+package screening
+
+import "github.com/quabynah-bilson/quantia/pkg/screening"
+
+// RepositoryConfiguration is a function that configures a repository
+type RepositoryConfiguration func(*Repository) error
+
+// Repository is the screening repository implementation
+type Repository struct {
+	DB screening.Database
+	screening.Repository
+}
+
+// NewRepository creates a new screening repository
+func NewRepository(configs ...RepositoryConfiguration) *Repository {
+	r := &Repository{}
+
+	for _, config := range configs {
+		_ = config(r)
+	}
+
+	return r
+}
+
+// Save saves a screening as the latest of its party.
+func (r *Repository) Save(s *screening.Screening) error {
+	return r.DB.SaveScreening(s)
+}
+
+// Find returns a screening by ID.
+func (r *Repository) Find(id string) (*screening.Screening, error) {
+	return r.DB.GetScreening(id)
+}
+
+// FindByParty returns the latest screening of a party.
+func (r *Repository) FindByParty(partyType screening.PartyType, partyID string) (*screening.Screening, error) {
+	return r.DB.GetPartyScreening(partyType, partyID)
+}
+
+// ReviewQueue returns the screenings awaiting review, oldest first.
+func (r *Repository) ReviewQueue() ([]*screening.Screening, error) {
+	return r.DB.GetReviewQueue()
+}
@@ -0,0 +1,43 @@
+package statement
+
+import "github.com/quabynah-bilson/quantia/pkg/statement"
+
+// RepositoryConfiguration is a function that configures a repository
+type RepositoryConfiguration func(*Repository) error
+
+// Repository is the statement repository implementation
+type Repository struct {
+	DB statement.Database
+	statement.Repository
+}
+
+// NewRepository creates a new statement repository
+func NewRepository(configs ...RepositoryConfiguration) *Repository {
+	r := &Repository{}
+
+	for _, config := range configs {
+		_ = config(r)
+	}
+
+	return r
+}
+
+// Save stores a new monthly statement.
+func (r *Repository) Save(statement *statement.Statement) error {
+	return r.DB.SaveStatement(statement)
+}
+
+// Find gets a statement by ID.
+func (r *Repository) Find(id string) (*statement.Statement, error) {
+	return r.DB.GetStatement(id)
+}
+
+// FindByPeriod gets the statement of an account for a period.
+func (r *Repository) FindByPeriod(accountID, period string) (*statement.Statement, error) {
+	return r.DB.GetPeriodStatement(accountID, period)
+}
+
+// FindByAccount gets the statements of an account, oldest period first.
+func (r *Repository) FindByAccount(accountID string) ([]*statement.Statement, error) {
+	return r.DB.GetAccountStatements(accountID)
+}
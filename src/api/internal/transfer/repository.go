@@ -0,0 +1,40 @@
+package transfer
+
+import (
+	"github.com/quabynah-bilson/quantia/pkg/transfer"
+)
+
+// RepositoryConfiguration is a function that configures a repository
+type RepositoryConfiguration func(*Repository) error
+
+// Repository is the transfer repository implementation
+type Repository struct {
+	DB transfer.Database
+	transfer.Repository
+}
+
+// NewRepository creates a new transfer repository
+func NewRepository(configs ...RepositoryConfiguration) *Repository {
+	r := &Repository{}
+
+	for _, config := range configs {
+		_ = config(r)
+	}
+
+	return r
+}
+
+// Save creates or replaces a transfer.
+func (r *Repository) Save(t *transfer.Transfer) error {
+	return r.DB.SaveTransfer(t)
+}
+
+// Find gets a transfer by ID.
+func (r *Repository) Find(id string) (*transfer.Transfer, error) {
+	return r.DB.GetTransfer(id)
+}
+
+// FindByDate gets the transfers made on a business date, oldest first.
+func (r *Repository) FindByDate(date string) ([]*transfer.Transfer, error) {
+	return r.DB.GetDateTransfers(date)
+}
@@ -27,5 +27,19 @@ func PerformMigrations(conn *pgx.Conn, errChan chan error) {
 	// alter the accounts table to add a unique constraint on the username column
 	_, _ = conn.Exec(ctx, "ALTER TABLE accounts ADD CONSTRAINT unique_username UNIQUE (username)")
 
+	// create the ledger entries table. A reference is posted once, so replays of the same operation are ignored
+	_, _ = conn.Exec(ctx, "CREATE TABLE IF NOT EXISTS ledger_entries (id UUID PRIMARY KEY, account_id VARCHAR(255) NOT NULL, reference VARCHAR(255) NOT NULL, entry_type VARCHAR(16) NOT NULL, amount NUMERIC(18, 2) NOT NULL CHECK (amount > 0), description TEXT NOT NULL DEFAULT '', created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP)")
+	_, _ = conn.Exec(ctx, "CREATE INDEX IF NOT EXISTS ledger_entries_account_id ON ledger_entries (account_id, created_at)")
+	_, _ = conn.Exec(ctx, "DROP INDEX IF EXISTS ledger_entries_reference")
+	_, _ = conn.Exec(ctx, "CREATE UNIQUE INDEX IF NOT EXISTS ledger_entries_posting ON ledger_entries (reference, account_id, entry_type)")
+
+	// create the ledger holds table
+	_, _ = conn.Exec(ctx, "CREATE TABLE IF NOT EXISTS ledger_holds (id VARCHAR(64) PRIMARY KEY, account_id VARCHAR(255) NOT NULL, credit_account_id VARCHAR(255) NOT NULL, amount NUMERIC(18, 2) NOT NULL CHECK (amount > 0), captured_amount NUMERIC(18, 2) NOT NULL DEFAULT 0, status VARCHAR(16) NOT NULL, expires_at TIMESTAMP NOT NULL, created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP)")
+	_, _ = conn.Exec(ctx, "CREATE INDEX IF NOT EXISTS ledger_holds_active ON ledger_holds (account_id) WHERE status = 'active'")
+	_, _ = conn.Exec(ctx, "CREATE INDEX IF NOT EXISTS ledger_holds_expiry ON ledger_holds (expires_at) WHERE status = 'active'")
+
+	// create the ledger overdrafts table, holding how far below zero each account may go
+	_, _ = conn.Exec(ctx, "CREATE TABLE IF NOT EXISTS ledger_overdrafts (account_id VARCHAR(255) PRIMARY KEY, overdraft_limit NUMERIC(18, 2) NOT NULL CHECK (overdraft_limit >= 0), updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP)")
+
 	errChan <- nil
 }
@@ -7,4 +7,7 @@ type Repository interface {
 
 	// Login logs in a user.
 	Login(username string, password string) (*Account, error)
+
+	// Find gets an account by ID.
+	Find(id string) (*Account, error)
 }
@@ -3,9 +3,11 @@ package pkg
 import (
 	"errors"
 	"github.com/quabynah-bilson/quantia/pkg/account"
+	"github.com/quabynah-bilson/quantia/pkg/ledger"
 	"github.com/quabynah-bilson/quantia/pkg/token"
 	"log"
 	"regexp"
+	"strings"
 )
 
 var (
@@ -17,12 +19,41 @@ var (
 
 	// ErrInvalidToken is returned when the token is invalid.
 	ErrInvalidToken = errors.New("invalid token. token must be a valid JWT token")
+
+	// ErrNameRequired is returned when a user registers without the full name they are screened by.
+	ErrNameRequired = errors.New("invalid name. full name is required to register")
+
+	// ErrRoleRequired is returned when an account does not hold any of the roles an operation requires.
+	ErrRoleRequired = errors.New("forbidden. account does not hold a role allowed to perform this operation")
+
+	// ErrAccountNotOwned is returned when an account acts on a resource that belongs to another account.
+	ErrAccountNotOwned = errors.New("forbidden. the resource belongs to another account")
+)
+
+// Role is the role of an operator account. Accounts without a role are customers.
+type Role string
+
+const (
+	// RoleAdmin configures the bank, e.g. its products and the overdraft limits of accounts.
+	RoleAdmin Role = "admin"
+
+	// RoleAnalyst reviews held payments and sanctions hits.
+	RoleAnalyst Role = "analyst"
 )
 
 // AuthUseCase is the auth use case. It contains the necessary repositories to perform auth operations.
 type AuthUseCase struct {
 	accountRepo account.Repository
 	tokenRepo   token.Repository
+
+	// screening checks new users against the sanctions lists. Users are not screened when it is nil.
+	screening *ScreeningUseCase
+
+	// roles holds the role of each operator account by its ID
+	roles map[string]Role
+
+	// merchants holds the ledger account of each merchant account by its ID
+	merchants map[string]string
 }
 
 // NewAuthUseCase creates a new account use case.
@@ -33,8 +64,33 @@ func NewAuthUseCase(authRepo account.Repository, tokenRepo token.Repository) *Au
 	}
 }
 
-// Register registers a new user.
-func (uc *AuthUseCase) Register(username string, password string) (*string, error) {
+// SetScreening makes new users go through sanctions screening when they register.
+func (uc *AuthUseCase) SetScreening(screening *ScreeningUseCase) {
+	uc.screening = screening
+}
+
+// SetRoles sets the roles of the operator accounts, by their ID.
+func (uc *AuthUseCase) SetRoles(roles map[string]Role) {
+	uc.roles = roles
+}
+
+// SetMerchants sets the merchants the accounts act for, as the URLs their payments notify by account ID.
+func (uc *AuthUseCase) SetMerchants(merchants map[string]string) {
+	uc.merchants = make(map[string]string, len(merchants))
+	for accountID, url := range merchants {
+		uc.merchants[accountID] = ledger.MerchantAccountID(url)
+	}
+}
+
+// MerchantAccount returns the ledger account of the merchant the account acts for, or an empty string when
+// it does not act for one.
+func (uc *AuthUseCase) MerchantAccount(accountID string) string {
+	return uc.merchants[accountID]
+}
+
+// Register registers a new user. When screening is enabled the full name is required, and a user with
+// sanctions hits is registered but cannot transact until the hits are reviewed.
+func (uc *AuthUseCase) Register(username string, password string, name string) (*string, error) {
 	if err := validateUsername(username); err != nil {
 		log.Printf("error validating username: %v", err)
 		return nil, err
@@ -45,12 +101,24 @@ func (uc *AuthUseCase) Register(username string, password string) (*string, erro
 		return nil, err
 	}
 
+	name = strings.TrimSpace(name)
+	if uc.screening != nil && name == "" {
+		return nil, ErrNameRequired
+	}
+
 	userAccount, err := uc.accountRepo.Register(username, password)
 	if err != nil {
 		log.Printf("error registering user: %v", err)
 		return nil, err
 	}
 
+	if uc.screening != nil {
+		if _, err = uc.screening.ScreenAccount(userAccount.ID, name); err != nil {
+			log.Printf("error screening user: %v", err)
+			return nil, err
+		}
+	}
+
 	generatedToken, err := uc.tokenRepo.GenerateToken(userAccount.ID)
 	if err != nil {
 		log.Printf("error generating token: %v", err)
@@ -107,6 +175,22 @@ func (uc *AuthUseCase) ValidateToken(rawToken, accountID string) error {
 	return nil
 }
 
+// CheckRole checks that the account holds one of the given roles.
+func (uc *AuthUseCase) CheckRole(accountID string, roles ...Role) error {
+	held, ok := uc.roles[accountID]
+	if !ok {
+		return ErrRoleRequired
+	}
+
+	for _, role := range roles {
+		if held == role {
+			return nil
+		}
+	}
+
+	return ErrRoleRequired
+}
+
 // validateUsername validates the given username.
 func validateUsername(username string) error {
 	emailRegex := regexp.MustCompile(`^[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}$`)
@@ -0,0 +1,30 @@
+package beneficiary
+
+import "errors"
+
+var (
+	// ErrBeneficiaryNotFound is the error returned when a beneficiary does not exist
+	ErrBeneficiaryNotFound = errors.New("beneficiary not found")
+
+	// ErrBeneficiaryAlreadyExists is the error returned when an account saves the same destination twice
+	ErrBeneficiaryAlreadyExists = errors.New("this destination is already saved as a beneficiary")
+
+	// ErrFailedToSaveBeneficiary is the error returned when a beneficiary cannot be stored
+	ErrFailedToSaveBeneficiary = errors.New("failed to save beneficiary. Please try again")
+)
+
+// Database is the interface that wraps the basic beneficiary database operations.
+type Database interface {
+	// CreateBeneficiary stores a new beneficiary, failing with ErrBeneficiaryAlreadyExists if the account
+	// already saved the same destination
+	CreateBeneficiary(beneficiary *Beneficiary) error
+
+	// GetBeneficiary gets a beneficiary by ID
+	GetBeneficiary(id string) (*Beneficiary, error)
+
+	// GetBeneficiaries gets the beneficiaries saved by an account, oldest first
+	GetBeneficiaries(accountID string) ([]*Beneficiary, error)
+
+	// DeleteBeneficiary deletes a beneficiary by ID
+	DeleteBeneficiary(id string) error
+}
@@ -0,0 +1,102 @@
+package beneficiary
+
+import (
+	"github.com/google/uuid"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// DestinationType is the type that represents where a beneficiary receives money
+type DestinationType string
+
+const (
+	// DestinationInternalAccount is an account held with us
+	DestinationInternalAccount DestinationType = "internal_account"
+
+	// DestinationBankAccount is an account at another bank, identified by a bank code and an account number
+	DestinationBankAccount DestinationType = "bank_account"
+
+	// DestinationMobileWallet is a mobile money wallet, identified by its phone number
+	DestinationMobileWallet DestinationType = "mobile_wallet"
+)
+
+// IsValid reports whether the destination type is known
+func (t DestinationType) IsValid() bool {
+	switch t {
+	case DestinationInternalAccount, DestinationBankAccount, DestinationMobileWallet:
+		return true
+	}
+	return false
+}
+
+// Beneficiary is the entity that represents a payee saved by an account
+type Beneficiary struct {
+	ID        string `json:"id"`
+	AccountID string `json:"account_id"`
+	Nickname  string `json:"nickname"`
+
+	DestinationType DestinationType `json:"destination_type"`
+
+	// Destination is the account ID, bank account number or wallet number of the payee
+	Destination string `json:"destination"`
+	BankCode    string `json:"bank_code,omitempty"`
+
+	// Name is the holder's name as confirmed by the destination when the beneficiary was saved
+	Name string `json:"name"`
+
+	// CoolingOffUntil is when the beneficiary may start receiving large amounts
+	CoolingOffUntil time.Time `json:"cooling_off_until"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// NewBeneficiary creates a new beneficiary whose cooling-off period starts now
+func NewBeneficiary(accountID, nickname string, destinationType DestinationType, destination, bankCode, name string, coolingOff time.Duration) *Beneficiary {
+	now := time.Now().UTC()
+	return &Beneficiary{
+		ID:              "ben_" + uuid.NewString(),
+		AccountID:       accountID,
+		Nickname:        nickname,
+		DestinationType: destinationType,
+		Destination:     destination,
+		BankCode:        bankCode,
+		Name:            name,
+		CoolingOffUntil: now.Add(coolingOff),
+		CreatedAt:       now,
+	}
+}
+
+// IsCoolingOff reports whether the beneficiary is still in its cooling-off period at the given time
+func (b *Beneficiary) IsCoolingOff(now time.Time) bool {
+	return now.Before(b.CoolingOffUntil)
+}
+
+// NamesMatch reports whether the name given for a beneficiary matches the name held by the destination.
+// Case, punctuation and the order of the names are ignored, and the destination may hold extra (middle) names
+// as long as at least two of the given names are confirmed.
+func NamesMatch(given, held string) bool {
+	givenNames, heldNames := nameParts(given), nameParts(held)
+	if len(givenNames) == 0 || len(heldNames) == 0 {
+		return false
+	}
+
+	remaining := make(map[string]int, len(heldNames))
+	for _, name := range heldNames {
+		remaining[name]++
+	}
+	for _, name := range givenNames {
+		if remaining[name] == 0 {
+			return false
+		}
+		remaining[name]--
+	}
+
+	return len(givenNames) == len(heldNames) || len(givenNames) >= 2
+}
+
+// nameParts splits a name into lower case words without punctuation
+func nameParts(name string) []string {
+	return strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
@@ -0,0 +1,16 @@
+package beneficiary
+
+// Repository is the beneficiary repository interface
+type Repository interface {
+	// Create saves a new beneficiary.
+	Create(beneficiary *Beneficiary) error
+
+	// Find gets a beneficiary by ID.
+	Find(id string) (*Beneficiary, error)
+
+	// FindByAccount returns the beneficiaries saved by an account, oldest first.
+	FindByAccount(accountID string) ([]*Beneficiary, error)
+
+	// Delete removes a beneficiary.
+	Delete(id string) error
+}
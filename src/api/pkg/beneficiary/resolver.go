@@ -0,0 +1,27 @@
+package beneficiary
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// ErrDestinationNotFound is the error returned when the destination does not exist
+	ErrDestinationNotFound = errors.New("destination not found. Please check the account details and try again")
+
+	// ErrNameMismatch is the error returned when the given name does not match the name held by the destination
+	ErrNameMismatch = errors.New("the name does not match the holder of the destination")
+
+	// ErrUnsupportedDestination is the error returned when names cannot be confirmed for a destination type
+	ErrUnsupportedDestination = errors.New("this destination type is not supported")
+
+	// ErrResolverUnavailable is the error returned when the destination cannot be reached to confirm the name
+	ErrResolverUnavailable = errors.New("unable to confirm the destination at the moment. Please try again later")
+)
+
+// NameResolver is the interface that wraps the lookup of the holder's name at a destination.
+type NameResolver interface {
+	// ResolveName returns the name of the holder of the destination (bankCode is only set for bank accounts).
+	// It fails with ErrDestinationNotFound when the destination does not exist.
+	ResolveName(ctx context.Context, destination, bankCode string) (string, error)
+}
@@ -0,0 +1,171 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/quabynah-bilson/quantia/pkg/beneficiary"
+	"log"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidBeneficiary is the error returned when a beneficiary's details are invalid.
+	ErrInvalidBeneficiary = errors.New("invalid beneficiary. Please check the nickname, destination and name")
+
+	// ErrBeneficiaryCoolingOff is the error returned when a new beneficiary is sent a large amount.
+	ErrBeneficiaryCoolingOff = errors.New("the beneficiary was added recently and cannot receive large amounts yet")
+)
+
+const (
+	// resolverTimeout bounds the confirmation of a beneficiary's name at the destination.
+	resolverTimeout = 10 * time.Second
+
+	// maxNicknameLength is the longest nickname a beneficiary can be saved with.
+	maxNicknameLength = 50
+)
+
+// BeneficiaryConfig configures the protection of newly added beneficiaries.
+type BeneficiaryConfig struct {
+	// CoolingOff is how long a new beneficiary can only receive amounts below LargeAmount
+	CoolingOff time.Duration
+
+	// LargeAmount is the smallest amount refused during the cooling-off period
+	LargeAmount float32
+}
+
+// BeneficiaryUseCase is the beneficiary use case. It contains the necessary repositories to manage the payees
+// saved by accounts and the resolvers used to confirm them.
+type BeneficiaryUseCase struct {
+	beneficiaryRepo beneficiary.Repository
+	resolvers       map[beneficiary.DestinationType]beneficiary.NameResolver
+	config          BeneficiaryConfig
+
+	// screening checks new beneficiaries against the sanctions lists. Beneficiaries are not screened when it is nil.
+	screening *ScreeningUseCase
+}
+
+// NewBeneficiaryUseCase creates a new beneficiary use case. Destination types without a resolver cannot be saved.
+func NewBeneficiaryUseCase(beneficiaryRepo beneficiary.Repository, resolvers map[beneficiary.DestinationType]beneficiary.NameResolver, config BeneficiaryConfig) *BeneficiaryUseCase {
+	return &BeneficiaryUseCase{
+		beneficiaryRepo: beneficiaryRepo,
+		resolvers:       resolvers,
+		config:          config,
+	}
+}
+
+// SetScreening makes new beneficiaries go through sanctions screening.
+func (uc *BeneficiaryUseCase) SetScreening(screening *ScreeningUseCase) {
+	uc.screening = screening
+}
+
+// AddBeneficiary saves a payee for the account once the given name is confirmed by the destination.
+// The beneficiary starts its cooling-off period.
+func (uc *BeneficiaryUseCase) AddBeneficiary(accountID, nickname string, destinationType beneficiary.DestinationType, destination, bankCode, name string) (*beneficiary.Beneficiary, error) {
+	nickname, destination, bankCode = strings.TrimSpace(nickname), strings.TrimSpace(destination), strings.TrimSpace(bankCode)
+	if accountID == "" || nickname == "" || len(nickname) > maxNicknameLength || destination == "" || strings.TrimSpace(name) == "" {
+		return nil, ErrInvalidBeneficiary
+	}
+
+	switch destinationType {
+	case beneficiary.DestinationInternalAccount:
+		if destination == accountID {
+			return nil, ErrInvalidBeneficiary
+		}
+	case beneficiary.DestinationBankAccount:
+		if bankCode == "" {
+			return nil, ErrInvalidBeneficiary
+		}
+	case beneficiary.DestinationMobileWallet:
+	default:
+		return nil, ErrInvalidBeneficiary
+	}
+
+	if destinationType != beneficiary.DestinationBankAccount {
+		bankCode = ""
+	}
+
+	resolver, ok := uc.resolvers[destinationType]
+	if !ok {
+		return nil, beneficiary.ErrUnsupportedDestination
+	}
+
+	// confirm the payee with the destination before anything is saved
+	ctx, cancel := context.WithTimeout(context.Background(), resolverTimeout)
+	defer cancel()
+
+	heldName, err := resolver.ResolveName(ctx, destination, bankCode)
+	if err != nil {
+		log.Printf("error resolving %s destination: %v", destinationType, err)
+		return nil, err
+	}
+
+	if !beneficiary.NamesMatch(name, heldName) {
+		return nil, beneficiary.ErrNameMismatch
+	}
+
+	b := beneficiary.NewBeneficiary(accountID, nickname, destinationType, destination, bankCode, heldName, uc.config.CoolingOff)
+	if err = uc.beneficiaryRepo.Create(b); err != nil {
+		log.Printf("error saving beneficiary: %v", err)
+		return nil, err
+	}
+
+	// a beneficiary with sanctions hits is kept, but cannot receive money until the hits are reviewed
+	if uc.screening != nil {
+		if _, err = uc.screening.ScreenBeneficiary(b); err != nil {
+			// an unscreened beneficiary could receive money, so it is not kept
+			if deleteErr := uc.beneficiaryRepo.Delete(b.ID); deleteErr != nil {
+				log.Printf("error deleting unscreened beneficiary %s: %v", b.ID, deleteErr)
+			}
+			return nil, err
+		}
+	}
+
+	return b, nil
+}
+
+// GetBeneficiaries returns the beneficiaries saved by the account.
+func (uc *BeneficiaryUseCase) GetBeneficiaries(accountID string) ([]*beneficiary.Beneficiary, error) {
+	return uc.beneficiaryRepo.FindByAccount(accountID)
+}
+
+// GetBeneficiary returns a beneficiary saved by the account.
+func (uc *BeneficiaryUseCase) GetBeneficiary(accountID, id string) (*beneficiary.Beneficiary, error) {
+	b, err := uc.beneficiaryRepo.Find(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// beneficiaries of other accounts are not disclosed
+	if b.AccountID != accountID {
+		return nil, beneficiary.ErrBeneficiaryNotFound
+	}
+
+	return b, nil
+}
+
+// RemoveBeneficiary deletes a beneficiary saved by the account.
+func (uc *BeneficiaryUseCase) RemoveBeneficiary(accountID, id string) error {
+	if _, err := uc.GetBeneficiary(accountID, id); err != nil {
+		return err
+	}
+
+	return uc.beneficiaryRepo.Delete(id)
+}
+
+// Authorize returns the account's beneficiary if it may receive the amount now. Large amounts are refused
+// until the beneficiary's cooling-off period is over.
+func (uc *BeneficiaryUseCase) Authorize(accountID, id string, amount float32) (*beneficiary.Beneficiary, error) {
+	b, err := uc.GetBeneficiary(accountID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if uc.config.LargeAmount > 0 && amount >= uc.config.LargeAmount && b.IsCoolingOff(time.Now()) {
+		return nil, fmt.Errorf("%w. Amounts of %.2f or more can be sent from %s",
+			ErrBeneficiaryCoolingOff, uc.config.LargeAmount, b.CoolingOffUntil.Format(time.RFC3339))
+	}
+
+	return b, nil
+}
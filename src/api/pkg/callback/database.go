@@ -0,0 +1,30 @@
+package callback
+
+import "errors"
+
+var (
+	// ErrCallbackNotFound is the error returned when a callback does not exist
+	ErrCallbackNotFound = errors.New("callback not found")
+
+	// ErrFailedToSaveCallback is the error returned when a callback cannot be stored
+	ErrFailedToSaveCallback = errors.New("failed to save callback. Please try again")
+
+	// ErrDuplicateCallback is the error returned when an event has already been received from the provider
+	ErrDuplicateCallback = errors.New("callback already received")
+)
+
+// Database is the interface that wraps the basic callback database operations.
+type Database interface {
+	// SaveCallback creates or replaces a callback
+	SaveCallback(callback *Callback) error
+
+	// GetCallback gets a callback by ID
+	GetCallback(id string) (*Callback, error)
+
+	// ClaimEvent records that an event of the provider is being handled by the callback, failing with
+	// ErrDuplicateCallback if another callback already claimed it
+	ClaimEvent(provider, eventID, callbackID string) error
+
+	// ReleaseEvent gives up the claim on an event, so that the provider's next delivery of it is handled
+	ReleaseEvent(provider, eventID string) error
+}
@@ -0,0 +1,65 @@
+package callback
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/google/uuid"
+	"time"
+)
+
+// Status is the type that represents how far a received callback was processed
+type Status string
+
+const (
+	// StatusReceived is the status of a verified callback that has been stored but not yet applied
+	StatusReceived Status = "received"
+
+	// StatusProcessed is the status of a callback applied to the payment, refund or payout it reports on
+	StatusProcessed Status = "processed"
+
+	// StatusFailed is the status of a callback that could not be understood or applied. Callbacks that could
+	// not be applied are processed again when the provider retries them.
+	StatusFailed Status = "failed"
+)
+
+// Callback is an asynchronous result received from a payment provider, kept with its raw payload
+type Callback struct {
+	ID       string `json:"id"`
+	Provider string `json:"provider"`
+
+	// EventID is the provider's ID for the callback, used to ignore deliveries of the same event
+	EventID string `json:"event_id"`
+
+	// Payload is the raw body, exactly as received
+	Payload string `json:"payload"`
+	Status  Status `json:"status"`
+
+	// Reference is our ID for the payment, refund or payout the callback reports on
+	Reference string `json:"reference,omitempty"`
+
+	// Result is the provider status reported, and TransactionStatus the status of the payment once it was applied
+	Result            string    `json:"result,omitempty"`
+	TransactionStatus string    `json:"transaction_status,omitempty"`
+	Error             string    `json:"error,omitempty"`
+	ReceivedAt        time.Time `json:"received_at"`
+	ProcessedAt       time.Time `json:"processed_at,omitempty"`
+}
+
+// NewCallback creates a received callback from the provider
+func NewCallback(provider, eventID string, payload []byte) *Callback {
+	return &Callback{
+		ID:         "cbk_" + uuid.NewString(),
+		Provider:   provider,
+		EventID:    eventID,
+		Payload:    string(payload),
+		Status:     StatusReceived,
+		ReceivedAt: time.Now().UTC(),
+	}
+}
+
+// PayloadEventID derives an event ID from a payload, for providers that do not send one. Retries of the
+// same callback carry the same payload and so the same ID.
+func PayloadEventID(payload []byte) string {
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
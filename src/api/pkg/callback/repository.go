@@ -0,0 +1,16 @@
+package callback
+
+// Repository is the callback repository interface
+type Repository interface {
+	// Save creates or replaces a callback.
+	Save(callback *Callback) error
+
+	// Find gets a callback by ID.
+	Find(id string) (*Callback, error)
+
+	// Claim records that an event of the provider is being handled by the callback.
+	Claim(provider, eventID, callbackID string) error
+
+	// Release gives up the claim on an event.
+	Release(provider, eventID string) error
+}
@@ -0,0 +1,52 @@
+package callback
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// ErrInvalidSignature is the error returned when a callback is not signed by the provider it claims to come from
+var ErrInvalidSignature = errors.New("invalid callback signature")
+
+// Verifier is the interface implemented by the signature schemes of providers.
+type Verifier interface {
+	// Verify checks that the body was signed by the provider, failing with ErrInvalidSignature otherwise
+	Verify(header http.Header, body []byte) error
+}
+
+// HMACVerifier verifies callbacks carrying the hex HMAC-SHA256 of their body, keyed with a secret shared
+// with the provider. The signature may be prefixed with "sha256=".
+type HMACVerifier struct {
+	// Header is the name of the header carrying the signature
+	Header string
+	Secret []byte
+}
+
+// Verify checks the signature of the body in constant time.
+func (v *HMACVerifier) Verify(header http.Header, body []byte) error {
+	if len(v.Secret) == 0 {
+		return ErrInvalidSignature
+	}
+
+	signature, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(header.Get(v.Header)), "sha256="))
+	if err != nil || len(signature) == 0 {
+		return ErrInvalidSignature
+	}
+
+	if !hmac.Equal(signature, Sign(v.Secret, body)) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// Sign returns the HMAC-SHA256 of the body keyed with the secret
+func Sign(secret, body []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,129 @@
+package pkg
+
+import (
+	"errors"
+	"github.com/quabynah-bilson/quantia/pkg/callback"
+	"github.com/quabynah-bilson/quantia/pkg/payment"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ErrUnknownCallbackProvider is the error returned when a callback names a provider that is not set up to send them.
+var ErrUnknownCallbackProvider = errors.New("unknown callback provider. Please check the callback URL")
+
+// CallbackProvider is a payment provider that reports results through the inbound webhook endpoint
+type CallbackProvider struct {
+	// Name identifies the provider in the callback URL
+	Name     string
+	Parser   payment.CallbackParser
+	Verifier callback.Verifier
+
+	// EventIDHeader is the header carrying the provider's event ID. When it is not set, or missing from a
+	// callback, the event ID is derived from the payload.
+	EventIDHeader string
+}
+
+// CallbackUseCase is the inbound webhook use case. It verifies the callbacks of payment providers, stores
+// them as received and applies each event once to the payment, refund or payout it reports on.
+type CallbackUseCase struct {
+	callbackRepo callback.Repository
+	payments     *PaymentUseCase
+	providers    map[string]CallbackProvider
+}
+
+// NewCallbackUseCase creates a new callback use case. Providers must be registered before callbacks arrive.
+func NewCallbackUseCase(callbackRepo callback.Repository, payments *PaymentUseCase) *CallbackUseCase {
+	return &CallbackUseCase{
+		callbackRepo: callbackRepo,
+		payments:     payments,
+		providers:    make(map[string]CallbackProvider),
+	}
+}
+
+// Register accepts the callbacks of a provider.
+func (uc *CallbackUseCase) Register(provider CallbackProvider) {
+	uc.providers[provider.Name] = provider
+}
+
+// Receive handles a callback from the named provider. Callbacks that are not signed by the provider are
+// refused and not stored. Events already received are refused with ErrDuplicateCallback, unless they failed
+// to apply, in which case the provider's retry applies them again.
+func (uc *CallbackUseCase) Receive(providerName string, header http.Header, body []byte) (*callback.Callback, error) {
+	provider, ok := uc.providers[providerName]
+	if !ok {
+		return nil, ErrUnknownCallbackProvider
+	}
+
+	if err := provider.Verifier.Verify(header, body); err != nil {
+		log.Printf("error verifying %s callback: %v", providerName, err)
+		return nil, err
+	}
+
+	eventID := ""
+	if provider.EventIDHeader != "" {
+		eventID = strings.TrimSpace(header.Get(provider.EventIDHeader))
+	}
+	if eventID == "" {
+		eventID = callback.PayloadEventID(body)
+	}
+
+	cb := callback.NewCallback(providerName, eventID, body)
+	if err := uc.callbackRepo.Claim(providerName, eventID, cb.ID); err != nil {
+		return nil, err
+	}
+
+	if err := uc.callbackRepo.Save(cb); err != nil {
+		log.Printf("error saving callback %s: %v", cb.ID, err)
+		uc.release(cb)
+		return nil, err
+	}
+
+	result, err := provider.Parser.ParseCallback(body)
+	if err != nil {
+		// the payload will not read any better when it is delivered again, so the event stays claimed
+		log.Printf("error parsing %s callback %s: %v", providerName, cb.ID, err)
+		return cb, uc.finish(cb, err)
+	}
+	cb.Reference, cb.Result = result.Reference, string(result.Status)
+
+	// a declined payment is a result like any other
+	transaction, err := uc.payments.HandleProviderResult(result)
+	if err != nil && !errors.Is(err, payment.ErrPaymentDeclined) {
+		log.Printf("error applying callback %s to %s: %v", cb.ID, result.Reference, err)
+		uc.release(cb)
+		return cb, uc.finish(cb, err)
+	}
+	if transaction != nil {
+		cb.TransactionStatus = string(transaction.Status)
+	}
+
+	return cb, uc.finish(cb, nil)
+}
+
+// GetCallback gets a received callback by ID.
+func (uc *CallbackUseCase) GetCallback(id string) (*callback.Callback, error) {
+	return uc.callbackRepo.Find(id)
+}
+
+// finish records whether a callback was applied and returns the reason it was not.
+func (uc *CallbackUseCase) finish(cb *callback.Callback, reason error) error {
+	cb.Status, cb.ProcessedAt = callback.StatusProcessed, time.Now().UTC()
+	if reason != nil {
+		cb.Status, cb.Error = callback.StatusFailed, reason.Error()
+	}
+
+	if err := uc.callbackRepo.Save(cb); err != nil {
+		log.Printf("error saving callback %s: %v", cb.ID, err)
+	}
+
+	return reason
+}
+
+// release lets the provider's next delivery of a callback's event be handled.
+func (uc *CallbackUseCase) release(cb *callback.Callback) {
+	if err := uc.callbackRepo.Release(cb.Provider, cb.EventID); err != nil {
+		log.Printf("error releasing event %s of callback %s: %v", cb.EventID, cb.ID, err)
+	}
+}
@@ -0,0 +1,47 @@
+package card
+
+import "errors"
+
+var (
+	// ErrCardNotFound is the error returned when a card does not exist
+	ErrCardNotFound = errors.New("card not found")
+
+	// ErrFailedToSaveCard is the error returned when a card cannot be stored
+	ErrFailedToSaveCard = errors.New("failed to save card. Please try again")
+
+	// ErrAuthorizationNotFound is the error returned when an authorization does not exist
+	ErrAuthorizationNotFound = errors.New("authorization not found")
+
+	// ErrFailedToSaveAuthorization is the error returned when an authorization cannot be stored
+	ErrFailedToSaveAuthorization = errors.New("failed to save authorization. Please try again")
+)
+
+// Database is the interface that wraps the basic card database operations.
+type Database interface {
+	// SaveCard creates or replaces a card. Cards are indexed by their account and their token.
+	SaveCard(card *Card) error
+
+	// GetCard gets a card by ID
+	GetCard(id string) (*Card, error)
+
+	// GetCardByToken gets the card of a vault token
+	GetCardByToken(token string) (*Card, error)
+
+	// GetAccountCards gets the cards of an account, oldest first
+	GetAccountCards(accountID string) ([]*Card, error)
+
+	// SaveAuthorization creates an authorization, indexed by its card
+	SaveAuthorization(authorization *Authorization) error
+
+	// GetCardAuthorizations gets the authorizations of a card, newest first
+	GetCardAuthorizations(cardID string) ([]*Authorization, error)
+
+	// SaveVaultEntry stores a vault entry unless its fingerprint is already stored
+	SaveVaultEntry(entry *VaultEntry) error
+
+	// GetVaultEntry gets the vault entry of a token
+	GetVaultEntry(token string) (*VaultEntry, error)
+
+	// GetVaultToken gets the token of a PAN fingerprint
+	GetVaultToken(fingerprint string) (string, error)
+}
@@ -0,0 +1,158 @@
+package card
+
+import (
+	"github.com/google/uuid"
+	"github.com/quabynah-bilson/quantia/pkg/limit"
+	"strings"
+	"time"
+)
+
+// Status is the type that represents the status of a card
+type Status string
+
+const (
+	// StatusActive is the status of a card that can be used
+	StatusActive Status = "active"
+
+	// StatusFrozen is the status of a card whose authorizations are declined until it is unfrozen
+	StatusFrozen Status = "frozen"
+)
+
+// Controls is the entity that represents the spend controls of a card. The limits are counted per card, on top
+// of the balance of its account; zero limits are unlimited.
+type Controls struct {
+	limit.Limits
+
+	// BlockedCategories are the merchant category codes (e.g. 7995 for gambling) the card cannot be used at
+	BlockedCategories []string `json:"blocked_categories,omitempty"`
+}
+
+// IsValid reports whether the controls have no negative limit
+func (c Controls) IsValid() bool {
+	return c.PerTransaction >= 0 && c.DailyAmount >= 0 && c.DailyCount >= 0 && c.MonthlyAmount >= 0 && c.MonthlyCount >= 0
+}
+
+// Blocks reports whether the card cannot be used at merchants of the category
+func (c Controls) Blocks(category string) bool {
+	category = strings.TrimSpace(category)
+	for _, blocked := range c.BlockedCategories {
+		if strings.EqualFold(strings.TrimSpace(blocked), category) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Card is the entity that represents a virtual debit card linked to an account. The PAN and CVV are only held
+// by the vault; the card knows them by their token.
+type Card struct {
+	ID        string `json:"id"`
+	AccountID string `json:"account_id"`
+
+	// Token stands for the PAN and CVV in the vault
+	Token       string   `json:"token"`
+	Last4       string   `json:"last4"`
+	ExpiryMonth int      `json:"expiry_month"`
+	ExpiryYear  int      `json:"expiry_year"`
+	Status      Status   `json:"status"`
+	Controls    Controls `json:"controls"`
+
+	// FailedAttempts counts the charges declined in a row for a wrong CVV or expiry date
+	FailedAttempts int       `json:"failed_attempts,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// NewCard creates a new active card of the account for the tokenized PAN, valid until the end of the expiry month
+func NewCard(accountID, token, last4 string, expiry time.Time, controls Controls) *Card {
+	now := time.Now().UTC()
+	return &Card{
+		ID:          "card_" + uuid.NewString(),
+		AccountID:   accountID,
+		Token:       token,
+		Last4:       last4,
+		ExpiryMonth: int(expiry.Month()),
+		ExpiryYear:  expiry.Year(),
+		Status:      StatusActive,
+		Controls:    controls,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// IsExpired reports whether the card's expiry month is over at the given time
+func (c *Card) IsExpired(now time.Time) bool {
+	expiresAt := time.Date(c.ExpiryYear, time.Month(c.ExpiryMonth)+1, 1, 0, 0, 0, 0, time.UTC)
+	return !now.Before(expiresAt)
+}
+
+// LimitSubject returns the subject the card's spend is counted under by the limit repository
+func (c *Card) LimitSubject() string {
+	return "card:" + c.ID
+}
+
+// AuthorizationStatus is the type that represents the outcome of an authorization
+type AuthorizationStatus string
+
+const (
+	// AuthorizationApproved is the status of an authorization whose amount was debited from the account
+	AuthorizationApproved AuthorizationStatus = "approved"
+
+	// AuthorizationDeclined is the status of an authorization refused without debiting the account
+	AuthorizationDeclined AuthorizationStatus = "declined"
+)
+
+// AuthorizationRequest is the entity that represents a merchant's request to charge a card, as sent by the
+// card network
+type AuthorizationRequest struct {
+	PAN         string  `json:"pan"`
+	CVV         string  `json:"cvv"`
+	ExpiryMonth int     `json:"expiry_month"`
+	ExpiryYear  int     `json:"expiry_year"`
+	Amount      float32 `json:"amount"`
+	Merchant    string  `json:"merchant"`
+
+	// Category is the merchant category code of the merchant
+	Category string `json:"category,omitempty"`
+
+	// Reference is the network's reference of the request
+	Reference string `json:"reference,omitempty"`
+}
+
+// Authorization is the entity that represents the outcome of a charge on a card
+type Authorization struct {
+	ID            string              `json:"id"`
+	CardID        string              `json:"card_id"`
+	AccountID     string              `json:"account_id"`
+	Amount        float32             `json:"amount"`
+	Merchant      string              `json:"merchant"`
+	Category      string              `json:"category,omitempty"`
+	Reference     string              `json:"reference,omitempty"`
+	Status        AuthorizationStatus `json:"status"`
+	DeclineReason string              `json:"decline_reason,omitempty"`
+
+	// HoldID is the ledger hold through which the amount was debited from the account
+	HoldID    string    `json:"hold_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewAuthorization creates a new approved authorization of the request on the card
+func NewAuthorization(c *Card, request *AuthorizationRequest) *Authorization {
+	return &Authorization{
+		ID:        "auth_" + uuid.NewString(),
+		CardID:    c.ID,
+		AccountID: c.AccountID,
+		Amount:    request.Amount,
+		Merchant:  strings.TrimSpace(request.Merchant),
+		Category:  strings.TrimSpace(request.Category),
+		Reference: request.Reference,
+		Status:    AuthorizationApproved,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+// Decline marks the authorization as declined for the reason
+func (a *Authorization) Decline(reason error) {
+	a.Status, a.DeclineReason = AuthorizationDeclined, reason.Error()
+}
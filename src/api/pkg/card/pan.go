@@ -0,0 +1,155 @@
+package card
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidBINRange is the error returned when a BIN range cannot produce card numbers
+var ErrInvalidBINRange = errors.New("invalid BIN range")
+
+const (
+	// minPANLength and maxPANLength are the lengths of card numbers allowed by ISO/IEC 7812
+	minPANLength = 12
+	maxPANLength = 19
+
+	// cvvLength is the number of digits of a CVV
+	cvvLength = 3
+)
+
+// BINRange is the entity that represents the bank identification numbers cards are issued under, from Low to
+// High inclusive. Both bounds have the same number of digits (6 or 8).
+type BINRange struct {
+	Low  string `json:"low"`
+	High string `json:"high"`
+}
+
+// ParseBINRange parses a BIN range written as low-high, or a single BIN
+func ParseBINRange(raw string) (BINRange, error) {
+	low, high, found := strings.Cut(strings.TrimSpace(raw), "-")
+	if !found {
+		high = low
+	}
+
+	bins := BINRange{Low: strings.TrimSpace(low), High: strings.TrimSpace(high)}
+	if !bins.IsValid() {
+		return BINRange{}, ErrInvalidBINRange
+	}
+
+	return bins, nil
+}
+
+// IsValid reports whether the bounds are numbers of 6 or 8 digits, the lower not above the higher
+func (b BINRange) IsValid() bool {
+	if len(b.Low) != len(b.High) || (len(b.Low) != 6 && len(b.Low) != 8) || !isDigits(b.Low) || !isDigits(b.High) {
+		return false
+	}
+
+	return b.Low <= b.High
+}
+
+// Contains reports whether the card number was issued under the range
+func (b BINRange) Contains(pan string) bool {
+	if len(pan) < len(b.Low) {
+		return false
+	}
+
+	bin := pan[:len(b.Low)]
+	return b.Low <= bin && bin <= b.High
+}
+
+// GeneratePAN generates a random card number of the given length under a BIN of the range, with a valid Luhn
+// check digit
+func GeneratePAN(bins BINRange, length int) (string, error) {
+	if !bins.IsValid() || length < minPANLength || length > maxPANLength || length <= len(bins.Low) {
+		return "", ErrInvalidBINRange
+	}
+
+	low, _ := strconv.ParseInt(bins.Low, 10, 64)
+	high, _ := strconv.ParseInt(bins.High, 10, 64)
+	offset, err := rand.Int(rand.Reader, big.NewInt(high-low+1))
+	if err != nil {
+		return "", err
+	}
+
+	bin := strconv.FormatInt(low+offset.Int64(), 10)
+	account, err := randomDigits(length - len(bin) - 1)
+	if err != nil {
+		return "", err
+	}
+
+	payload := bin + account
+	return payload + string(luhnCheckDigit(payload)), nil
+}
+
+// GenerateCVV generates a random card verification value
+func GenerateCVV() (string, error) {
+	return randomDigits(cvvLength)
+}
+
+// ExpiryAfter returns the expiry month of a card issued now and valid for the given number of years
+func ExpiryAfter(now time.Time, years int) time.Time {
+	year, month, _ := now.UTC().Date()
+	return time.Date(year+years, month, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// LuhnValid reports whether the card number is made of digits and passes the Luhn check
+func LuhnValid(pan string) bool {
+	if len(pan) < 2 || !isDigits(pan) {
+		return false
+	}
+
+	return luhnCheckDigit(pan[:len(pan)-1]) == pan[len(pan)-1]
+}
+
+// luhnCheckDigit returns the digit that makes the payload pass the Luhn check. The payload must be digits.
+func luhnCheckDigit(payload string) byte {
+	sum := 0
+	for i := len(payload) - 1; i >= 0; i-- {
+		digit := int(payload[i] - '0')
+
+		// double every other digit, starting with the one next to the check digit
+		if (len(payload)-1-i)%2 == 0 {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+	}
+
+	return byte('0' + (10-sum%10)%10)
+}
+
+// randomDigits returns n digits from the cryptographic random source
+func randomDigits(n int) (string, error) {
+	digits := make([]byte, n)
+	for i := range digits {
+		digit, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		digits[i] = byte('0' + digit.Int64())
+	}
+
+	return string(digits), nil
+}
+
+// isDigits reports whether the value is only made of ASCII digits
+func isDigits(value string) bool {
+	if value == "" {
+		return false
+	}
+
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
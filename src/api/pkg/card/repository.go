@@ -0,0 +1,24 @@
+package card
+
+// Repository is the card repository interface. It also stores the entries of the vault.
+type Repository interface {
+	VaultStore
+
+	// Save creates or replaces a card.
+	Save(card *Card) error
+
+	// Find gets a card by ID.
+	Find(id string) (*Card, error)
+
+	// FindByToken gets the card of a vault token.
+	FindByToken(token string) (*Card, error)
+
+	// FindByAccount gets the cards of an account, oldest first.
+	FindByAccount(accountID string) ([]*Card, error)
+
+	// SaveAuthorization creates an authorization.
+	SaveAuthorization(authorization *Authorization) error
+
+	// Authorizations gets the authorizations of a card, newest first.
+	Authorizations(cardID string) ([]*Authorization, error)
+}
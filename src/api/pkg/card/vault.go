@@ -0,0 +1,154 @@
+package card
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"time"
+)
+
+var (
+	// ErrInvalidVaultKey is the error returned when the vault is given a key that is not 32 bytes long
+	ErrInvalidVaultKey = errors.New("invalid vault key. The key must be 32 bytes")
+
+	// ErrSecretNotFound is the error returned when a token is not in the vault
+	ErrSecretNotFound = errors.New("card details not found")
+
+	// ErrDuplicatePAN is the error returned when a card number is already in the vault
+	ErrDuplicatePAN = errors.New("the card number is already in the vault")
+
+	// ErrFailedToSaveSecret is the error returned when card details cannot be stored
+	ErrFailedToSaveSecret = errors.New("failed to save card details. Please try again")
+)
+
+// Secret is the sensitive data of a card. It only leaves the vault to be shown once when the card is issued
+// and to verify the details of authorizations.
+type Secret struct {
+	PAN string `json:"pan"`
+	CVV string `json:"cvv"`
+}
+
+// VaultEntry is the entity stored by the vault for a token. The secret is sealed with AES-256-GCM and the
+// fingerprint is a keyed hash of the PAN, so that the token of a card number can be found without decrypting.
+type VaultEntry struct {
+	Token       string    `json:"token"`
+	Fingerprint string    `json:"fingerprint"`
+	Ciphertext  []byte    `json:"ciphertext"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// VaultStore is the interface that wraps the storage of the vault's entries
+type VaultStore interface {
+	// SaveSecret stores a vault entry. It fails with ErrDuplicatePAN when the fingerprint is already stored.
+	SaveSecret(entry *VaultEntry) error
+
+	// FindSecret gets the vault entry of a token.
+	FindSecret(token string) (*VaultEntry, error)
+
+	// FindToken gets the token of the PAN with the given fingerprint.
+	FindToken(fingerprint string) (string, error)
+}
+
+// Vault is the tokenization vault. It stores card numbers encrypted and hands out tokens in their place, so
+// that the rest of the system never holds a PAN.
+type Vault struct {
+	store          VaultStore
+	aead           cipher.AEAD
+	fingerprintKey []byte
+}
+
+// NewVault creates a vault sealing its entries with keys derived from the 32 bytes master key
+func NewVault(store VaultStore, key []byte) (*Vault, error) {
+	if len(key) != 32 {
+		return nil, ErrInvalidVaultKey
+	}
+
+	block, err := aes.NewCipher(deriveKey(key, "encryption"))
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Vault{store: store, aead: aead, fingerprintKey: deriveKey(key, "fingerprint")}, nil
+}
+
+// Tokenize encrypts and stores the secret, returning the token that stands for it
+func (v *Vault) Tokenize(secret *Secret) (string, error) {
+	plaintext, err := json.Marshal(secret)
+	if err != nil {
+		return "", ErrFailedToSaveSecret
+	}
+
+	nonce := make([]byte, v.aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	// the token is authenticated with the ciphertext, so an entry cannot be swapped for another token's
+	entry := &VaultEntry{
+		Token:       "ctok_" + uuid.NewString(),
+		Fingerprint: v.fingerprint(secret.PAN),
+		CreatedAt:   time.Now().UTC(),
+	}
+	entry.Ciphertext = v.aead.Seal(nonce, nonce, plaintext, []byte(entry.Token))
+
+	if err = v.store.SaveSecret(entry); err != nil {
+		return "", err
+	}
+
+	return entry.Token, nil
+}
+
+// Detokenize decrypts the secret of a token
+func (v *Vault) Detokenize(token string) (*Secret, error) {
+	entry, err := v.store.FindSecret(token)
+	if err != nil {
+		return nil, err
+	}
+
+	size := v.aead.NonceSize()
+	if len(entry.Ciphertext) < size {
+		return nil, ErrSecretNotFound
+	}
+
+	plaintext, err := v.aead.Open(nil, entry.Ciphertext[:size], entry.Ciphertext[size:], []byte(entry.Token))
+	if err != nil {
+		return nil, ErrSecretNotFound
+	}
+
+	var secret Secret
+	if err = json.Unmarshal(plaintext, &secret); err != nil {
+		return nil, ErrSecretNotFound
+	}
+
+	return &secret, nil
+}
+
+// Lookup returns the token of a card number
+func (v *Vault) Lookup(pan string) (string, error) {
+	return v.store.FindToken(v.fingerprint(pan))
+}
+
+// fingerprint returns the keyed hash of a card number
+func (v *Vault) fingerprint(pan string) string {
+	mac := hmac.New(sha256.New, v.fingerprintKey)
+	mac.Write([]byte(pan))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// deriveKey derives the key used for one purpose from the master key
+func deriveKey(key []byte, purpose string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(purpose))
+	return mac.Sum(nil)
+}
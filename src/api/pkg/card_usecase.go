@@ -0,0 +1,362 @@
+package pkg
+
+import (
+	"crypto/subtle"
+	"errors"
+	"github.com/quabynah-bilson/quantia/pkg/card"
+	"github.com/quabynah-bilson/quantia/pkg/ledger"
+	"github.com/quabynah-bilson/quantia/pkg/limit"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrInvalidCard is the error returned when a card is issued without an account or with negative spend controls.
+	ErrInvalidCard = errors.New("invalid card. Please check the account and the spend controls")
+
+	// ErrInvalidAuthorization is the error returned when an authorization request misses its merchant.
+	ErrInvalidAuthorization = errors.New("invalid authorization. Please check the card details, the amount and the merchant")
+
+	// ErrCardFrozen is the error returned when a frozen card is charged.
+	ErrCardFrozen = errors.New("the card is frozen")
+
+	// ErrCardExpired is the error returned when a card is charged after its expiry month.
+	ErrCardExpired = errors.New("the card has expired")
+
+	// ErrInvalidCardDetails is the error returned when the CVV or expiry date of a charge do not match the card.
+	ErrInvalidCardDetails = errors.New("invalid card details")
+
+	// ErrCategoryBlocked is the error returned when a card is charged at a merchant category its controls block.
+	ErrCategoryBlocked = errors.New("the card cannot be used at this type of merchant")
+)
+
+const (
+	// defaultCardBIN is the BIN cards are issued under when no range is configured
+	defaultCardBIN = "400000"
+
+	// defaultPANLength is the length of the card numbers when the configuration does not say
+	defaultPANLength = 16
+
+	// defaultCardValidityYears is how long cards are valid when the configuration does not say
+	defaultCardValidityYears = 3
+
+	// maxPANAttempts is the number of card numbers generated before issuing gives up on collisions
+	maxPANAttempts = 5
+
+	// cardHoldExpiry bounds the hold that reserves an authorization's amount until it is debited
+	cardHoldExpiry = time.Minute
+
+	// defaultMaxFailedAttempts is the number of charges in a row with wrong card details that freezes a card
+	// when the configuration does not say
+	defaultMaxFailedAttempts = 3
+)
+
+// CardConfig is the configuration of the card use case
+type CardConfig struct {
+	// BINs is the range of bank identification numbers assigned to us by the card network
+	BINs card.BINRange
+
+	// PANLength is the number of digits of the card numbers
+	PANLength int
+
+	// ValidityYears is how many years cards are valid after the month they are issued in
+	ValidityYears int
+
+	// MaxFailedAttempts is the number of charges in a row with a wrong CVV or expiry date after which the
+	// card is frozen, so that its details cannot be guessed
+	MaxFailedAttempts int
+}
+
+// IssuedCard is the view of a card returned when it is issued. It is the only time the PAN and CVV are shown.
+type IssuedCard struct {
+	Card *card.Card `json:"card"`
+	PAN  string     `json:"pan"`
+	CVV  string     `json:"cvv"`
+}
+
+// CardUseCase is the virtual card use case. It issues cards linked to accounts, keeping their numbers in the
+// vault, and authorizes charges against the card's status and spend controls before debiting the account.
+type CardUseCase struct {
+	cardRepo   card.Repository
+	ledgerRepo ledger.Repository
+
+	// limitRepo counts the authorizations of each card against its spend controls
+	limitRepo limit.Repository
+	vault     *card.Vault
+	config    CardConfig
+
+	// mu serializes the changes made to cards
+	mu sync.Mutex
+}
+
+// NewCardUseCase creates a new card use case.
+func NewCardUseCase(cardRepo card.Repository, ledgerRepo ledger.Repository, limitRepo limit.Repository, vault *card.Vault, config CardConfig) *CardUseCase {
+	if !config.BINs.IsValid() {
+		config.BINs = card.BINRange{Low: defaultCardBIN, High: defaultCardBIN}
+	}
+	if config.PANLength <= 0 {
+		config.PANLength = defaultPANLength
+	}
+	if config.ValidityYears <= 0 {
+		config.ValidityYears = defaultCardValidityYears
+	}
+	if config.MaxFailedAttempts <= 0 {
+		config.MaxFailedAttempts = defaultMaxFailedAttempts
+	}
+
+	return &CardUseCase{
+		cardRepo:   cardRepo,
+		ledgerRepo: ledgerRepo,
+		limitRepo:  limitRepo,
+		vault:      vault,
+		config:     config,
+	}
+}
+
+// Issue issues a new virtual card linked to the account. The PAN and CVV are stored in the vault and returned
+// this once; the card only keeps their token and the last four digits.
+func (uc *CardUseCase) Issue(accountID string, controls card.Controls) (*IssuedCard, error) {
+	if strings.TrimSpace(accountID) == "" || !controls.IsValid() {
+		return nil, ErrInvalidCard
+	}
+
+	cvv, err := card.GenerateCVV()
+	if err != nil {
+		log.Printf("error generating CVV: %v", err)
+		return nil, err
+	}
+
+	// a new number is drawn when the vault already holds the generated one
+	var pan, token string
+	for attempt := 0; attempt < maxPANAttempts; attempt++ {
+		if pan, err = card.GeneratePAN(uc.config.BINs, uc.config.PANLength); err != nil {
+			log.Printf("error generating PAN: %v", err)
+			return nil, err
+		}
+
+		if token, err = uc.vault.Tokenize(&card.Secret{PAN: pan, CVV: cvv}); !errors.Is(err, card.ErrDuplicatePAN) {
+			break
+		}
+	}
+	if err != nil {
+		log.Printf("error tokenizing PAN: %v", err)
+		return nil, err
+	}
+
+	c := card.NewCard(accountID, token, pan[len(pan)-4:], card.ExpiryAfter(time.Now(), uc.config.ValidityYears), controls)
+	if err = uc.cardRepo.Save(c); err != nil {
+		log.Printf("error saving card %s: %v", c.ID, err)
+		return nil, err
+	}
+
+	return &IssuedCard{Card: c, PAN: pan, CVV: cvv}, nil
+}
+
+// GetCard gets a card by ID.
+func (uc *CardUseCase) GetCard(id string) (*card.Card, error) {
+	return uc.cardRepo.Find(id)
+}
+
+// GetAccountCards gets the cards of an account, oldest first.
+func (uc *CardUseCase) GetAccountCards(accountID string) ([]*card.Card, error) {
+	return uc.cardRepo.FindByAccount(accountID)
+}
+
+// GetAuthorizations gets the authorizations of a card, newest first.
+func (uc *CardUseCase) GetAuthorizations(cardID string) ([]*card.Authorization, error) {
+	if _, err := uc.cardRepo.Find(cardID); err != nil {
+		return nil, err
+	}
+
+	return uc.cardRepo.Authorizations(cardID)
+}
+
+// Freeze declines the authorizations of a card until it is unfrozen.
+func (uc *CardUseCase) Freeze(id string) (*card.Card, error) {
+	return uc.update(id, func(c *card.Card) {
+		c.Status = card.StatusFrozen
+	})
+}
+
+// Unfreeze makes a frozen card usable again, with a new count of failed attempts.
+func (uc *CardUseCase) Unfreeze(id string) (*card.Card, error) {
+	return uc.update(id, func(c *card.Card) {
+		c.Status, c.FailedAttempts = card.StatusActive, 0
+	})
+}
+
+// SetControls replaces the spend controls of a card. Spend already counted this day and month still counts.
+func (uc *CardUseCase) SetControls(id string, controls card.Controls) (*card.Card, error) {
+	if !controls.IsValid() {
+		return nil, ErrInvalidCard
+	}
+
+	return uc.update(id, func(c *card.Card) {
+		c.Controls = controls
+	})
+}
+
+// Authorize charges a card for a merchant. The card is found from its number through the vault; it must be
+// active and unexpired, the CVV and expiry date must match, and the charge must fit within the card's spend
+// controls and the account's available balance. Approved charges are debited from the account at once.
+// Declined charges are recorded and returned with the reason of the decline. A card is frozen after
+// MaxFailedAttempts charges in a row with a wrong CVV or expiry date.
+func (uc *CardUseCase) Authorize(request *card.AuthorizationRequest) (*card.Authorization, error) {
+	if err := validateAmount(request.Amount); err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(request.Merchant) == "" {
+		return nil, ErrInvalidAuthorization
+	}
+
+	// numbers outside our range or failing the Luhn check cannot be ours
+	if !uc.config.BINs.Contains(request.PAN) || !card.LuhnValid(request.PAN) {
+		return nil, card.ErrCardNotFound
+	}
+
+	token, err := uc.vault.Lookup(request.PAN)
+	if err != nil {
+		return nil, card.ErrCardNotFound
+	}
+
+	c, err := uc.cardRepo.FindByToken(token)
+	if err != nil {
+		log.Printf("error finding card of token %s: %v", token, err)
+		return nil, err
+	}
+
+	a := card.NewAuthorization(c, request)
+	err = uc.check(c, token, request, a.CreatedAt)
+	uc.countAttempt(c, err)
+	if err != nil {
+		return uc.decline(a, err)
+	}
+
+	// count the charge against the card's spend controls before the account is debited
+	reservation, err := uc.limitRepo.Reserve(c.LimitSubject(), a.Amount, c.Controls.Limits, a.CreatedAt)
+	if err != nil {
+		return uc.decline(a, err)
+	}
+
+	if err = uc.debit(a); err != nil {
+		if releaseErr := uc.limitRepo.Release(reservation); releaseErr != nil {
+			log.Printf("error releasing spend of card %s: %v", c.ID, releaseErr)
+		}
+		return uc.decline(a, err)
+	}
+
+	// the account has been debited, so the approval stands even if it cannot be recorded
+	if err = uc.cardRepo.SaveAuthorization(a); err != nil {
+		log.Printf("error saving authorization %s: %v", a.ID, err)
+	}
+
+	return a, nil
+}
+
+// check returns the reason a charge on the card must be declined, or nil
+func (uc *CardUseCase) check(c *card.Card, token string, request *card.AuthorizationRequest, now time.Time) error {
+	if c.Status == card.StatusFrozen {
+		return ErrCardFrozen
+	}
+
+	if c.IsExpired(now) {
+		return ErrCardExpired
+	}
+
+	secret, err := uc.vault.Detokenize(token)
+	if err != nil {
+		log.Printf("error detokenizing card %s: %v", c.ID, err)
+		return err
+	}
+
+	validCVV := subtle.ConstantTimeCompare([]byte(secret.CVV), []byte(request.CVV)) == 1
+	if !validCVV || request.ExpiryMonth != c.ExpiryMonth || request.ExpiryYear != c.ExpiryYear {
+		return ErrInvalidCardDetails
+	}
+
+	if c.Controls.Blocks(request.Category) {
+		return ErrCategoryBlocked
+	}
+
+	return nil
+}
+
+// countAttempt counts a charge declined for wrong card details, freezing the card once there were
+// MaxFailedAttempts in a row, and starts the count again once the details are right
+func (uc *CardUseCase) countAttempt(c *card.Card, reason error) {
+	var change func(c *card.Card)
+	switch {
+	case errors.Is(reason, ErrInvalidCardDetails):
+		change = func(c *card.Card) {
+			c.FailedAttempts++
+			if c.FailedAttempts >= uc.config.MaxFailedAttempts && c.Status == card.StatusActive {
+				log.Printf("freezing card %s after %d charges with wrong details", c.ID, c.FailedAttempts)
+				c.Status = card.StatusFrozen
+			}
+		}
+	case (reason == nil || errors.Is(reason, ErrCategoryBlocked)) && c.FailedAttempts > 0:
+		change = func(c *card.Card) {
+			c.FailedAttempts = 0
+		}
+	default:
+		return
+	}
+
+	if _, err := uc.update(c.ID, change); err != nil {
+		log.Printf("error counting failed attempt of card %s: %v", c.ID, err)
+	}
+}
+
+// debit moves the authorized amount from the card's account to the card settlement account. The hold checks
+// the available balance and reserves the funds atomically before they are captured.
+func (uc *CardUseCase) debit(a *card.Authorization) error {
+	hold := ledger.NewHold(a.AccountID, ledger.CardSettlementAccountID, a.Amount, cardHoldExpiry)
+	if err := uc.ledgerRepo.Hold(hold); err != nil {
+		log.Printf("error placing hold for authorization %s: %v", a.ID, err)
+		return err
+	}
+	a.HoldID = hold.ID
+
+	if _, err := uc.ledgerRepo.Capture(hold.ID, a.Amount); err != nil {
+		log.Printf("error capturing hold %s for authorization %s: %v", hold.ID, a.ID, err)
+		if _, releaseErr := uc.ledgerRepo.Release(hold.ID, ledger.HoldStatusVoided); releaseErr != nil {
+			log.Printf("error releasing hold %s: %v", hold.ID, releaseErr)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// decline records the authorization as declined for the reason and returns it with the reason
+func (uc *CardUseCase) decline(a *card.Authorization, reason error) (*card.Authorization, error) {
+	a.Decline(reason)
+	if err := uc.cardRepo.SaveAuthorization(a); err != nil {
+		log.Printf("error saving authorization %s: %v", a.ID, err)
+	}
+
+	return a, reason
+}
+
+// update applies a change to a card and saves it
+func (uc *CardUseCase) update(id string, change func(c *card.Card)) (*card.Card, error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	c, err := uc.cardRepo.Find(id)
+	if err != nil {
+		return nil, err
+	}
+
+	change(c)
+	c.UpdatedAt = time.Now().UTC()
+	if err = uc.cardRepo.Save(c); err != nil {
+		log.Printf("error saving card %s: %v", c.ID, err)
+		return nil, err
+	}
+
+	return c, nil
+}
@@ -0,0 +1,31 @@
+package dispute
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrDisputeNotFound is the error returned when a dispute does not exist
+	ErrDisputeNotFound = errors.New("dispute not found")
+
+	// ErrFailedToSaveDispute is the error returned when a dispute cannot be stored
+	ErrFailedToSaveDispute = errors.New("failed to save dispute. Please try again")
+)
+
+// Database is the interface that wraps the basic dispute database operations.
+type Database interface {
+	// SaveDispute creates or replaces a dispute. Disputes are indexed by their transaction, and by their
+	// deadline while they await evidence.
+	SaveDispute(dispute *Dispute) error
+
+	// GetDispute gets a dispute by ID
+	GetDispute(id string) (*Dispute, error)
+
+	// GetTransactionDisputes gets the disputes of a transaction, oldest first
+	GetTransactionDisputes(transactionID string) ([]*Dispute, error)
+
+	// GetOverdueDisputes gets the IDs of the disputes awaiting evidence whose deadline is at or before the
+	// given time, earliest deadline first
+	GetOverdueDisputes(now time.Time, limit int) ([]string, error)
+}
@@ -0,0 +1,134 @@
+package dispute
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// Status is the type that represents the status of a dispute
+type Status string
+
+const (
+	// StatusOpened is the status of a dispute raised by the payer. The disputed amount is held on the merchant's account.
+	StatusOpened Status = "opened"
+
+	// StatusEvidenceRequired is the status of a dispute waiting for the merchant's evidence until its deadline
+	StatusEvidenceRequired Status = "evidence_required"
+
+	// StatusWon is the status of a dispute decided for the merchant. The held amount is released.
+	StatusWon Status = "won"
+
+	// StatusLost is the status of a dispute decided for the payer. The held amount is charged back.
+	StatusLost Status = "lost"
+)
+
+// EvidenceType is the type that represents what a piece of evidence shows
+type EvidenceType string
+
+const (
+	// EvidenceTypeReceipt is a receipt or invoice for the payment
+	EvidenceTypeReceipt EvidenceType = "receipt"
+
+	// EvidenceTypeDeliveryProof shows that the goods or service were delivered
+	EvidenceTypeDeliveryProof EvidenceType = "delivery_proof"
+
+	// EvidenceTypeCorrespondence is an exchange with the payer
+	EvidenceTypeCorrespondence EvidenceType = "correspondence"
+
+	// EvidenceTypeRefundPolicy is the refund policy the payer agreed to
+	EvidenceTypeRefundPolicy EvidenceType = "refund_policy"
+
+	// EvidenceTypeOther is any other evidence, explained by its description
+	EvidenceTypeOther EvidenceType = "other"
+)
+
+// IsValid reports whether the evidence type is known
+func (t EvidenceType) IsValid() bool {
+	switch t {
+	case EvidenceTypeReceipt, EvidenceTypeDeliveryProof, EvidenceTypeCorrespondence, EvidenceTypeRefundPolicy, EvidenceTypeOther:
+		return true
+	default:
+		return false
+	}
+}
+
+// Evidence is the metadata of a file submitted by the merchant to contest a dispute. The file itself is kept
+// in the merchant's or our document storage, at its location.
+type Evidence struct {
+	ID          string       `json:"id"`
+	Type        EvidenceType `json:"type"`
+	FileName    string       `json:"file_name"`
+	ContentType string       `json:"content_type,omitempty"`
+	Size        int64        `json:"size"`
+
+	// Checksum is the SHA-256 of the file, so that the file sent to the provider can be checked against it
+	Checksum    string    `json:"checksum,omitempty"`
+	Location    string    `json:"location,omitempty"`
+	Description string    `json:"description,omitempty"`
+	SubmittedBy string    `json:"submitted_by,omitempty"`
+	UploadedAt  time.Time `json:"uploaded_at"`
+}
+
+// Dispute is the entity that represents a payer contesting all or part of a payment, weeks after it was made
+type Dispute struct {
+	ID            string `json:"id"`
+	TransactionID string `json:"transaction_id"`
+
+	// Url is the endpoint of the merchant paid by the transaction, notified about the dispute
+	Url    string  `json:"url"`
+	Amount float32 `json:"amount"`
+	Reason string  `json:"reason"`
+
+	// ProviderReference is the card scheme's or provider's ID for the case
+	ProviderReference string `json:"provider_reference,omitempty"`
+	Status            Status `json:"status"`
+
+	// HoldID is the hold reserving the disputed amount on the merchant's account. It is empty when the
+	// merchant's balance could not cover it; a lost dispute is then charged back directly.
+	HoldID   string     `json:"hold_id,omitempty"`
+	Evidence []Evidence `json:"evidence"`
+
+	// DueBy is the deadline for the merchant's evidence
+	DueBy *time.Time `json:"due_by,omitempty"`
+
+	// Outcome explains how the dispute was decided
+	Outcome    string     `json:"outcome,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+}
+
+// NewDispute creates an opened dispute of an amount of the transaction paid to the merchant at the URL
+func NewDispute(transactionID, url string, amount float32, reason, providerReference string) *Dispute {
+	now := time.Now().UTC()
+	return &Dispute{
+		ID:                "dsp_" + uuid.NewString(),
+		TransactionID:     transactionID,
+		Url:               url,
+		Amount:            amount,
+		Reason:            reason,
+		ProviderReference: providerReference,
+		Status:            StatusOpened,
+		Evidence:          []Evidence{},
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+}
+
+// NewEvidence creates the metadata of a file submitted as evidence
+func NewEvidence(evidence Evidence) Evidence {
+	evidence.ID = "evd_" + uuid.NewString()
+	evidence.UploadedAt = time.Now().UTC()
+	return evidence
+}
+
+// IsFinal reports whether the dispute has been decided
+func (d *Dispute) IsFinal() bool {
+	return d.Status == StatusWon || d.Status == StatusLost
+}
+
+// AwaitsEvidence reports whether the dispute is waiting for evidence that has not been submitted, and so is
+// lost when its deadline passes
+func (d *Dispute) AwaitsEvidence() bool {
+	return d.Status == StatusEvidenceRequired && d.DueBy != nil && len(d.Evidence) == 0
+}
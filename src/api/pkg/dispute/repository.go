@@ -0,0 +1,18 @@
+package dispute
+
+import "time"
+
+// Repository is the dispute repository interface
+type Repository interface {
+	// Save creates or replaces a dispute.
+	Save(dispute *Dispute) error
+
+	// Find gets a dispute by ID.
+	Find(id string) (*Dispute, error)
+
+	// FindByTransaction gets the disputes of a transaction, oldest first.
+	FindByTransaction(transactionID string) ([]*Dispute, error)
+
+	// Overdue gets the IDs of the disputes whose evidence deadline has passed.
+	Overdue(now time.Time, limit int) ([]string, error)
+}
@@ -0,0 +1,380 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"github.com/quabynah-bilson/quantia/pkg/dispute"
+	"github.com/quabynah-bilson/quantia/pkg/event"
+	"github.com/quabynah-bilson/quantia/pkg/ledger"
+	"github.com/quabynah-bilson/quantia/pkg/payment"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrInvalidDispute is the error returned when a dispute is opened without a reason or for more than was paid.
+	ErrInvalidDispute = errors.New("invalid dispute. Please check the transaction, the amount and the reason")
+
+	// ErrDisputeExists is the error returned when a dispute is opened for a transaction whose last dispute is undecided.
+	ErrDisputeExists = errors.New("the transaction already has an open dispute")
+
+	// ErrInvalidDisputeState is the error returned when a dispute cannot be changed in its current state.
+	ErrInvalidDisputeState = errors.New("the dispute cannot be changed in its current state")
+
+	// ErrInvalidEvidence is the error returned when evidence misses its file name or size, or has an unknown type.
+	ErrInvalidEvidence = errors.New("invalid evidence. Please check the type, the file name and the size")
+
+	// ErrEvidenceDeadlinePassed is the error returned when evidence is submitted after the dispute's deadline.
+	ErrEvidenceDeadlinePassed = errors.New("the deadline for evidence has passed")
+
+	// ErrInvalidDisputeOutcome is the error returned when a dispute is resolved with a status other than won or lost.
+	ErrInvalidDisputeOutcome = errors.New("invalid dispute outcome. Please check the outcome is won or lost")
+
+	// ErrInvalidDeadline is the error returned when the deadline for evidence is not in the future.
+	ErrInvalidDeadline = errors.New("invalid deadline. Please check the deadline is in the future")
+)
+
+const (
+	// defaultDisputeHoldExpiry keeps the disputed amount held for the longest chargeback cycle when the configuration does not say.
+	defaultDisputeHoldExpiry = 120 * 24 * time.Hour
+
+	// defaultEvidenceWindow is how long merchants have to submit evidence when no deadline is given.
+	defaultEvidenceWindow = 7 * 24 * time.Hour
+
+	// overdueBatchSize is the number of overdue disputes decided per tick.
+	overdueBatchSize = 100
+)
+
+// DisputeConfig is the configuration of the dispute use case
+type DisputeConfig struct {
+	// HoldExpiry bounds the hold on the disputed amount; it should outlast the provider's dispute cycle
+	HoldExpiry time.Duration
+
+	// EvidenceWindow is the time given for evidence when evidence is required without a deadline
+	EvidenceWindow time.Duration
+}
+
+// DisputeUseCase is the dispute use case. It holds the disputed amount on the merchant's account while the
+// payer's claim is contested, tracks the merchant's evidence against its deadline and charges the amount
+// back or releases it once the dispute is decided. Merchants are notified at every step.
+type DisputeUseCase struct {
+	disputeRepo dispute.Repository
+	ledgerRepo  ledger.Repository
+	paymentRepo payment.Repository
+	config      DisputeConfig
+
+	// mu serializes the changes made to disputes by the API and the deadline worker
+	mu sync.Mutex
+}
+
+// NewDisputeUseCase creates a new dispute use case.
+func NewDisputeUseCase(disputeRepo dispute.Repository, ledgerRepo ledger.Repository, paymentRepo payment.Repository, config DisputeConfig) *DisputeUseCase {
+	if config.HoldExpiry <= 0 {
+		config.HoldExpiry = defaultDisputeHoldExpiry
+	}
+	if config.EvidenceWindow <= 0 {
+		config.EvidenceWindow = defaultEvidenceWindow
+	}
+
+	return &DisputeUseCase{
+		disputeRepo: disputeRepo,
+		ledgerRepo:  ledgerRepo,
+		paymentRepo: paymentRepo,
+		config:      config,
+	}
+}
+
+// OpenDispute records a dispute of an amount of a successful payment and holds that amount on the merchant's
+// account. A transaction has one undecided dispute at a time. The dispute is opened even when the merchant's
+// balance cannot cover the hold, since the payer's claim stands either way.
+func (uc *DisputeUseCase) OpenDispute(transactionID string, amount float32, reason, providerReference string) (*dispute.Dispute, error) {
+	if err := validateAmount(amount); err != nil {
+		log.Printf("error validating amount: %v", err)
+		return nil, err
+	}
+
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, ErrInvalidDispute
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	transaction, err := uc.paymentRepo.Find(transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if (!transaction.IsRefundable() && transaction.Status != payment.TransactionStatusRefunded) || amount > transaction.Amount {
+		return nil, ErrInvalidDispute
+	}
+
+	disputes, err := uc.disputeRepo.FindByTransaction(transactionID)
+	if err != nil {
+		log.Printf("error finding disputes of transaction %s: %v", transactionID, err)
+		return nil, err
+	}
+	for _, existing := range disputes {
+		if !existing.IsFinal() {
+			return nil, ErrDisputeExists
+		}
+	}
+
+	d := dispute.NewDispute(transactionID, transaction.Url, amount, reason, strings.TrimSpace(providerReference))
+	hold := ledger.NewHold(ledger.MerchantAccountID(d.Url), ledger.DisputesClearingAccountID, amount, uc.config.HoldExpiry)
+	switch err = uc.ledgerRepo.Hold(hold); {
+	case err == nil:
+		d.HoldID = hold.ID
+	case errors.Is(err, ledger.ErrInsufficientFunds):
+		log.Printf("merchant of dispute %s cannot cover a hold of %.2f", d.ID, amount)
+	default:
+		log.Printf("error placing hold for dispute %s: %v", d.ID, err)
+		return nil, err
+	}
+
+	if err = uc.disputeRepo.Save(d); err != nil {
+		log.Printf("error saving dispute %s: %v", d.ID, err)
+		uc.releaseHold(d)
+		return nil, err
+	}
+
+	uc.notify(d, event.TypeDisputeOpened)
+
+	return d, nil
+}
+
+// GetDispute gets a dispute by ID.
+func (uc *DisputeUseCase) GetDispute(id string) (*dispute.Dispute, error) {
+	return uc.disputeRepo.Find(id)
+}
+
+// GetTransactionDisputes gets the disputes of a transaction, oldest first.
+func (uc *DisputeUseCase) GetTransactionDisputes(transactionID string) ([]*dispute.Dispute, error) {
+	return uc.disputeRepo.FindByTransaction(transactionID)
+}
+
+// RequireEvidence asks the merchant for evidence by the deadline, or within the evidence window when the
+// deadline is zero. The deadline of a dispute already waiting for evidence can be moved.
+func (uc *DisputeUseCase) RequireEvidence(id string, dueBy time.Time) (*dispute.Dispute, error) {
+	now := time.Now().UTC()
+	if dueBy.IsZero() {
+		dueBy = now.Add(uc.config.EvidenceWindow)
+	}
+	if !dueBy.After(now) {
+		return nil, ErrInvalidDeadline
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	d, err := uc.disputeRepo.Find(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.Status != dispute.StatusOpened && d.Status != dispute.StatusEvidenceRequired {
+		return nil, ErrInvalidDisputeState
+	}
+
+	dueBy = dueBy.UTC()
+	d.Status, d.DueBy, d.UpdatedAt = dispute.StatusEvidenceRequired, &dueBy, now
+	if err = uc.disputeRepo.Save(d); err != nil {
+		log.Printf("error saving dispute %s: %v", d.ID, err)
+		return nil, err
+	}
+
+	uc.notify(d, event.TypeDisputeEvidenceRequired)
+
+	return d, nil
+}
+
+// AddEvidence records the metadata of a file submitted by the merchant. Evidence is accepted until the dispute
+// is decided or its deadline passes.
+func (uc *DisputeUseCase) AddEvidence(id string, evidence dispute.Evidence) (*dispute.Dispute, error) {
+	evidence.FileName = strings.TrimSpace(evidence.FileName)
+	if !evidence.Type.IsValid() || evidence.FileName == "" || evidence.Size <= 0 {
+		return nil, ErrInvalidEvidence
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	d, err := uc.disputeRepo.Find(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.IsFinal() {
+		return nil, ErrInvalidDisputeState
+	}
+
+	now := time.Now().UTC()
+	if d.DueBy != nil && !now.Before(*d.DueBy) {
+		return nil, ErrEvidenceDeadlinePassed
+	}
+
+	d.Evidence = append(d.Evidence, dispute.NewEvidence(evidence))
+	d.UpdatedAt = now
+	if err = uc.disputeRepo.Save(d); err != nil {
+		log.Printf("error saving dispute %s: %v", d.ID, err)
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// Resolve records the decision on a dispute, explained by the reason: a won dispute releases the held amount
+// to the merchant, a lost one charges it back.
+func (uc *DisputeUseCase) Resolve(id string, outcome dispute.Status, reason string) (*dispute.Dispute, error) {
+	if outcome != dispute.StatusWon && outcome != dispute.StatusLost {
+		return nil, ErrInvalidDisputeOutcome
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	d, err := uc.disputeRepo.Find(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.IsFinal() {
+		return nil, ErrInvalidDisputeState
+	}
+
+	return uc.resolve(d, outcome, strings.TrimSpace(reason))
+}
+
+// Run decides the disputes whose evidence deadline has passed at every interval until the context is cancelled.
+func (uc *DisputeUseCase) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		uc.RunOverdue(time.Now().UTC())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOverdue loses the disputes still waiting for evidence at their deadline and returns how many were found.
+func (uc *DisputeUseCase) RunOverdue(now time.Time) int {
+	ids, err := uc.disputeRepo.Overdue(now, overdueBatchSize)
+	if err != nil {
+		log.Printf("error getting overdue disputes: %v", err)
+		return 0
+	}
+
+	for _, id := range ids {
+		uc.expire(id, now)
+	}
+
+	return len(ids)
+}
+
+// expire loses a dispute that is still waiting for evidence past its deadline.
+func (uc *DisputeUseCase) expire(id string, now time.Time) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	d, err := uc.disputeRepo.Find(id)
+	if err != nil {
+		log.Printf("error finding dispute %s: %v", id, err)
+		return
+	}
+
+	// evidence may have arrived, or the deadline moved, since the dispute was listed
+	if !d.AwaitsEvidence() || now.Before(*d.DueBy) {
+		return
+	}
+
+	if _, err = uc.resolve(d, dispute.StatusLost, "no evidence was submitted before the deadline"); err != nil {
+		log.Printf("error expiring dispute %s: %v", d.ID, err)
+	}
+}
+
+// resolve settles the held amount of an undecided dispute and saves the decision.
+func (uc *DisputeUseCase) resolve(d *dispute.Dispute, outcome dispute.Status, reason string) (*dispute.Dispute, error) {
+	eventType := event.TypeDisputeLost
+	if outcome == dispute.StatusWon {
+		eventType = event.TypeDisputeWon
+		uc.releaseHold(d)
+	} else if err := uc.chargeBack(d); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	d.Status, d.Outcome, d.UpdatedAt, d.ResolvedAt = outcome, reason, now, &now
+	if err := uc.disputeRepo.Save(d); err != nil {
+		log.Printf("error saving dispute %s: %v", d.ID, err)
+		return nil, err
+	}
+
+	uc.notify(d, eventType)
+
+	return d, nil
+}
+
+// chargeBack moves the disputed amount from the merchant's account by capturing its hold. Disputes without
+// an active hold are posted directly, which may leave the merchant owing the amount.
+func (uc *DisputeUseCase) chargeBack(d *dispute.Dispute) error {
+	if d.HoldID != "" {
+		_, err := uc.ledgerRepo.Capture(d.HoldID, d.Amount)
+		if err == nil {
+			return nil
+		}
+		// the hold may have expired, in which case the amount is charged back directly
+		log.Printf("error capturing hold %s of dispute %s: %v", d.HoldID, d.ID, err)
+	}
+
+	entries := ledger.NewTransfer(d.ID+":chargeback", "chargeback of dispute "+d.ID, ledger.MerchantAccountID(d.Url), ledger.DisputesClearingAccountID, d.Amount)
+	if err := uc.ledgerRepo.Post(entries...); err != nil && !errors.Is(err, ledger.ErrEntriesAlreadyPosted) {
+		log.Printf("error posting chargeback of dispute %s: %v", d.ID, err)
+		return err
+	}
+
+	return nil
+}
+
+// releaseHold gives the held amount of a dispute back to the merchant.
+func (uc *DisputeUseCase) releaseHold(d *dispute.Dispute) {
+	if d.HoldID == "" {
+		return
+	}
+
+	if _, err := uc.ledgerRepo.Release(d.HoldID, ledger.HoldStatusVoided); err != nil {
+		log.Printf("error releasing hold %s of dispute %s: %v", d.HoldID, d.ID, err)
+	}
+}
+
+// notify queues a dispute event for the merchant.
+func (uc *DisputeUseCase) notify(d *dispute.Dispute, eventType event.Type) {
+	reason := d.Reason
+	if d.Outcome != "" {
+		reason = d.Outcome
+	}
+
+	envelope, err := event.New(eventType, &event.DisputeData{
+		DisputeID:     d.ID,
+		TransactionID: d.TransactionID,
+		Amount:        d.Amount,
+		Status:        string(d.Status),
+		Reason:        reason,
+		DueBy:         d.DueBy,
+	})
+	if err != nil {
+		log.Printf("error creating %s event: %v", eventType, err)
+		return
+	}
+
+	if err = uc.paymentRepo.Notify(d.Url, envelope); err != nil {
+		log.Printf("error queueing %s event: %v", eventType, err)
+	}
+}
@@ -0,0 +1,23 @@
+package escrow
+
+import "errors"
+
+var (
+	// ErrEscrowNotFound is the error returned when an escrow does not exist
+	ErrEscrowNotFound = errors.New("escrow not found")
+
+	// ErrFailedToSaveEscrow is the error returned when an escrow cannot be stored
+	ErrFailedToSaveEscrow = errors.New("failed to save escrow. Please try again")
+)
+
+// Database is the interface that wraps the basic escrow database operations.
+type Database interface {
+	// SaveEscrow creates or replaces an escrow. Escrows are indexed by the transaction funding them.
+	SaveEscrow(escrow *Escrow) error
+
+	// GetEscrow gets an escrow by ID
+	GetEscrow(id string) (*Escrow, error)
+
+	// GetTransactionEscrow gets the escrow funded by a transaction
+	GetTransactionEscrow(transactionID string) (*Escrow, error)
+}
@@ -0,0 +1,72 @@
+package escrow
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// Status is the type that represents the status of an escrow
+type Status string
+
+const (
+	// StatusPending is the status of an escrow whose payment is waiting for the provider or a review
+	StatusPending Status = "pending"
+
+	// StatusFailed is the status of an escrow whose payment was declined, blocked or failed
+	StatusFailed Status = "failed"
+
+	// StatusFunded is the status of an escrow holding the payment until delivery is confirmed
+	StatusFunded Status = "funded"
+
+	// StatusDisputed is the status of a funded escrow whose delivery is contested. It is released or refunded
+	// once the dispute is settled.
+	StatusDisputed Status = "disputed"
+
+	// StatusReleased is the status of an escrow whose funds were paid out to the parties
+	StatusReleased Status = "released"
+
+	// StatusRefunded is the status of an escrow whose funds were returned to the payer
+	StatusRefunded Status = "refunded"
+)
+
+// Escrow is the entity that represents a marketplace payment held until delivery is confirmed, then split
+// between the platform and the recipients
+type Escrow struct {
+	ID string `json:"id"`
+
+	// AccountID is the account of the payer who took the payment into escrow
+	AccountID string `json:"account_id"`
+
+	// TransactionID is the payment funding the escrow
+	TransactionID string `json:"transaction_id,omitempty"`
+
+	// Url is the endpoint of the platform taking the payment
+	Url    string  `json:"url"`
+	Amount float32 `json:"amount"`
+	Split  Split   `json:"split"`
+
+	// Allocations are the amounts paid to each party on release, worked out when the escrow is created
+	Allocations []Allocation `json:"allocations"`
+	Status      Status       `json:"status"`
+
+	// Reason explains the last dispute or refund
+	Reason    string    `json:"reason,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NewEscrow creates a pending escrow of the allocated amount paid by the account for the platform at the URL
+func NewEscrow(accountID, url string, amount float32, split Split, allocations []Allocation) *Escrow {
+	now := time.Now().UTC()
+	return &Escrow{
+		ID:          "esc_" + uuid.NewString(),
+		AccountID:   accountID,
+		Url:         url,
+		Amount:      amount,
+		Split:       split,
+		Allocations: allocations,
+		Status:      StatusPending,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
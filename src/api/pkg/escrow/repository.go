@@ -0,0 +1,13 @@
+package escrow
+
+// Repository is the escrow repository interface
+type Repository interface {
+	// Save creates or replaces an escrow.
+	Save(escrow *Escrow) error
+
+	// Find gets an escrow by ID.
+	Find(id string) (*Escrow, error)
+
+	// FindByTransaction gets the escrow funded by a transaction.
+	FindByTransaction(transactionID string) (*Escrow, error)
+}
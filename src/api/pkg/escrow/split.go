@@ -0,0 +1,149 @@
+package escrow
+
+import (
+	"errors"
+	"github.com/quabynah-bilson/quantia/pkg/ledger"
+	"math"
+)
+
+// ErrInvalidSplit is the error returned when split rules are incomplete or add up to more than the amount
+var ErrInvalidSplit = errors.New("invalid split. Please check the shares of the recipients and the fee")
+
+// ShareType is the type that represents how a share of a payment is worked out
+type ShareType string
+
+const (
+	// ShareTypeFixed is a share of a fixed amount
+	ShareTypeFixed ShareType = "fixed"
+
+	// ShareTypePercentage is a share of a percentage of the payment, rounded down to the cent
+	ShareTypePercentage ShareType = "percentage"
+
+	// ShareTypeRemainder is the share of what is left once the fixed and percentage shares are taken
+	ShareTypeRemainder ShareType = "remainder"
+)
+
+// Share is a part of a payment
+type Share struct {
+	Type ShareType `json:"type"`
+
+	// Value is the amount of a fixed share or the percentage (0-100] of a percentage share
+	Value float32 `json:"value,omitempty"`
+}
+
+// Rule pays a share of a payment to a recipient
+type Rule struct {
+	// Url is the recipient's endpoint, notified about the escrow. Its host is the recipient's ledger account.
+	Url string `json:"url"`
+	Share
+}
+
+// Split is the set of rules dividing a payment between the recipients and the platform. The platform is
+// the merchant taking the payment: it receives the fee, and the remainder when no rule takes it.
+type Split struct {
+	Fee   *Share `json:"fee,omitempty"`
+	Rules []Rule `json:"rules"`
+}
+
+// Allocation is the amount of a payment paid to a party
+type Allocation struct {
+	Url    string  `json:"url"`
+	Amount float32 `json:"amount"`
+
+	// Platform is set on the allocation of the merchant taking the payment
+	Platform bool `json:"platform,omitempty"`
+}
+
+// Allocate divides an amount between the platform at the URL and the recipients. Percentage shares are
+// rounded down to the cent; the cents left over go with the remainder.
+func (s *Split) Allocate(platformURL string, amount float32) ([]Allocation, error) {
+	total := toMinorUnits(amount)
+	if total <= 0 || len(s.Rules) == 0 {
+		return nil, ErrInvalidSplit
+	}
+
+	fee := int64(0)
+	if s.Fee != nil {
+		if s.Fee.Type == ShareTypeRemainder {
+			return nil, ErrInvalidSplit
+		}
+
+		var err error
+		if fee, err = s.Fee.minorUnits(total); err != nil {
+			return nil, err
+		}
+	}
+
+	parties := map[string]bool{ledger.MerchantAccountID(platformURL): true}
+	allocated := fee
+	remainder := -1
+	shares := make([]int64, len(s.Rules))
+	for i, rule := range s.Rules {
+		// each party, the platform included, has one allocation and so one ledger account
+		if rule.Url == "" || parties[ledger.MerchantAccountID(rule.Url)] {
+			return nil, ErrInvalidSplit
+		}
+		parties[ledger.MerchantAccountID(rule.Url)] = true
+
+		if rule.Type == ShareTypeRemainder {
+			if remainder >= 0 || rule.Value != 0 {
+				return nil, ErrInvalidSplit
+			}
+			remainder = i
+			continue
+		}
+
+		share, err := rule.minorUnits(total)
+		if err != nil {
+			return nil, err
+		}
+		shares[i] = share
+		allocated += share
+	}
+
+	if allocated > total {
+		return nil, ErrInvalidSplit
+	}
+
+	left := total - allocated
+	if remainder >= 0 {
+		shares[remainder] = left
+	} else {
+		fee += left
+	}
+
+	allocations := []Allocation{{Url: platformURL, Amount: fromMinorUnits(fee), Platform: true}}
+	for i, rule := range s.Rules {
+		allocations = append(allocations, Allocation{Url: rule.Url, Amount: fromMinorUnits(shares[i])})
+	}
+
+	return allocations, nil
+}
+
+// minorUnits works out a fixed or percentage share of a total in cents
+func (s *Share) minorUnits(total int64) (int64, error) {
+	switch s.Type {
+	case ShareTypeFixed:
+		if s.Value <= 0 {
+			return 0, ErrInvalidSplit
+		}
+		return toMinorUnits(s.Value), nil
+	case ShareTypePercentage:
+		if s.Value <= 0 || s.Value > 100 {
+			return 0, ErrInvalidSplit
+		}
+		return int64(math.Floor(float64(total) * float64(s.Value) / 100)), nil
+	default:
+		return 0, ErrInvalidSplit
+	}
+}
+
+// toMinorUnits converts an amount to cents
+func toMinorUnits(amount float32) int64 {
+	return int64(math.Round(float64(amount) * 100))
+}
+
+// fromMinorUnits converts cents to an amount
+func fromMinorUnits(amount int64) float32 {
+	return float32(amount) / 100
+}
@@ -0,0 +1,275 @@
+package pkg
+
+import (
+	"errors"
+	"github.com/quabynah-bilson/quantia/pkg/escrow"
+	"github.com/quabynah-bilson/quantia/pkg/event"
+	"github.com/quabynah-bilson/quantia/pkg/fraud"
+	"github.com/quabynah-bilson/quantia/pkg/ledger"
+	"github.com/quabynah-bilson/quantia/pkg/payment"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrInvalidEscrowState is the error returned when an escrow cannot be changed in its current state.
+var ErrInvalidEscrowState = errors.New("the escrow cannot be changed in its current state")
+
+// EscrowUseCase is the escrow use case. It holds marketplace payments in a ledger account of their own until
+// delivery is confirmed, then splits them between the platform and the recipients.
+type EscrowUseCase struct {
+	escrowRepo escrow.Repository
+	ledgerRepo ledger.Repository
+	payments   *PaymentUseCase
+
+	// mu serializes the changes made to escrows by the platform and payment results
+	mu sync.Mutex
+}
+
+// NewEscrowUseCase creates a new escrow use case. Escrows are funded as their payments complete.
+func NewEscrowUseCase(escrowRepo escrow.Repository, ledgerRepo ledger.Repository, payments *PaymentUseCase) *EscrowUseCase {
+	uc := &EscrowUseCase{
+		escrowRepo: escrowRepo,
+		ledgerRepo: ledgerRepo,
+		payments:   payments,
+	}
+	payments.OnCompleted(uc.handlePayment)
+
+	return uc
+}
+
+// CreateEscrow takes a payment from the source of the account for the platform at the URL and holds it in
+// escrow. The split is worked out up front, so that a split that does not add up is refused before anything
+// is charged. The escrow is funded once the payment succeeds.
+func (uc *EscrowUseCase) CreateEscrow(accountID string, amount float32, url, source string, split escrow.Split, origin *fraud.Origin) (*escrow.Escrow, error) {
+	if accountID == "" {
+		return nil, ErrInvalidAccount
+	}
+
+	if err := validateAmount(amount); err != nil {
+		log.Printf("error validating amount: %v", err)
+		return nil, err
+	}
+
+	if err := uc.payments.checkURL(url); err != nil {
+		return nil, err
+	}
+
+	for _, rule := range split.Rules {
+		if err := uc.payments.checkURL(rule.Url); err != nil {
+			return nil, err
+		}
+	}
+
+	allocations, err := split.Allocate(url, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	e := escrow.NewEscrow(accountID, url, amount, split, allocations)
+	if err = uc.escrowRepo.Save(e); err != nil {
+		log.Printf("error saving escrow: %v", err)
+		return nil, err
+	}
+
+	transaction, err := uc.payments.MakePayment(amount, url, source, origin)
+	if transaction == nil {
+		// nothing was charged, so the escrow will never be funded
+		uc.fail(e.ID, err)
+		return nil, err
+	}
+
+	e.TransactionID = transaction.ID
+	if saveErr := uc.escrowRepo.Save(e); saveErr != nil {
+		log.Printf("error saving escrow %s: %v", e.ID, saveErr)
+	}
+
+	// the payment may have completed before it was recorded, in which case no result will follow
+	if stored, findErr := uc.payments.paymentRepo.Find(transaction.ID); findErr == nil {
+		transaction = stored
+	}
+	uc.settle(e.ID, transaction)
+
+	if stored, findErr := uc.escrowRepo.Find(e.ID); findErr == nil {
+		e = stored
+	}
+
+	return e, err
+}
+
+// GetEscrow gets an escrow by ID.
+func (uc *EscrowUseCase) GetEscrow(id string) (*escrow.Escrow, error) {
+	return uc.escrowRepo.Find(id)
+}
+
+// Release pays a funded or disputed escrow out to the platform and the recipients, as allocated when it
+// was created.
+func (uc *EscrowUseCase) Release(id string) (*escrow.Escrow, error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	e, err := uc.escrowRepo.Find(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if e.Status != escrow.StatusFunded && e.Status != escrow.StatusDisputed {
+		return nil, ErrInvalidEscrowState
+	}
+
+	credits := make([]ledger.Credit, 0, len(e.Allocations))
+	for _, allocation := range e.Allocations {
+		credits = append(credits, ledger.Credit{AccountID: ledger.MerchantAccountID(allocation.Url), Amount: allocation.Amount})
+	}
+
+	// a release that was posted before the escrow could be saved is not posted twice
+	entries := ledger.NewSplitTransfer(e.ID+":release", "release of escrow "+e.ID, ledger.EscrowAccountID(e.ID), credits)
+	if err = uc.ledgerRepo.Post(entries...); err != nil && !errors.Is(err, ledger.ErrEntriesAlreadyPosted) {
+		log.Printf("error posting release of escrow %s: %v", e.ID, err)
+		return nil, err
+	}
+
+	return uc.transition(e, escrow.StatusReleased, e.Reason, event.TypeEscrowReleased)
+}
+
+// Dispute holds a funded escrow while its delivery is contested. A disputed escrow is settled by releasing
+// or refunding it.
+func (uc *EscrowUseCase) Dispute(id, reason string) (*escrow.Escrow, error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	e, err := uc.escrowRepo.Find(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if e.Status != escrow.StatusFunded {
+		return nil, ErrInvalidEscrowState
+	}
+
+	return uc.transition(e, escrow.StatusDisputed, strings.TrimSpace(reason), event.TypeEscrowDisputed)
+}
+
+// Refund returns a funded or disputed escrow to the payer by refunding its payment in full. The escrow is
+// refunded as soon as the provider accepts the refund, even if its answer is still pending.
+func (uc *EscrowUseCase) Refund(id, reason string) (*escrow.Escrow, error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	e, err := uc.escrowRepo.Find(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if e.Status != escrow.StatusFunded && e.Status != escrow.StatusDisputed {
+		return nil, ErrInvalidEscrowState
+	}
+
+	reason = strings.TrimSpace(reason)
+	if _, err = uc.payments.RefundPayment(e.TransactionID, e.Amount, reason); err != nil {
+		log.Printf("error refunding escrow %s: %v", e.ID, err)
+		return nil, err
+	}
+
+	// the refund reverses the payment's posting to the platform, so the funds go back there first
+	uc.post(ledger.NewTransfer(e.ID+":refund", "refund of escrow "+e.ID, ledger.EscrowAccountID(e.ID), ledger.MerchantAccountID(e.Url), e.Amount))
+
+	return uc.transition(e, escrow.StatusRefunded, reason, event.TypeEscrowRefunded)
+}
+
+// handlePayment funds or fails the escrow of a payment that succeeded or failed. Payments that do not fund
+// an escrow are ignored.
+func (uc *EscrowUseCase) handlePayment(transaction *payment.Transaction) {
+	e, err := uc.escrowRepo.FindByTransaction(transaction.ID)
+	if err != nil {
+		return
+	}
+
+	uc.settle(e.ID, transaction)
+}
+
+// settle records the outcome of an escrow's payment: the funds move from the platform's account into the
+// escrow's when it succeeds. Each escrow is settled once.
+func (uc *EscrowUseCase) settle(id string, transaction *payment.Transaction) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	e, err := uc.escrowRepo.Find(id)
+	if err != nil {
+		log.Printf("error finding escrow %s: %v", id, err)
+		return
+	}
+
+	if e.Status != escrow.StatusPending {
+		return
+	}
+
+	switch transaction.Status {
+	case payment.TransactionStatusSuccess:
+		uc.post(ledger.NewTransfer(e.ID+":fund", "funding of escrow "+e.ID, ledger.MerchantAccountID(e.Url), ledger.EscrowAccountID(e.ID), e.Amount))
+		_, _ = uc.transition(e, escrow.StatusFunded, "", event.TypeEscrowFunded)
+	case payment.TransactionStatusFailed:
+		e.Status, e.UpdatedAt = escrow.StatusFailed, time.Now().UTC()
+		if err = uc.escrowRepo.Save(e); err != nil {
+			log.Printf("error saving escrow %s: %v", e.ID, err)
+		}
+	}
+}
+
+// fail marks an escrow whose payment was never made as failed.
+func (uc *EscrowUseCase) fail(id string, reason error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	e, err := uc.escrowRepo.Find(id)
+	if err != nil || e.Status != escrow.StatusPending {
+		return
+	}
+
+	e.Status, e.UpdatedAt = escrow.StatusFailed, time.Now().UTC()
+	if reason != nil {
+		e.Reason = reason.Error()
+	}
+	if err = uc.escrowRepo.Save(e); err != nil {
+		log.Printf("error saving escrow %s: %v", e.ID, err)
+	}
+}
+
+// transition saves an escrow in its new status and notifies each party of its share.
+func (uc *EscrowUseCase) transition(e *escrow.Escrow, status escrow.Status, reason string, eventType event.Type) (*escrow.Escrow, error) {
+	e.Status, e.Reason, e.UpdatedAt = status, reason, time.Now().UTC()
+	if err := uc.escrowRepo.Save(e); err != nil {
+		log.Printf("error saving escrow %s: %v", e.ID, err)
+		return nil, err
+	}
+
+	for _, allocation := range e.Allocations {
+		envelope, err := event.New(eventType, &event.EscrowData{
+			EscrowID:      e.ID,
+			TransactionID: e.TransactionID,
+			Amount:        e.Amount,
+			Share:         allocation.Amount,
+			Status:        string(e.Status),
+			Reason:        e.Reason,
+		})
+		if err != nil {
+			log.Printf("error creating %s event: %v", eventType, err)
+			continue
+		}
+
+		if err = uc.payments.paymentRepo.Notify(allocation.Url, envelope); err != nil {
+			log.Printf("error queueing %s event: %v", eventType, err)
+		}
+	}
+
+	return e, nil
+}
+
+// post records ledger entries. Failures are logged, not returned, because the money has already moved
+// at the provider.
+func (uc *EscrowUseCase) post(entries []*ledger.Entry) {
+	if err := uc.ledgerRepo.Post(entries...); err != nil && !errors.Is(err, ledger.ErrEntriesAlreadyPosted) {
+		log.Printf("error posting ledger entries for %s: %v", entries[0].Reference, err)
+	}
+}
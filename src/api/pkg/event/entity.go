@@ -0,0 +1,234 @@
+package event
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"time"
+)
+
+// Version is the version of the event envelope schema. It changes whenever a field is removed or its meaning changes.
+const Version = "v1"
+
+var (
+	// ErrUnknownEventType is the error returned when an event type is not part of the taxonomy
+	ErrUnknownEventType = errors.New("unknown event type")
+
+	// ErrFailedToMarshalEvent is the error returned when an event's data cannot be marshalled
+	ErrFailedToMarshalEvent = errors.New("invalid event details. Please check and try again")
+)
+
+// Type is the type that represents an event type
+type Type string
+
+const (
+	// TypePaymentCreated is emitted when a payment is accepted for processing
+	TypePaymentCreated Type = "payment.created"
+
+	// TypePaymentSucceeded is emitted when a payment completes successfully
+	TypePaymentSucceeded Type = "payment.succeeded"
+
+	// TypePaymentFailed is emitted when a payment is declined or fails
+	TypePaymentFailed Type = "payment.failed"
+
+	// TypeRefundCreated is emitted when a refund is accepted for processing
+	TypeRefundCreated Type = "refund.created"
+
+	// TypeRefundSucceeded is emitted when refunded money has been returned to the payer
+	TypeRefundSucceeded Type = "refund.succeeded"
+
+	// TypeRefundFailed is emitted when the provider rejects a refund
+	TypeRefundFailed Type = "refund.failed"
+
+	// TypeScheduledPaymentFailed is emitted when an occurrence of a scheduled payment is declined, fails or is missed
+	TypeScheduledPaymentFailed Type = "scheduled_payment.failed"
+
+	// TypeTransferCompleted is emitted when money has moved between two accounts
+	TypeTransferCompleted Type = "transfer.completed"
+
+	// TypeAccountOverdrawn is emitted when an account's available balance goes below zero, into its overdraft
+	TypeAccountOverdrawn Type = "account.overdrawn"
+
+	// TypeAccountOverdraftRepaid is emitted when an overdrawn account's available balance is back at or above zero
+	TypeAccountOverdraftRepaid Type = "account.overdraft_repaid"
+
+	// TypeInvoicePaid is emitted when the payment of an invoice succeeds
+	TypeInvoicePaid Type = "invoice.paid"
+
+	// TypePaymentLinkPaid is emitted when a payment made through a link without an invoice succeeds
+	TypePaymentLinkPaid Type = "payment_link.paid"
+
+	// TypeEscrowFunded is emitted to each party of an escrow when its payment succeeds
+	TypeEscrowFunded Type = "escrow.funded"
+
+	// TypeEscrowReleased is emitted to each party of an escrow when its funds are paid out
+	TypeEscrowReleased Type = "escrow.released"
+
+	// TypeEscrowDisputed is emitted to each party of an escrow when its delivery is contested
+	TypeEscrowDisputed Type = "escrow.disputed"
+
+	// TypeEscrowRefunded is emitted to each party of an escrow when its funds are returned to the payer
+	TypeEscrowRefunded Type = "escrow.refunded"
+
+	// TypeDisputeOpened is emitted when a payer disputes a payment and the disputed amount is held
+	TypeDisputeOpened Type = "dispute.opened"
+
+	// TypeDisputeEvidenceRequired is emitted when the merchant must submit evidence before the dispute's deadline
+	TypeDisputeEvidenceRequired Type = "dispute.evidence_required"
+
+	// TypeDisputeWon is emitted when a dispute is decided for the merchant and the held amount is released
+	TypeDisputeWon Type = "dispute.won"
+
+	// TypeDisputeLost is emitted when a dispute is decided for the payer and the held amount is charged back
+	TypeDisputeLost Type = "dispute.lost"
+)
+
+// Types returns every event type of the taxonomy
+func Types() []Type {
+	return []Type{
+		TypePaymentCreated,
+		TypePaymentSucceeded,
+		TypePaymentFailed,
+		TypeRefundCreated,
+		TypeRefundSucceeded,
+		TypeRefundFailed,
+		TypeScheduledPaymentFailed,
+		TypeTransferCompleted,
+		TypeAccountOverdrawn,
+		TypeAccountOverdraftRepaid,
+		TypeInvoicePaid,
+		TypePaymentLinkPaid,
+		TypeEscrowFunded,
+		TypeEscrowReleased,
+		TypeEscrowDisputed,
+		TypeEscrowRefunded,
+		TypeDisputeOpened,
+		TypeDisputeEvidenceRequired,
+		TypeDisputeWon,
+		TypeDisputeLost,
+	}
+}
+
+// Envelope is the entity that wraps every outbound notification.
+// ID is unique per event and stays the same across delivery attempts so that receivers can de-duplicate.
+type Envelope struct {
+	ID        string          `json:"id"`
+	Type      Type            `json:"type"`
+	Version   string          `json:"version"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// PaymentData is the data of the payment.* events
+type PaymentData struct {
+	TransactionID string  `json:"transaction_id"`
+	Amount        float32 `json:"amount"`
+	Status        string  `json:"status"`
+}
+
+// RefundData is the data of the refund.* events
+type RefundData struct {
+	RefundID      string  `json:"refund_id"`
+	TransactionID string  `json:"transaction_id"`
+	Amount        float32 `json:"amount"`
+	Status        string  `json:"status"`
+	Reason        string  `json:"reason,omitempty"`
+}
+
+// ScheduledPaymentData is the data of the scheduled_payment.* events
+type ScheduledPaymentData struct {
+	ScheduleID    string    `json:"schedule_id"`
+	Occurrence    int       `json:"occurrence"`
+	ScheduledAt   time.Time `json:"scheduled_at"`
+	TransactionID string    `json:"transaction_id,omitempty"`
+	Reason        string    `json:"reason,omitempty"`
+}
+
+// TransferData is the data of the transfer.* events
+type TransferData struct {
+	TransferID    string `json:"transfer_id"`
+	FromAccountID string `json:"from_account_id"`
+
+	// ToAccountID is the destination of the transfer: a ledger account, a wallet number or a bank account number
+	ToAccountID string  `json:"to_account_id"`
+	Amount      float32 `json:"amount"`
+}
+
+// OverdraftData is the data of the account.overdrawn and account.overdraft_repaid events. Balance is the
+// available balance that crossed zero; OverdrawnSince is when the account entered its overdraft.
+type OverdraftData struct {
+	AccountID      string     `json:"account_id"`
+	Balance        float32    `json:"balance"`
+	Limit          float32    `json:"limit"`
+	OverdrawnSince *time.Time `json:"overdrawn_since,omitempty"`
+}
+
+// InvoiceData is the data of the invoice.* events
+type InvoiceData struct {
+	InvoiceID     string  `json:"invoice_id"`
+	TransactionID string  `json:"transaction_id"`
+	LinkID        string  `json:"link_id,omitempty"`
+	Amount        float32 `json:"amount"`
+	Currency      string  `json:"currency"`
+	Status        string  `json:"status"`
+}
+
+// PaymentLinkData is the data of the payment_link.* events
+type PaymentLinkData struct {
+	LinkID        string  `json:"link_id"`
+	TransactionID string  `json:"transaction_id"`
+	Amount        float32 `json:"amount"`
+	Currency      string  `json:"currency"`
+}
+
+// New creates a new event envelope of the given type with a fresh event ID
+func New(eventType Type, data interface{}) (*Envelope, error) {
+	if !eventType.IsValid() {
+		return nil, ErrUnknownEventType
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, ErrFailedToMarshalEvent
+	}
+
+	return &Envelope{
+		ID:        "evt_" + uuid.NewString(),
+		Type:      eventType,
+		Version:   Version,
+		CreatedAt: time.Now().UTC(),
+		Data:      raw,
+	}, nil
+}
+
+// IsValid reports whether the event type is part of the taxonomy
+func (t Type) IsValid() bool {
+	for _, known := range Types() {
+		if t == known {
+			return true
+		}
+	}
+
+	return false
+}
+
+// EscrowData is the data of the escrow.* events. Share is the amount of the escrow allocated to the party
+// receiving the event.
+type EscrowData struct {
+	EscrowID      string  `json:"escrow_id"`
+	TransactionID string  `json:"transaction_id"`
+	Amount        float32 `json:"amount"`
+	Share         float32 `json:"share"`
+	Status        string  `json:"status"`
+	Reason        string  `json:"reason,omitempty"`
+}
+
+// DisputeData is the data of the dispute.* events. DueBy is the deadline for the merchant's evidence.
+type DisputeData struct {
+	DisputeID     string     `json:"dispute_id"`
+	TransactionID string     `json:"transaction_id"`
+	Amount        float32    `json:"amount"`
+	Status        string     `json:"status"`
+	Reason        string     `json:"reason,omitempty"`
+	DueBy         *time.Time `json:"due_by,omitempty"`
+}
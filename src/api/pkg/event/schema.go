@@ -0,0 +1,20 @@
+package event
+
+import (
+	"embed"
+	"fmt"
+)
+
+// schemas holds the JSON Schema definition of each event type, named after the type (e.g. payment.created.json)
+//
+//go:embed schemas/*.json
+var schemas embed.FS
+
+// Schema returns the JSON Schema definition of the given event type
+func Schema(eventType Type) ([]byte, error) {
+	if !eventType.IsValid() {
+		return nil, ErrUnknownEventType
+	}
+
+	return schemas.ReadFile(fmt.Sprintf("schemas/%s.json", eventType))
+}
@@ -0,0 +1,119 @@
+package fraud
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"time"
+)
+
+// ErrInvalidConfig is the error returned when a fraud rules configuration is unusable
+var ErrInvalidConfig = errors.New("invalid fraud rules configuration")
+
+// RuleName is the type that names a fraud rule
+type RuleName string
+
+const (
+	// RuleNewDevice matches payments from a device the subject has not used before
+	RuleNewDevice RuleName = "new_device"
+
+	// RuleUnusualAmount matches payments much larger than the subject's usual amounts
+	RuleUnusualAmount RuleName = "unusual_amount"
+
+	// RuleRapidSuccession matches payments attempted in quick succession
+	RuleRapidSuccession RuleName = "rapid_succession"
+
+	// RuleNewBeneficiary matches payments to someone the subject has not paid before
+	RuleNewBeneficiary RuleName = "new_beneficiary"
+
+	// RuleGeoMismatch matches payments whose IP address is not where the payer is expected to be
+	RuleGeoMismatch RuleName = "geo_mismatch"
+)
+
+// RuleConfig is the entity that represents the settings of a rule. Only the settings the rule uses are read.
+type RuleConfig struct {
+	// Score is added to the payment's score when the rule matches
+	Score int `json:"score"`
+
+	// Multiplier is how many times the average amount a payment must exceed to be unusual
+	Multiplier float32 `json:"multiplier,omitempty"`
+
+	// MinHistory is how many trusted amounts are needed before amounts are judged unusual
+	MinHistory int `json:"min_history,omitempty"`
+
+	// MaxCount is how many payments may be attempted within WindowSeconds before more are suspicious
+	WindowSeconds int `json:"window_seconds,omitempty"`
+	MaxCount      int `json:"max_count,omitempty"`
+}
+
+// Window returns the rapid succession window
+func (r RuleConfig) Window() time.Duration {
+	return time.Duration(r.WindowSeconds) * time.Second
+}
+
+// Config is the entity that represents the enabled rules and the scores at which payments are reviewed or
+// blocked. Rules missing from the configuration are disabled.
+type Config struct {
+	ReviewScore int                     `json:"review_score"`
+	BlockScore  int                     `json:"block_score"`
+	Rules       map[RuleName]RuleConfig `json:"rules"`
+}
+
+// DefaultConfig returns the rules used when no configuration file is given
+func DefaultConfig() *Config {
+	return &Config{
+		ReviewScore: 50,
+		BlockScore:  80,
+		Rules: map[RuleName]RuleConfig{
+			RuleNewDevice:       {Score: 20},
+			RuleUnusualAmount:   {Score: 30, Multiplier: 3, MinHistory: 3},
+			RuleRapidSuccession: {Score: 40, WindowSeconds: 600, MaxCount: 5},
+			RuleNewBeneficiary:  {Score: 15},
+			RuleGeoMismatch:     {Score: 35},
+		},
+	}
+}
+
+// LoadConfig reads a fraud rules configuration from a JSON file
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+// validate checks that the thresholds are ordered and that every rule is known and usable
+func (c *Config) validate() error {
+	if c.ReviewScore <= 0 || c.BlockScore < c.ReviewScore {
+		return ErrInvalidConfig
+	}
+
+	for name, rule := range c.Rules {
+		switch name {
+		case RuleNewDevice, RuleNewBeneficiary, RuleGeoMismatch:
+		case RuleUnusualAmount:
+			if rule.Multiplier <= 1 {
+				return ErrInvalidConfig
+			}
+		case RuleRapidSuccession:
+			if rule.WindowSeconds <= 0 || rule.MaxCount <= 0 {
+				return ErrInvalidConfig
+			}
+		default:
+			return ErrInvalidConfig
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package fraud
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrAssessmentNotFound is the error returned when a transaction has no fraud assessment
+	ErrAssessmentNotFound = errors.New("fraud assessment not found")
+
+	// ErrFailedToSaveAssessment is the error returned when an assessment or profile cannot be saved
+	ErrFailedToSaveAssessment = errors.New("failed to save fraud assessment")
+)
+
+// MaxProfileAmounts is how many trusted amounts a profile keeps to judge unusual amounts
+const MaxProfileAmounts = 20
+
+// Database is the interface that wraps the basic fraud database operations.
+type Database interface {
+	// GetProfile gets the history of a subject, counting the payments attempted since the given time
+	GetProfile(subject string, since time.Time) (*Profile, error)
+
+	// RecordAttempt records a payment attempted by a subject
+	RecordAttempt(subject string, at time.Time) error
+
+	// LearnProfile adds the device, beneficiary, country and amount of a trusted payment to the subject's history
+	LearnProfile(signals Signals) error
+
+	// SaveAssessment saves an assessment, adding it to the review queue while it awaits review
+	SaveAssessment(assessment *Assessment) error
+
+	// GetAssessment gets the assessment of a transaction
+	GetAssessment(transactionID string) (*Assessment, error)
+
+	// GetReviewQueue gets the assessments awaiting review, oldest first
+	GetReviewQueue() ([]*Assessment, error)
+}
@@ -0,0 +1,129 @@
+package fraud
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrPaymentBlocked is the error returned when the fraud checks block a payment
+var ErrPaymentBlocked = errors.New("payment blocked by fraud checks")
+
+// Decision is the type that represents the outcome of a fraud assessment
+type Decision string
+
+const (
+	// DecisionAllow lets the payment go through
+	DecisionAllow Decision = "allow"
+
+	// DecisionReview holds the payment until an analyst approves or rejects it
+	DecisionReview Decision = "review"
+
+	// DecisionBlock fails the payment
+	DecisionBlock Decision = "block"
+)
+
+// ReviewStatus is the type that represents the progress of a manual review
+type ReviewStatus string
+
+const (
+	// ReviewStatusPending is the status of an assessment waiting in the review queue
+	ReviewStatusPending ReviewStatus = "pending"
+
+	// ReviewStatusApproved is the status of a held payment an analyst let through
+	ReviewStatusApproved ReviewStatus = "approved"
+
+	// ReviewStatusRejected is the status of a held payment an analyst failed
+	ReviewStatusRejected ReviewStatus = "rejected"
+)
+
+// Origin is the entity that represents where a payment request came from
+type Origin struct {
+	DeviceID  string `json:"device_id,omitempty"`
+	IPAddress string `json:"ip_address,omitempty"`
+
+	// Country is the country the payer declared (e.g. the billing country), as an ISO 3166 alpha-2 code
+	Country string `json:"country,omitempty"`
+}
+
+// Signals is the entity that represents what the rules know about a payment
+type Signals struct {
+	// Subject is whose history the payment is compared with, e.g. the payer's instrument
+	Subject string  `json:"subject"`
+	Amount  float32 `json:"amount"`
+
+	// Beneficiary is who receives the money, e.g. the merchant
+	Beneficiary string `json:"beneficiary"`
+	Origin
+
+	// IPCountry is the country the IP address is located in, empty when unknown
+	IPCountry string `json:"ip_country,omitempty"`
+}
+
+// Profile is the entity that represents the history of a subject the rules compare payments with
+type Profile struct {
+	Devices       []string `json:"devices"`
+	Beneficiaries []string `json:"beneficiaries"`
+	Countries     []string `json:"countries"`
+
+	// Amounts are the most recent trusted amounts, newest first
+	Amounts []float32 `json:"amounts"`
+
+	// Attempts is the number of payments attempted within the rapid succession window
+	Attempts int `json:"attempts"`
+}
+
+// Hit is the entity that represents a rule that matched a payment
+type Hit struct {
+	Rule   RuleName `json:"rule"`
+	Score  int      `json:"score"`
+	Reason string   `json:"reason"`
+}
+
+// Assessment is the entity that represents the fraud decision recorded for a transaction
+type Assessment struct {
+	TransactionID string   `json:"transaction_id"`
+	Signals       Signals  `json:"signals"`
+	Score         int      `json:"score"`
+	Decision      Decision `json:"decision"`
+	Hits          []Hit    `json:"hits"`
+
+	// ReviewStatus is set for the assessments that went to the review queue
+	ReviewStatus ReviewStatus `json:"review_status,omitempty"`
+	Reviewer     string       `json:"reviewer,omitempty"`
+	ReviewNote   string       `json:"review_note,omitempty"`
+	ReviewedAt   *time.Time   `json:"reviewed_at,omitempty"`
+	CreatedAt    time.Time    `json:"created_at"`
+}
+
+// NewAssessment records the decision of the rules for a transaction, queueing it for review when needed
+func NewAssessment(transactionID string, signals Signals, score int, decision Decision, hits []Hit) *Assessment {
+	a := &Assessment{
+		TransactionID: transactionID,
+		Signals:       signals,
+		Score:         score,
+		Decision:      decision,
+		Hits:          hits,
+		CreatedAt:     time.Now().UTC(),
+	}
+	if decision == DecisionReview {
+		a.ReviewStatus = ReviewStatusPending
+	}
+
+	return a
+}
+
+// IsAwaitingReview reports whether the assessment is in the review queue
+func (a *Assessment) IsAwaitingReview() bool {
+	return a.ReviewStatus == ReviewStatusPending
+}
+
+// IsApproved reports whether the payment may go through, by the rules or by an analyst
+func (a *Assessment) IsApproved() bool {
+	return a.Decision == DecisionAllow || a.ReviewStatus == ReviewStatusApproved
+}
+
+// GeoLocator is the interface that locates IP addresses
+type GeoLocator interface {
+	// Country returns the ISO 3166 alpha-2 code of the country the IP address is in, empty when unknown
+	Country(ip string) (string, error)
+}
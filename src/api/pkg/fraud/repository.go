@@ -0,0 +1,24 @@
+package fraud
+
+import "time"
+
+// Repository is the fraud repository interface
+type Repository interface {
+	// Profile returns the history of a subject, counting the payments attempted since the given time.
+	Profile(subject string, since time.Time) (*Profile, error)
+
+	// RecordAttempt records a payment attempted by a subject.
+	RecordAttempt(subject string, at time.Time) error
+
+	// Learn adds a trusted payment to the subject's history.
+	Learn(signals Signals) error
+
+	// Save saves an assessment.
+	Save(assessment *Assessment) error
+
+	// Find returns the assessment of a transaction.
+	Find(transactionID string) (*Assessment, error)
+
+	// ReviewQueue returns the assessments awaiting review, oldest first.
+	ReviewQueue() ([]*Assessment, error)
+}
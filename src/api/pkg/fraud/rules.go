@@ -0,0 +1,104 @@
+package fraud
+
+import "fmt"
+
+// Evaluate runs the enabled rules against a payment and the subject's profile, returning the total score,
+// the decision it leads to and the rules that matched
+func (c *Config) Evaluate(signals Signals, profile *Profile) (int, Decision, []Hit) {
+	var hits []Hit
+	score := 0
+	for _, name := range []RuleName{RuleNewDevice, RuleUnusualAmount, RuleRapidSuccession, RuleNewBeneficiary, RuleGeoMismatch} {
+		rule, ok := c.Rules[name]
+		if !ok {
+			continue
+		}
+
+		if reason := match(name, rule, signals, profile); reason != "" {
+			hits = append(hits, Hit{Rule: name, Score: rule.Score, Reason: reason})
+			score += rule.Score
+		}
+	}
+
+	return score, c.Decide(score), hits
+}
+
+// Decide returns the decision for a score
+func (c *Config) Decide(score int) Decision {
+	switch {
+	case score >= c.BlockScore:
+		return DecisionBlock
+	case score >= c.ReviewScore:
+		return DecisionReview
+	default:
+		return DecisionAllow
+	}
+}
+
+// match returns why a rule matches the payment, empty when it does not
+func match(name RuleName, rule RuleConfig, signals Signals, profile *Profile) string {
+	switch name {
+	case RuleNewDevice:
+		if signals.DeviceID != "" && !contains(profile.Devices, signals.DeviceID) {
+			return "first payment from device " + signals.DeviceID
+		}
+	case RuleUnusualAmount:
+		if len(profile.Amounts) < rule.MinHistory || len(profile.Amounts) == 0 {
+			return ""
+		}
+		if average := mean(profile.Amounts); signals.Amount > average*rule.Multiplier {
+			return fmt.Sprintf("%.2f is more than %.1f times the average of %.2f", signals.Amount, rule.Multiplier, average)
+		}
+	case RuleRapidSuccession:
+		if profile.Attempts >= rule.MaxCount {
+			return fmt.Sprintf("%d payments attempted in the last %s", profile.Attempts, rule.Window())
+		}
+	case RuleNewBeneficiary:
+		if signals.Beneficiary != "" && !contains(profile.Beneficiaries, signals.Beneficiary) {
+			return "first payment to " + signals.Beneficiary
+		}
+	case RuleGeoMismatch:
+		return geoMismatch(signals, profile)
+	}
+
+	return ""
+}
+
+// geoMismatch returns why the IP address's country does not fit the payer: it differs from the declared
+// country or, without one, from every country the payer has paid from
+func geoMismatch(signals Signals, profile *Profile) string {
+	if signals.IPCountry == "" {
+		return ""
+	}
+
+	if signals.Country != "" {
+		if signals.Country != signals.IPCountry {
+			return fmt.Sprintf("IP address in %s, payer declared %s", signals.IPCountry, signals.Country)
+		}
+		return ""
+	}
+
+	if len(profile.Countries) > 0 && !contains(profile.Countries, signals.IPCountry) {
+		return fmt.Sprintf("IP address in %s, payer has only paid from %v", signals.IPCountry, profile.Countries)
+	}
+
+	return ""
+}
+
+// contains reports whether the values include the value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// mean returns the average of the amounts
+func mean(amounts []float32) float32 {
+	var total float32
+	for _, amount := range amounts {
+		total += amount
+	}
+	return total / float32(len(amounts))
+}
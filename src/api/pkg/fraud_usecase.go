@@ -0,0 +1,157 @@
+package pkg
+
+import (
+	"errors"
+	"github.com/quabynah-bilson/quantia/pkg/fraud"
+	"log"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrNotAwaitingReview is the error returned when a decision is made on a payment that is not in the review queue.
+	ErrNotAwaitingReview = errors.New("the payment is not awaiting review")
+
+	// ErrInvalidReview is the error returned when a review does not say who made it.
+	ErrInvalidReview = errors.New("invalid review. Please provide the reviewer and try again")
+)
+
+// FraudUseCase is the fraud scoring use case. It contains the necessary repositories to score payments with
+// the configured rules, record the decisions and let analysts work through the review queue.
+type FraudUseCase struct {
+	fraudRepo fraud.Repository
+	config    *fraud.Config
+
+	// geo locates IP addresses for the geo mismatch rule. The rule never matches when it is nil.
+	geo fraud.GeoLocator
+
+	// reviewMu serializes the decisions of analysts
+	reviewMu sync.Mutex
+
+	// onReviewed resumes the payments analysts have decided on
+	onReviewed func(assessment *fraud.Assessment) error
+}
+
+// NewFraudUseCase creates a new fraud use case.
+func NewFraudUseCase(fraudRepo fraud.Repository, config *fraud.Config, geo fraud.GeoLocator) *FraudUseCase {
+	return &FraudUseCase{
+		fraudRepo: fraudRepo,
+		config:    config,
+		geo:       geo,
+	}
+}
+
+// OnReviewed registers the handler that completes or fails held payments once an analyst has decided on them.
+func (uc *FraudUseCase) OnReviewed(handler func(assessment *fraud.Assessment) error) {
+	uc.onReviewed = handler
+}
+
+// Assess scores a payment against the subject's history and records the decision for the transaction.
+// Allowed payments are added to the history; held payments are once an analyst approves them.
+func (uc *FraudUseCase) Assess(transactionID string, signals fraud.Signals) (*fraud.Assessment, error) {
+	now := time.Now()
+	if uc.geo != nil && signals.IPAddress != "" {
+		country, err := uc.geo.Country(signals.IPAddress)
+		if err != nil {
+			log.Printf("error locating %s: %v", signals.IPAddress, err)
+		}
+		signals.IPCountry = country
+	}
+
+	window := uc.config.Rules[fraud.RuleRapidSuccession].Window()
+	profile, err := uc.fraudRepo.Profile(signals.Subject, now.Add(-window))
+	if err != nil {
+		log.Printf("error getting fraud profile of %s: %v", signals.Subject, err)
+		return nil, err
+	}
+
+	if err = uc.fraudRepo.RecordAttempt(signals.Subject, now); err != nil {
+		log.Printf("error recording payment attempt of %s: %v", signals.Subject, err)
+	}
+
+	score, decision, hits := uc.config.Evaluate(signals, profile)
+	assessment := fraud.NewAssessment(transactionID, signals, score, decision, hits)
+	if err = uc.fraudRepo.Save(assessment); err != nil {
+		log.Printf("error saving fraud assessment of %s: %v", transactionID, err)
+		return nil, err
+	}
+
+	if decision == fraud.DecisionAllow {
+		uc.learn(assessment)
+	}
+
+	return assessment, nil
+}
+
+// GetAssessment gets the fraud assessment of a transaction.
+func (uc *FraudUseCase) GetAssessment(transactionID string) (*fraud.Assessment, error) {
+	return uc.fraudRepo.Find(transactionID)
+}
+
+// GetReviewQueue gets the assessments of the payments held for review, oldest first.
+func (uc *FraudUseCase) GetReviewQueue() ([]*fraud.Assessment, error) {
+	return uc.fraudRepo.ReviewQueue()
+}
+
+// Review records an analyst's decision on a held payment and resumes it: approved payments go on to the
+// provider, rejected payments fail.
+func (uc *FraudUseCase) Review(transactionID string, approve bool, reviewer, note string) (*fraud.Assessment, error) {
+	if reviewer == "" {
+		return nil, ErrInvalidReview
+	}
+
+	assessment, err := uc.review(transactionID, approve, reviewer, note)
+	if err != nil {
+		return nil, err
+	}
+
+	if uc.onReviewed != nil {
+		if err = uc.onReviewed(assessment); err != nil {
+			log.Printf("error resuming reviewed payment %s: %v", transactionID, err)
+			return assessment, err
+		}
+	}
+
+	return assessment, nil
+}
+
+// review moves an assessment out of the review queue.
+func (uc *FraudUseCase) review(transactionID string, approve bool, reviewer, note string) (*fraud.Assessment, error) {
+	uc.reviewMu.Lock()
+	defer uc.reviewMu.Unlock()
+
+	assessment, err := uc.fraudRepo.Find(transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !assessment.IsAwaitingReview() {
+		return nil, ErrNotAwaitingReview
+	}
+
+	now := time.Now().UTC()
+	assessment.ReviewStatus = fraud.ReviewStatusRejected
+	if approve {
+		assessment.ReviewStatus = fraud.ReviewStatusApproved
+	}
+	assessment.Reviewer, assessment.ReviewNote, assessment.ReviewedAt = reviewer, note, &now
+
+	if err = uc.fraudRepo.Save(assessment); err != nil {
+		log.Printf("error saving review of %s: %v", transactionID, err)
+		return nil, err
+	}
+
+	if approve {
+		uc.learn(assessment)
+	}
+
+	return assessment, nil
+}
+
+// learn adds a trusted payment to its subject's history. Failures are logged, not returned, because the
+// decision has already been recorded.
+func (uc *FraudUseCase) learn(assessment *fraud.Assessment) {
+	if err := uc.fraudRepo.Learn(assessment.Signals); err != nil {
+		log.Printf("error learning from payment %s: %v", assessment.TransactionID, err)
+	}
+}
@@ -0,0 +1,41 @@
+package invoice
+
+import "errors"
+
+var (
+	// ErrInvoiceNotFound is the error returned when an invoice does not exist
+	ErrInvoiceNotFound = errors.New("invoice not found")
+
+	// ErrLinkNotFound is the error returned when a payment link does not exist
+	ErrLinkNotFound = errors.New("payment link not found")
+
+	// ErrPaymentNotFound is the error returned when a transaction was not made through a payment link
+	ErrPaymentNotFound = errors.New("payment link payment not found")
+
+	// ErrFailedToSaveInvoice is the error returned when an invoice, a payment link or one of its payments cannot be stored
+	ErrFailedToSaveInvoice = errors.New("failed to save invoice. Please try again")
+)
+
+// Database is the interface that wraps the basic invoice and payment link database operations.
+type Database interface {
+	// SaveInvoice creates or replaces an invoice
+	SaveInvoice(invoice *Invoice) error
+
+	// GetInvoice gets an invoice by ID
+	GetInvoice(id string) (*Invoice, error)
+
+	// SaveLink creates or replaces a payment link
+	SaveLink(link *Link) error
+
+	// GetLink gets a payment link by ID
+	GetLink(id string) (*Link, error)
+
+	// SavePayment creates or replaces the record of a payment made through a link
+	SavePayment(payment *Payment) error
+
+	// GetPayment gets the record of a payment by its transaction ID
+	GetPayment(transactionID string) (*Payment, error)
+
+	// GetLinkPayments gets the payments made through a link, oldest first
+	GetLinkPayments(linkID string) ([]*Payment, error)
+}
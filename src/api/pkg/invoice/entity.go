@@ -0,0 +1,208 @@
+package invoice
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"math"
+	"time"
+)
+
+var (
+	// ErrInvoiceNotPayable is the error returned when an invoice is paid, void or already being paid
+	ErrInvoiceNotPayable = errors.New("the invoice cannot be paid in its current state")
+
+	// ErrLinkExpired is the error returned when a payment link is used after its expiry
+	ErrLinkExpired = errors.New("the payment link has expired")
+
+	// ErrLinkNotActive is the error returned when a payment link is disabled, used up or already being paid
+	ErrLinkNotActive = errors.New("the payment link can no longer be used")
+)
+
+// Status is the type that represents the status of an invoice
+type Status string
+
+const (
+	// StatusOpen is the status of an invoice waiting for its payment
+	StatusOpen Status = "open"
+
+	// StatusProcessing is the status of an invoice whose payment is waiting for the provider or a review
+	StatusProcessing Status = "processing"
+
+	// StatusPaid is the status of an invoice whose payment succeeded
+	StatusPaid Status = "paid"
+
+	// StatusVoid is the status of an invoice cancelled by its merchant
+	StatusVoid Status = "void"
+)
+
+// LineItem is a line of an invoice. Its amount is the quantity times the unit price.
+type LineItem struct {
+	Description string  `json:"description"`
+	Quantity    int     `json:"quantity"`
+	UnitPrice   float32 `json:"unit_price"`
+	Amount      float32 `json:"amount"`
+}
+
+// Invoice is the entity that represents a merchant's request for money
+type Invoice struct {
+	ID string `json:"id"`
+
+	// Url is the merchant endpoint of the payment, notified when the invoice is paid
+	Url         string     `json:"url"`
+	Description string     `json:"description,omitempty"`
+	Currency    string     `json:"currency"`
+	LineItems   []LineItem `json:"line_items"`
+
+	// Amount is the total of the line items
+	Amount  float32   `json:"amount"`
+	DueDate time.Time `json:"due_date"`
+	Status  Status    `json:"status"`
+
+	// TransactionID is the payment that paid the invoice
+	TransactionID string     `json:"transaction_id,omitempty"`
+	PaidAt        *time.Time `json:"paid_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// NewInvoice creates an open invoice, working out the amount of each line and the total
+func NewInvoice(url, description, currency string, items []LineItem, dueDate time.Time) *Invoice {
+	inv := &Invoice{
+		ID:          "inv_" + uuid.NewString(),
+		Url:         url,
+		Description: description,
+		Currency:    currency,
+		LineItems:   make([]LineItem, 0, len(items)),
+		DueDate:     dueDate.UTC(),
+		Status:      StatusOpen,
+		CreatedAt:   time.Now().UTC(),
+	}
+
+	// the totals are worked out in minor units so that the lines add up to the invoice amount
+	var total int64
+	for _, item := range items {
+		amount := int64(item.Quantity) * toMinorUnits(item.UnitPrice)
+		item.Amount = fromMinorUnits(amount)
+		inv.LineItems = append(inv.LineItems, item)
+		total += amount
+	}
+	inv.Amount = fromMinorUnits(total)
+
+	return inv
+}
+
+// IsOverdue reports whether the invoice is still unpaid at the given time after its due date
+func (i *Invoice) IsOverdue(now time.Time) bool {
+	return (i.Status == StatusOpen || i.Status == StatusProcessing) && now.After(i.DueDate)
+}
+
+// LinkStatus is the type that represents the status of a payment link
+type LinkStatus string
+
+const (
+	// LinkStatusActive is the status of a payment link that can be paid
+	LinkStatusActive LinkStatus = "active"
+
+	// LinkStatusProcessing is the status of a single-use link whose payment is waiting for the provider or a review
+	LinkStatusProcessing LinkStatus = "processing"
+
+	// LinkStatusUsed is the status of a single-use link whose payment succeeded
+	LinkStatusUsed LinkStatus = "used"
+
+	// LinkStatusDisabled is the status of a payment link turned off by its merchant
+	LinkStatusDisabled LinkStatus = "disabled"
+)
+
+// Link is the entity that represents a shareable payment link. A link either pays an invoice or asks for
+// a fixed amount of its own; only links without an invoice can be paid more than once.
+type Link struct {
+	ID string `json:"id"`
+
+	// InvoiceID is the invoice paid through the link, if any
+	InvoiceID string `json:"invoice_id,omitempty"`
+
+	// Url is the merchant endpoint of the payments, notified when the link is paid
+	Url         string  `json:"url"`
+	Description string  `json:"description,omitempty"`
+	Amount      float32 `json:"amount"`
+	Currency    string  `json:"currency"`
+	MultiUse    bool    `json:"multi_use"`
+
+	// ExpiresAt is the time after which the link cannot be paid, if set
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Status    LinkStatus `json:"status"`
+
+	// Payments is the number of successful payments made through the link
+	Payments  int       `json:"payments"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewLink creates an active payment link for a fixed amount
+func NewLink(url, description string, amount float32, currency string, multiUse bool, expiresAt *time.Time) *Link {
+	return &Link{
+		ID:          "lnk_" + uuid.NewString(),
+		Url:         url,
+		Description: description,
+		Amount:      amount,
+		Currency:    currency,
+		MultiUse:    multiUse,
+		ExpiresAt:   expiresAt,
+		Status:      LinkStatusActive,
+		CreatedAt:   time.Now().UTC(),
+	}
+}
+
+// NewInvoiceLink creates an active, single-use payment link for the invoice
+func NewInvoiceLink(inv *Invoice, expiresAt *time.Time) *Link {
+	link := NewLink(inv.Url, inv.Description, inv.Amount, inv.Currency, false, expiresAt)
+	link.InvoiceID = inv.ID
+
+	return link
+}
+
+// CheckPayable returns the reason the link cannot be paid at the given time, if any
+func (l *Link) CheckPayable(now time.Time) error {
+	if l.Status != LinkStatusActive {
+		return ErrLinkNotActive
+	}
+
+	if l.ExpiresAt != nil && !now.Before(*l.ExpiresAt) {
+		return ErrLinkExpired
+	}
+
+	return nil
+}
+
+// PaymentStatus is the type that represents the status of a payment made through a link
+type PaymentStatus string
+
+const (
+	// PaymentStatusPending is the status of a payment waiting for the provider or a review
+	PaymentStatusPending PaymentStatus = "pending"
+
+	// PaymentStatusSucceeded is the status of a payment that succeeded
+	PaymentStatusSucceeded PaymentStatus = "succeeded"
+
+	// PaymentStatusFailed is the status of a payment that was declined, blocked or failed
+	PaymentStatusFailed PaymentStatus = "failed"
+)
+
+// Payment is the entity that records a payment made through a link
+type Payment struct {
+	TransactionID string        `json:"transaction_id"`
+	LinkID        string        `json:"link_id"`
+	InvoiceID     string        `json:"invoice_id,omitempty"`
+	Amount        float32       `json:"amount"`
+	Status        PaymentStatus `json:"status"`
+	CreatedAt     time.Time     `json:"created_at"`
+	CompletedAt   *time.Time    `json:"completed_at,omitempty"`
+}
+
+// toMinorUnits converts an amount to cents
+func toMinorUnits(amount float32) int64 {
+	return int64(math.Round(float64(amount) * 100))
+}
+
+// fromMinorUnits converts cents to an amount
+func fromMinorUnits(amount int64) float32 {
+	return float32(amount) / 100
+}
@@ -0,0 +1,25 @@
+package invoice
+
+// Repository is the invoice and payment link repository interface
+type Repository interface {
+	// Save creates or replaces an invoice.
+	Save(invoice *Invoice) error
+
+	// Find gets an invoice by ID.
+	Find(id string) (*Invoice, error)
+
+	// SaveLink creates or replaces a payment link.
+	SaveLink(link *Link) error
+
+	// FindLink gets a payment link by ID.
+	FindLink(id string) (*Link, error)
+
+	// Record creates or replaces the record of a payment made through a link.
+	Record(payment *Payment) error
+
+	// FindPayment gets the record of a payment by its transaction ID.
+	FindPayment(transactionID string) (*Payment, error)
+
+	// Payments returns the payments made through a link, oldest first.
+	Payments(linkID string) ([]*Payment, error)
+}
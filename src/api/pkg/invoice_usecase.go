@@ -0,0 +1,444 @@
+package pkg
+
+import (
+	"errors"
+	"github.com/quabynah-bilson/quantia/pkg/event"
+	"github.com/quabynah-bilson/quantia/pkg/fraud"
+	"github.com/quabynah-bilson/quantia/pkg/invoice"
+	"github.com/quabynah-bilson/quantia/pkg/payment"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrInvalidInvoice is the error returned when an invoice definition is invalid.
+	ErrInvalidInvoice = errors.New("invalid invoice. Please check the currency, line items and due date")
+
+	// ErrInvalidLink is the error returned when a payment link definition is invalid.
+	ErrInvalidLink = errors.New("invalid payment link. Please check the amount, currency and expiry")
+
+	// ErrInvalidInvoiceState is the error returned when an invoice or a payment link cannot be changed in its current state.
+	ErrInvalidInvoiceState = errors.New("the invoice cannot be changed in its current state")
+)
+
+// currencyPattern matches ISO 4217 currency codes.
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// InvoiceConfig is the configuration of the invoice use case
+type InvoiceConfig struct {
+	// Currencies are the ISO 4217 codes invoices and links may be issued in. Any code is accepted when empty.
+	Currencies []string
+}
+
+// InvoiceUseCase is the invoice use case. It lets merchants request money with invoices and payment links,
+// which payers pay through the payment use case.
+type InvoiceUseCase struct {
+	invoiceRepo invoice.Repository
+	payments    *PaymentUseCase
+	config      InvoiceConfig
+
+	// mu serializes the changes made to invoices and links by merchants, payers and payment results
+	mu sync.Mutex
+}
+
+// NewInvoiceUseCase creates a new invoice use case. Invoices and links are settled as their payments complete.
+func NewInvoiceUseCase(invoiceRepo invoice.Repository, payments *PaymentUseCase, config InvoiceConfig) *InvoiceUseCase {
+	uc := &InvoiceUseCase{
+		invoiceRepo: invoiceRepo,
+		payments:    payments,
+		config:      config,
+	}
+	payments.OnCompleted(uc.handlePayment)
+
+	return uc
+}
+
+// CreateInvoice creates an open invoice to the merchant at the URL. The due date may not be in the past.
+func (uc *InvoiceUseCase) CreateInvoice(url, description, currency string, items []invoice.LineItem, dueDate time.Time) (*invoice.Invoice, error) {
+	if err := uc.payments.checkURL(url); err != nil {
+		return nil, err
+	}
+
+	currency, ok := uc.currency(currency)
+	if !ok || len(items) == 0 || dueDate.Before(time.Now().UTC().Truncate(24*time.Hour)) {
+		return nil, ErrInvalidInvoice
+	}
+
+	for i, item := range items {
+		items[i].Description = strings.TrimSpace(item.Description)
+		if items[i].Description == "" || item.Quantity <= 0 || item.UnitPrice <= 0 {
+			return nil, ErrInvalidInvoice
+		}
+	}
+
+	inv := invoice.NewInvoice(url, strings.TrimSpace(description), currency, items, dueDate)
+	if err := uc.invoiceRepo.Save(inv); err != nil {
+		log.Printf("error saving invoice: %v", err)
+		return nil, err
+	}
+
+	return inv, nil
+}
+
+// GetInvoice gets an invoice by ID.
+func (uc *InvoiceUseCase) GetInvoice(id string) (*invoice.Invoice, error) {
+	return uc.invoiceRepo.Find(id)
+}
+
+// VoidInvoice cancels an open invoice so that it can no longer be paid.
+func (uc *InvoiceUseCase) VoidInvoice(id string) (*invoice.Invoice, error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	inv, err := uc.invoiceRepo.Find(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if inv.Status != invoice.StatusOpen {
+		return nil, ErrInvalidInvoiceState
+	}
+
+	inv.Status = invoice.StatusVoid
+	if err = uc.invoiceRepo.Save(inv); err != nil {
+		log.Printf("error saving invoice %s: %v", inv.ID, err)
+		return nil, err
+	}
+
+	return inv, nil
+}
+
+// CreateInvoiceLink creates a single-use payment link for an open invoice, valid until expiresAt when it is set.
+func (uc *InvoiceUseCase) CreateInvoiceLink(invoiceID string, expiresAt *time.Time) (*invoice.Link, error) {
+	inv, err := uc.invoiceRepo.Find(invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if inv.Status != invoice.StatusOpen {
+		return nil, ErrInvalidInvoiceState
+	}
+
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, ErrInvalidLink
+	}
+
+	link := invoice.NewInvoiceLink(inv, expiresAt)
+	if err = uc.invoiceRepo.SaveLink(link); err != nil {
+		log.Printf("error saving payment link: %v", err)
+		return nil, err
+	}
+
+	return link, nil
+}
+
+// CreateLink creates a payment link for a fixed amount to the merchant at the URL. Multi-use links can be
+// paid any number of times until they expire or are disabled.
+func (uc *InvoiceUseCase) CreateLink(url, description string, amount float32, currency string, multiUse bool, expiresAt *time.Time) (*invoice.Link, error) {
+	if err := validateAmount(amount); err != nil {
+		log.Printf("error validating amount: %v", err)
+		return nil, err
+	}
+
+	if err := uc.payments.checkURL(url); err != nil {
+		return nil, err
+	}
+
+	currency, ok := uc.currency(currency)
+	if !ok || (expiresAt != nil && !expiresAt.After(time.Now())) {
+		return nil, ErrInvalidLink
+	}
+
+	link := invoice.NewLink(url, strings.TrimSpace(description), amount, currency, multiUse, expiresAt)
+	if err := uc.invoiceRepo.SaveLink(link); err != nil {
+		log.Printf("error saving payment link: %v", err)
+		return nil, err
+	}
+
+	return link, nil
+}
+
+// GetLink gets a payment link by ID.
+func (uc *InvoiceUseCase) GetLink(id string) (*invoice.Link, error) {
+	return uc.invoiceRepo.FindLink(id)
+}
+
+// GetLinkPayments gets the payments made through a link, oldest first.
+func (uc *InvoiceUseCase) GetLinkPayments(id string) ([]*invoice.Payment, error) {
+	if _, err := uc.invoiceRepo.FindLink(id); err != nil {
+		return nil, err
+	}
+
+	return uc.invoiceRepo.Payments(id)
+}
+
+// DisableLink turns off an active payment link.
+func (uc *InvoiceUseCase) DisableLink(id string) (*invoice.Link, error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	link, err := uc.invoiceRepo.FindLink(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if link.Status != invoice.LinkStatusActive {
+		return nil, invoice.ErrLinkNotActive
+	}
+
+	link.Status = invoice.LinkStatusDisabled
+	if err = uc.invoiceRepo.SaveLink(link); err != nil {
+		log.Printf("error saving payment link %s: %v", link.ID, err)
+		return nil, err
+	}
+
+	return link, nil
+}
+
+// PayLink pays a payment link from the given source. A single-use link, and the invoice it pays, cannot be
+// paid again while the payment is pending; they are paid once it succeeds and open again if it fails.
+// The merchant is notified when the payment succeeds.
+func (uc *InvoiceUseCase) PayLink(id, source string, origin *fraud.Origin) (*payment.Transaction, error) {
+	now := time.Now()
+	link, err := uc.reserve(id, now)
+	if err != nil {
+		return nil, err
+	}
+
+	transaction, err := uc.payments.MakePayment(link.Amount, link.Url, source, origin)
+	if transaction == nil {
+		// nothing was charged, so the link can be paid again
+		uc.release(link)
+		return nil, err
+	}
+
+	p := &invoice.Payment{
+		TransactionID: transaction.ID,
+		LinkID:        link.ID,
+		InvoiceID:     link.InvoiceID,
+		Amount:        transaction.Amount,
+		Status:        invoice.PaymentStatusPending,
+		CreatedAt:     now.UTC(),
+	}
+	if recordErr := uc.invoiceRepo.Record(p); recordErr != nil {
+		log.Printf("error recording payment of link %s: %v", link.ID, recordErr)
+	}
+
+	// the payment may have completed before it was recorded, in which case no result will follow
+	if stored, findErr := uc.payments.paymentRepo.Find(transaction.ID); findErr == nil {
+		transaction = stored
+	}
+	uc.settle(p, transaction)
+
+	return transaction, err
+}
+
+// handlePayment settles the link and invoice of a payment that succeeded or failed. Payments that were not
+// made through a link are ignored.
+func (uc *InvoiceUseCase) handlePayment(transaction *payment.Transaction) {
+	p, err := uc.invoiceRepo.FindPayment(transaction.ID)
+	if err != nil {
+		return
+	}
+
+	uc.settle(p, transaction)
+}
+
+// reserve checks that a link can be paid and, for single-use links and invoices, holds it for one payment.
+func (uc *InvoiceUseCase) reserve(id string, now time.Time) (*invoice.Link, error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	link, err := uc.invoiceRepo.FindLink(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = link.CheckPayable(now); err != nil {
+		return nil, err
+	}
+
+	if link.InvoiceID != "" {
+		inv, err := uc.invoiceRepo.Find(link.InvoiceID)
+		if err != nil {
+			return nil, err
+		}
+
+		if inv.Status != invoice.StatusOpen {
+			return nil, invoice.ErrInvoiceNotPayable
+		}
+
+		inv.Status = invoice.StatusProcessing
+		if err = uc.invoiceRepo.Save(inv); err != nil {
+			log.Printf("error saving invoice %s: %v", inv.ID, err)
+			return nil, err
+		}
+	}
+
+	if !link.MultiUse {
+		link.Status = invoice.LinkStatusProcessing
+		if err = uc.invoiceRepo.SaveLink(link); err != nil {
+			log.Printf("error saving payment link %s: %v", link.ID, err)
+			uc.reopen(link.InvoiceID)
+			return nil, err
+		}
+	}
+
+	return link, nil
+}
+
+// release gives back a link, and its invoice, reserved for a payment that was never made.
+func (uc *InvoiceUseCase) release(link *invoice.Link) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	uc.reactivate(link.ID)
+	uc.reopen(link.InvoiceID)
+}
+
+// settle records the outcome of a payment made through a link: the link counts the payment and the invoice
+// is paid when it succeeds, and both can be paid again when it fails. Each payment is settled once.
+func (uc *InvoiceUseCase) settle(p *invoice.Payment, transaction *payment.Transaction) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	if stored, err := uc.invoiceRepo.FindPayment(p.TransactionID); err == nil {
+		p = stored
+	}
+	if p.Status != invoice.PaymentStatusPending {
+		return
+	}
+
+	switch transaction.Status {
+	case payment.TransactionStatusSuccess:
+		p.Status = invoice.PaymentStatusSucceeded
+	case payment.TransactionStatusFailed:
+		p.Status = invoice.PaymentStatusFailed
+	default:
+		// still pending at the provider or held for review
+		return
+	}
+
+	now := time.Now().UTC()
+	p.CompletedAt = &now
+	if err := uc.invoiceRepo.Record(p); err != nil {
+		log.Printf("error recording payment of link %s: %v", p.LinkID, err)
+	}
+
+	if p.Status == invoice.PaymentStatusFailed {
+		uc.reactivate(p.LinkID)
+		uc.reopen(p.InvoiceID)
+		return
+	}
+
+	link, err := uc.invoiceRepo.FindLink(p.LinkID)
+	if err != nil {
+		log.Printf("error finding payment link %s: %v", p.LinkID, err)
+		return
+	}
+
+	link.Payments++
+	if !link.MultiUse {
+		link.Status = invoice.LinkStatusUsed
+	}
+	if err = uc.invoiceRepo.SaveLink(link); err != nil {
+		log.Printf("error saving payment link %s: %v", link.ID, err)
+	}
+
+	if p.InvoiceID == "" {
+		uc.notify(link.Url, event.TypePaymentLinkPaid, &event.PaymentLinkData{
+			LinkID:        link.ID,
+			TransactionID: p.TransactionID,
+			Amount:        p.Amount,
+			Currency:      link.Currency,
+		})
+		return
+	}
+
+	inv, err := uc.invoiceRepo.Find(p.InvoiceID)
+	if err != nil {
+		log.Printf("error finding invoice %s: %v", p.InvoiceID, err)
+		return
+	}
+
+	inv.Status, inv.TransactionID, inv.PaidAt = invoice.StatusPaid, p.TransactionID, &now
+	if err = uc.invoiceRepo.Save(inv); err != nil {
+		log.Printf("error saving invoice %s: %v", inv.ID, err)
+	}
+
+	uc.notify(inv.Url, event.TypeInvoicePaid, &event.InvoiceData{
+		InvoiceID:     inv.ID,
+		TransactionID: p.TransactionID,
+		LinkID:        link.ID,
+		Amount:        inv.Amount,
+		Currency:      inv.Currency,
+		Status:        string(inv.Status),
+	})
+}
+
+// reactivate makes a single-use link held for a payment payable again.
+func (uc *InvoiceUseCase) reactivate(linkID string) {
+	link, err := uc.invoiceRepo.FindLink(linkID)
+	if err != nil || link.Status != invoice.LinkStatusProcessing {
+		return
+	}
+
+	link.Status = invoice.LinkStatusActive
+	if err = uc.invoiceRepo.SaveLink(link); err != nil {
+		log.Printf("error saving payment link %s: %v", link.ID, err)
+	}
+}
+
+// reopen makes an invoice held for a payment payable again.
+func (uc *InvoiceUseCase) reopen(invoiceID string) {
+	if invoiceID == "" {
+		return
+	}
+
+	inv, err := uc.invoiceRepo.Find(invoiceID)
+	if err != nil || inv.Status != invoice.StatusProcessing {
+		return
+	}
+
+	inv.Status = invoice.StatusOpen
+	if err = uc.invoiceRepo.Save(inv); err != nil {
+		log.Printf("error saving invoice %s: %v", inv.ID, err)
+	}
+}
+
+// notify queues an event for the merchant. Failures are logged, not returned, because the payment has
+// already been recorded.
+func (uc *InvoiceUseCase) notify(url string, eventType event.Type, data interface{}) {
+	envelope, err := event.New(eventType, data)
+	if err != nil {
+		log.Printf("error creating %s event: %v", eventType, err)
+		return
+	}
+
+	if err = uc.payments.paymentRepo.Notify(url, envelope); err != nil {
+		log.Printf("error queueing %s event: %v", eventType, err)
+	}
+}
+
+// currency normalizes a currency code and reports whether invoices may be issued in it.
+func (uc *InvoiceUseCase) currency(code string) (string, bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if !currencyPattern.MatchString(code) {
+		return "", false
+	}
+
+	if len(uc.config.Currencies) == 0 {
+		return code, true
+	}
+
+	for _, allowed := range uc.config.Currencies {
+		if strings.EqualFold(allowed, code) {
+			return code, true
+		}
+	}
+
+	return "", false
+}
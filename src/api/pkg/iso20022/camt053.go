@@ -0,0 +1,339 @@
+package iso20022
+
+import (
+	"encoding/xml"
+	"github.com/quabynah-bilson/quantia/pkg/reconciliation"
+	"strings"
+	"time"
+)
+
+// Camt053Namespace is the namespace of the camt.053 statements we read (version 02, the one partner banks send)
+const Camt053Namespace = "urn:iso:std:iso:20022:tech:xsd:camt.053.001.02"
+
+const (
+	// IndicatorCredit and IndicatorDebit tell whether an entry or balance is a credit or a debit
+	IndicatorCredit = "CRDT"
+	IndicatorDebit  = "DBIT"
+
+	// StatusBooked is the status of the entries booked on the account; pending and informational entries are not
+	StatusBooked = "BOOK"
+
+	// notProvided is the value banks give to references the initiator did not provide
+	notProvided = "NOTPROVIDED"
+)
+
+// Camt053Document is a bank-to-customer statement: the entries booked on our accounts at a partner bank
+type Camt053Document struct {
+	XMLName   xml.Name                `xml:"urn:iso:std:iso:20022:tech:xsd:camt.053.001.02 Document"`
+	Statement BankToCustomerStatement `xml:"BkToCstmrStmt"`
+}
+
+// BankToCustomerStatement is the body of a camt.053 message
+type BankToCustomerStatement struct {
+	GroupHeader StatementGroupHeader `xml:"GrpHdr"`
+	Statements  []AccountStatement   `xml:"Stmt"`
+}
+
+// StatementGroupHeader identifies a camt.053 message
+type StatementGroupHeader struct {
+	MessageID        string `xml:"MsgId"`
+	CreationDateTime string `xml:"CreDtTm"`
+}
+
+// AccountStatement is the statement of one account
+type AccountStatement struct {
+	ID               string      `xml:"Id"`
+	CreationDateTime string      `xml:"CreDtTm"`
+	Account          CashAccount `xml:"Acct"`
+	Balances         []Balance   `xml:"Bal"`
+	Entries          []Entry     `xml:"Ntry"`
+}
+
+// Balance is a balance of the account, e.g. the opening (OPBD) or closing (CLBD) booked balance
+type Balance struct {
+	Type      string     `xml:"Tp>CdOrPrtry>Cd"`
+	Amount    Amount     `xml:"Amt"`
+	Indicator string     `xml:"CdtDbtInd"`
+	Date      DateChoice `xml:"Dt"`
+}
+
+// DateChoice is a date or a date and time
+type DateChoice struct {
+	Date     string `xml:"Dt,omitempty"`
+	DateTime string `xml:"DtTm,omitempty"`
+}
+
+// Entry is an amount credited or debited to the account
+type Entry struct {
+	Reference         string               `xml:"NtryRef,omitempty"`
+	Amount            Amount               `xml:"Amt"`
+	Indicator         string               `xml:"CdtDbtInd"`
+	Status            string               `xml:"Sts"`
+	BookingDate       *DateChoice          `xml:"BookgDt,omitempty"`
+	ValueDate         *DateChoice          `xml:"ValDt,omitempty"`
+	ServicerReference string               `xml:"AcctSvcrRef,omitempty"`
+	TransactionCode   BankTransactionCode  `xml:"BkTxCd"`
+	Details           []TransactionDetails `xml:"NtryDtls>TxDtls,omitempty"`
+	AdditionalInfo    string               `xml:"AddtlNtryInf,omitempty"`
+}
+
+// BankTransactionCode classifies an entry, by the ISO domain codes or by the bank's own code
+type BankTransactionCode struct {
+	Domain      *TransactionDomain `xml:"Domn,omitempty"`
+	Proprietary *ProprietaryCode   `xml:"Prtry,omitempty"`
+}
+
+// TransactionDomain is the ISO classification of an entry, e.g. PMNT / RCDT / ESCT for a received transfer
+type TransactionDomain struct {
+	Code          string `xml:"Cd"`
+	Family        string `xml:"Fmly>Cd"`
+	SubFamilyCode string `xml:"Fmly>SubFmlyCd"`
+}
+
+// ProprietaryCode is the bank's own classification of an entry
+type ProprietaryCode struct {
+	Code string `xml:"Cd"`
+}
+
+// TransactionDetails are the references and remittance information of a transaction in an entry
+type TransactionDetails struct {
+	References *TransactionReferences `xml:"Refs,omitempty"`
+	Remittance []string               `xml:"RmtInf>Ustrd,omitempty"`
+}
+
+// TransactionReferences are the references given to a transaction by its initiator and the bank
+type TransactionReferences struct {
+	MessageID         string `xml:"MsgId,omitempty"`
+	ServicerReference string `xml:"AcctSvcrRef,omitempty"`
+	EndToEndID        string `xml:"EndToEndId,omitempty"`
+}
+
+// ParseCamt053 reads a camt.053 statement and validates it
+func ParseCamt053(data []byte) (*Camt053Document, error) {
+	var doc Camt053Document
+	if err := unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	return &doc, nil
+}
+
+// Lines returns the booked entries of the statements as reconciliation lines. Each entry is matched by the
+// end-to-end reference of its transaction, or the bank's references when the initiator gave none. Debits
+// are negative.
+func (d *Camt053Document) Lines() []reconciliation.Line {
+	var lines []reconciliation.Line
+	for _, statement := range d.Statement.Statements {
+		for _, entry := range statement.Entries {
+			if entry.Status != StatusBooked {
+				continue
+			}
+
+			amount := entry.Amount.Float()
+			if entry.Indicator == IndicatorDebit {
+				amount = -amount
+			}
+
+			lines = append(lines, reconciliation.Line{
+				Reference:   entry.reference(),
+				Amount:      amount,
+				Date:        entry.date(),
+				Description: entry.description(),
+			})
+		}
+	}
+
+	return lines
+}
+
+// Validate checks the statement against the camt.053.001.02 schema
+func (d *Camt053Document) Validate() error {
+	header := d.Statement.GroupHeader
+	if err := validateText("GrpHdr/MsgId", header.MessageID, maxTextLength); err != nil {
+		return err
+	}
+	if err := validateDateTime("GrpHdr/CreDtTm", header.CreationDateTime); err != nil {
+		return err
+	}
+
+	if len(d.Statement.Statements) == 0 {
+		return &ValidationError{Element: "Stmt", Reason: "is required"}
+	}
+
+	for _, statement := range d.Statement.Statements {
+		if err := statement.validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validate checks the statement of an account
+func (s AccountStatement) validate() error {
+	if err := validateText("Stmt/Id", s.ID, maxTextLength); err != nil {
+		return err
+	}
+	if err := validateDateTime("Stmt/CreDtTm", s.CreationDateTime); err != nil {
+		return err
+	}
+	if err := s.Account.validate("Stmt/Acct"); err != nil {
+		return err
+	}
+
+	if len(s.Balances) == 0 {
+		return &ValidationError{Element: "Stmt/Bal", Reason: "is required"}
+	}
+	for _, balance := range s.Balances {
+		if err := validateText("Stmt/Bal/Tp/CdOrPrtry/Cd", balance.Type, 4); err != nil {
+			return err
+		}
+		if err := balance.Amount.validate("Stmt/Bal/Amt"); err != nil {
+			return err
+		}
+		if err := validateIndicator("Stmt/Bal/CdtDbtInd", balance.Indicator); err != nil {
+			return err
+		}
+		if err := balance.Date.validate("Stmt/Bal/Dt"); err != nil {
+			return err
+		}
+	}
+
+	for _, entry := range s.Entries {
+		if err := entry.validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validate checks an entry of a statement
+func (e Entry) validate() error {
+	if err := e.Amount.validate("Stmt/Ntry/Amt"); err != nil {
+		return err
+	}
+	if err := validateIndicator("Stmt/Ntry/CdtDbtInd", e.Indicator); err != nil {
+		return err
+	}
+	if e.Status != StatusBooked && e.Status != "PDNG" && e.Status != "INFO" {
+		return &ValidationError{Element: "Stmt/Ntry/Sts", Reason: "must be BOOK, PDNG or INFO"}
+	}
+
+	if e.BookingDate != nil {
+		if err := e.BookingDate.validate("Stmt/Ntry/BookgDt"); err != nil {
+			return err
+		}
+	}
+	if e.ValueDate != nil {
+		if err := e.ValueDate.validate("Stmt/Ntry/ValDt"); err != nil {
+			return err
+		}
+	}
+
+	// booked entries are reconciled by their date
+	if e.Status == StatusBooked && e.BookingDate == nil && e.ValueDate == nil {
+		return &ValidationError{Element: "Stmt/Ntry/BookgDt", Reason: "is required for booked entries"}
+	}
+
+	code := e.TransactionCode
+	switch {
+	case code.Domain != nil:
+		if code.Domain.Code == "" || code.Domain.Family == "" || code.Domain.SubFamilyCode == "" {
+			return &ValidationError{Element: "Stmt/Ntry/BkTxCd/Domn", Reason: "must hold the domain, family and sub-family codes"}
+		}
+	case code.Proprietary != nil:
+		if err := validateText("Stmt/Ntry/BkTxCd/Prtry/Cd", code.Proprietary.Code, maxTextLength); err != nil {
+			return err
+		}
+	default:
+		return &ValidationError{Element: "Stmt/Ntry/BkTxCd", Reason: "is required"}
+	}
+
+	return nil
+}
+
+// reference returns the reference an entry is reconciled by
+func (e Entry) reference() string {
+	for _, details := range e.Details {
+		if details.References == nil {
+			continue
+		}
+		if id := strings.TrimSpace(details.References.EndToEndID); id != "" && id != notProvided {
+			return id
+		}
+	}
+
+	for _, details := range e.Details {
+		if details.References != nil && details.References.ServicerReference != "" {
+			return details.References.ServicerReference
+		}
+	}
+
+	if e.Reference != "" {
+		return e.Reference
+	}
+
+	return e.ServicerReference
+}
+
+// date returns the booking date of an entry, or its value date when it has none
+func (e Entry) date() time.Time {
+	for _, choice := range []*DateChoice{e.BookingDate, e.ValueDate} {
+		if choice == nil {
+			continue
+		}
+		if t, err := choice.time(); err == nil {
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+// description returns the additional information of an entry, or the remittance information of its transactions
+func (e Entry) description() string {
+	if e.AdditionalInfo != "" {
+		return e.AdditionalInfo
+	}
+
+	var remittance []string
+	for _, details := range e.Details {
+		remittance = append(remittance, details.Remittance...)
+	}
+
+	return strings.Join(remittance, " ")
+}
+
+// validate checks that exactly one of the date and the date and time is a valid value
+func (c DateChoice) validate(element string) error {
+	switch {
+	case c.Date != "" && c.DateTime != "":
+		return &ValidationError{Element: element, Reason: "must hold either Dt or DtTm"}
+	case c.Date != "":
+		return validateDate(element+"/Dt", c.Date)
+	case c.DateTime != "":
+		return validateDateTime(element+"/DtTm", c.DateTime)
+	default:
+		return &ValidationError{Element: element, Reason: "must hold Dt or DtTm"}
+	}
+}
+
+// time returns the date or the date and time, in UTC
+func (c DateChoice) time() (time.Time, error) {
+	if c.Date != "" {
+		return time.Parse(DateLayout, c.Date)
+	}
+
+	t, err := parseDateTime(c.DateTime)
+	return t.UTC(), err
+}
+
+// validateIndicator checks a credit or debit indicator
+func validateIndicator(element, indicator string) error {
+	if indicator != IndicatorCredit && indicator != IndicatorDebit {
+		return &ValidationError{Element: element, Reason: "must be CRDT or DBIT"}
+	}
+
+	return nil
+}
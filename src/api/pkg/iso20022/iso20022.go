@@ -0,0 +1,283 @@
+// Package iso20022 reads and writes the ISO 20022 messages exchanged with partner banks: pain.001 customer
+// credit transfer initiations for outgoing transfers and camt.053 bank-to-customer statements.
+package iso20022
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidDocument is the error wrapped by every ValidationError
+var ErrInvalidDocument = errors.New("invalid ISO 20022 document")
+
+const (
+	// DateLayout is the layout of ISODate values
+	DateLayout = "2006-01-02"
+
+	// DateTimeLayout is the layout of the ISODateTime values we write. Values with a fraction or a zone are read too.
+	DateTimeLayout = "2006-01-02T15:04:05"
+
+	// maxTextLength and maxNameLength are the lengths of the Max35Text and Max140Text types
+	maxTextLength = 35
+	maxNameLength = 140
+)
+
+var (
+	// ibanPattern, bicPattern and currencyPattern are the patterns of the IBAN2007Identifier, BICIdentifier
+	// and ActiveOrHistoricCurrencyCode types
+	ibanPattern     = regexp.MustCompile(`^[A-Z]{2}[0-9]{2}[a-zA-Z0-9]{1,30}$`)
+	bicPattern      = regexp.MustCompile(`^[A-Z]{6}[A-Z2-9][A-NP-Z0-9]([A-Z0-9]{3})?$`)
+	currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+	// decimalPattern is a non-negative decimal with at most 5 fraction digits, as allowed by the amount types
+	decimalPattern = regexp.MustCompile(`^[0-9]{1,13}(\.[0-9]{1,5})?$`)
+)
+
+// ValidationError is the error returned when a document breaks a rule of its schema. It names the element
+// at fault by its path in the document.
+type ValidationError struct {
+	Element string `json:"element"`
+	Reason  string `json:"reason"`
+}
+
+// Error describes the element at fault and the rule it breaks
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%v: %s %s", ErrInvalidDocument, e.Element, e.Reason)
+}
+
+// Unwrap makes errors.Is(err, ErrInvalidDocument) hold
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidDocument
+}
+
+// Document is the interface of the messages of the package
+type Document interface {
+	// Validate checks the document against the rules of its schema
+	Validate() error
+}
+
+// Marshal validates a document and writes it as XML
+func Marshal(doc Document) ([]byte, error) {
+	if err := doc.Validate(); err != nil {
+		return nil, err
+	}
+
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
+
+// unmarshal reads an XML document and validates it
+func unmarshal(data []byte, doc Document) error {
+	if err := xml.Unmarshal(data, doc); err != nil {
+		return &ValidationError{Element: "Document", Reason: err.Error()}
+	}
+
+	return doc.Validate()
+}
+
+// Amount is an amount in a currency, as in the ActiveOrHistoricCurrencyAndAmount type
+type Amount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+// NewAmount creates an amount of the currency, rounded to cents
+func NewAmount(value float32, currency string) Amount {
+	return Amount{Currency: currency, Value: formatCents(toCents(value))}
+}
+
+// Float returns the value of the amount
+func (a Amount) Float() float32 {
+	value, _ := strconv.ParseFloat(a.Value, 32)
+	return float32(value)
+}
+
+// validate checks the currency and the value of the amount
+func (a Amount) validate(element string) error {
+	if !currencyPattern.MatchString(a.Currency) {
+		return &ValidationError{Element: element + "/@Ccy", Reason: "must be an ISO 4217 currency code"}
+	}
+
+	if !decimalPattern.MatchString(strings.TrimSpace(a.Value)) {
+		return &ValidationError{Element: element, Reason: "must be a non-negative decimal with at most 5 fraction digits"}
+	}
+
+	return nil
+}
+
+// Party is a party to a payment identified by its name
+type Party struct {
+	Name string `xml:"Nm,omitempty"`
+}
+
+// AccountIdentification identifies an account by its IBAN, or by another identifier
+type AccountIdentification struct {
+	IBAN  string                        `xml:"IBAN,omitempty"`
+	Other *GenericAccountIdentification `xml:"Othr,omitempty"`
+}
+
+// GenericAccountIdentification is an identifier of an account that is not an IBAN
+type GenericAccountIdentification struct {
+	ID string `xml:"Id"`
+}
+
+// CashAccount is an account identified by its IBAN or another identifier
+type CashAccount struct {
+	ID       AccountIdentification `xml:"Id"`
+	Currency string                `xml:"Ccy,omitempty"`
+}
+
+// NewCashAccount creates the account of the number, identified by IBAN when the number is one
+func NewCashAccount(number string) CashAccount {
+	compact := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(number), " ", ""))
+	if ibanPattern.MatchString(compact) {
+		return CashAccount{ID: AccountIdentification{IBAN: compact}}
+	}
+
+	return CashAccount{ID: AccountIdentification{Other: &GenericAccountIdentification{ID: strings.TrimSpace(number)}}}
+}
+
+// Number returns the IBAN or other identifier of the account
+func (a CashAccount) Number() string {
+	if a.ID.Other != nil {
+		return a.ID.Other.ID
+	}
+
+	return a.ID.IBAN
+}
+
+// validate checks that the account is identified by a valid IBAN or another identifier
+func (a CashAccount) validate(element string) error {
+	switch {
+	case a.ID.IBAN != "" && a.ID.Other != nil:
+		return &ValidationError{Element: element + "/Id", Reason: "must hold either an IBAN or another identifier"}
+	case a.ID.IBAN != "":
+		if !ibanPattern.MatchString(a.ID.IBAN) {
+			return &ValidationError{Element: element + "/Id/IBAN", Reason: "must be a valid IBAN"}
+		}
+	case a.ID.Other != nil:
+		if err := validateText(element+"/Id/Othr/Id", a.ID.Other.ID, maxTextLength); err != nil {
+			return err
+		}
+	default:
+		return &ValidationError{Element: element + "/Id", Reason: "is required"}
+	}
+
+	if a.Currency != "" && !currencyPattern.MatchString(a.Currency) {
+		return &ValidationError{Element: element + "/Ccy", Reason: "must be an ISO 4217 currency code"}
+	}
+
+	return nil
+}
+
+// Agent is a financial institution identified by its BIC, or by its member ID in a clearing system
+type Agent struct {
+	Institution FinancialInstitution `xml:"FinInstnId"`
+}
+
+// FinancialInstitution identifies a financial institution
+type FinancialInstitution struct {
+	BIC            string `xml:"BIC,omitempty"`
+	ClearingMember string `xml:"ClrSysMmbId>MmbId,omitempty"`
+}
+
+// NewAgent creates the agent of the bank code, identified by BIC when the code is one
+func NewAgent(code string) Agent {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if bicPattern.MatchString(code) {
+		return Agent{Institution: FinancialInstitution{BIC: code}}
+	}
+
+	return Agent{Institution: FinancialInstitution{ClearingMember: code}}
+}
+
+// Code returns the BIC or clearing member ID of the agent
+func (a Agent) Code() string {
+	if a.Institution.BIC != "" {
+		return a.Institution.BIC
+	}
+
+	return a.Institution.ClearingMember
+}
+
+// validate checks that the agent is identified by a valid BIC or a clearing member ID
+func (a Agent) validate(element string) error {
+	switch {
+	case a.Institution.BIC != "":
+		if !bicPattern.MatchString(a.Institution.BIC) {
+			return &ValidationError{Element: element + "/FinInstnId/BIC", Reason: "must be a valid BIC"}
+		}
+	case a.Institution.ClearingMember != "":
+		return validateText(element+"/FinInstnId/ClrSysMmbId/MmbId", a.Institution.ClearingMember, maxTextLength)
+	default:
+		return &ValidationError{Element: element + "/FinInstnId", Reason: "must hold a BIC or a clearing member ID"}
+	}
+
+	return nil
+}
+
+// validateText checks that a required text is between 1 and max characters long
+func validateText(element, value string, max int) error {
+	if strings.TrimSpace(value) == "" {
+		return &ValidationError{Element: element, Reason: "is required"}
+	}
+
+	if len([]rune(value)) > max {
+		return &ValidationError{Element: element, Reason: fmt.Sprintf("must be at most %d characters", max)}
+	}
+
+	return nil
+}
+
+// validateDate checks that a value is an ISODate
+func validateDate(element, value string) error {
+	if _, err := time.Parse(DateLayout, value); err != nil {
+		return &ValidationError{Element: element, Reason: "must be a date formatted as YYYY-MM-DD"}
+	}
+
+	return nil
+}
+
+// validateDateTime checks that a value is an ISODateTime
+func validateDateTime(element, value string) error {
+	if _, err := parseDateTime(value); err != nil {
+		return &ValidationError{Element: element, Reason: "must be a date and time formatted as YYYY-MM-DDThh:mm:ss"}
+	}
+
+	return nil
+}
+
+// parseDateTime parses an ISODateTime, with or without a fraction and a zone. Times without a zone are UTC.
+func parseDateTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, nil
+	}
+
+	return time.Parse(DateTimeLayout+".999999999", value)
+}
+
+// toCents converts an amount to cents, rounding to the nearest cent
+func toCents(value float32) int64 {
+	return int64(math.Round(float64(value) * 100))
+}
+
+// parseCents reads a decimal as cents, rounding to the nearest cent. Values that are not decimals are zero.
+func parseCents(value string) int64 {
+	parsed, _ := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	return int64(math.Round(parsed * 100))
+}
+
+// formatCents writes an amount of cents as a decimal with two fraction digits
+func formatCents(cents int64) string {
+	return fmt.Sprintf("%d.%02d", cents/100, cents%100)
+}
@@ -0,0 +1,297 @@
+package iso20022
+
+import (
+	"encoding/xml"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Pain001Namespace is the namespace of the pain.001 messages we write (version 03, the one partner banks accept)
+const Pain001Namespace = "urn:iso:std:iso:20022:tech:xsd:pain.001.001.03"
+
+// paymentMethodTransfer is the payment method of credit transfers
+const paymentMethodTransfer = "TRF"
+
+// Pain001Document is a customer credit transfer initiation: the instruction to a bank to pay creditors from
+// one of our accounts.
+type Pain001Document struct {
+	XMLName    xml.Name                         `xml:"urn:iso:std:iso:20022:tech:xsd:pain.001.001.03 Document"`
+	Initiation CustomerCreditTransferInitiation `xml:"CstmrCdtTrfInitn"`
+}
+
+// CustomerCreditTransferInitiation is the body of a pain.001 message
+type CustomerCreditTransferInitiation struct {
+	GroupHeader GroupHeader          `xml:"GrpHdr"`
+	Payments    []PaymentInformation `xml:"PmtInf"`
+}
+
+// GroupHeader identifies a pain.001 message and totals its transactions
+type GroupHeader struct {
+	MessageID            string `xml:"MsgId"`
+	CreationDateTime     string `xml:"CreDtTm"`
+	NumberOfTransactions string `xml:"NbOfTxs"`
+	ControlSum           string `xml:"CtrlSum,omitempty"`
+	InitiatingParty      Party  `xml:"InitgPty"`
+}
+
+// PaymentInformation is a set of credit transfers paid from the same account on the same date
+type PaymentInformation struct {
+	ID                     string                      `xml:"PmtInfId"`
+	Method                 string                      `xml:"PmtMtd"`
+	NumberOfTransactions   string                      `xml:"NbOfTxs,omitempty"`
+	ControlSum             string                      `xml:"CtrlSum,omitempty"`
+	RequestedExecutionDate string                      `xml:"ReqdExctnDt"`
+	Debtor                 Party                       `xml:"Dbtr"`
+	DebtorAccount          CashAccount                 `xml:"DbtrAcct"`
+	DebtorAgent            Agent                       `xml:"DbtrAgt"`
+	Transactions           []CreditTransferTransaction `xml:"CdtTrfTxInf"`
+}
+
+// CreditTransferTransaction is the payment of an amount to a creditor
+type CreditTransferTransaction struct {
+	EndToEndID      string       `xml:"PmtId>EndToEndId"`
+	Amount          Amount       `xml:"Amt>InstdAmt"`
+	CreditorAgent   *Agent       `xml:"CdtrAgt,omitempty"`
+	Creditor        Party        `xml:"Cdtr"`
+	CreditorAccount *CashAccount `xml:"CdtrAcct,omitempty"`
+	Remittance      string       `xml:"RmtInf>Ustrd,omitempty"`
+}
+
+// Debtor is the account the transfers of a pain.001 message are paid from
+type Debtor struct {
+	Name string
+
+	// Account is the IBAN or account number, and Agent the BIC or bank code of the account
+	Account string
+	Agent   string
+}
+
+// CreditTransfer is a payment to a creditor, written into a pain.001 message
+type CreditTransfer struct {
+	// EndToEndID is our reference of the payment, returned by the bank in its statements
+	EndToEndID string
+	Amount     float32
+	Currency   string
+
+	CreditorName string
+
+	// CreditorAccount is the IBAN or account number, and CreditorAgent the BIC or bank code of the creditor
+	CreditorAccount string
+	CreditorAgent   string
+	Remittance      string
+}
+
+// NewPain001 creates a pain.001 message paying the transfers from the debtor's account on the execution date
+func NewPain001(messageID string, debtor Debtor, executionDate time.Time, transfers []CreditTransfer) *Pain001Document {
+	var total int64
+	transactions := make([]CreditTransferTransaction, 0, len(transfers))
+	for _, transfer := range transfers {
+		total += toCents(transfer.Amount)
+
+		transaction := CreditTransferTransaction{
+			EndToEndID: transfer.EndToEndID,
+			Amount:     NewAmount(transfer.Amount, transfer.Currency),
+			Creditor:   Party{Name: transfer.CreditorName},
+			Remittance: transfer.Remittance,
+		}
+		if transfer.CreditorAgent != "" {
+			agent := NewAgent(transfer.CreditorAgent)
+			transaction.CreditorAgent = &agent
+		}
+		if transfer.CreditorAccount != "" {
+			account := NewCashAccount(transfer.CreditorAccount)
+			transaction.CreditorAccount = &account
+		}
+		transactions = append(transactions, transaction)
+	}
+
+	count, sum := strconv.Itoa(len(transactions)), formatCents(total)
+	return &Pain001Document{
+		Initiation: CustomerCreditTransferInitiation{
+			GroupHeader: GroupHeader{
+				MessageID:            messageID,
+				CreationDateTime:     time.Now().UTC().Format(DateTimeLayout),
+				NumberOfTransactions: count,
+				ControlSum:           sum,
+				InitiatingParty:      Party{Name: debtor.Name},
+			},
+			Payments: []PaymentInformation{{
+				ID:                     messageID,
+				Method:                 paymentMethodTransfer,
+				NumberOfTransactions:   count,
+				ControlSum:             sum,
+				RequestedExecutionDate: executionDate.UTC().Format(DateLayout),
+				Debtor:                 Party{Name: debtor.Name},
+				DebtorAccount:          NewCashAccount(debtor.Account),
+				DebtorAgent:            NewAgent(debtor.Agent),
+				Transactions:           transactions,
+			}},
+		},
+	}
+}
+
+// ParsePain001 reads a pain.001 message and validates it
+func ParsePain001(data []byte) (*Pain001Document, error) {
+	var doc Pain001Document
+	if err := unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	return &doc, nil
+}
+
+// Transfers returns the credit transfers of the message
+func (d *Pain001Document) Transfers() []CreditTransfer {
+	var transfers []CreditTransfer
+	for _, payment := range d.Initiation.Payments {
+		for _, transaction := range payment.Transactions {
+			transfer := CreditTransfer{
+				EndToEndID:   transaction.EndToEndID,
+				Amount:       transaction.Amount.Float(),
+				Currency:     transaction.Amount.Currency,
+				CreditorName: transaction.Creditor.Name,
+				Remittance:   transaction.Remittance,
+			}
+			if transaction.CreditorAccount != nil {
+				transfer.CreditorAccount = transaction.CreditorAccount.Number()
+			}
+			if transaction.CreditorAgent != nil {
+				transfer.CreditorAgent = transaction.CreditorAgent.Code()
+			}
+			transfers = append(transfers, transfer)
+		}
+	}
+
+	return transfers
+}
+
+// Validate checks the message against the pain.001.001.03 schema, and that its transaction counts and
+// control sums add up
+func (d *Pain001Document) Validate() error {
+	header := d.Initiation.GroupHeader
+	if err := validateText("GrpHdr/MsgId", header.MessageID, maxTextLength); err != nil {
+		return err
+	}
+	if err := validateDateTime("GrpHdr/CreDtTm", header.CreationDateTime); err != nil {
+		return err
+	}
+	if header.InitiatingParty.Name != "" {
+		if err := validateText("GrpHdr/InitgPty/Nm", header.InitiatingParty.Name, maxNameLength); err != nil {
+			return err
+		}
+	}
+
+	if len(d.Initiation.Payments) == 0 {
+		return &ValidationError{Element: "PmtInf", Reason: "is required"}
+	}
+
+	count, total := 0, int64(0)
+	for _, payment := range d.Initiation.Payments {
+		paymentTotal, err := payment.validate()
+		if err != nil {
+			return err
+		}
+		count += len(payment.Transactions)
+		total += paymentTotal
+	}
+
+	return validateTotals("GrpHdr", header.NumberOfTransactions, header.ControlSum, count, total)
+}
+
+// validate checks a payment information block and returns the total of its transactions in cents
+func (p PaymentInformation) validate() (int64, error) {
+	if err := validateText("PmtInf/PmtInfId", p.ID, maxTextLength); err != nil {
+		return 0, err
+	}
+	if p.Method != paymentMethodTransfer && p.Method != "CHK" && p.Method != "TRA" {
+		return 0, &ValidationError{Element: "PmtInf/PmtMtd", Reason: "must be TRF, CHK or TRA"}
+	}
+	if err := validateDate("PmtInf/ReqdExctnDt", p.RequestedExecutionDate); err != nil {
+		return 0, err
+	}
+	if p.Debtor.Name != "" {
+		if err := validateText("PmtInf/Dbtr/Nm", p.Debtor.Name, maxNameLength); err != nil {
+			return 0, err
+		}
+	}
+	if err := p.DebtorAccount.validate("PmtInf/DbtrAcct"); err != nil {
+		return 0, err
+	}
+	if err := p.DebtorAgent.validate("PmtInf/DbtrAgt"); err != nil {
+		return 0, err
+	}
+
+	if len(p.Transactions) == 0 {
+		return 0, &ValidationError{Element: "PmtInf/CdtTrfTxInf", Reason: "is required"}
+	}
+
+	var total int64
+	for _, transaction := range p.Transactions {
+		if err := transaction.validate(); err != nil {
+			return 0, err
+		}
+		total += parseCents(transaction.Amount.Value)
+	}
+
+	// the block's own totals are optional, but must add up when given
+	if p.NumberOfTransactions != "" || p.ControlSum != "" {
+		numberOfTransactions := p.NumberOfTransactions
+		if numberOfTransactions == "" {
+			numberOfTransactions = strconv.Itoa(len(p.Transactions))
+		}
+		if err := validateTotals("PmtInf", numberOfTransactions, p.ControlSum, len(p.Transactions), total); err != nil {
+			return 0, err
+		}
+	}
+
+	return total, nil
+}
+
+// validate checks a credit transfer transaction
+func (t CreditTransferTransaction) validate() error {
+	if err := validateText("CdtTrfTxInf/PmtId/EndToEndId", t.EndToEndID, maxTextLength); err != nil {
+		return err
+	}
+	if err := t.Amount.validate("CdtTrfTxInf/Amt/InstdAmt"); err != nil {
+		return err
+	}
+	if t.CreditorAgent != nil {
+		if err := t.CreditorAgent.validate("CdtTrfTxInf/CdtrAgt"); err != nil {
+			return err
+		}
+	}
+	if t.Creditor.Name != "" {
+		if err := validateText("CdtTrfTxInf/Cdtr/Nm", t.Creditor.Name, maxNameLength); err != nil {
+			return err
+		}
+	}
+	if t.CreditorAccount == nil {
+		return &ValidationError{Element: "CdtTrfTxInf/CdtrAcct", Reason: "is required for credit transfers"}
+	}
+	if err := t.CreditorAccount.validate("CdtTrfTxInf/CdtrAcct"); err != nil {
+		return err
+	}
+	if t.Remittance != "" && len([]rune(t.Remittance)) > maxNameLength {
+		return &ValidationError{Element: "CdtTrfTxInf/RmtInf/Ustrd", Reason: "must be at most 140 characters"}
+	}
+
+	return nil
+}
+
+// validateTotals checks that a number of transactions and a control sum match the transactions they total
+func validateTotals(element, numberOfTransactions, controlSum string, count int, total int64) error {
+	if n, err := strconv.Atoi(numberOfTransactions); err != nil || len(numberOfTransactions) > 15 || n != count {
+		return &ValidationError{Element: element + "/NbOfTxs", Reason: "must be the number of transactions (" + strconv.Itoa(count) + ")"}
+	}
+
+	if controlSum == "" {
+		return nil
+	}
+
+	if !decimalPattern.MatchString(strings.TrimSpace(controlSum)) || parseCents(controlSum) != total {
+		return &ValidationError{Element: element + "/CtrlSum", Reason: "must be the sum of the transaction amounts (" + formatCents(total) + ")"}
+	}
+
+	return nil
+}
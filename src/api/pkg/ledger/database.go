@@ -0,0 +1,78 @@
+package ledger
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrUnbalancedEntries is the error returned when the debits and credits of a posting do not match
+	ErrUnbalancedEntries = errors.New("ledger entries are not balanced")
+
+	// ErrInvalidEntry is the error returned when a ledger entry is incomplete
+	ErrInvalidEntry = errors.New("invalid ledger entry. Please check and try again")
+
+	// ErrEntriesAlreadyPosted is the error returned when a posting with the same reference already exists
+	ErrEntriesAlreadyPosted = errors.New("ledger entries already posted for this reference")
+
+	// ErrFailedToPostEntries is the error returned when ledger entries could not be stored
+	ErrFailedToPostEntries = errors.New("failed to post ledger entries. Please try again")
+
+	// ErrInsufficientFunds is the error returned when the available balance does not cover an amount
+	ErrInsufficientFunds = errors.New("insufficient available balance")
+
+	// ErrHoldNotFound is the error returned when a hold does not exist
+	ErrHoldNotFound = errors.New("hold not found")
+
+	// ErrHoldNotActive is the error returned when a hold has already been captured, voided or has expired
+	ErrHoldNotActive = errors.New("hold is no longer active")
+
+	// ErrInvalidCaptureAmount is the error returned when a capture exceeds the held amount
+	ErrInvalidCaptureAmount = errors.New("capture amount must be positive and not exceed the held amount")
+
+	// ErrFailedToSaveHold is the error returned when a hold could not be stored
+	ErrFailedToSaveHold = errors.New("failed to save hold. Please try again")
+
+	// ErrFailedToGetBalance is the error returned when a balance could not be computed
+	ErrFailedToGetBalance = errors.New("failed to get balance. Please try again")
+
+	// ErrInvalidOverdraftLimit is the error returned when an overdraft limit is negative
+	ErrInvalidOverdraftLimit = errors.New("invalid overdraft limit. Please check the limit is not negative")
+
+	// ErrFailedToSaveOverdraftLimit is the error returned when an overdraft limit could not be stored
+	ErrFailedToSaveOverdraftLimit = errors.New("failed to save overdraft limit. Please try again")
+)
+
+// Database is the interface that wraps the basic ledger database operations.
+type Database interface {
+	// PostEntries stores the entries of a posting atomically. All entries share one reference and
+	// a reference can only be posted once.
+	PostEntries(entries []*Entry) error
+
+	// GetBalance computes the current and available balance of an account, with its overdraft limit
+	GetBalance(accountID string) (*Balance, error)
+
+	// SetOverdraftLimit creates or replaces the overdraft limit of an account
+	SetOverdraftLimit(accountID string, limit float32) error
+
+	// PlaceHold stores an active hold if the account's available balance and overdraft limit cover it, failing with ErrInsufficientFunds otherwise
+	PlaceHold(hold *Hold) error
+
+	// GetHold gets a hold by ID
+	GetHold(id string) (*Hold, error)
+
+	// CaptureHold marks an active hold as captured for an amount and stores the entries settling it, atomically
+	CaptureHold(id string, amount float32, entries []*Entry) (*Hold, error)
+
+	// ReleaseHold moves an active hold to the given status (voided or expired)
+	ReleaseHold(id string, status HoldStatus) (*Hold, error)
+
+	// GetExpiredHolds gets the active holds that expired at or before the given time
+	GetExpiredHolds(now time.Time) ([]*Hold, error)
+
+	// GetEntries gets the entries of an account, oldest first
+	GetEntries(accountID string) ([]*Entry, error)
+
+	// GetAccountIDs gets the IDs of the accounts that have entries, in order
+	GetAccountIDs() ([]string, error)
+}
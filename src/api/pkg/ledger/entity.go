@@ -0,0 +1,216 @@
+package ledger
+
+import (
+	"github.com/google/uuid"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// PaymentsClearingAccountID is the system account that holds the funds collected by payment providers
+const PaymentsClearingAccountID = "system:payments-clearing"
+
+// PayoutsClearingAccountID is the system account that holds the funds sent out through payout providers until they are confirmed
+const PayoutsClearingAccountID = "system:payouts-clearing"
+
+// DisputesClearingAccountID is the system account that receives the amounts charged back to merchants for lost disputes
+const DisputesClearingAccountID = "system:disputes-clearing"
+
+// CardSettlementAccountID is the system account that receives the card payments debited from accounts until they are settled with the card network
+const CardSettlementAccountID = "system:card-settlement"
+
+// InterestExpenseAccountID is the system account that pays the interest earned by accounts
+const InterestExpenseAccountID = "system:interest-expense"
+
+// FeeIncomeAccountID is the system account that receives the fees charged to accounts
+const FeeIncomeAccountID = "system:fee-income"
+
+// InterestIncomeAccountID is the system account that receives the overdraft interest charged to accounts
+const InterestIncomeAccountID = "system:interest-income"
+
+// IsSystemAccount reports whether an account is kept by the ledger itself (a system or escrow account) rather
+// than owned by a customer or merchant
+func IsSystemAccount(accountID string) bool {
+	return strings.HasPrefix(accountID, "system:") || strings.HasPrefix(accountID, "escrow:")
+}
+
+// EscrowAccountID returns the ledger account holding the funds of an escrow until they are released or refunded
+func EscrowAccountID(escrowID string) string {
+	return "escrow:" + escrowID
+}
+
+// EntryType is the type that represents the side of a ledger entry
+type EntryType string
+
+const (
+	// EntryTypeDebit is an entry that decreases the account balance
+	EntryTypeDebit EntryType = "debit"
+
+	// EntryTypeCredit is an entry that increases the account balance
+	EntryTypeCredit EntryType = "credit"
+)
+
+// Entry is the entity that represents one side of a ledger posting
+type Entry struct {
+	ID        string `json:"id"`
+	AccountID string `json:"account_id"`
+
+	// Reference is the ID of the operation (payment, refund...) that caused the entry
+	Reference   string    `json:"reference"`
+	Type        EntryType `json:"type"`
+	Amount      float32   `json:"amount"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Balance is the entity that represents the balance of a ledger account.
+// Current is the posted balance; Available also deducts the funds reserved by active holds. OverdraftLimit is
+// how far below zero the available balance is allowed to go.
+type Balance struct {
+	AccountID      string  `json:"account_id"`
+	Current        float32 `json:"current"`
+	Available      float32 `json:"available"`
+	OverdraftLimit float32 `json:"overdraft_limit,omitempty"`
+}
+
+// Spendable returns the amount the account can still spend: its available balance plus its overdraft limit
+func (b *Balance) Spendable() float32 {
+	return b.Available + b.OverdraftLimit
+}
+
+// IsOverdrawn reports whether the account is spending its overdraft
+func (b *Balance) IsOverdrawn() bool {
+	return b.Available < 0
+}
+
+// HoldStatus is the type that represents the status of a hold
+type HoldStatus string
+
+const (
+	// HoldStatusActive is the status of a hold that reserves funds
+	HoldStatusActive HoldStatus = "active"
+
+	// HoldStatusCaptured is the status of a hold whose funds have been settled
+	HoldStatusCaptured HoldStatus = "captured"
+
+	// HoldStatusVoided is the status of a hold released without capture
+	HoldStatusVoided HoldStatus = "voided"
+
+	// HoldStatusExpired is the status of a hold released because it was not captured in time
+	HoldStatusExpired HoldStatus = "expired"
+)
+
+// Hold is the entity that represents funds reserved on an account until they are captured or released
+type Hold struct {
+	ID        string `json:"id"`
+	AccountID string `json:"account_id"`
+
+	// CreditAccountID is the account that receives the funds on capture
+	CreditAccountID string     `json:"credit_account_id"`
+	Amount          float32    `json:"amount"`
+	CapturedAmount  float32    `json:"captured_amount"`
+	Status          HoldStatus `json:"status"`
+	ExpiresAt       time.Time  `json:"expires_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// NewHold creates an active hold of an amount on an account, expiring after the given duration
+func NewHold(accountID, creditAccountID string, amount float32, expiresIn time.Duration) *Hold {
+	now := time.Now().UTC()
+	return &Hold{
+		ID:              "hold_" + uuid.NewString(),
+		AccountID:       accountID,
+		CreditAccountID: creditAccountID,
+		Amount:          amount,
+		Status:          HoldStatusActive,
+		ExpiresAt:       now.Add(expiresIn),
+		CreatedAt:       now,
+	}
+}
+
+// IsExpired reports whether an active hold has passed its expiry time
+func (h *Hold) IsExpired(now time.Time) bool {
+	return h.Status == HoldStatusActive && !now.Before(h.ExpiresAt)
+}
+
+// NewTransfer creates the balanced pair of entries that moves an amount between two accounts
+func NewTransfer(reference, description, debitAccountID, creditAccountID string, amount float32) []*Entry {
+	now := time.Now().UTC()
+	return []*Entry{
+		{
+			ID:          uuid.NewString(),
+			AccountID:   debitAccountID,
+			Reference:   reference,
+			Type:        EntryTypeDebit,
+			Amount:      amount,
+			Description: description,
+			CreatedAt:   now,
+		},
+		{
+			ID:          uuid.NewString(),
+			AccountID:   creditAccountID,
+			Reference:   reference,
+			Type:        EntryTypeCredit,
+			Amount:      amount,
+			Description: description,
+			CreatedAt:   now,
+		},
+	}
+}
+
+// Credit is an amount paid into an account by a split transfer
+type Credit struct {
+	AccountID string
+	Amount    float32
+}
+
+// NewSplitTransfer creates the balanced entries that move the total of the credits out of one account into
+// several. Empty credits are left out, and the credits of the same account are paid as one entry.
+func NewSplitTransfer(reference, description, debitAccountID string, credits []Credit) []*Entry {
+	now := time.Now().UTC()
+	debit := &Entry{
+		ID:          uuid.NewString(),
+		AccountID:   debitAccountID,
+		Reference:   reference,
+		Type:        EntryTypeDebit,
+		Description: description,
+		CreatedAt:   now,
+	}
+
+	entries := []*Entry{debit}
+	byAccount := make(map[string]*Entry)
+	for _, credit := range credits {
+		if credit.Amount <= 0 {
+			continue
+		}
+
+		debit.Amount += credit.Amount
+		if entry, ok := byAccount[credit.AccountID]; ok {
+			entry.Amount += credit.Amount
+			continue
+		}
+
+		byAccount[credit.AccountID] = &Entry{
+			ID:          uuid.NewString(),
+			AccountID:   credit.AccountID,
+			Reference:   reference,
+			Type:        EntryTypeCredit,
+			Amount:      credit.Amount,
+			Description: description,
+			CreatedAt:   now,
+		}
+		entries = append(entries, byAccount[credit.AccountID])
+	}
+
+	return entries
+}
+
+// MerchantAccountID returns the ledger account of the merchant notified at the given URL
+func MerchantAccountID(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" {
+		return "merchant:" + rawURL
+	}
+
+	return "merchant:" + parsed.Host
+}
@@ -0,0 +1,38 @@
+package ledger
+
+import "time"
+
+// Repository is the ledger repository interface
+type Repository interface {
+	// Post records a balanced set of entries.
+	Post(entries ...*Entry) error
+
+	// Balance returns the current and available balance of an account, with its overdraft limit.
+	Balance(accountID string) (*Balance, error)
+
+	// SetOverdraftLimit sets how far below zero the available balance of an account may go. A zero limit
+	// removes the overdraft.
+	SetOverdraftLimit(accountID string, limit float32) error
+
+	// Entries returns the entries of an account, oldest first.
+	Entries(accountID string) ([]*Entry, error)
+
+	// AccountIDs returns the IDs of the accounts that have entries, in order.
+	AccountIDs() ([]string, error)
+
+	// Hold reserves funds on an account, reducing its available balance. The account's overdraft limit may
+	// be used.
+	Hold(hold *Hold) error
+
+	// FindHold gets a hold by ID.
+	FindHold(id string) (*Hold, error)
+
+	// Capture settles all or part of an active hold into its credit account. The rest of the hold is released.
+	Capture(id string, amount float32) (*Hold, error)
+
+	// Release releases an active hold without settling it.
+	Release(id string, status HoldStatus) (*Hold, error)
+
+	// ExpiredHolds returns the active holds that expired at or before the given time.
+	ExpiredHolds(now time.Time) ([]*Hold, error)
+}
@@ -0,0 +1,112 @@
+package pkg
+
+import (
+	"errors"
+	"github.com/quabynah-bilson/quantia/pkg/ledger"
+	"log"
+	"time"
+)
+
+var (
+	// ErrInvalidAccount is the error returned when a ledger account ID is missing.
+	ErrInvalidAccount = errors.New("invalid account. Please check and try again")
+
+	// ErrInvalidHoldExpiry is the error returned when a hold would expire in the past or too far in the future.
+	ErrInvalidHoldExpiry = errors.New("invalid hold expiry. Please check and try again")
+)
+
+const (
+	// DefaultHoldExpiry is how long a hold reserves funds when no expiry is given.
+	DefaultHoldExpiry = 7 * 24 * time.Hour
+
+	// maxHoldExpiry is the longest a hold may reserve funds.
+	maxHoldExpiry = 30 * 24 * time.Hour
+)
+
+// LedgerUseCase is the ledger use case. It contains the necessary repositories to read balances and move reserved funds.
+type LedgerUseCase struct {
+	ledgerRepo ledger.Repository
+}
+
+// NewLedgerUseCase creates a new ledger use case.
+func NewLedgerUseCase(ledgerRepo ledger.Repository) *LedgerUseCase {
+	return &LedgerUseCase{ledgerRepo: ledgerRepo}
+}
+
+// GetBalance returns the current and available balance of an account.
+func (uc *LedgerUseCase) GetBalance(accountID string) (*ledger.Balance, error) {
+	if accountID == "" {
+		return nil, ErrInvalidAccount
+	}
+
+	return uc.ledgerRepo.Balance(accountID)
+}
+
+// Authorize places a hold of an amount on an account, to be captured into the credit account.
+// The hold reduces the available balance only; the current balance changes on capture.
+// A zero expiresIn uses DefaultHoldExpiry.
+func (uc *LedgerUseCase) Authorize(accountID, creditAccountID string, amount float32, expiresIn time.Duration) (*ledger.Hold, error) {
+	if err := validateAmount(amount); err != nil {
+		log.Printf("error validating amount: %v", err)
+		return nil, err
+	}
+
+	if accountID == "" || creditAccountID == "" || accountID == creditAccountID {
+		return nil, ErrInvalidAccount
+	}
+
+	if expiresIn == 0 {
+		expiresIn = DefaultHoldExpiry
+	}
+	if expiresIn < 0 || expiresIn > maxHoldExpiry {
+		return nil, ErrInvalidHoldExpiry
+	}
+
+	hold := ledger.NewHold(accountID, creditAccountID, amount, expiresIn)
+	if err := uc.ledgerRepo.Hold(hold); err != nil {
+		log.Printf("error placing hold on account %s: %v", accountID, err)
+		return nil, err
+	}
+
+	return hold, nil
+}
+
+// GetHold gets a hold by ID.
+func (uc *LedgerUseCase) GetHold(id string) (*ledger.Hold, error) {
+	return uc.ledgerRepo.FindHold(id)
+}
+
+// Capture settles a hold. A zero amount captures the full held amount; a smaller amount captures
+// part of it and releases the rest.
+func (uc *LedgerUseCase) Capture(id string, amount float32) (*ledger.Hold, error) {
+	if amount < 0 {
+		return nil, ledger.ErrInvalidCaptureAmount
+	}
+
+	if amount == 0 {
+		hold, err := uc.ledgerRepo.FindHold(id)
+		if err != nil {
+			return nil, err
+		}
+		amount = hold.Amount
+	}
+
+	hold, err := uc.ledgerRepo.Capture(id, amount)
+	if err != nil {
+		log.Printf("error capturing hold %s: %v", id, err)
+		return nil, err
+	}
+
+	return hold, nil
+}
+
+// Void releases a hold without settling it.
+func (uc *LedgerUseCase) Void(id string) (*ledger.Hold, error) {
+	hold, err := uc.ledgerRepo.Release(id, ledger.HoldStatusVoided)
+	if err != nil {
+		log.Printf("error voiding hold %s: %v", id, err)
+		return nil, err
+	}
+
+	return hold, nil
+}
@@ -0,0 +1,47 @@
+package limit
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+)
+
+// ErrInvalidConfig is the error returned when a limits configuration is unusable
+var ErrInvalidConfig = errors.New("invalid limits configuration")
+
+// Config is the entity that represents the limits of every tier and the tier of accounts without one
+type Config struct {
+	DefaultTier Tier            `json:"default_tier"`
+	Tiers       map[Tier]Limits `json:"tiers"`
+}
+
+// DefaultConfig returns the limits used when no configuration file is given
+func DefaultConfig() *Config {
+	return &Config{
+		DefaultTier: "standard",
+		Tiers: map[Tier]Limits{
+			"standard": {PerTransaction: 1000, DailyAmount: 2000, DailyCount: 20, MonthlyAmount: 20000, MonthlyCount: 200},
+			"premium":  {PerTransaction: 5000, DailyAmount: 10000, DailyCount: 50, MonthlyAmount: 100000, MonthlyCount: 500},
+			"business": {PerTransaction: 50000, DailyAmount: 200000, DailyCount: 1000, MonthlyAmount: 2000000},
+		},
+	}
+}
+
+// LoadConfig reads a limits configuration from a JSON file
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+
+	if _, ok := config.Tiers[config.DefaultTier]; !ok {
+		return nil, ErrInvalidConfig
+	}
+
+	return &config, nil
+}
@@ -0,0 +1,28 @@
+package limit
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrFailedToRecordUsage is the error returned when usage cannot be read or recorded
+var ErrFailedToRecordUsage = errors.New("failed to check transaction limits. Please try again")
+
+// Database is the interface that wraps the basic limit database operations.
+type Database interface {
+	// GetTier gets the tier of a subject (account or payer), empty when none was set
+	GetTier(subject string) (Tier, error)
+
+	// SetTier sets the tier of a subject
+	SetTier(subject string, tier Tier) error
+
+	// GetUsage gets what a subject has spent in the day and month of the given time
+	GetUsage(subject string, now time.Time) (*Usage, error)
+
+	// ReserveUsage atomically checks the amount against the limits and records it in the day and month of
+	// the given time. It fails with an ExceededError, recording nothing, when a limit would be exceeded.
+	ReserveUsage(subject string, amount float32, limits Limits, now time.Time) error
+
+	// ReleaseUsage gives back a reservation
+	ReleaseUsage(reservation *Reservation) error
+}
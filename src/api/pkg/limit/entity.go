@@ -0,0 +1,143 @@
+package limit
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrLimitExceeded is the error wrapped by every ExceededError
+var ErrLimitExceeded = errors.New("transaction limit exceeded")
+
+// amountTolerance absorbs float rounding when amounts are compared with limits
+const amountTolerance = 0.005
+
+// Tier is the type that represents the limit tier of an account (e.g. standard, premium)
+type Tier string
+
+// Limits is the entity that represents the ceilings of a tier. Zero values are unlimited.
+type Limits struct {
+	PerTransaction float32 `json:"per_transaction,omitempty"`
+	DailyAmount    float32 `json:"daily_amount,omitempty"`
+	DailyCount     int     `json:"daily_count,omitempty"`
+	MonthlyAmount  float32 `json:"monthly_amount,omitempty"`
+	MonthlyCount   int     `json:"monthly_count,omitempty"`
+}
+
+// Usage is the entity that represents what an account has spent in the current day and month
+type Usage struct {
+	DailyAmount   float32 `json:"daily_amount"`
+	DailyCount    int     `json:"daily_count"`
+	MonthlyAmount float32 `json:"monthly_amount"`
+	MonthlyCount  int     `json:"monthly_count"`
+}
+
+// Kind is the type that represents which limit was exceeded
+type Kind string
+
+const (
+	// KindPerTransaction is the ceiling of a single transaction
+	KindPerTransaction Kind = "per_transaction"
+
+	// KindDailyAmount is the ceiling of the amount spent in a day
+	KindDailyAmount Kind = "daily_amount"
+
+	// KindDailyCount is the ceiling of the number of transactions in a day
+	KindDailyCount Kind = "daily_count"
+
+	// KindMonthlyAmount is the ceiling of the amount spent in a month
+	KindMonthlyAmount Kind = "monthly_amount"
+
+	// KindMonthlyCount is the ceiling of the number of transactions in a month
+	KindMonthlyCount Kind = "monthly_count"
+)
+
+// ExceededError is the error returned when a transaction would go over a limit. It tells how much is left
+// and when the limit resets.
+type ExceededError struct {
+	Kind Kind `json:"kind"`
+
+	// Remaining is the amount (or number of transactions for count limits) still allowed
+	Remaining float32 `json:"remaining"`
+
+	// ResetAt is when the allowance is restored. It is nil for the per-transaction limit.
+	ResetAt *time.Time `json:"reset_at,omitempty"`
+}
+
+// Error describes the exceeded limit, the remaining allowance and the reset time
+func (e *ExceededError) Error() string {
+	switch e.Kind {
+	case KindPerTransaction:
+		return fmt.Sprintf("%v: at most %.2f per transaction", ErrLimitExceeded, e.Remaining)
+	case KindDailyCount, KindMonthlyCount:
+		return fmt.Sprintf("%v: %s allows %d more transactions until %s", ErrLimitExceeded, e.Kind, int(e.Remaining), e.ResetAt.Format(time.RFC3339))
+	default:
+		return fmt.Sprintf("%v: %s allows %.2f more until %s", ErrLimitExceeded, e.Kind, e.Remaining, e.ResetAt.Format(time.RFC3339))
+	}
+}
+
+// Unwrap makes errors.Is(err, ErrLimitExceeded) hold
+func (e *ExceededError) Unwrap() error {
+	return ErrLimitExceeded
+}
+
+// Check returns an ExceededError if a transaction of the amount would go over the limits, given the usage at now
+func (l Limits) Check(usage Usage, amount float32, now time.Time) error {
+	if l.PerTransaction > 0 && amount > l.PerTransaction+amountTolerance {
+		return &ExceededError{Kind: KindPerTransaction, Remaining: l.PerTransaction}
+	}
+
+	dayReset, monthReset := DayResetAt(now), MonthResetAt(now)
+	if l.DailyCount > 0 && usage.DailyCount+1 > l.DailyCount {
+		return &ExceededError{Kind: KindDailyCount, Remaining: remaining(float32(l.DailyCount), float32(usage.DailyCount)), ResetAt: &dayReset}
+	}
+	if l.DailyAmount > 0 && usage.DailyAmount+amount > l.DailyAmount+amountTolerance {
+		return &ExceededError{Kind: KindDailyAmount, Remaining: remaining(l.DailyAmount, usage.DailyAmount), ResetAt: &dayReset}
+	}
+	if l.MonthlyCount > 0 && usage.MonthlyCount+1 > l.MonthlyCount {
+		return &ExceededError{Kind: KindMonthlyCount, Remaining: remaining(float32(l.MonthlyCount), float32(usage.MonthlyCount)), ResetAt: &monthReset}
+	}
+	if l.MonthlyAmount > 0 && usage.MonthlyAmount+amount > l.MonthlyAmount+amountTolerance {
+		return &ExceededError{Kind: KindMonthlyAmount, Remaining: remaining(l.MonthlyAmount, usage.MonthlyAmount), ResetAt: &monthReset}
+	}
+
+	return nil
+}
+
+// Reservation is the entity that represents a transaction counted against an account's limits, so that it
+// can be given back if the transaction fails
+type Reservation struct {
+	Subject string    `json:"subject"`
+	Amount  float32   `json:"amount"`
+	At      time.Time `json:"at"`
+}
+
+// DayWindow returns the key of the day (UTC) of the given time
+func DayWindow(t time.Time) string {
+	return t.UTC().Format("2006-01-02")
+}
+
+// MonthWindow returns the key of the month (UTC) of the given time
+func MonthWindow(t time.Time) string {
+	return t.UTC().Format("2006-01")
+}
+
+// DayResetAt returns when the daily limits reset after the given time (the next UTC midnight)
+func DayResetAt(t time.Time) time.Time {
+	year, month, day := t.UTC().Date()
+	return time.Date(year, month, day+1, 0, 0, 0, 0, time.UTC)
+}
+
+// MonthResetAt returns when the monthly limits reset after the given time (the first of the next UTC month)
+func MonthResetAt(t time.Time) time.Time {
+	year, month, _ := t.UTC().Date()
+	return time.Date(year, month+1, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// remaining returns what is left of a limit, never below zero
+func remaining(limit, used float32) float32 {
+	if used >= limit {
+		return 0
+	}
+	return limit - used
+}
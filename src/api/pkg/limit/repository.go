@@ -0,0 +1,21 @@
+package limit
+
+import "time"
+
+// Repository is the limit repository interface
+type Repository interface {
+	// Tier returns the tier of a subject, empty when none was set.
+	Tier(subject string) (Tier, error)
+
+	// SetTier sets the tier of a subject.
+	SetTier(subject string, tier Tier) error
+
+	// Usage returns what a subject has spent in the day and month of the given time.
+	Usage(subject string, now time.Time) (*Usage, error)
+
+	// Reserve atomically checks and records a transaction against the limits.
+	Reserve(subject string, amount float32, limits Limits, now time.Time) (*Reservation, error)
+
+	// Release gives back a reservation.
+	Release(reservation *Reservation) error
+}
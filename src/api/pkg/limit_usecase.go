@@ -0,0 +1,111 @@
+package pkg
+
+import (
+	"errors"
+	"github.com/quabynah-bilson/quantia/pkg/limit"
+	"log"
+	"time"
+)
+
+// ErrUnknownTier is the error returned when an account is moved to a tier that is not configured.
+var ErrUnknownTier = errors.New("unknown limit tier. Please check and try again")
+
+// LimitStatus is the view of a subject's limits, usage and remaining allowance.
+type LimitStatus struct {
+	Subject        string       `json:"subject"`
+	Tier           limit.Tier   `json:"tier"`
+	Limits         limit.Limits `json:"limits"`
+	Usage          limit.Usage  `json:"usage"`
+	DailyResetAt   time.Time    `json:"daily_reset_at"`
+	MonthlyResetAt time.Time    `json:"monthly_reset_at"`
+}
+
+// LimitUseCase is the transaction limit use case. It contains the necessary repositories to count
+// transactions against the limits of the subject's tier.
+type LimitUseCase struct {
+	limitRepo limit.Repository
+	config    *limit.Config
+}
+
+// NewLimitUseCase creates a new limit use case.
+func NewLimitUseCase(limitRepo limit.Repository, config *limit.Config) *LimitUseCase {
+	return &LimitUseCase{
+		limitRepo: limitRepo,
+		config:    config,
+	}
+}
+
+// Reserve counts a transaction of the amount against the subject's limits, failing with a limit.ExceededError
+// when it would go over one. The reservation must be released if the transaction does not go through.
+func (uc *LimitUseCase) Reserve(subject string, amount float32, now time.Time) (*limit.Reservation, error) {
+	tier, err := uc.tier(subject)
+	if err != nil {
+		return nil, err
+	}
+
+	reservation, err := uc.limitRepo.Reserve(subject, amount, uc.config.Tiers[tier], now)
+	if err != nil {
+		log.Printf("error reserving %.2f for %s: %v", amount, subject, err)
+		return nil, err
+	}
+
+	return reservation, nil
+}
+
+// Release gives back a transaction that did not go through.
+func (uc *LimitUseCase) Release(reservation *limit.Reservation) {
+	if err := uc.limitRepo.Release(reservation); err != nil {
+		log.Printf("error releasing %.2f for %s: %v", reservation.Amount, reservation.Subject, err)
+	}
+}
+
+// GetLimits returns the limits of the subject's tier and what it has used today and this month.
+func (uc *LimitUseCase) GetLimits(subject string) (*LimitStatus, error) {
+	tier, err := uc.tier(subject)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	usage, err := uc.limitRepo.Usage(subject, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LimitStatus{
+		Subject:        subject,
+		Tier:           tier,
+		Limits:         uc.config.Tiers[tier],
+		Usage:          *usage,
+		DailyResetAt:   limit.DayResetAt(now),
+		MonthlyResetAt: limit.MonthResetAt(now),
+	}, nil
+}
+
+// SetTier moves the subject to another configured tier.
+func (uc *LimitUseCase) SetTier(subject string, tier limit.Tier) (*LimitStatus, error) {
+	if _, ok := uc.config.Tiers[tier]; !ok || subject == "" {
+		return nil, ErrUnknownTier
+	}
+
+	if err := uc.limitRepo.SetTier(subject, tier); err != nil {
+		return nil, err
+	}
+
+	return uc.GetLimits(subject)
+}
+
+// tier returns the configured tier of a subject, the default tier when it has none
+func (uc *LimitUseCase) tier(subject string) (limit.Tier, error) {
+	tier, err := uc.limitRepo.Tier(subject)
+	if err != nil {
+		return "", err
+	}
+
+	// tiers removed from the configuration fall back to the default
+	if _, ok := uc.config.Tiers[tier]; !ok {
+		tier = uc.config.DefaultTier
+	}
+
+	return tier, nil
+}
@@ -0,0 +1,23 @@
+package overdraft
+
+import "errors"
+
+var (
+	// ErrFacilityNotFound is the error returned when an account has no overdraft facility
+	ErrFacilityNotFound = errors.New("the account has no overdraft")
+
+	// ErrFailedToSaveFacility is the error returned when an overdraft facility cannot be stored
+	ErrFailedToSaveFacility = errors.New("failed to save overdraft. Please try again")
+)
+
+// Database is the interface that wraps the basic overdraft database operations.
+type Database interface {
+	// SaveFacility creates or replaces the overdraft facility of an account
+	SaveFacility(facility *Facility) error
+
+	// GetFacility gets the overdraft facility of an account
+	GetFacility(accountID string) (*Facility, error)
+
+	// GetFacilities gets every overdraft facility, oldest first
+	GetFacilities() ([]*Facility, error)
+}
@@ -0,0 +1,49 @@
+package overdraft
+
+import (
+	"github.com/quabynah-bilson/quantia/pkg/ledger"
+	"time"
+)
+
+// Facility is the entity that represents the overdraft approved for an account. The ledger keeps the limit
+// enforced on holds; the facility remembers whether the account was last seen overdrawn, so that entering and
+// leaving the overdraft are each notified once.
+type Facility struct {
+	AccountID string  `json:"account_id"`
+	Limit     float32 `json:"limit"`
+
+	// Url is the endpoint notified when the account enters or leaves its overdraft
+	Url string `json:"url,omitempty"`
+
+	Overdrawn      bool       `json:"overdrawn"`
+	OverdrawnSince *time.Time `json:"overdrawn_since,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// NewFacility creates the overdraft facility of an account
+func NewFacility(accountID string, limit float32, url string) *Facility {
+	now := time.Now().UTC()
+	return &Facility{
+		AccountID: accountID,
+		Limit:     limit,
+		Url:       url,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Observe records whether the account is overdrawn at a balance, and reports whether it entered or left its
+// overdraft since it was last observed
+func (f *Facility) Observe(balance *ledger.Balance, now time.Time) bool {
+	if balance.IsOverdrawn() == f.Overdrawn {
+		return false
+	}
+
+	f.Overdrawn, f.OverdrawnSince, f.UpdatedAt = balance.IsOverdrawn(), nil, now
+	if f.Overdrawn {
+		f.OverdrawnSince = &now
+	}
+
+	return true
+}
@@ -0,0 +1,13 @@
+package overdraft
+
+// Repository is the overdraft repository interface
+type Repository interface {
+	// Save creates or replaces the overdraft facility of an account.
+	Save(facility *Facility) error
+
+	// Find gets the overdraft facility of an account.
+	Find(accountID string) (*Facility, error)
+
+	// FindAll gets every overdraft facility, oldest first.
+	FindAll() ([]*Facility, error)
+}
@@ -0,0 +1,244 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"github.com/quabynah-bilson/quantia/pkg/event"
+	"github.com/quabynah-bilson/quantia/pkg/ledger"
+	"github.com/quabynah-bilson/quantia/pkg/overdraft"
+	"github.com/quabynah-bilson/quantia/pkg/payment"
+	"github.com/quabynah-bilson/quantia/pkg/product"
+	"log"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrOverdraftNotAllowed is the error returned when an overdraft is approved for an account whose product does not allow one.
+	ErrOverdraftNotAllowed = errors.New("the account's product does not allow an overdraft. Please move the account to a product that does")
+
+	// ErrOverdraftLimitTooHigh is the error returned when an overdraft limit is above the maximum of the account's product.
+	ErrOverdraftLimitTooHigh = errors.New("the overdraft limit is above the maximum of the account's product. Please check and try again")
+
+	// ErrOverdraftLimitInUse is the error returned when an overdraft limit is lowered below what the account already owes.
+	ErrOverdraftLimitInUse = errors.New("the account owes more than the new overdraft limit. Please wait until it is repaid")
+)
+
+// OverdraftUseCase is the overdraft use case. It approves how far below zero accounts may go, within the
+// maximum of their product, and notifies each account when it enters or leaves its overdraft. The limit
+// itself is enforced by the ledger whenever funds are held; the interest is charged by the end-of-day batch.
+type OverdraftUseCase struct {
+	overdraftRepo overdraft.Repository
+	productRepo   product.Repository
+	ledgerRepo    ledger.Repository
+	paymentRepo   payment.Repository
+
+	// mu serializes the changes of facilities, so that each crossing of zero is notified once
+	mu sync.Mutex
+}
+
+// NewOverdraftUseCase creates a new overdraft use case.
+func NewOverdraftUseCase(overdraftRepo overdraft.Repository, productRepo product.Repository, ledgerRepo ledger.Repository, paymentRepo payment.Repository) *OverdraftUseCase {
+	return &OverdraftUseCase{
+		overdraftRepo: overdraftRepo,
+		productRepo:   productRepo,
+		ledgerRepo:    ledgerRepo,
+		paymentRepo:   paymentRepo,
+	}
+}
+
+// SetLimit approves the overdraft limit of an account, up to the maximum of its product. A zero limit
+// withdraws the overdraft; no limit can be below what the account already owes. The url, when given, replaces
+// the endpoint notified when the account enters or leaves its overdraft.
+func (uc *OverdraftUseCase) SetLimit(accountID string, limit float32, url string) (*overdraft.Facility, error) {
+	if accountID == "" {
+		return nil, ErrInvalidAccount
+	}
+	if limit < 0 {
+		return nil, ledger.ErrInvalidOverdraftLimit
+	}
+
+	assignment, err := uc.productRepo.FindAssignment(accountID)
+	if err != nil {
+		return nil, err
+	}
+	p, err := uc.productRepo.Find(assignment.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	if limit > 0 && !p.Overdraft.IsAllowed() {
+		return nil, ErrOverdraftNotAllowed
+	}
+	if limit > p.Overdraft.MaxLimit {
+		return nil, ErrOverdraftLimitTooHigh
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	balance, err := uc.ledgerRepo.Balance(accountID)
+	if err != nil {
+		log.Printf("error getting balance of account %s: %v", accountID, err)
+		return nil, err
+	}
+	if balance.Available < -limit {
+		return nil, ErrOverdraftLimitInUse
+	}
+
+	facility, err := uc.overdraftRepo.Find(accountID)
+	if err != nil {
+		facility = overdraft.NewFacility(accountID, limit, url)
+	}
+	facility.Limit, facility.UpdatedAt = limit, time.Now().UTC()
+	if url != "" {
+		facility.Url = url
+	}
+
+	if err = uc.save(facility); err != nil {
+		return nil, err
+	}
+
+	return facility, nil
+}
+
+// GetFacility gets the overdraft facility of an account.
+func (uc *OverdraftUseCase) GetFacility(accountID string) (*overdraft.Facility, error) {
+	return uc.overdraftRepo.Find(accountID)
+}
+
+// Cap lowers the overdraft limit of an account to a maximum, when its product changes to one allowing less.
+// What the account already owes is kept, but no more can be spent until it is back within the new limit.
+func (uc *OverdraftUseCase) Cap(accountID string, maxLimit float32) error {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	facility, err := uc.overdraftRepo.Find(accountID)
+	if err != nil || facility.Limit <= maxLimit {
+		return nil
+	}
+
+	facility.Limit, facility.UpdatedAt = maxLimit, time.Now().UTC()
+	return uc.save(facility)
+}
+
+// Check looks at the balance of an account with an overdraft facility, and notifies the account when it
+// entered or left its overdraft since it was last checked. Accounts without a facility are not checked.
+func (uc *OverdraftUseCase) Check(accountID string) (*overdraft.Facility, error) {
+	facility, _, err := uc.checkAccount(accountID)
+	return facility, err
+}
+
+// Run checks every account with an overdraft facility, every interval until the context is cancelled.
+func (uc *OverdraftUseCase) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		uc.RunChecks()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunChecks checks every account with an overdraft facility, and returns how many entered or left their
+// overdraft since they were last checked.
+func (uc *OverdraftUseCase) RunChecks() int {
+	facilities, err := uc.overdraftRepo.FindAll()
+	if err != nil {
+		log.Printf("error getting overdrafts: %v", err)
+		return 0
+	}
+
+	changed := 0
+	for _, f := range facilities {
+		if _, ok, err := uc.checkAccount(f.AccountID); err != nil {
+			log.Printf("error checking overdraft of account %s: %v", f.AccountID, err)
+		} else if ok {
+			changed++
+		}
+	}
+
+	return changed
+}
+
+// checkAccount checks the facility of an account, and reports whether the account entered or left its overdraft.
+func (uc *OverdraftUseCase) checkAccount(accountID string) (*overdraft.Facility, bool, error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	facility, err := uc.overdraftRepo.Find(accountID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	changed, err := uc.check(facility)
+	return facility, changed, err
+}
+
+// check records whether the account of a facility is overdrawn, notifies it when that changed, and reports
+// whether it did.
+func (uc *OverdraftUseCase) check(facility *overdraft.Facility) (bool, error) {
+	balance, err := uc.ledgerRepo.Balance(facility.AccountID)
+	if err != nil {
+		log.Printf("error getting balance of account %s: %v", facility.AccountID, err)
+		return false, err
+	}
+
+	if !facility.Observe(balance, time.Now().UTC()) {
+		return false, nil
+	}
+
+	if err = uc.overdraftRepo.Save(facility); err != nil {
+		log.Printf("error saving overdraft of account %s: %v", facility.AccountID, err)
+		return false, err
+	}
+
+	eventType := event.TypeAccountOverdraftRepaid
+	if facility.Overdrawn {
+		eventType = event.TypeAccountOverdrawn
+	}
+	uc.notify(facility, balance, eventType)
+
+	return true, nil
+}
+
+// save stores a facility and the limit the ledger enforces for its account.
+func (uc *OverdraftUseCase) save(facility *overdraft.Facility) error {
+	if err := uc.ledgerRepo.SetOverdraftLimit(facility.AccountID, facility.Limit); err != nil {
+		log.Printf("error setting overdraft limit of account %s: %v", facility.AccountID, err)
+		return err
+	}
+
+	if err := uc.overdraftRepo.Save(facility); err != nil {
+		log.Printf("error saving overdraft of account %s: %v", facility.AccountID, err)
+		return err
+	}
+
+	return nil
+}
+
+// notify queues an overdraft event for the account, when it has an endpoint.
+func (uc *OverdraftUseCase) notify(facility *overdraft.Facility, balance *ledger.Balance, eventType event.Type) {
+	if facility.Url == "" {
+		return
+	}
+
+	envelope, err := event.New(eventType, &event.OverdraftData{
+		AccountID:      facility.AccountID,
+		Balance:        balance.Available,
+		Limit:          facility.Limit,
+		OverdrawnSince: facility.OverdrawnSince,
+	})
+	if err != nil {
+		log.Printf("error creating %s event: %v", eventType, err)
+		return
+	}
+
+	if err = uc.paymentRepo.Notify(facility.Url, envelope); err != nil {
+		log.Printf("error queueing %s event: %v", eventType, err)
+	}
+}
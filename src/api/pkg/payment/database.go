@@ -1,6 +1,10 @@
 package payment
 
-import "errors"
+import (
+	"context"
+	"errors"
+	"github.com/quabynah-bilson/quantia/pkg/event"
+)
 
 var (
 	// ErrFailedToMarshalTransaction is the error returned when a transaction fails to marshal
@@ -8,13 +12,62 @@ var (
 
 	// ErrFailedToSubscribeToWebhook is the error returned when a webhook subscription fails
 	ErrFailedToSubscribeToWebhook = errors.New("failed to subscribe to webhook. Please check and try again")
+
+	// ErrFailedToCheckpointWebhook is the error returned when a webhook cannot be saved for later delivery
+	ErrFailedToCheckpointWebhook = errors.New("failed to checkpoint webhook. Please check and try again")
+
+	// ErrTransactionNotFound is the error returned when a transaction does not exist
+	ErrTransactionNotFound = errors.New("transaction not found")
+
+	// ErrFailedToSaveTransaction is the error returned when a transaction cannot be stored
+	ErrFailedToSaveTransaction = errors.New("failed to save transaction. Please try again")
+
+	// ErrRefundNotFound is the error returned when a refund does not exist
+	ErrRefundNotFound = errors.New("refund not found")
+
+	// ErrFailedToSaveRefund is the error returned when a refund cannot be stored
+	ErrFailedToSaveRefund = errors.New("failed to save refund. Please try again")
+
+	// ErrRefundExceedsAmount is the error returned when refunds would return more than the transaction amount
+	ErrRefundExceedsAmount = errors.New("refund exceeds the amount left to refund on this transaction")
 )
 
 // Database is the interface that wraps the basic payment database operations.
 type Database interface {
-	// SendWebhook sends a webhook to a URL
+	// SendWebhook creates a pending transaction and queues its payment.created webhook for a URL
 	SendWebhook(amount float32, url string) (*Transaction, error)
 
-	// SubscribeToWebhook subscribes to a webhook
-	SubscribeToWebhook(url string, queue chan *WebhookPayload) error
+	// PublishEvent queues an event for delivery to a URL
+	PublishEvent(url string, envelope *event.Envelope) error
+
+	// SaveTransaction creates or replaces a transaction
+	SaveTransaction(transaction *Transaction) error
+
+	// GetTransaction gets a transaction by ID
+	GetTransaction(id string) (*Transaction, error)
+
+	// SaveRefund creates or replaces a refund
+	SaveRefund(refund *Refund) error
+
+	// GetRefund gets a refund by ID
+	GetRefund(id string) (*Refund, error)
+
+	// GetRefunds gets the refunds of a transaction, oldest first
+	GetRefunds(transactionID string) ([]*Refund, error)
+
+	// ReserveRefund atomically adds an amount to the refunds of a transaction, failing with
+	// ErrRefundExceedsAmount if the total would go over the limit
+	ReserveRefund(transactionID string, amount, limit float32) error
+
+	// ReleaseRefund gives back an amount reserved by ReserveRefund
+	ReleaseRefund(transactionID string, amount float32) error
+
+	// SubscribeToWebhook subscribes to a webhook until the context is cancelled
+	SubscribeToWebhook(ctx context.Context, url string, queue chan *WebhookPayload) error
+
+	// CheckpointWebhook saves an undelivered webhook so that it can be resumed after a restart
+	CheckpointWebhook(payload *WebhookPayload) error
+
+	// RestoreWebhooks removes and returns all checkpointed webhooks
+	RestoreWebhooks() ([]*WebhookPayload, error)
 }
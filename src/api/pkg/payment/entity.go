@@ -1,6 +1,11 @@
 package payment
 
-import "time"
+import (
+	"github.com/google/uuid"
+	"github.com/quabynah-bilson/quantia/pkg/event"
+	"strings"
+	"time"
+)
 
 // TransactionStatus is the type that represents a transaction status
 type TransactionStatus string
@@ -12,8 +17,17 @@ const (
 	// TransactionStatusFailed is the status of a failed transaction
 	TransactionStatusFailed TransactionStatus = "failed"
 
+	// TransactionStatusInReview is the status of a transaction held by the fraud checks until an analyst decides on it
+	TransactionStatusInReview TransactionStatus = "in_review"
+
 	// TransactionStatusSuccess is the status of a successful transaction
 	TransactionStatusSuccess TransactionStatus = "success"
+
+	// TransactionStatusPartiallyRefunded is the status of a successful transaction of which part has been refunded
+	TransactionStatusPartiallyRefunded TransactionStatus = "partially_refunded"
+
+	// TransactionStatusRefunded is the status of a successful transaction that has been refunded in full
+	TransactionStatusRefunded TransactionStatus = "refunded"
 )
 
 // Transaction is the entity that represents a payment transaction
@@ -21,27 +35,99 @@ type Transaction struct {
 	ID     string            `json:"id"`
 	Amount float32           `json:"amount"`
 	Status TransactionStatus `json:"status"`
+
+	// Url is the merchant endpoint notified about this transaction
+	Url string `json:"url,omitempty"`
+
+	// ProviderReference is the payment provider's ID for this transaction
+	ProviderReference string `json:"provider_reference,omitempty"`
+
+	// Source is the payer's instrument. Payments count against the limits of their source.
+	Source string `json:"source,omitempty"`
+
+	// LimitReservedAt is when the payment was counted against the limits of its source, which sets the
+	// windows it is given back to if it fails
+	LimitReservedAt *time.Time `json:"limit_reserved_at,omitempty"`
+
+	// RefundedAmount is the total of the transaction's successful refunds
+	RefundedAmount float32   `json:"refunded_amount,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
 }
 
-// WebhookPayload is the entity that represents a webhook payload
-type WebhookPayload struct {
-	ID     string             `json:"id"`
-	Status TransactionStatus  `json:"status"`
-	Url    string             `json:"url"`
-	Amount float32            `json:"amount"`
-	Data   WebhookPayloadData `json:"data"`
+// IsRefundable reports whether money can be returned from the transaction
+func (t *Transaction) IsRefundable() bool {
+	return t.Status == TransactionStatusSuccess || t.Status == TransactionStatusPartiallyRefunded
+}
+
+// IsComplete reports whether the transaction has succeeded or failed, so that no provider result applies to it anymore
+func (t *Transaction) IsComplete() bool {
+	return t.Status != TransactionStatusPending && t.Status != TransactionStatusInReview
+}
+
+// refundIDPrefix distinguishes refund IDs from transaction IDs in provider references
+const refundIDPrefix = "rfd_"
+
+// RefundStatus is the type that represents a refund status
+type RefundStatus string
+
+const (
+	// RefundStatusPending is the status of a refund waiting for the provider
+	RefundStatusPending RefundStatus = "pending"
+
+	// RefundStatusSucceeded is the status of a refund returned to the payer
+	RefundStatusSucceeded RefundStatus = "succeeded"
+
+	// RefundStatusFailed is the status of a refund rejected by the provider
+	RefundStatusFailed RefundStatus = "failed"
+)
+
+// Refund is the entity that represents money returned from a transaction to its payer
+type Refund struct {
+	ID            string       `json:"id"`
+	TransactionID string       `json:"transaction_id"`
+	Amount        float32      `json:"amount"`
+	Reason        string       `json:"reason,omitempty"`
+	Status        RefundStatus `json:"status"`
+
+	// ProviderReference is the payment provider's ID for this refund
+	ProviderReference string    `json:"provider_reference,omitempty"`
+	FailureReason     string    `json:"failure_reason,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// NewRefund creates a pending refund of an amount of the given transaction
+func NewRefund(transactionID string, amount float32, reason string) *Refund {
+	return &Refund{
+		ID:            refundIDPrefix + uuid.NewString(),
+		TransactionID: transactionID,
+		Amount:        amount,
+		Reason:        reason,
+		Status:        RefundStatusPending,
+		CreatedAt:     time.Now().UTC(),
+	}
 }
 
-// WebhookPayloadData is the entity that represents a webhook payload data
-type WebhookPayloadData struct {
-	TransactionID string `json:"transaction_id"`
-	Date          string `json:"created_at"`
+// IsRefundReference reports whether a provider result reference is a refund ID
+func IsRefundReference(reference string) bool {
+	return strings.HasPrefix(reference, refundIDPrefix)
+}
+
+// WebhookPayload is the entity that represents a queued webhook: an event envelope and where to deliver it
+type WebhookPayload struct {
+	// ID is the ID of the event being delivered
+	ID    string          `json:"id"`
+	Url   string          `json:"url"`
+	Event *event.Envelope `json:"event"`
+
+	// Attempt is the number of delivery attempts made so far
+	Attempt int `json:"attempt,omitempty"`
 }
 
-// Payload is the entity that represents a webhook payload
-type Payload struct {
-	ID      string    `json:"id"`
-	Event   string    `json:"event"`
-	Date    time.Time `json:"date"`
-	Payment string    `json:"payment"`
+// NewWebhookPayload wraps an event for delivery to the given URL
+func NewWebhookPayload(url string, envelope *event.Envelope) *WebhookPayload {
+	return &WebhookPayload{
+		ID:    envelope.ID,
+		Url:   url,
+		Event: envelope,
+	}
 }
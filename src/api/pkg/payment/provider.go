@@ -0,0 +1,137 @@
+package payment
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// ErrPaymentDeclined is the error returned when the provider declines a payment
+	ErrPaymentDeclined = errors.New("payment declined by provider")
+
+	// ErrRefundDeclined is the error returned when the provider declines a refund
+	ErrRefundDeclined = errors.New("refund declined by provider")
+
+	// ErrDisbursementDeclined is the error returned when the provider declines a disbursement
+	ErrDisbursementDeclined = errors.New("disbursement declined by provider")
+
+	// ErrAccountHolderNotFound is the error returned when the provider has no account for a payee
+	ErrAccountHolderNotFound = errors.New("account holder not found at provider")
+
+	// ErrProviderTimeout is the error returned when the provider does not answer in time. The outcome is unknown.
+	ErrProviderTimeout = errors.New("payment provider timed out. Please check the payment status later")
+
+	// ErrProviderReferenceNotFound is the error returned when the provider does not know a payment reference
+	ErrProviderReferenceNotFound = errors.New("payment not found at provider")
+
+	// ErrInvalidProviderOperation is the error returned when an operation is not allowed in the payment's current state
+	ErrInvalidProviderOperation = errors.New("operation not allowed for this payment. Please check and try again")
+
+	// ErrProviderUnavailable is the error returned when the provider rejects or fails a request
+	ErrProviderUnavailable = errors.New("payment provider unavailable. Please try again later")
+
+	// ErrInvalidCallback is the error returned when a provider callback cannot be understood
+	ErrInvalidCallback = errors.New("invalid provider callback")
+)
+
+// ProviderStatus is the type that represents the status of a payment at the provider
+type ProviderStatus string
+
+const (
+	// ProviderStatusPending means the provider will report the outcome asynchronously
+	ProviderStatusPending ProviderStatus = "pending"
+
+	// ProviderStatusAuthorized means the funds are reserved but not yet captured
+	ProviderStatusAuthorized ProviderStatus = "authorized"
+
+	// ProviderStatusCaptured means the funds have been settled
+	ProviderStatusCaptured ProviderStatus = "captured"
+
+	// ProviderStatusVoided means the authorization was released without capture
+	ProviderStatusVoided ProviderStatus = "voided"
+
+	// ProviderStatusRefunded means captured funds have been (partially) returned
+	ProviderStatusRefunded ProviderStatus = "refunded"
+
+	// ProviderStatusDeclined means the provider refused the payment
+	ProviderStatusDeclined ProviderStatus = "declined"
+
+	// ProviderStatusDisbursed means money has been sent out to the payee
+	ProviderStatusDisbursed ProviderStatus = "disbursed"
+)
+
+// AuthorizeRequest is the entity that represents a request to reserve funds at the provider
+type AuthorizeRequest struct {
+	// Reference is our transaction ID, echoed back in every result
+	Reference string  `json:"reference"`
+	Amount    float32 `json:"amount"`
+
+	// Source identifies the payer's instrument (card token, wallet number...)
+	Source string `json:"source,omitempty"`
+}
+
+// RefundRequest is the entity that represents a request to return captured funds to the payer
+type RefundRequest struct {
+	// Reference is our refund ID, echoed back in every result
+	Reference string `json:"reference"`
+
+	// ProviderReference is the provider's ID of the payment being refunded
+	ProviderReference string  `json:"provider_reference"`
+	Amount            float32 `json:"amount"`
+}
+
+// ProviderResult is the entity that represents the provider's view of a payment
+type ProviderResult struct {
+	Reference         string         `json:"reference"`
+	ProviderReference string         `json:"provider_reference"`
+	Status            ProviderStatus `json:"status"`
+	AuthorizedAmount  float32        `json:"authorized_amount"`
+	CapturedAmount    float32        `json:"captured_amount"`
+	RefundedAmount    float32        `json:"refunded_amount"`
+	DeclineReason     string         `json:"decline_reason,omitempty"`
+}
+
+// PaymentProvider is the interface that wraps the operations of a payment service provider.
+type PaymentProvider interface {
+	// Authorize reserves the amount on the payer's instrument. The result may be pending if the provider answers asynchronously.
+	Authorize(ctx context.Context, req *AuthorizeRequest) (*ProviderResult, error)
+
+	// Capture settles all or part of an authorized amount
+	Capture(ctx context.Context, providerReference string, amount float32) (*ProviderResult, error)
+
+	// Void releases an authorization that has not been captured
+	Void(ctx context.Context, providerReference string) (*ProviderResult, error)
+
+	// Refund returns all or part of a captured amount to the payer. The result carries the refund's reference
+	// and may be pending if the provider answers asynchronously.
+	Refund(ctx context.Context, req *RefundRequest) (*ProviderResult, error)
+
+	// Status returns the provider's current view of a payment
+	Status(ctx context.Context, providerReference string) (*ProviderResult, error)
+}
+
+// DisbursementRequest is the entity that represents a request to send money to a payee
+type DisbursementRequest struct {
+	// Reference is our ID for the disbursement, echoed back in every result
+	Reference string  `json:"reference"`
+	Amount    float32 `json:"amount"`
+
+	// Destination identifies the payee's instrument (wallet number, bank account...)
+	Destination string `json:"destination"`
+	Note        string `json:"note,omitempty"`
+}
+
+// PayoutProvider is the interface that wraps the operations of a provider that can send money out.
+type PayoutProvider interface {
+	// Disburse sends the amount to the destination. The result is usually pending until the provider confirms it.
+	Disburse(ctx context.Context, req *DisbursementRequest) (*ProviderResult, error)
+
+	// DisbursementStatus returns the provider's current view of a disbursement
+	DisbursementStatus(ctx context.Context, providerReference string) (*ProviderResult, error)
+}
+
+// CallbackParser is the interface implemented by providers that report results through HTTP callbacks.
+type CallbackParser interface {
+	// ParseCallback converts the body of a callback into a provider result
+	ParseCallback(body []byte) (*ProviderResult, error)
+}
@@ -1,10 +1,45 @@
 package payment
 
+import (
+	"context"
+	"github.com/quabynah-bilson/quantia/pkg/event"
+)
+
 // Repository is the payment repository interface
 type Repository interface {
 	// Pay pays an amount to a given URL.
 	Pay(amount float32, url string) (*Transaction, error)
 
-	// Subscribe subscribes to a given webhook URL.
-	Subscribe(url string, queue chan *WebhookPayload) error
+	// Save creates or replaces a transaction.
+	Save(transaction *Transaction) error
+
+	// Find gets a transaction by ID.
+	Find(id string) (*Transaction, error)
+
+	// SaveRefund creates or replaces a refund.
+	SaveRefund(refund *Refund) error
+
+	// FindRefund gets a refund by ID.
+	FindRefund(id string) (*Refund, error)
+
+	// FindRefunds gets the refunds of a transaction, oldest first.
+	FindRefunds(transactionID string) ([]*Refund, error)
+
+	// ReserveRefund reserves an amount of a transaction for a refund, without going over the limit.
+	ReserveRefund(transactionID string, amount, limit float32) error
+
+	// ReleaseRefund gives back an amount reserved for a refund that failed.
+	ReleaseRefund(transactionID string, amount float32) error
+
+	// Notify queues an event for delivery to the given URL.
+	Notify(url string, envelope *event.Envelope) error
+
+	// Subscribe subscribes to a given webhook URL until the context is cancelled.
+	Subscribe(ctx context.Context, url string, queue chan *WebhookPayload) error
+
+	// Checkpoint saves an undelivered webhook for later delivery.
+	Checkpoint(payload *WebhookPayload) error
+
+	// Restore returns the webhooks saved by Checkpoint.
+	Restore() ([]*WebhookPayload, error)
 }
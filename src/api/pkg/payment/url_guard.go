@@ -0,0 +1,20 @@
+package payment
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// ErrUnsafeURL is the error returned when a merchant URL points to a private, loopback or otherwise forbidden destination
+	ErrUnsafeURL = errors.New("URL is not allowed. Please use a publicly reachable endpoint")
+
+	// ErrInsecureURL is the error returned when a merchant URL does not use HTTPS where it is required
+	ErrInsecureURL = errors.New("URL must use HTTPS. Please check and try again")
+)
+
+// URLGuard is the interface that wraps the checks made before calling a merchant-supplied URL.
+type URLGuard interface {
+	// CheckURL resolves the URL's host and returns an error if any of its addresses may not be called
+	CheckURL(ctx context.Context, rawURL string) error
+}
@@ -0,0 +1,45 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrWebhookDeliveryFailed is the error returned when a webhook could not be delivered to its destination
+	ErrWebhookDeliveryFailed = errors.New("failed to deliver webhook. Please check and try again")
+)
+
+// DeliveryError is the error returned when a merchant endpoint answers a webhook with a non-2xx status
+type DeliveryError struct {
+	Status int
+	Delay  time.Duration
+}
+
+// Error returns the error message
+func (e *DeliveryError) Error() string {
+	return fmt.Sprintf("%v: unexpected status %d", ErrWebhookDeliveryFailed, e.Status)
+}
+
+// Unwrap returns ErrWebhookDeliveryFailed so that callers can match it with errors.Is
+func (e *DeliveryError) Unwrap() error {
+	return ErrWebhookDeliveryFailed
+}
+
+// StatusCode returns the HTTP status answered by the merchant
+func (e *DeliveryError) StatusCode() int {
+	return e.Status
+}
+
+// RetryAfter returns the delay requested by the merchant's Retry-After header
+func (e *DeliveryError) RetryAfter() time.Duration {
+	return e.Delay
+}
+
+// WebhookClient is the interface that wraps the basic webhook delivery operations.
+type WebhookClient interface {
+	// Deliver sends the given payload to its destination URL
+	Deliver(ctx context.Context, payload *WebhookPayload) error
+}
@@ -1,10 +1,18 @@
 package pkg
 
 import (
+	"context"
 	"errors"
+	"github.com/quabynah-bilson/quantia/pkg/event"
+	"github.com/quabynah-bilson/quantia/pkg/fraud"
+	"github.com/quabynah-bilson/quantia/pkg/ledger"
+	"github.com/quabynah-bilson/quantia/pkg/limit"
 	"github.com/quabynah-bilson/quantia/pkg/payment"
 	"log"
+	"math"
 	"regexp"
+	"sync"
+	"time"
 )
 
 var (
@@ -13,38 +21,569 @@ var (
 
 	// ErrInvalidURL is the error returned when a URL is invalid.
 	ErrInvalidURL = errors.New("invalid URL. Please check and try again")
+
+	// ErrPaymentNotRefundable is the error returned when a refund is requested for a payment that has not succeeded.
+	ErrPaymentNotRefundable = errors.New("only successful payments can be refunded")
+
+	// ErrSourceRequired is the error returned when a payment without a source would escape the limits and fraud checks.
+	ErrSourceRequired = errors.New("invalid source. a payment source is required")
 )
 
+// providerTimeout bounds every call made to the payment provider.
+const providerTimeout = 30 * time.Second
+
 // PaymentUseCase is the payment use case. It contains the necessary repositories to perform payment operations.
 type PaymentUseCase struct {
 	paymentRepo payment.Repository
+	ledgerRepo  ledger.Repository
+	urlGuard    payment.URLGuard
+	provider    payment.PaymentProvider
+
+	// refundMu serializes the updates of a transaction's refunded amount
+	refundMu sync.Mutex
+
+	// resultMu serializes the completion of transactions, so that a callback and a poll cannot both apply a result
+	resultMu sync.Mutex
+
+	// routes complete the operations of other use cases (e.g. transfers) that went through the provider
+	routes []resultRoute
+
+	// limits counts payments against the limits of their source. Payments are not limited when it is nil.
+	limits *LimitUseCase
+
+	// fraud scores payments before they are charged. Payments are not scored when it is nil.
+	fraud *FraudUseCase
+
+	// completions are told about payments that succeeded or failed (e.g. to mark invoices paid)
+	completions []func(transaction *payment.Transaction)
+}
+
+// resultRoute sends the provider results whose reference matches to another use case
+type resultRoute struct {
+	matches func(reference string) bool
+	handle  func(result *payment.ProviderResult) error
 }
 
 // NewPaymentUseCase creates a new payment use case.
-func NewPaymentUseCase(paymentRepo payment.Repository) *PaymentUseCase {
+func NewPaymentUseCase(paymentRepo payment.Repository, ledgerRepo ledger.Repository, urlGuard payment.URLGuard, provider payment.PaymentProvider) *PaymentUseCase {
 	return &PaymentUseCase{
 		paymentRepo: paymentRepo,
+		ledgerRepo:  ledgerRepo,
+		urlGuard:    urlGuard,
+		provider:    provider,
 	}
 }
 
-// MakePayment makes a payment.
-func (uc *PaymentUseCase) MakePayment(amount float32, url string) (*payment.Transaction, error) {
+// RouteResults sends the provider results whose reference matches (e.g. transfers) to the handler instead
+// of completing a payment. Routes must be set up before results arrive.
+func (uc *PaymentUseCase) RouteResults(matches func(reference string) bool, handler func(result *payment.ProviderResult) error) {
+	uc.routes = append(uc.routes, resultRoute{matches: matches, handle: handler})
+}
+
+// OnCompleted registers a handler told about every payment once it has succeeded or failed, whether it
+// completed right away or through a provider result. Handlers must be registered before payments are made.
+func (uc *PaymentUseCase) OnCompleted(handler func(transaction *payment.Transaction)) {
+	uc.completions = append(uc.completions, handler)
+}
+
+// SetLimits makes payments count against the transaction limits of their source.
+func (uc *PaymentUseCase) SetLimits(limits *LimitUseCase) {
+	uc.limits = limits
+}
+
+// SetFraud makes payments pass the fraud checks before they are charged, and resumes the payments held for
+// review once an analyst has decided on them.
+func (uc *PaymentUseCase) SetFraud(fraud *FraudUseCase) {
+	uc.fraud = fraud
+	fraud.OnReviewed(uc.completeReview)
+}
+
+// MakePayment makes a payment from the given source (card token, wallet number...). The amount is
+// charged through the payment provider and the merchant webhooks are queued for asynchronous delivery.
+// If the provider answers asynchronously or times out, the transaction is returned pending.
+// Payments with a source must fit within its transaction limits and pass the fraud checks, which use the
+// origin of the request when it is known: blocked payments fail and others may be held for review.
+func (uc *PaymentUseCase) MakePayment(amount float32, url, source string, origin *fraud.Origin) (*payment.Transaction, error) {
 	if err := validateAmount(amount); err != nil {
 		log.Printf("error validating amount: %v", err)
 		return nil, err
 	}
 
-	return uc.paymentRepo.Pay(amount, url)
+	if err := uc.checkURL(url); err != nil {
+		return nil, err
+	}
+
+	// limits and fraud scores are kept per source, so payments must name theirs once either is enabled
+	if source == "" && (uc.limits != nil || uc.fraud != nil) {
+		return nil, ErrSourceRequired
+	}
+
+	// count the payment against the payer's limits before anything is charged
+	var reservation *limit.Reservation
+	if uc.limits != nil {
+		var err error
+		if reservation, err = uc.limits.Reserve(source, amount, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+
+	transaction, err := uc.paymentRepo.Pay(amount, url)
+	if err != nil {
+		log.Printf("error creating transaction: %v", err)
+		if reservation != nil {
+			uc.limits.Release(reservation)
+		}
+		return nil, err
+	}
+	transaction.Source = source
+	if reservation != nil {
+		transaction.LimitReservedAt = &reservation.At
+	}
+
+	// score the payment before it is charged
+	if uc.fraud != nil {
+		assessment, err := uc.fraud.Assess(transaction.ID, uc.signals(transaction, origin))
+		switch {
+		case err != nil:
+			return uc.reject(transaction, err)
+		case assessment.Decision == fraud.DecisionBlock:
+			return uc.reject(transaction, fraud.ErrPaymentBlocked)
+		case assessment.Decision == fraud.DecisionReview:
+			// nothing is charged until an analyst approves the payment
+			transaction.Status = payment.TransactionStatusInReview
+			return transaction, uc.paymentRepo.Save(transaction)
+		}
+	}
+
+	return uc.authorize(transaction)
+}
+
+// HandleProviderResult completes a pending transaction with an asynchronous result from the payment provider.
+// Results for refunds complete the refund and return its transaction; routed results return no transaction.
+func (uc *PaymentUseCase) HandleProviderResult(result *payment.ProviderResult) (*payment.Transaction, error) {
+	for _, route := range uc.routes {
+		if route.matches(result.Reference) {
+			return nil, route.handle(result)
+		}
+	}
+
+	if payment.IsRefundReference(result.Reference) {
+		refund, err := uc.paymentRepo.FindRefund(result.Reference)
+		if err != nil {
+			log.Printf("error finding refund %s: %v", result.Reference, err)
+			return nil, err
+		}
+
+		if _, err = uc.applyRefundResult(refund, result); err != nil && !errors.Is(err, payment.ErrRefundDeclined) {
+			return nil, err
+		}
+
+		return uc.paymentRepo.Find(refund.TransactionID)
+	}
+
+	transaction, err := uc.paymentRepo.Find(result.Reference)
+	if err != nil {
+		log.Printf("error finding transaction %s: %v", result.Reference, err)
+		return nil, err
+	}
+
+	// results for transactions that are already complete are ignored
+	if transaction.Status != payment.TransactionStatusPending {
+		return transaction, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), providerTimeout)
+	defer cancel()
+
+	return uc.applyProviderResult(ctx, transaction, result)
 }
 
-// Subscribe subscribes to a webhook.
-func (uc *PaymentUseCase) Subscribe(url string, queue chan *payment.WebhookPayload) error {
+// GetPayment gets a transaction by ID. A pending transaction is refreshed by polling the provider,
+// in case its callback was missed.
+func (uc *PaymentUseCase) GetPayment(id string) (*payment.Transaction, error) {
+	transaction, err := uc.paymentRepo.Find(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if transaction.Status != payment.TransactionStatusPending || transaction.ProviderReference == "" {
+		return transaction, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), providerTimeout)
+	defer cancel()
+
+	result, err := uc.provider.Status(ctx, transaction.ProviderReference)
+	if err != nil {
+		log.Printf("error polling status of transaction %s: %v", transaction.ID, err)
+		return transaction, nil
+	}
+
+	transaction, err = uc.applyProviderResult(ctx, transaction, result)
+	if errors.Is(err, payment.ErrPaymentDeclined) {
+		// a declined payment is a valid state to report
+		return transaction, nil
+	}
+
+	return transaction, err
+}
+
+// RefundPayment returns all or part of a successful payment to its payer. The cumulative amount of
+// the payment's refunds never exceeds the original amount. If the provider answers asynchronously
+// the refund is returned pending.
+func (uc *PaymentUseCase) RefundPayment(transactionID string, amount float32, reason string) (*payment.Refund, error) {
+	if err := validateAmount(amount); err != nil {
+		log.Printf("error validating amount: %v", err)
+		return nil, err
+	}
+
+	transaction, err := uc.paymentRepo.Find(transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !transaction.IsRefundable() {
+		return nil, ErrPaymentNotRefundable
+	}
+
+	// reserve the amount first so that concurrent refunds cannot exceed the payment
+	if err = uc.paymentRepo.ReserveRefund(transaction.ID, amount, transaction.Amount); err != nil {
+		log.Printf("error reserving refund of transaction %s: %v", transaction.ID, err)
+		return nil, err
+	}
+
+	refund := payment.NewRefund(transaction.ID, amount, reason)
+	if err = uc.paymentRepo.SaveRefund(refund); err != nil {
+		log.Printf("error saving refund of transaction %s: %v", transaction.ID, err)
+		uc.releaseRefund(refund)
+		return nil, err
+	}
+
+	uc.notifyRefund(transaction, refund, event.TypeRefundCreated)
+
+	ctx, cancel := context.WithTimeout(context.Background(), providerTimeout)
+	defer cancel()
+
+	result, err := uc.provider.Refund(ctx, &payment.RefundRequest{
+		Reference:         refund.ID,
+		ProviderReference: transaction.ProviderReference,
+		Amount:            amount,
+	})
+	if errors.Is(err, payment.ErrInvalidProviderOperation) {
+		// the provider will not refund this payment, so the refund cannot succeed later
+		return uc.applyRefundResult(refund, &payment.ProviderResult{
+			Reference:     refund.ID,
+			Status:        payment.ProviderStatusDeclined,
+			DeclineReason: err.Error(),
+		})
+	}
+	if result == nil {
+		// the outcome is unknown, so the refund stays pending until the provider reports back
+		log.Printf("error refunding transaction %s: %v", transaction.ID, err)
+		return refund, nil
+	}
+
+	return uc.applyRefundResult(refund, result)
+}
+
+// GetRefunds gets the refunds of a transaction, oldest first.
+func (uc *PaymentUseCase) GetRefunds(transactionID string) ([]*payment.Refund, error) {
+	if _, err := uc.paymentRepo.Find(transactionID); err != nil {
+		return nil, err
+	}
+
+	return uc.paymentRepo.FindRefunds(transactionID)
+}
+
+// Subscribe subscribes to a webhook until the context is cancelled.
+func (uc *PaymentUseCase) Subscribe(ctx context.Context, url string, queue chan *payment.WebhookPayload) error {
 	if err := validateURL(url); err != nil {
 		log.Printf("error validating URL: %v", err)
 		return err
 	}
 
-	return uc.paymentRepo.Subscribe(url, queue)
+	return uc.paymentRepo.Subscribe(ctx, url, queue)
+}
+
+// applyProviderResult moves the transaction to the state reported by the provider, capturing
+// authorized funds, and notifies the merchant once the payment has succeeded or failed. Results for
+// transactions that are already complete are ignored. A capture of another amount than the transaction's
+// fails the payment instead of booking it as paid in full.
+func (uc *PaymentUseCase) applyProviderResult(ctx context.Context, transaction *payment.Transaction, result *payment.ProviderResult) (*payment.Transaction, error) {
+	uc.resultMu.Lock()
+	defer uc.resultMu.Unlock()
+
+	// another result may have completed the transaction since it was read
+	if stored, err := uc.paymentRepo.Find(transaction.ID); err == nil && stored.IsComplete() {
+		return stored, nil
+	}
+
+	// callbacks do not always repeat the provider reference
+	if result.ProviderReference != "" {
+		transaction.ProviderReference = result.ProviderReference
+	}
+
+	if result.Status == payment.ProviderStatusAuthorized {
+		captured, err := uc.provider.Capture(ctx, result.ProviderReference, transaction.Amount)
+		if err != nil {
+			// the authorization stands, so the capture can be retried from the pending transaction
+			log.Printf("error capturing transaction %s: %v", transaction.ID, err)
+			return transaction, uc.paymentRepo.Save(transaction)
+		}
+		result = captured
+	}
+
+	var eventType event.Type
+	switch result.Status {
+	case payment.ProviderStatusCaptured:
+		if toCents(result.CapturedAmount) != toCents(transaction.Amount) {
+			log.Printf("error capturing transaction %s: captured %v instead of %v", transaction.ID, result.CapturedAmount, transaction.Amount)
+			transaction.Status, eventType = payment.TransactionStatusFailed, event.TypePaymentFailed
+			break
+		}
+		transaction.Status, eventType = payment.TransactionStatusSuccess, event.TypePaymentSucceeded
+	case payment.ProviderStatusDeclined:
+		transaction.Status, eventType = payment.TransactionStatusFailed, event.TypePaymentFailed
+	default:
+		// still pending at the provider
+		return transaction, uc.paymentRepo.Save(transaction)
+	}
+
+	if transaction.Status == payment.TransactionStatusSuccess {
+		// the collected funds are now owed to the merchant. The transaction stays pending until they are
+		// posted, so that the next result or poll posts them again.
+		if err := uc.post(ledger.NewTransfer(transaction.ID, "payment", ledger.PaymentsClearingAccountID, ledger.MerchantAccountID(transaction.Url), transaction.Amount)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := uc.paymentRepo.Save(transaction); err != nil {
+		log.Printf("error saving transaction %s: %v", transaction.ID, err)
+		return nil, err
+	}
+
+	uc.notify(transaction, eventType)
+	uc.complete(transaction)
+
+	if transaction.Status == payment.TransactionStatusFailed {
+		uc.releaseLimits(transaction)
+		return transaction, payment.ErrPaymentDeclined
+	}
+
+	return transaction, nil
+}
+
+// authorize charges a pending transaction through the provider.
+func (uc *PaymentUseCase) authorize(transaction *payment.Transaction) (*payment.Transaction, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), providerTimeout)
+	defer cancel()
+
+	result, err := uc.provider.Authorize(ctx, &payment.AuthorizeRequest{Reference: transaction.ID, Amount: transaction.Amount, Source: transaction.Source})
+	if result == nil {
+		// the outcome is unknown, so the transaction stays pending until the provider reports back
+		log.Printf("error authorizing transaction %s: %v", transaction.ID, err)
+		return transaction, uc.paymentRepo.Save(transaction)
+	}
+
+	return uc.applyProviderResult(ctx, transaction, result)
+}
+
+// reject fails a transaction that was never sent to the provider and notifies the merchant.
+func (uc *PaymentUseCase) reject(transaction *payment.Transaction, reason error) (*payment.Transaction, error) {
+	log.Printf("rejecting transaction %s: %v", transaction.ID, reason)
+	transaction.Status = payment.TransactionStatusFailed
+	if err := uc.paymentRepo.Save(transaction); err != nil {
+		log.Printf("error saving transaction %s: %v", transaction.ID, err)
+		return nil, err
+	}
+
+	uc.notify(transaction, event.TypePaymentFailed)
+	uc.releaseLimits(transaction)
+	uc.complete(transaction)
+
+	return transaction, reason
+}
+
+// completeReview resumes a payment held for review: it is charged if the analyst approved it and fails otherwise.
+func (uc *PaymentUseCase) completeReview(assessment *fraud.Assessment) error {
+	transaction, err := uc.paymentRepo.Find(assessment.TransactionID)
+	if err != nil {
+		log.Printf("error finding transaction %s: %v", assessment.TransactionID, err)
+		return err
+	}
+
+	if transaction.Status != payment.TransactionStatusInReview {
+		return nil
+	}
+
+	if !assessment.IsApproved() {
+		_, err = uc.reject(transaction, fraud.ErrPaymentBlocked)
+	} else {
+		transaction.Status = payment.TransactionStatusPending
+		_, err = uc.authorize(transaction)
+	}
+
+	// a payment that failed is a valid outcome of the review
+	if errors.Is(err, fraud.ErrPaymentBlocked) || errors.Is(err, payment.ErrPaymentDeclined) {
+		return nil
+	}
+
+	return err
+}
+
+// signals describes a payment to the fraud rules. Payments are compared with the history of their source.
+func (uc *PaymentUseCase) signals(transaction *payment.Transaction, origin *fraud.Origin) fraud.Signals {
+	signals := fraud.Signals{
+		Subject:     transaction.Source,
+		Amount:      transaction.Amount,
+		Beneficiary: ledger.MerchantAccountID(transaction.Url),
+	}
+	if origin != nil {
+		signals.Origin = *origin
+	}
+
+	return signals
+}
+
+// complete tells the completion handlers about a payment that succeeded or failed.
+func (uc *PaymentUseCase) complete(transaction *payment.Transaction) {
+	for _, handler := range uc.completions {
+		handler(transaction)
+	}
+}
+
+// releaseLimits gives back the limits counted for a transaction that failed, since failed payments do not count.
+// They are given back to the day and month they were counted in.
+func (uc *PaymentUseCase) releaseLimits(transaction *payment.Transaction) {
+	if uc.limits != nil && transaction.LimitReservedAt != nil {
+		uc.limits.Release(&limit.Reservation{Subject: transaction.Source, Amount: transaction.Amount, At: *transaction.LimitReservedAt})
+	}
+}
+
+// applyRefundResult moves a pending refund to the state reported by the provider. A successful refund
+// is reversed in the ledger and added to the transaction's refunded amount; a failed refund gives its
+// reservation back.
+func (uc *PaymentUseCase) applyRefundResult(refund *payment.Refund, result *payment.ProviderResult) (*payment.Refund, error) {
+	uc.refundMu.Lock()
+	defer uc.refundMu.Unlock()
+
+	// results for refunds that are already complete are ignored
+	if stored, err := uc.paymentRepo.FindRefund(refund.ID); err == nil {
+		refund = stored
+	}
+	if refund.Status != payment.RefundStatusPending {
+		return refund, nil
+	}
+
+	if result.ProviderReference != "" {
+		refund.ProviderReference = result.ProviderReference
+	}
+
+	var eventType event.Type
+	switch result.Status {
+	case payment.ProviderStatusRefunded, payment.ProviderStatusDisbursed:
+		refund.Status, eventType = payment.RefundStatusSucceeded, event.TypeRefundSucceeded
+	case payment.ProviderStatusDeclined:
+		refund.Status, refund.FailureReason, eventType = payment.RefundStatusFailed, result.DeclineReason, event.TypeRefundFailed
+	default:
+		return refund, uc.paymentRepo.SaveRefund(refund)
+	}
+
+	transaction, err := uc.paymentRepo.Find(refund.TransactionID)
+	if err != nil {
+		log.Printf("error finding transaction %s: %v", refund.TransactionID, err)
+		return nil, err
+	}
+
+	if refund.Status == payment.RefundStatusSucceeded {
+		// reverse the payment's posting for the refunded amount. The refund stays pending until it is posted,
+		// so that the provider's next result posts it again.
+		if err = uc.post(ledger.NewTransfer(refund.ID, "refund of "+transaction.ID, ledger.MerchantAccountID(transaction.Url), ledger.PaymentsClearingAccountID, refund.Amount)); err != nil {
+			refund.Status = payment.RefundStatusPending
+			_ = uc.paymentRepo.SaveRefund(refund)
+			return nil, err
+		}
+	}
+
+	if err = uc.paymentRepo.SaveRefund(refund); err != nil {
+		log.Printf("error saving refund %s: %v", refund.ID, err)
+		return nil, err
+	}
+
+	if refund.Status == payment.RefundStatusFailed {
+		uc.releaseRefund(refund)
+		uc.notifyRefund(transaction, refund, eventType)
+		return refund, payment.ErrRefundDeclined
+	}
+
+	transaction.RefundedAmount += refund.Amount
+	if transaction.RefundedAmount >= transaction.Amount {
+		transaction.Status = payment.TransactionStatusRefunded
+	} else {
+		transaction.Status = payment.TransactionStatusPartiallyRefunded
+	}
+	if err = uc.paymentRepo.Save(transaction); err != nil {
+		log.Printf("error saving transaction %s: %v", transaction.ID, err)
+		return nil, err
+	}
+
+	uc.notifyRefund(transaction, refund, eventType)
+	return refund, nil
+}
+
+// releaseRefund gives back the amount reserved for a refund that will not be paid.
+func (uc *PaymentUseCase) releaseRefund(refund *payment.Refund) {
+	if err := uc.paymentRepo.ReleaseRefund(refund.TransactionID, refund.Amount); err != nil {
+		log.Printf("error releasing refund %s: %v", refund.ID, err)
+	}
+}
+
+// post records ledger entries. Entries that were already posted are not an error, so that a result applied
+// again after a failed posting records them once.
+func (uc *PaymentUseCase) post(entries []*ledger.Entry) error {
+	if err := uc.ledgerRepo.Post(entries...); err != nil && !errors.Is(err, ledger.ErrEntriesAlreadyPosted) {
+		log.Printf("error posting ledger entries for %s: %v", entries[0].Reference, err)
+		return err
+	}
+
+	return nil
+}
+
+// notifyRefund queues a refund event for the transaction's merchant.
+func (uc *PaymentUseCase) notifyRefund(transaction *payment.Transaction, refund *payment.Refund, eventType event.Type) {
+	envelope, err := event.New(eventType, &event.RefundData{
+		RefundID:      refund.ID,
+		TransactionID: transaction.ID,
+		Amount:        refund.Amount,
+		Status:        string(refund.Status),
+		Reason:        refund.Reason,
+	})
+	if err != nil {
+		log.Printf("error creating %s event: %v", eventType, err)
+		return
+	}
+
+	if err := uc.paymentRepo.Notify(transaction.Url, envelope); err != nil {
+		log.Printf("error queueing %s event for refund %s: %v", eventType, refund.ID, err)
+	}
+}
+
+// notify queues a payment event for the transaction's merchant. Failures are logged, not returned,
+// because the transaction itself has already been recorded.
+func (uc *PaymentUseCase) notify(transaction *payment.Transaction, eventType event.Type) {
+	envelope, err := event.New(eventType, &event.PaymentData{
+		TransactionID: transaction.ID,
+		Amount:        transaction.Amount,
+		Status:        string(transaction.Status),
+	})
+	if err != nil {
+		log.Printf("error creating %s event: %v", eventType, err)
+		return
+	}
+
+	if err := uc.paymentRepo.Notify(transaction.Url, envelope); err != nil {
+		log.Printf("error queueing %s event for transaction %s: %v", eventType, transaction.ID, err)
+	}
 }
 
 // validateAmount validates an amount.
@@ -56,6 +595,26 @@ func validateAmount(amount float32) error {
 	return nil
 }
 
+// toCents converts an amount to a whole number of cents, so that amounts compare without float errors.
+func toCents(amount float32) int64 {
+	return int64(math.Round(float64(amount) * 100))
+}
+
+// checkURL validates a merchant URL and makes sure it does not point into our own network.
+func (uc *PaymentUseCase) checkURL(url string) error {
+	if err := validateURL(url); err != nil {
+		log.Printf("error validating URL: %v", err)
+		return err
+	}
+
+	if err := uc.urlGuard.CheckURL(context.Background(), url); err != nil {
+		log.Printf("error checking URL: %v", err)
+		return err
+	}
+
+	return nil
+}
+
 // validateURL validates a URL.
 func validateURL(url string) error {
 	urlPattern := `^(http|https)://[^\s/$.?#].[^\s]*$`
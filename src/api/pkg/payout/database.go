@@ -0,0 +1,45 @@
+package payout
+
+import "errors"
+
+var (
+	// ErrBatchNotFound is the error returned when a payout batch does not exist
+	ErrBatchNotFound = errors.New("payout batch not found")
+
+	// ErrItemNotFound is the error returned when a payout item does not exist
+	ErrItemNotFound = errors.New("payout item not found")
+
+	// ErrFailedToSaveBatch is the error returned when a payout batch or its items cannot be stored
+	ErrFailedToSaveBatch = errors.New("failed to save payout batch. Please try again")
+
+	// ErrItemAlreadyClaimed is the error returned when an item has already been paid, or is being paid
+	ErrItemAlreadyClaimed = errors.New("payout item already claimed")
+)
+
+// Database is the interface that wraps the basic payout database operations.
+type Database interface {
+	// SaveBatch creates or replaces a batch. Approved and processing batches are queued for the payout worker.
+	SaveBatch(batch *Batch) error
+
+	// GetBatch gets a batch by ID
+	GetBatch(id string) (*Batch, error)
+
+	// GetQueuedBatches gets the IDs of the approved and processing batches, oldest approval first
+	GetQueuedBatches(limit int) ([]string, error)
+
+	// CreateItems stores the items of a new batch
+	CreateItems(items []*Item) error
+
+	// SaveItem replaces an item
+	SaveItem(item *Item) error
+
+	// GetItem gets an item by ID
+	GetItem(id string) (*Item, error)
+
+	// GetBatchItems gets the items of a batch, in upload order
+	GetBatchItems(batchID string) ([]*Item, error)
+
+	// ClaimItem marks an item as taken, failing with ErrItemAlreadyClaimed if it already was. Claims are
+	// permanent so that an item is never paid twice, even across restarts.
+	ClaimItem(id string) error
+}
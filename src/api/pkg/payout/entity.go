@@ -0,0 +1,149 @@
+package payout
+
+import (
+	"github.com/google/uuid"
+	"github.com/quabynah-bilson/quantia/pkg/beneficiary"
+	"strings"
+	"time"
+)
+
+// itemReferencePrefix is the prefix of payout item IDs, used to route provider results to payout items
+const itemReferencePrefix = "pyi_"
+
+// BatchStatus is the type that represents the status of a payout batch
+type BatchStatus string
+
+const (
+	// BatchStatusAwaitingApproval is the status of a validated batch waiting for a second person to approve it
+	BatchStatusAwaitingApproval BatchStatus = "awaiting_approval"
+
+	// BatchStatusRejected is the status of a batch turned down by its approver. Nothing was paid.
+	BatchStatusRejected BatchStatus = "rejected"
+
+	// BatchStatusApproved is the status of an approved batch waiting for the payout worker
+	BatchStatusApproved BatchStatus = "approved"
+
+	// BatchStatusProcessing is the status of a batch whose items are being paid
+	BatchStatusProcessing BatchStatus = "processing"
+
+	// BatchStatusCompleted is the status of a batch whose items have all succeeded or failed
+	BatchStatusCompleted BatchStatus = "completed"
+)
+
+// ItemStatus is the type that represents the status of a payout item
+type ItemStatus string
+
+const (
+	// ItemStatusPending is the status of an item that has not been paid yet
+	ItemStatusPending ItemStatus = "pending"
+
+	// ItemStatusProcessing is the status of an item sent to the payout provider and waiting for its answer
+	ItemStatusProcessing ItemStatus = "processing"
+
+	// ItemStatusSucceeded is the status of an item that reached the payee
+	ItemStatusSucceeded ItemStatus = "succeeded"
+
+	// ItemStatusFailed is the status of an item that could not be paid. The account is refunded.
+	ItemStatusFailed ItemStatus = "failed"
+)
+
+// Instruction is a payment to make, as uploaded in a batch
+type Instruction struct {
+	DestinationType beneficiary.DestinationType `json:"destination_type"`
+
+	// Destination is the account ID, bank account number or wallet number of the payee
+	Destination string  `json:"destination"`
+	BankCode    string  `json:"bank_code,omitempty"`
+	Amount      float32 `json:"amount"`
+
+	// Reference is the payer's own reference for the payment, e.g. an invoice number. It is unique within a batch.
+	Reference string `json:"reference,omitempty"`
+	Note      string `json:"note,omitempty"`
+}
+
+// Batch is the entity that represents a set of payouts uploaded, approved and paid together
+type Batch struct {
+	ID string `json:"id"`
+
+	// AccountID is the account the payouts are paid from
+	AccountID string      `json:"account_id"`
+	Items     int         `json:"items"`
+	Total     float32     `json:"total"`
+	Status    BatchStatus `json:"status"`
+
+	// CreatedBy and ApprovedBy are the people who uploaded and approved (or rejected) the batch
+	CreatedBy  string `json:"created_by"`
+	ApprovedBy string `json:"approved_by,omitempty"`
+
+	// Reason explains a rejection
+	Reason string `json:"reason,omitempty"`
+
+	// Succeeded and Failed count the items paid and refused once the batch is completed
+	Succeeded   int        `json:"succeeded"`
+	Failed      int        `json:"failed"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ApprovedAt  *time.Time `json:"approved_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// Item is the entity that represents one payout of a batch
+type Item struct {
+	ID      string `json:"id"`
+	BatchID string `json:"batch_id"`
+
+	// Row is the position of the item in the upload, starting at 1
+	Row int `json:"row"`
+	Instruction
+
+	Status            ItemStatus `json:"status"`
+	HoldID            string     `json:"hold_id,omitempty"`
+	ProviderReference string     `json:"provider_reference,omitempty"`
+	FailureReason     string     `json:"failure_reason,omitempty"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// NewBatch creates a batch awaiting approval, with a pending item for each instruction
+func NewBatch(accountID, createdBy string, instructions []Instruction) (*Batch, []*Item) {
+	now := time.Now().UTC()
+	batch := &Batch{
+		ID:        "pob_" + uuid.NewString(),
+		AccountID: accountID,
+		Items:     len(instructions),
+		Status:    BatchStatusAwaitingApproval,
+		CreatedBy: createdBy,
+		CreatedAt: now,
+	}
+
+	// the total is worked out in minor units so that it matches the sum of the items
+	var total int64
+	items := make([]*Item, 0, len(instructions))
+	for i, instruction := range instructions {
+		items = append(items, &Item{
+			ID:          itemReferencePrefix + uuid.NewString(),
+			BatchID:     batch.ID,
+			Row:         i + 1,
+			Instruction: instruction,
+			Status:      ItemStatusPending,
+			UpdatedAt:   now,
+		})
+		total += toMinorUnits(instruction.Amount)
+	}
+	batch.Total = fromMinorUnits(total)
+
+	return batch, items
+}
+
+// IsInternal reports whether the item stays within our ledger
+func (i *Item) IsInternal() bool {
+	return i.DestinationType == beneficiary.DestinationInternalAccount
+}
+
+// IsFinal reports whether the item has succeeded or failed
+func (i *Item) IsFinal() bool {
+	return i.Status == ItemStatusSucceeded || i.Status == ItemStatusFailed
+}
+
+// IsItemReference reports whether a provider reference identifies a payout item
+func IsItemReference(reference string) bool {
+	return strings.HasPrefix(reference, itemReferencePrefix)
+}
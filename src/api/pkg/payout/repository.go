@@ -0,0 +1,28 @@
+package payout
+
+// Repository is the payout repository interface
+type Repository interface {
+	// Save creates or replaces a batch.
+	Save(batch *Batch) error
+
+	// Find gets a batch by ID.
+	Find(id string) (*Batch, error)
+
+	// Queued gets the IDs of the batches waiting for the payout worker.
+	Queued(limit int) ([]string, error)
+
+	// CreateItems stores the items of a new batch.
+	CreateItems(items []*Item) error
+
+	// SaveItem replaces an item.
+	SaveItem(item *Item) error
+
+	// FindItem gets an item by ID.
+	FindItem(id string) (*Item, error)
+
+	// Items gets the items of a batch, in upload order.
+	Items(batchID string) ([]*Item, error)
+
+	// Claim marks an item as taken before it is paid.
+	Claim(id string) error
+}
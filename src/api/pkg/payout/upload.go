@@ -0,0 +1,192 @@
+package payout
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/quabynah-bilson/quantia/pkg/beneficiary"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidFile is the error returned when an uploaded CSV file cannot be read or misses required columns
+var ErrInvalidFile = errors.New("invalid payout file. Please check the CSV header and columns")
+
+// columns are the columns of payout CSV files, in the order of the results file. The destination type,
+// destination and amount are required.
+var columns = []string{"destination_type", "destination", "bank_code", "amount", "reference", "note"}
+
+// RowError is a problem with one field of an uploaded row. Rows are numbered from 1, not counting the CSV header.
+type RowError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ParseCSV reads payout instructions from a CSV file with a header row. Columns are matched by name, in any
+// order; unknown columns are ignored. Amounts that are not numbers are reported as row errors and read as zero.
+func ParseCSV(r io.Reader) ([]Instruction, []RowError, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, ErrInvalidFile
+	}
+
+	index := make(map[string]int)
+	for i, name := range header {
+		index[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"destination_type", "destination", "amount"} {
+		if _, ok := index[required]; !ok {
+			return nil, nil, ErrInvalidFile
+		}
+	}
+
+	var instructions []Instruction
+	var report []RowError
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, ErrInvalidFile
+		}
+
+		field := func(name string) string {
+			if i, ok := index[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		instruction := Instruction{
+			DestinationType: beneficiary.DestinationType(field("destination_type")),
+			Destination:     field("destination"),
+			BankCode:        field("bank_code"),
+			Reference:       field("reference"),
+			Note:            field("note"),
+		}
+		if amount, err := strconv.ParseFloat(field("amount"), 32); err != nil {
+			report = append(report, RowError{Row: row, Field: "amount", Message: "amount is not a number"})
+		} else {
+			instruction.Amount = float32(amount)
+		}
+		instructions = append(instructions, instruction)
+	}
+
+	return instructions, report, nil
+}
+
+// Validate checks the instructions of a batch paid from the account, normalizing their fields, and reports
+// every problem found.
+func Validate(accountID string, instructions []Instruction) []RowError {
+	var report []RowError
+	references := make(map[string]int)
+	for i := range instructions {
+		row, instruction := i+1, &instructions[i]
+		instruction.Destination = strings.TrimSpace(instruction.Destination)
+		instruction.BankCode = strings.TrimSpace(instruction.BankCode)
+		instruction.Reference = strings.TrimSpace(instruction.Reference)
+		instruction.Note = strings.TrimSpace(instruction.Note)
+
+		if !instruction.DestinationType.IsValid() {
+			report = append(report, RowError{Row: row, Field: "destination_type", Message: fmt.Sprintf("unknown destination type %q", instruction.DestinationType)})
+		}
+
+		switch {
+		case instruction.Destination == "":
+			report = append(report, RowError{Row: row, Field: "destination", Message: "destination is required"})
+		case instruction.DestinationType == beneficiary.DestinationInternalAccount && instruction.Destination == accountID:
+			report = append(report, RowError{Row: row, Field: "destination", Message: "destination is the paying account"})
+		}
+
+		if instruction.DestinationType == beneficiary.DestinationBankAccount && instruction.BankCode == "" {
+			report = append(report, RowError{Row: row, Field: "bank_code", Message: "bank code is required for bank accounts"})
+		}
+		if instruction.DestinationType != beneficiary.DestinationBankAccount {
+			instruction.BankCode = ""
+		}
+
+		switch {
+		case instruction.Amount <= 0:
+			report = append(report, RowError{Row: row, Field: "amount", Message: "amount must be greater than zero"})
+		case fromMinorUnits(toMinorUnits(instruction.Amount)) != instruction.Amount:
+			report = append(report, RowError{Row: row, Field: "amount", Message: "amount has more than two decimal places"})
+		}
+
+		if instruction.Reference != "" {
+			if first, ok := references[instruction.Reference]; ok {
+				report = append(report, RowError{Row: row, Field: "reference", Message: fmt.Sprintf("reference already used on row %d", first)})
+			} else {
+				references[instruction.Reference] = row
+			}
+		}
+	}
+
+	return report
+}
+
+// MergeReports combines row error reports, ordered by row. Only the first error reported for a field of a
+// row is kept, e.g. an amount that could not be parsed is not also reported as missing.
+func MergeReports(reports ...[]RowError) []RowError {
+	var merged []RowError
+	seen := make(map[string]bool)
+	for _, report := range reports {
+		for _, rowErr := range report {
+			key := strconv.Itoa(rowErr.Row) + ":" + rowErr.Field
+			if !seen[key] {
+				seen[key] = true
+				merged = append(merged, rowErr)
+			}
+		}
+	}
+	sort.SliceStable(merged, func(i, j int) bool { return merged[i].Row < merged[j].Row })
+
+	return merged
+}
+
+// WriteResults writes the items of a batch as a CSV file with their status, in upload order
+func WriteResults(w io.Writer, items []*Item) error {
+	writer := csv.NewWriter(w)
+	header := append([]string{"row"}, columns...)
+	header = append(header, "status", "provider_reference", "failure_reason")
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		if err := writer.Write([]string{
+			strconv.Itoa(item.Row),
+			string(item.DestinationType),
+			item.Destination,
+			item.BankCode,
+			strconv.FormatFloat(float64(item.Amount), 'f', 2, 32),
+			item.Reference,
+			item.Note,
+			string(item.Status),
+			item.ProviderReference,
+			item.FailureReason,
+		}); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// toMinorUnits converts an amount to cents
+func toMinorUnits(amount float32) int64 {
+	return int64(math.Round(float64(amount) * 100))
+}
+
+// fromMinorUnits converts cents to an amount
+func fromMinorUnits(amount int64) float32 {
+	return float32(amount) / 100
+}
@@ -0,0 +1,469 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/quabynah-bilson/quantia/pkg/beneficiary"
+	"github.com/quabynah-bilson/quantia/pkg/ledger"
+	"github.com/quabynah-bilson/quantia/pkg/payment"
+	"github.com/quabynah-bilson/quantia/pkg/payout"
+	"github.com/quabynah-bilson/quantia/pkg/screening"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrInvalidBatch is the error returned when a payout batch is empty, too large or has invalid rows.
+	ErrInvalidBatch = errors.New("invalid payout batch. Please check the account, the creator and the rows")
+
+	// ErrInvalidApproval is the error returned when a batch is approved or rejected anonymously, or approved by its creator.
+	ErrInvalidApproval = errors.New("invalid approval. A batch must be approved by someone other than its creator")
+
+	// ErrInvalidBatchState is the error returned when a payout batch cannot be changed in its current state.
+	ErrInvalidBatchState = errors.New("the payout batch cannot be changed in its current state")
+)
+
+const (
+	// defaultMaxBatchItems bounds the size of a batch when the configuration does not.
+	defaultMaxBatchItems = 1000
+
+	// queuedBatchSize is the number of queued batches processed per tick.
+	queuedBatchSize = 10
+)
+
+// PayoutConfig is the configuration of the payout use case
+type PayoutConfig struct {
+	// MaxItems is the largest number of rows a batch may have
+	MaxItems int
+}
+
+// PayoutUseCase is the bulk payout use case. Batches of payouts are validated when they are uploaded,
+// approved by a second person and then paid item by item by the payout worker.
+type PayoutUseCase struct {
+	payoutRepo payout.Repository
+	ledgerRepo ledger.Repository
+
+	// payouts sends money to external destinations. It is nil when the provider cannot disburse.
+	payouts payment.PayoutProvider
+	config  PayoutConfig
+
+	// screening keeps sanctioned or unreviewed accounts from paying out. It is nil when screening is off.
+	screening *ScreeningUseCase
+
+	// mu serializes the changes made to batches and items by the API, the worker and the provider results
+	mu sync.Mutex
+}
+
+// NewPayoutUseCase creates a new payout use case.
+func NewPayoutUseCase(payoutRepo payout.Repository, ledgerRepo ledger.Repository, payouts payment.PayoutProvider, config PayoutConfig) *PayoutUseCase {
+	if config.MaxItems <= 0 {
+		config.MaxItems = defaultMaxBatchItems
+	}
+
+	return &PayoutUseCase{
+		payoutRepo: payoutRepo,
+		ledgerRepo: ledgerRepo,
+		payouts:    payouts,
+		config:     config,
+	}
+}
+
+// SetScreening keeps the accounts with sanctions hits from approving batches until they are cleared.
+func (uc *PayoutUseCase) SetScreening(screening *ScreeningUseCase) {
+	uc.screening = screening
+}
+
+// ValidateBatch checks the rows of a batch paid from the account, normalizing them, and reports every
+// problem found. A batch can only be created once the report is empty.
+func (uc *PayoutUseCase) ValidateBatch(accountID string, instructions []payout.Instruction) []payout.RowError {
+	report := payout.Validate(accountID, instructions)
+	if len(instructions) > uc.config.MaxItems {
+		report = append(report, payout.RowError{
+			Row:     uc.config.MaxItems + 1,
+			Field:   "row",
+			Message: fmt.Sprintf("a batch may have at most %d rows", uc.config.MaxItems),
+		})
+	}
+
+	if uc.payouts == nil {
+		for i, instruction := range instructions {
+			if instruction.DestinationType.IsValid() && instruction.DestinationType != beneficiary.DestinationInternalAccount {
+				report = append(report, payout.RowError{Row: i + 1, Field: "destination_type", Message: ErrPayoutsNotSupported.Error()})
+			}
+		}
+	}
+
+	return payout.MergeReports(report)
+}
+
+// CreateBatch validates and stores a batch of payouts from the account, which then waits for approval.
+// When any row is invalid nothing is stored and the report lists the problems of every row.
+func (uc *PayoutUseCase) CreateBatch(accountID, createdBy string, instructions []payout.Instruction) (*payout.Batch, []payout.RowError, error) {
+	createdBy = strings.TrimSpace(createdBy)
+	if accountID == "" || createdBy == "" || len(instructions) == 0 {
+		return nil, nil, ErrInvalidBatch
+	}
+
+	if report := uc.ValidateBatch(accountID, instructions); len(report) > 0 {
+		return nil, report, ErrInvalidBatch
+	}
+
+	batch, items := payout.NewBatch(accountID, createdBy, instructions)
+	if err := uc.payoutRepo.CreateItems(items); err != nil {
+		log.Printf("error saving items of payout batch: %v", err)
+		return nil, nil, err
+	}
+
+	if err := uc.payoutRepo.Save(batch); err != nil {
+		log.Printf("error saving payout batch: %v", err)
+		return nil, nil, err
+	}
+
+	return batch, nil, nil
+}
+
+// GetBatch gets a payout batch by ID.
+func (uc *PayoutUseCase) GetBatch(id string) (*payout.Batch, error) {
+	return uc.payoutRepo.Find(id)
+}
+
+// GetItems gets the items of a batch, in upload order.
+func (uc *PayoutUseCase) GetItems(id string) ([]*payout.Item, error) {
+	if _, err := uc.payoutRepo.Find(id); err != nil {
+		return nil, err
+	}
+
+	return uc.payoutRepo.Items(id)
+}
+
+// Approve queues a batch for the payout worker. The approver must not be the batch's creator, and the
+// account must have the funds to pay the whole batch.
+func (uc *PayoutUseCase) Approve(id, approvedBy string) (*payout.Batch, error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	batch, err := uc.payoutRepo.Find(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if batch.Status != payout.BatchStatusAwaitingApproval {
+		return nil, ErrInvalidBatchState
+	}
+
+	approvedBy = strings.TrimSpace(approvedBy)
+	if approvedBy == "" || strings.EqualFold(approvedBy, batch.CreatedBy) {
+		return nil, ErrInvalidApproval
+	}
+
+	if uc.screening != nil {
+		if err = uc.screening.Authorize(screening.PartyAccount, batch.AccountID); err != nil {
+			return nil, err
+		}
+	}
+
+	// each item is debited when it is paid; the check gives early warning of a batch that cannot be paid in full
+	balance, err := uc.ledgerRepo.Balance(batch.AccountID)
+	if err != nil {
+		log.Printf("error getting balance of account %s: %v", batch.AccountID, err)
+		return nil, err
+	}
+	if balance.Spendable() < batch.Total {
+		return nil, ledger.ErrInsufficientFunds
+	}
+
+	now := time.Now().UTC()
+	batch.Status, batch.ApprovedBy, batch.ApprovedAt = payout.BatchStatusApproved, approvedBy, &now
+	if err = uc.payoutRepo.Save(batch); err != nil {
+		log.Printf("error saving payout batch %s: %v", batch.ID, err)
+		return nil, err
+	}
+
+	return batch, nil
+}
+
+// Reject turns down a batch awaiting approval. Nothing is paid.
+func (uc *PayoutUseCase) Reject(id, rejectedBy, reason string) (*payout.Batch, error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	batch, err := uc.payoutRepo.Find(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if batch.Status != payout.BatchStatusAwaitingApproval {
+		return nil, ErrInvalidBatchState
+	}
+
+	rejectedBy = strings.TrimSpace(rejectedBy)
+	if rejectedBy == "" {
+		return nil, ErrInvalidApproval
+	}
+
+	batch.Status, batch.ApprovedBy, batch.Reason = payout.BatchStatusRejected, rejectedBy, strings.TrimSpace(reason)
+	if err = uc.payoutRepo.Save(batch); err != nil {
+		log.Printf("error saving payout batch %s: %v", batch.ID, err)
+		return nil, err
+	}
+
+	return batch, nil
+}
+
+// Run pays the items of approved batches every interval until the context is cancelled.
+func (uc *PayoutUseCase) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		uc.RunQueued()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunQueued pays the pending items of the queued batches and returns how many batches were processed.
+// Each item is claimed before it is paid, so it is paid at most once even if the worker restarts or
+// several workers share the store.
+func (uc *PayoutUseCase) RunQueued() int {
+	ids, err := uc.payoutRepo.Queued(queuedBatchSize)
+	if err != nil {
+		log.Printf("error getting queued payout batches: %v", err)
+		return 0
+	}
+
+	for _, id := range ids {
+		uc.process(id)
+	}
+
+	return len(ids)
+}
+
+// HandleProviderResult completes a payout item with an asynchronous result from the payout provider.
+func (uc *PayoutUseCase) HandleProviderResult(result *payment.ProviderResult) error {
+	item, err := uc.applyResult(result.Reference, result)
+	if err != nil {
+		return err
+	}
+
+	uc.complete(item.BatchID)
+	return nil
+}
+
+// process starts an approved batch and pays its pending items.
+func (uc *PayoutUseCase) process(id string) {
+	batch, err := uc.start(id)
+	if err != nil {
+		return
+	}
+
+	items, err := uc.payoutRepo.Items(batch.ID)
+	if err != nil {
+		log.Printf("error getting items of payout batch %s: %v", batch.ID, err)
+		return
+	}
+
+	for _, item := range items {
+		if item.Status == payout.ItemStatusPending {
+			uc.pay(batch, item)
+		}
+	}
+
+	uc.complete(batch.ID)
+}
+
+// start moves an approved batch to processing. Batches that are already processing are returned as they are.
+func (uc *PayoutUseCase) start(id string) (*payout.Batch, error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	batch, err := uc.payoutRepo.Find(id)
+	if err != nil {
+		log.Printf("error finding payout batch %s: %v", id, err)
+		return nil, err
+	}
+
+	switch batch.Status {
+	case payout.BatchStatusProcessing:
+		return batch, nil
+	case payout.BatchStatusApproved:
+	default:
+		return nil, ErrInvalidBatchState
+	}
+
+	batch.Status = payout.BatchStatusProcessing
+	if err = uc.payoutRepo.Save(batch); err != nil {
+		log.Printf("error saving payout batch %s: %v", batch.ID, err)
+		return nil, err
+	}
+
+	return batch, nil
+}
+
+// pay debits the batch's account for an item and sends it to the payee. Items to internal accounts are paid
+// at once; the others wait for the payout provider and are refunded if it declines them.
+func (uc *PayoutUseCase) pay(batch *payout.Batch, item *payout.Item) {
+	if err := uc.payoutRepo.Claim(item.ID); err != nil {
+		// another worker has the item, or a previous run stopped before recording it
+		log.Printf("payout item %s already claimed: %v", item.ID, err)
+		return
+	}
+
+	creditAccountID := item.Destination
+	if !item.IsInternal() {
+		creditAccountID = ledger.PayoutsClearingAccountID
+	}
+
+	var err error
+	if !item.IsInternal() && uc.payouts == nil {
+		err = ErrPayoutsNotSupported
+	} else {
+		item.HoldID, err = uc.debit(batch.AccountID, creditAccountID, item)
+	}
+
+	// save the item before calling the provider, so that an early result finds it
+	item.UpdatedAt = time.Now().UTC()
+	switch {
+	case err != nil:
+		item.Status, item.FailureReason = payout.ItemStatusFailed, err.Error()
+	case item.IsInternal():
+		item.Status = payout.ItemStatusSucceeded
+	default:
+		item.Status = payout.ItemStatusProcessing
+	}
+	if err = uc.payoutRepo.SaveItem(item); err != nil {
+		log.Printf("error saving payout item %s: %v", item.ID, err)
+	}
+
+	if item.Status != payout.ItemStatusProcessing {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), providerTimeout)
+	defer cancel()
+
+	result, err := uc.payouts.Disburse(ctx, &payment.DisbursementRequest{
+		Reference:   item.ID,
+		Amount:      item.Amount,
+		Destination: disbursementDestination(item.DestinationType, item.Destination, item.BankCode),
+		Note:        item.Note,
+	})
+	if result == nil {
+		// the outcome is unknown, so the item stays processing until the provider reports back
+		log.Printf("error disbursing payout item %s: %v", item.ID, err)
+		return
+	}
+
+	if _, err = uc.applyResult(item.ID, result); err != nil {
+		log.Printf("error completing payout item %s: %v", item.ID, err)
+	}
+}
+
+// debit moves an item's amount from the account to the credit account and returns the hold that did it.
+// The hold checks the available balance and reserves the funds atomically before they are captured.
+func (uc *PayoutUseCase) debit(accountID, creditAccountID string, item *payout.Item) (string, error) {
+	hold := ledger.NewHold(accountID, creditAccountID, item.Amount, transferHoldExpiry)
+	if err := uc.ledgerRepo.Hold(hold); err != nil {
+		log.Printf("error placing hold for payout item %s: %v", item.ID, err)
+		return "", err
+	}
+
+	if _, err := uc.ledgerRepo.Capture(hold.ID, item.Amount); err != nil {
+		log.Printf("error capturing hold %s for payout item %s: %v", hold.ID, item.ID, err)
+		if _, releaseErr := uc.ledgerRepo.Release(hold.ID, ledger.HoldStatusVoided); releaseErr != nil {
+			log.Printf("error releasing hold %s: %v", hold.ID, releaseErr)
+		}
+		return "", err
+	}
+
+	return hold.ID, nil
+}
+
+// applyResult completes an item waiting for the provider with its result. Declined items are refunded to
+// the batch's account. Results for items that are already complete are ignored.
+func (uc *PayoutUseCase) applyResult(id string, result *payment.ProviderResult) (*payout.Item, error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	item, err := uc.payoutRepo.FindItem(id)
+	if err != nil {
+		log.Printf("error finding payout item %s: %v", id, err)
+		return nil, err
+	}
+
+	if item.Status != payout.ItemStatusProcessing {
+		return item, nil
+	}
+
+	switch result.Status {
+	case payment.ProviderStatusDisbursed:
+		item.Status = payout.ItemStatusSucceeded
+	case payment.ProviderStatusDeclined:
+		batch, err := uc.payoutRepo.Find(item.BatchID)
+		if err != nil {
+			log.Printf("error finding payout batch %s: %v", item.BatchID, err)
+			return nil, err
+		}
+
+		entries := ledger.NewTransfer(item.ID, "payout reversal", ledger.PayoutsClearingAccountID, batch.AccountID, item.Amount)
+		if err = uc.ledgerRepo.Post(entries...); err != nil && !errors.Is(err, ledger.ErrEntriesAlreadyPosted) {
+			log.Printf("error reversing payout item %s: %v", item.ID, err)
+			return nil, err
+		}
+		item.Status, item.FailureReason = payout.ItemStatusFailed, result.DeclineReason
+	default:
+		// still pending at the provider
+		return item, nil
+	}
+
+	item.ProviderReference, item.UpdatedAt = result.ProviderReference, time.Now().UTC()
+	if err = uc.payoutRepo.SaveItem(item); err != nil {
+		log.Printf("error saving payout item %s: %v", item.ID, err)
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// complete closes a processing batch once all of its items have succeeded or failed.
+func (uc *PayoutUseCase) complete(id string) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	batch, err := uc.payoutRepo.Find(id)
+	if err != nil || batch.Status != payout.BatchStatusProcessing {
+		return
+	}
+
+	items, err := uc.payoutRepo.Items(id)
+	if err != nil {
+		log.Printf("error getting items of payout batch %s: %v", id, err)
+		return
+	}
+
+	succeeded, failed := 0, 0
+	for _, item := range items {
+		if !item.IsFinal() {
+			return
+		}
+
+		if item.Status == payout.ItemStatusSucceeded {
+			succeeded++
+		} else {
+			failed++
+		}
+	}
+
+	now := time.Now().UTC()
+	batch.Status, batch.Succeeded, batch.Failed, batch.CompletedAt = payout.BatchStatusCompleted, succeeded, failed, &now
+	if err = uc.payoutRepo.Save(batch); err != nil {
+		log.Printf("error saving payout batch %s: %v", batch.ID, err)
+	}
+}
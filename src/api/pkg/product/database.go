@@ -0,0 +1,62 @@
+package product
+
+import "errors"
+
+var (
+	// ErrProductNotFound is the error returned when a product does not exist
+	ErrProductNotFound = errors.New("product not found")
+
+	// ErrFailedToSaveProduct is the error returned when a product or an assignment cannot be stored
+	ErrFailedToSaveProduct = errors.New("failed to save product. Please try again")
+
+	// ErrAssignmentNotFound is the error returned when an account does not hold a product
+	ErrAssignmentNotFound = errors.New("the account does not hold a product")
+
+	// ErrAccrualAlreadyRecorded is the error returned when an account's interest is accrued twice for a business date
+	ErrAccrualAlreadyRecorded = errors.New("interest already accrued for this business date")
+
+	// ErrFailedToSaveAccrual is the error returned when an accrual or a business date cannot be stored
+	ErrFailedToSaveAccrual = errors.New("failed to save accrual. Please try again")
+
+	// ErrBusinessDayNotFound is the error returned when the end-of-day batch has not run for a business date
+	ErrBusinessDayNotFound = errors.New("business date not run")
+)
+
+// Database is the interface that wraps the basic product database operations.
+type Database interface {
+	// SaveProduct creates or replaces a product
+	SaveProduct(product *Product) error
+
+	// GetProduct gets a product by ID
+	GetProduct(id string) (*Product, error)
+
+	// GetProducts gets every product, oldest first
+	GetProducts() ([]*Product, error)
+
+	// SaveAssignment creates or replaces the product of an account
+	SaveAssignment(assignment *Assignment) error
+
+	// GetAssignment gets the product of an account
+	GetAssignment(accountID string) (*Assignment, error)
+
+	// GetAssignments gets the products of every account, in account order
+	GetAssignments() ([]*Assignment, error)
+
+	// SaveAccrual stores the accrual of an account for a business date. It fails with ErrAccrualAlreadyRecorded
+	// when the account already accrued interest on that date.
+	SaveAccrual(accrual *Accrual) error
+
+	// GetAccruals gets the accruals of an account between two business dates, both included, oldest first
+	GetAccruals(accountID, from, to string) ([]*Accrual, error)
+
+	// SaveBusinessDay creates or replaces the outcome of the end-of-day batch of a business date, and records
+	// it as the latest completed date when it is completed
+	SaveBusinessDay(day *BusinessDay) error
+
+	// GetBusinessDay gets the outcome of the end-of-day batch of a business date
+	GetBusinessDay(date string) (*BusinessDay, error)
+
+	// GetLastCompletedDay gets the latest completed business date. It fails with ErrBusinessDayNotFound when
+	// no business date has completed.
+	GetLastCompletedDay() (string, error)
+}
@@ -0,0 +1,326 @@
+package product
+
+import (
+	"github.com/google/uuid"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DateLayout is the layout of business dates
+const DateLayout = "2006-01-02"
+
+// Type is the type that represents the kind of account a product is for
+type Type string
+
+const (
+	// TypeSavings is a product for savings accounts, which earn interest
+	TypeSavings Type = "savings"
+
+	// TypeCurrent is a product for current accounts, which pay for their maintenance and transfers
+	TypeCurrent Type = "current"
+)
+
+// DayCount is the type that represents the day-count convention that turns an annual rate into a daily one
+type DayCount string
+
+const (
+	// DayCountActual365 accrues 1/365 of the annual interest every day, leap years included
+	DayCountActual365 DayCount = "actual/365"
+
+	// DayCountActual360 accrues 1/360 of the annual interest every day
+	DayCountActual360 DayCount = "actual/360"
+
+	// DayCountActualActual accrues 1/365 of the annual interest every day, or 1/366 in leap years
+	DayCountActualActual DayCount = "actual/actual"
+
+	// DayCount30360 counts every month as 30 days of a 360-day year: the 31st accrues nothing and the last
+	// day of February makes up the days February lacks
+	DayCount30360 DayCount = "30/360"
+)
+
+// IsValid reports whether the day-count convention is known
+func (d DayCount) IsValid() bool {
+	switch d {
+	case DayCountActual365, DayCountActual360, DayCountActualActual, DayCount30360:
+		return true
+	default:
+		return false
+	}
+}
+
+// Fraction returns the fraction of a year accrued on a date
+func (d DayCount) Fraction(date time.Time) float64 {
+	switch d {
+	case DayCountActual360:
+		return 1.0 / 360
+	case DayCountActualActual:
+		return 1.0 / float64(daysInYear(date.Year()))
+	case DayCount30360:
+		if date.Day() == 31 {
+			return 0
+		}
+		if date.Month() == time.February && IsMonthEnd(date) {
+			return float64(31-date.Day()) / 360
+		}
+		return 1.0 / 360
+	default:
+		return 1.0 / 365
+	}
+}
+
+// RateTier is a band of balance earning an annual rate, in percent. A tier runs from its From balance to the
+// From balance of the next tier.
+type RateTier struct {
+	From float32 `json:"from"`
+	Rate float64 `json:"rate"`
+}
+
+// FeeRule is a fee of a fixed amount plus a percentage of the amount charged for, kept between its minimum
+// and its maximum. A zero maximum is uncapped.
+type FeeRule struct {
+	Fixed   float32 `json:"fixed,omitempty"`
+	Percent float64 `json:"percent,omitempty"`
+	Min     float32 `json:"min,omitempty"`
+	Max     float32 `json:"max,omitempty"`
+}
+
+// IsValid reports whether the fee rule's amounts are positive and its maximum is not below its minimum
+func (r FeeRule) IsValid() bool {
+	return r.Fixed >= 0 && r.Percent >= 0 && r.Percent <= 100 && r.Min >= 0 && r.Max >= 0 && (r.Max == 0 || r.Max >= r.Min)
+}
+
+// IsZero reports whether the rule charges nothing
+func (r FeeRule) IsZero() bool {
+	return r.Fixed == 0 && r.Percent == 0 && r.Min == 0
+}
+
+// Fee returns the fee charged for an amount, rounded to cents
+func (r FeeRule) Fee(amount float32) float32 {
+	if r.IsZero() {
+		return 0
+	}
+
+	fee := float64(r.Fixed) + float64(amount)*r.Percent/100
+	fee = math.Max(fee, float64(r.Min))
+	if r.Max > 0 {
+		fee = math.Min(fee, float64(r.Max))
+	}
+
+	return float32(math.Round(fee*100) / 100)
+}
+
+// FeeSchedule is the entity that represents the fees of a product
+type FeeSchedule struct {
+	// Maintenance is charged at the end of every month
+	Maintenance float32 `json:"maintenance,omitempty"`
+
+	// Transfer is charged for every transfer made from the account that did not fail
+	Transfer FeeRule `json:"transfer"`
+}
+
+// OverdraftTerms is the entity that represents the overdraft a product allows: accounts may be approved to go
+// below zero up to MaxLimit, and pay Rate, an annual rate in percent, on what they owe
+type OverdraftTerms struct {
+	MaxLimit float32 `json:"max_limit,omitempty"`
+	Rate     float64 `json:"rate,omitempty"`
+}
+
+// IsAllowed reports whether the terms allow accounts to go below zero
+func (o OverdraftTerms) IsAllowed() bool {
+	return o.MaxLimit > 0
+}
+
+// Product is the entity that represents the configuration of an account product: the interest it earns, the
+// overdraft it allows and the fees it pays. Interest accrues daily on the closing balance and is posted at the
+// end of every month.
+type Product struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type Type   `json:"type"`
+
+	// InterestTiers are the rates earned by each band of a positive balance, lowest band first
+	InterestTiers []RateTier     `json:"interest_tiers"`
+	DayCount      DayCount       `json:"day_count,omitempty"`
+	Overdraft     OverdraftTerms `json:"overdraft"`
+	Fees          FeeSchedule    `json:"fees"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+}
+
+// NewProduct creates a product from its configuration, with its tiers sorted by balance. Products earning or
+// charging interest count days as actual/365 unless another convention is set.
+func NewProduct(config Product) *Product {
+	now := time.Now().UTC()
+	config.ID = "prod_" + uuid.NewString()
+	config.Name = strings.TrimSpace(config.Name)
+	config.CreatedAt, config.UpdatedAt = now, now
+	config.normalize()
+	return &config
+}
+
+// Update replaces the configuration of the product, keeping its ID and creation time
+func (p *Product) Update(config Product) {
+	config.ID, config.CreatedAt, config.UpdatedAt = p.ID, p.CreatedAt, time.Now().UTC()
+	config.Name = strings.TrimSpace(config.Name)
+	config.normalize()
+	*p = config
+}
+
+// IsValid reports whether the product is named, of a known type, and has valid rates, day count and fees.
+// Tiers must start at distinct balances and rates lie between 0 and 100 percent.
+func (p *Product) IsValid() bool {
+	if p.Name == "" || (p.Type != TypeSavings && p.Type != TypeCurrent) {
+		return false
+	}
+
+	for i, tier := range p.InterestTiers {
+		if tier.From < 0 || tier.Rate < 0 || tier.Rate > 100 || (i > 0 && tier.From == p.InterestTiers[i-1].From) {
+			return false
+		}
+	}
+
+	if p.AccruesInterest() && !p.DayCount.IsValid() {
+		return false
+	}
+
+	if p.Overdraft.MaxLimit < 0 || p.Overdraft.Rate < 0 || p.Overdraft.Rate > 100 {
+		return false
+	}
+
+	return p.Fees.Maintenance >= 0 && p.Fees.Transfer.IsValid()
+}
+
+// AnnualInterest returns the interest a balance earns over a year: each band of the balance earns the rate of
+// its tier. Balances at or below zero earn nothing.
+func (p *Product) AnnualInterest(balance float64) float64 {
+	interest := 0.0
+	for i, tier := range p.InterestTiers {
+		from := float64(tier.From)
+		if balance <= from {
+			break
+		}
+
+		upper := balance
+		if i+1 < len(p.InterestTiers) {
+			upper = math.Min(upper, float64(p.InterestTiers[i+1].From))
+		}
+		interest += (upper - from) * tier.Rate / 100
+	}
+
+	return interest
+}
+
+// DailyInterest returns the interest a closing balance accrues on a date
+func (p *Product) DailyInterest(balance float64, date time.Time) float64 {
+	if len(p.InterestTiers) == 0 {
+		return 0
+	}
+
+	return p.AnnualInterest(balance) * p.DayCount.Fraction(date)
+}
+
+// DailyOverdraftInterest returns the overdraft interest a closing balance below zero is charged on a date
+func (p *Product) DailyOverdraftInterest(balance float64, date time.Time) float64 {
+	if balance >= 0 || p.Overdraft.Rate == 0 {
+		return 0
+	}
+
+	return -balance * p.Overdraft.Rate / 100 * p.DayCount.Fraction(date)
+}
+
+// normalize sorts the tiers and sets the default day count
+func (p *Product) normalize() {
+	if p.InterestTiers == nil {
+		p.InterestTiers = []RateTier{}
+	}
+	sort.SliceStable(p.InterestTiers, func(i, j int) bool { return p.InterestTiers[i].From < p.InterestTiers[j].From })
+
+	if p.AccruesInterest() && p.DayCount == "" {
+		p.DayCount = DayCountActual365
+	}
+}
+
+// AccruesInterest reports whether the product earns or charges interest
+func (p *Product) AccruesInterest() bool {
+	return len(p.InterestTiers) > 0 || p.Overdraft.Rate > 0
+}
+
+// Assignment is the entity that represents the product an account holds, since a business date
+type Assignment struct {
+	AccountID string `json:"account_id"`
+	ProductID string `json:"product_id"`
+
+	// Since is the first business date the product applies to
+	Since     string    `json:"since"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewAssignment creates the assignment of a product to an account from a business date
+func NewAssignment(accountID, productID, since string) *Assignment {
+	return &Assignment{
+		AccountID: accountID,
+		ProductID: productID,
+		Since:     since,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+// Accrual is the entity that represents the interest accrued by an account on a business date: Amount is
+// earned by a balance above zero and Overdraft is charged on a balance below it. Accruals keep fractions of
+// cents; they are rounded once, when the month's interest is posted.
+type Accrual struct {
+	AccountID string  `json:"account_id"`
+	ProductID string  `json:"product_id"`
+	Date      string  `json:"date"`
+	Balance   float32 `json:"balance"`
+	Amount    float64 `json:"amount"`
+	Overdraft float64 `json:"overdraft,omitempty"`
+}
+
+// BusinessDayStatus is the type that represents the status of the end-of-day batch of a business date
+type BusinessDayStatus string
+
+const (
+	// BusinessDayCompleted is the status of a business date whose accruals, interest and fees were all posted
+	BusinessDayCompleted BusinessDayStatus = "completed"
+
+	// BusinessDayIncomplete is the status of a business date some accounts failed for. Running it again
+	// finishes it without posting anything twice.
+	BusinessDayIncomplete BusinessDayStatus = "incomplete"
+)
+
+// BusinessDay is the entity that represents the outcome of the end-of-day batch of a business date
+type BusinessDay struct {
+	Date     string            `json:"date"`
+	Status   BusinessDayStatus `json:"status"`
+	Accounts int               `json:"accounts"`
+
+	// Accrued is the interest accrued on the date; Interest is the interest posted at the end of a month.
+	// OverdraftAccrued and OverdraftInterest are their counterparts for the interest charged on overdrafts.
+	Accrued           float64 `json:"accrued"`
+	Interest          float32 `json:"interest"`
+	OverdraftAccrued  float64 `json:"overdraft_accrued,omitempty"`
+	OverdraftInterest float32 `json:"overdraft_interest,omitempty"`
+	Fees              float32 `json:"fees"`
+	Failures          int     `json:"failures"`
+
+	StartedAt   time.Time `json:"started_at"`
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+// IsMonthEnd reports whether a date is the last day of its month
+func IsMonthEnd(date time.Time) bool {
+	return date.AddDate(0, 0, 1).Day() == 1
+}
+
+// Period returns the month (YYYY-MM) of a date
+func Period(date time.Time) string {
+	return date.Format("2006-01")
+}
+
+// daysInYear returns the number of days of a year
+func daysInYear(year int) int {
+	return time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC).YearDay()
+}
@@ -0,0 +1,37 @@
+package product
+
+// Repository is the product repository interface
+type Repository interface {
+	// Save creates or replaces a product.
+	Save(product *Product) error
+
+	// Find gets a product by ID.
+	Find(id string) (*Product, error)
+
+	// FindAll gets every product, oldest first.
+	FindAll() ([]*Product, error)
+
+	// Assign creates or replaces the product of an account.
+	Assign(assignment *Assignment) error
+
+	// FindAssignment gets the product of an account.
+	FindAssignment(accountID string) (*Assignment, error)
+
+	// Assignments gets the products of every account.
+	Assignments() ([]*Assignment, error)
+
+	// SaveAccrual stores the accrual of an account for a business date, once.
+	SaveAccrual(accrual *Accrual) error
+
+	// Accruals gets the accruals of an account between two business dates, both included, oldest first.
+	Accruals(accountID, from, to string) ([]*Accrual, error)
+
+	// SaveBusinessDay creates or replaces the outcome of the end-of-day batch of a business date.
+	SaveBusinessDay(day *BusinessDay) error
+
+	// FindBusinessDay gets the outcome of the end-of-day batch of a business date.
+	FindBusinessDay(date string) (*BusinessDay, error)
+
+	// LastCompletedDay gets the latest completed business date.
+	LastCompletedDay() (string, error)
+}
@@ -0,0 +1,432 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/quabynah-bilson/quantia/pkg/ledger"
+	"github.com/quabynah-bilson/quantia/pkg/product"
+	"github.com/quabynah-bilson/quantia/pkg/transfer"
+	"log"
+	"math"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrInvalidProduct is the error returned when a product is not named or has invalid rates, day count or fees.
+	ErrInvalidProduct = errors.New("invalid product. Please check its name, type, rate tiers, day count and fees")
+
+	// ErrInvalidBusinessDate is the error returned when the end-of-day batch is run for a date that cannot be read or has not ended.
+	ErrInvalidBusinessDate = errors.New("invalid business date. Please check the date is formatted as YYYY-MM-DD and has ended")
+)
+
+// defaultMaxCatchUpDays is how many missed business dates are run at once when no maximum is configured.
+const defaultMaxCatchUpDays = 31
+
+// ProductConfig is the configuration of the product use case
+type ProductConfig struct {
+	// MaxCatchUpDays is how many missed business dates the end-of-day job runs at once, oldest first
+	MaxCatchUpDays int
+}
+
+// ProductUseCase is the account product use case. It keeps the configuration of the products accounts hold,
+// and runs the end-of-day batch that accrues their interest, posts it at the end of each month, and charges
+// their fees. Every posting has a reference derived from its business date, so a date can be run again
+// without posting anything twice.
+type ProductUseCase struct {
+	productRepo  product.Repository
+	ledgerRepo   ledger.Repository
+	transferRepo transfer.Repository
+	config       ProductConfig
+
+	// overdrafts lowers the overdraft limits of accounts moved to products allowing less. It is nil when overdrafts are off.
+	overdrafts *OverdraftUseCase
+
+	// mu serializes the end-of-day batches
+	mu sync.Mutex
+}
+
+// NewProductUseCase creates a new product use case.
+func NewProductUseCase(productRepo product.Repository, ledgerRepo ledger.Repository, transferRepo transfer.Repository, config ProductConfig) *ProductUseCase {
+	if config.MaxCatchUpDays <= 0 {
+		config.MaxCatchUpDays = defaultMaxCatchUpDays
+	}
+
+	return &ProductUseCase{
+		productRepo:  productRepo,
+		ledgerRepo:   ledgerRepo,
+		transferRepo: transferRepo,
+		config:       config,
+	}
+}
+
+// SetOverdrafts keeps the overdraft limits of accounts within the maximum of the products they are moved to.
+func (uc *ProductUseCase) SetOverdrafts(overdrafts *OverdraftUseCase) {
+	uc.overdrafts = overdrafts
+}
+
+// CreateProduct creates a product from its configuration.
+func (uc *ProductUseCase) CreateProduct(config product.Product) (*product.Product, error) {
+	p := product.NewProduct(config)
+	if !p.IsValid() {
+		return nil, ErrInvalidProduct
+	}
+
+	if err := uc.productRepo.Save(p); err != nil {
+		log.Printf("error saving product: %v", err)
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// UpdateProduct replaces the configuration of a product. The new rates and fees apply from the next business
+// date run; what was accrued or charged before is kept.
+func (uc *ProductUseCase) UpdateProduct(id string, config product.Product) (*product.Product, error) {
+	p, err := uc.productRepo.Find(id)
+	if err != nil {
+		return nil, err
+	}
+
+	p.Update(config)
+	if !p.IsValid() {
+		return nil, ErrInvalidProduct
+	}
+
+	if err = uc.productRepo.Save(p); err != nil {
+		log.Printf("error saving product %s: %v", id, err)
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// GetProduct gets a product by ID.
+func (uc *ProductUseCase) GetProduct(id string) (*product.Product, error) {
+	return uc.productRepo.Find(id)
+}
+
+// GetProducts gets every product, oldest first.
+func (uc *ProductUseCase) GetProducts() ([]*product.Product, error) {
+	return uc.productRepo.FindAll()
+}
+
+// AssignProduct moves an account to a product from today's business date. An overdraft above the maximum of
+// the new product is lowered to it.
+func (uc *ProductUseCase) AssignProduct(accountID, productID string) (*product.Assignment, error) {
+	if accountID == "" {
+		return nil, ErrInvalidAccount
+	}
+
+	p, err := uc.productRepo.Find(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	assignment := product.NewAssignment(accountID, productID, time.Now().UTC().Format(product.DateLayout))
+	if err = uc.productRepo.Assign(assignment); err != nil {
+		log.Printf("error assigning product %s to account %s: %v", productID, accountID, err)
+		return nil, err
+	}
+
+	if uc.overdrafts != nil {
+		if err = uc.overdrafts.Cap(accountID, p.Overdraft.MaxLimit); err != nil {
+			log.Printf("error capping overdraft of account %s: %v", accountID, err)
+		}
+	}
+
+	return assignment, nil
+}
+
+// GetAccountProduct gets the product an account holds.
+func (uc *ProductUseCase) GetAccountProduct(accountID string) (*product.Assignment, error) {
+	return uc.productRepo.FindAssignment(accountID)
+}
+
+// GetAccruals gets the interest accrued by an account from one business date to another, both included. A
+// missing to is today and a missing from is the first day of to's month.
+func (uc *ProductUseCase) GetAccruals(accountID, from, to string) ([]*product.Accrual, error) {
+	if to == "" {
+		to = time.Now().UTC().Format(product.DateLayout)
+	}
+
+	end, err := time.Parse(product.DateLayout, to)
+	if err != nil {
+		return nil, ErrInvalidBusinessDate
+	}
+
+	if from == "" {
+		from = end.AddDate(0, 0, 1-end.Day()).Format(product.DateLayout)
+	}
+	if _, err = time.Parse(product.DateLayout, from); err != nil || from > to {
+		return nil, ErrInvalidBusinessDate
+	}
+
+	return uc.productRepo.Accruals(accountID, from, to)
+}
+
+// GetBusinessDay gets the outcome of the end-of-day batch of a business date.
+func (uc *ProductUseCase) GetBusinessDay(date string) (*product.BusinessDay, error) {
+	return uc.productRepo.FindBusinessDay(date)
+}
+
+// RunEndOfDay runs the end-of-day batch of a business date that has ended. For every account holding a
+// product, it accrues the interest, or overdraft interest, of the date's closing balance and charges the fees
+// of the date's transfers; on the last day of a month, it also posts the month's interest and maintenance fee. A completed date is
+// not run again; an incomplete one is finished.
+func (uc *ProductUseCase) RunEndOfDay(date string) (*product.BusinessDay, error) {
+	businessDate, err := time.Parse(product.DateLayout, date)
+	if err != nil || !businessDate.Before(time.Now().UTC().Truncate(24*time.Hour)) {
+		return nil, ErrInvalidBusinessDate
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	if day, err := uc.productRepo.FindBusinessDay(date); err == nil && day.Status == product.BusinessDayCompleted {
+		return day, nil
+	}
+
+	assignments, err := uc.productRepo.Assignments()
+	if err != nil {
+		log.Printf("error getting product assignments: %v", err)
+		return nil, err
+	}
+
+	transfers, err := uc.transferRepo.FindByDate(date)
+	if err != nil {
+		log.Printf("error getting transfers of %s: %v", date, err)
+		return nil, err
+	}
+
+	// only the transfers that did not fail pay fees
+	accountTransfers := make(map[string][]*transfer.Transfer)
+	for _, t := range transfers {
+		if t.Status != transfer.StatusFailed {
+			accountTransfers[t.AccountID] = append(accountTransfers[t.AccountID], t)
+		}
+	}
+
+	day := &product.BusinessDay{Date: date, StartedAt: time.Now().UTC()}
+	products := make(map[string]*product.Product)
+	for _, assignment := range assignments {
+		if assignment.Since > date {
+			continue
+		}
+
+		p, ok := products[assignment.ProductID]
+		if !ok {
+			if p, err = uc.productRepo.Find(assignment.ProductID); err != nil {
+				log.Printf("error finding product %s: %v", assignment.ProductID, err)
+				day.Failures++
+				continue
+			}
+			products[p.ID] = p
+		}
+
+		day.Accounts++
+		if err = uc.endOfDay(assignment, p, businessDate, accountTransfers[assignment.AccountID], day); err != nil {
+			log.Printf("error running end of day %s for account %s: %v", date, assignment.AccountID, err)
+			day.Failures++
+		}
+	}
+
+	day.Status = product.BusinessDayCompleted
+	if day.Failures > 0 {
+		day.Status = product.BusinessDayIncomplete
+	}
+	day.CompletedAt = time.Now().UTC()
+	if err = uc.productRepo.SaveBusinessDay(day); err != nil {
+		log.Printf("error saving business date %s: %v", date, err)
+		return nil, err
+	}
+
+	return day, nil
+}
+
+// Run runs the end-of-day batch of the business dates that have ended since the last completed one, checking
+// every interval until the context is cancelled.
+func (uc *ProductUseCase) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		uc.RunDue(time.Now().UTC())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunDue runs the business dates after the last completed one up to the day before now, oldest first and at
+// most MaxCatchUpDays of them, and returns how many completed. Without a completed date, only yesterday is
+// run. It stops at a date that does not complete, so that it is retried before the dates after it.
+func (uc *ProductUseCase) RunDue(now time.Time) int {
+	yesterday := now.UTC().Truncate(24*time.Hour).AddDate(0, 0, -1)
+	next := yesterday
+	if last, err := uc.productRepo.LastCompletedDay(); err == nil {
+		if lastDate, err := time.Parse(product.DateLayout, last); err == nil {
+			next = lastDate.AddDate(0, 0, 1)
+		}
+	}
+
+	completed := 0
+	for ; !next.After(yesterday) && completed < uc.config.MaxCatchUpDays; next = next.AddDate(0, 0, 1) {
+		day, err := uc.RunEndOfDay(next.Format(product.DateLayout))
+		if err != nil || day.Status != product.BusinessDayCompleted {
+			break
+		}
+		completed++
+	}
+
+	if completed > 0 {
+		log.Printf("completed %d business dates", completed)
+	}
+
+	return completed
+}
+
+// endOfDay accrues the interest and charges the fees of an account for a business date, adding them to the
+// outcome of the date.
+func (uc *ProductUseCase) endOfDay(assignment *product.Assignment, p *product.Product, date time.Time, transfers []*transfer.Transfer, day *product.BusinessDay) error {
+	accountID, businessDate := assignment.AccountID, date.Format(product.DateLayout)
+
+	if p.AccruesInterest() {
+		balance, err := uc.closingBalance(accountID, date)
+		if err != nil {
+			return err
+		}
+
+		accrual := &product.Accrual{
+			AccountID: accountID,
+			ProductID: p.ID,
+			Date:      businessDate,
+			Balance:   float32(balance),
+			Amount:    p.DailyInterest(balance, date),
+			Overdraft: p.DailyOverdraftInterest(balance, date),
+		}
+		if err = uc.productRepo.SaveAccrual(accrual); err == nil {
+			day.Accrued += accrual.Amount
+			day.OverdraftAccrued += accrual.Overdraft
+		} else if !errors.Is(err, product.ErrAccrualAlreadyRecorded) {
+			return err
+		}
+	}
+
+	for _, t := range transfers {
+		if err := uc.charge(accountID, "fee:"+t.ID, "transfer fee", p.Fees.Transfer.Fee(t.Amount), date, day); err != nil {
+			return err
+		}
+	}
+
+	if !product.IsMonthEnd(date) {
+		return nil
+	}
+
+	period := product.Period(date)
+	if err := uc.postInterest(assignment, period, date, day); err != nil {
+		return err
+	}
+
+	return uc.charge(accountID, "fee:maintenance:"+accountID+":"+period, "maintenance fee for "+period, p.Fees.Maintenance, date, day)
+}
+
+// postInterest posts the interest an account earned over the month of a business date, and charges the
+// overdraft interest it accrued, each rounded to cents.
+func (uc *ProductUseCase) postInterest(assignment *product.Assignment, period string, date time.Time, day *product.BusinessDay) error {
+	from := date.AddDate(0, 0, 1-date.Day()).Format(product.DateLayout)
+	accruals, err := uc.productRepo.Accruals(assignment.AccountID, from, date.Format(product.DateLayout))
+	if err != nil {
+		return err
+	}
+
+	accrued, overdraft := 0.0, 0.0
+	for _, accrual := range accruals {
+		accrued += accrual.Amount
+		overdraft += accrual.Overdraft
+	}
+
+	if interest := float32(math.Round(accrued*100) / 100); interest > 0 {
+		entries := ledger.NewTransfer("interest:"+assignment.AccountID+":"+period, "interest for "+period, ledger.InterestExpenseAccountID, assignment.AccountID, interest)
+		posted, err := uc.post(entries, date)
+		if err != nil {
+			return err
+		}
+		if posted {
+			day.Interest += interest
+		}
+	}
+
+	if interest := float32(math.Round(overdraft*100) / 100); interest > 0 {
+		entries := ledger.NewTransfer("interest:overdraft:"+assignment.AccountID+":"+period, "overdraft interest for "+period, assignment.AccountID, ledger.InterestIncomeAccountID, interest)
+		posted, err := uc.post(entries, date)
+		if err != nil {
+			return err
+		}
+		if posted {
+			day.OverdraftInterest += interest
+		}
+	}
+
+	return nil
+}
+
+// charge posts a fee from an account to the fee income account. Zero fees are not posted.
+func (uc *ProductUseCase) charge(accountID, reference, description string, fee float32, date time.Time, day *product.BusinessDay) error {
+	if fee <= 0 {
+		return nil
+	}
+
+	posted, err := uc.post(ledger.NewTransfer(reference, description, accountID, ledger.FeeIncomeAccountID, fee), date)
+	if posted {
+		day.Fees += fee
+	}
+
+	return err
+}
+
+// post records entries on the last second of their business date, so that they belong to it in balances and
+// statements. Entries posted by an earlier run of the date are left alone.
+func (uc *ProductUseCase) post(entries []*ledger.Entry, date time.Time) (bool, error) {
+	valueDate := date.AddDate(0, 0, 1).Add(-time.Second)
+	for _, entry := range entries {
+		entry.CreatedAt = valueDate
+	}
+
+	if err := uc.ledgerRepo.Post(entries...); err != nil {
+		if errors.Is(err, ledger.ErrEntriesAlreadyPosted) {
+			return false, nil
+		}
+		return false, fmt.Errorf("posting %s: %w", entries[0].Reference, err)
+	}
+
+	return true, nil
+}
+
+// closingBalance returns the balance of an account at the end of a business date
+func (uc *ProductUseCase) closingBalance(accountID string, date time.Time) (float64, error) {
+	entries, err := uc.ledgerRepo.Entries(accountID)
+	if err != nil {
+		return 0, err
+	}
+
+	end := date.AddDate(0, 0, 1)
+	var cents int64
+	for _, entry := range entries {
+		if !entry.CreatedAt.Before(end) {
+			continue
+		}
+
+		amount := int64(math.Round(float64(entry.Amount) * 100))
+		if entry.Type == ledger.EntryTypeDebit {
+			amount = -amount
+		}
+		cents += amount
+	}
+
+	return float64(cents) / 100, nil
+}
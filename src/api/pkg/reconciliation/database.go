@@ -0,0 +1,30 @@
+package reconciliation
+
+import "errors"
+
+var (
+	// ErrStatementNotFound is the error returned when a statement does not exist
+	ErrStatementNotFound = errors.New("statement not found")
+
+	// ErrFailedToSaveStatement is the error returned when a statement or its items cannot be stored
+	ErrFailedToSaveStatement = errors.New("failed to save statement. Please try again")
+
+	// ErrStatementAlreadyImported is the error returned when the same statement file is imported twice
+	ErrStatementAlreadyImported = errors.New("statement already imported")
+)
+
+// Database is the interface that wraps the basic reconciliation database operations.
+type Database interface {
+	// SaveStatement stores a new statement with its items, indexing the items by business date. It fails with
+	// ErrStatementAlreadyImported when a statement with the same checksum was stored before.
+	SaveStatement(statement *Statement, items []*Item) error
+
+	// GetStatement gets a statement by ID
+	GetStatement(id string) (*Statement, error)
+
+	// GetStatementItems gets the items of a statement, in statement order
+	GetStatementItems(statementID string) ([]*Item, error)
+
+	// GetDateItems gets the items of every statement falling on a business date, in import order
+	GetDateItems(date string) ([]*Item, error)
+}
@@ -0,0 +1,167 @@
+package reconciliation
+
+import (
+	"github.com/google/uuid"
+	"math"
+	"time"
+)
+
+// DateLayout is the layout of the business dates that statement entries and settlement reports fall on
+const DateLayout = "2006-01-02"
+
+// ItemStatus is the type that represents the outcome of matching a statement entry
+type ItemStatus string
+
+const (
+	// ItemStatusMatched is the status of an entry that agrees with our records, within the tolerance
+	ItemStatusMatched ItemStatus = "matched"
+
+	// ItemStatusMismatched is the status of an entry whose transaction was found but disagrees with it: the
+	// amount is outside the tolerance, the transaction did not succeed, or the entry is a duplicate
+	ItemStatusMismatched ItemStatus = "mismatched"
+
+	// ItemStatusUnmatched is the status of an entry with no transaction or refund of ours behind it
+	ItemStatusUnmatched ItemStatus = "unmatched"
+)
+
+// ItemType is the type that represents what a statement entry was matched against
+type ItemType string
+
+const (
+	// ItemTypePayment is the type of an entry matched against a payment transaction
+	ItemTypePayment ItemType = "payment"
+
+	// ItemTypeRefund is the type of an entry matched against a refund
+	ItemTypeRefund ItemType = "refund"
+)
+
+// Line is an entry of a provider or bank statement
+type Line struct {
+	// Reference is the reference we sent with the payment or refund, which is its ID
+	Reference string `json:"reference"`
+
+	// Amount is the amount settled. Refunds may be negative; only the size of the amount is compared.
+	Amount      float32   `json:"amount"`
+	Date        time.Time `json:"date"`
+	Description string    `json:"description,omitempty"`
+}
+
+// Statement is an imported statement file and the outcome of matching its entries
+type Statement struct {
+	ID     string `json:"id"`
+	Source string `json:"source"`
+
+	// Checksum is the SHA-256 of the file, so that a statement is not imported twice
+	Checksum   string    `json:"checksum"`
+	Lines      int       `json:"lines"`
+	Matched    int       `json:"matched"`
+	Mismatched int       `json:"mismatched"`
+	Unmatched  int       `json:"unmatched"`
+	ImportedAt time.Time `json:"imported_at"`
+}
+
+// NewStatement creates a statement imported from the source
+func NewStatement(source, checksum string) *Statement {
+	return &Statement{
+		ID:         "stm_" + uuid.NewString(),
+		Source:     source,
+		Checksum:   checksum,
+		ImportedAt: time.Now().UTC(),
+	}
+}
+
+// Count adds the outcome of an item to the statement's totals
+func (s *Statement) Count(item *Item) {
+	s.Lines++
+	switch item.Status {
+	case ItemStatusMatched:
+		s.Matched++
+	case ItemStatusMismatched:
+		s.Mismatched++
+	case ItemStatusUnmatched:
+		s.Unmatched++
+	}
+}
+
+// Item is the outcome of matching a statement entry against our records
+type Item struct {
+	StatementID string `json:"statement_id"`
+
+	// Row is the entry's position in the statement, from 1
+	Row  int  `json:"row"`
+	Line Line `json:"line"`
+
+	// Date is the business date of the entry
+	Date   string     `json:"date"`
+	Status ItemStatus `json:"status"`
+	Type   ItemType   `json:"type,omitempty"`
+
+	// TransactionID is the payment matched, or refunded by the refund matched
+	TransactionID string `json:"transaction_id,omitempty"`
+	RefundID      string `json:"refund_id,omitempty"`
+
+	// Merchant is the ledger account of the merchant the transaction was paid to
+	Merchant string `json:"merchant,omitempty"`
+
+	// Expected is the amount of our transaction or refund; Difference is the statement amount less it
+	Expected   float32 `json:"expected,omitempty"`
+	Difference float32 `json:"difference,omitempty"`
+	Reason     string  `json:"reason,omitempty"`
+}
+
+// NewItem creates an unmatched item for the entry at the given row of a statement
+func NewItem(statementID string, row int, line Line) *Item {
+	return &Item{
+		StatementID: statementID,
+		Row:         row,
+		Line:        line,
+		Date:        line.Date.UTC().Format(DateLayout),
+		Status:      ItemStatusUnmatched,
+	}
+}
+
+// Settled returns the size of the entry's amount
+func (i *Item) Settled() float32 {
+	return float32(math.Abs(float64(i.Line.Amount)))
+}
+
+// Compare records our amount for the entry and how far the settled amount is from it
+func (i *Item) Compare(expected float32) {
+	i.Expected = expected
+	i.Difference = fromMinorUnits(toMinorUnits(i.Settled()) - toMinorUnits(expected))
+}
+
+// Tolerance is how far a settled amount may be from ours and still match. The larger of the two rules
+// applies; with neither set, amounts must match to the cent.
+type Tolerance struct {
+	// Amount is the absolute difference allowed
+	Amount float32 `json:"amount,omitempty"`
+
+	// Percent is the difference allowed as a percentage of our amount
+	Percent float32 `json:"percent,omitempty"`
+}
+
+// Allows reports whether the settled amount is close enough to the expected amount
+func (t Tolerance) Allows(expected, settled float32) bool {
+	difference := toMinorUnits(settled) - toMinorUnits(expected)
+	if difference < 0 {
+		difference = -difference
+	}
+
+	allowed := toMinorUnits(t.Amount)
+	if percent := int64(math.Floor(float64(toMinorUnits(expected)) * float64(t.Percent) / 100)); percent > allowed {
+		allowed = percent
+	}
+
+	return difference <= allowed
+}
+
+// toMinorUnits converts an amount to cents
+func toMinorUnits(amount float32) int64 {
+	return int64(math.Round(float64(amount) * 100))
+}
+
+// fromMinorUnits converts cents to an amount
+func fromMinorUnits(amount int64) float32 {
+	return float32(amount) / 100
+}
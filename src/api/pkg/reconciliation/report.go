@@ -0,0 +1,116 @@
+package reconciliation
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// SettlementReport is what was settled to a merchant on a business date, according to the imported statements
+type SettlementReport struct {
+	Date     string `json:"date"`
+	Merchant string `json:"merchant"`
+
+	// Payments and Gross are the count and total of the payments settled as expected
+	Payments int     `json:"payments"`
+	Gross    float32 `json:"gross"`
+
+	// Refunds and Refunded are the count and total of the refunds settled as expected
+	Refunds  int     `json:"refunds"`
+	Refunded float32 `json:"refunded"`
+
+	// Net is the gross less the refunds
+	Net float32 `json:"net"`
+
+	// Mismatched is the number of entries that disagree with our records; Difference is the sum of the
+	// differences between the settled amounts and ours, over every entry of the merchant
+	Mismatched int     `json:"mismatched"`
+	Difference float32 `json:"difference"`
+}
+
+// BuildReports sums the items of a business date into a report per merchant, ordered by merchant. Unmatched
+// items belong to no merchant and are left out.
+func BuildReports(date string, items []*Item) []*SettlementReport {
+	type totals struct {
+		payments, refunds, mismatched int
+		gross, refunded, difference   int64
+	}
+
+	byMerchant := make(map[string]*totals)
+	for _, item := range items {
+		if item.Date != date || item.Merchant == "" {
+			continue
+		}
+
+		t, ok := byMerchant[item.Merchant]
+		if !ok {
+			t = &totals{}
+			byMerchant[item.Merchant] = t
+		}
+
+		t.difference += toMinorUnits(item.Difference)
+		switch {
+		case item.Status == ItemStatusMismatched:
+			t.mismatched++
+		case item.Type == ItemTypeRefund:
+			t.refunds++
+			t.refunded += toMinorUnits(item.Settled())
+		default:
+			t.payments++
+			t.gross += toMinorUnits(item.Settled())
+		}
+	}
+
+	reports := make([]*SettlementReport, 0, len(byMerchant))
+	for merchant, t := range byMerchant {
+		reports = append(reports, &SettlementReport{
+			Date:       date,
+			Merchant:   merchant,
+			Payments:   t.payments,
+			Gross:      fromMinorUnits(t.gross),
+			Refunds:    t.refunds,
+			Refunded:   fromMinorUnits(t.refunded),
+			Net:        fromMinorUnits(t.gross - t.refunded),
+			Mismatched: t.mismatched,
+			Difference: fromMinorUnits(t.difference),
+		})
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		return reports[i].Merchant < reports[j].Merchant
+	})
+
+	return reports
+}
+
+// WriteReports writes settlement reports as a CSV file with a header row
+func WriteReports(w io.Writer, reports []*SettlementReport) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"date", "merchant", "payments", "gross", "refunds", "refunded", "net", "mismatched", "difference"}); err != nil {
+		return err
+	}
+
+	for _, report := range reports {
+		if err := writer.Write([]string{
+			report.Date,
+			report.Merchant,
+			strconv.Itoa(report.Payments),
+			formatAmount(report.Gross),
+			strconv.Itoa(report.Refunds),
+			formatAmount(report.Refunded),
+			formatAmount(report.Net),
+			strconv.Itoa(report.Mismatched),
+			formatAmount(report.Difference),
+		}); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// formatAmount formats an amount with two decimal places
+func formatAmount(amount float32) string {
+	return strconv.FormatFloat(float64(amount), 'f', 2, 32)
+}
@@ -0,0 +1,16 @@
+package reconciliation
+
+// Repository is the reconciliation repository interface
+type Repository interface {
+	// Save stores a new statement with its items.
+	Save(statement *Statement, items []*Item) error
+
+	// Find gets a statement by ID.
+	Find(id string) (*Statement, error)
+
+	// Items gets the items of a statement, in statement order.
+	Items(statementID string) ([]*Item, error)
+
+	// DateItems gets the items falling on a business date.
+	DateItems(date string) ([]*Item, error)
+}
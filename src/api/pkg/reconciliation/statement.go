@@ -0,0 +1,86 @@
+package reconciliation
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidStatement is the error returned when a statement file cannot be read, misses required columns or has no entries
+var ErrInvalidStatement = errors.New("invalid statement. Please check the CSV header, the amounts and the dates")
+
+// ParseCSV reads the entries of a statement from a CSV file with a header row. The reference, amount and date
+// columns are required and matched by name, in any order; a description column is read when present. Dates
+// are either business dates (2006-01-02) or RFC 3339 timestamps.
+func ParseCSV(r io.Reader) ([]Line, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, ErrInvalidStatement
+	}
+
+	index := make(map[string]int)
+	for i, name := range header {
+		index[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"reference", "amount", "date"} {
+		if _, ok := index[required]; !ok {
+			return nil, ErrInvalidStatement
+		}
+	}
+
+	var lines []Line
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, ErrInvalidStatement
+		}
+
+		field := func(name string) string {
+			if i, ok := index[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		amount, err := strconv.ParseFloat(field("amount"), 32)
+		if err != nil {
+			return nil, ErrInvalidStatement
+		}
+
+		date, err := ParseDate(field("date"))
+		if err != nil {
+			return nil, ErrInvalidStatement
+		}
+
+		lines = append(lines, Line{
+			Reference:   field("reference"),
+			Amount:      float32(amount),
+			Date:        date,
+			Description: field("description"),
+		})
+	}
+
+	if len(lines) == 0 {
+		return nil, ErrInvalidStatement
+	}
+
+	return lines, nil
+}
+
+// ParseDate reads a business date or an RFC 3339 timestamp
+func ParseDate(value string) (time.Time, error) {
+	if date, err := time.Parse(DateLayout, value); err == nil {
+		return date, nil
+	}
+
+	return time.Parse(time.RFC3339, value)
+}
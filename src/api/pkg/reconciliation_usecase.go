@@ -0,0 +1,224 @@
+package pkg
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/quabynah-bilson/quantia/pkg/iso20022"
+	"github.com/quabynah-bilson/quantia/pkg/ledger"
+	"github.com/quabynah-bilson/quantia/pkg/payment"
+	"github.com/quabynah-bilson/quantia/pkg/reconciliation"
+	"log"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidStatementSource is the error returned when a statement is imported without naming its provider or bank.
+	ErrInvalidStatementSource = errors.New("invalid statement source. Please name the provider or bank the statement comes from")
+
+	// ErrUnsupportedStatementFormat is the error returned when a statement is imported in a format we cannot read.
+	ErrUnsupportedStatementFormat = errors.New("unsupported statement format. Please send a CSV file or a camt.053 statement")
+
+	// ErrInvalidSettlementDate is the error returned when a settlement report is asked for without a valid business date.
+	ErrInvalidSettlementDate = errors.New("invalid settlement date. Please check the date is formatted as YYYY-MM-DD")
+)
+
+// ReconciliationConfig is the configuration of the reconciliation use case
+type ReconciliationConfig struct {
+	// Tolerance is how far a settled amount may be from ours and still match
+	Tolerance reconciliation.Tolerance
+}
+
+// ReconciliationUseCase is the reconciliation use case. It matches the entries of provider and bank statements
+// against our transactions and refunds, and sums what was settled into daily reports per merchant.
+type ReconciliationUseCase struct {
+	reconciliationRepo reconciliation.Repository
+	paymentRepo        payment.Repository
+	config             ReconciliationConfig
+}
+
+// NewReconciliationUseCase creates a new reconciliation use case.
+func NewReconciliationUseCase(reconciliationRepo reconciliation.Repository, paymentRepo payment.Repository, config ReconciliationConfig) *ReconciliationUseCase {
+	return &ReconciliationUseCase{
+		reconciliationRepo: reconciliationRepo,
+		paymentRepo:        paymentRepo,
+		config:             config,
+	}
+}
+
+// ImportCSV reads a CSV statement from the source and reconciles its entries.
+func (uc *ReconciliationUseCase) ImportCSV(source string, content []byte) (*reconciliation.Statement, error) {
+	lines, err := reconciliation.ParseCSV(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.importLines(source, content, lines)
+}
+
+// ImportCamt053 reads an ISO 20022 camt.053 bank statement from the source and reconciles its booked entries.
+func (uc *ReconciliationUseCase) ImportCamt053(source string, content []byte) (*reconciliation.Statement, error) {
+	doc, err := iso20022.ParseCamt053(content)
+	if err != nil {
+		log.Printf("error parsing camt.053 statement: %v", err)
+		return nil, err
+	}
+
+	return uc.importLines(source, content, doc.Lines())
+}
+
+// GetStatement gets a statement by ID.
+func (uc *ReconciliationUseCase) GetStatement(id string) (*reconciliation.Statement, error) {
+	return uc.reconciliationRepo.Find(id)
+}
+
+// GetItems gets the items of a statement, keeping those with the given status when one is set.
+func (uc *ReconciliationUseCase) GetItems(statementID string, status reconciliation.ItemStatus) ([]*reconciliation.Item, error) {
+	if _, err := uc.reconciliationRepo.Find(statementID); err != nil {
+		return nil, err
+	}
+
+	items, err := uc.reconciliationRepo.Items(statementID)
+	if err != nil {
+		log.Printf("error getting items of statement %s: %v", statementID, err)
+		return nil, err
+	}
+
+	if status == "" {
+		return items, nil
+	}
+
+	filtered := make([]*reconciliation.Item, 0, len(items))
+	for _, item := range items {
+		if item.Status == status {
+			filtered = append(filtered, item)
+		}
+	}
+
+	return filtered, nil
+}
+
+// SettlementReports builds the settlement reports of a business date, for every merchant or for the one
+// whose ledger account is given.
+func (uc *ReconciliationUseCase) SettlementReports(date, merchant string) ([]*reconciliation.SettlementReport, error) {
+	if _, err := time.Parse(reconciliation.DateLayout, date); err != nil {
+		return nil, ErrInvalidSettlementDate
+	}
+
+	items, err := uc.reconciliationRepo.DateItems(date)
+	if err != nil {
+		log.Printf("error getting reconciliation items of %s: %v", date, err)
+		return nil, err
+	}
+
+	reports := reconciliation.BuildReports(date, items)
+	if merchant == "" {
+		return reports, nil
+	}
+
+	filtered := make([]*reconciliation.SettlementReport, 0, 1)
+	for _, report := range reports {
+		if report.Merchant == merchant {
+			filtered = append(filtered, report)
+		}
+	}
+
+	return filtered, nil
+}
+
+// importLines matches the entries read from a statement file and stores the outcome. The same file is
+// only imported once.
+func (uc *ReconciliationUseCase) importLines(source string, content []byte, lines []reconciliation.Line) (*reconciliation.Statement, error) {
+	source = strings.TrimSpace(source)
+	if source == "" {
+		return nil, ErrInvalidStatementSource
+	}
+
+	if len(lines) == 0 {
+		return nil, reconciliation.ErrInvalidStatement
+	}
+
+	checksum := sha256.Sum256(content)
+	statement := reconciliation.NewStatement(source, hex.EncodeToString(checksum[:]))
+
+	seen := make(map[string]bool)
+	items := make([]*reconciliation.Item, 0, len(lines))
+	for i, line := range lines {
+		item := reconciliation.NewItem(statement.ID, i+1, line)
+		if err := uc.match(item, seen); err != nil {
+			return nil, err
+		}
+		statement.Count(item)
+		items = append(items, item)
+	}
+
+	if err := uc.reconciliationRepo.Save(statement, items); err != nil {
+		log.Printf("error saving statement from %s: %v", source, err)
+		return nil, err
+	}
+
+	return statement, nil
+}
+
+// match looks up the transaction or refund behind a statement entry and compares them. Entries whose
+// reference was already seen in the statement are mismatched, so that money settled twice stands out.
+func (uc *ReconciliationUseCase) match(item *reconciliation.Item, seen map[string]bool) error {
+	reference := item.Line.Reference
+	if reference == "" {
+		item.Reason = "the entry has no reference"
+		return nil
+	}
+
+	var transaction *payment.Transaction
+	var settled bool
+	var err error
+	if payment.IsRefundReference(reference) {
+		var refund *payment.Refund
+		if refund, err = uc.paymentRepo.FindRefund(reference); err == nil {
+			item.Type, item.RefundID, item.TransactionID = reconciliation.ItemTypeRefund, refund.ID, refund.TransactionID
+			item.Compare(refund.Amount)
+			transaction, err = uc.paymentRepo.Find(refund.TransactionID)
+			settled = refund.Status == payment.RefundStatusSucceeded
+			if !settled {
+				item.Reason = fmt.Sprintf("the refund is %s", refund.Status)
+			}
+		}
+	} else {
+		if transaction, err = uc.paymentRepo.Find(reference); err == nil {
+			item.Type, item.TransactionID = reconciliation.ItemTypePayment, transaction.ID
+			item.Compare(transaction.Amount)
+			// payments refunded since are still settled; the refunds have entries of their own
+			settled = transaction.IsRefundable() || transaction.Status == payment.TransactionStatusRefunded
+			if !settled {
+				item.Reason = fmt.Sprintf("the transaction is %s", transaction.Status)
+			}
+		}
+	}
+
+	if err != nil {
+		if errors.Is(err, payment.ErrTransactionNotFound) || errors.Is(err, payment.ErrRefundNotFound) {
+			item.Reason = "no transaction or refund has this reference"
+			return nil
+		}
+		log.Printf("error matching statement entry %s: %v", reference, err)
+		return err
+	}
+
+	item.Merchant = ledger.MerchantAccountID(transaction.Url)
+	switch {
+	case seen[reference]:
+		item.Status, item.Reason = reconciliation.ItemStatusMismatched, "the reference appears more than once in the statement"
+	case !settled:
+		item.Status = reconciliation.ItemStatusMismatched
+	case !uc.config.Tolerance.Allows(item.Expected, item.Settled()):
+		item.Status, item.Reason = reconciliation.ItemStatusMismatched, fmt.Sprintf("the settled amount differs by %.2f", item.Difference)
+	default:
+		item.Status = reconciliation.ItemStatusMatched
+	}
+	seen[reference] = true
+
+	return nil
+}
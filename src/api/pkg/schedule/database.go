@@ -0,0 +1,39 @@
+package schedule
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrScheduleNotFound is the error returned when a scheduled payment does not exist
+	ErrScheduleNotFound = errors.New("scheduled payment not found")
+
+	// ErrFailedToSaveSchedule is the error returned when a scheduled payment cannot be stored
+	ErrFailedToSaveSchedule = errors.New("failed to save scheduled payment. Please try again")
+
+	// ErrOccurrenceAlreadyClaimed is the error returned when an occurrence has already been run, or is being run
+	ErrOccurrenceAlreadyClaimed = errors.New("occurrence already claimed")
+)
+
+// Database is the interface that wraps the basic scheduled payment database operations.
+type Database interface {
+	// SaveSchedule creates or replaces a scheduled payment. Active schedules are indexed by their next run time.
+	SaveSchedule(schedule *ScheduledPayment) error
+
+	// GetSchedule gets a scheduled payment by ID
+	GetSchedule(id string) (*ScheduledPayment, error)
+
+	// GetDueSchedules gets the IDs of the active schedules whose next run is at or before the given time
+	GetDueSchedules(now time.Time, limit int) ([]string, error)
+
+	// ClaimOccurrence marks an occurrence as taken, failing with ErrOccurrenceAlreadyClaimed if it already was.
+	// Claims are permanent so that an occurrence never runs twice, even across restarts.
+	ClaimOccurrence(id string, occurrence int) error
+
+	// SaveRun records the outcome of an occurrence
+	SaveRun(run *Run) error
+
+	// GetRuns gets the outcomes of a schedule's occurrences, oldest first
+	GetRuns(id string) ([]*Run, error)
+}
@@ -0,0 +1,189 @@
+package schedule
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// Frequency is the type that represents how often a scheduled payment runs
+type Frequency string
+
+const (
+	// FrequencyOnce runs a payment once, at a future date
+	FrequencyOnce Frequency = "once"
+
+	// FrequencyDaily runs a payment every day
+	FrequencyDaily Frequency = "daily"
+
+	// FrequencyWeekly runs a payment every week on the weekday of the start date
+	FrequencyWeekly Frequency = "weekly"
+
+	// FrequencyMonthly runs a payment every month on the day of the start date, or the last day of shorter months
+	FrequencyMonthly Frequency = "monthly"
+)
+
+// IsValid reports whether the frequency is supported
+func (f Frequency) IsValid() bool {
+	switch f {
+	case FrequencyOnce, FrequencyDaily, FrequencyWeekly, FrequencyMonthly:
+		return true
+	}
+
+	return false
+}
+
+// Status is the type that represents the status of a scheduled payment
+type Status string
+
+const (
+	// StatusActive is the status of a schedule waiting for its next occurrence
+	StatusActive Status = "active"
+
+	// StatusPaused is the status of a schedule whose occurrences are skipped until it is resumed
+	StatusPaused Status = "paused"
+
+	// StatusCancelled is the status of a schedule stopped by its owner
+	StatusCancelled Status = "cancelled"
+
+	// StatusCompleted is the status of a schedule that reached its end date or count
+	StatusCompleted Status = "completed"
+)
+
+// ScheduledPayment is the entity that represents a payment made on a schedule (a standing order)
+type ScheduledPayment struct {
+	ID string `json:"id"`
+
+	// AccountID is the account of the payer who set up the schedule
+	AccountID string  `json:"account_id"`
+	Amount    float32 `json:"amount"`
+
+	// Url is the merchant endpoint of the payments, also notified when an occurrence fails
+	Url string `json:"url"`
+
+	// Source is the payer's instrument charged on every occurrence
+	Source    string    `json:"source,omitempty"`
+	Frequency Frequency `json:"frequency"`
+	StartAt   time.Time `json:"start_at"`
+
+	// EndAt is the date after which no occurrence runs, if set
+	EndAt *time.Time `json:"end_at,omitempty"`
+
+	// Count is the number of occurrences, skipped ones included, if set
+	Count  int    `json:"count,omitempty"`
+	Status Status `json:"status"`
+
+	// NextOccurrence is the index of the next occurrence, starting at 0 for StartAt
+	NextOccurrence int       `json:"next_occurrence"`
+	NextRunAt      time.Time `json:"next_run_at"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// NewScheduledPayment creates an active scheduled payment set up by the account, whose first occurrence is at startAt
+func NewScheduledPayment(accountID string, amount float32, url, source string, frequency Frequency, startAt time.Time, endAt *time.Time, count int) *ScheduledPayment {
+	s := &ScheduledPayment{
+		ID:        "sch_" + uuid.NewString(),
+		AccountID: accountID,
+		Amount:    amount,
+		Url:       url,
+		Source:    source,
+		Frequency: frequency,
+		StartAt:   startAt.UTC(),
+		EndAt:     endAt,
+		Count:     count,
+		Status:    StatusActive,
+		CreatedAt: time.Now().UTC(),
+	}
+	s.NextRunAt = s.OccurrenceAt(0)
+
+	return s
+}
+
+// OccurrenceAt returns the time of the nth occurrence. Occurrences are computed from the start date so
+// that monthly payments return to their day after a short month.
+func (s *ScheduledPayment) OccurrenceAt(n int) time.Time {
+	switch s.Frequency {
+	case FrequencyDaily:
+		return s.StartAt.AddDate(0, 0, n)
+	case FrequencyWeekly:
+		return s.StartAt.AddDate(0, 0, 7*n)
+	case FrequencyMonthly:
+		year, month, day := s.StartAt.Date()
+		first := time.Date(year, month+time.Month(n), 1, s.StartAt.Hour(), s.StartAt.Minute(), s.StartAt.Second(), s.StartAt.Nanosecond(), time.UTC)
+		if last := first.AddDate(0, 1, -1).Day(); day > last {
+			day = last
+		}
+		return first.AddDate(0, 0, day-1)
+	default:
+		return s.StartAt
+	}
+}
+
+// HasOccurrence reports whether the nth occurrence is within the schedule's end date and count
+func (s *ScheduledPayment) HasOccurrence(n int) bool {
+	if s.Frequency == FrequencyOnce && n > 0 {
+		return false
+	}
+
+	if s.Count > 0 && n >= s.Count {
+		return false
+	}
+
+	return s.EndAt == nil || !s.OccurrenceAt(n).After(*s.EndAt)
+}
+
+// Advance moves the schedule past the nth occurrence, completing it when no occurrence is left
+func (s *ScheduledPayment) Advance(n int) {
+	s.NextOccurrence = n + 1
+	if !s.HasOccurrence(s.NextOccurrence) {
+		if s.Status == StatusActive || s.Status == StatusPaused {
+			s.Status = StatusCompleted
+		}
+		return
+	}
+
+	s.NextRunAt = s.OccurrenceAt(s.NextOccurrence)
+}
+
+// AdvanceTo moves the schedule to its first occurrence at or after the given time, without running the ones in between
+func (s *ScheduledPayment) AdvanceTo(now time.Time) {
+	for s.HasOccurrence(s.NextOccurrence) && s.OccurrenceAt(s.NextOccurrence).Before(now) {
+		s.NextOccurrence++
+	}
+
+	if !s.HasOccurrence(s.NextOccurrence) {
+		s.Status = StatusCompleted
+		return
+	}
+
+	s.NextRunAt = s.OccurrenceAt(s.NextOccurrence)
+}
+
+// RunStatus is the type that represents the outcome of an occurrence
+type RunStatus string
+
+const (
+	// RunStatusExecuted is the status of an occurrence whose payment was made
+	RunStatusExecuted RunStatus = "executed"
+
+	// RunStatusFailed is the status of an occurrence whose payment was declined or could not be made
+	RunStatusFailed RunStatus = "failed"
+
+	// RunStatusSkipped is the status of an occurrence skipped by the schedule's owner
+	RunStatusSkipped RunStatus = "skipped"
+
+	// RunStatusMissed is the status of an occurrence that was not run in time, e.g. while the scheduler was down
+	RunStatusMissed RunStatus = "missed"
+)
+
+// Run is the entity that represents the outcome of one occurrence of a scheduled payment
+type Run struct {
+	ScheduleID  string    `json:"schedule_id"`
+	Occurrence  int       `json:"occurrence"`
+	ScheduledAt time.Time `json:"scheduled_at"`
+	Status      RunStatus `json:"status"`
+
+	// TransactionID is the payment made by the occurrence, if any
+	TransactionID string    `json:"transaction_id,omitempty"`
+	Error         string    `json:"error,omitempty"`
+	RanAt         time.Time `json:"ran_at"`
+}
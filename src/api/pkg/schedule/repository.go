@@ -0,0 +1,24 @@
+package schedule
+
+import "time"
+
+// Repository is the scheduled payment repository interface
+type Repository interface {
+	// Save creates or replaces a scheduled payment.
+	Save(schedule *ScheduledPayment) error
+
+	// Find gets a scheduled payment by ID.
+	Find(id string) (*ScheduledPayment, error)
+
+	// Due returns the IDs of the active schedules that should run at or before the given time.
+	Due(now time.Time, limit int) ([]string, error)
+
+	// Claim takes an occurrence so that no other run executes it.
+	Claim(id string, occurrence int) error
+
+	// Record saves the outcome of an occurrence.
+	Record(run *Run) error
+
+	// Runs returns the outcomes of a schedule's occurrences, oldest first.
+	Runs(id string) ([]*Run, error)
+}
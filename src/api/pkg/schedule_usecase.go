@@ -0,0 +1,301 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"github.com/quabynah-bilson/quantia/pkg/event"
+	"github.com/quabynah-bilson/quantia/pkg/payment"
+	"github.com/quabynah-bilson/quantia/pkg/schedule"
+	"log"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrInvalidSchedule is the error returned when a schedule definition is invalid.
+	ErrInvalidSchedule = errors.New("invalid schedule. Please check the frequency, start date, end date and count")
+
+	// ErrInvalidScheduleState is the error returned when a schedule cannot be changed in its current state.
+	ErrInvalidScheduleState = errors.New("the scheduled payment cannot be changed in its current state")
+)
+
+const (
+	// missedOccurrenceAfter is how late an occurrence may run, e.g. after the scheduler was down. Older
+	// occurrences are recorded as missed rather than charged late.
+	missedOccurrenceAfter = 24 * time.Hour
+
+	// dueBatchSize is the number of due schedules run per tick.
+	dueBatchSize = 100
+)
+
+// ScheduleUseCase is the scheduled payment use case. It contains the necessary repositories to manage
+// standing orders and runs their occurrences through the payment use case.
+type ScheduleUseCase struct {
+	scheduleRepo schedule.Repository
+	paymentRepo  payment.Repository
+	payments     *PaymentUseCase
+
+	// mu serializes the changes made to schedules by the API and the scheduler
+	mu sync.Mutex
+}
+
+// NewScheduleUseCase creates a new scheduled payment use case.
+func NewScheduleUseCase(scheduleRepo schedule.Repository, paymentRepo payment.Repository, payments *PaymentUseCase) *ScheduleUseCase {
+	return &ScheduleUseCase{
+		scheduleRepo: scheduleRepo,
+		paymentRepo:  paymentRepo,
+		payments:     payments,
+	}
+}
+
+// CreateSchedule creates a scheduled payment by the account from the given source to the merchant at the URL. The first
+// occurrence is at startAt; it ends after endAt or count occurrences when they are set.
+func (uc *ScheduleUseCase) CreateSchedule(accountID string, amount float32, url, source string, frequency schedule.Frequency, startAt time.Time, endAt *time.Time, count int) (*schedule.ScheduledPayment, error) {
+	if accountID == "" {
+		return nil, ErrInvalidAccount
+	}
+
+	if err := validateAmount(amount); err != nil {
+		log.Printf("error validating amount: %v", err)
+		return nil, err
+	}
+
+	if err := uc.payments.checkURL(url); err != nil {
+		return nil, err
+	}
+
+	if !frequency.IsValid() || !startAt.After(time.Now()) || count < 0 || (endAt != nil && endAt.Before(startAt)) {
+		return nil, ErrInvalidSchedule
+	}
+
+	s := schedule.NewScheduledPayment(accountID, amount, url, source, frequency, startAt, endAt, count)
+	if err := uc.scheduleRepo.Save(s); err != nil {
+		log.Printf("error saving schedule: %v", err)
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// GetSchedule gets a scheduled payment by ID.
+func (uc *ScheduleUseCase) GetSchedule(id string) (*schedule.ScheduledPayment, error) {
+	return uc.scheduleRepo.Find(id)
+}
+
+// GetRuns gets the outcomes of a scheduled payment's occurrences, oldest first.
+func (uc *ScheduleUseCase) GetRuns(id string) ([]*schedule.Run, error) {
+	if _, err := uc.scheduleRepo.Find(id); err != nil {
+		return nil, err
+	}
+
+	return uc.scheduleRepo.Runs(id)
+}
+
+// Skip skips the next occurrence of an active or paused schedule.
+func (uc *ScheduleUseCase) Skip(id string) (*schedule.ScheduledPayment, error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	s, err := uc.scheduleRepo.Find(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.Status != schedule.StatusActive && s.Status != schedule.StatusPaused {
+		return nil, ErrInvalidScheduleState
+	}
+
+	// claim the occurrence so that a scheduler that already picked it up does not run it
+	n := s.NextOccurrence
+	if err = uc.scheduleRepo.Claim(s.ID, n); err != nil {
+		log.Printf("error claiming occurrence %d of schedule %s: %v", n, s.ID, err)
+		return nil, ErrInvalidScheduleState
+	}
+
+	uc.record(s, n, schedule.RunStatusSkipped, "", nil)
+	s.Advance(n)
+
+	return s, uc.scheduleRepo.Save(s)
+}
+
+// Pause stops the occurrences of an active schedule until it is resumed.
+func (uc *ScheduleUseCase) Pause(id string) (*schedule.ScheduledPayment, error) {
+	return uc.transition(id, schedule.StatusActive, schedule.StatusPaused)
+}
+
+// Resume restarts a paused schedule from its next occurrence in the future. Occurrences that fell
+// while the schedule was paused are not run.
+func (uc *ScheduleUseCase) Resume(id string) (*schedule.ScheduledPayment, error) {
+	return uc.transition(id, schedule.StatusPaused, schedule.StatusActive)
+}
+
+// Cancel stops an active or paused schedule for good.
+func (uc *ScheduleUseCase) Cancel(id string) (*schedule.ScheduledPayment, error) {
+	s, err := uc.transition(id, schedule.StatusActive, schedule.StatusCancelled)
+	if errors.Is(err, ErrInvalidScheduleState) {
+		return uc.transition(id, schedule.StatusPaused, schedule.StatusCancelled)
+	}
+
+	return s, err
+}
+
+// Run runs the due occurrences every interval until the context is cancelled.
+func (uc *ScheduleUseCase) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		uc.RunDue(time.Now().UTC())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunDue runs the occurrences due at or before now and returns how many schedules were processed.
+// Each occurrence is claimed before its payment is made, so it runs at most once even if the
+// scheduler restarts or several schedulers share the store.
+func (uc *ScheduleUseCase) RunDue(now time.Time) int {
+	ids, err := uc.scheduleRepo.Due(now, dueBatchSize)
+	if err != nil {
+		log.Printf("error getting due schedules: %v", err)
+		return 0
+	}
+
+	for _, id := range ids {
+		uc.runOccurrence(id, now)
+	}
+
+	return len(ids)
+}
+
+// runOccurrence runs the next occurrence of a due schedule and moves the schedule past it.
+func (uc *ScheduleUseCase) runOccurrence(id string, now time.Time) {
+	s, err := uc.scheduleRepo.Find(id)
+	if err != nil {
+		log.Printf("error finding schedule %s: %v", id, err)
+		return
+	}
+
+	if s.Status != schedule.StatusActive || s.NextRunAt.After(now) {
+		return
+	}
+
+	n := s.NextOccurrence
+	if err = uc.scheduleRepo.Claim(s.ID, n); errors.Is(err, schedule.ErrOccurrenceAlreadyClaimed) {
+		// another run has the occurrence: it was skipped, or a previous run stopped before advancing the schedule
+		log.Printf("occurrence %d of schedule %s already claimed", n, s.ID)
+		uc.advance(s.ID, n)
+		return
+	} else if err != nil {
+		// the occurrence did not run, so the next tick tries it again
+		log.Printf("error claiming occurrence %d of schedule %s: %v", n, s.ID, err)
+		return
+	}
+
+	if now.Sub(s.OccurrenceAt(n)) > missedOccurrenceAfter {
+		uc.record(s, n, schedule.RunStatusMissed, "", errors.New("the occurrence was not run in time"))
+		uc.advance(s.ID, n)
+		return
+	}
+
+	// scheduled payments have no request, so the fraud checks see no device or IP address
+	transaction, err := uc.payments.MakePayment(s.Amount, s.Url, s.Source, nil)
+	transactionID := ""
+	if transaction != nil {
+		transactionID = transaction.ID
+	}
+
+	if err != nil {
+		uc.record(s, n, schedule.RunStatusFailed, transactionID, err)
+	} else {
+		uc.record(s, n, schedule.RunStatusExecuted, transactionID, nil)
+	}
+
+	uc.advance(s.ID, n)
+}
+
+// advance moves a schedule past the nth occurrence, unless it has already moved on.
+func (uc *ScheduleUseCase) advance(id string, n int) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	s, err := uc.scheduleRepo.Find(id)
+	if err != nil || s.NextOccurrence != n {
+		return
+	}
+
+	s.Advance(n)
+	if err = uc.scheduleRepo.Save(s); err != nil {
+		log.Printf("error saving schedule %s: %v", s.ID, err)
+	}
+}
+
+// transition moves a schedule from one status to another.
+func (uc *ScheduleUseCase) transition(id string, from, to schedule.Status) (*schedule.ScheduledPayment, error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	s, err := uc.scheduleRepo.Find(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.Status != from {
+		return nil, ErrInvalidScheduleState
+	}
+
+	s.Status = to
+	if to == schedule.StatusActive {
+		s.AdvanceTo(time.Now().UTC())
+	}
+
+	if err = uc.scheduleRepo.Save(s); err != nil {
+		log.Printf("error saving schedule %s: %v", s.ID, err)
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// record saves the outcome of an occurrence and notifies the merchant when it did not result in a payment.
+func (uc *ScheduleUseCase) record(s *schedule.ScheduledPayment, n int, status schedule.RunStatus, transactionID string, cause error) {
+	run := &schedule.Run{
+		ScheduleID:    s.ID,
+		Occurrence:    n,
+		ScheduledAt:   s.OccurrenceAt(n),
+		Status:        status,
+		TransactionID: transactionID,
+		RanAt:         time.Now().UTC(),
+	}
+	if cause != nil {
+		run.Error = cause.Error()
+	}
+
+	if err := uc.scheduleRepo.Record(run); err != nil {
+		log.Printf("error recording occurrence %d of schedule %s: %v", n, s.ID, err)
+	}
+
+	if status != schedule.RunStatusFailed && status != schedule.RunStatusMissed {
+		return
+	}
+
+	envelope, err := event.New(event.TypeScheduledPaymentFailed, &event.ScheduledPaymentData{
+		ScheduleID:    s.ID,
+		Occurrence:    n,
+		ScheduledAt:   run.ScheduledAt,
+		TransactionID: transactionID,
+		Reason:        run.Error,
+	})
+	if err != nil {
+		log.Printf("error creating %s event: %v", event.TypeScheduledPaymentFailed, err)
+		return
+	}
+
+	if err = uc.paymentRepo.Notify(s.Url, envelope); err != nil {
+		log.Printf("error queueing %s event for schedule %s: %v", event.TypeScheduledPaymentFailed, s.ID, err)
+	}
+}
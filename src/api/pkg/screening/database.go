@@ -0,0 +1,26 @@
+package screening
+
+import "errors"
+
+var (
+	// ErrScreeningNotFound is the error returned when a screening cannot be found
+	ErrScreeningNotFound = errors.New("screening not found")
+
+	// ErrFailedToSaveScreening is the error returned when a screening cannot be saved
+	ErrFailedToSaveScreening = errors.New("failed to save screening")
+)
+
+// Database is the interface that wraps the basic screening database operations.
+type Database interface {
+	// SaveScreening saves a screening as the latest of its party, adding it to the review queue while it awaits review
+	SaveScreening(screening *Screening) error
+
+	// GetScreening gets a screening by ID
+	GetScreening(id string) (*Screening, error)
+
+	// GetPartyScreening gets the latest screening of a party
+	GetPartyScreening(partyType PartyType, partyID string) (*Screening, error)
+
+	// GetReviewQueue gets the screenings awaiting review, oldest first
+	GetReviewQueue() ([]*Screening, error)
+}
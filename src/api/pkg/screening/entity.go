@@ -0,0 +1,120 @@
+package screening
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"time"
+)
+
+var (
+	// ErrScreeningPending is the error returned when a party has possible sanctions matches waiting for review
+	ErrScreeningPending = errors.New("sanctions screening is under review. Transactions are not allowed until it is cleared")
+
+	// ErrSanctionsMatch is the error returned when a party has been confirmed as a sanctions match
+	ErrSanctionsMatch = errors.New("party matches a sanctions list. Transactions are not allowed")
+)
+
+// screeningIDPrefix distinguishes screening IDs from other IDs
+const screeningIDPrefix = "scr_"
+
+// PartyType is the type that represents who was screened
+type PartyType string
+
+const (
+	// PartyAccount is a customer screened when registering
+	PartyAccount PartyType = "account"
+
+	// PartyBeneficiary is a payee screened when it is added
+	PartyBeneficiary PartyType = "beneficiary"
+)
+
+// Status is the type that represents the outcome of a screening
+type Status string
+
+const (
+	// StatusClear is the status of a screening without hits
+	StatusClear Status = "clear"
+
+	// StatusPendingReview is the status of a screening with hits an analyst has not looked at yet
+	StatusPendingReview Status = "pending_review"
+
+	// StatusDismissed is the status of a screening whose hits an analyst found to be false positives
+	StatusDismissed Status = "dismissed"
+
+	// StatusConfirmed is the status of a screening whose party an analyst confirmed as sanctioned
+	StatusConfirmed Status = "confirmed"
+)
+
+// Entry is the entity that represents a sanctioned individual or organisation on a list
+type Entry struct {
+	// ID is the entry's identifier in its list (e.g. the OFAC UID or the UN reference number)
+	ID      string   `json:"id"`
+	List    string   `json:"list"`
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases,omitempty"`
+	Type    string   `json:"type,omitempty"`
+	Program string   `json:"program,omitempty"`
+}
+
+// Hit is the entity that represents a list entry whose name resembles the screened name
+type Hit struct {
+	EntryID     string  `json:"entry_id"`
+	List        string  `json:"list"`
+	MatchedName string  `json:"matched_name"`
+	Score       float64 `json:"score"`
+	Program     string  `json:"program,omitempty"`
+}
+
+// Screening is the entity that represents a party checked against the sanctions lists
+type Screening struct {
+	ID        string    `json:"id"`
+	PartyType PartyType `json:"party_type"`
+	PartyID   string    `json:"party_id"`
+
+	// AccountID is the account the party belongs to: the account itself, or the owner of the beneficiary
+	AccountID string `json:"account_id"`
+	Name      string `json:"name"`
+	Status    Status `json:"status"`
+	Hits      []Hit  `json:"hits,omitempty"`
+
+	Reviewer   string     `json:"reviewer,omitempty"`
+	ReviewNote string     `json:"review_note,omitempty"`
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// NewScreening records the hits found for a party, holding it for review when there are any
+func NewScreening(partyType PartyType, partyID, accountID, name string, hits []Hit) *Screening {
+	status := StatusClear
+	if len(hits) > 0 {
+		status = StatusPendingReview
+	}
+
+	return &Screening{
+		ID:        screeningIDPrefix + uuid.NewString(),
+		PartyType: partyType,
+		PartyID:   partyID,
+		AccountID: accountID,
+		Name:      name,
+		Status:    status,
+		Hits:      hits,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+// IsAwaitingReview reports whether the screening is in the review queue
+func (s *Screening) IsAwaitingReview() bool {
+	return s.Status == StatusPendingReview
+}
+
+// Authorize returns why the screened party may not transact, nil when it may
+func (s *Screening) Authorize() error {
+	switch s.Status {
+	case StatusPendingReview:
+		return ErrScreeningPending
+	case StatusConfirmed:
+		return ErrSanctionsMatch
+	default:
+		return nil
+	}
+}
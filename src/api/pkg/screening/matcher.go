@@ -0,0 +1,116 @@
+package screening
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// NameScore returns how similar two names are, from 0 (nothing in common) to 1 (the same name). Names are
+// compared ignoring case, punctuation and word order, and a name may leave out some of the other's words
+// (e.g. a middle name), so the score is the best of the whole-name and the word-by-word similarities.
+func NameScore(a, b string) float64 {
+	aTokens, bTokens := tokens(a), tokens(b)
+	if len(aTokens) == 0 || len(bTokens) == 0 {
+		return 0
+	}
+
+	whole := jaroWinkler(strings.Join(sorted(aTokens), " "), strings.Join(sorted(bTokens), " "))
+	return max(whole, tokenScore(aTokens, bTokens))
+}
+
+// tokenScore pairs every word of the shorter name with its most similar word in the longer name and
+// averages the similarities. Single-word names are not matched word by word, since any one word of a
+// listed name would otherwise match them.
+func tokenScore(a, b []string) float64 {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	if len(a) < 2 {
+		return 0
+	}
+
+	var total float64
+	used := make([]bool, len(b))
+	for _, token := range a {
+		best, bestIndex := 0.0, -1
+		for i, candidate := range b {
+			if used[i] {
+				continue
+			}
+			if score := jaroWinkler(token, candidate); score > best {
+				best, bestIndex = score, i
+			}
+		}
+		if bestIndex >= 0 {
+			used[bestIndex] = true
+		}
+		total += best
+	}
+
+	return total / float64(len(a))
+}
+
+// tokens splits a name into lower case words without punctuation
+func tokens(name string) []string {
+	return strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// sorted returns a sorted copy of the words
+func sorted(words []string) []string {
+	words = append([]string(nil), words...)
+	sort.Strings(words)
+	return words
+}
+
+// jaroWinkler returns the Jaro-Winkler similarity of two strings, which favours strings sharing a prefix
+func jaroWinkler(a, b string) float64 {
+	s, t := []rune(a), []rune(b)
+	if len(s) == 0 || len(t) == 0 {
+		return 0
+	}
+
+	window := max(len(s), len(t))/2 - 1
+	window = max(window, 0)
+
+	sMatched, tMatched := make([]bool, len(s)), make([]bool, len(t))
+	matches := 0
+	for i := range s {
+		for j := max(0, i-window); j < min(len(t), i+window+1); j++ {
+			if !tMatched[j] && s[i] == t[j] {
+				sMatched[i], tMatched[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	transpositions, j := 0, 0
+	for i := range s {
+		if !sMatched[i] {
+			continue
+		}
+		for !tMatched[j] {
+			j++
+		}
+		if s[i] != t[j] {
+			transpositions++
+		}
+		j++
+	}
+
+	m := float64(matches)
+	jaro := (m/float64(len(s)) + m/float64(len(t)) + (m-float64(transpositions)/2)/m) / 3
+
+	prefix := 0
+	for prefix < min(4, len(s), len(t)) && s[prefix] == t[prefix] {
+		prefix++
+	}
+
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
@@ -0,0 +1,16 @@
+package screening
+
+// Repository is the screening repository interface
+type Repository interface {
+	// Save saves a screening as the latest of its party.
+	Save(screening *Screening) error
+
+	// Find returns a screening by ID.
+	Find(id string) (*Screening, error)
+
+	// FindByParty returns the latest screening of a party.
+	FindByParty(partyType PartyType, partyID string) (*Screening, error)
+
+	// ReviewQueue returns the screenings awaiting review, oldest first.
+	ReviewQueue() ([]*Screening, error)
+}
@@ -0,0 +1,67 @@
+package screening
+
+import "sort"
+
+// Config is the entity that represents how similar a name must be to a listed name to be a hit
+type Config struct {
+	// Threshold is the lowest score of a hit, between 0 and 1
+	Threshold float64 `json:"threshold"`
+
+	// ListThresholds override the threshold of some lists, by list name
+	ListThresholds map[string]float64 `json:"list_thresholds,omitempty"`
+}
+
+// DefaultThreshold is the lowest score of a hit when none is configured
+const DefaultThreshold = 0.9
+
+// threshold returns the lowest score of a hit on the list
+func (c Config) threshold(list string) float64 {
+	if threshold, ok := c.ListThresholds[list]; ok {
+		return threshold
+	}
+	if c.Threshold > 0 {
+		return c.Threshold
+	}
+	return DefaultThreshold
+}
+
+// Watchlist is the entity that represents the entries of the loaded sanctions lists
+type Watchlist struct {
+	entries []Entry
+	config  Config
+}
+
+// NewWatchlist creates a watchlist of the entries of every list
+func NewWatchlist(config Config, lists ...[]Entry) *Watchlist {
+	w := &Watchlist{config: config}
+	for _, entries := range lists {
+		w.entries = append(w.entries, entries...)
+	}
+
+	return w
+}
+
+// Len returns the number of listed entries
+func (w *Watchlist) Len() int {
+	return len(w.entries)
+}
+
+// Screen returns the entries whose name or aliases resemble the name, most similar first
+func (w *Watchlist) Screen(name string) []Hit {
+	var hits []Hit
+	for _, entry := range w.entries {
+		best, matched := 0.0, ""
+		for _, listed := range append([]string{entry.Name}, entry.Aliases...) {
+			if score := NameScore(name, listed); score > best {
+				best, matched = score, listed
+			}
+		}
+
+		if best >= w.config.threshold(entry.List) {
+			hits = append(hits, Hit{EntryID: entry.ID, List: entry.List, MatchedName: matched, Score: best, Program: entry.Program})
+		}
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].Score > hits[j].Score })
+	return hits
+}
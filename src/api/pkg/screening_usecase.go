@@ -0,0 +1,120 @@
+package pkg
+
+import (
+	"errors"
+	"github.com/quabynah-bilson/quantia/pkg/beneficiary"
+	"github.com/quabynah-bilson/quantia/pkg/screening"
+	"log"
+	"sync"
+	"time"
+)
+
+// ErrScreeningNotAwaitingReview is the error returned when a decision is made on a screening that is not in the review queue.
+var ErrScreeningNotAwaitingReview = errors.New("the screening is not awaiting review")
+
+// ScreeningUseCase is the sanctions screening use case. It contains the necessary repositories to screen
+// customers and beneficiaries against the loaded lists and to hold the parties with hits until they are reviewed.
+type ScreeningUseCase struct {
+	screeningRepo screening.Repository
+	watchlist     *screening.Watchlist
+
+	// reviewMu serializes the decisions of analysts
+	reviewMu sync.Mutex
+}
+
+// NewScreeningUseCase creates a new screening use case.
+func NewScreeningUseCase(screeningRepo screening.Repository, watchlist *screening.Watchlist) *ScreeningUseCase {
+	return &ScreeningUseCase{
+		screeningRepo: screeningRepo,
+		watchlist:     watchlist,
+	}
+}
+
+// ScreenAccount screens a newly registered customer by their full name.
+func (uc *ScreeningUseCase) ScreenAccount(accountID, name string) (*screening.Screening, error) {
+	return uc.screen(screening.PartyAccount, accountID, accountID, name)
+}
+
+// ScreenBeneficiary screens a new beneficiary by the name its destination confirmed.
+func (uc *ScreeningUseCase) ScreenBeneficiary(b *beneficiary.Beneficiary) (*screening.Screening, error) {
+	return uc.screen(screening.PartyBeneficiary, b.ID, b.AccountID, b.Name)
+}
+
+// Authorize returns why a party may not transact: its hits are waiting for review, or it was confirmed as a
+// match. Parties that were never screened (e.g. registered before screening was enabled) may transact.
+func (uc *ScreeningUseCase) Authorize(partyType screening.PartyType, partyID string) error {
+	s, err := uc.screeningRepo.FindByParty(partyType, partyID)
+	if errors.Is(err, screening.ErrScreeningNotFound) {
+		return nil
+	}
+	if err != nil {
+		log.Printf("error finding screening of %s %s: %v", partyType, partyID, err)
+		return err
+	}
+
+	return s.Authorize()
+}
+
+// GetScreening gets a screening by ID.
+func (uc *ScreeningUseCase) GetScreening(id string) (*screening.Screening, error) {
+	return uc.screeningRepo.Find(id)
+}
+
+// GetPartyScreening gets the latest screening of a party.
+func (uc *ScreeningUseCase) GetPartyScreening(partyType screening.PartyType, partyID string) (*screening.Screening, error) {
+	return uc.screeningRepo.FindByParty(partyType, partyID)
+}
+
+// GetReviewQueue gets the screenings with hits waiting for review, oldest first.
+func (uc *ScreeningUseCase) GetReviewQueue() ([]*screening.Screening, error) {
+	return uc.screeningRepo.ReviewQueue()
+}
+
+// Review records an analyst's decision on the hits of a screening: confirmed parties may never transact,
+// dismissed hits are false positives and release the party.
+func (uc *ScreeningUseCase) Review(id string, confirm bool, reviewer, note string) (*screening.Screening, error) {
+	if reviewer == "" {
+		return nil, ErrInvalidReview
+	}
+
+	uc.reviewMu.Lock()
+	defer uc.reviewMu.Unlock()
+
+	s, err := uc.screeningRepo.Find(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.IsAwaitingReview() {
+		return nil, ErrScreeningNotAwaitingReview
+	}
+
+	now := time.Now().UTC()
+	s.Status = screening.StatusDismissed
+	if confirm {
+		s.Status = screening.StatusConfirmed
+	}
+	s.Reviewer, s.ReviewNote, s.ReviewedAt = reviewer, note, &now
+
+	if err = uc.screeningRepo.Save(s); err != nil {
+		log.Printf("error saving review of screening %s: %v", id, err)
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// screen checks a name against the lists and records the outcome for the party.
+func (uc *ScreeningUseCase) screen(partyType screening.PartyType, partyID, accountID, name string) (*screening.Screening, error) {
+	s := screening.NewScreening(partyType, partyID, accountID, name, uc.watchlist.Screen(name))
+	if err := uc.screeningRepo.Save(s); err != nil {
+		log.Printf("error saving screening of %s %s: %v", partyType, partyID, err)
+		return nil, err
+	}
+
+	if s.IsAwaitingReview() {
+		log.Printf("%s %s held for review with %d sanctions hits", partyType, partyID, len(s.Hits))
+	}
+
+	return s, nil
+}
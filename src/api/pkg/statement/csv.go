@@ -0,0 +1,39 @@
+package statement
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+)
+
+// WriteCSV writes a statement as a CSV file with a header row. The opening balance comes first, then each
+// entry with the balance after it, then the closing balance.
+func WriteCSV(w io.Writer, s *Statement) error {
+	writer := csv.NewWriter(w)
+	rows := [][]string{
+		{"date", "reference", "description", "debit", "credit", "balance"},
+		{s.From.Format(DateLayout), "", "Opening balance", "", "", formatAmount(s.OpeningBalance)},
+	}
+
+	for _, line := range s.Lines {
+		debit, credit := "", formatAmount(line.Amount)
+		if line.Amount < 0 {
+			debit, credit = formatAmount(-line.Amount), ""
+		}
+
+		rows = append(rows, []string{line.Date.Format(time.RFC3339), line.Reference, line.Description, debit, credit, formatAmount(line.Balance)})
+	}
+
+	rows = append(rows, []string{s.LastDay().Format(DateLayout), "", "Closing balance", formatAmount(s.Debits), formatAmount(s.Credits), formatAmount(s.ClosingBalance)})
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+
+	return writer.Error()
+}
+
+// formatAmount formats an amount with two decimal places
+func formatAmount(amount float32) string {
+	return strconv.FormatFloat(float64(amount), 'f', 2, 32)
+}
@@ -0,0 +1,30 @@
+package statement
+
+import "errors"
+
+var (
+	// ErrStatementNotFound is the error returned when a statement does not exist
+	ErrStatementNotFound = errors.New("statement not found")
+
+	// ErrFailedToSaveStatement is the error returned when a statement cannot be stored
+	ErrFailedToSaveStatement = errors.New("failed to save statement. Please try again")
+
+	// ErrStatementAlreadyGenerated is the error returned when the statement of an account for a month is stored twice
+	ErrStatementAlreadyGenerated = errors.New("statement already generated for this period")
+)
+
+// Database is the interface that wraps the basic statement database operations.
+type Database interface {
+	// SaveStatement stores a new monthly statement, indexed by its account and period. It fails with
+	// ErrStatementAlreadyGenerated when the account already has a statement for the period.
+	SaveStatement(statement *Statement) error
+
+	// GetStatement gets a statement by ID
+	GetStatement(id string) (*Statement, error)
+
+	// GetPeriodStatement gets the statement of an account for a period (YYYY-MM)
+	GetPeriodStatement(accountID, period string) (*Statement, error)
+
+	// GetAccountStatements gets the statements of an account, oldest period first
+	GetAccountStatements(accountID string) ([]*Statement, error)
+}
@@ -0,0 +1,225 @@
+package statement
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"github.com/quabynah-bilson/quantia/pkg/ledger"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// DateLayout is the layout of the dates bounding a statement
+	DateLayout = "2006-01-02"
+
+	// PeriodLayout is the layout of the month covered by a monthly statement
+	PeriodLayout = "2006-01"
+)
+
+// ErrUnsupportedFormat is the error returned when a statement is asked for in a format we cannot write
+var ErrUnsupportedFormat = errors.New("unsupported statement format. Please ask for csv, ofx or pdf")
+
+// Format is the type that represents the file format a statement is exported in
+type Format string
+
+const (
+	// FormatCSV is a spreadsheet of the entries between the opening and closing balances
+	FormatCSV Format = "csv"
+
+	// FormatOFX is an Open Financial Exchange 2 bank statement, read by accounting software
+	FormatOFX Format = "ofx"
+
+	// FormatPDF is a printable document, for customers and auditors
+	FormatPDF Format = "pdf"
+)
+
+// ParseFormat reads a format name, ignoring case
+func ParseFormat(name string) (Format, error) {
+	format := Format(strings.ToLower(strings.TrimSpace(name)))
+	switch format {
+	case FormatCSV, FormatOFX, FormatPDF:
+		return format, nil
+	default:
+		return "", ErrUnsupportedFormat
+	}
+}
+
+// ContentType returns the media type of files of the format
+func (f Format) ContentType() string {
+	switch f {
+	case FormatOFX:
+		return "application/x-ofx"
+	case FormatPDF:
+		return "application/pdf"
+	default:
+		return "text/csv"
+	}
+}
+
+// Write writes a statement in a format
+func Write(w io.Writer, s *Statement, format Format, institution string) error {
+	switch format {
+	case FormatCSV:
+		return WriteCSV(w, s)
+	case FormatOFX:
+		return WriteOFX(w, s, institution)
+	case FormatPDF:
+		return WritePDF(w, s, institution)
+	default:
+		return ErrUnsupportedFormat
+	}
+}
+
+// Line is an entry of the account within the statement's period
+type Line struct {
+	EntryID     string           `json:"entry_id"`
+	Date        time.Time        `json:"date"`
+	Reference   string           `json:"reference"`
+	Description string           `json:"description"`
+	Type        ledger.EntryType `json:"type"`
+
+	// Amount is positive for credits and negative for debits
+	Amount float32 `json:"amount"`
+
+	// Balance is the balance of the account after the entry
+	Balance float32 `json:"balance"`
+}
+
+// Statement is the entity that represents the entries of an account over a period, between its opening and
+// closing balances. Statements are generated on demand, or every month and stored for later download.
+type Statement struct {
+	ID        string `json:"id"`
+	AccountID string `json:"account_id"`
+
+	// Period is the month of a monthly statement (YYYY-MM). It is empty for statements generated on demand.
+	Period   string `json:"period,omitempty"`
+	Currency string `json:"currency,omitempty"`
+
+	// From is the start of the statement; To is its end, excluded
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+
+	OpeningBalance float32 `json:"opening_balance"`
+	Credits        float32 `json:"credits"`
+	Debits         float32 `json:"debits"`
+	ClosingBalance float32 `json:"closing_balance"`
+
+	// Lines is left out of the summaries of stored statements
+	Lines       []Line    `json:"lines,omitempty"`
+	GeneratedAt time.Time `json:"generated_at"`
+}
+
+// New builds the statement of an account from its entries between from and to (excluded). Entries before
+// from make up the opening balance; entries from to onwards are left out.
+func New(accountID, currency string, from, to time.Time, entries []*ledger.Entry) *Statement {
+	entries = append([]*ledger.Entry(nil), entries...)
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].CreatedAt.Before(entries[j].CreatedAt) })
+
+	s := &Statement{
+		ID:          "stmt_" + uuid.NewString(),
+		AccountID:   accountID,
+		Currency:    currency,
+		From:        from.UTC(),
+		To:          to.UTC(),
+		Lines:       []Line{},
+		GeneratedAt: time.Now().UTC(),
+	}
+
+	// balances are summed in cents so that long statements do not drift
+	var opening, credits, debits int64
+	for _, entry := range entries {
+		if !entry.CreatedAt.Before(to) {
+			continue
+		}
+
+		amount := toMinorUnits(entry.Amount)
+		if entry.Type == ledger.EntryTypeDebit {
+			amount = -amount
+		}
+
+		if entry.CreatedAt.Before(from) {
+			opening += amount
+			continue
+		}
+
+		if amount < 0 {
+			debits -= amount
+		} else {
+			credits += amount
+		}
+
+		s.Lines = append(s.Lines, Line{
+			EntryID:     entry.ID,
+			Date:        entry.CreatedAt.UTC(),
+			Reference:   entry.Reference,
+			Description: entry.Description,
+			Type:        entry.Type,
+			Amount:      fromMinorUnits(amount),
+			Balance:     fromMinorUnits(opening + credits - debits),
+		})
+	}
+
+	s.OpeningBalance = fromMinorUnits(opening)
+	s.Credits = fromMinorUnits(credits)
+	s.Debits = fromMinorUnits(debits)
+	s.ClosingBalance = fromMinorUnits(opening + credits - debits)
+	return s
+}
+
+// NewMonthly builds the statement of an account for a month (YYYY-MM)
+func NewMonthly(accountID, currency, period string, entries []*ledger.Entry) (*Statement, error) {
+	from, to, err := ParsePeriod(period)
+	if err != nil {
+		return nil, err
+	}
+
+	s := New(accountID, currency, from, to, entries)
+	s.Period = period
+	return s, nil
+}
+
+// ParsePeriod returns the start and end (excluded) of a month (YYYY-MM)
+func ParsePeriod(period string) (time.Time, time.Time, error) {
+	from, err := time.Parse(PeriodLayout, period)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	return from, from.AddDate(0, 1, 0), nil
+}
+
+// PreviousPeriod returns the month (YYYY-MM) before the one of the given time
+func PreviousPeriod(now time.Time) string {
+	now = now.UTC()
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -1, 0).Format(PeriodLayout)
+}
+
+// LastDay returns the last day covered by the statement
+func (s *Statement) LastDay() time.Time {
+	return s.To.Add(-time.Nanosecond)
+}
+
+// FileName returns the name of the statement's file in a format, e.g. statement-2026-10-01-2026-10-31.pdf
+func (s *Statement) FileName(format Format) string {
+	return "statement-" + s.From.Format(DateLayout) + "-" + s.LastDay().Format(DateLayout) + "." + string(format)
+}
+
+// Summary returns a copy of the statement without its lines
+func (s *Statement) Summary() *Statement {
+	summary := *s
+	summary.Lines = nil
+	return &summary
+}
+
+// toMinorUnits converts an amount to cents
+func toMinorUnits(amount float32) int64 {
+	return int64(math.Round(float64(amount) * 100))
+}
+
+// fromMinorUnits converts cents to an amount
+func fromMinorUnits(amount int64) float32 {
+	return float32(amount) / 100
+}
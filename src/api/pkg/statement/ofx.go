@@ -0,0 +1,139 @@
+package statement
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+const (
+	// ofxHeader is the header of OFX 2.2 files, before the OFX element
+	ofxHeader = xml.Header + `<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>` + "\n"
+
+	// ofxDateTimeLayout is the layout of OFX dates and times, in UTC
+	ofxDateTimeLayout = "20060102150405"
+
+	// ofxNameLength is the longest payee name OFX allows
+	ofxNameLength = 32
+)
+
+// ofxDocument is the OFX element of a bank statement download
+type ofxDocument struct {
+	XMLName xml.Name `xml:"OFX"`
+	SignOn  struct {
+		Response struct {
+			Status      ofxStatus `xml:"STATUS"`
+			ServerTime  string    `xml:"DTSERVER"`
+			Language    string    `xml:"LANGUAGE"`
+			Institution struct {
+				Organization string `xml:"ORG"`
+			} `xml:"FI"`
+		} `xml:"SONRS"`
+	} `xml:"SIGNONMSGSRSV1"`
+	Bank struct {
+		Transaction struct {
+			ID        string       `xml:"TRNUID"`
+			Status    ofxStatus    `xml:"STATUS"`
+			Statement ofxStatement `xml:"STMTRS"`
+		} `xml:"STMTTRNRS"`
+	} `xml:"BANKMSGSRSV1"`
+}
+
+// ofxStatus is the outcome of an OFX request
+type ofxStatus struct {
+	Code     int    `xml:"CODE"`
+	Severity string `xml:"SEVERITY"`
+}
+
+// ofxStatement is the STMTRS element: the account, its transactions and its closing balance
+type ofxStatement struct {
+	Currency string `xml:"CURDEF"`
+	Account  struct {
+		BankID    string `xml:"BANKID"`
+		AccountID string `xml:"ACCTID"`
+		Type      string `xml:"ACCTTYPE"`
+	} `xml:"BANKACCTFROM"`
+	Transactions struct {
+		Start   string           `xml:"DTSTART"`
+		End     string           `xml:"DTEND"`
+		Entries []ofxTransaction `xml:"STMTTRN"`
+	} `xml:"BANKTRANLIST"`
+	LedgerBalance struct {
+		Amount string `xml:"BALAMT"`
+		AsOf   string `xml:"DTASOF"`
+	} `xml:"LEDGERBAL"`
+}
+
+// ofxTransaction is a STMTTRN element. FITID identifies the transaction so that imports skip the ones seen before.
+type ofxTransaction struct {
+	Type   string `xml:"TRNTYPE"`
+	Posted string `xml:"DTPOSTED"`
+	Amount string `xml:"TRNAMT"`
+	ID     string `xml:"FITID"`
+	Name   string `xml:"NAME,omitempty"`
+	Memo   string `xml:"MEMO,omitempty"`
+}
+
+// WriteOFX writes a statement as an OFX 2.2 bank statement of the institution. Statements without a currency
+// are written in XXX, the ISO 4217 code for no currency.
+func WriteOFX(w io.Writer, s *Statement, institution string) error {
+	var doc ofxDocument
+	response := &doc.SignOn.Response
+	response.Status = ofxStatus{Code: 0, Severity: "INFO"}
+	response.ServerTime = s.GeneratedAt.Format(ofxDateTimeLayout)
+	response.Language = "ENG"
+	response.Institution.Organization = institution
+
+	doc.Bank.Transaction.ID = s.ID
+	doc.Bank.Transaction.Status = ofxStatus{Code: 0, Severity: "INFO"}
+
+	stmt := &doc.Bank.Transaction.Statement
+	stmt.Currency = s.Currency
+	if stmt.Currency == "" {
+		stmt.Currency = "XXX"
+	}
+	stmt.Account.BankID = institution
+	stmt.Account.AccountID = s.AccountID
+	stmt.Account.Type = "CHECKING"
+	stmt.Transactions.Start = s.From.Format(ofxDateTimeLayout)
+	stmt.Transactions.End = s.To.Format(ofxDateTimeLayout)
+	for _, line := range s.Lines {
+		transactionType := "CREDIT"
+		if line.Amount < 0 {
+			transactionType = "DEBIT"
+		}
+
+		stmt.Transactions.Entries = append(stmt.Transactions.Entries, ofxTransaction{
+			Type:   transactionType,
+			Posted: line.Date.UTC().Format(ofxDateTimeLayout),
+			Amount: formatAmount(line.Amount),
+			ID:     line.EntryID,
+			Name:   truncate(line.Description, ofxNameLength),
+			Memo:   line.Reference,
+		})
+	}
+	stmt.LedgerBalance.Amount = formatAmount(s.ClosingBalance)
+	stmt.LedgerBalance.AsOf = s.To.Format(ofxDateTimeLayout)
+
+	if _, err := io.WriteString(w, ofxHeader); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(&doc); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// truncate shortens a text to at most n characters
+func truncate(text string, n int) string {
+	runes := []rune(text)
+	if len(runes) <= n {
+		return text
+	}
+
+	return string(runes[:n])
+}
@@ -0,0 +1,210 @@
+package statement
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// The PDF is laid out on A4 pages, in points. The entries are set in Courier so that amounts can be aligned
+// on the right without font metrics.
+const (
+	pdfPageWidth  = 595
+	pdfPageHeight = 842
+	pdfMargin     = 50
+	pdfFontSize   = 8
+	pdfLineHeight = 12
+
+	// pdfCharWidth is the width of a Courier character at pdfFontSize
+	pdfCharWidth = 0.6 * pdfFontSize
+)
+
+// pdfColumn is a column of the table of entries. Amounts are aligned on the right of the column.
+type pdfColumn struct {
+	title string
+	x     float64
+	width int
+	right bool
+}
+
+// pdfColumns are the columns of the table of entries
+var pdfColumns = []pdfColumn{
+	{title: "Date", x: pdfMargin, width: 16},
+	{title: "Reference", x: 135, width: 18},
+	{title: "Description", x: 228, width: 28},
+	{title: "Debit", x: 430, width: 12, right: true},
+	{title: "Credit", x: 490, width: 12, right: true},
+	{title: "Balance", x: pdfPageWidth - pdfMargin, width: 12, right: true},
+}
+
+// pdfPage is the content stream of a page
+type pdfPage struct {
+	content bytes.Buffer
+}
+
+// WritePDF writes a statement as a printable PDF document of the institution: a summary of the balances on
+// the first page, then the entries, continued on as many pages as needed.
+func WritePDF(w io.Writer, s *Statement, institution string) error {
+	var pages []*pdfPage
+	var page *pdfPage
+	y := 0.0
+
+	// newPage starts a page with the header row of the table
+	newPage := func() {
+		page = &pdfPage{}
+		pages = append(pages, page)
+		y = pdfPageHeight - pdfMargin
+		if len(pages) == 1 {
+			y = page.summary(s, institution, y)
+		}
+
+		for _, column := range pdfColumns {
+			page.cell(column, y, "F2", column.title)
+		}
+		y -= 4
+		page.rule(y)
+		y -= pdfLineHeight
+	}
+
+	newPage()
+	rows := make([][]string, 0, len(s.Lines)+2)
+	rows = append(rows, []string{s.From.Format(DateLayout), "", "Opening balance", "", "", formatAmount(s.OpeningBalance)})
+	for _, line := range s.Lines {
+		debit, credit := "", formatAmount(line.Amount)
+		if line.Amount < 0 {
+			debit, credit = formatAmount(-line.Amount), ""
+		}
+		rows = append(rows, []string{line.Date.Format("2006-01-02 15:04"), line.Reference, line.Description, debit, credit, formatAmount(line.Balance)})
+	}
+	rows = append(rows, []string{s.LastDay().Format(DateLayout), "", "Closing balance", formatAmount(s.Debits), formatAmount(s.Credits), formatAmount(s.ClosingBalance)})
+
+	for _, row := range rows {
+		if y < pdfMargin+pdfLineHeight {
+			newPage()
+		}
+
+		for i, column := range pdfColumns {
+			page.cell(column, y, "F1", row[i])
+		}
+		y -= pdfLineHeight
+	}
+
+	for i, p := range pages {
+		footer := fmt.Sprintf("%s - page %d of %d", s.AccountID, i+1, len(pages))
+		p.text("F1", pdfFontSize, pdfMargin, pdfMargin/2, footer)
+	}
+
+	return writePDFDocument(w, pages)
+}
+
+// summary writes the title of the statement and its balances from the top of the page, and returns where
+// the table of entries starts
+func (p *pdfPage) summary(s *Statement, institution string, y float64) float64 {
+	p.text("F2", 16, pdfMargin, y, institution+" account statement")
+	y -= 28
+
+	currency := s.Currency
+	if currency == "" {
+		currency = "-"
+	}
+
+	for _, detail := range [][2]string{
+		{"Account", s.AccountID},
+		{"Period", s.From.Format(DateLayout) + " to " + s.LastDay().Format(DateLayout)},
+		{"Currency", currency},
+		{"Generated", s.GeneratedAt.Format(time.RFC1123)},
+		{"", ""},
+		{"Opening balance", formatAmount(s.OpeningBalance)},
+		{"Credits", formatAmount(s.Credits)},
+		{"Debits", formatAmount(s.Debits)},
+		{"Closing balance", formatAmount(s.ClosingBalance)},
+	} {
+		if detail[0] != "" {
+			p.text("F2", 9, pdfMargin, y, detail[0])
+			p.text("F1", 9, pdfMargin+110, y, detail[1])
+		}
+		y -= 14
+	}
+
+	return y - 14
+}
+
+// cell writes a value in a column of the table, cut to the column's width
+func (p *pdfPage) cell(column pdfColumn, y float64, font, value string) {
+	value = truncate(value, column.width)
+	x := column.x
+	if column.right {
+		x -= float64(len([]rune(value))) * pdfCharWidth
+	}
+
+	p.text(font, pdfFontSize, x, y, value)
+}
+
+// text writes a line of text at a position
+func (p *pdfPage) text(font string, size, x, y float64, value string) {
+	fmt.Fprintf(&p.content, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, pdfEscape(value))
+}
+
+// rule draws a horizontal line across the page
+func (p *pdfPage) rule(y float64) {
+	fmt.Fprintf(&p.content, "0.5 w %d %.2f m %d %.2f l S\n", pdfMargin, y, pdfPageWidth-pdfMargin, y)
+}
+
+// writePDFDocument writes the pages as a PDF 1.4 document. The fonts are the standard Courier (F1) and
+// Helvetica-Bold (F2), which readers provide, so nothing is embedded.
+func writePDFDocument(w io.Writer, pages []*pdfPage) error {
+	var buf bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// the pages and their contents follow the catalog, the page tree and the fonts
+	kids := make([]string, 0, len(pages))
+	for i := range pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", 5+2*i))
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	for i, page := range pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, 6+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.content.Len(), page.content.String()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// pdfEscape escapes a text for a PDF string. Characters outside printable ASCII are replaced, as the
+// standard fonts cannot be relied on to have them.
+func pdfEscape(value string) string {
+	var b strings.Builder
+	for _, r := range value {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteRune('\\')
+			b.WriteRune(r)
+		case r < 32 || r > 126:
+			b.WriteRune('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
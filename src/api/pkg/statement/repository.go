@@ -0,0 +1,16 @@
+package statement
+
+// Repository is the statement repository interface
+type Repository interface {
+	// Save stores a new monthly statement. An account has one statement per period.
+	Save(statement *Statement) error
+
+	// Find gets a statement by ID.
+	Find(id string) (*Statement, error)
+
+	// FindByPeriod gets the statement of an account for a period (YYYY-MM).
+	FindByPeriod(accountID, period string) (*Statement, error)
+
+	// FindByAccount gets the statements of an account, oldest period first.
+	FindByAccount(accountID string) ([]*Statement, error)
+}
@@ -0,0 +1,214 @@
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/quabynah-bilson/quantia/pkg/ledger"
+	"github.com/quabynah-bilson/quantia/pkg/statement"
+	"log"
+	"time"
+)
+
+var (
+	// ErrInvalidStatementPeriod is the error returned when a statement is asked for with invalid dates.
+	ErrInvalidStatementPeriod = errors.New("invalid statement period. Please check the dates are formatted as YYYY-MM-DD and from is not after to")
+
+	// ErrStatementPeriodTooLong is the error returned when a statement would cover more than the longest period allowed.
+	ErrStatementPeriodTooLong = errors.New("the statement period is too long. Please ask for a shorter period")
+)
+
+const (
+	// defaultStatementMaxPeriod is the longest period a statement covers when no maximum is configured.
+	defaultStatementMaxPeriod = 366 * 24 * time.Hour
+
+	// defaultStatementInstitution is the name statements are issued under when none is configured.
+	defaultStatementInstitution = "Quantia"
+)
+
+// StatementConfig is the configuration of the statement use case
+type StatementConfig struct {
+	// Currency is the currency the balances of statements are shown in
+	Currency string
+
+	// Institution is the name printed on PDF statements and identifying us in OFX files
+	Institution string
+
+	// MaxPeriod is the longest period a statement generated on demand may cover
+	MaxPeriod time.Duration
+}
+
+// StatementUseCase is the account statement use case. It builds the statements of ledger accounts on demand
+// or for each month, and exports them as CSV, OFX or PDF files.
+type StatementUseCase struct {
+	statementRepo statement.Repository
+	ledgerRepo    ledger.Repository
+	config        StatementConfig
+}
+
+// NewStatementUseCase creates a new statement use case.
+func NewStatementUseCase(statementRepo statement.Repository, ledgerRepo ledger.Repository, config StatementConfig) *StatementUseCase {
+	if config.Institution == "" {
+		config.Institution = defaultStatementInstitution
+	}
+	if config.MaxPeriod <= 0 {
+		config.MaxPeriod = defaultStatementMaxPeriod
+	}
+
+	return &StatementUseCase{
+		statementRepo: statementRepo,
+		ledgerRepo:    ledgerRepo,
+		config:        config,
+	}
+}
+
+// Generate builds the statement of an account from one day to another, both included. A missing to is
+// today and a missing from is the first day of to's month.
+func (uc *StatementUseCase) Generate(accountID, from, to string) (*statement.Statement, error) {
+	if accountID == "" {
+		return nil, ErrInvalidAccount
+	}
+
+	end := time.Now().UTC().Truncate(24 * time.Hour)
+	if to != "" {
+		var err error
+		if end, err = time.Parse(statement.DateLayout, to); err != nil {
+			return nil, ErrInvalidStatementPeriod
+		}
+	}
+
+	start := time.Date(end.Year(), end.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if from != "" {
+		var err error
+		if start, err = time.Parse(statement.DateLayout, from); err != nil {
+			return nil, ErrInvalidStatementPeriod
+		}
+	}
+
+	// the last day is included
+	end = end.AddDate(0, 0, 1)
+	if !start.Before(end) {
+		return nil, ErrInvalidStatementPeriod
+	}
+	if end.Sub(start) > uc.config.MaxPeriod {
+		return nil, ErrStatementPeriodTooLong
+	}
+
+	entries, err := uc.ledgerRepo.Entries(accountID)
+	if err != nil {
+		log.Printf("error getting entries of account %s: %v", accountID, err)
+		return nil, err
+	}
+
+	return statement.New(accountID, uc.config.Currency, start, end, entries), nil
+}
+
+// GetMonthlyStatements gets the summaries of the stored monthly statements of an account, oldest first.
+func (uc *StatementUseCase) GetMonthlyStatements(accountID string) ([]*statement.Statement, error) {
+	statements, err := uc.statementRepo.FindByAccount(accountID)
+	if err != nil {
+		log.Printf("error getting statements of account %s: %v", accountID, err)
+		return nil, err
+	}
+
+	summaries := make([]*statement.Statement, 0, len(statements))
+	for _, s := range statements {
+		summaries = append(summaries, s.Summary())
+	}
+
+	return summaries, nil
+}
+
+// GetMonthlyStatement gets the stored statement of an account for a month (YYYY-MM).
+func (uc *StatementUseCase) GetMonthlyStatement(accountID, period string) (*statement.Statement, error) {
+	return uc.statementRepo.FindByPeriod(accountID, period)
+}
+
+// Export writes a statement as a file in a format.
+func (uc *StatementUseCase) Export(s *statement.Statement, format statement.Format) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := statement.Write(&buf, s, format, uc.config.Institution); err != nil {
+		log.Printf("error exporting statement %s as %s: %v", s.ID, format, err)
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Run generates the monthly statements of the previous month, checking every interval until the context is
+// cancelled.
+func (uc *StatementUseCase) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		uc.RunMonthly(time.Now().UTC())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunMonthly stores the statement of the month before now for every customer and merchant account that
+// existed by the end of it and does not have one yet, and returns how many were generated.
+func (uc *StatementUseCase) RunMonthly(now time.Time) int {
+	period := statement.PreviousPeriod(now)
+	accountIDs, err := uc.ledgerRepo.AccountIDs()
+	if err != nil {
+		log.Printf("error getting ledger accounts: %v", err)
+		return 0
+	}
+
+	generated := 0
+	for _, accountID := range accountIDs {
+		if ledger.IsSystemAccount(accountID) {
+			continue
+		}
+
+		if _, err = uc.statementRepo.FindByPeriod(accountID, period); err == nil {
+			continue
+		}
+
+		if uc.generateMonthly(accountID, period) {
+			generated++
+		}
+	}
+
+	if generated > 0 {
+		log.Printf("generated %d statements for %s", generated, period)
+	}
+
+	return generated
+}
+
+// generateMonthly stores the statement of an account for a month. Accounts opened after the month are left out.
+func (uc *StatementUseCase) generateMonthly(accountID, period string) bool {
+	entries, err := uc.ledgerRepo.Entries(accountID)
+	if err != nil {
+		log.Printf("error getting entries of account %s: %v", accountID, err)
+		return false
+	}
+
+	s, err := statement.NewMonthly(accountID, uc.config.Currency, period, entries)
+	if err != nil {
+		log.Printf("error building statement of account %s for %s: %v", accountID, period, err)
+		return false
+	}
+
+	if len(entries) == 0 || !entries[0].CreatedAt.Before(s.To) {
+		return false
+	}
+
+	// another worker may have stored the statement since it was looked up
+	if err = uc.statementRepo.Save(s); err != nil {
+		if !errors.Is(err, statement.ErrStatementAlreadyGenerated) {
+			log.Printf("error saving statement of account %s for %s: %v", accountID, period, err)
+		}
+		return false
+	}
+
+	return true
+}
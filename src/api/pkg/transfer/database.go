@@ -0,0 +1,23 @@
+package transfer
+
+import "errors"
+
+var (
+	// ErrTransferNotFound is the error returned when a transfer does not exist
+	ErrTransferNotFound = errors.New("transfer not found")
+
+	// ErrFailedToSaveTransfer is the error returned when a transfer cannot be stored
+	ErrFailedToSaveTransfer = errors.New("failed to save transfer. Please try again")
+)
+
+// Database is the interface that wraps the basic transfer database operations.
+type Database interface {
+	// SaveTransfer creates or replaces a transfer
+	SaveTransfer(transfer *Transfer) error
+
+	// GetTransfer gets a transfer by ID
+	GetTransfer(id string) (*Transfer, error)
+
+	// GetDateTransfers gets the transfers made on a business date (UTC, 2006-01-02), oldest first
+	GetDateTransfers(date string) ([]*Transfer, error)
+}
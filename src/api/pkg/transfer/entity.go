@@ -0,0 +1,87 @@
+package transfer
+
+import (
+	"github.com/google/uuid"
+	"github.com/quabynah-bilson/quantia/pkg/beneficiary"
+	"strings"
+	"time"
+)
+
+// referencePrefix is the prefix of transfer IDs, used to route provider results to transfers
+const referencePrefix = "trf_"
+
+// Status is the type that represents the status of a transfer
+type Status string
+
+const (
+	// StatusPending is the status of a transfer waiting for the payout provider
+	StatusPending Status = "pending"
+
+	// StatusCompleted is the status of a transfer that reached the beneficiary
+	StatusCompleted Status = "completed"
+
+	// StatusFailed is the status of a transfer that was declined. The account is refunded.
+	StatusFailed Status = "failed"
+)
+
+// Transfer is the entity that represents money sent from an account to one of its beneficiaries
+type Transfer struct {
+	ID            string  `json:"id"`
+	AccountID     string  `json:"account_id"`
+	BeneficiaryID string  `json:"beneficiary_id"`
+	Amount        float32 `json:"amount"`
+	Note          string  `json:"note,omitempty"`
+
+	// Url is the endpoint notified when the transfer completes, if set
+	Url string `json:"url,omitempty"`
+
+	// DestinationType, Destination, BankCode and BeneficiaryName are copied from the beneficiary when the transfer is made
+	DestinationType beneficiary.DestinationType `json:"destination_type"`
+	Destination     string                      `json:"destination"`
+	BankCode        string                      `json:"bank_code,omitempty"`
+	BeneficiaryName string                      `json:"beneficiary_name,omitempty"`
+
+	Status Status `json:"status"`
+
+	// HoldID is the ledger hold that debited the account
+	HoldID            string    `json:"hold_id,omitempty"`
+	ProviderReference string    `json:"provider_reference,omitempty"`
+	FailureReason     string    `json:"failure_reason,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// NewTransfer creates a new pending transfer to the beneficiary, notifying the URL when it completes
+func NewTransfer(accountID string, b *beneficiary.Beneficiary, amount float32, note, url string) *Transfer {
+	now := time.Now().UTC()
+	return &Transfer{
+		ID:              referencePrefix + uuid.NewString(),
+		AccountID:       accountID,
+		BeneficiaryID:   b.ID,
+		Amount:          amount,
+		Note:            note,
+		Url:             url,
+		DestinationType: b.DestinationType,
+		Destination:     b.Destination,
+		BankCode:        b.BankCode,
+		BeneficiaryName: b.Name,
+		Status:          StatusPending,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+}
+
+// IsBankTransfer reports whether the transfer goes to an account at another bank
+func (t *Transfer) IsBankTransfer() bool {
+	return t.DestinationType == beneficiary.DestinationBankAccount
+}
+
+// IsInternal reports whether the transfer stays within our ledger
+func (t *Transfer) IsInternal() bool {
+	return t.DestinationType == beneficiary.DestinationInternalAccount
+}
+
+// IsTransferReference reports whether a provider reference identifies a transfer
+func IsTransferReference(reference string) bool {
+	return strings.HasPrefix(reference, referencePrefix)
+}
@@ -0,0 +1,13 @@
+package transfer
+
+// Repository is the transfer repository interface
+type Repository interface {
+	// Save creates or replaces a transfer.
+	Save(transfer *Transfer) error
+
+	// Find gets a transfer by ID.
+	Find(id string) (*Transfer, error)
+
+	// FindByDate gets the transfers made on a business date, oldest first.
+	FindByDate(date string) ([]*Transfer, error)
+}
@@ -0,0 +1,398 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/quabynah-bilson/quantia/pkg/beneficiary"
+	"github.com/quabynah-bilson/quantia/pkg/event"
+	"github.com/quabynah-bilson/quantia/pkg/iso20022"
+	"github.com/quabynah-bilson/quantia/pkg/ledger"
+	"github.com/quabynah-bilson/quantia/pkg/limit"
+	"github.com/quabynah-bilson/quantia/pkg/overdraft"
+	"github.com/quabynah-bilson/quantia/pkg/payment"
+	"github.com/quabynah-bilson/quantia/pkg/screening"
+	"github.com/quabynah-bilson/quantia/pkg/transfer"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrPayoutsNotSupported is the error returned when money cannot be sent to a destination outside the ledger.
+	ErrPayoutsNotSupported = errors.New("the payment provider cannot send money to this destination")
+
+	// ErrBankExportNotConfigured is the error returned when transfers are exported without a partner bank account to pay them from.
+	ErrBankExportNotConfigured = errors.New("transfers cannot be exported without a partner bank account")
+
+	// ErrInvalidExportDate is the error returned when transfers are exported without a valid business date.
+	ErrInvalidExportDate = errors.New("invalid export date. Please check the date is formatted as YYYY-MM-DD")
+
+	// ErrTransferNotificationsNotSupported is the error returned when a transfer asks for an endpoint to be notified without an event queue to notify it through.
+	ErrTransferNotificationsNotSupported = errors.New("transfers cannot notify an endpoint")
+
+	// ErrNoTransfersToExport is the error returned when no transfer to a bank account was made on the exported date.
+	ErrNoTransfersToExport = errors.New("no transfers to bank accounts were made on this date")
+)
+
+// transferHoldExpiry bounds the hold that reserves a transfer's amount until it is debited.
+const transferHoldExpiry = time.Minute
+
+// BankExportConfig is the partner bank account the transfers to bank accounts are paid from, and the currency
+// they are paid in, when they are exported as ISO 20022 pain.001 files.
+type BankExportConfig struct {
+	Debtor   iso20022.Debtor
+	Currency string
+}
+
+// TransferUseCase is the transfer use case. It contains the necessary repositories to send money from an
+// account to its beneficiaries, within the ledger or out through the payout provider.
+type TransferUseCase struct {
+	transferRepo  transfer.Repository
+	ledgerRepo    ledger.Repository
+	beneficiaries *BeneficiaryUseCase
+
+	// payouts sends money to external destinations. It is nil when the provider cannot disburse.
+	payouts payment.PayoutProvider
+
+	// limits counts transfers against the limits of their account. Transfers are not limited when it is nil.
+	limits *LimitUseCase
+
+	// screening keeps sanctioned or unreviewed accounts and beneficiaries from transacting. It is nil when screening is off.
+	screening *ScreeningUseCase
+
+	// overdrafts is told about the accounts transfers move money between. Overdrafts are only checked by the background jobs when it is nil.
+	overdrafts *OverdraftUseCase
+
+	// payments checks the endpoints of transfers and queues their events. Transfers cannot notify an endpoint when it is nil.
+	payments *PaymentUseCase
+
+	// bankExport is the partner bank account transfers to bank accounts are exported from. Transfers cannot be exported when it is nil.
+	bankExport *BankExportConfig
+
+	// mu serializes the completion of transfers by the API and the provider results
+	mu sync.Mutex
+}
+
+// NewTransferUseCase creates a new transfer use case.
+func NewTransferUseCase(transferRepo transfer.Repository, ledgerRepo ledger.Repository, beneficiaries *BeneficiaryUseCase, payouts payment.PayoutProvider) *TransferUseCase {
+	return &TransferUseCase{
+		transferRepo:  transferRepo,
+		ledgerRepo:    ledgerRepo,
+		beneficiaries: beneficiaries,
+		payouts:       payouts,
+	}
+}
+
+// SetLimits makes transfers count against the transaction limits of their account.
+func (uc *TransferUseCase) SetLimits(limits *LimitUseCase) {
+	uc.limits = limits
+}
+
+// SetScreening keeps the accounts and beneficiaries with sanctions hits from transacting until they are cleared.
+func (uc *TransferUseCase) SetScreening(screening *ScreeningUseCase) {
+	uc.screening = screening
+}
+
+// SetOverdrafts notifies the accounts of a transfer entering or leaving their overdraft as soon as it is made.
+func (uc *TransferUseCase) SetOverdrafts(overdrafts *OverdraftUseCase) {
+	uc.overdrafts = overdrafts
+}
+
+// SetNotifications notifies the endpoints of transfers when they complete, through the event queue of the payments.
+func (uc *TransferUseCase) SetNotifications(payments *PaymentUseCase) {
+	uc.payments = payments
+}
+
+// SetBankExport allows the transfers to bank accounts to be exported for the partner bank, paid from its account.
+func (uc *TransferUseCase) SetBankExport(config *BankExportConfig) {
+	uc.bankExport = config
+}
+
+// Transfer sends the amount from the account to one of its beneficiaries. The account is debited at once;
+// transfers to internal accounts complete immediately, the others stay pending until the payout provider
+// confirms them and are refunded if it declines them. Transfers must fit within the account's limits. The URL,
+// when given, is notified once the transfer completes.
+func (uc *TransferUseCase) Transfer(accountID, beneficiaryID string, amount float32, note, url string) (*transfer.Transfer, error) {
+	if err := validateAmount(amount); err != nil {
+		log.Printf("error validating amount: %v", err)
+		return nil, err
+	}
+
+	if url != "" {
+		if uc.payments == nil {
+			return nil, ErrTransferNotificationsNotSupported
+		}
+		if err := uc.payments.checkURL(url); err != nil {
+			return nil, err
+		}
+	}
+
+	b, err := uc.beneficiaries.Authorize(accountID, beneficiaryID, amount)
+	if err != nil {
+		log.Printf("error authorizing beneficiary %s: %v", beneficiaryID, err)
+		return nil, err
+	}
+
+	if uc.screening != nil {
+		if err = uc.screening.Authorize(screening.PartyAccount, accountID); err != nil {
+			return nil, err
+		}
+		if err = uc.screening.Authorize(screening.PartyBeneficiary, b.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	t := transfer.NewTransfer(accountID, b, amount, note, url)
+	creditAccountID := b.Destination
+	if !t.IsInternal() {
+		if uc.payouts == nil {
+			return nil, ErrPayoutsNotSupported
+		}
+		creditAccountID = ledger.PayoutsClearingAccountID
+	}
+
+	// count the transfer against the account's limits before it is debited
+	if uc.limits != nil {
+		if _, err = uc.limits.Reserve(accountID, amount, t.CreatedAt); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = uc.debit(t, creditAccountID); err != nil {
+		uc.releaseLimit(t)
+		return nil, err
+	}
+
+	if t.IsInternal() {
+		t.Status = transfer.StatusCompleted
+	}
+
+	// save the transfer before calling the provider, so that an early result finds it. A transfer that cannot
+	// be saved is given back, so that the account is not debited again when it is retried.
+	if err = uc.transferRepo.Save(t); err != nil {
+		log.Printf("error saving transfer %s: %v", t.ID, err)
+		uc.reverse(t, creditAccountID)
+		uc.releaseLimit(t)
+		return nil, err
+	}
+
+	if t.IsInternal() {
+		uc.notify(t)
+		return t, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), providerTimeout)
+	defer cancel()
+
+	result, err := uc.payouts.Disburse(ctx, &payment.DisbursementRequest{
+		Reference:   t.ID,
+		Amount:      amount,
+		Destination: disbursementDestination(b.DestinationType, b.Destination, b.BankCode),
+		Note:        note,
+	})
+	if result == nil {
+		// the outcome is unknown, so the transfer stays pending until the provider reports back
+		log.Printf("error disbursing transfer %s: %v", t.ID, err)
+		return t, nil
+	}
+
+	return uc.applyResult(t.ID, result)
+}
+
+// GetTransfer gets a transfer by ID.
+func (uc *TransferUseCase) GetTransfer(id string) (*transfer.Transfer, error) {
+	return uc.transferRepo.Find(id)
+}
+
+// HandleProviderResult completes a pending transfer with an asynchronous result from the payout provider.
+func (uc *TransferUseCase) HandleProviderResult(result *payment.ProviderResult) error {
+	if _, err := uc.applyResult(result.Reference, result); err != nil && !errors.Is(err, payment.ErrDisbursementDeclined) {
+		return err
+	}
+
+	return nil
+}
+
+// ExportPain001 writes the transfers to bank accounts made on a business date as an ISO 20022 pain.001 credit
+// transfer initiation, for the partner bank to pay them. Failed transfers are left out. Each transfer is
+// identified by its end-to-end ID, its ID without separators.
+func (uc *TransferUseCase) ExportPain001(date string) ([]byte, error) {
+	if uc.bankExport == nil {
+		return nil, ErrBankExportNotConfigured
+	}
+
+	executionDate, err := time.Parse(iso20022.DateLayout, date)
+	if err != nil {
+		return nil, ErrInvalidExportDate
+	}
+
+	transfers, err := uc.transferRepo.FindByDate(date)
+	if err != nil {
+		log.Printf("error getting transfers of %s: %v", date, err)
+		return nil, err
+	}
+
+	var credits []iso20022.CreditTransfer
+	for _, t := range transfers {
+		if !t.IsBankTransfer() || t.Status == transfer.StatusFailed {
+			continue
+		}
+
+		credits = append(credits, iso20022.CreditTransfer{
+			EndToEndID:      endToEndID(t.ID),
+			Amount:          t.Amount,
+			Currency:        uc.bankExport.Currency,
+			CreditorName:    t.BeneficiaryName,
+			CreditorAccount: t.Destination,
+			CreditorAgent:   t.BankCode,
+			Remittance:      t.Note,
+		})
+	}
+
+	if len(credits) == 0 {
+		return nil, ErrNoTransfersToExport
+	}
+
+	messageID := "TRF" + strings.ReplaceAll(date, "-", "") + "-" + strings.ReplaceAll(uuid.NewString(), "-", "")[:16]
+	data, err := iso20022.Marshal(iso20022.NewPain001(messageID, uc.bankExport.Debtor, executionDate, credits))
+	if err != nil {
+		log.Printf("error exporting transfers of %s: %v", date, err)
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// endToEndID returns the end-to-end ID a transfer is exported with: its ID without separators, which fits the
+// 35 characters allowed by ISO 20022.
+func endToEndID(transferID string) string {
+	return strings.NewReplacer("_", "", "-", "").Replace(transferID)
+}
+
+// debit moves the amount from the account to the credit account. The hold checks the available balance and
+// reserves the funds atomically before they are captured.
+func (uc *TransferUseCase) debit(t *transfer.Transfer, creditAccountID string) error {
+	hold := ledger.NewHold(t.AccountID, creditAccountID, t.Amount, transferHoldExpiry)
+	if err := uc.ledgerRepo.Hold(hold); err != nil {
+		log.Printf("error placing hold for transfer %s: %v", t.ID, err)
+		return err
+	}
+	t.HoldID = hold.ID
+
+	if _, err := uc.ledgerRepo.Capture(hold.ID, t.Amount); err != nil {
+		log.Printf("error capturing hold %s for transfer %s: %v", hold.ID, t.ID, err)
+		if _, releaseErr := uc.ledgerRepo.Release(hold.ID, ledger.HoldStatusVoided); releaseErr != nil {
+			log.Printf("error releasing hold %s: %v", hold.ID, releaseErr)
+		}
+		return err
+	}
+	uc.checkOverdrafts(t.AccountID, creditAccountID)
+
+	return nil
+}
+
+// applyResult completes a pending transfer with the provider's result. Results for transfers that are
+// already complete are ignored.
+func (uc *TransferUseCase) applyResult(id string, result *payment.ProviderResult) (*transfer.Transfer, error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	t, err := uc.transferRepo.Find(id)
+	if err != nil {
+		log.Printf("error finding transfer %s: %v", id, err)
+		return nil, err
+	}
+
+	if t.Status != transfer.StatusPending {
+		return t, nil
+	}
+
+	t.ProviderReference = result.ProviderReference
+	switch result.Status {
+	case payment.ProviderStatusDisbursed:
+		t.Status = transfer.StatusCompleted
+	case payment.ProviderStatusDeclined:
+		t.Status, t.FailureReason = transfer.StatusFailed, result.DeclineReason
+		uc.reverse(t, ledger.PayoutsClearingAccountID)
+		uc.releaseLimit(t)
+	}
+	t.UpdatedAt = time.Now().UTC()
+
+	if err = uc.transferRepo.Save(t); err != nil {
+		log.Printf("error saving transfer %s: %v", t.ID, err)
+		return nil, err
+	}
+
+	if t.Status == transfer.StatusFailed {
+		return t, payment.ErrDisbursementDeclined
+	}
+
+	if t.Status == transfer.StatusCompleted {
+		uc.notify(t)
+	}
+
+	return t, nil
+}
+
+// notify queues the transfer.completed event for the transfer, when it has an endpoint.
+func (uc *TransferUseCase) notify(t *transfer.Transfer) {
+	if t.Url == "" || uc.payments == nil {
+		return
+	}
+
+	envelope, err := event.New(event.TypeTransferCompleted, &event.TransferData{
+		TransferID:    t.ID,
+		FromAccountID: t.AccountID,
+		ToAccountID:   t.Destination,
+		Amount:        t.Amount,
+	})
+	if err != nil {
+		log.Printf("error creating %s event: %v", event.TypeTransferCompleted, err)
+		return
+	}
+
+	if err = uc.payments.paymentRepo.Notify(t.Url, envelope); err != nil {
+		log.Printf("error queueing %s event: %v", event.TypeTransferCompleted, err)
+	}
+}
+
+// reverse returns the amount of a transfer that did not go through from the account it was credited to
+func (uc *TransferUseCase) reverse(t *transfer.Transfer, creditAccountID string) {
+	entries := ledger.NewTransfer(t.ID, "transfer reversal", creditAccountID, t.AccountID, t.Amount)
+	if err := uc.ledgerRepo.Post(entries...); err != nil && !errors.Is(err, ledger.ErrEntriesAlreadyPosted) {
+		log.Printf("error reversing transfer %s: %v", t.ID, err)
+	}
+	uc.checkOverdrafts(t.AccountID, creditAccountID)
+}
+
+// checkOverdrafts checks whether the accounts a transfer moved money between entered or left their overdraft
+func (uc *TransferUseCase) checkOverdrafts(accountIDs ...string) {
+	if uc.overdrafts == nil {
+		return
+	}
+
+	for _, accountID := range accountIDs {
+		if _, err := uc.overdrafts.Check(accountID); err != nil && !errors.Is(err, overdraft.ErrFacilityNotFound) {
+			log.Printf("error checking overdraft of account %s: %v", accountID, err)
+		}
+	}
+}
+
+// releaseLimit gives back the limits counted for a transfer that did not go through
+func (uc *TransferUseCase) releaseLimit(t *transfer.Transfer) {
+	if uc.limits != nil {
+		uc.limits.Release(&limit.Reservation{Subject: t.AccountID, Amount: t.Amount, At: t.CreatedAt})
+	}
+}
+
+// disbursementDestination returns the destination sent to the payout provider. Bank accounts are
+// identified by their bank code and account number.
+func disbursementDestination(destinationType beneficiary.DestinationType, destination, bankCode string) string {
+	if destinationType == beneficiary.DestinationBankAccount {
+		return bankCode + "/" + destination
+	}
+
+	return destination
+}
@@ -3,7 +3,6 @@ package mocks
 import (
 	"errors"
 	"github.com/quabynah-bilson/quantia/pkg/account"
-	pkg "github.com/quabynah-bilson/quantia/pkg/payment"
 )
 
 var (
@@ -30,7 +29,8 @@ var (
 type MockAccountRepository struct {
 	LoginFn    func(username, password string) (*account.Account, error)
 	RegisterFn func(username, password string) (*account.Account, error)
-	pkg.Repository
+	FindFn     func(id string) (*account.Account, error)
+	account.Repository
 }
 
 // Login mocks the login method.
@@ -54,3 +54,12 @@ func (m *MockAccountRepository) Register(username, password string) (*account.Ac
 
 	return m.RegisterFn(username, password)
 }
+
+// Find mocks the find method.
+func (m *MockAccountRepository) Find(id string) (*account.Account, error) {
+	if m.FindFn == nil {
+		return nil, account.ErrAccountNotFound
+	}
+
+	return m.FindFn(id)
+}
@@ -93,7 +93,7 @@ func TestAuthUseCase_RegisterUser(t *testing.T) {
 			}
 
 			uc := pkg.NewAuthUseCase(authRepo, tokenRepo)
-			token, err := uc.Register(tc.username, tc.password)
+			token, err := uc.Register(tc.username, tc.password, "")
 			if !errors.Is(err, tc.expectedErr) {
 				t.Errorf("expected error %v, got %v", tc.expectedErr, err)
 			}
@@ -256,3 +256,92 @@ func TestAuthUseCase_ValidateToken(t *testing.T) {
 		})
 	}
 }
+
+// TestAuthUseCase_CheckRole tests the check role method of the auth use case.
+func TestAuthUseCase_CheckRole(t *testing.T) {
+	testCases := []struct {
+		name        string
+		accountID   string
+		roles       []pkg.Role
+		expectedErr error
+	}{
+		{
+			name:      "admin",
+			accountID: "admin_1",
+			roles:     []pkg.Role{pkg.RoleAdmin},
+		},
+		{
+			name:      "one of the roles",
+			accountID: "analyst_1",
+			roles:     []pkg.Role{pkg.RoleAdmin, pkg.RoleAnalyst},
+		},
+		{
+			name:        "other role",
+			accountID:   "analyst_1",
+			roles:       []pkg.Role{pkg.RoleAdmin},
+			expectedErr: pkg.ErrRoleRequired,
+		},
+		{
+			name:        "customer",
+			accountID:   uuid.NewString(),
+			roles:       []pkg.Role{pkg.RoleAdmin, pkg.RoleAnalyst},
+			expectedErr: pkg.ErrRoleRequired,
+		},
+		{
+			name:        "no role allowed",
+			accountID:   "admin_1",
+			expectedErr: pkg.ErrRoleRequired,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			uc := pkg.NewAuthUseCase(nil, &mocks.MockTokenRepository{})
+			uc.SetRoles(map[string]pkg.Role{"admin_1": pkg.RoleAdmin, "analyst_1": pkg.RoleAnalyst})
+
+			// Act
+			err := uc.CheckRole(tc.accountID, tc.roles...)
+
+			// Assert
+			if !errors.Is(err, tc.expectedErr) {
+				t.Errorf("expected error %v, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+// TestAuthUseCase_MerchantAccount tests that accounts acting for a merchant are given the ledger account of its URL.
+func TestAuthUseCase_MerchantAccount(t *testing.T) {
+	testCases := []struct {
+		name            string
+		accountID       string
+		expectedAccount string
+	}{
+		{
+			name:            "merchant",
+			accountID:       "merchant_1",
+			expectedAccount: "merchant:shop.example.com",
+		},
+		{
+			name:      "customer",
+			accountID: uuid.NewString(),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			uc := pkg.NewAuthUseCase(nil, &mocks.MockTokenRepository{})
+			uc.SetMerchants(map[string]string{"merchant_1": "https://shop.example.com/hooks"})
+
+			// Act
+			account := uc.MerchantAccount(tc.accountID)
+
+			// Assert
+			if account != tc.expectedAccount {
+				t.Errorf("expected account %q, got %q", tc.expectedAccount, account)
+			}
+		})
+	}
+}
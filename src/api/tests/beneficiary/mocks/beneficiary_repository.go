@@ -0,0 +1,113 @@
+package mocks
+
+import (
+	"context"
+	"github.com/quabynah-bilson/quantia/pkg"
+	"github.com/quabynah-bilson/quantia/pkg/beneficiary"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MockBeneficiaryRepository is an in-memory beneficiary repository
+type MockBeneficiaryRepository struct {
+	mu            sync.Mutex
+	Beneficiaries map[string]*beneficiary.Beneficiary
+}
+
+// NewMockBeneficiaryRepository creates an empty in-memory beneficiary repository
+func NewMockBeneficiaryRepository() *MockBeneficiaryRepository {
+	return &MockBeneficiaryRepository{Beneficiaries: make(map[string]*beneficiary.Beneficiary)}
+}
+
+// Create stores a copy of the beneficiary unless the account already saved its destination
+func (m *MockBeneficiaryRepository) Create(b *beneficiary.Beneficiary) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, existing := range m.Beneficiaries {
+		if existing.AccountID == b.AccountID && existing.DestinationType == b.DestinationType &&
+			existing.BankCode == b.BankCode && existing.Destination == b.Destination {
+			return beneficiary.ErrBeneficiaryAlreadyExists
+		}
+	}
+
+	copied := *b
+	m.Beneficiaries[b.ID] = &copied
+	return nil
+}
+
+// Find returns a copy of the beneficiary
+func (m *MockBeneficiaryRepository) Find(id string) (*beneficiary.Beneficiary, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	b, ok := m.Beneficiaries[id]
+	if !ok {
+		return nil, beneficiary.ErrBeneficiaryNotFound
+	}
+	copied := *b
+	return &copied, nil
+}
+
+// FindByAccount returns the beneficiaries of the account, oldest first
+func (m *MockBeneficiaryRepository) FindByAccount(accountID string) ([]*beneficiary.Beneficiary, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var beneficiaries []*beneficiary.Beneficiary
+	for _, b := range m.Beneficiaries {
+		if b.AccountID == accountID {
+			copied := *b
+			beneficiaries = append(beneficiaries, &copied)
+		}
+	}
+	sort.Slice(beneficiaries, func(i, j int) bool { return beneficiaries[i].CreatedAt.Before(beneficiaries[j].CreatedAt) })
+
+	return beneficiaries, nil
+}
+
+// Delete removes the beneficiary
+func (m *MockBeneficiaryRepository) Delete(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.Beneficiaries[id]; !ok {
+		return beneficiary.ErrBeneficiaryNotFound
+	}
+	delete(m.Beneficiaries, id)
+	return nil
+}
+
+// MockNameResolver resolves the destinations in Names and fails with Err when it is set
+type MockNameResolver struct {
+	Names map[string]string
+	Err   error
+}
+
+// ResolveName returns the name held for the destination
+func (m *MockNameResolver) ResolveName(_ context.Context, destination, bankCode string) (string, error) {
+	if m.Err != nil {
+		return "", m.Err
+	}
+
+	key := destination
+	if bankCode != "" {
+		key = bankCode + ":" + destination
+	}
+	name, ok := m.Names[key]
+	if !ok {
+		return "", beneficiary.ErrDestinationNotFound
+	}
+
+	return name, nil
+}
+
+// NewBeneficiaryUseCase creates a beneficiary use case over an empty repository, resolving the internal accounts
+// and mobile wallets in names. Amounts of 50 or more are refused to beneficiaries added less than coolingOff ago.
+func NewBeneficiaryUseCase(names map[string]string, coolingOff time.Duration) *pkg.BeneficiaryUseCase {
+	return pkg.NewBeneficiaryUseCase(NewMockBeneficiaryRepository(), map[beneficiary.DestinationType]beneficiary.NameResolver{
+		beneficiary.DestinationInternalAccount: &MockNameResolver{Names: names},
+		beneficiary.DestinationMobileWallet:    &MockNameResolver{Names: names},
+	}, pkg.BeneficiaryConfig{CoolingOff: coolingOff, LargeAmount: 50})
+}
@@ -0,0 +1,53 @@
+package unit
+
+import (
+	"github.com/quabynah-bilson/quantia/pkg/beneficiary"
+	"testing"
+	"time"
+)
+
+// TestNamesMatch tests the comparison of given names with the names held by destinations.
+func TestNamesMatch(t *testing.T) {
+	testCases := []struct {
+		name     string
+		given    string
+		held     string
+		expected bool
+	}{
+		{name: "exact", given: "Kofi Mensah", held: "Kofi Mensah", expected: true},
+		{name: "case and punctuation", given: "kofi  mensah.", held: "KOFI MENSAH", expected: true},
+		{name: "reversed order", given: "Mensah Kofi", held: "Kofi Mensah", expected: true},
+		{name: "held middle name", given: "Kofi Mensah", held: "Kofi Ato Mensah", expected: true},
+		{name: "single name of many", given: "Kofi", held: "Kofi Ato Mensah", expected: false},
+		{name: "different surname", given: "Kofi Boateng", held: "Kofi Mensah", expected: false},
+		{name: "extra given name", given: "Kofi Ato Mensah", held: "Kofi Mensah", expected: false},
+		{name: "empty", given: "", held: "Kofi Mensah", expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			matches := beneficiary.NamesMatch(tc.given, tc.held)
+
+			// Assert
+			if matches != tc.expected {
+				t.Errorf("expected %v for %q and %q, got: %v", tc.expected, tc.given, tc.held, matches)
+			}
+		})
+	}
+}
+
+// TestBeneficiary_IsCoolingOff tests the cooling-off period of new beneficiaries.
+func TestBeneficiary_IsCoolingOff(t *testing.T) {
+	// Arrange
+	b := beneficiary.NewBeneficiary("acc_1", "Mum", beneficiary.DestinationMobileWallet, "233240000001", "", "Ama Owusu", time.Hour)
+
+	// Act & Assert
+	if !b.IsCoolingOff(time.Now()) {
+		t.Errorf("expected a new beneficiary to be cooling off")
+	}
+
+	if b.IsCoolingOff(time.Now().Add(time.Hour)) {
+		t.Errorf("expected the cooling-off period to end after an hour")
+	}
+}
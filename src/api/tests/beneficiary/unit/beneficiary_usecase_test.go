@@ -0,0 +1,150 @@
+package unit
+
+import (
+	"errors"
+	"github.com/quabynah-bilson/quantia/pkg"
+	"github.com/quabynah-bilson/quantia/pkg/beneficiary"
+	"github.com/quabynah-bilson/quantia/tests/beneficiary/mocks"
+	"testing"
+	"time"
+)
+
+// newBeneficiaryUseCase creates a beneficiary use case whose destinations are resolved from fixed names.
+func newBeneficiaryUseCase(repo *mocks.MockBeneficiaryRepository, coolingOff time.Duration) *pkg.BeneficiaryUseCase {
+	return pkg.NewBeneficiaryUseCase(repo, map[beneficiary.DestinationType]beneficiary.NameResolver{
+		beneficiary.DestinationInternalAccount: &mocks.MockNameResolver{Names: map[string]string{"acc_2": "Kofi Mensah"}},
+		beneficiary.DestinationMobileWallet:    &mocks.MockNameResolver{Names: map[string]string{"233240000001": "Ama Owusu"}},
+		beneficiary.DestinationBankAccount:     &mocks.MockNameResolver{Err: beneficiary.ErrResolverUnavailable},
+	}, pkg.BeneficiaryConfig{CoolingOff: coolingOff, LargeAmount: 1000})
+}
+
+// TestBeneficiaryUseCase_AddBeneficiary tests that beneficiaries are only saved once their name is confirmed.
+func TestBeneficiaryUseCase_AddBeneficiary(t *testing.T) {
+	testCases := []struct {
+		name            string
+		nickname        string
+		destinationType beneficiary.DestinationType
+		destination     string
+		bankCode        string
+		holder          string
+		expectedErr     error
+	}{
+		{name: "internal account", nickname: "Kofi", destinationType: beneficiary.DestinationInternalAccount, destination: "acc_2", holder: "kofi mensah"},
+		{name: "mobile wallet", nickname: "Mum", destinationType: beneficiary.DestinationMobileWallet, destination: "233240000001", holder: "Ama Owusu"},
+		{name: "name mismatch", nickname: "Mum", destinationType: beneficiary.DestinationMobileWallet, destination: "233240000001", holder: "Akosua Owusu", expectedErr: beneficiary.ErrNameMismatch},
+		{name: "unknown destination", nickname: "Mum", destinationType: beneficiary.DestinationMobileWallet, destination: "233240000009", holder: "Ama Owusu", expectedErr: beneficiary.ErrDestinationNotFound},
+		{name: "own account", nickname: "Me", destinationType: beneficiary.DestinationInternalAccount, destination: "acc_1", holder: "Me", expectedErr: pkg.ErrInvalidBeneficiary},
+		{name: "bank account without bank code", nickname: "Rent", destinationType: beneficiary.DestinationBankAccount, destination: "0012345678", holder: "Landlord", expectedErr: pkg.ErrInvalidBeneficiary},
+		{name: "bank unavailable", nickname: "Rent", destinationType: beneficiary.DestinationBankAccount, destination: "0012345678", bankCode: "GCB", holder: "Landlord", expectedErr: beneficiary.ErrResolverUnavailable},
+		{name: "missing nickname", destinationType: beneficiary.DestinationMobileWallet, destination: "233240000001", holder: "Ama Owusu", expectedErr: pkg.ErrInvalidBeneficiary},
+		{name: "unknown type", nickname: "Card", destinationType: "card", destination: "4111", holder: "Ama Owusu", expectedErr: pkg.ErrInvalidBeneficiary},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			repo := mocks.NewMockBeneficiaryRepository()
+			beneficiaryUseCase := newBeneficiaryUseCase(repo, time.Hour)
+
+			// Act
+			b, err := beneficiaryUseCase.AddBeneficiary("acc_1", tc.nickname, tc.destinationType, tc.destination, tc.bankCode, tc.holder)
+
+			// Assert
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error: %v, got: %v", tc.expectedErr, err)
+			}
+
+			if tc.expectedErr != nil {
+				if len(repo.Beneficiaries) != 0 {
+					t.Errorf("expected nothing to be saved, got: %d beneficiaries", len(repo.Beneficiaries))
+				}
+				return
+			}
+
+			if !b.IsCoolingOff(time.Now()) || b.AccountID != "acc_1" {
+				t.Errorf("expected a cooling-off beneficiary of acc_1, got: %+v", b)
+			}
+		})
+	}
+}
+
+// TestBeneficiaryUseCase_Duplicates tests that an account cannot save the same destination twice.
+func TestBeneficiaryUseCase_Duplicates(t *testing.T) {
+	// Arrange
+	beneficiaryUseCase := newBeneficiaryUseCase(mocks.NewMockBeneficiaryRepository(), time.Hour)
+	if _, err := beneficiaryUseCase.AddBeneficiary("acc_1", "Mum", beneficiary.DestinationMobileWallet, "233240000001", "", "Ama Owusu"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Act
+	_, err := beneficiaryUseCase.AddBeneficiary("acc_1", "Mother", beneficiary.DestinationMobileWallet, "233240000001", "", "Ama Owusu")
+	_, otherErr := beneficiaryUseCase.AddBeneficiary("acc_3", "Auntie", beneficiary.DestinationMobileWallet, "233240000001", "", "Ama Owusu")
+
+	// Assert
+	if !errors.Is(err, beneficiary.ErrBeneficiaryAlreadyExists) {
+		t.Errorf("expected error: %v, got: %v", beneficiary.ErrBeneficiaryAlreadyExists, err)
+	}
+
+	if otherErr != nil {
+		t.Errorf("expected another account to save the destination, got: %v", otherErr)
+	}
+}
+
+// TestBeneficiaryUseCase_Authorize tests the cooling-off period and the ownership of beneficiaries.
+func TestBeneficiaryUseCase_Authorize(t *testing.T) {
+	testCases := []struct {
+		name        string
+		accountID   string
+		coolingOff  time.Duration
+		amount      float32
+		expectedErr error
+	}{
+		{name: "small amount while cooling off", accountID: "acc_1", coolingOff: time.Hour, amount: 999},
+		{name: "large amount while cooling off", accountID: "acc_1", coolingOff: time.Hour, amount: 1000, expectedErr: pkg.ErrBeneficiaryCoolingOff},
+		{name: "large amount after cooling off", accountID: "acc_1", amount: 5000},
+		{name: "another account", accountID: "acc_3", amount: 10, expectedErr: beneficiary.ErrBeneficiaryNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			beneficiaryUseCase := newBeneficiaryUseCase(mocks.NewMockBeneficiaryRepository(), tc.coolingOff)
+			b, err := beneficiaryUseCase.AddBeneficiary("acc_1", "Mum", beneficiary.DestinationMobileWallet, "233240000001", "", "Ama Owusu")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// Act
+			_, err = beneficiaryUseCase.Authorize(tc.accountID, b.ID, tc.amount)
+
+			// Assert
+			if !errors.Is(err, tc.expectedErr) {
+				t.Errorf("expected error: %v, got: %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+// TestBeneficiaryUseCase_RemoveBeneficiary tests that accounts can only remove their own beneficiaries.
+func TestBeneficiaryUseCase_RemoveBeneficiary(t *testing.T) {
+	// Arrange
+	beneficiaryUseCase := newBeneficiaryUseCase(mocks.NewMockBeneficiaryRepository(), time.Hour)
+	b, err := beneficiaryUseCase.AddBeneficiary("acc_1", "Mum", beneficiary.DestinationMobileWallet, "233240000001", "", "Ama Owusu")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Act
+	otherErr := beneficiaryUseCase.RemoveBeneficiary("acc_3", b.ID)
+	err = beneficiaryUseCase.RemoveBeneficiary("acc_1", b.ID)
+
+	// Assert
+	if !errors.Is(otherErr, beneficiary.ErrBeneficiaryNotFound) {
+		t.Errorf("expected error: %v, got: %v", beneficiary.ErrBeneficiaryNotFound, otherErr)
+	}
+
+	beneficiaries, _ := beneficiaryUseCase.GetBeneficiaries("acc_1")
+	if err != nil || len(beneficiaries) != 0 {
+		t.Errorf("expected the beneficiary to be removed, got: %d beneficiaries, %v", len(beneficiaries), err)
+	}
+}
@@ -0,0 +1,61 @@
+package mocks
+
+import (
+	"github.com/quabynah-bilson/quantia/pkg/callback"
+	"sync"
+)
+
+// MockCallbackRepository is an in-memory callback repository
+type MockCallbackRepository struct {
+	mu        sync.Mutex
+	Callbacks map[string]*callback.Callback
+	Claims    map[string]string
+}
+
+// NewMockCallbackRepository creates an empty in-memory callback repository
+func NewMockCallbackRepository() *MockCallbackRepository {
+	return &MockCallbackRepository{
+		Callbacks: make(map[string]*callback.Callback),
+		Claims:    make(map[string]string),
+	}
+}
+
+// Save saves a copy of the callback
+func (m *MockCallbackRepository) Save(cb *callback.Callback) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	copied := *cb
+	m.Callbacks[cb.ID] = &copied
+	return nil
+}
+
+// Find returns a copy of the callback
+func (m *MockCallbackRepository) Find(id string) (*callback.Callback, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cb, ok := m.Callbacks[id]
+	if !ok {
+		return nil, callback.ErrCallbackNotFound
+	}
+	copied := *cb
+	return &copied, nil
+}
+
+// Claim records the callback handling an event, unless one already does
+func (m *MockCallbackRepository) Claim(provider, eventID, callbackID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.Claims[provider+":"+eventID]; ok {
+		return callback.ErrDuplicateCallback
+	}
+	m.Claims[provider+":"+eventID] = callbackID
+	return nil
+}
+
+// Release removes the claim on an event
+func (m *MockCallbackRepository) Release(provider, eventID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.Claims, provider+":"+eventID)
+	return nil
+}
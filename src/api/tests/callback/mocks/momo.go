@@ -0,0 +1,34 @@
+package mocks
+
+import (
+	"encoding/hex"
+	"github.com/quabynah-bilson/quantia/adapters/payment/provider"
+	"github.com/quabynah-bilson/quantia/pkg"
+	"github.com/quabynah-bilson/quantia/pkg/callback"
+	"net/http"
+)
+
+// MoMoSecret is the secret the momo callbacks of MoMoProvider are signed with
+const MoMoSecret = "momo-secret"
+
+// MoMoProvider returns a momo callback provider, whose callbacks are signed with MoMoSecret in X-Signature and
+// carry their event ID in X-Event-Id
+func MoMoProvider() pkg.CallbackProvider {
+	return pkg.CallbackProvider{
+		Name:          "momo",
+		Parser:        provider.NewMoMoProvider(provider.MoMoConfig{}),
+		Verifier:      &callback.HMACVerifier{Header: "X-Signature", Secret: []byte(MoMoSecret)},
+		EventIDHeader: "X-Event-Id",
+	}
+}
+
+// SignedMoMoHeader returns the headers of a momo callback with the body's signature and the given event ID
+func SignedMoMoHeader(body, eventID string) http.Header {
+	header := http.Header{}
+	header.Set("X-Signature", hex.EncodeToString(callback.Sign([]byte(MoMoSecret), []byte(body))))
+	if eventID != "" {
+		header.Set("X-Event-Id", eventID)
+	}
+
+	return header
+}
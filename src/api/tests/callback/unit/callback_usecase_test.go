@@ -0,0 +1,192 @@
+package unit
+
+import (
+	"errors"
+	"github.com/quabynah-bilson/quantia/adapters/payment/provider"
+	"github.com/quabynah-bilson/quantia/pkg"
+	"github.com/quabynah-bilson/quantia/pkg/callback"
+	"github.com/quabynah-bilson/quantia/pkg/payment"
+	"github.com/quabynah-bilson/quantia/tests/callback/mocks"
+	ledgerMocks "github.com/quabynah-bilson/quantia/tests/ledger/mocks"
+	paymentMocks "github.com/quabynah-bilson/quantia/tests/payment/mocks"
+	"net/http"
+	"testing"
+)
+
+// testCase is a struct that represents a test case.
+type testCase struct {
+	name              string
+	provider          string
+	header            http.Header
+	body              string
+	expectedErr       error
+	expectedStatus    payment.TransactionStatus
+	expectedCbStatus  callback.Status
+	expectedCbResult  string
+	expectedTxnStatus string
+}
+
+// TestReceive tests that verified callbacks are stored and move their transaction to its new state.
+func TestReceive(t *testing.T) {
+	testCases := []testCase{
+		{
+			name:              "successful collection",
+			body:              `{"referenceId":"ref_1","externalId":"tx_1","amount":"25","status":"SUCCESSFUL"}`,
+			expectedStatus:    payment.TransactionStatusSuccess,
+			expectedCbStatus:  callback.StatusProcessed,
+			expectedCbResult:  string(payment.ProviderStatusCaptured),
+			expectedTxnStatus: string(payment.TransactionStatusSuccess),
+		},
+		{
+			name:              "rejected collection",
+			body:              `{"referenceId":"ref_1","externalId":"tx_1","amount":"25","status":"REJECTED"}`,
+			expectedStatus:    payment.TransactionStatusFailed,
+			expectedCbStatus:  callback.StatusProcessed,
+			expectedCbResult:  string(payment.ProviderStatusDeclined),
+			expectedTxnStatus: string(payment.TransactionStatusFailed),
+		},
+		{
+			name:             "unreadable payload",
+			body:             `{"status":"SUCCESSFUL"}`,
+			expectedStatus:   payment.TransactionStatusPending,
+			expectedErr:      payment.ErrInvalidCallback,
+			expectedCbStatus: callback.StatusFailed,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			paymentRepo := paymentMocks.NewMockPaymentRepository()
+			for _, id := range []string{"tx_1", "tx_2"} {
+				_ = paymentRepo.Save(&payment.Transaction{ID: id, Amount: 25, Status: payment.TransactionStatusPending, Url: "https://shop.example.com/hook"})
+			}
+			paymentUseCase := paymentMocks.NewPaymentUseCase(paymentRepo, ledgerMocks.NewMockLedgerRepository(), paymentMocks.NewSimulator(provider.SimulatorConfig{}))
+			callbackUseCase := pkg.NewCallbackUseCase(mocks.NewMockCallbackRepository(), paymentUseCase)
+			callbackUseCase.Register(mocks.MoMoProvider())
+
+			// Act
+			cb, err := callbackUseCase.Receive("momo", mocks.SignedMoMoHeader(tc.body, "evt_1"), []byte(tc.body))
+
+			// Assert
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error: %v, got: %v", tc.expectedErr, err)
+			}
+
+			stored, findErr := callbackUseCase.GetCallback(cb.ID)
+			if findErr != nil {
+				t.Fatalf("unexpected error: %v", findErr)
+			}
+			if stored.Payload != tc.body || stored.EventID != "evt_1" || stored.Status != tc.expectedCbStatus ||
+				stored.Result != tc.expectedCbResult || stored.TransactionStatus != tc.expectedTxnStatus {
+				t.Errorf("unexpected callback: %+v", stored)
+			}
+
+			if transaction, _ := paymentRepo.Find("tx_1"); transaction.Status != tc.expectedStatus {
+				t.Errorf("expected status: %s, got: %s", tc.expectedStatus, transaction.Status)
+			}
+		})
+	}
+}
+
+// TestReceive_Rejections tests that unsigned callbacks and unknown providers are refused without being stored.
+func TestReceive_Rejections(t *testing.T) {
+	body := `{"referenceId":"ref_1","externalId":"tx_1","amount":"25","status":"SUCCESSFUL"}`
+	testCases := []testCase{
+		{
+			name:        "missing signature",
+			provider:    "momo",
+			header:      http.Header{},
+			expectedErr: callback.ErrInvalidSignature,
+		},
+		{
+			name:        "signature of another body",
+			provider:    "momo",
+			header:      mocks.SignedMoMoHeader(`{}`, ""),
+			expectedErr: callback.ErrInvalidSignature,
+		},
+		{
+			name:        "unknown provider",
+			provider:    "stripe",
+			header:      mocks.SignedMoMoHeader(body, ""),
+			expectedErr: pkg.ErrUnknownCallbackProvider,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			paymentRepo := paymentMocks.NewMockPaymentRepository()
+			for _, id := range []string{"tx_1", "tx_2"} {
+				_ = paymentRepo.Save(&payment.Transaction{ID: id, Amount: 25, Status: payment.TransactionStatusPending, Url: "https://shop.example.com/hook"})
+			}
+			paymentUseCase := paymentMocks.NewPaymentUseCase(paymentRepo, ledgerMocks.NewMockLedgerRepository(), paymentMocks.NewSimulator(provider.SimulatorConfig{}))
+			callbackRepo := mocks.NewMockCallbackRepository()
+			callbackUseCase := pkg.NewCallbackUseCase(callbackRepo, paymentUseCase)
+			callbackUseCase.Register(mocks.MoMoProvider())
+
+			// Act
+			_, err := callbackUseCase.Receive(tc.provider, tc.header, []byte(body))
+
+			// Assert
+			if !errors.Is(err, tc.expectedErr) {
+				t.Errorf("expected error: %v, got: %v", tc.expectedErr, err)
+			}
+
+			if len(callbackRepo.Callbacks) != 0 {
+				t.Errorf("expected no callback to be stored, got: %d", len(callbackRepo.Callbacks))
+			}
+
+			if transaction, _ := paymentRepo.Find("tx_1"); transaction.Status != payment.TransactionStatusPending {
+				t.Errorf("expected the transaction to stay pending, got: %s", transaction.Status)
+			}
+		})
+	}
+}
+
+// TestReceive_Duplicates tests that each provider event is applied once, by event ID or by payload, and that
+// events that failed to apply are applied when they are delivered again.
+func TestReceive_Duplicates(t *testing.T) {
+	// Arrange
+	paymentRepo := paymentMocks.NewMockPaymentRepository()
+	for _, id := range []string{"tx_1", "tx_2"} {
+		_ = paymentRepo.Save(&payment.Transaction{ID: id, Amount: 25, Status: payment.TransactionStatusPending, Url: "https://shop.example.com/hook"})
+	}
+	paymentUseCase := paymentMocks.NewPaymentUseCase(paymentRepo, ledgerMocks.NewMockLedgerRepository(), paymentMocks.NewSimulator(provider.SimulatorConfig{}))
+	callbackUseCase := pkg.NewCallbackUseCase(mocks.NewMockCallbackRepository(), paymentUseCase)
+	callbackUseCase.Register(mocks.MoMoProvider())
+	body := `{"referenceId":"ref_1","externalId":"tx_1","amount":"25","status":"SUCCESSFUL"}`
+	if _, err := callbackUseCase.Receive("momo", mocks.SignedMoMoHeader(body, "evt_1"), []byte(body)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Act
+	other := `{"referenceId":"ref_2","externalId":"tx_2","amount":"25","status":"SUCCESSFUL"}`
+	_, byEventErr := callbackUseCase.Receive("momo", mocks.SignedMoMoHeader(other, "evt_1"), []byte(other))
+	_, firstErr := callbackUseCase.Receive("momo", mocks.SignedMoMoHeader(body, ""), []byte(body))
+	_, byPayloadErr := callbackUseCase.Receive("momo", mocks.SignedMoMoHeader(body, ""), []byte(body))
+
+	unknown := `{"referenceId":"ref_3","externalId":"tx_3","amount":"25","status":"SUCCESSFUL"}`
+	_, unknownErr := callbackUseCase.Receive("momo", mocks.SignedMoMoHeader(unknown, "evt_3"), []byte(unknown))
+	_ = paymentRepo.Save(&payment.Transaction{ID: "tx_3", Amount: 25, Status: payment.TransactionStatusPending, Url: "https://shop.example.com/hook"})
+	_, retryErr := callbackUseCase.Receive("momo", mocks.SignedMoMoHeader(unknown, "evt_3"), []byte(unknown))
+
+	// Assert
+	if !errors.Is(byEventErr, callback.ErrDuplicateCallback) {
+		t.Errorf("expected a repeated event ID to be a duplicate, got: %v", byEventErr)
+	}
+	if transaction, _ := paymentRepo.Find("tx_2"); transaction.Status != payment.TransactionStatusPending {
+		t.Errorf("expected tx_2 to stay pending, got: %s", transaction.Status)
+	}
+
+	if firstErr != nil || !errors.Is(byPayloadErr, callback.ErrDuplicateCallback) {
+		t.Errorf("expected a repeated payload without event ID to be a duplicate, got: %v then %v", firstErr, byPayloadErr)
+	}
+
+	if !errors.Is(unknownErr, payment.ErrTransactionNotFound) || retryErr != nil {
+		t.Errorf("expected the retry of a failed callback to be applied, got: %v then %v", unknownErr, retryErr)
+	}
+	if transaction, _ := paymentRepo.Find("tx_3"); transaction.Status != payment.TransactionStatusSuccess {
+		t.Errorf("expected tx_3 to succeed, got: %s", transaction.Status)
+	}
+}
@@ -0,0 +1,50 @@
+package unit
+
+import (
+	"encoding/hex"
+	"errors"
+	"github.com/quabynah-bilson/quantia/pkg/callback"
+	"net/http"
+	"testing"
+)
+
+// TestHMACVerifier tests that only bodies signed with the shared secret are accepted.
+func TestHMACVerifier(t *testing.T) {
+	body := []byte(`{"externalId":"tx_1","status":"SUCCESSFUL"}`)
+	signature := hex.EncodeToString(callback.Sign([]byte("secret"), body))
+
+	testCases := []struct {
+		name        string
+		secret      string
+		signature   string
+		body        []byte
+		expectedErr error
+	}{
+		{name: "valid signature", secret: "secret", signature: signature, body: body},
+		{name: "valid signature with prefix", secret: "secret", signature: "sha256=" + signature, body: body},
+		{name: "tampered body", secret: "secret", signature: signature, body: []byte(`{"externalId":"tx_2","status":"SUCCESSFUL"}`), expectedErr: callback.ErrInvalidSignature},
+		{name: "other secret", secret: "other", signature: signature, body: body, expectedErr: callback.ErrInvalidSignature},
+		{name: "missing signature", secret: "secret", body: body, expectedErr: callback.ErrInvalidSignature},
+		{name: "signature that is not hex", secret: "secret", signature: "not-hex", body: body, expectedErr: callback.ErrInvalidSignature},
+		{name: "no secret", signature: hex.EncodeToString(callback.Sign(nil, body)), body: body, expectedErr: callback.ErrInvalidSignature},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			verifier := &callback.HMACVerifier{Header: "X-Signature", Secret: []byte(tc.secret)}
+			header := http.Header{}
+			if tc.signature != "" {
+				header.Set("X-Signature", tc.signature)
+			}
+
+			// Act
+			err := verifier.Verify(header, tc.body)
+
+			// Assert
+			if !errors.Is(err, tc.expectedErr) {
+				t.Errorf("expected error: %v, got: %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package mocks
+
+import (
+	"github.com/quabynah-bilson/quantia/pkg/card"
+	"sort"
+	"sync"
+)
+
+// MockCardRepository is an in-memory card repository, vault entries included
+type MockCardRepository struct {
+	mu      sync.Mutex
+	Cards   map[string]*card.Card
+	Charges map[string]*card.Authorization
+	Entries map[string]*card.VaultEntry
+	tokens  map[string]string
+}
+
+// NewMockCardRepository creates an empty in-memory card repository
+func NewMockCardRepository() *MockCardRepository {
+	return &MockCardRepository{
+		Cards:   make(map[string]*card.Card),
+		Charges: make(map[string]*card.Authorization),
+		Entries: make(map[string]*card.VaultEntry),
+		tokens:  make(map[string]string),
+	}
+}
+
+// Save saves a copy of the card
+func (m *MockCardRepository) Save(c *card.Card) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Cards[c.ID] = copyCard(c)
+	return nil
+}
+
+// Find returns a copy of the card
+func (m *MockCardRepository) Find(id string) (*card.Card, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c, ok := m.Cards[id]
+	if !ok {
+		return nil, card.ErrCardNotFound
+	}
+	return copyCard(c), nil
+}
+
+// FindByToken returns a copy of the card of the token
+func (m *MockCardRepository) FindByToken(token string) (*card.Card, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, c := range m.Cards {
+		if c.Token == token {
+			return copyCard(c), nil
+		}
+	}
+	return nil, card.ErrCardNotFound
+}
+
+// FindByAccount returns copies of the cards of the account, oldest first
+func (m *MockCardRepository) FindByAccount(accountID string) ([]*card.Card, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cards := []*card.Card{}
+	for _, c := range m.Cards {
+		if c.AccountID == accountID {
+			cards = append(cards, copyCard(c))
+		}
+	}
+	sort.Slice(cards, func(i, j int) bool { return cards[i].CreatedAt.Before(cards[j].CreatedAt) })
+	return cards, nil
+}
+
+// SaveAuthorization saves a copy of the authorization
+func (m *MockCardRepository) SaveAuthorization(authorization *card.Authorization) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	copied := *authorization
+	m.Charges[authorization.ID] = &copied
+	return nil
+}
+
+// Authorizations returns copies of the authorizations of the card, newest first
+func (m *MockCardRepository) Authorizations(cardID string) ([]*card.Authorization, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	authorizations := []*card.Authorization{}
+	for _, a := range m.Charges {
+		if a.CardID == cardID {
+			copied := *a
+			authorizations = append(authorizations, &copied)
+		}
+	}
+	sort.Slice(authorizations, func(i, j int) bool { return authorizations[i].CreatedAt.After(authorizations[j].CreatedAt) })
+	return authorizations, nil
+}
+
+// SaveSecret stores the vault entry unless its fingerprint is already stored
+func (m *MockCardRepository) SaveSecret(entry *card.VaultEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.tokens[entry.Fingerprint]; ok {
+		return card.ErrDuplicatePAN
+	}
+	copied := *entry
+	m.Entries[entry.Token] = &copied
+	m.tokens[entry.Fingerprint] = entry.Token
+	return nil
+}
+
+// FindSecret returns a copy of the vault entry of the token
+func (m *MockCardRepository) FindSecret(token string) (*card.VaultEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, ok := m.Entries[token]
+	if !ok {
+		return nil, card.ErrSecretNotFound
+	}
+	copied := *entry
+	return &copied, nil
+}
+
+// FindToken returns the token of the fingerprint
+func (m *MockCardRepository) FindToken(fingerprint string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	token, ok := m.tokens[fingerprint]
+	if !ok {
+		return "", card.ErrSecretNotFound
+	}
+	return token, nil
+}
+
+// copyCard copies a card and its blocked categories
+func copyCard(c *card.Card) *card.Card {
+	copied := *c
+	copied.Controls.BlockedCategories = append([]string(nil), c.Controls.BlockedCategories...)
+	return &copied
+}
@@ -0,0 +1,34 @@
+package mocks
+
+import (
+	"github.com/quabynah-bilson/quantia/pkg"
+	"github.com/quabynah-bilson/quantia/pkg/card"
+	"github.com/quabynah-bilson/quantia/pkg/ledger"
+	limitMocks "github.com/quabynah-bilson/quantia/tests/limit/mocks"
+)
+
+// NewCardUseCase creates a card use case issuing cards in the 400000-400099 range, whose vault is sealed with
+// the key
+func NewCardUseCase(cardRepo *MockCardRepository, ledgerRepo ledger.Repository, key []byte) (*pkg.CardUseCase, error) {
+	vault, err := card.NewVault(cardRepo, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return pkg.NewCardUseCase(cardRepo, ledgerRepo, limitMocks.NewMockLimitRepository(), vault, pkg.CardConfig{
+		BINs: card.BINRange{Low: "400000", High: "400099"},
+	}), nil
+}
+
+// NewAuthorizationRequest returns a valid authorization request of the amount at a coffee shop on the issued card
+func NewAuthorizationRequest(issued *pkg.IssuedCard, amount float32) *card.AuthorizationRequest {
+	return &card.AuthorizationRequest{
+		PAN:         issued.PAN,
+		CVV:         issued.CVV,
+		ExpiryMonth: issued.Card.ExpiryMonth,
+		ExpiryYear:  issued.Card.ExpiryYear,
+		Amount:      amount,
+		Merchant:    "Coffee Shop",
+		Category:    "5814",
+	}
+}
@@ -0,0 +1,155 @@
+package unit
+
+import (
+	"bytes"
+	"errors"
+	"github.com/quabynah-bilson/quantia/pkg/card"
+	"github.com/quabynah-bilson/quantia/tests/card/mocks"
+	"strings"
+	"testing"
+)
+
+// vaultKey is the master key of the vaults of the tests
+var vaultKey = bytes.Repeat([]byte{7}, 32)
+
+// TestLuhnValid tests the Luhn check on known card numbers.
+func TestLuhnValid(t *testing.T) {
+	testCases := []struct {
+		pan      string
+		expected bool
+	}{
+		{pan: "4111111111111111", expected: true},
+		{pan: "5500005555555559", expected: true},
+		{pan: "4111111111111112", expected: false},
+		{pan: "4111-1111-1111-1111", expected: false},
+		{pan: "", expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.pan, func(t *testing.T) {
+			if valid := card.LuhnValid(tc.pan); valid != tc.expected {
+				t.Errorf("expected valid: %v, got: %v", tc.expected, valid)
+			}
+		})
+	}
+}
+
+// TestGeneratePAN tests that generated card numbers pass the Luhn check and sit in the BIN range.
+func TestGeneratePAN(t *testing.T) {
+	testCases := []struct {
+		name        string
+		bins        string
+		length      int
+		expectedErr error
+	}{
+		{name: "single BIN", bins: "400000", length: 16},
+		{name: "range of BINs", bins: "45000000-45000099", length: 16},
+		{name: "19 digits", bins: "222100-272099", length: 19},
+		{name: "too short", bins: "400000", length: 11, expectedErr: card.ErrInvalidBINRange},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			bins, err := card.ParseBINRange(tc.bins)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for i := 0; i < 50; i++ {
+				// Act
+				pan, err := card.GeneratePAN(bins, tc.length)
+
+				// Assert
+				if !errors.Is(err, tc.expectedErr) {
+					t.Fatalf("expected error: %v, got: %v", tc.expectedErr, err)
+				}
+				if err != nil {
+					return
+				}
+
+				if len(pan) != tc.length || !card.LuhnValid(pan) || !bins.Contains(pan) {
+					t.Fatalf("unexpected card number: %s", pan)
+				}
+			}
+		})
+	}
+}
+
+// TestParseBINRange tests that only ranges of 6 or 8 digits bounds in order are accepted.
+func TestParseBINRange(t *testing.T) {
+	testCases := []struct {
+		raw         string
+		expected    card.BINRange
+		expectedErr error
+	}{
+		{raw: "400000-400099", expected: card.BINRange{Low: "400000", High: "400099"}},
+		{raw: " 45000000 ", expected: card.BINRange{Low: "45000000", High: "45000000"}},
+		{raw: "400099-400000", expectedErr: card.ErrInvalidBINRange},
+		{raw: "400000-4000000", expectedErr: card.ErrInvalidBINRange},
+		{raw: "40000a", expectedErr: card.ErrInvalidBINRange},
+		{raw: "", expectedErr: card.ErrInvalidBINRange},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.raw, func(t *testing.T) {
+			bins, err := card.ParseBINRange(tc.raw)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error: %v, got: %v", tc.expectedErr, err)
+			}
+
+			if bins != tc.expected {
+				t.Errorf("expected range: %+v, got: %+v", tc.expected, bins)
+			}
+		})
+	}
+}
+
+// TestVault tests that the vault only stores card numbers encrypted and gives them back from their token.
+func TestVault(t *testing.T) {
+	// Arrange
+	repo := mocks.NewMockCardRepository()
+	vault, err := card.NewVault(repo, vaultKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	secret := &card.Secret{PAN: "4000001234567899", CVV: "123"}
+
+	// Act
+	token, err := vault.Tokenize(secret)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	entry := repo.Entries[token]
+	if entry == nil || bytes.Contains(entry.Ciphertext, []byte(secret.PAN)) || strings.Contains(entry.Fingerprint, secret.PAN) {
+		t.Fatalf("expected the card number to be stored encrypted, got: %+v", entry)
+	}
+
+	if found, err := vault.Lookup(secret.PAN); err != nil || found != token {
+		t.Errorf("expected the lookup to find token %s, got: %s (%v)", token, found, err)
+	}
+
+	if revealed, err := vault.Detokenize(token); err != nil || *revealed != *secret {
+		t.Errorf("expected the secret back, got: %+v (%v)", revealed, err)
+	}
+
+	if _, err = vault.Tokenize(secret); !errors.Is(err, card.ErrDuplicatePAN) {
+		t.Errorf("expected error: %v, got: %v", card.ErrDuplicatePAN, err)
+	}
+
+	// a vault with another key can neither find nor open the entry
+	other, _ := card.NewVault(repo, bytes.Repeat([]byte{8}, 32))
+	if _, err = other.Lookup(secret.PAN); !errors.Is(err, card.ErrSecretNotFound) {
+		t.Errorf("expected error: %v, got: %v", card.ErrSecretNotFound, err)
+	}
+	if _, err = other.Detokenize(token); !errors.Is(err, card.ErrSecretNotFound) {
+		t.Errorf("expected error: %v, got: %v", card.ErrSecretNotFound, err)
+	}
+
+	if _, err = card.NewVault(repo, []byte("short")); !errors.Is(err, card.ErrInvalidVaultKey) {
+		t.Errorf("expected error: %v, got: %v", card.ErrInvalidVaultKey, err)
+	}
+}
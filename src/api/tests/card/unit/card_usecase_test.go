@@ -0,0 +1,346 @@
+package unit
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/quabynah-bilson/quantia/pkg"
+	"github.com/quabynah-bilson/quantia/pkg/card"
+	"github.com/quabynah-bilson/quantia/pkg/ledger"
+	"github.com/quabynah-bilson/quantia/pkg/limit"
+	"github.com/quabynah-bilson/quantia/tests/card/mocks"
+	ledgerMocks "github.com/quabynah-bilson/quantia/tests/ledger/mocks"
+	"strings"
+	"testing"
+)
+
+// testCase is a struct that represents a test case.
+type testCase struct {
+	name            string
+	amount          float32
+	category        string
+	wrongCVV        bool
+	expiryShift     int
+	frozen          bool
+	drained         float32
+	wrongDetails    []bool
+	expectedErr     error
+	expectedErrs    []error
+	expectedStatus  card.AuthorizationStatus
+	expectedCard    card.Status
+	expectedBalance float32
+}
+
+// wrongCVV shifts every digit of the CVV so that it always differs from the issued one
+func wrongCVV(cvv string) string {
+	return strings.Map(func(r rune) rune { return '0' + (r-'0'+1)%10 }, cvv)
+}
+
+// TestIssue tests that cards get a valid number in the range and only keep its token and last four digits.
+func TestIssue(t *testing.T) {
+	// Arrange
+	cardRepo := mocks.NewMockCardRepository()
+	cardUseCase, err := mocks.NewCardUseCase(cardRepo, ledgerMocks.NewMockLedgerRepository(), vaultKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Act
+	issued, err := cardUseCase.Issue("acc_1", card.Controls{Limits: limit.Limits{DailyAmount: 50}})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	c := issued.Card
+	if len(issued.PAN) != 16 || !card.LuhnValid(issued.PAN) || !strings.HasPrefix(issued.PAN, "4000") || len(issued.CVV) != 3 {
+		t.Errorf("unexpected card details: %s %s", issued.PAN, issued.CVV)
+	}
+
+	if c.Status != card.StatusActive || c.Last4 != issued.PAN[12:] || c.Token == "" || c.IsExpired(c.CreatedAt) {
+		t.Errorf("unexpected card: %+v", c)
+	}
+
+	stored, _ := json.Marshal(cardRepo.Cards[c.ID])
+	if strings.Contains(string(stored), issued.PAN) {
+		t.Errorf("expected the card record not to hold the PAN, got: %s", stored)
+	}
+
+	if _, err = cardUseCase.Issue(" ", card.Controls{}); !errors.Is(err, pkg.ErrInvalidCard) {
+		t.Errorf("expected error: %v, got: %v", pkg.ErrInvalidCard, err)
+	}
+
+	if _, err = cardUseCase.Issue("acc_1", card.Controls{Limits: limit.Limits{PerTransaction: -1}}); !errors.Is(err, pkg.ErrInvalidCard) {
+		t.Errorf("expected error: %v, got: %v", pkg.ErrInvalidCard, err)
+	}
+}
+
+// TestAuthorize tests that charges are approved and debited only when the card, its details, its controls and
+// the account's balance allow them.
+func TestAuthorize(t *testing.T) {
+	testCases := []testCase{
+		{
+			name:            "approved",
+			amount:          30,
+			category:        "5814",
+			expectedStatus:  card.AuthorizationApproved,
+			expectedBalance: 70,
+		},
+		{
+			name:            "wrong CVV",
+			amount:          30,
+			category:        "5814",
+			wrongCVV:        true,
+			expectedErr:     pkg.ErrInvalidCardDetails,
+			expectedStatus:  card.AuthorizationDeclined,
+			expectedBalance: 100,
+		},
+		{
+			name:            "wrong expiry",
+			amount:          30,
+			category:        "5814",
+			expiryShift:     1,
+			expectedErr:     pkg.ErrInvalidCardDetails,
+			expectedStatus:  card.AuthorizationDeclined,
+			expectedBalance: 100,
+		},
+		{
+			name:            "frozen card",
+			amount:          30,
+			category:        "5814",
+			frozen:          true,
+			expectedErr:     pkg.ErrCardFrozen,
+			expectedStatus:  card.AuthorizationDeclined,
+			expectedBalance: 100,
+		},
+		{
+			name:            "blocked category",
+			amount:          30,
+			category:        "7995",
+			expectedErr:     pkg.ErrCategoryBlocked,
+			expectedStatus:  card.AuthorizationDeclined,
+			expectedBalance: 100,
+		},
+		{
+			name:            "over the per transaction limit",
+			amount:          60,
+			category:        "5814",
+			expectedErr:     limit.ErrLimitExceeded,
+			expectedStatus:  card.AuthorizationDeclined,
+			expectedBalance: 100,
+		},
+		{
+			name:            "insufficient funds",
+			amount:          30,
+			category:        "5814",
+			drained:         90,
+			expectedErr:     ledger.ErrInsufficientFunds,
+			expectedStatus:  card.AuthorizationDeclined,
+			expectedBalance: 10,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			ledgerRepo := ledgerMocks.NewMockLedgerRepository()
+			ledgerRepo.Fund("acc_1", 100)
+			cardUseCase, err := mocks.NewCardUseCase(mocks.NewMockCardRepository(), ledgerRepo, vaultKey)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			issued, err := cardUseCase.Issue("acc_1", card.Controls{Limits: limit.Limits{PerTransaction: 50}, BlockedCategories: []string{"7995"}})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if tc.frozen {
+				if _, err = cardUseCase.Freeze(issued.Card.ID); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			if tc.drained > 0 {
+				if err = ledgerRepo.Post(ledger.NewTransfer("drain", "drain", "acc_1", "system:funding", tc.drained)...); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			req := mocks.NewAuthorizationRequest(issued, tc.amount)
+			req.Category = tc.category
+			req.ExpiryYear += tc.expiryShift
+			if tc.wrongCVV {
+				req.CVV = wrongCVV(issued.CVV)
+			}
+
+			// Act
+			a, err := cardUseCase.Authorize(req)
+
+			// Assert
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error: %v, got: %v", tc.expectedErr, err)
+			}
+
+			if a == nil || a.Status != tc.expectedStatus || a.CardID != issued.Card.ID || a.AccountID != "acc_1" {
+				t.Fatalf("unexpected authorization: %+v", a)
+			}
+
+			if current, available := ledgerRepo.Current("acc_1"), ledgerRepo.Available("acc_1"); current != tc.expectedBalance || available != tc.expectedBalance {
+				t.Errorf("expected balance: %.2f, got: %.2f/%.2f", tc.expectedBalance, current, available)
+			}
+
+			if authorizations, _ := cardUseCase.GetAuthorizations(issued.Card.ID); len(authorizations) != 1 || authorizations[0].Status != tc.expectedStatus {
+				t.Errorf("expected the authorization to be recorded, got: %+v", authorizations)
+			}
+		})
+	}
+}
+
+// TestAuthorize_FailedAttempts tests that a card is frozen after three charges in a row with wrong details, and
+// that right details start the count again.
+func TestAuthorize_FailedAttempts(t *testing.T) {
+	testCases := []testCase{
+		{
+			name:         "frozen after three wrong CVVs",
+			wrongDetails: []bool{true, true, true, false},
+			expectedErrs: []error{pkg.ErrInvalidCardDetails, pkg.ErrInvalidCardDetails, pkg.ErrInvalidCardDetails, pkg.ErrCardFrozen},
+			expectedCard: card.StatusFrozen,
+		},
+		{
+			name:         "count started again by right details",
+			wrongDetails: []bool{true, true, false, true, true},
+			expectedErrs: []error{pkg.ErrInvalidCardDetails, pkg.ErrInvalidCardDetails, nil, pkg.ErrInvalidCardDetails, pkg.ErrInvalidCardDetails},
+			expectedCard: card.StatusActive,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			ledgerRepo := ledgerMocks.NewMockLedgerRepository()
+			ledgerRepo.Fund("acc_1", 100)
+			cardUseCase, err := mocks.NewCardUseCase(mocks.NewMockCardRepository(), ledgerRepo, vaultKey)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			issued, err := cardUseCase.Issue("acc_1", card.Controls{})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// Act
+			var errs []error
+			for _, wrong := range tc.wrongDetails {
+				req := mocks.NewAuthorizationRequest(issued, 10)
+				if wrong {
+					req.CVV = wrongCVV(issued.CVV)
+				}
+				_, err := cardUseCase.Authorize(req)
+				errs = append(errs, err)
+			}
+
+			// Assert
+			for i, err := range errs {
+				if !errors.Is(err, tc.expectedErrs[i]) {
+					t.Errorf("expected error %d: %v, got: %v", i, tc.expectedErrs[i], err)
+				}
+			}
+
+			if c, _ := cardUseCase.GetCard(issued.Card.ID); c.Status != tc.expectedCard {
+				t.Errorf("expected status: %s, got: %s", tc.expectedCard, c.Status)
+			}
+		})
+	}
+}
+
+// TestAuthorizeUnknownCard tests that numbers the vault does not hold are refused without an authorization.
+func TestAuthorizeUnknownCard(t *testing.T) {
+	// Arrange
+	ledgerRepo := ledgerMocks.NewMockLedgerRepository()
+	ledgerRepo.Fund("acc_1", 100)
+	cardUseCase, err := mocks.NewCardUseCase(mocks.NewMockCardRepository(), ledgerRepo, vaultKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	issued, err := cardUseCase.Issue("acc_1", card.Controls{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Act & Assert
+	for _, pan := range []string{"4000001234567899", "4111111111111111", issued.PAN[:15] + "x"} {
+		req := mocks.NewAuthorizationRequest(issued, 10)
+		req.PAN = pan
+
+		if a, err := cardUseCase.Authorize(req); a != nil || !errors.Is(err, card.ErrCardNotFound) {
+			t.Errorf("expected error: %v, got: %v (%+v)", card.ErrCardNotFound, err, a)
+		}
+	}
+
+	req := mocks.NewAuthorizationRequest(issued, 10)
+	req.Merchant = ""
+	if _, err := cardUseCase.Authorize(req); !errors.Is(err, pkg.ErrInvalidAuthorization) {
+		t.Errorf("expected error: %v, got: %v", pkg.ErrInvalidAuthorization, err)
+	}
+}
+
+// TestSpendControls tests that daily spend is counted per card, that declined charges do not count, and that
+// unfrozen cards can be charged again.
+func TestSpendControls(t *testing.T) {
+	// Arrange
+	ledgerRepo := ledgerMocks.NewMockLedgerRepository()
+	ledgerRepo.Fund("acc_1", 100)
+	cardUseCase, err := mocks.NewCardUseCase(mocks.NewMockCardRepository(), ledgerRepo, vaultKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var cards []*pkg.IssuedCard
+	for i := 0; i < 2; i++ {
+		issued, err := cardUseCase.Issue("acc_1", card.Controls{Limits: limit.Limits{DailyAmount: 40}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		cards = append(cards, issued)
+	}
+	issued, other := cards[0], cards[1]
+
+	// Act & Assert
+	if _, err := cardUseCase.Authorize(mocks.NewAuthorizationRequest(issued, 25)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the second card has its own allowance
+	if _, err := cardUseCase.Authorize(mocks.NewAuthorizationRequest(other, 25)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var exceeded *limit.ExceededError
+	if _, err := cardUseCase.Authorize(mocks.NewAuthorizationRequest(issued, 20)); !errors.As(err, &exceeded) || exceeded.Kind != limit.KindDailyAmount {
+		t.Fatalf("expected the daily amount to be exceeded, got: %v", err)
+	}
+
+	if _, err := cardUseCase.Freeze(issued.Card.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := cardUseCase.Authorize(mocks.NewAuthorizationRequest(issued, 10)); !errors.Is(err, pkg.ErrCardFrozen) {
+		t.Fatalf("expected error: %v, got: %v", pkg.ErrCardFrozen, err)
+	}
+
+	if _, err := cardUseCase.Unfreeze(issued.Card.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := cardUseCase.SetControls(issued.Card.ID, card.Controls{Limits: limit.Limits{DailyAmount: 100}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := cardUseCase.Authorize(mocks.NewAuthorizationRequest(issued, 20)); err != nil {
+		t.Fatalf("expected the raised limit to allow the charge, got: %v", err)
+	}
+
+	if current := ledgerRepo.Current(ledger.CardSettlementAccountID); current != 70 {
+		t.Errorf("expected the settlement account to hold 70, got: %.2f", current)
+	}
+}
@@ -0,0 +1,83 @@
+package mocks
+
+import (
+	"github.com/quabynah-bilson/quantia/pkg/dispute"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MockDisputeRepository is an in-memory dispute repository
+type MockDisputeRepository struct {
+	mu       sync.Mutex
+	Disputes map[string]*dispute.Dispute
+}
+
+// NewMockDisputeRepository creates an empty in-memory dispute repository
+func NewMockDisputeRepository() *MockDisputeRepository {
+	return &MockDisputeRepository{Disputes: make(map[string]*dispute.Dispute)}
+}
+
+// Save saves a copy of the dispute
+func (m *MockDisputeRepository) Save(d *dispute.Dispute) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Disputes[d.ID] = copyDispute(d)
+	return nil
+}
+
+// Find returns a copy of the dispute
+func (m *MockDisputeRepository) Find(id string) (*dispute.Dispute, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	d, ok := m.Disputes[id]
+	if !ok {
+		return nil, dispute.ErrDisputeNotFound
+	}
+	return copyDispute(d), nil
+}
+
+// FindByTransaction returns copies of the disputes of the transaction, oldest first
+func (m *MockDisputeRepository) FindByTransaction(transactionID string) ([]*dispute.Dispute, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	disputes := []*dispute.Dispute{}
+	for _, d := range m.Disputes {
+		if d.TransactionID == transactionID {
+			disputes = append(disputes, copyDispute(d))
+		}
+	}
+	sort.Slice(disputes, func(i, j int) bool { return disputes[i].CreatedAt.Before(disputes[j].CreatedAt) })
+	return disputes, nil
+}
+
+// Overdue returns the disputes awaiting evidence past their deadline, earliest deadline first
+func (m *MockDisputeRepository) Overdue(now time.Time, limit int) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var overdue []*dispute.Dispute
+	for _, d := range m.Disputes {
+		if d.AwaitsEvidence() && !d.DueBy.After(now) {
+			overdue = append(overdue, d)
+		}
+	}
+	sort.Slice(overdue, func(i, j int) bool { return overdue[i].DueBy.Before(*overdue[j].DueBy) })
+
+	var ids []string
+	for _, d := range overdue {
+		if len(ids) == limit {
+			break
+		}
+		ids = append(ids, d.ID)
+	}
+	return ids, nil
+}
+
+// copyDispute copies a dispute and its evidence
+func copyDispute(d *dispute.Dispute) *dispute.Dispute {
+	copied := *d
+	copied.Evidence = append([]dispute.Evidence{}, d.Evidence...)
+	return &copied
+}
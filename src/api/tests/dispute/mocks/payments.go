@@ -0,0 +1,18 @@
+package mocks
+
+import (
+	"github.com/quabynah-bilson/quantia/pkg/payment"
+	paymentMocks "github.com/quabynah-bilson/quantia/tests/payment/mocks"
+)
+
+// Merchant is the ledger account of the merchant paid by the transactions of NewPaymentRepository
+const Merchant = "merchant:shop.example.com"
+
+// NewPaymentRepository creates a payment repository holding the successful payment tx_1 of 80 and the pending
+// payment tx_2, both paid to Merchant
+func NewPaymentRepository() *paymentMocks.MockPaymentRepository {
+	paymentRepo := paymentMocks.NewMockPaymentRepository()
+	_ = paymentRepo.Save(&payment.Transaction{ID: "tx_1", Amount: 80, Status: payment.TransactionStatusSuccess, Url: "https://shop.example.com/hook"})
+	_ = paymentRepo.Save(&payment.Transaction{ID: "tx_2", Amount: 80, Status: payment.TransactionStatusPending, Url: "https://shop.example.com/hook"})
+	return paymentRepo
+}
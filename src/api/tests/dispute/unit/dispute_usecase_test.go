@@ -0,0 +1,277 @@
+package unit
+
+import (
+	"errors"
+	"github.com/quabynah-bilson/quantia/pkg"
+	"github.com/quabynah-bilson/quantia/pkg/dispute"
+	"github.com/quabynah-bilson/quantia/pkg/event"
+	"github.com/quabynah-bilson/quantia/pkg/ledger"
+	"github.com/quabynah-bilson/quantia/pkg/payment"
+	"github.com/quabynah-bilson/quantia/tests/dispute/mocks"
+	ledgerMocks "github.com/quabynah-bilson/quantia/tests/ledger/mocks"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// testCase is a struct that represents a test case.
+type testCase struct {
+	name              string
+	transactionID     string
+	amount            float32
+	reason            string
+	balance           float32
+	outcome           dispute.Status
+	expectedErr       error
+	expectedCurrent   float32
+	expectedAvailable float32
+	expectedEvent     event.Type
+}
+
+// TestOpenDispute tests that disputes are opened on successful payments only, holding the disputed amount.
+func TestOpenDispute(t *testing.T) {
+	testCases := []testCase{
+		{
+			name:              "partial dispute",
+			transactionID:     "tx_1",
+			amount:            30,
+			reason:            "fraudulent",
+			expectedAvailable: 70,
+		},
+		{
+			name:              "more than was paid",
+			transactionID:     "tx_1",
+			amount:            81,
+			reason:            "fraudulent",
+			expectedErr:       pkg.ErrInvalidDispute,
+			expectedAvailable: 100,
+		},
+		{
+			name:              "no reason",
+			transactionID:     "tx_1",
+			amount:            30,
+			reason:            " ",
+			expectedErr:       pkg.ErrInvalidDispute,
+			expectedAvailable: 100,
+		},
+		{
+			name:              "pending payment",
+			transactionID:     "tx_2",
+			amount:            30,
+			reason:            "fraudulent",
+			expectedErr:       pkg.ErrInvalidDispute,
+			expectedAvailable: 100,
+		},
+		{
+			name:              "unknown payment",
+			transactionID:     "tx_3",
+			amount:            30,
+			reason:            "fraudulent",
+			expectedErr:       payment.ErrTransactionNotFound,
+			expectedAvailable: 100,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			paymentRepo := mocks.NewPaymentRepository()
+			ledgerRepo := ledgerMocks.NewMockLedgerRepository()
+			ledgerRepo.Fund(mocks.Merchant, 100)
+			disputeUseCase := pkg.NewDisputeUseCase(mocks.NewMockDisputeRepository(), ledgerRepo, paymentRepo, pkg.DisputeConfig{})
+
+			// Act
+			d, err := disputeUseCase.OpenDispute(tc.transactionID, tc.amount, tc.reason, "case_1")
+
+			// Assert
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error: %v, got: %v", tc.expectedErr, err)
+			}
+
+			if available := ledgerRepo.Available(mocks.Merchant); available != tc.expectedAvailable {
+				t.Errorf("expected available balance: %.2f, got: %.2f", tc.expectedAvailable, available)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if d.Status != dispute.StatusOpened || d.HoldID == "" || d.Url != "https://shop.example.com/hook" {
+				t.Errorf("unexpected dispute: %+v", d)
+			}
+
+			if types := paymentRepo.EventTypes(); !reflect.DeepEqual(types, []event.Type{event.TypeDisputeOpened}) {
+				t.Errorf("expected a dispute.opened event, got: %v", types)
+			}
+
+			if _, err = disputeUseCase.OpenDispute(tc.transactionID, 10, "duplicate", ""); !errors.Is(err, pkg.ErrDisputeExists) {
+				t.Errorf("expected error: %v, got: %v", pkg.ErrDisputeExists, err)
+			}
+		})
+	}
+}
+
+// TestResolve tests that won disputes release the held amount and lost ones charge it back, even without a hold.
+func TestResolve(t *testing.T) {
+	testCases := []testCase{
+		{
+			name:              "won",
+			balance:           100,
+			outcome:           dispute.StatusWon,
+			expectedCurrent:   100,
+			expectedAvailable: 100,
+			expectedEvent:     event.TypeDisputeWon,
+		},
+		{
+			name:              "lost",
+			balance:           100,
+			outcome:           dispute.StatusLost,
+			expectedCurrent:   70,
+			expectedAvailable: 70,
+			expectedEvent:     event.TypeDisputeLost,
+		},
+		{
+			name:              "lost without a hold",
+			balance:           10,
+			outcome:           dispute.StatusLost,
+			expectedCurrent:   -20,
+			expectedAvailable: -20,
+			expectedEvent:     event.TypeDisputeLost,
+		},
+		{
+			name:              "invalid outcome",
+			balance:           100,
+			outcome:           dispute.StatusOpened,
+			expectedErr:       pkg.ErrInvalidDisputeOutcome,
+			expectedCurrent:   100,
+			expectedAvailable: 70,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			paymentRepo := mocks.NewPaymentRepository()
+			ledgerRepo := ledgerMocks.NewMockLedgerRepository()
+			ledgerRepo.Fund(mocks.Merchant, tc.balance)
+			disputeUseCase := pkg.NewDisputeUseCase(mocks.NewMockDisputeRepository(), ledgerRepo, paymentRepo, pkg.DisputeConfig{})
+			d, err := disputeUseCase.OpenDispute("tx_1", 30, "fraudulent", "")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// Act
+			resolved, err := disputeUseCase.Resolve(d.ID, tc.outcome, "decided by the scheme")
+
+			// Assert
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error: %v, got: %v", tc.expectedErr, err)
+			}
+
+			if current, available := ledgerRepo.Current(mocks.Merchant), ledgerRepo.Available(mocks.Merchant); current != tc.expectedCurrent || available != tc.expectedAvailable {
+				t.Errorf("expected balance: %.2f/%.2f, got: %.2f/%.2f", tc.expectedCurrent, tc.expectedAvailable, current, available)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if resolved.Status != tc.outcome || resolved.ResolvedAt == nil || resolved.Outcome != "decided by the scheme" {
+				t.Errorf("unexpected dispute: %+v", resolved)
+			}
+
+			if types := paymentRepo.EventTypes(); types[len(types)-1] != tc.expectedEvent {
+				t.Errorf("expected a %s event, got: %v", tc.expectedEvent, types)
+			}
+
+			if _, err = disputeUseCase.Resolve(d.ID, dispute.StatusWon, ""); !errors.Is(err, pkg.ErrInvalidDisputeState) {
+				t.Errorf("expected error: %v, got: %v", pkg.ErrInvalidDisputeState, err)
+			}
+
+			if _, err = disputeUseCase.OpenDispute("tx_1", 30, "second dispute", ""); err != nil {
+				t.Errorf("expected a decided dispute to allow a new one, got: %v", err)
+			}
+		})
+	}
+}
+
+// TestResolve_ChargesBack tests that the clearing account receives what a lost dispute charged back.
+func TestResolve_ChargesBack(t *testing.T) {
+	// Arrange
+	ledgerRepo := ledgerMocks.NewMockLedgerRepository()
+	ledgerRepo.Fund(mocks.Merchant, 100)
+	disputeUseCase := pkg.NewDisputeUseCase(mocks.NewMockDisputeRepository(), ledgerRepo, mocks.NewPaymentRepository(), pkg.DisputeConfig{})
+	d, err := disputeUseCase.OpenDispute("tx_1", 30, "fraudulent", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Act
+	_, err = disputeUseCase.Resolve(d.ID, dispute.StatusLost, "")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if current := ledgerRepo.Current(ledger.DisputesClearingAccountID); current != 30 {
+		t.Errorf("expected the clearing account to hold 30, got: %.2f", current)
+	}
+}
+
+// TestEvidenceDeadline tests that evidence is accepted until the deadline, and that disputes still waiting for
+// evidence at their deadline are lost.
+func TestEvidenceDeadline(t *testing.T) {
+	// Arrange
+	paymentRepo := mocks.NewPaymentRepository()
+	ledgerRepo := ledgerMocks.NewMockLedgerRepository()
+	ledgerRepo.Fund(mocks.Merchant, 100)
+	disputeUseCase := pkg.NewDisputeUseCase(mocks.NewMockDisputeRepository(), ledgerRepo, paymentRepo, pkg.DisputeConfig{})
+	withEvidence, _ := disputeUseCase.OpenDispute("tx_1", 30, "not received", "")
+	_ = paymentRepo.Save(&payment.Transaction{ID: "tx_3", Amount: 50, Status: payment.TransactionStatusSuccess, Url: "https://shop.example.com/hook"})
+	withoutEvidence, _ := disputeUseCase.OpenDispute("tx_3", 50, "not received", "")
+
+	dueBy := time.Now().UTC().Add(time.Hour)
+	for _, id := range []string{withEvidence.ID, withoutEvidence.ID} {
+		if _, err := disputeUseCase.RequireEvidence(id, dueBy); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if _, err := disputeUseCase.RequireEvidence(withEvidence.ID, time.Now().Add(-time.Minute)); !errors.Is(err, pkg.ErrInvalidDeadline) {
+		t.Errorf("expected error: %v, got: %v", pkg.ErrInvalidDeadline, err)
+	}
+
+	if _, err := disputeUseCase.AddEvidence(withEvidence.ID, dispute.Evidence{Type: "selfie", FileName: "a.png", Size: 1}); !errors.Is(err, pkg.ErrInvalidEvidence) {
+		t.Errorf("expected error: %v, got: %v", pkg.ErrInvalidEvidence, err)
+	}
+
+	d, err := disputeUseCase.AddEvidence(withEvidence.ID, dispute.Evidence{Type: dispute.EvidenceTypeDeliveryProof, FileName: " signed-delivery.pdf ", ContentType: "application/pdf", Size: 52000})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(d.Evidence) != 1 || d.Evidence[0].ID == "" || d.Evidence[0].FileName != "signed-delivery.pdf" {
+		t.Errorf("unexpected evidence: %+v", d.Evidence)
+	}
+
+	// Act
+	early := disputeUseCase.RunOverdue(time.Now().UTC())
+	overdue := disputeUseCase.RunOverdue(dueBy.Add(time.Second))
+
+	// Assert
+	if early != 0 || overdue != 1 {
+		t.Errorf("expected only the dispute without evidence to be overdue, got: %d then %d", early, overdue)
+	}
+
+	if d, _ = disputeUseCase.GetDispute(withoutEvidence.ID); d.Status != dispute.StatusLost || d.Outcome != "no evidence was submitted before the deadline" {
+		t.Errorf("expected the dispute without evidence to be lost, got: %+v", d)
+	}
+
+	if d, _ = disputeUseCase.GetDispute(withEvidence.ID); d.Status != dispute.StatusEvidenceRequired {
+		t.Errorf("expected the dispute with evidence to wait for a decision, got: %s", d.Status)
+	}
+
+	if current, available := ledgerRepo.Current(mocks.Merchant), ledgerRepo.Available(mocks.Merchant); current != 50 || available != 20 {
+		t.Errorf("expected balance: 50.00/20.00, got: %.2f/%.2f", current, available)
+	}
+}
@@ -0,0 +1,61 @@
+package mocks
+
+import (
+	"github.com/quabynah-bilson/quantia/pkg/escrow"
+	"sync"
+)
+
+// MockEscrowRepository is an in-memory escrow repository
+type MockEscrowRepository struct {
+	mu           sync.Mutex
+	Escrows      map[string]*escrow.Escrow
+	Transactions map[string]string
+}
+
+// NewMockEscrowRepository creates an empty in-memory escrow repository
+func NewMockEscrowRepository() *MockEscrowRepository {
+	return &MockEscrowRepository{
+		Escrows:      make(map[string]*escrow.Escrow),
+		Transactions: make(map[string]string),
+	}
+}
+
+// Save saves a copy of the escrow and indexes it by its transaction
+func (m *MockEscrowRepository) Save(e *escrow.Escrow) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	copied := *e
+	m.Escrows[e.ID] = &copied
+	if e.TransactionID != "" {
+		m.Transactions[e.TransactionID] = e.ID
+	}
+	return nil
+}
+
+// Find returns a copy of the escrow
+func (m *MockEscrowRepository) Find(id string) (*escrow.Escrow, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.find(id)
+}
+
+// FindByTransaction returns a copy of the escrow funded by the transaction
+func (m *MockEscrowRepository) FindByTransaction(transactionID string) (*escrow.Escrow, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	id, ok := m.Transactions[transactionID]
+	if !ok {
+		return nil, escrow.ErrEscrowNotFound
+	}
+	return m.find(id)
+}
+
+// find returns a copy of the escrow. The caller holds the lock.
+func (m *MockEscrowRepository) find(id string) (*escrow.Escrow, error) {
+	e, ok := m.Escrows[id]
+	if !ok {
+		return nil, escrow.ErrEscrowNotFound
+	}
+	copied := *e
+	return &copied, nil
+}
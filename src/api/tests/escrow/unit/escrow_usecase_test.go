@@ -0,0 +1,279 @@
+package unit
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/quabynah-bilson/quantia/adapters/payment/provider"
+	"github.com/quabynah-bilson/quantia/pkg"
+	"github.com/quabynah-bilson/quantia/pkg/escrow"
+	"github.com/quabynah-bilson/quantia/pkg/event"
+	"github.com/quabynah-bilson/quantia/pkg/ledger"
+	"github.com/quabynah-bilson/quantia/pkg/payment"
+	"github.com/quabynah-bilson/quantia/tests/escrow/mocks"
+	ledgerMocks "github.com/quabynah-bilson/quantia/tests/ledger/mocks"
+	paymentMocks "github.com/quabynah-bilson/quantia/tests/payment/mocks"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const (
+	// platformURL is the endpoint of the marketplace taking the payments
+	platformURL = "https://platform.example.com/webhooks"
+
+	// sellerURL is the endpoint of a seller on the marketplace
+	sellerURL = "https://seller.example.com/webhooks"
+
+	// courierURL is the endpoint of a courier delivering the orders
+	courierURL = "https://courier.example.com/webhooks"
+)
+
+// marketSplit pays the platform a 10% fee, the courier 5 and the seller the rest
+var marketSplit = escrow.Split{
+	Fee: &escrow.Share{Type: escrow.ShareTypePercentage, Value: 10},
+	Rules: []escrow.Rule{
+		{Url: sellerURL, Share: escrow.Share{Type: escrow.ShareTypeRemainder}},
+		{Url: courierURL, Share: escrow.Share{Type: escrow.ShareTypeFixed, Value: 5}},
+	},
+}
+
+// testCase is a struct that represents a test case.
+type testCase struct {
+	name              string
+	accountID         string
+	source            string
+	split             escrow.Split
+	dispute           bool
+	refund            bool
+	expectedErr       error
+	expectedStatus    escrow.Status
+	expectedBalances  []float32
+	expectedEvents    []event.Type
+	expectedEvent     event.Type
+	expectedRefunded  float32
+	expectedTxnStatus payment.TransactionStatus
+}
+
+// partyBalances returns the current balances of the platform, the seller, the courier and the escrow.
+func partyBalances(ledgerRepo *ledgerMocks.MockLedgerRepository, escrowID string) []float32 {
+	var balances []float32
+	for _, accountID := range []string{
+		ledger.MerchantAccountID(platformURL),
+		ledger.MerchantAccountID(sellerURL),
+		ledger.MerchantAccountID(courierURL),
+		ledger.EscrowAccountID(escrowID),
+	} {
+		balances = append(balances, ledgerRepo.Current(accountID))
+	}
+
+	return balances
+}
+
+// TestEscrowUseCase_CreateEscrow tests that escrows hold their payment once it succeeds and that nothing
+// is charged for splits that do not add up.
+func TestEscrowUseCase_CreateEscrow(t *testing.T) {
+	testCases := []testCase{
+		{
+			name:             "funded",
+			accountID:        "acc_1",
+			source:           paymentMocks.Wallet,
+			split:            marketSplit,
+			expectedStatus:   escrow.StatusFunded,
+			expectedBalances: []float32{0, 0, 0, 100},
+			expectedEvents:   []event.Type{event.TypePaymentSucceeded, event.TypeEscrowFunded, event.TypeEscrowFunded, event.TypeEscrowFunded},
+		},
+		{
+			name:             "payment declined",
+			accountID:        "acc_1",
+			source:           paymentMocks.DeclinedWallet,
+			split:            marketSplit,
+			expectedStatus:   escrow.StatusFailed,
+			expectedBalances: []float32{0, 0, 0, 0},
+			expectedEvents:   []event.Type{event.TypePaymentFailed},
+			expectedErr:      payment.ErrPaymentDeclined,
+		},
+		{
+			name:      "split above the amount",
+			accountID: "acc_1",
+			source:    paymentMocks.Wallet,
+			split: escrow.Split{Rules: []escrow.Rule{
+				{Url: sellerURL, Share: escrow.Share{Type: escrow.ShareTypeFixed, Value: 150}},
+			}},
+			expectedErr: escrow.ErrInvalidSplit,
+		},
+		{
+			name:        "no payer account",
+			source:      paymentMocks.Wallet,
+			split:       marketSplit,
+			expectedErr: pkg.ErrInvalidAccount,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			ledgerRepo, paymentRepo := ledgerMocks.NewMockLedgerRepository(), paymentMocks.NewMockPaymentRepository()
+			paymentUseCase := paymentMocks.NewPaymentUseCase(paymentRepo, ledgerRepo, paymentMocks.NewSimulator(provider.SimulatorConfig{}))
+			escrowUseCase := pkg.NewEscrowUseCase(mocks.NewMockEscrowRepository(), ledgerRepo, paymentUseCase)
+
+			// Act
+			e, err := escrowUseCase.CreateEscrow(tc.accountID, 100, platformURL, tc.source, tc.split, nil)
+
+			// Assert
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error: %v, got: %v", tc.expectedErr, err)
+			}
+
+			if tc.expectedStatus == "" {
+				if e != nil || len(paymentRepo.Transactions) != 0 {
+					t.Errorf("expected nothing to be charged, got: %+v", e)
+				}
+				return
+			}
+
+			if e.Status != tc.expectedStatus || e.AccountID != tc.accountID {
+				t.Errorf("expected status %s for %s, got: %s for %s", tc.expectedStatus, tc.accountID, e.Status, e.AccountID)
+			}
+
+			if balances := partyBalances(ledgerRepo, e.ID); !reflect.DeepEqual(balances, tc.expectedBalances) {
+				t.Errorf("expected balances: %v, got: %v", tc.expectedBalances, balances)
+			}
+
+			if types := paymentRepo.EventTypes(); !reflect.DeepEqual(types, tc.expectedEvents) {
+				t.Errorf("expected events: %v, got: %v", tc.expectedEvents, types)
+			}
+		})
+	}
+}
+
+// TestEscrowUseCase_AsyncPayment tests that an escrow is funded when its payment succeeds after it is created.
+func TestEscrowUseCase_AsyncPayment(t *testing.T) {
+	// Arrange
+	ledgerRepo, paymentRepo := ledgerMocks.NewMockLedgerRepository(), paymentMocks.NewMockPaymentRepository()
+	simulator := paymentMocks.NewSimulator(provider.SimulatorConfig{Behaviour: provider.BehaviourAsync, AsyncDelay: 10 * time.Millisecond})
+	paymentUseCase := paymentMocks.NewPaymentUseCase(paymentRepo, ledgerRepo, simulator)
+	escrowUseCase := pkg.NewEscrowUseCase(mocks.NewMockEscrowRepository(), ledgerRepo, paymentUseCase)
+
+	// Act
+	e, err := escrowUseCase.CreateEscrow("acc_1", 100, platformURL, paymentMocks.Wallet, marketSplit, nil)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if e.Status != escrow.StatusPending {
+		t.Fatalf("expected a pending escrow, got: %s", e.Status)
+	}
+
+	if _, err = escrowUseCase.Release(e.ID); !errors.Is(err, pkg.ErrInvalidEscrowState) {
+		t.Errorf("expected error: %v, got: %v", pkg.ErrInvalidEscrowState, err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for e.Status == escrow.StatusPending && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+		e, _ = escrowUseCase.GetEscrow(e.ID)
+	}
+
+	if e.Status != escrow.StatusFunded {
+		t.Errorf("expected a funded escrow, got: %s", e.Status)
+	}
+}
+
+// TestEscrowUseCase_Settle tests that escrows are released to the parties or refunded to the payer, and
+// that each party is told its share.
+func TestEscrowUseCase_Settle(t *testing.T) {
+	testCases := []testCase{
+		{
+			name:              "released",
+			expectedStatus:    escrow.StatusReleased,
+			expectedBalances:  []float32{10, 85, 5, 0},
+			expectedEvent:     event.TypeEscrowReleased,
+			expectedTxnStatus: payment.TransactionStatusSuccess,
+		},
+		{
+			name:              "released after a dispute",
+			dispute:           true,
+			expectedStatus:    escrow.StatusReleased,
+			expectedBalances:  []float32{10, 85, 5, 0},
+			expectedEvent:     event.TypeEscrowReleased,
+			expectedTxnStatus: payment.TransactionStatusSuccess,
+		},
+		{
+			name:              "refunded after a dispute",
+			dispute:           true,
+			refund:            true,
+			expectedStatus:    escrow.StatusRefunded,
+			expectedBalances:  []float32{0, 0, 0, 0},
+			expectedEvent:     event.TypeEscrowRefunded,
+			expectedRefunded:  100,
+			expectedTxnStatus: payment.TransactionStatusRefunded,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			ledgerRepo, paymentRepo := ledgerMocks.NewMockLedgerRepository(), paymentMocks.NewMockPaymentRepository()
+			paymentUseCase := paymentMocks.NewPaymentUseCase(paymentRepo, ledgerRepo, paymentMocks.NewSimulator(provider.SimulatorConfig{}))
+			escrowUseCase := pkg.NewEscrowUseCase(mocks.NewMockEscrowRepository(), ledgerRepo, paymentUseCase)
+			e, err := escrowUseCase.CreateEscrow("acc_1", 100, platformURL, paymentMocks.Wallet, marketSplit, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if tc.dispute {
+				if _, err = escrowUseCase.Dispute(e.ID, "late delivery"); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			// Act
+			if tc.refund {
+				e, err = escrowUseCase.Refund(e.ID, "never delivered")
+			} else {
+				e, err = escrowUseCase.Release(e.ID)
+			}
+
+			// Assert
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if e.Status != tc.expectedStatus {
+				t.Errorf("expected status: %s, got: %s", tc.expectedStatus, e.Status)
+			}
+
+			if balances := partyBalances(ledgerRepo, e.ID); !reflect.DeepEqual(balances, tc.expectedBalances) {
+				t.Errorf("expected balances: %v, got: %v", tc.expectedBalances, balances)
+			}
+
+			transaction, _ := paymentRepo.Find(e.TransactionID)
+			if transaction.Status != tc.expectedTxnStatus || transaction.RefundedAmount != tc.expectedRefunded {
+				t.Errorf("expected a %s transaction refunded %v, got: %+v", tc.expectedTxnStatus, tc.expectedRefunded, transaction)
+			}
+
+			var shared float32
+			for _, envelope := range paymentRepo.Events {
+				var data event.EscrowData
+				if envelope.Type != tc.expectedEvent || json.Unmarshal(envelope.Data, &data) != nil || data.EscrowID != e.ID {
+					continue
+				}
+				shared += data.Share
+			}
+			if shared != 100 {
+				t.Errorf("expected the %s events to share 100, got: %v", tc.expectedEvent, shared)
+			}
+
+			// an escrow is settled once
+			if _, err = escrowUseCase.Release(e.ID); !errors.Is(err, pkg.ErrInvalidEscrowState) {
+				t.Errorf("expected error: %v, got: %v", pkg.ErrInvalidEscrowState, err)
+			}
+
+			if _, err = escrowUseCase.Refund(e.ID, ""); !errors.Is(err, pkg.ErrInvalidEscrowState) {
+				t.Errorf("expected error: %v, got: %v", pkg.ErrInvalidEscrowState, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package unit
+
+import (
+	"errors"
+	"github.com/quabynah-bilson/quantia/pkg/escrow"
+	"reflect"
+	"testing"
+)
+
+// TestSplit_Allocate tests how payments are divided between the platform and the recipients.
+func TestSplit_Allocate(t *testing.T) {
+	testCases := []struct {
+		name        string
+		amount      float32
+		split       escrow.Split
+		expected    []float32
+		expectedErr error
+	}{
+		{
+			name:   "fee and remainder",
+			amount: 100,
+			split: escrow.Split{
+				Fee:   &escrow.Share{Type: escrow.ShareTypePercentage, Value: 10},
+				Rules: []escrow.Rule{{Url: sellerURL, Share: escrow.Share{Type: escrow.ShareTypeRemainder}}},
+			},
+			expected: []float32{10, 90},
+		},
+		{
+			name:   "leftover cents go to the platform",
+			amount: 10,
+			split: escrow.Split{Rules: []escrow.Rule{
+				{Url: sellerURL, Share: escrow.Share{Type: escrow.ShareTypePercentage, Value: 33.33}},
+				{Url: courierURL, Share: escrow.Share{Type: escrow.ShareTypeFixed, Value: 2.5}},
+			}},
+			expected: []float32{4.17, 3.33, 2.5},
+		},
+		{
+			name:   "percentages rounded down for the remainder",
+			amount: 0.1,
+			split: escrow.Split{
+				Fee: &escrow.Share{Type: escrow.ShareTypePercentage, Value: 15},
+				Rules: []escrow.Rule{
+					{Url: sellerURL, Share: escrow.Share{Type: escrow.ShareTypeRemainder}},
+					{Url: courierURL, Share: escrow.Share{Type: escrow.ShareTypePercentage, Value: 15}},
+				},
+			},
+			expected: []float32{0.01, 0.08, 0.01},
+		},
+		{
+			name:   "shares above the amount",
+			amount: 10,
+			split: escrow.Split{
+				Fee:   &escrow.Share{Type: escrow.ShareTypeFixed, Value: 1},
+				Rules: []escrow.Rule{{Url: sellerURL, Share: escrow.Share{Type: escrow.ShareTypeFixed, Value: 9.5}}},
+			},
+			expectedErr: escrow.ErrInvalidSplit,
+		},
+		{
+			name:   "two remainders",
+			amount: 10,
+			split: escrow.Split{Rules: []escrow.Rule{
+				{Url: sellerURL, Share: escrow.Share{Type: escrow.ShareTypeRemainder}},
+				{Url: courierURL, Share: escrow.Share{Type: escrow.ShareTypeRemainder}},
+			}},
+			expectedErr: escrow.ErrInvalidSplit,
+		},
+		{
+			name:        "recipient sharing the platform's account",
+			amount:      10,
+			split:       escrow.Split{Rules: []escrow.Rule{{Url: "https://platform.example.com/other", Share: escrow.Share{Type: escrow.ShareTypeRemainder}}}},
+			expectedErr: escrow.ErrInvalidSplit,
+		},
+		{
+			name:        "percentage above 100",
+			amount:      10,
+			split:       escrow.Split{Rules: []escrow.Rule{{Url: sellerURL, Share: escrow.Share{Type: escrow.ShareTypePercentage, Value: 120}}}},
+			expectedErr: escrow.ErrInvalidSplit,
+		},
+		{name: "no recipients", amount: 10, expectedErr: escrow.ErrInvalidSplit},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			allocations, err := tc.split.Allocate(platformURL, tc.amount)
+
+			// Assert
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error: %v, got: %v", tc.expectedErr, err)
+			}
+
+			var amounts []float32
+			for _, allocation := range allocations {
+				amounts = append(amounts, allocation.Amount)
+			}
+			if !reflect.DeepEqual(amounts, tc.expected) {
+				t.Errorf("expected allocations: %v, got: %v", tc.expected, amounts)
+			}
+
+			if err == nil && (!allocations[0].Platform || allocations[0].Url != platformURL) {
+				t.Errorf("expected the platform to come first, got: %+v", allocations[0])
+			}
+		})
+	}
+}
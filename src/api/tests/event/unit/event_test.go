@@ -0,0 +1,104 @@
+package unit
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/quabynah-bilson/quantia/pkg/event"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sampleData returns sample data for each event type.
+func sampleData(eventType event.Type) interface{} {
+	switch eventType {
+	case event.TypeRefundCreated, event.TypeRefundSucceeded, event.TypeRefundFailed:
+		return &event.RefundData{RefundID: "rfd_1", TransactionID: "tx_1", Amount: 10, Status: "pending"}
+	case event.TypeScheduledPaymentFailed:
+		return &event.ScheduledPaymentData{ScheduleID: "sch_1", Occurrence: 0, ScheduledAt: time.Now(), Reason: "payment declined by provider"}
+	case event.TypeTransferCompleted:
+		return &event.TransferData{TransferID: "tr_1", FromAccountID: "acc_1", ToAccountID: "acc_2", Amount: 10}
+	case event.TypeAccountOverdrawn:
+		since := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+		return &event.OverdraftData{AccountID: "acc_1", Balance: -25, Limit: 100, OverdrawnSince: &since}
+	case event.TypeAccountOverdraftRepaid:
+		return &event.OverdraftData{AccountID: "acc_1", Balance: 10, Limit: 100}
+	case event.TypeInvoicePaid:
+		return &event.InvoiceData{InvoiceID: "inv_1", TransactionID: "tx_1", LinkID: "lnk_1", Amount: 10, Currency: "GHS", Status: "paid"}
+	case event.TypePaymentLinkPaid:
+		return &event.PaymentLinkData{LinkID: "lnk_1", TransactionID: "tx_1", Amount: 10, Currency: "GHS"}
+	case event.TypeEscrowFunded, event.TypeEscrowReleased, event.TypeEscrowDisputed, event.TypeEscrowRefunded:
+		return &event.EscrowData{EscrowID: "esc_1", TransactionID: "tx_1", Amount: 10, Share: 2.5, Status: strings.TrimPrefix(string(eventType), "escrow."), Reason: "not delivered"}
+	case event.TypeDisputeOpened, event.TypeDisputeEvidenceRequired, event.TypeDisputeWon, event.TypeDisputeLost:
+		dueBy := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+		return &event.DisputeData{DisputeID: "dsp_1", TransactionID: "tx_1", Amount: 10, Status: strings.TrimPrefix(string(eventType), "dispute."), Reason: "fraudulent", DueBy: &dueBy}
+	default:
+		return &event.PaymentData{TransactionID: "tx_1", Amount: 10, Status: "pending"}
+	}
+}
+
+// TestSchema_CoversEveryEventType tests that each event type has a schema matching its envelope.
+func TestSchema_CoversEveryEventType(t *testing.T) {
+	for _, eventType := range event.Types() {
+		t.Run(string(eventType), func(t *testing.T) {
+			raw, err := event.Schema(eventType)
+			if err != nil {
+				t.Fatalf("expected a schema, got: %v", err)
+			}
+
+			var schema struct {
+				Required   []string `json:"required"`
+				Properties struct {
+					Type    struct{ Const string } `json:"type"`
+					Version struct{ Const string } `json:"version"`
+					Data    struct {
+						Required []string `json:"required"`
+					} `json:"data"`
+				} `json:"properties"`
+			}
+			if err := json.Unmarshal(raw, &schema); err != nil {
+				t.Fatalf("expected a valid JSON schema, got: %v", err)
+			}
+
+			if schema.Properties.Type.Const != string(eventType) || schema.Properties.Version.Const != event.Version {
+				t.Errorf("expected schema for %s %s, got: %s %s", eventType, event.Version, schema.Properties.Type.Const, schema.Properties.Version.Const)
+			}
+
+			// every required field of the schema must be present in an emitted envelope
+			envelope, err := event.New(eventType, sampleData(eventType))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			encoded, _ := json.Marshal(envelope)
+
+			var emitted map[string]json.RawMessage
+			_ = json.Unmarshal(encoded, &emitted)
+			for _, field := range schema.Required {
+				if _, ok := emitted[field]; !ok {
+					t.Errorf("expected envelope field %q", field)
+				}
+			}
+
+			var data map[string]json.RawMessage
+			_ = json.Unmarshal(emitted["data"], &data)
+			for _, field := range schema.Properties.Data.Required {
+				if _, ok := data[field]; !ok {
+					t.Errorf("expected data field %q", field)
+				}
+			}
+		})
+	}
+}
+
+// TestNew tests the creation of event envelopes.
+func TestNew(t *testing.T) {
+	first, _ := event.New(event.TypePaymentCreated, sampleData(event.TypePaymentCreated))
+	second, _ := event.New(event.TypePaymentCreated, sampleData(event.TypePaymentCreated))
+	if first.ID == second.ID {
+		t.Errorf("expected unique event IDs, got: %s twice", first.ID)
+	}
+
+	if _, err := event.New("payment.teleported", nil); !errors.Is(err, event.ErrUnknownEventType) {
+		t.Errorf("expected error: %v, got: %v", event.ErrUnknownEventType, err)
+	}
+}
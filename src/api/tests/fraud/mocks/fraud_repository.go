@@ -0,0 +1,134 @@
+package mocks
+
+import (
+	"github.com/quabynah-bilson/quantia/pkg/fraud"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MockFraudRepository is an in-memory fraud repository
+type MockFraudRepository struct {
+	mu          sync.Mutex
+	Profiles    map[string]*fraud.Profile
+	Assessments map[string]*fraud.Assessment
+	attempts    map[string][]time.Time
+}
+
+// NewMockFraudRepository creates an empty in-memory fraud repository
+func NewMockFraudRepository() *MockFraudRepository {
+	return &MockFraudRepository{
+		Profiles:    make(map[string]*fraud.Profile),
+		Assessments: make(map[string]*fraud.Assessment),
+		attempts:    make(map[string][]time.Time),
+	}
+}
+
+// Profile returns a copy of the subject's history with the attempts made since the given time
+func (m *MockFraudRepository) Profile(subject string, since time.Time) (*fraud.Profile, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	profile := fraud.Profile{}
+	if stored, ok := m.Profiles[subject]; ok {
+		profile = *stored
+	}
+	for _, at := range m.attempts[subject] {
+		if !at.Before(since) {
+			profile.Attempts++
+		}
+	}
+
+	return &profile, nil
+}
+
+// RecordAttempt records a payment attempted by the subject
+func (m *MockFraudRepository) RecordAttempt(subject string, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.attempts[subject] = append(m.attempts[subject], at)
+	return nil
+}
+
+// Learn adds a trusted payment to the subject's history
+func (m *MockFraudRepository) Learn(signals fraud.Signals) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	profile, ok := m.Profiles[signals.Subject]
+	if !ok {
+		profile = &fraud.Profile{}
+		m.Profiles[signals.Subject] = profile
+	}
+
+	profile.Devices = appendNew(profile.Devices, signals.DeviceID)
+	profile.Beneficiaries = appendNew(profile.Beneficiaries, signals.Beneficiary)
+	profile.Countries = appendNew(profile.Countries, signals.IPCountry)
+	profile.Amounts = append([]float32{signals.Amount}, profile.Amounts...)
+	if len(profile.Amounts) > fraud.MaxProfileAmounts {
+		profile.Amounts = profile.Amounts[:fraud.MaxProfileAmounts]
+	}
+
+	return nil
+}
+
+// Save saves a copy of the assessment
+func (m *MockFraudRepository) Save(assessment *fraud.Assessment) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	copied := *assessment
+	m.Assessments[assessment.TransactionID] = &copied
+	return nil
+}
+
+// Find returns a copy of the transaction's assessment
+func (m *MockFraudRepository) Find(transactionID string) (*fraud.Assessment, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	assessment, ok := m.Assessments[transactionID]
+	if !ok {
+		return nil, fraud.ErrAssessmentNotFound
+	}
+
+	copied := *assessment
+	return &copied, nil
+}
+
+// ReviewQueue returns the assessments awaiting review, oldest first
+func (m *MockFraudRepository) ReviewQueue() ([]*fraud.Assessment, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var queue []*fraud.Assessment
+	for _, assessment := range m.Assessments {
+		if assessment.IsAwaitingReview() {
+			copied := *assessment
+			queue = append(queue, &copied)
+		}
+	}
+	sort.Slice(queue, func(i, j int) bool { return queue[i].CreatedAt.Before(queue[j].CreatedAt) })
+
+	return queue, nil
+}
+
+// MockGeoLocator locates IP addresses from a map of addresses to countries
+type MockGeoLocator map[string]string
+
+// Country returns the country of the IP address, empty when unknown
+func (m MockGeoLocator) Country(ip string) (string, error) {
+	return m[ip], nil
+}
+
+// appendNew appends a non-empty value that is not in the values yet
+func appendNew(values []string, value string) []string {
+	if value == "" {
+		return values
+	}
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
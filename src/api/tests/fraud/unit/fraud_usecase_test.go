@@ -0,0 +1,128 @@
+package unit
+
+import (
+	"errors"
+	"github.com/quabynah-bilson/quantia/pkg"
+	"github.com/quabynah-bilson/quantia/pkg/fraud"
+	"github.com/quabynah-bilson/quantia/tests/fraud/mocks"
+	"testing"
+	"time"
+)
+
+// newFraudUseCase creates a fraud use case that reviews payments from new devices and blocks payments from abroad.
+func newFraudUseCase(fraudRepo *mocks.MockFraudRepository) *pkg.FraudUseCase {
+	return pkg.NewFraudUseCase(fraudRepo, &fraud.Config{
+		ReviewScore: 20,
+		BlockScore:  50,
+		Rules: map[fraud.RuleName]fraud.RuleConfig{
+			fraud.RuleNewDevice:   {Score: 20},
+			fraud.RuleGeoMismatch: {Score: 50},
+		},
+	}, mocks.MockGeoLocator{"41.66.0.1": "GH", "5.3.0.1": "RU"})
+}
+
+// TestFraudUseCase_Assess tests that decisions are recorded per transaction and that trusted payments are learnt.
+func TestFraudUseCase_Assess(t *testing.T) {
+	// Arrange
+	fraudRepo := mocks.NewMockFraudRepository()
+	fraudRepo.Profiles["card_1"] = &fraud.Profile{Devices: []string{"device_1"}}
+	fraudUseCase := newFraudUseCase(fraudRepo)
+	signals := func(device, ip string) fraud.Signals {
+		return fraud.Signals{Subject: "card_1", Amount: 10, Origin: fraud.Origin{DeviceID: device, IPAddress: ip, Country: "GH"}}
+	}
+
+	// Act
+	allowed, allowErr := fraudUseCase.Assess("tx_1", signals("device_1", "41.66.0.1"))
+	held, reviewErr := fraudUseCase.Assess("tx_2", signals("device_2", "41.66.0.1"))
+	blocked, blockErr := fraudUseCase.Assess("tx_3", signals("device_1", "5.3.0.1"))
+
+	// Assert
+	if allowErr != nil || reviewErr != nil || blockErr != nil {
+		t.Fatalf("unexpected errors: %v %v %v", allowErr, reviewErr, blockErr)
+	}
+
+	for _, tc := range []struct {
+		assessment   *fraud.Assessment
+		decision     fraud.Decision
+		reviewStatus fraud.ReviewStatus
+	}{
+		{allowed, fraud.DecisionAllow, ""},
+		{held, fraud.DecisionReview, fraud.ReviewStatusPending},
+		{blocked, fraud.DecisionBlock, ""},
+	} {
+		recorded, err := fraudUseCase.GetAssessment(tc.assessment.TransactionID)
+		if err != nil || recorded.Decision != tc.decision || recorded.ReviewStatus != tc.reviewStatus {
+			t.Errorf("expected %s to be recorded as %s %q, got: %+v %v", tc.assessment.TransactionID, tc.decision, tc.reviewStatus, recorded, err)
+		}
+	}
+
+	if blocked.Signals.IPCountry != "RU" {
+		t.Errorf("expected the IP address to be located in RU, got: %q", blocked.Signals.IPCountry)
+	}
+
+	// only the allowed payment is trusted
+	profile, _ := fraudRepo.Profile("card_1", time.Time{})
+	if len(profile.Amounts) != 1 || len(profile.Devices) != 1 || profile.Attempts != 3 {
+		t.Errorf("expected one trusted payment out of 3 attempts, got: %+v", profile)
+	}
+}
+
+// TestFraudUseCase_Review tests the decisions of analysts on held payments.
+func TestFraudUseCase_Review(t *testing.T) {
+	testCases := []struct {
+		name            string
+		approve         bool
+		expectedStatus  fraud.ReviewStatus
+		expectedDevices int
+	}{
+		{name: "analyst approves", approve: true, expectedStatus: fraud.ReviewStatusApproved, expectedDevices: 1},
+		{name: "analyst rejects", approve: false, expectedStatus: fraud.ReviewStatusRejected},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			fraudRepo := mocks.NewMockFraudRepository()
+			fraudUseCase := newFraudUseCase(fraudRepo)
+			var resumed []*fraud.Assessment
+			fraudUseCase.OnReviewed(func(assessment *fraud.Assessment) error {
+				resumed = append(resumed, assessment)
+				return nil
+			})
+
+			if _, err := fraudUseCase.Assess("tx_1", fraud.Signals{Subject: "card_1", Amount: 10, Origin: fraud.Origin{DeviceID: "device_1"}}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			queue, _ := fraudUseCase.GetReviewQueue()
+			if len(queue) != 1 || queue[0].TransactionID != "tx_1" {
+				t.Fatalf("expected tx_1 in the review queue, got: %v", queue)
+			}
+
+			// Act
+			_, missingReviewerErr := fraudUseCase.Review("tx_1", tc.approve, "", "")
+			assessment, err := fraudUseCase.Review("tx_1", tc.approve, "analyst@quantia.com", "called the payer")
+			_, secondErr := fraudUseCase.Review("tx_1", tc.approve, "analyst@quantia.com", "")
+
+			// Assert
+			if !errors.Is(missingReviewerErr, pkg.ErrInvalidReview) {
+				t.Errorf("expected error: %v, got: %v", pkg.ErrInvalidReview, missingReviewerErr)
+			}
+			if err != nil || assessment.ReviewStatus != tc.expectedStatus || assessment.ReviewedAt == nil {
+				t.Errorf("expected status: %s, got: %+v %v", tc.expectedStatus, assessment, err)
+			}
+			if !errors.Is(secondErr, pkg.ErrNotAwaitingReview) {
+				t.Errorf("expected error: %v, got: %v", pkg.ErrNotAwaitingReview, secondErr)
+			}
+
+			if len(resumed) != 1 || resumed[0].IsApproved() != tc.approve {
+				t.Errorf("expected the payment to be resumed once, got: %v", resumed)
+			}
+
+			queue, _ = fraudUseCase.GetReviewQueue()
+			profile, _ := fraudRepo.Profile("card_1", time.Time{})
+			if len(queue) != 0 || len(profile.Devices) != tc.expectedDevices {
+				t.Errorf("expected an empty queue and %d trusted devices, got: %v %v", tc.expectedDevices, queue, profile.Devices)
+			}
+		})
+	}
+}
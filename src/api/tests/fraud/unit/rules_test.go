@@ -0,0 +1,140 @@
+package unit
+
+import (
+	"github.com/quabynah-bilson/quantia/pkg/fraud"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestConfig_Evaluate tests the rules that match a payment and the decision their score leads to.
+func TestConfig_Evaluate(t *testing.T) {
+	config := fraud.DefaultConfig()
+	history := &fraud.Profile{
+		Devices:       []string{"device_1"},
+		Beneficiaries: []string{"merchant:shop.com"},
+		Countries:     []string{"GH"},
+		Amounts:       []float32{40, 60, 50},
+	}
+	known := fraud.Signals{Subject: "card_1", Amount: 55, Beneficiary: "merchant:shop.com", Origin: fraud.Origin{DeviceID: "device_1"}, IPCountry: "GH"}
+
+	testCases := []struct {
+		name             string
+		signals          func(s fraud.Signals) fraud.Signals
+		attempts         int
+		expectedRules    []fraud.RuleName
+		expectedDecision fraud.Decision
+	}{
+		{
+			name:             "known device, merchant and country",
+			signals:          func(s fraud.Signals) fraud.Signals { return s },
+			expectedDecision: fraud.DecisionAllow,
+		},
+		{
+			name: "new device and merchant",
+			signals: func(s fraud.Signals) fraud.Signals {
+				s.DeviceID, s.Beneficiary = "device_2", "merchant:other.com"
+				return s
+			},
+			expectedRules:    []fraud.RuleName{fraud.RuleNewDevice, fraud.RuleNewBeneficiary},
+			expectedDecision: fraud.DecisionAllow,
+		},
+		{
+			name:             "unusual amount from a new device",
+			signals:          func(s fraud.Signals) fraud.Signals { s.Amount, s.DeviceID = 500, "device_2"; return s },
+			expectedRules:    []fraud.RuleName{fraud.RuleNewDevice, fraud.RuleUnusualAmount},
+			expectedDecision: fraud.DecisionReview,
+		},
+		{
+			name:             "declared country differs from the IP address",
+			signals:          func(s fraud.Signals) fraud.Signals { s.Country = "NG"; return s },
+			expectedRules:    []fraud.RuleName{fraud.RuleGeoMismatch},
+			expectedDecision: fraud.DecisionAllow,
+		},
+		{
+			name:             "rapid succession from a new country",
+			signals:          func(s fraud.Signals) fraud.Signals { s.IPCountry = "RU"; return s },
+			attempts:         5,
+			expectedRules:    []fraud.RuleName{fraud.RuleRapidSuccession, fraud.RuleGeoMismatch},
+			expectedDecision: fraud.DecisionReview,
+		},
+		{
+			name: "every rule",
+			signals: func(s fraud.Signals) fraud.Signals {
+				s.Amount, s.DeviceID, s.Beneficiary, s.IPCountry = 900, "device_2", "merchant:other.com", "RU"
+				return s
+			},
+			attempts:         6,
+			expectedRules:    []fraud.RuleName{fraud.RuleNewDevice, fraud.RuleUnusualAmount, fraud.RuleRapidSuccession, fraud.RuleNewBeneficiary, fraud.RuleGeoMismatch},
+			expectedDecision: fraud.DecisionBlock,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			profile := *history
+			profile.Attempts = tc.attempts
+
+			// Act
+			score, decision, hits := config.Evaluate(tc.signals(known), &profile)
+
+			// Assert
+			var rules []fraud.RuleName
+			total := 0
+			for _, hit := range hits {
+				rules = append(rules, hit.Rule)
+				total += hit.Score
+				if hit.Reason == "" {
+					t.Errorf("expected %s to give a reason", hit.Rule)
+				}
+			}
+
+			if !reflect.DeepEqual(rules, tc.expectedRules) {
+				t.Errorf("expected rules: %v, got: %v", tc.expectedRules, rules)
+			}
+			if decision != tc.expectedDecision || score != total {
+				t.Errorf("expected decision %s with a score of %d, got: %s %d", tc.expectedDecision, total, decision, score)
+			}
+		})
+	}
+}
+
+// TestLoadConfig tests that rules are read from a file and that unusable configurations are refused.
+func TestLoadConfig(t *testing.T) {
+	testCases := []struct {
+		name        string
+		content     string
+		expectedErr bool
+	}{
+		{
+			name:    "valid rules",
+			content: `{"review_score": 40, "block_score": 70, "rules": {"new_device": {"score": 30}, "rapid_succession": {"score": 50, "window_seconds": 60, "max_count": 3}}}`,
+		},
+		{name: "unknown rule", content: `{"review_score": 40, "block_score": 70, "rules": {"full_moon": {"score": 30}}}`, expectedErr: true},
+		{name: "block below review", content: `{"review_score": 40, "block_score": 30, "rules": {}}`, expectedErr: true},
+		{name: "rapid succession without a window", content: `{"review_score": 40, "block_score": 70, "rules": {"rapid_succession": {"score": 50}}}`, expectedErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			path := filepath.Join(t.TempDir(), "rules.json")
+			if err := os.WriteFile(path, []byte(tc.content), 0o600); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// Act
+			config, err := fraud.LoadConfig(path)
+
+			// Assert
+			if (err != nil) != tc.expectedErr {
+				t.Fatalf("expected an error: %v, got: %v", tc.expectedErr, err)
+			}
+			if err == nil && config.Rules[fraud.RuleNewDevice].Score != 30 {
+				t.Errorf("expected the new device rule to score 30, got: %+v", config.Rules)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package mocks
+
+import (
+	"github.com/quabynah-bilson/quantia/pkg/invoice"
+	"sort"
+	"sync"
+)
+
+// MockInvoiceRepository is an in-memory invoice and payment link repository
+type MockInvoiceRepository struct {
+	mu       sync.Mutex
+	Invoices map[string]*invoice.Invoice
+	Links    map[string]*invoice.Link
+	Records  map[string]*invoice.Payment
+}
+
+// NewMockInvoiceRepository creates an empty in-memory invoice repository
+func NewMockInvoiceRepository() *MockInvoiceRepository {
+	return &MockInvoiceRepository{
+		Invoices: make(map[string]*invoice.Invoice),
+		Links:    make(map[string]*invoice.Link),
+		Records:  make(map[string]*invoice.Payment),
+	}
+}
+
+// Save saves a copy of the invoice
+func (m *MockInvoiceRepository) Save(inv *invoice.Invoice) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	copied := *inv
+	m.Invoices[inv.ID] = &copied
+	return nil
+}
+
+// Find returns a copy of the invoice
+func (m *MockInvoiceRepository) Find(id string) (*invoice.Invoice, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	inv, ok := m.Invoices[id]
+	if !ok {
+		return nil, invoice.ErrInvoiceNotFound
+	}
+	copied := *inv
+	return &copied, nil
+}
+
+// SaveLink saves a copy of the payment link
+func (m *MockInvoiceRepository) SaveLink(link *invoice.Link) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	copied := *link
+	m.Links[link.ID] = &copied
+	return nil
+}
+
+// FindLink returns a copy of the payment link
+func (m *MockInvoiceRepository) FindLink(id string) (*invoice.Link, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	link, ok := m.Links[id]
+	if !ok {
+		return nil, invoice.ErrLinkNotFound
+	}
+	copied := *link
+	return &copied, nil
+}
+
+// Record saves a copy of the payment made through a link
+func (m *MockInvoiceRepository) Record(p *invoice.Payment) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	copied := *p
+	m.Records[p.TransactionID] = &copied
+	return nil
+}
+
+// FindPayment returns a copy of the payment made by the transaction
+func (m *MockInvoiceRepository) FindPayment(transactionID string) (*invoice.Payment, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	p, ok := m.Records[transactionID]
+	if !ok {
+		return nil, invoice.ErrPaymentNotFound
+	}
+	copied := *p
+	return &copied, nil
+}
+
+// Payments returns copies of the payments made through the link, oldest first
+func (m *MockInvoiceRepository) Payments(linkID string) ([]*invoice.Payment, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	payments := make([]*invoice.Payment, 0)
+	for _, p := range m.Records {
+		if p.LinkID == linkID {
+			copied := *p
+			payments = append(payments, &copied)
+		}
+	}
+	sort.Slice(payments, func(i, j int) bool { return payments[i].CreatedAt.Before(payments[j].CreatedAt) })
+	return payments, nil
+}
@@ -0,0 +1,311 @@
+package unit
+
+import (
+	"errors"
+	"github.com/quabynah-bilson/quantia/adapters/payment/provider"
+	"github.com/quabynah-bilson/quantia/pkg"
+	"github.com/quabynah-bilson/quantia/pkg/event"
+	"github.com/quabynah-bilson/quantia/pkg/invoice"
+	"github.com/quabynah-bilson/quantia/pkg/payment"
+	"github.com/quabynah-bilson/quantia/tests/invoice/mocks"
+	ledgerMocks "github.com/quabynah-bilson/quantia/tests/ledger/mocks"
+	paymentMocks "github.com/quabynah-bilson/quantia/tests/payment/mocks"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// merchantURL is the endpoint of the merchant issuing the invoices
+const merchantURL = "https://merchant.example.com/webhooks"
+
+// testCase is a struct that represents a test case.
+type testCase struct {
+	name               string
+	currency           string
+	items              []invoice.LineItem
+	dueDate            time.Time
+	source             string
+	expectedAmount     float32
+	expectedErr        error
+	expectedStatus     invoice.Status
+	expectedLinkStatus invoice.LinkStatus
+	expectedEvents     []event.Type
+}
+
+// createInvoice creates an invoice of 25.50 due tomorrow, with a link to pay it.
+func createInvoice(t *testing.T, invoiceUseCase *pkg.InvoiceUseCase) (*invoice.Invoice, *invoice.Link) {
+	t.Helper()
+
+	inv, err := invoiceUseCase.CreateInvoice(merchantURL, "March consulting", "ghs", []invoice.LineItem{
+		{Description: "Consulting hours", Quantity: 2, UnitPrice: 10.1},
+		{Description: "Travel", Quantity: 1, UnitPrice: 5.3},
+	}, time.Now().Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	link, err := invoiceUseCase.CreateInvoiceLink(inv.ID, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return inv, link
+}
+
+// TestInvoiceUseCase_CreateInvoice tests the validation of invoices and the totals of their lines.
+func TestInvoiceUseCase_CreateInvoice(t *testing.T) {
+	tomorrow := time.Now().Add(24 * time.Hour)
+	testCases := []testCase{
+		{
+			name:           "lines added up",
+			currency:       "GHS",
+			items:          []invoice.LineItem{{Description: "Widget", Quantity: 3, UnitPrice: 0.1}, {Description: "Delivery", Quantity: 1, UnitPrice: 2.55}},
+			dueDate:        tomorrow,
+			expectedAmount: 2.85,
+		},
+		{
+			name:        "no line items",
+			currency:    "GHS",
+			dueDate:     tomorrow,
+			expectedErr: pkg.ErrInvalidInvoice,
+		},
+		{
+			name:        "currency not offered",
+			currency:    "USD",
+			items:       []invoice.LineItem{{Description: "Widget", Quantity: 1, UnitPrice: 1}},
+			dueDate:     tomorrow,
+			expectedErr: pkg.ErrInvalidInvoice,
+		},
+		{
+			name:        "empty quantity",
+			currency:    "GHS",
+			items:       []invoice.LineItem{{Description: "Widget", Quantity: 0, UnitPrice: 1}},
+			dueDate:     tomorrow,
+			expectedErr: pkg.ErrInvalidInvoice,
+		},
+		{
+			name:        "due date in the past",
+			currency:    "GHS",
+			items:       []invoice.LineItem{{Description: "Widget", Quantity: 1, UnitPrice: 1}},
+			dueDate:     time.Now().Add(-48 * time.Hour),
+			expectedErr: pkg.ErrInvalidInvoice,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			paymentUseCase := paymentMocks.NewPaymentUseCase(paymentMocks.NewMockPaymentRepository(), ledgerMocks.NewMockLedgerRepository(), paymentMocks.NewSimulator(provider.SimulatorConfig{}))
+			invoiceUseCase := pkg.NewInvoiceUseCase(mocks.NewMockInvoiceRepository(), paymentUseCase, pkg.InvoiceConfig{Currencies: []string{"GHS"}})
+
+			// Act
+			inv, err := invoiceUseCase.CreateInvoice(merchantURL, "", tc.currency, tc.items, tc.dueDate)
+
+			// Assert
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error: %v, got: %v", tc.expectedErr, err)
+			}
+
+			if err == nil && (inv.Amount != tc.expectedAmount || inv.Status != invoice.StatusOpen || inv.IsOverdue(time.Now())) {
+				t.Errorf("expected an open invoice of %v, got: %+v", tc.expectedAmount, inv)
+			}
+		})
+	}
+}
+
+// TestInvoiceUseCase_PayInvoiceLink tests that invoices are paid once through their link and that the
+// merchant is told when they are.
+func TestInvoiceUseCase_PayInvoiceLink(t *testing.T) {
+	testCases := []testCase{
+		{
+			name:               "paid",
+			source:             paymentMocks.Wallet,
+			expectedStatus:     invoice.StatusPaid,
+			expectedLinkStatus: invoice.LinkStatusUsed,
+			expectedEvents:     []event.Type{event.TypePaymentSucceeded, event.TypeInvoicePaid},
+		},
+		{
+			name:               "declined",
+			source:             paymentMocks.DeclinedWallet,
+			expectedErr:        payment.ErrPaymentDeclined,
+			expectedStatus:     invoice.StatusOpen,
+			expectedLinkStatus: invoice.LinkStatusActive,
+			expectedEvents:     []event.Type{event.TypePaymentFailed},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			paymentRepo := paymentMocks.NewMockPaymentRepository()
+			paymentUseCase := paymentMocks.NewPaymentUseCase(paymentRepo, ledgerMocks.NewMockLedgerRepository(), paymentMocks.NewSimulator(provider.SimulatorConfig{}))
+			invoiceUseCase := pkg.NewInvoiceUseCase(mocks.NewMockInvoiceRepository(), paymentUseCase, pkg.InvoiceConfig{Currencies: []string{"GHS"}})
+			inv, link := createInvoice(t, invoiceUseCase)
+
+			// Act
+			transaction, err := invoiceUseCase.PayLink(link.ID, tc.source, nil)
+
+			// Assert
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error: %v, got: %v", tc.expectedErr, err)
+			}
+
+			if transaction.Amount != 25.5 {
+				t.Errorf("expected the invoice total to be charged, got: %v", transaction.Amount)
+			}
+
+			stored, _ := invoiceUseCase.GetInvoice(inv.ID)
+			if stored.Status != tc.expectedStatus {
+				t.Errorf("expected status: %s, got: %s", tc.expectedStatus, stored.Status)
+			}
+
+			if paid := stored.Status == invoice.StatusPaid; paid != (stored.TransactionID == transaction.ID && stored.PaidAt != nil) {
+				t.Errorf("expected the paid invoice to record its payment %s, got: %+v", transaction.ID, stored)
+			}
+
+			storedLink, _ := invoiceUseCase.GetLink(link.ID)
+			if storedLink.Status != tc.expectedLinkStatus {
+				t.Errorf("expected link status: %s, got: %s", tc.expectedLinkStatus, storedLink.Status)
+			}
+
+			if types := paymentRepo.EventTypes(); !reflect.DeepEqual(types, tc.expectedEvents) {
+				t.Errorf("expected events: %v, got: %v", tc.expectedEvents, types)
+			}
+		})
+	}
+}
+
+// TestInvoiceUseCase_DeclinedPaymentReopens tests that an invoice whose payment was declined can be paid again,
+// but not once it is paid.
+func TestInvoiceUseCase_DeclinedPaymentReopens(t *testing.T) {
+	// Arrange
+	paymentUseCase := paymentMocks.NewPaymentUseCase(paymentMocks.NewMockPaymentRepository(), ledgerMocks.NewMockLedgerRepository(), paymentMocks.NewSimulator(provider.SimulatorConfig{}))
+	invoiceUseCase := pkg.NewInvoiceUseCase(mocks.NewMockInvoiceRepository(), paymentUseCase, pkg.InvoiceConfig{Currencies: []string{"GHS"}})
+	inv, link := createInvoice(t, invoiceUseCase)
+
+	// Act
+	_, declinedErr := invoiceUseCase.PayLink(link.ID, paymentMocks.DeclinedWallet, nil)
+	reopened, _ := invoiceUseCase.GetInvoice(inv.ID)
+	transaction, err := invoiceUseCase.PayLink(link.ID, paymentMocks.Wallet, nil)
+	_, paidErr := invoiceUseCase.PayLink(link.ID, paymentMocks.Wallet, nil)
+
+	// Assert
+	if !errors.Is(declinedErr, payment.ErrPaymentDeclined) || reopened.Status != invoice.StatusOpen {
+		t.Errorf("expected the declined invoice to be open, got: %s %v", reopened.Status, declinedErr)
+	}
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stored, _ := invoiceUseCase.GetInvoice(inv.ID)
+	if stored.Status != invoice.StatusPaid || stored.TransactionID != transaction.ID {
+		t.Errorf("expected the invoice to be paid by %s, got: %+v", transaction.ID, stored)
+	}
+
+	payments, _ := invoiceUseCase.GetLinkPayments(link.ID)
+	if len(payments) != 2 || payments[0].Status != invoice.PaymentStatusFailed || payments[1].Status != invoice.PaymentStatusSucceeded {
+		t.Errorf("expected a failed then a successful payment, got: %+v", payments)
+	}
+
+	if !errors.Is(paidErr, invoice.ErrLinkNotActive) {
+		t.Errorf("expected error: %v, got: %v", invoice.ErrLinkNotActive, paidErr)
+	}
+}
+
+// TestInvoiceUseCase_AsyncPayment tests that an invoice cannot be paid twice while its payment is pending,
+// and is paid when the provider reports back.
+func TestInvoiceUseCase_AsyncPayment(t *testing.T) {
+	// Arrange
+	paymentUseCase := paymentMocks.NewPaymentUseCase(paymentMocks.NewMockPaymentRepository(), ledgerMocks.NewMockLedgerRepository(), paymentMocks.NewSimulator(provider.SimulatorConfig{
+		Behaviour:    provider.BehaviourAsync,
+		AsyncDelay:   20 * time.Millisecond,
+		AsyncOutcome: provider.BehaviourSucceed,
+	}))
+	invoiceUseCase := pkg.NewInvoiceUseCase(mocks.NewMockInvoiceRepository(), paymentUseCase, pkg.InvoiceConfig{Currencies: []string{"GHS"}})
+	inv, link := createInvoice(t, invoiceUseCase)
+	second, err := invoiceUseCase.CreateInvoiceLink(inv.ID, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Act
+	transaction, err := invoiceUseCase.PayLink(link.ID, paymentMocks.Wallet, nil)
+	if err != nil || transaction.Status != payment.TransactionStatusPending {
+		t.Fatalf("expected a pending payment, got: %v %v", transaction, err)
+	}
+	_, pendingErr := invoiceUseCase.PayLink(second.ID, paymentMocks.Wallet, nil)
+
+	stored, _ := invoiceUseCase.GetInvoice(inv.ID)
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) && stored.Status == invoice.StatusProcessing {
+		time.Sleep(5 * time.Millisecond)
+		stored, _ = invoiceUseCase.GetInvoice(inv.ID)
+	}
+
+	// Assert
+	if !errors.Is(pendingErr, invoice.ErrInvoiceNotPayable) {
+		t.Errorf("expected error: %v, got: %v", invoice.ErrInvoiceNotPayable, pendingErr)
+	}
+
+	if stored.Status != invoice.StatusPaid || stored.TransactionID != transaction.ID {
+		t.Errorf("expected the invoice to be paid by %s, got: %+v", transaction.ID, stored)
+	}
+
+	if _, err = invoiceUseCase.VoidInvoice(inv.ID); !errors.Is(err, pkg.ErrInvalidInvoiceState) {
+		t.Errorf("expected error: %v, got: %v", pkg.ErrInvalidInvoiceState, err)
+	}
+}
+
+// TestInvoiceUseCase_MultiUseLink tests that multi-use links are paid until they are disabled or expire.
+func TestInvoiceUseCase_MultiUseLink(t *testing.T) {
+	// Arrange
+	paymentRepo := paymentMocks.NewMockPaymentRepository()
+	paymentUseCase := paymentMocks.NewPaymentUseCase(paymentRepo, ledgerMocks.NewMockLedgerRepository(), paymentMocks.NewSimulator(provider.SimulatorConfig{}))
+	invoiceRepo := mocks.NewMockInvoiceRepository()
+	invoiceUseCase := pkg.NewInvoiceUseCase(invoiceRepo, paymentUseCase, pkg.InvoiceConfig{Currencies: []string{"GHS"}})
+	expiresAt := time.Now().Add(time.Hour)
+	link, err := invoiceUseCase.CreateLink(merchantURL, "Concert ticket", 15, "GHS", true, &expiresAt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	single, err := invoiceUseCase.CreateLink(merchantURL, "Donation", 5, "GHS", false, &expiresAt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Act
+	_, firstErr := invoiceUseCase.PayLink(link.ID, paymentMocks.Wallet, nil)
+	_, secondErr := invoiceUseCase.PayLink(link.ID, paymentMocks.Wallet, nil)
+	if _, err = invoiceUseCase.DisableLink(link.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, disabledErr := invoiceUseCase.PayLink(link.ID, paymentMocks.Wallet, nil)
+
+	// the single-use link expires before it is paid
+	invoiceRepo.Links[single.ID].ExpiresAt = func() *time.Time { past := time.Now().Add(-time.Minute); return &past }()
+	_, expiredErr := invoiceUseCase.PayLink(single.ID, paymentMocks.Wallet, nil)
+
+	// Assert
+	if firstErr != nil || secondErr != nil {
+		t.Fatalf("unexpected errors: %v %v", firstErr, secondErr)
+	}
+
+	stored, _ := invoiceUseCase.GetLink(link.ID)
+	if stored.Payments != 2 || stored.Status != invoice.LinkStatusDisabled {
+		t.Errorf("expected a disabled link paid twice, got: %+v", stored)
+	}
+
+	expectedEvents := []event.Type{event.TypePaymentSucceeded, event.TypePaymentLinkPaid, event.TypePaymentSucceeded, event.TypePaymentLinkPaid}
+	if types := paymentRepo.EventTypes(); !reflect.DeepEqual(types, expectedEvents) {
+		t.Errorf("expected events: %v, got: %v", expectedEvents, types)
+	}
+
+	if !errors.Is(disabledErr, invoice.ErrLinkNotActive) {
+		t.Errorf("expected error: %v, got: %v", invoice.ErrLinkNotActive, disabledErr)
+	}
+
+	if !errors.Is(expiredErr, invoice.ErrLinkExpired) {
+		t.Errorf("expected error: %v, got: %v", invoice.ErrLinkExpired, expiredErr)
+	}
+}
@@ -0,0 +1,241 @@
+package unit
+
+import (
+	"errors"
+	"github.com/quabynah-bilson/quantia/pkg/iso20022"
+	"github.com/quabynah-bilson/quantia/pkg/reconciliation"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// readSample reads a sample file of the testdata directory
+func readSample(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return data
+}
+
+// TestPain001RoundTrip tests that a sample pain.001 file reads, writes and reads back to the same transfers.
+func TestPain001RoundTrip(t *testing.T) {
+	// Arrange
+	doc, err := iso20022.ParsePain001(readSample(t, "pain001.xml"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Act
+	data, err := iso20022.Marshal(doc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	parsed, err := iso20022.ParsePain001(data)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(doc, parsed) {
+		t.Errorf("expected the document to survive the round trip, got:\n%s", data)
+	}
+
+	expected := []iso20022.CreditTransfer{
+		{EndToEndID: "trf6f1c2e0a9b8d4c7e8f1a2b3c4d5e6f70", Amount: 1500, Currency: "EUR", CreditorName: "Ama Mensah", CreditorAccount: "DE89370400440532013000", CreditorAgent: "DEUTDEFF", Remittance: "Invoice 2026-114"},
+		{EndToEndID: "trf0a1b2c3d4e5f60718293a4b5c6d7e8f9", Amount: 250.25, Currency: "EUR", CreditorName: "Kofi Boateng", CreditorAccount: "1441000123456", CreditorAgent: "030100"},
+	}
+	if transfers := parsed.Transfers(); !reflect.DeepEqual(transfers, expected) {
+		t.Errorf("expected transfers: %+v, got: %+v", expected, transfers)
+	}
+}
+
+// TestNewPain001 tests that transfers written into a pain.001 message are totalled and read back unchanged.
+func TestNewPain001(t *testing.T) {
+	// Arrange
+	debtor := iso20022.Debtor{Name: "Quantia Payments Ltd", Account: "GB29 NWBK 6016 1331 9268 19", Agent: "nwbkgb2l"}
+	transfers := []iso20022.CreditTransfer{
+		{EndToEndID: "trf1", Amount: 0.1, Currency: "GHS", CreditorName: "Ama Mensah", CreditorAccount: "1441000123456", CreditorAgent: "030100", Remittance: "rent"},
+		{EndToEndID: "trf2", Amount: 0.2, Currency: "GHS", CreditorName: "Kofi Boateng", CreditorAccount: "DE89370400440532013000", CreditorAgent: "DEUTDEFFXXX"},
+	}
+
+	// Act
+	data, err := iso20022.Marshal(iso20022.NewPain001("TRF20261019-1", debtor, time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC), transfers))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	doc, err := iso20022.ParsePain001(data)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	header, payment := doc.Initiation.GroupHeader, doc.Initiation.Payments[0]
+	if header.NumberOfTransactions != "2" || header.ControlSum != "0.30" || payment.RequestedExecutionDate != "2026-10-19" {
+		t.Errorf("unexpected totals: %+v", header)
+	}
+
+	if payment.DebtorAccount.ID.IBAN != "GB29NWBK60161331926819" || payment.DebtorAgent.Institution.BIC != "NWBKGB2L" {
+		t.Errorf("expected the debtor to be identified by IBAN and BIC, got: %+v %+v", payment.DebtorAccount, payment.DebtorAgent)
+	}
+
+	if !strings.Contains(string(data), `xmlns="`+iso20022.Pain001Namespace+`"`) {
+		t.Errorf("expected the pain.001 namespace, got:\n%s", data)
+	}
+
+	if read := doc.Transfers(); !reflect.DeepEqual(read, transfers) {
+		t.Errorf("expected transfers: %+v, got: %+v", transfers, read)
+	}
+}
+
+// TestPain001Validation tests that pain.001 messages breaking the schema are refused, naming the element at fault.
+func TestPain001Validation(t *testing.T) {
+	testCases := []struct {
+		name            string
+		change          func(doc *iso20022.Pain001Document)
+		expectedElement string
+	}{
+		{name: "message ID too long", change: func(doc *iso20022.Pain001Document) {
+			doc.Initiation.GroupHeader.MessageID = strings.Repeat("x", 36)
+		}, expectedElement: "GrpHdr/MsgId"},
+		{name: "wrong number of transactions", change: func(doc *iso20022.Pain001Document) {
+			doc.Initiation.GroupHeader.NumberOfTransactions = "3"
+		}, expectedElement: "GrpHdr/NbOfTxs"},
+		{name: "wrong control sum", change: func(doc *iso20022.Pain001Document) {
+			doc.Initiation.GroupHeader.ControlSum = "1750.26"
+		}, expectedElement: "GrpHdr/CtrlSum"},
+		{name: "invalid execution date", change: func(doc *iso20022.Pain001Document) {
+			doc.Initiation.Payments[0].RequestedExecutionDate = "19/10/2026"
+		}, expectedElement: "PmtInf/ReqdExctnDt"},
+		{name: "invalid debtor IBAN", change: func(doc *iso20022.Pain001Document) {
+			doc.Initiation.Payments[0].DebtorAccount.ID.IBAN = "GB29-NWBK"
+		}, expectedElement: "PmtInf/DbtrAcct/Id/IBAN"},
+		{name: "invalid BIC", change: func(doc *iso20022.Pain001Document) {
+			doc.Initiation.Payments[0].DebtorAgent.Institution.BIC = "NWBK"
+		}, expectedElement: "PmtInf/DbtrAgt/FinInstnId/BIC"},
+		{name: "invalid currency", change: func(doc *iso20022.Pain001Document) {
+			doc.Initiation.Payments[0].Transactions[0].Amount.Currency = "euro"
+		}, expectedElement: "CdtTrfTxInf/Amt/InstdAmt/@Ccy"},
+		{name: "negative amount", change: func(doc *iso20022.Pain001Document) {
+			doc.Initiation.Payments[0].Transactions[0].Amount.Value = "-1500.00"
+		}, expectedElement: "CdtTrfTxInf/Amt/InstdAmt"},
+		{name: "missing creditor account", change: func(doc *iso20022.Pain001Document) {
+			doc.Initiation.Payments[0].Transactions[1].CreditorAccount = nil
+		}, expectedElement: "CdtTrfTxInf/CdtrAcct"},
+		{name: "no transactions", change: func(doc *iso20022.Pain001Document) {
+			doc.Initiation.Payments[0].Transactions = nil
+		}, expectedElement: "PmtInf/CdtTrfTxInf"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			doc, err := iso20022.ParsePain001(readSample(t, "pain001.xml"))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			tc.change(doc)
+
+			// Act
+			_, err = iso20022.Marshal(doc)
+
+			// Assert
+			var invalid *iso20022.ValidationError
+			if !errors.As(err, &invalid) || !errors.Is(err, iso20022.ErrInvalidDocument) {
+				t.Fatalf("expected a validation error, got: %v", err)
+			}
+
+			if invalid.Element != tc.expectedElement {
+				t.Errorf("expected element: %s, got: %s (%v)", tc.expectedElement, invalid.Element, err)
+			}
+		})
+	}
+}
+
+// TestCamt053RoundTrip tests that a sample camt.053 statement reads, writes and reads back unchanged, and that
+// its booked entries become reconciliation lines.
+func TestCamt053RoundTrip(t *testing.T) {
+	// Arrange
+	doc, err := iso20022.ParseCamt053(readSample(t, "camt053.xml"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Act
+	data, err := iso20022.Marshal(doc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	parsed, err := iso20022.ParseCamt053(data)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(doc, parsed) {
+		t.Errorf("expected the statement to survive the round trip, got:\n%s", data)
+	}
+
+	expected := []reconciliation.Line{
+		{Reference: "tx_1", Amount: 100, Date: time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC), Description: "Order 1001"},
+		{Reference: "rfd_1", Amount: -25.5, Date: time.Date(2026, 10, 19, 14, 5, 0, 0, time.UTC), Description: "Refund of order 1001"},
+		{Reference: "3", Amount: 40, Date: time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)},
+	}
+	if lines := parsed.Lines(); !reflect.DeepEqual(lines, expected) {
+		t.Errorf("expected lines: %+v, got: %+v", expected, lines)
+	}
+}
+
+// TestCamt053Validation tests that statements breaking the schema or of another message type are refused.
+func TestCamt053Validation(t *testing.T) {
+	sample := string(readSample(t, "camt053.xml"))
+
+	testCases := []struct {
+		name            string
+		data            string
+		expectedElement string
+	}{
+		{name: "other message type", data: strings.Replace(sample, iso20022.Camt053Namespace, "urn:iso:std:iso:20022:tech:xsd:camt.052.001.02", 1), expectedElement: "Document"},
+		{name: "not XML", data: "reference,amount,date\ntx_1,100,2026-10-19\n", expectedElement: "Document"},
+		{name: "missing balances", data: removeAll(sample, "<Bal>", "</Bal>"), expectedElement: "Stmt/Bal"},
+		{name: "unknown indicator", data: strings.Replace(sample, "<CdtDbtInd>DBIT</CdtDbtInd>", "<CdtDbtInd>DEBIT</CdtDbtInd>", 1), expectedElement: "Stmt/Ntry/CdtDbtInd"},
+		{name: "unknown status", data: strings.Replace(sample, "<Sts>PDNG</Sts>", "<Sts>PENDING</Sts>", 1), expectedElement: "Stmt/Ntry/Sts"},
+		{name: "invalid booking date", data: strings.Replace(sample, "<DtTm>2026-10-19T14:05:00+00:00</DtTm>", "<DtTm>yesterday</DtTm>", 1), expectedElement: "Stmt/Ntry/BookgDt/DtTm"},
+		{name: "missing transaction code", data: removeAll(sample, "<BkTxCd>", "</BkTxCd>"), expectedElement: "Stmt/Ntry/BkTxCd"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			_, err := iso20022.ParseCamt053([]byte(tc.data))
+
+			// Assert
+			var invalid *iso20022.ValidationError
+			if !errors.As(err, &invalid) {
+				t.Fatalf("expected a validation error, got: %v", err)
+			}
+
+			if invalid.Element != tc.expectedElement {
+				t.Errorf("expected element: %s, got: %s (%v)", tc.expectedElement, invalid.Element, err)
+			}
+		})
+	}
+}
+
+// removeAll removes every element between the start and end tags, tags included
+func removeAll(data, start, end string) string {
+	for {
+		from := strings.Index(data, start)
+		if from < 0 {
+			return data
+		}
+		to := strings.Index(data[from:], end)
+		data = data[:from] + data[from+to+len(end):]
+	}
+}
@@ -0,0 +1,235 @@
+package mocks
+
+import (
+	internal "github.com/quabynah-bilson/quantia/internal/ledger"
+	"github.com/quabynah-bilson/quantia/pkg/ledger"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MockLedgerRepository is an in-memory ledger repository
+type MockLedgerRepository struct {
+	mu              sync.Mutex
+	Accounts        map[string][]*ledger.Entry
+	Holds           map[string]*ledger.Hold
+	OverdraftLimits map[string]float32
+	posted          map[string]bool
+
+	// PostErr, when set, is returned by Post instead of recording the entries
+	PostErr error
+}
+
+// NewMockLedgerRepository creates an empty in-memory ledger
+func NewMockLedgerRepository() *MockLedgerRepository {
+	return &MockLedgerRepository{
+		Accounts:        make(map[string][]*ledger.Entry),
+		Holds:           make(map[string]*ledger.Hold),
+		OverdraftLimits: make(map[string]float32),
+		posted:          make(map[string]bool),
+	}
+}
+
+// Fund credits an account from outside the ledger, for tests that need money to move
+func (m *MockLedgerRepository) Fund(accountID string, amount float32) {
+	_ = m.Post(ledger.NewTransfer("fund:"+time.Now().Format(time.RFC3339Nano)+accountID, "funding", "system:funding", accountID, amount)...)
+}
+
+// FundAt credits an account from outside the ledger as if it had been done at the given time
+func (m *MockLedgerRepository) FundAt(accountID string, amount float32, at time.Time) error {
+	entries := ledger.NewTransfer("fund:"+accountID+":"+at.Format(time.RFC3339), "funding", "system:funding", accountID, amount)
+	entries[0].CreatedAt, entries[1].CreatedAt = at, at
+	return m.Post(entries...)
+}
+
+// Post validates and records entries, posting each reference once
+func (m *MockLedgerRepository) Post(entries ...*ledger.Entry) error {
+	if err := internal.ValidateEntries(entries); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.PostErr != nil {
+		return m.PostErr
+	}
+	return m.post(entries)
+}
+
+// Balance sums the entries of an account and deducts its active holds
+func (m *MockLedgerRepository) Balance(accountID string) (*ledger.Balance, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.balance(accountID), nil
+}
+
+// Current returns the current balance of an account
+func (m *MockLedgerRepository) Current(accountID string) float32 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.balance(accountID).Current
+}
+
+// Available returns the available balance of an account
+func (m *MockLedgerRepository) Available(accountID string) float32 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.balance(accountID).Available
+}
+
+// SetOverdraftLimit sets the overdraft limit of an account
+func (m *MockLedgerRepository) SetOverdraftLimit(accountID string, limit float32) error {
+	if limit < 0 {
+		return ledger.ErrInvalidOverdraftLimit
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.OverdraftLimits[accountID] = limit
+	return nil
+}
+
+// Entries returns the entries of an account
+func (m *MockLedgerRepository) Entries(accountID string) ([]*ledger.Entry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]*ledger.Entry(nil), m.Accounts[accountID]...), nil
+}
+
+// AccountIDs returns the IDs of the accounts that have entries, in order
+func (m *MockLedgerRepository) AccountIDs() ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	accountIDs := make([]string, 0, len(m.Accounts))
+	for accountID := range m.Accounts {
+		accountIDs = append(accountIDs, accountID)
+	}
+	sort.Strings(accountIDs)
+
+	return accountIDs, nil
+}
+
+// Hold places a hold if the available balance and overdraft limit cover it
+func (m *MockLedgerRepository) Hold(hold *ledger.Hold) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.balance(hold.AccountID).Spendable() < hold.Amount {
+		return ledger.ErrInsufficientFunds
+	}
+
+	copied := *hold
+	m.Holds[hold.ID] = &copied
+	return nil
+}
+
+// FindHold gets a hold by ID
+func (m *MockLedgerRepository) FindHold(id string) (*ledger.Hold, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	hold, ok := m.Holds[id]
+	if !ok {
+		return nil, ledger.ErrHoldNotFound
+	}
+
+	copied := *hold
+	return &copied, nil
+}
+
+// Capture settles an active hold into its credit account
+func (m *MockLedgerRepository) Capture(id string, amount float32) (*ledger.Hold, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	hold, ok := m.Holds[id]
+	if !ok {
+		return nil, ledger.ErrHoldNotFound
+	}
+	if hold.Status != ledger.HoldStatusActive || hold.IsExpired(time.Now()) {
+		return nil, ledger.ErrHoldNotActive
+	}
+	if amount <= 0 || amount > hold.Amount {
+		return nil, ledger.ErrInvalidCaptureAmount
+	}
+
+	if err := m.post(ledger.NewTransfer(hold.ID, "capture", hold.AccountID, hold.CreditAccountID, amount)); err != nil {
+		return nil, err
+	}
+	hold.Status, hold.CapturedAmount = ledger.HoldStatusCaptured, amount
+
+	copied := *hold
+	return &copied, nil
+}
+
+// Release moves an active hold to the given status
+func (m *MockLedgerRepository) Release(id string, status ledger.HoldStatus) (*ledger.Hold, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	hold, ok := m.Holds[id]
+	if !ok {
+		return nil, ledger.ErrHoldNotFound
+	}
+	if hold.Status != ledger.HoldStatusActive {
+		return nil, ledger.ErrHoldNotActive
+	}
+	hold.Status = status
+
+	copied := *hold
+	return &copied, nil
+}
+
+// ExpiredHolds returns the active holds that expired at or before now
+func (m *MockLedgerRepository) ExpiredHolds(now time.Time) ([]*ledger.Hold, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var holds []*ledger.Hold
+	for _, hold := range m.Holds {
+		if hold.IsExpired(now) {
+			copied := *hold
+			holds = append(holds, &copied)
+		}
+	}
+
+	return holds, nil
+}
+
+// post records entries, posting each reference once
+func (m *MockLedgerRepository) post(entries []*ledger.Entry) error {
+	if m.posted[entries[0].Reference] {
+		return ledger.ErrEntriesAlreadyPosted
+	}
+	m.posted[entries[0].Reference] = true
+
+	for _, entry := range entries {
+		m.Accounts[entry.AccountID] = append(m.Accounts[entry.AccountID], entry)
+	}
+
+	return nil
+}
+
+// balance computes the balance of an account
+func (m *MockLedgerRepository) balance(accountID string) *ledger.Balance {
+	balance := &ledger.Balance{AccountID: accountID, OverdraftLimit: m.OverdraftLimits[accountID]}
+	for _, entry := range m.Accounts[accountID] {
+		if entry.Type == ledger.EntryTypeCredit {
+			balance.Current += entry.Amount
+		} else {
+			balance.Current -= entry.Amount
+		}
+	}
+
+	balance.Available = balance.Current
+	now := time.Now()
+	for _, hold := range m.Holds {
+		if hold.AccountID == accountID && hold.Status == ledger.HoldStatusActive && !hold.IsExpired(now) {
+			balance.Available -= hold.Amount
+		}
+	}
+
+	return balance
+}
@@ -0,0 +1,170 @@
+package unit
+
+import (
+	"errors"
+	internal "github.com/quabynah-bilson/quantia/internal/ledger"
+	"github.com/quabynah-bilson/quantia/pkg"
+	"github.com/quabynah-bilson/quantia/pkg/ledger"
+	"github.com/quabynah-bilson/quantia/tests/ledger/mocks"
+	"testing"
+	"time"
+)
+
+const (
+	customerAccountID = "acc_customer"
+	merchantAccountID = "merchant:shop.example"
+)
+
+// newFundedLedger creates a ledger use case whose customer account holds 100.
+func newFundedLedger() (*pkg.LedgerUseCase, *mocks.MockLedgerRepository) {
+	ledgerRepo := mocks.NewMockLedgerRepository()
+	ledgerRepo.Fund(customerAccountID, 100)
+	return pkg.NewLedgerUseCase(ledgerRepo), ledgerRepo
+}
+
+// expectBalance checks the current and available balance of an account.
+func expectBalance(t *testing.T, ledgerUseCase *pkg.LedgerUseCase, accountID string, current, available float32) {
+	t.Helper()
+
+	balance, err := ledgerUseCase.GetBalance(accountID)
+	if err != nil || balance.Current != current || balance.Available != available {
+		t.Errorf("expected %s current %.2f available %.2f, got: %v %v", accountID, current, available, balance, err)
+	}
+}
+
+// TestLedgerUseCase_Authorize tests that holds reduce the available balance only.
+func TestLedgerUseCase_Authorize(t *testing.T) {
+	// Arrange
+	ledgerUseCase, _ := newFundedLedger()
+
+	// Act
+	hold, err := ledgerUseCase.Authorize(customerAccountID, merchantAccountID, 60, 0)
+
+	// Assert
+	if err != nil || hold.Status != ledger.HoldStatusActive {
+		t.Fatalf("expected an active hold, got: %v %v", hold, err)
+	}
+	if hold.ExpiresAt.Sub(hold.CreatedAt) != pkg.DefaultHoldExpiry {
+		t.Errorf("expected the default expiry, got: %v", hold.ExpiresAt.Sub(hold.CreatedAt))
+	}
+	expectBalance(t, ledgerUseCase, customerAccountID, 100, 40)
+
+	// a second hold cannot reserve more than is available
+	if _, err := ledgerUseCase.Authorize(customerAccountID, merchantAccountID, 50, 0); !errors.Is(err, ledger.ErrInsufficientFunds) {
+		t.Errorf("expected error: %v, got: %v", ledger.ErrInsufficientFunds, err)
+	}
+
+	if _, err := ledgerUseCase.Authorize(customerAccountID, customerAccountID, 10, 0); !errors.Is(err, pkg.ErrInvalidAccount) {
+		t.Errorf("expected error: %v, got: %v", pkg.ErrInvalidAccount, err)
+	}
+}
+
+// TestLedgerUseCase_CaptureAndVoid tests the settlement and release of holds.
+func TestLedgerUseCase_CaptureAndVoid(t *testing.T) {
+	testCases := []struct {
+		name              string
+		settle            func(ledgerUseCase *pkg.LedgerUseCase, id string) (*ledger.Hold, error)
+		expectedStatus    ledger.HoldStatus
+		expectedErr       error
+		expectedCustomer  float32
+		expectedAvailable float32
+		expectedMerchant  float32
+	}{
+		{
+			name: "full capture",
+			settle: func(ledgerUseCase *pkg.LedgerUseCase, id string) (*ledger.Hold, error) {
+				return ledgerUseCase.Capture(id, 0)
+			},
+			expectedStatus:    ledger.HoldStatusCaptured,
+			expectedCustomer:  40,
+			expectedAvailable: 40,
+			expectedMerchant:  60,
+		},
+		{
+			name: "partial capture releases the rest",
+			settle: func(ledgerUseCase *pkg.LedgerUseCase, id string) (*ledger.Hold, error) {
+				return ledgerUseCase.Capture(id, 25)
+			},
+			expectedStatus:    ledger.HoldStatusCaptured,
+			expectedCustomer:  75,
+			expectedAvailable: 75,
+			expectedMerchant:  25,
+		},
+		{
+			name: "capture more than held",
+			settle: func(ledgerUseCase *pkg.LedgerUseCase, id string) (*ledger.Hold, error) {
+				return ledgerUseCase.Capture(id, 61)
+			},
+			expectedErr:       ledger.ErrInvalidCaptureAmount,
+			expectedCustomer:  100,
+			expectedAvailable: 40,
+		},
+		{
+			name: "void",
+			settle: func(ledgerUseCase *pkg.LedgerUseCase, id string) (*ledger.Hold, error) {
+				return ledgerUseCase.Void(id)
+			},
+			expectedStatus:    ledger.HoldStatusVoided,
+			expectedCustomer:  100,
+			expectedAvailable: 100,
+		},
+		{
+			name: "capture after void",
+			settle: func(ledgerUseCase *pkg.LedgerUseCase, id string) (*ledger.Hold, error) {
+				_, _ = ledgerUseCase.Void(id)
+				return ledgerUseCase.Capture(id, 0)
+			},
+			expectedErr:       ledger.ErrHoldNotActive,
+			expectedCustomer:  100,
+			expectedAvailable: 100,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			ledgerUseCase, _ := newFundedLedger()
+			hold, _ := ledgerUseCase.Authorize(customerAccountID, merchantAccountID, 60, 0)
+
+			// Act
+			settled, err := tc.settle(ledgerUseCase, hold.ID)
+
+			// Assert
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error: %v, got: %v", tc.expectedErr, err)
+			}
+			if err == nil && settled.Status != tc.expectedStatus {
+				t.Errorf("expected status: %s, got: %s", tc.expectedStatus, settled.Status)
+			}
+			expectBalance(t, ledgerUseCase, customerAccountID, tc.expectedCustomer, tc.expectedAvailable)
+			expectBalance(t, ledgerUseCase, merchantAccountID, tc.expectedMerchant, tc.expectedMerchant)
+		})
+	}
+}
+
+// TestExpireHolds tests that holds which are not captured in time are released.
+func TestExpireHolds(t *testing.T) {
+	// Arrange
+	ledgerUseCase, ledgerRepo := newFundedLedger()
+	expiring, _ := ledgerUseCase.Authorize(customerAccountID, merchantAccountID, 30, 10*time.Millisecond)
+	lasting, _ := ledgerUseCase.Authorize(customerAccountID, merchantAccountID, 20, time.Hour)
+	time.Sleep(20 * time.Millisecond)
+
+	// Act
+	released := internal.ExpireHoldsOnce(ledgerRepo, time.Now())
+
+	// Assert
+	if released != 1 {
+		t.Errorf("expected 1 expired hold, got: %d", released)
+	}
+	if hold, _ := ledgerUseCase.GetHold(expiring.ID); hold.Status != ledger.HoldStatusExpired {
+		t.Errorf("expected status: %s, got: %s", ledger.HoldStatusExpired, hold.Status)
+	}
+	if hold, _ := ledgerUseCase.GetHold(lasting.ID); hold.Status != ledger.HoldStatusActive {
+		t.Errorf("expected status: %s, got: %s", ledger.HoldStatusActive, hold.Status)
+	}
+	if _, err := ledgerUseCase.Capture(expiring.ID, 0); !errors.Is(err, ledger.ErrHoldNotActive) {
+		t.Errorf("expected error: %v, got: %v", ledger.ErrHoldNotActive, err)
+	}
+	expectBalance(t, ledgerUseCase, customerAccountID, 100, 80)
+}
@@ -0,0 +1,100 @@
+package unit
+
+import (
+	"errors"
+	internal "github.com/quabynah-bilson/quantia/internal/ledger"
+	"github.com/quabynah-bilson/quantia/pkg/ledger"
+	"testing"
+)
+
+// TestValidateEntries tests that only complete and balanced postings are accepted.
+func TestValidateEntries(t *testing.T) {
+	transfer := func() []*ledger.Entry {
+		return ledger.NewTransfer("tx_1", "payment", ledger.PaymentsClearingAccountID, "merchant:shop.example", 25.5)
+	}
+
+	testCases := []struct {
+		name        string
+		entries     func() []*ledger.Entry
+		expectedErr error
+	}{
+		{
+			name:    "balanced transfer",
+			entries: transfer,
+		},
+		{
+			name:        "single entry",
+			entries:     func() []*ledger.Entry { return transfer()[:1] },
+			expectedErr: ledger.ErrUnbalancedEntries,
+		},
+		{
+			name: "unbalanced amounts",
+			entries: func() []*ledger.Entry {
+				entries := transfer()
+				entries[1].Amount = 20
+				return entries
+			},
+			expectedErr: ledger.ErrUnbalancedEntries,
+		},
+		{
+			name: "mixed references",
+			entries: func() []*ledger.Entry {
+				entries := transfer()
+				entries[1].Reference = "tx_2"
+				return entries
+			},
+			expectedErr: ledger.ErrInvalidEntry,
+		},
+		{
+			name: "negative amount",
+			entries: func() []*ledger.Entry {
+				entries := transfer()
+				entries[0].Amount, entries[1].Amount = -25.5, -25.5
+				return entries
+			},
+			expectedErr: ledger.ErrInvalidEntry,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			err := internal.ValidateEntries(tc.entries())
+
+			// Assert
+			if !errors.Is(err, tc.expectedErr) {
+				t.Errorf("expected error: %v, got: %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+// TestMerchantAccountID tests that merchants are identified by the host of their webhook URL.
+func TestMerchantAccountID(t *testing.T) {
+	if id := ledger.MerchantAccountID("https://shop.example/hooks?x=1"); id != "merchant:shop.example" {
+		t.Errorf("expected merchant:shop.example, got: %s", id)
+	}
+}
+
+// TestNewSplitTransfer tests that the credits of the same account are paid as one entry.
+func TestNewSplitTransfer(t *testing.T) {
+	// Arrange
+	credits := []ledger.Credit{
+		{AccountID: "merchant:shop.example", Amount: 10},
+		{AccountID: "merchant:partner.example", Amount: 5},
+		{AccountID: "merchant:shop.example", Amount: 2.5},
+		{AccountID: "merchant:empty.example", Amount: 0},
+	}
+
+	// Act
+	entries := ledger.NewSplitTransfer("esc_1", "release", "escrow:esc_1", credits)
+
+	// Assert
+	if err := internal.ValidateEntries(entries); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(entries) != 3 || entries[0].Amount != 17.5 || entries[1].AccountID != "merchant:shop.example" || entries[1].Amount != 12.5 || entries[2].Amount != 5 {
+		t.Errorf("expected a debit of 17.5 and credits of 12.5 and 5, got: %+v, %+v, %+v", entries[0], entries[1], entries[2])
+	}
+}
@@ -0,0 +1,93 @@
+package mocks
+
+import (
+	"github.com/quabynah-bilson/quantia/pkg/limit"
+	"sync"
+	"time"
+)
+
+// MockLimitRepository is an in-memory limit repository
+type MockLimitRepository struct {
+	mu     sync.Mutex
+	Tiers  map[string]limit.Tier
+	usages map[string]*limit.Usage
+}
+
+// NewMockLimitRepository creates an empty in-memory limit repository
+func NewMockLimitRepository() *MockLimitRepository {
+	return &MockLimitRepository{
+		Tiers:  make(map[string]limit.Tier),
+		usages: make(map[string]*limit.Usage),
+	}
+}
+
+// Tier returns the tier of the subject
+func (m *MockLimitRepository) Tier(subject string) (limit.Tier, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.Tiers[subject], nil
+}
+
+// SetTier sets the tier of the subject
+func (m *MockLimitRepository) SetTier(subject string, tier limit.Tier) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Tiers[subject] = tier
+	return nil
+}
+
+// Usage returns a copy of the subject's usage in the day and month of now
+func (m *MockLimitRepository) Usage(subject string, now time.Time) (*limit.Usage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	usage := m.usage(subject, now)
+	return &usage, nil
+}
+
+// Reserve checks and records the amount under the lock
+func (m *MockLimitRepository) Reserve(subject string, amount float32, limits limit.Limits, now time.Time) (*limit.Reservation, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := limits.Check(m.usage(subject, now), amount, now); err != nil {
+		return nil, err
+	}
+	m.add(subject, now, amount, 1)
+
+	return &limit.Reservation{Subject: subject, Amount: amount, At: now}, nil
+}
+
+// Release gives back the reservation
+func (m *MockLimitRepository) Release(reservation *limit.Reservation) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.add(reservation.Subject, reservation.At, -reservation.Amount, -1)
+	return nil
+}
+
+// usage combines the day and month windows of the subject
+func (m *MockLimitRepository) usage(subject string, now time.Time) limit.Usage {
+	usage := limit.Usage{}
+	if day, ok := m.usages[subject+":"+limit.DayWindow(now)]; ok {
+		usage.DailyAmount, usage.DailyCount = day.DailyAmount, day.DailyCount
+	}
+	if month, ok := m.usages[subject+":"+limit.MonthWindow(now)]; ok {
+		usage.MonthlyAmount, usage.MonthlyCount = month.MonthlyAmount, month.MonthlyCount
+	}
+	return usage
+}
+
+// add records an amount and a count in the day and month windows of the subject
+func (m *MockLimitRepository) add(subject string, at time.Time, amount float32, count int) {
+	day, month := subject+":"+limit.DayWindow(at), subject+":"+limit.MonthWindow(at)
+	if m.usages[day] == nil {
+		m.usages[day] = &limit.Usage{}
+	}
+	if m.usages[month] == nil {
+		m.usages[month] = &limit.Usage{}
+	}
+	m.usages[day].DailyAmount += amount
+	m.usages[day].DailyCount += count
+	m.usages[month].MonthlyAmount += amount
+	m.usages[month].MonthlyCount += count
+}
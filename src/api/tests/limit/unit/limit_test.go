@@ -0,0 +1,78 @@
+package unit
+
+import (
+	"errors"
+	"github.com/quabynah-bilson/quantia/pkg/limit"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestLimits_Check tests the limits exceeded by a transaction, with the remaining allowance and reset time.
+func TestLimits_Check(t *testing.T) {
+	now := time.Date(2026, time.January, 31, 15, 0, 0, 0, time.UTC)
+	dayReset := time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)
+
+	// the last day of the month resets both windows at once
+	monthReset := dayReset
+	limits := limit.Limits{PerTransaction: 500, DailyAmount: 1000, DailyCount: 3, MonthlyAmount: 5000, MonthlyCount: 10}
+
+	testCases := []struct {
+		name              string
+		usage             limit.Usage
+		amount            float32
+		expectedKind      limit.Kind
+		expectedRemaining float32
+		expectedResetAt   *time.Time
+	}{
+		{name: "within limits", usage: limit.Usage{DailyAmount: 400, DailyCount: 2, MonthlyAmount: 400, MonthlyCount: 2}, amount: 500},
+		{name: "per transaction", amount: 600, expectedKind: limit.KindPerTransaction, expectedRemaining: 500},
+		{name: "daily count", usage: limit.Usage{DailyAmount: 30, DailyCount: 3, MonthlyAmount: 30, MonthlyCount: 3}, amount: 10, expectedKind: limit.KindDailyCount, expectedResetAt: &dayReset},
+		{name: "daily amount", usage: limit.Usage{DailyAmount: 850, DailyCount: 2, MonthlyAmount: 850, MonthlyCount: 2}, amount: 200, expectedKind: limit.KindDailyAmount, expectedRemaining: 150, expectedResetAt: &dayReset},
+		{name: "monthly amount", usage: limit.Usage{MonthlyAmount: 4900, MonthlyCount: 9}, amount: 200, expectedKind: limit.KindMonthlyAmount, expectedRemaining: 100, expectedResetAt: &monthReset},
+		{name: "monthly count", usage: limit.Usage{MonthlyAmount: 100, MonthlyCount: 10}, amount: 10, expectedKind: limit.KindMonthlyCount, expectedResetAt: &monthReset},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			err := limits.Check(tc.usage, tc.amount, now)
+
+			// Assert
+			if tc.expectedKind == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var exceeded *limit.ExceededError
+			if !errors.As(err, &exceeded) || !errors.Is(err, limit.ErrLimitExceeded) {
+				t.Fatalf("expected a limit exceeded error, got: %v", err)
+			}
+
+			if exceeded.Kind != tc.expectedKind || exceeded.Remaining != tc.expectedRemaining {
+				t.Errorf("expected %s with %v remaining, got: %s with %v", tc.expectedKind, tc.expectedRemaining, exceeded.Kind, exceeded.Remaining)
+			}
+
+			if (tc.expectedResetAt == nil) != (exceeded.ResetAt == nil) || (tc.expectedResetAt != nil && !exceeded.ResetAt.Equal(*tc.expectedResetAt)) {
+				t.Errorf("expected reset at: %v, got: %v", tc.expectedResetAt, exceeded.ResetAt)
+			}
+
+			if tc.expectedResetAt != nil && !strings.Contains(err.Error(), tc.expectedResetAt.Format(time.RFC3339)) {
+				t.Errorf("expected the error to mention the reset time, got: %v", err)
+			}
+		})
+	}
+}
+
+// TestLimits_Unlimited tests that zero limits are not enforced.
+func TestLimits_Unlimited(t *testing.T) {
+	// Act
+	err := limit.Limits{}.Check(limit.Usage{DailyAmount: 1e6, DailyCount: 1000}, 1e6, time.Now())
+
+	// Assert
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,110 @@
+package unit
+
+import (
+	"errors"
+	"github.com/quabynah-bilson/quantia/pkg"
+	"github.com/quabynah-bilson/quantia/pkg/limit"
+	"github.com/quabynah-bilson/quantia/tests/limit/mocks"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newLimitUseCase creates a limit use case with a standard and a premium tier
+func newLimitUseCase(repo *mocks.MockLimitRepository) *pkg.LimitUseCase {
+	return pkg.NewLimitUseCase(repo, &limit.Config{
+		DefaultTier: "standard",
+		Tiers: map[limit.Tier]limit.Limits{
+			"standard": {PerTransaction: 100, DailyAmount: 200, DailyCount: 5},
+			"premium":  {PerTransaction: 1000, DailyAmount: 2000, DailyCount: 50},
+		},
+	})
+}
+
+// TestLimitUseCase_Tiers tests that accounts are limited by their tier, the default tier when they have none.
+func TestLimitUseCase_Tiers(t *testing.T) {
+	// Arrange
+	limitUseCase := newLimitUseCase(mocks.NewMockLimitRepository())
+
+	// Act
+	_, standardErr := limitUseCase.Reserve("acc_1", 150, time.Now())
+	_, tierErr := limitUseCase.SetTier("acc_1", "premium")
+	_, premiumErr := limitUseCase.Reserve("acc_1", 150, time.Now())
+	_, unknownErr := limitUseCase.SetTier("acc_1", "gold")
+
+	// Assert
+	if !errors.Is(standardErr, limit.ErrLimitExceeded) {
+		t.Errorf("expected error: %v, got: %v", limit.ErrLimitExceeded, standardErr)
+	}
+
+	if tierErr != nil || premiumErr != nil {
+		t.Errorf("expected premium accounts to pay 150, got: %v %v", tierErr, premiumErr)
+	}
+
+	if !errors.Is(unknownErr, pkg.ErrUnknownTier) {
+		t.Errorf("expected error: %v, got: %v", pkg.ErrUnknownTier, unknownErr)
+	}
+
+	status, err := limitUseCase.GetLimits("acc_1")
+	if err != nil || status.Tier != "premium" || status.Usage.DailyAmount != 150 || status.Usage.DailyCount != 1 {
+		t.Errorf("expected premium usage of 150 in 1 transaction, got: %+v %v", status, err)
+	}
+}
+
+// TestLimitUseCase_ConcurrentReservations tests that concurrent transactions cannot go over a limit together.
+func TestLimitUseCase_ConcurrentReservations(t *testing.T) {
+	// Arrange
+	limitUseCase := newLimitUseCase(mocks.NewMockLimitRepository())
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		reserved int
+	)
+
+	// Act
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := limitUseCase.Reserve("acc_1", 10, time.Now()); err == nil {
+				mu.Lock()
+				reserved++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Assert
+	if reserved != 5 {
+		t.Errorf("expected 5 reservations within the daily count, got: %d", reserved)
+	}
+}
+
+// TestLimitUseCase_Release tests that released transactions no longer count against the limits.
+func TestLimitUseCase_Release(t *testing.T) {
+	// Arrange
+	limitUseCase := newLimitUseCase(mocks.NewMockLimitRepository())
+	reservation, err := limitUseCase.Reserve("acc_1", 100, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err = limitUseCase.Reserve("acc_1", 100, time.Now()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Act
+	_, exceededErr := limitUseCase.Reserve("acc_1", 100, time.Now())
+	limitUseCase.Release(reservation)
+	_, err = limitUseCase.Reserve("acc_1", 100, time.Now())
+
+	// Assert
+	if !errors.Is(exceededErr, limit.ErrLimitExceeded) {
+		t.Errorf("expected error: %v, got: %v", limit.ErrLimitExceeded, exceededErr)
+	}
+
+	if err != nil {
+		t.Errorf("expected the released amount to be available again, got: %v", err)
+	}
+}
@@ -0,0 +1,28 @@
+package mocks
+
+import (
+	"github.com/quabynah-bilson/quantia/pkg/product"
+	ledgerMocks "github.com/quabynah-bilson/quantia/tests/ledger/mocks"
+	productMocks "github.com/quabynah-bilson/quantia/tests/product/mocks"
+	"time"
+)
+
+// SetUpAccounts gives acc_1 100 in a current account allowing overdrafts of up to 500, and acc_2 100 in a savings
+// account that does not allow any, returning the savings product
+func SetUpAccounts(productRepo *productMocks.MockProductRepository, ledgerRepo *ledgerMocks.MockLedgerRepository) (*product.Product, error) {
+	current := product.NewProduct(product.Product{Name: "Current", Type: product.TypeCurrent, Overdraft: product.OverdraftTerms{MaxLimit: 500, Rate: 18}})
+	savings := product.NewProduct(product.Product{Name: "Savings", Type: product.TypeSavings, InterestTiers: []product.RateTier{{Rate: 2}}})
+	for _, p := range []*product.Product{current, savings} {
+		if err := productRepo.Save(p); err != nil {
+			return nil, err
+		}
+	}
+
+	today := time.Now().UTC().Format(product.DateLayout)
+	_ = productRepo.Assign(product.NewAssignment("acc_1", current.ID, today))
+	_ = productRepo.Assign(product.NewAssignment("acc_2", savings.ID, today))
+
+	ledgerRepo.Fund("acc_1", 100)
+	ledgerRepo.Fund("acc_2", 100)
+	return savings, nil
+}
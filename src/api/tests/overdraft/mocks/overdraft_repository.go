@@ -0,0 +1,56 @@
+package mocks
+
+import (
+	"github.com/quabynah-bilson/quantia/pkg/overdraft"
+	"sort"
+	"sync"
+)
+
+// MockOverdraftRepository is an in-memory overdraft repository
+type MockOverdraftRepository struct {
+	mu         sync.Mutex
+	Facilities map[string]*overdraft.Facility
+}
+
+// NewMockOverdraftRepository creates an empty in-memory overdraft repository
+func NewMockOverdraftRepository() *MockOverdraftRepository {
+	return &MockOverdraftRepository{Facilities: make(map[string]*overdraft.Facility)}
+}
+
+// Save stores a copy of a facility
+func (m *MockOverdraftRepository) Save(facility *overdraft.Facility) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	copied := *facility
+	m.Facilities[facility.AccountID] = &copied
+	return nil
+}
+
+// Find returns a copy of the facility of an account
+func (m *MockOverdraftRepository) Find(accountID string) (*overdraft.Facility, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	facility, ok := m.Facilities[accountID]
+	if !ok {
+		return nil, overdraft.ErrFacilityNotFound
+	}
+
+	copied := *facility
+	return &copied, nil
+}
+
+// FindAll returns copies of every facility, oldest first
+func (m *MockOverdraftRepository) FindAll() ([]*overdraft.Facility, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	facilities := []*overdraft.Facility{}
+	for _, facility := range m.Facilities {
+		copied := *facility
+		facilities = append(facilities, &copied)
+	}
+	sort.Slice(facilities, func(i, j int) bool { return facilities[i].CreatedAt.Before(facilities[j].CreatedAt) })
+	return facilities, nil
+}
@@ -0,0 +1,272 @@
+package unit
+
+import (
+	"errors"
+	"github.com/quabynah-bilson/quantia/pkg"
+	"github.com/quabynah-bilson/quantia/pkg/event"
+	"github.com/quabynah-bilson/quantia/pkg/ledger"
+	"github.com/quabynah-bilson/quantia/pkg/product"
+	ledgerMocks "github.com/quabynah-bilson/quantia/tests/ledger/mocks"
+	"github.com/quabynah-bilson/quantia/tests/overdraft/mocks"
+	paymentMocks "github.com/quabynah-bilson/quantia/tests/payment/mocks"
+	productMocks "github.com/quabynah-bilson/quantia/tests/product/mocks"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// hook is the endpoint the accounts of the tests are notified at
+const hook = "https://bank.example.com/overdrafts"
+
+// testCase is a struct that represents a test case.
+type testCase struct {
+	name              string
+	accountID         string
+	limit             float32
+	amount            float32
+	expectedErr       error
+	expectedAvailable float32
+}
+
+// TestOverdraftUseCase_SetLimit tests that overdraft limits are approved within the maximum of the account's product.
+func TestOverdraftUseCase_SetLimit(t *testing.T) {
+	testCases := []testCase{
+		{
+			name:      "approved",
+			accountID: "acc_1",
+			limit:     300,
+		},
+		{
+			name:      "maximum",
+			accountID: "acc_1",
+			limit:     500,
+		},
+		{
+			name:        "above the maximum",
+			accountID:   "acc_1",
+			limit:       600,
+			expectedErr: pkg.ErrOverdraftLimitTooHigh,
+		},
+		{
+			name:        "negative",
+			accountID:   "acc_1",
+			limit:       -1,
+			expectedErr: ledger.ErrInvalidOverdraftLimit,
+		},
+		{
+			name:        "product without overdraft",
+			accountID:   "acc_2",
+			limit:       100,
+			expectedErr: pkg.ErrOverdraftNotAllowed,
+		},
+		{
+			name:      "no limit on a product without overdraft",
+			accountID: "acc_2",
+			limit:     0,
+		},
+		{
+			name:        "account without product",
+			accountID:   "acc_3",
+			limit:       100,
+			expectedErr: product.ErrAssignmentNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			productRepo := productMocks.NewMockProductRepository()
+			ledgerRepo := ledgerMocks.NewMockLedgerRepository()
+			if _, err := mocks.SetUpAccounts(productRepo, ledgerRepo); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			overdraftUseCase := pkg.NewOverdraftUseCase(mocks.NewMockOverdraftRepository(), productRepo, ledgerRepo, paymentMocks.NewMockPaymentRepository())
+
+			// Act
+			facility, err := overdraftUseCase.SetLimit(tc.accountID, tc.limit, hook)
+
+			// Assert
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error: %v, got: %v", tc.expectedErr, err)
+			}
+
+			balance, _ := ledgerRepo.Balance(tc.accountID)
+			if tc.expectedErr == nil && (facility.Limit != tc.limit || facility.Url != hook || balance.OverdraftLimit != tc.limit) {
+				t.Errorf("expected a limit of %v in the facility and the ledger, got: %+v and %+v", tc.limit, facility, balance)
+			}
+			if tc.expectedErr != nil && balance.OverdraftLimit != 0 {
+				t.Errorf("expected no overdraft limit, got: %v", balance.OverdraftLimit)
+			}
+		})
+	}
+}
+
+// TestOverdraftUseCase_Hold tests that holds may use the overdraft limit, but not more.
+func TestOverdraftUseCase_Hold(t *testing.T) {
+	testCases := []testCase{
+		{
+			name:              "within the balance",
+			amount:            80,
+			expectedAvailable: 20,
+		},
+		{
+			name:              "into the overdraft",
+			amount:            250,
+			expectedAvailable: -150,
+		},
+		{
+			name:              "whole overdraft",
+			amount:            400,
+			expectedAvailable: -300,
+		},
+		{
+			name:              "beyond the overdraft",
+			amount:            400.01,
+			expectedErr:       ledger.ErrInsufficientFunds,
+			expectedAvailable: 100,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			productRepo := productMocks.NewMockProductRepository()
+			ledgerRepo := ledgerMocks.NewMockLedgerRepository()
+			if _, err := mocks.SetUpAccounts(productRepo, ledgerRepo); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			overdraftUseCase := pkg.NewOverdraftUseCase(mocks.NewMockOverdraftRepository(), productRepo, ledgerRepo, paymentMocks.NewMockPaymentRepository())
+			if _, err := overdraftUseCase.SetLimit("acc_1", 300, hook); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// Act
+			err := ledgerRepo.Hold(ledger.NewHold("acc_1", ledger.CardSettlementAccountID, tc.amount, time.Hour))
+
+			// Assert
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error: %v, got: %v", tc.expectedErr, err)
+			}
+
+			if available := ledgerRepo.Available("acc_1"); available != tc.expectedAvailable {
+				t.Errorf("expected available balance: %v, got: %v", tc.expectedAvailable, available)
+			}
+		})
+	}
+}
+
+// TestOverdraftUseCase_SetLimit_InUse tests that a limit cannot be lowered below what the account owes.
+func TestOverdraftUseCase_SetLimit_InUse(t *testing.T) {
+	// Arrange
+	productRepo := productMocks.NewMockProductRepository()
+	ledgerRepo := ledgerMocks.NewMockLedgerRepository()
+	if _, err := mocks.SetUpAccounts(productRepo, ledgerRepo); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	overdraftUseCase := pkg.NewOverdraftUseCase(mocks.NewMockOverdraftRepository(), productRepo, ledgerRepo, paymentMocks.NewMockPaymentRepository())
+	if _, err := overdraftUseCase.SetLimit("acc_1", 300, hook); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := ledgerRepo.Post(ledger.NewTransfer("trf_1", "test", "acc_1", "acc_2", 250)...); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Act
+	_, lowErr := overdraftUseCase.SetLimit("acc_1", 100, "")
+	facility, err := overdraftUseCase.SetLimit("acc_1", 150, "")
+
+	// Assert
+	if !errors.Is(lowErr, pkg.ErrOverdraftLimitInUse) {
+		t.Errorf("expected error: %v, got: %v", pkg.ErrOverdraftLimitInUse, lowErr)
+	}
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if facility.Limit != 150 || facility.Url != hook {
+		t.Errorf("expected a limit of 150 keeping the endpoint, got: %+v", facility)
+	}
+}
+
+// TestOverdraftUseCase_RunChecks tests that accounts are notified once when they enter their overdraft and
+// once when they leave it.
+func TestOverdraftUseCase_RunChecks(t *testing.T) {
+	// Arrange
+	productRepo := productMocks.NewMockProductRepository()
+	ledgerRepo := ledgerMocks.NewMockLedgerRepository()
+	paymentRepo := paymentMocks.NewMockPaymentRepository()
+	if _, err := mocks.SetUpAccounts(productRepo, ledgerRepo); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	overdraftUseCase := pkg.NewOverdraftUseCase(mocks.NewMockOverdraftRepository(), productRepo, ledgerRepo, paymentRepo)
+	if _, err := overdraftUseCase.SetLimit("acc_1", 300, hook); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Act
+	steps := []int{overdraftUseCase.RunChecks()}
+	if err := ledgerRepo.Post(ledger.NewTransfer("trf_1", "test", "acc_1", "acc_2", 250)...); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	steps = append(steps, overdraftUseCase.RunChecks(), overdraftUseCase.RunChecks())
+	overdrawn, _ := overdraftUseCase.GetFacility("acc_1")
+	if err := ledgerRepo.Post(ledger.NewTransfer("trf_2", "test", "acc_2", "acc_1", 150)...); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	steps = append(steps, overdraftUseCase.RunChecks())
+
+	// Assert
+	if expected := []int{0, 1, 0, 1}; !reflect.DeepEqual(steps, expected) {
+		t.Errorf("expected changes: %v, got: %v", expected, steps)
+	}
+
+	if !overdrawn.Overdrawn || overdrawn.OverdrawnSince == nil {
+		t.Errorf("expected the account to be overdrawn, got: %+v", overdrawn)
+	}
+
+	expected := []event.Type{event.TypeAccountOverdrawn, event.TypeAccountOverdraftRepaid}
+	if types := paymentRepo.EventTypes(); !reflect.DeepEqual(types, expected) {
+		t.Fatalf("expected events: %v, got: %v", expected, types)
+	}
+
+	if data := string(paymentRepo.Events[0].Data); data != `{"account_id":"acc_1","balance":-150,"limit":300,"overdrawn_since":"`+overdrawn.OverdrawnSince.Format(time.RFC3339Nano)+`"}` {
+		t.Errorf("unexpected overdrawn event data: %s", data)
+	}
+
+	facility, _ := overdraftUseCase.GetFacility("acc_1")
+	if facility.Overdrawn || facility.OverdrawnSince != nil {
+		t.Errorf("expected the account to be out of its overdraft, got: %+v", facility)
+	}
+}
+
+// TestProductUseCase_AssignProduct_CapsOverdraft tests that an account moved to a product allowing less keeps
+// an overdraft within its maximum.
+func TestProductUseCase_AssignProduct_CapsOverdraft(t *testing.T) {
+	// Arrange
+	productRepo := productMocks.NewMockProductRepository()
+	ledgerRepo := ledgerMocks.NewMockLedgerRepository()
+	savings, err := mocks.SetUpAccounts(productRepo, ledgerRepo)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	overdraftUseCase := pkg.NewOverdraftUseCase(mocks.NewMockOverdraftRepository(), productRepo, ledgerRepo, paymentMocks.NewMockPaymentRepository())
+	if _, err = overdraftUseCase.SetLimit("acc_1", 300, hook); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	productUseCase := pkg.NewProductUseCase(productRepo, ledgerRepo, nil, pkg.ProductConfig{})
+	productUseCase.SetOverdrafts(overdraftUseCase)
+
+	// Act
+	_, err = productUseCase.AssignProduct("acc_1", savings.ID)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	facility, _ := overdraftUseCase.GetFacility("acc_1")
+	balance, _ := ledgerRepo.Balance("acc_1")
+	if facility.Limit != 0 || balance.OverdraftLimit != 0 {
+		t.Errorf("expected the overdraft to be withdrawn, got: %+v and %+v", facility, balance)
+	}
+}
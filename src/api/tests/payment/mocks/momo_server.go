@@ -0,0 +1,150 @@
+package mocks
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RejectingMoMoNumber is a wallet number whose owner rejects every request-to-pay.
+const RejectingMoMoNumber = "233200000000"
+
+// MockMoMoServer is a local stand-in for the mobile money API. Requests are accepted with 202,
+// stay PENDING for CallbackDelay and are then resolved and reported to the X-Callback-Url,
+// like the real provider does once the payer approves or rejects the prompt on their phone.
+type MockMoMoServer struct {
+	*httptest.Server
+	CallbackDelay time.Duration
+
+	// AccountHolders maps wallet numbers to the names returned by the account holder lookup
+	AccountHolders map[string]string
+
+	mu       sync.Mutex
+	requests map[string]map[string]interface{}
+}
+
+// NewMockMoMoServer starts a new mobile money stand-in server
+func NewMockMoMoServer(callbackDelay time.Duration) *MockMoMoServer {
+	m := &MockMoMoServer{
+		CallbackDelay:  callbackDelay,
+		AccountHolders: make(map[string]string),
+		requests:       make(map[string]map[string]interface{}),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/collection/token/", m.token)
+	mux.HandleFunc("/disbursement/token/", m.token)
+	mux.HandleFunc("/collection/v1_0/requesttopay", m.create)
+	mux.HandleFunc("/collection/v1_0/requesttopay/", m.status)
+	mux.HandleFunc("/disbursement/v1_0/transfer", m.create)
+	mux.HandleFunc("/disbursement/v1_0/transfer/", m.status)
+	mux.HandleFunc("/disbursement/v1_0/accountholder/msisdn/", m.accountHolder)
+	m.Server = httptest.NewServer(mux)
+
+	return m
+}
+
+// token issues an access token for valid basic credentials
+func (m *MockMoMoServer) token(w http.ResponseWriter, r *http.Request) {
+	if user, key, ok := r.BasicAuth(); !ok || user == "" || key == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"access_token": "mock-access-token",
+		"token_type":   "access_token",
+		"expires_in":   3600,
+	})
+}
+
+// create accepts a request-to-pay or transfer and resolves it after the callback delay
+func (m *MockMoMoServer) create(w http.ResponseWriter, r *http.Request) {
+	referenceID := r.Header.Get("X-Reference-Id")
+	if r.Header.Get("Authorization") != "Bearer mock-access-token" || referenceID == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var request map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	request["status"] = "PENDING"
+
+	m.mu.Lock()
+	if _, exists := m.requests[referenceID]; exists {
+		m.mu.Unlock()
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+	m.requests[referenceID] = request
+	m.mu.Unlock()
+
+	callbackURL := r.Header.Get("X-Callback-Url")
+	time.AfterFunc(m.CallbackDelay, func() { m.resolve(referenceID, callbackURL) })
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// status returns the current state of a request-to-pay or transfer
+func (m *MockMoMoServer) status(w http.ResponseWriter, r *http.Request) {
+	referenceID := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+
+	m.mu.Lock()
+	request, ok := m.requests[referenceID]
+	var body []byte
+	if ok {
+		body, _ = json.Marshal(request)
+	}
+	m.mu.Unlock()
+
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(body)
+}
+
+// accountHolder returns the basic information of a known wallet number
+func (m *MockMoMoServer) accountHolder(w http.ResponseWriter, r *http.Request) {
+	msisdn := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/disbursement/v1_0/accountholder/msisdn/"), "/basicuserinfo")
+	name, ok := m.AccountHolders[msisdn]
+	if r.Header.Get("Authorization") != "Bearer mock-access-token" || !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	given, family, _ := strings.Cut(name, " ")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"given_name":  given,
+		"family_name": family,
+	})
+}
+
+// resolve approves or rejects a pending request and posts the result to the callback URL
+func (m *MockMoMoServer) resolve(referenceID, callbackURL string) {
+	m.mu.Lock()
+	request := m.requests[referenceID]
+	request["status"], request["financialTransactionId"] = "SUCCESSFUL", "ft-"+referenceID[:8]
+	if payer, ok := request["payer"].(map[string]interface{}); ok && payer["partyId"] == RejectingMoMoNumber {
+		request["status"], request["reason"] = "FAILED", "APPROVAL_REJECTED"
+		delete(request, "financialTransactionId")
+	}
+	body, _ := json.Marshal(request)
+	m.mu.Unlock()
+
+	if callbackURL != "" {
+		resp, err := http.Post(callbackURL, "application/json", bytes.NewReader(body))
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+	}
+}
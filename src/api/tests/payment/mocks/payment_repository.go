@@ -1,11 +1,137 @@
 package mocks
 
-import "github.com/quabynah-bilson/quantia/pkg/payment"
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/quabynah-bilson/quantia/pkg/event"
+	"github.com/quabynah-bilson/quantia/pkg/payment"
+	"sync"
+	"time"
+)
 
 // MockPaymentRepository is a mock of the payment repository
 type MockPaymentRepository struct {
-	PayFn       func(amount float32, url string) (*payment.Transaction, error)
-	SubscribeFn func(url string, queue chan *payment.WebhookPayload) error
+	PayFn           func(amount float32, url string) (*payment.Transaction, error)
+	SaveFn          func(transaction *payment.Transaction) error
+	FindFn          func(id string) (*payment.Transaction, error)
+	SaveRefundFn    func(refund *payment.Refund) error
+	FindRefundFn    func(id string) (*payment.Refund, error)
+	FindRefundsFn   func(transactionID string) ([]*payment.Refund, error)
+	ReserveRefundFn func(transactionID string, amount, limit float32) error
+	ReleaseRefundFn func(transactionID string, amount float32) error
+	NotifyFn        func(url string, envelope *event.Envelope) error
+	SubscribeFn     func(ctx context.Context, url string, queue chan *payment.WebhookPayload) error
+	CheckpointFn    func(payload *payment.WebhookPayload) error
+	RestoreFn       func() ([]*payment.WebhookPayload, error)
+
+	mu           sync.Mutex
+	Transactions map[string]*payment.Transaction
+	Refunds      map[string]*payment.Refund
+	Reserved     map[string]float32
+	Events       []*event.Envelope
+}
+
+// NewMockPaymentRepository creates a mock payment repository that keeps transactions and
+// notified events in memory
+func NewMockPaymentRepository() *MockPaymentRepository {
+	m := &MockPaymentRepository{
+		Transactions: make(map[string]*payment.Transaction),
+		Refunds:      make(map[string]*payment.Refund),
+		Reserved:     make(map[string]float32),
+	}
+
+	m.PayFn = func(amount float32, url string) (*payment.Transaction, error) {
+		transaction := &payment.Transaction{
+			ID:        uuid.NewString(),
+			Amount:    amount,
+			Status:    payment.TransactionStatusPending,
+			Url:       url,
+			CreatedAt: time.Now().UTC(),
+		}
+		return transaction, m.SaveFn(transaction)
+	}
+	m.SaveFn = func(transaction *payment.Transaction) error {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		copied := *transaction
+		m.Transactions[transaction.ID] = &copied
+		return nil
+	}
+	m.FindFn = func(id string) (*payment.Transaction, error) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		transaction, ok := m.Transactions[id]
+		if !ok {
+			return nil, payment.ErrTransactionNotFound
+		}
+		copied := *transaction
+		return &copied, nil
+	}
+	m.SaveRefundFn = func(refund *payment.Refund) error {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		copied := *refund
+		m.Refunds[refund.ID] = &copied
+		return nil
+	}
+	m.FindRefundFn = func(id string) (*payment.Refund, error) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		refund, ok := m.Refunds[id]
+		if !ok {
+			return nil, payment.ErrRefundNotFound
+		}
+		copied := *refund
+		return &copied, nil
+	}
+	m.FindRefundsFn = func(transactionID string) ([]*payment.Refund, error) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		var refunds []*payment.Refund
+		for _, refund := range m.Refunds {
+			if refund.TransactionID == transactionID {
+				copied := *refund
+				refunds = append(refunds, &copied)
+			}
+		}
+		return refunds, nil
+	}
+	m.ReserveRefundFn = func(transactionID string, amount, limit float32) error {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if m.Reserved[transactionID]+amount > limit {
+			return payment.ErrRefundExceedsAmount
+		}
+		m.Reserved[transactionID] += amount
+		return nil
+	}
+	m.ReleaseRefundFn = func(transactionID string, amount float32) error {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.Reserved[transactionID] -= amount
+		return nil
+	}
+	m.NotifyFn = func(url string, envelope *event.Envelope) error {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.Events = append(m.Events, envelope)
+		return nil
+	}
+
+	return m
+}
+
+// EventTypes returns the types of the notified events, in order
+func (m *MockPaymentRepository) EventTypes() []event.Type {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var types []event.Type
+	for _, envelope := range m.Events {
+		types = append(types, envelope.Type)
+	}
+
+	return types
 }
 
 // Pay calls the PayFn
@@ -13,7 +139,57 @@ func (m *MockPaymentRepository) Pay(amount float32, url string) (*payment.Transa
 	return m.PayFn(amount, url)
 }
 
+// Save calls the SaveFn
+func (m *MockPaymentRepository) Save(transaction *payment.Transaction) error {
+	return m.SaveFn(transaction)
+}
+
+// Find calls the FindFn
+func (m *MockPaymentRepository) Find(id string) (*payment.Transaction, error) {
+	return m.FindFn(id)
+}
+
+// SaveRefund calls the SaveRefundFn
+func (m *MockPaymentRepository) SaveRefund(refund *payment.Refund) error {
+	return m.SaveRefundFn(refund)
+}
+
+// FindRefund calls the FindRefundFn
+func (m *MockPaymentRepository) FindRefund(id string) (*payment.Refund, error) {
+	return m.FindRefundFn(id)
+}
+
+// FindRefunds calls the FindRefundsFn
+func (m *MockPaymentRepository) FindRefunds(transactionID string) ([]*payment.Refund, error) {
+	return m.FindRefundsFn(transactionID)
+}
+
+// ReserveRefund calls the ReserveRefundFn
+func (m *MockPaymentRepository) ReserveRefund(transactionID string, amount, limit float32) error {
+	return m.ReserveRefundFn(transactionID, amount, limit)
+}
+
+// ReleaseRefund calls the ReleaseRefundFn
+func (m *MockPaymentRepository) ReleaseRefund(transactionID string, amount float32) error {
+	return m.ReleaseRefundFn(transactionID, amount)
+}
+
+// Notify calls the NotifyFn
+func (m *MockPaymentRepository) Notify(url string, envelope *event.Envelope) error {
+	return m.NotifyFn(url, envelope)
+}
+
 // Subscribe calls the SubscribeFn
-func (m *MockPaymentRepository) Subscribe(url string, queue chan *payment.WebhookPayload) error {
-	return m.SubscribeFn(url, queue)
+func (m *MockPaymentRepository) Subscribe(ctx context.Context, url string, queue chan *payment.WebhookPayload) error {
+	return m.SubscribeFn(ctx, url, queue)
+}
+
+// Checkpoint calls the CheckpointFn
+func (m *MockPaymentRepository) Checkpoint(payload *payment.WebhookPayload) error {
+	return m.CheckpointFn(payload)
+}
+
+// Restore calls the RestoreFn
+func (m *MockPaymentRepository) Restore() ([]*payment.WebhookPayload, error) {
+	return m.RestoreFn()
 }
@@ -0,0 +1,39 @@
+package mocks
+
+import (
+	"github.com/quabynah-bilson/quantia/adapters/payment/provider"
+	"github.com/quabynah-bilson/quantia/pkg"
+	"github.com/quabynah-bilson/quantia/pkg/ledger"
+	"github.com/quabynah-bilson/quantia/pkg/payment"
+)
+
+const (
+	// DeclinedWallet is a wallet the simulators of NewSimulator refuse to charge or pay
+	DeclinedWallet = "233200000000"
+
+	// Wallet is a wallet the simulators of NewSimulator charge and pay
+	Wallet = "233240000001"
+)
+
+// NewSimulator creates a simulator with the given configuration that declines DeclinedWallet
+func NewSimulator(config provider.SimulatorConfig) *provider.Simulator {
+	config.Sources = map[string]provider.Behaviour{DeclinedWallet: provider.BehaviourDecline}
+	return provider.NewSimulator(config)
+}
+
+// NewPaymentUseCase creates a payment use case whose payments go through the simulator, and which completes
+// them as the simulator reports their results
+func NewPaymentUseCase(paymentRepo *MockPaymentRepository, ledgerRepo ledger.Repository, simulator *provider.Simulator) *pkg.PaymentUseCase {
+	paymentUseCase := pkg.NewPaymentUseCase(paymentRepo, ledgerRepo, &MockURLGuard{}, simulator)
+	simulator.OnResult(func(result *payment.ProviderResult) {
+		_, _ = paymentUseCase.HandleProviderResult(result)
+	})
+
+	return paymentUseCase
+}
+
+// RouteResults makes the results the simulator reports for the matching references (e.g. transfers) reach the
+// handler through a payment use case, like in the server
+func RouteResults(ledgerRepo ledger.Repository, simulator *provider.Simulator, matches func(reference string) bool, handler func(result *payment.ProviderResult) error) {
+	NewPaymentUseCase(NewMockPaymentRepository(), ledgerRepo, simulator).RouteResults(matches, handler)
+}
@@ -0,0 +1,21 @@
+package mocks
+
+import (
+	"context"
+	"github.com/quabynah-bilson/quantia/pkg/payment"
+)
+
+// InternalWebhookURL is a merchant URL that points into the internal network.
+const InternalWebhookURL = "http://169.254.169.254/latest/meta-data"
+
+// MockURLGuard is a mock of the URL guard that only rejects InternalWebhookURL
+type MockURLGuard struct{}
+
+// CheckURL rejects InternalWebhookURL
+func (m *MockURLGuard) CheckURL(_ context.Context, rawURL string) error {
+	if rawURL == InternalWebhookURL {
+		return payment.ErrUnsafeURL
+	}
+
+	return nil
+}
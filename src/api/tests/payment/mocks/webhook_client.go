@@ -0,0 +1,16 @@
+package mocks
+
+import (
+	"context"
+	"github.com/quabynah-bilson/quantia/pkg/payment"
+)
+
+// MockWebhookClient is a mock of the webhook client
+type MockWebhookClient struct {
+	DeliverFn func(ctx context.Context, payload *payment.WebhookPayload) error
+}
+
+// Deliver calls the DeliverFn
+func (m *MockWebhookClient) Deliver(ctx context.Context, payload *payment.WebhookPayload) error {
+	return m.DeliverFn(ctx, payload)
+}
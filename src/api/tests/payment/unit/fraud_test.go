@@ -0,0 +1,120 @@
+package unit
+
+import (
+	"errors"
+	"github.com/quabynah-bilson/quantia/adapters/payment/provider"
+	"github.com/quabynah-bilson/quantia/pkg"
+	"github.com/quabynah-bilson/quantia/pkg/event"
+	"github.com/quabynah-bilson/quantia/pkg/fraud"
+	"github.com/quabynah-bilson/quantia/pkg/limit"
+	"github.com/quabynah-bilson/quantia/pkg/payment"
+	fraudMocks "github.com/quabynah-bilson/quantia/tests/fraud/mocks"
+	ledgerMocks "github.com/quabynah-bilson/quantia/tests/ledger/mocks"
+	limitMocks "github.com/quabynah-bilson/quantia/tests/limit/mocks"
+	"github.com/quabynah-bilson/quantia/tests/payment/mocks"
+	"reflect"
+	"testing"
+)
+
+// TestPaymentUseCase_Fraud tests that payments are blocked or held for review by the fraud checks, and
+// resumed by the analysts' decisions.
+func TestPaymentUseCase_Fraud(t *testing.T) {
+	testCases := []struct {
+		name           string
+		origin         *fraud.Origin
+		review         *bool
+		expectedErr    error
+		expectedStatus payment.TransactionStatus
+		expectedEvents []event.Type
+	}{
+		{
+			name:           "known device",
+			origin:         &fraud.Origin{DeviceID: "device_1"},
+			expectedStatus: payment.TransactionStatusSuccess,
+			expectedEvents: []event.Type{event.TypePaymentSucceeded},
+		},
+		{
+			name:           "IP address abroad",
+			origin:         &fraud.Origin{DeviceID: "device_1", IPAddress: "5.3.0.1", Country: "GH"},
+			expectedErr:    fraud.ErrPaymentBlocked,
+			expectedStatus: payment.TransactionStatusFailed,
+			expectedEvents: []event.Type{event.TypePaymentFailed},
+		},
+		{
+			name:           "new device held for review",
+			origin:         &fraud.Origin{DeviceID: "device_2"},
+			expectedStatus: payment.TransactionStatusInReview,
+		},
+		{
+			name:           "new device approved by an analyst",
+			origin:         &fraud.Origin{DeviceID: "device_2"},
+			review:         func() *bool { approve := true; return &approve }(),
+			expectedStatus: payment.TransactionStatusSuccess,
+			expectedEvents: []event.Type{event.TypePaymentSucceeded},
+		},
+		{
+			name:           "new device rejected by an analyst",
+			origin:         &fraud.Origin{DeviceID: "device_2"},
+			review:         new(bool),
+			expectedStatus: payment.TransactionStatusFailed,
+			expectedEvents: []event.Type{event.TypePaymentFailed},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			paymentRepo := mocks.NewMockPaymentRepository()
+			fraudRepo := fraudMocks.NewMockFraudRepository()
+			fraudRepo.Profiles["card_1"] = &fraud.Profile{Devices: []string{"device_1"}}
+			fraudUseCase := pkg.NewFraudUseCase(fraudRepo, &fraud.Config{
+				ReviewScore: 20,
+				BlockScore:  50,
+				Rules: map[fraud.RuleName]fraud.RuleConfig{
+					fraud.RuleNewDevice:   {Score: 20},
+					fraud.RuleGeoMismatch: {Score: 50},
+				},
+			}, fraudMocks.MockGeoLocator{"5.3.0.1": "RU"})
+			limitUseCase := pkg.NewLimitUseCase(limitMocks.NewMockLimitRepository(), &limit.Config{
+				DefaultTier: "standard",
+				Tiers:       map[limit.Tier]limit.Limits{"standard": {DailyCount: 10}},
+			})
+
+			paymentUseCase := pkg.NewPaymentUseCase(paymentRepo, ledgerMocks.NewMockLedgerRepository(), &mocks.MockURLGuard{}, provider.NewSimulator(provider.SimulatorConfig{}))
+			paymentUseCase.SetLimits(limitUseCase)
+			paymentUseCase.SetFraud(fraudUseCase)
+
+			// Act
+			transaction, err := paymentUseCase.MakePayment(100, "https://quantia-webhooks.com", "card_1", tc.origin)
+			if tc.review != nil {
+				if _, reviewErr := fraudUseCase.Review(transaction.ID, *tc.review, "analyst@quantia.com", ""); reviewErr != nil {
+					t.Fatalf("unexpected error: %v", reviewErr)
+				}
+			}
+
+			// Assert
+			if !errors.Is(err, tc.expectedErr) {
+				t.Errorf("expected error: %v, got: %v", tc.expectedErr, err)
+			}
+
+			stored, _ := paymentRepo.Find(transaction.ID)
+			if stored.Status != tc.expectedStatus {
+				t.Errorf("expected status: %s, got: %s", tc.expectedStatus, stored.Status)
+			}
+
+			if !reflect.DeepEqual(paymentRepo.EventTypes(), tc.expectedEvents) {
+				t.Errorf("expected events: %v, got: %v", tc.expectedEvents, paymentRepo.EventTypes())
+			}
+
+			if _, err := fraudUseCase.GetAssessment(transaction.ID); err != nil {
+				t.Errorf("expected the decision to be recorded, got: %v", err)
+			}
+
+			// failed payments do not count against the payer's limits
+			status, _ := limitUseCase.GetLimits("card_1")
+			if counted := stored.Status != payment.TransactionStatusFailed; counted != (status.Usage.DailyCount == 1) {
+				t.Errorf("expected the payment to count: %v, got: %+v", counted, status.Usage)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package unit
+
+import (
+	"errors"
+	"github.com/quabynah-bilson/quantia/adapters/payment/provider"
+	"github.com/quabynah-bilson/quantia/pkg"
+	"github.com/quabynah-bilson/quantia/pkg/limit"
+	"github.com/quabynah-bilson/quantia/pkg/payment"
+	ledgerMocks "github.com/quabynah-bilson/quantia/tests/ledger/mocks"
+	limitMocks "github.com/quabynah-bilson/quantia/tests/limit/mocks"
+	"github.com/quabynah-bilson/quantia/tests/payment/mocks"
+	"testing"
+	"time"
+)
+
+// TestPaymentUseCase_Limits tests that payments count against the limits of their source.
+func TestPaymentUseCase_Limits(t *testing.T) {
+	// Arrange
+	const declinedSource = "card_declined"
+	limitRepo := limitMocks.NewMockLimitRepository()
+	limitUseCase := pkg.NewLimitUseCase(limitRepo, &limit.Config{
+		DefaultTier: "standard",
+		Tiers:       map[limit.Tier]limit.Limits{"standard": {DailyAmount: 150, DailyCount: 2}},
+	})
+	simulator := provider.NewSimulator(provider.SimulatorConfig{Sources: map[string]provider.Behaviour{declinedSource: provider.BehaviourDecline}})
+	paymentUseCase := pkg.NewPaymentUseCase(mocks.NewMockPaymentRepository(), ledgerMocks.NewMockLedgerRepository(), &mocks.MockURLGuard{}, simulator)
+	paymentUseCase.SetLimits(limitUseCase)
+
+	// Act
+	_, firstErr := paymentUseCase.MakePayment(100, "https://quantia-webhooks.com", "card_1", nil)
+	_, exceededErr := paymentUseCase.MakePayment(100, "https://quantia-webhooks.com", "card_1", nil)
+	_, otherSourceErr := paymentUseCase.MakePayment(100, "https://quantia-webhooks.com", "card_2", nil)
+	_, declinedErr := paymentUseCase.MakePayment(100, "https://quantia-webhooks.com", declinedSource, nil)
+
+	// Assert
+	if firstErr != nil || otherSourceErr != nil {
+		t.Errorf("unexpected errors: %v %v", firstErr, otherSourceErr)
+	}
+
+	var exceeded *limit.ExceededError
+	if !errors.As(exceededErr, &exceeded) || exceeded.Kind != limit.KindDailyAmount || exceeded.Remaining != 50 {
+		t.Errorf("expected the daily amount to allow 50 more, got: %v", exceededErr)
+	}
+
+	if !errors.Is(declinedErr, payment.ErrPaymentDeclined) {
+		t.Fatalf("expected error: %v, got: %v", payment.ErrPaymentDeclined, declinedErr)
+	}
+
+	status, _ := limitUseCase.GetLimits(declinedSource)
+	if status.Usage.DailyCount != 0 || status.Usage.DailyAmount != 0 {
+		t.Errorf("expected a declined payment not to count, got: %+v", status.Usage)
+	}
+}
+
+// TestPaymentUseCase_Limits_ReleasedWhereReserved tests that a payment that fails is given back to the windows
+// it was counted in, even when it was recorded on another day.
+func TestPaymentUseCase_Limits_ReleasedWhereReserved(t *testing.T) {
+	// Arrange
+	limitUseCase := pkg.NewLimitUseCase(limitMocks.NewMockLimitRepository(), &limit.Config{
+		DefaultTier: "standard",
+		Tiers:       map[limit.Tier]limit.Limits{"standard": {DailyAmount: 150, DailyCount: 2}},
+	})
+	paymentRepo := mocks.NewMockPaymentRepository()
+	simulator := provider.NewSimulator(provider.SimulatorConfig{Behaviour: provider.BehaviourAsync, AsyncDelay: 20 * time.Millisecond, AsyncOutcome: provider.BehaviourDecline})
+	paymentUseCase := pkg.NewPaymentUseCase(paymentRepo, ledgerMocks.NewMockLedgerRepository(), &mocks.MockURLGuard{}, simulator)
+	paymentUseCase.SetLimits(limitUseCase)
+
+	declined := make(chan struct{})
+	simulator.OnResult(func(result *payment.ProviderResult) {
+		_, _ = paymentUseCase.HandleProviderResult(result)
+		close(declined)
+	})
+
+	// Act
+	pending, err := paymentUseCase.MakePayment(100, "https://quantia-webhooks.com", "card_1", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the transaction was recorded just before midnight, the payment counted just after
+	recorded, _ := paymentRepo.Find(pending.ID)
+	recorded.CreatedAt = recorded.CreatedAt.AddDate(0, 0, -1)
+	_ = paymentRepo.Save(recorded)
+	<-declined
+
+	// Assert
+	status, _ := limitUseCase.GetLimits("card_1")
+	if status.Usage.DailyCount != 0 || status.Usage.DailyAmount != 0 {
+		t.Errorf("expected the declined payment to be given back today, got: %+v", status.Usage)
+	}
+}
+
+// TestPaymentUseCase_Limits_SourceRequired tests that payments without a source are refused once limits apply,
+// rather than escaping them.
+func TestPaymentUseCase_Limits_SourceRequired(t *testing.T) {
+	// Arrange
+	paymentRepo := mocks.NewMockPaymentRepository()
+	paymentUseCase := pkg.NewPaymentUseCase(paymentRepo, ledgerMocks.NewMockLedgerRepository(), &mocks.MockURLGuard{}, provider.NewSimulator(provider.SimulatorConfig{}))
+	paymentUseCase.SetLimits(pkg.NewLimitUseCase(limitMocks.NewMockLimitRepository(), &limit.Config{
+		DefaultTier: "standard",
+		Tiers:       map[limit.Tier]limit.Limits{"standard": {DailyAmount: 150, DailyCount: 2}},
+	}))
+
+	// Act
+	transaction, err := paymentUseCase.MakePayment(100, "https://quantia-webhooks.com", "", nil)
+
+	// Assert
+	if !errors.Is(err, pkg.ErrSourceRequired) || transaction != nil {
+		t.Fatalf("expected error: %v, got: %v (%+v)", pkg.ErrSourceRequired, err, transaction)
+	}
+
+	if len(paymentRepo.Transactions) != 0 {
+		t.Errorf("expected no transaction to be recorded, got: %d", len(paymentRepo.Transactions))
+	}
+}
@@ -0,0 +1,192 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"github.com/quabynah-bilson/quantia/adapters/payment/provider"
+	"github.com/quabynah-bilson/quantia/pkg"
+	"github.com/quabynah-bilson/quantia/pkg/payment"
+	ledgerMocks "github.com/quabynah-bilson/quantia/tests/ledger/mocks"
+	"github.com/quabynah-bilson/quantia/tests/payment/mocks"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newMoMoProvider creates a mobile money provider talking to the stand-in server.
+func newMoMoProvider(server *mocks.MockMoMoServer, callbackURL string) *provider.MoMoProvider {
+	credentials := provider.MoMoCredentials{SubscriptionKey: "key", APIUser: "user", APIKey: "secret"}
+	return provider.NewMoMoProvider(provider.MoMoConfig{
+		BaseURL:           server.URL,
+		TargetEnvironment: "sandbox",
+		Currency:          "EUR",
+		CallbackURL:       callbackURL,
+		Collection:        credentials,
+		Disbursement:      credentials,
+	})
+}
+
+// TestMoMo_CollectionCallbacks tests request-to-pay collections completed by the provider's callback.
+func TestMoMo_CollectionCallbacks(t *testing.T) {
+	testCases := []struct {
+		name           string
+		payer          string
+		expectedStatus payment.TransactionStatus
+	}{
+		{
+			name:           "payer approves the prompt",
+			payer:          "233240000001",
+			expectedStatus: payment.TransactionStatusSuccess,
+		},
+		{
+			name:           "payer rejects the prompt",
+			payer:          mocks.RejectingMoMoNumber,
+			expectedStatus: payment.TransactionStatusFailed,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			momoServer := mocks.NewMockMoMoServer(20 * time.Millisecond)
+			defer momoServer.Close()
+
+			paymentRepo := mocks.NewMockPaymentRepository()
+			var paymentUseCase *pkg.PaymentUseCase
+			var momo *provider.MoMoProvider
+			completed := make(chan *payment.Transaction, 1)
+			callbackServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				result, err := momo.ParseCallback(body)
+				if err != nil {
+					t.Errorf("unexpected callback error: %v", err)
+					return
+				}
+				transaction, err := paymentUseCase.HandleProviderResult(result)
+				if err != nil && transaction == nil {
+					t.Errorf("unexpected callback error: %v", err)
+				}
+				completed <- transaction
+			}))
+			defer callbackServer.Close()
+
+			momo = newMoMoProvider(momoServer, callbackServer.URL)
+			paymentUseCase = pkg.NewPaymentUseCase(paymentRepo, ledgerMocks.NewMockLedgerRepository(), &mocks.MockURLGuard{}, momo)
+
+			// Act
+			pending, err := paymentUseCase.MakePayment(100, "https://quantia-webhooks.com", tc.payer, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// Assert
+			if pending.Status != payment.TransactionStatusPending {
+				t.Errorf("expected status: %s, got: %s", payment.TransactionStatusPending, pending.Status)
+			}
+
+			select {
+			case transaction := <-completed:
+				if transaction.ID != pending.ID || transaction.Status != tc.expectedStatus {
+					t.Errorf("expected transaction %s to be %s, got: %s %s", pending.ID, tc.expectedStatus, transaction.ID, transaction.Status)
+				}
+			case <-time.After(time.Second):
+				t.Fatalf("expected a callback from the provider")
+			}
+		})
+	}
+}
+
+// TestMoMo_StatusPolling tests that a pending collection is completed by polling when no callback arrives.
+func TestMoMo_StatusPolling(t *testing.T) {
+	// Arrange
+	momoServer := mocks.NewMockMoMoServer(10 * time.Millisecond)
+	defer momoServer.Close()
+
+	paymentRepo := mocks.NewMockPaymentRepository()
+	paymentUseCase := pkg.NewPaymentUseCase(paymentRepo, ledgerMocks.NewMockLedgerRepository(), &mocks.MockURLGuard{}, newMoMoProvider(momoServer, ""))
+
+	pending, err := paymentUseCase.MakePayment(25.5, "https://quantia-webhooks.com", "233240000001", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Act
+	time.Sleep(50 * time.Millisecond)
+	transaction, err := paymentUseCase.GetPayment(pending.ID)
+
+	// Assert
+	if err != nil || transaction.Status != payment.TransactionStatusSuccess {
+		t.Errorf("expected status: %s, got: %v %v", payment.TransactionStatusSuccess, transaction, err)
+	}
+}
+
+// TestMoMo_DisbursementAndRefund tests disbursements and refunds of approved collections.
+func TestMoMo_DisbursementAndRefund(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	momoServer := mocks.NewMockMoMoServer(10 * time.Millisecond)
+	defer momoServer.Close()
+	momo := newMoMoProvider(momoServer, "")
+
+	// Act
+	disbursement, err := momo.Disburse(ctx, &payment.DisbursementRequest{Reference: "payout_1", Amount: 40, Destination: "233240000002"})
+	if err != nil || disbursement.Status != payment.ProviderStatusPending {
+		t.Fatalf("expected a pending disbursement, got: %v %v", disbursement, err)
+	}
+
+	collection, err := momo.Authorize(ctx, &payment.AuthorizeRequest{Reference: "tx_1", Amount: 100, Source: "233240000001"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// refunds are only possible once the payer has approved the collection
+	if _, err := momo.Refund(ctx, &payment.RefundRequest{Reference: "rfd_1", ProviderReference: collection.ProviderReference, Amount: 30}); err != payment.ErrInvalidProviderOperation {
+		t.Errorf("expected error: %v, got: %v", payment.ErrInvalidProviderOperation, err)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	// Assert
+	status, err := momo.DisbursementStatus(ctx, disbursement.ProviderReference)
+	if err != nil || status.Status != payment.ProviderStatusDisbursed {
+		t.Errorf("expected status: %s, got: %v %v", payment.ProviderStatusDisbursed, status, err)
+	}
+
+	if _, err := momo.Refund(ctx, &payment.RefundRequest{Reference: "rfd_1", ProviderReference: collection.ProviderReference, Amount: 150}); err != payment.ErrInvalidProviderOperation {
+		t.Errorf("expected error: %v, got: %v", payment.ErrInvalidProviderOperation, err)
+	}
+
+	refund, err := momo.Refund(ctx, &payment.RefundRequest{Reference: "rfd_1", ProviderReference: collection.ProviderReference, Amount: 30})
+	if err != nil || refund.Reference != "rfd_1" || refund.RefundedAmount != 30 {
+		t.Errorf("expected a refund of 30 for rfd_1, got: %v %v", refund, err)
+	}
+}
+
+// TestMoMo_AccountHolderName tests the lookup of the names registered for wallet numbers.
+func TestMoMo_AccountHolderName(t *testing.T) {
+	// Arrange
+	momoServer := mocks.NewMockMoMoServer(10 * time.Millisecond)
+	defer momoServer.Close()
+	momoServer.AccountHolders["233240000003"] = "Ama Owusu"
+	momo := newMoMoProvider(momoServer, "")
+
+	// Act
+	name, err := momo.AccountHolderName(context.Background(), "233240000003")
+	_, unknownErr := momo.AccountHolderName(context.Background(), "233240000004")
+	_, injectedErr := momo.AccountHolderName(context.Background(), "233240000003?x=")
+
+	// Assert
+	if err != nil || name != "Ama Owusu" {
+		t.Errorf("expected name: Ama Owusu, got: %q %v", name, err)
+	}
+
+	if !errors.Is(unknownErr, payment.ErrAccountHolderNotFound) {
+		t.Errorf("expected error: %v, got: %v", payment.ErrAccountHolderNotFound, unknownErr)
+	}
+
+	// the number is escaped, so it cannot rewrite the upstream request
+	if !errors.Is(injectedErr, payment.ErrAccountHolderNotFound) {
+		t.Errorf("expected error: %v, got: %v", payment.ErrAccountHolderNotFound, injectedErr)
+	}
+}
@@ -1,12 +1,20 @@
 package unit
 
 import (
+	"context"
 	"errors"
+	"github.com/quabynah-bilson/quantia/adapters/payment/provider"
 	"github.com/quabynah-bilson/quantia/pkg"
+	"github.com/quabynah-bilson/quantia/pkg/event"
+	"github.com/quabynah-bilson/quantia/pkg/ledger"
 	"github.com/quabynah-bilson/quantia/pkg/payment"
+	ledgerMocks "github.com/quabynah-bilson/quantia/tests/ledger/mocks"
 	"github.com/quabynah-bilson/quantia/tests/payment/mocks"
 	"log"
+	"reflect"
+	"sync"
 	"testing"
+	"time"
 )
 
 // testCase is a struct that represents a test case.
@@ -28,6 +36,20 @@ func TestPaymentUseCase_Pay(t *testing.T) {
 			expectedTransactionID: "",
 			expectedErr:           pkg.ErrInvalidAmount,
 		},
+		{
+			name:                  "invalid URL",
+			amount:                100,
+			url:                   "quantia.com",
+			expectedTransactionID: "",
+			expectedErr:           pkg.ErrInvalidURL,
+		},
+		{
+			name:                  "internal URL",
+			amount:                100,
+			url:                   mocks.InternalWebhookURL,
+			expectedTransactionID: "",
+			expectedErr:           payment.ErrUnsafeURL,
+		},
 		{
 			name:                  "valid payment",
 			amount:                100,
@@ -40,20 +62,20 @@ func TestPaymentUseCase_Pay(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			// Arrange
-			paymentRepo := &mocks.MockPaymentRepository{
-				PayFn: func(amount float32, url string) (*payment.Transaction, error) {
-					return &payment.Transaction{
-						ID:     "123e4567-e89b-12d3-a456-426614174000",
-						Amount: amount,
-						Status: payment.TransactionStatusSuccess,
-					}, nil
-				},
+			paymentRepo := mocks.NewMockPaymentRepository()
+			paymentRepo.PayFn = func(amount float32, url string) (*payment.Transaction, error) {
+				return &payment.Transaction{
+					ID:     "123e4567-e89b-12d3-a456-426614174000",
+					Amount: amount,
+					Status: payment.TransactionStatusPending,
+					Url:    url,
+				}, nil
 			}
 
-			paymentUseCase := pkg.NewPaymentUseCase(paymentRepo)
+			paymentUseCase := pkg.NewPaymentUseCase(paymentRepo, ledgerMocks.NewMockLedgerRepository(), &mocks.MockURLGuard{}, provider.NewSimulator(provider.SimulatorConfig{}))
 
 			// Act
-			transaction, err := paymentUseCase.MakePayment(tc.amount, tc.url)
+			transaction, err := paymentUseCase.MakePayment(tc.amount, tc.url, "", nil)
 
 			// Assert
 			if !errors.Is(err, tc.expectedErr) {
@@ -67,6 +89,219 @@ func TestPaymentUseCase_Pay(t *testing.T) {
 	}
 }
 
+// TestPaymentUseCase_ProviderOutcomes tests how the payment use case reacts to each provider behaviour.
+func TestPaymentUseCase_ProviderOutcomes(t *testing.T) {
+	testCases := []struct {
+		name           string
+		config         provider.SimulatorConfig
+		expectedStatus payment.TransactionStatus
+		expectedEvents []event.Type
+		expectedErr    error
+	}{
+		{
+			name:           "provider captures the payment",
+			config:         provider.SimulatorConfig{Behaviour: provider.BehaviourSucceed},
+			expectedStatus: payment.TransactionStatusSuccess,
+			expectedEvents: []event.Type{event.TypePaymentSucceeded},
+		},
+		{
+			name:           "provider declines the payment",
+			config:         provider.SimulatorConfig{Behaviour: provider.BehaviourDecline},
+			expectedStatus: payment.TransactionStatusFailed,
+			expectedEvents: []event.Type{event.TypePaymentFailed},
+			expectedErr:    payment.ErrPaymentDeclined,
+		},
+		{
+			name:           "provider times out",
+			config:         provider.SimulatorConfig{Behaviour: provider.BehaviourTimeout, Timeout: 10 * time.Millisecond},
+			expectedStatus: payment.TransactionStatusPending,
+		},
+		{
+			name:           "provider answers asynchronously",
+			config:         provider.SimulatorConfig{Behaviour: provider.BehaviourAsync, AsyncDelay: time.Hour},
+			expectedStatus: payment.TransactionStatusPending,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			paymentRepo := mocks.NewMockPaymentRepository()
+			paymentUseCase := pkg.NewPaymentUseCase(paymentRepo, ledgerMocks.NewMockLedgerRepository(), &mocks.MockURLGuard{}, provider.NewSimulator(tc.config))
+
+			// Act
+			transaction, err := paymentUseCase.MakePayment(100, "https://quantia-webhooks.com", "", nil)
+
+			// Assert
+			if !errors.Is(err, tc.expectedErr) {
+				t.Errorf("expected error: %v, got: %v", tc.expectedErr, err)
+			}
+
+			stored, _ := paymentRepo.Find(transaction.ID)
+			if stored.Status != tc.expectedStatus {
+				t.Errorf("expected status: %s, got: %s", tc.expectedStatus, stored.Status)
+			}
+
+			if !reflect.DeepEqual(paymentRepo.EventTypes(), tc.expectedEvents) {
+				t.Errorf("expected events: %v, got: %v", tc.expectedEvents, paymentRepo.EventTypes())
+			}
+		})
+	}
+}
+
+// TestPaymentUseCase_HandleProviderResult tests the completion of payments the provider answers asynchronously.
+func TestPaymentUseCase_HandleProviderResult(t *testing.T) {
+	// Arrange
+	paymentRepo := mocks.NewMockPaymentRepository()
+	simulator := provider.NewSimulator(provider.SimulatorConfig{Behaviour: provider.BehaviourAsync, AsyncDelay: 50 * time.Millisecond})
+	paymentUseCase := pkg.NewPaymentUseCase(paymentRepo, ledgerMocks.NewMockLedgerRepository(), &mocks.MockURLGuard{}, simulator)
+
+	results := make(chan *payment.Transaction, 1)
+	simulator.OnResult(func(result *payment.ProviderResult) {
+		transaction, _ := paymentUseCase.HandleProviderResult(result)
+		results <- transaction
+	})
+
+	// Act
+	pending, err := paymentUseCase.MakePayment(100, "https://quantia-webhooks.com", "", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	completed := <-results
+
+	// Assert
+	if pending.Status != payment.TransactionStatusPending {
+		t.Errorf("expected status: %s, got: %s", payment.TransactionStatusPending, pending.Status)
+	}
+	if completed.ID != pending.ID || completed.Status != payment.TransactionStatusSuccess {
+		t.Errorf("expected transaction %s to succeed, got: %s %s", pending.ID, completed.ID, completed.Status)
+	}
+}
+
+// TestPaymentUseCase_HandleProviderResult_CapturedAmount tests that a payment only succeeds when the provider
+// captured its whole amount.
+func TestPaymentUseCase_HandleProviderResult_CapturedAmount(t *testing.T) {
+	testCases := []struct {
+		name             string
+		capturedAmount   float32
+		expectedErr      error
+		expectedStatus   payment.TransactionStatus
+		expectedEvent    event.Type
+		expectedMerchant float32
+	}{
+		{name: "whole amount", capturedAmount: 25, expectedStatus: payment.TransactionStatusSuccess, expectedEvent: event.TypePaymentSucceeded, expectedMerchant: 25},
+		{name: "partial capture", capturedAmount: 10, expectedErr: payment.ErrPaymentDeclined, expectedStatus: payment.TransactionStatusFailed, expectedEvent: event.TypePaymentFailed},
+		{name: "overpayment", capturedAmount: 30, expectedErr: payment.ErrPaymentDeclined, expectedStatus: payment.TransactionStatusFailed, expectedEvent: event.TypePaymentFailed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			paymentRepo, ledgerRepo := mocks.NewMockPaymentRepository(), ledgerMocks.NewMockLedgerRepository()
+			paymentUseCase := pkg.NewPaymentUseCase(paymentRepo, ledgerRepo, &mocks.MockURLGuard{}, provider.NewSimulator(provider.SimulatorConfig{}))
+			_ = paymentRepo.Save(&payment.Transaction{ID: "tx_1", Amount: 25, Status: payment.TransactionStatusPending, Url: "https://shop.example.com/hook"})
+
+			// Act
+			transaction, err := paymentUseCase.HandleProviderResult(&payment.ProviderResult{Reference: "tx_1", ProviderReference: "ref_1", Status: payment.ProviderStatusCaptured, CapturedAmount: tc.capturedAmount})
+
+			// Assert
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error: %v, got: %v", tc.expectedErr, err)
+			}
+
+			if transaction.Status != tc.expectedStatus {
+				t.Errorf("expected status: %s, got: %s", tc.expectedStatus, transaction.Status)
+			}
+
+			if types := paymentRepo.EventTypes(); !reflect.DeepEqual(types, []event.Type{tc.expectedEvent}) {
+				t.Errorf("expected events: %v, got: %v", []event.Type{tc.expectedEvent}, types)
+			}
+
+			if balance, _ := ledgerRepo.Balance(ledger.MerchantAccountID(transaction.Url)); balance.Current != tc.expectedMerchant {
+				t.Errorf("expected merchant balance: %v, got: %v", tc.expectedMerchant, balance.Current)
+			}
+		})
+	}
+}
+
+// TestPaymentUseCase_HandleProviderResult_PostingFailure tests that a payment whose ledger posting fails stays
+// pending, and succeeds once the result is applied again.
+func TestPaymentUseCase_HandleProviderResult_PostingFailure(t *testing.T) {
+	// Arrange
+	paymentRepo, ledgerRepo := mocks.NewMockPaymentRepository(), ledgerMocks.NewMockLedgerRepository()
+	paymentUseCase := pkg.NewPaymentUseCase(paymentRepo, ledgerRepo, &mocks.MockURLGuard{}, provider.NewSimulator(provider.SimulatorConfig{}))
+	_ = paymentRepo.Save(&payment.Transaction{ID: "tx_1", Amount: 25, Status: payment.TransactionStatusPending, Url: "https://shop.example.com/hook"})
+	result := &payment.ProviderResult{Reference: "tx_1", ProviderReference: "ref_1", Status: payment.ProviderStatusCaptured, CapturedAmount: 25}
+
+	// Act
+	ledgerRepo.PostErr = ledger.ErrFailedToPostEntries
+	_, failedErr := paymentUseCase.HandleProviderResult(result)
+	pending, _ := paymentRepo.Find("tx_1")
+
+	ledgerRepo.PostErr = nil
+	completed, err := paymentUseCase.HandleProviderResult(result)
+
+	// Assert
+	if !errors.Is(failedErr, ledger.ErrFailedToPostEntries) {
+		t.Errorf("expected error: %v, got: %v", ledger.ErrFailedToPostEntries, failedErr)
+	}
+
+	if pending.Status != payment.TransactionStatusPending {
+		t.Errorf("expected status: %s, got: %s", payment.TransactionStatusPending, pending.Status)
+	}
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if completed.Status != payment.TransactionStatusSuccess {
+		t.Errorf("expected status: %s, got: %s", payment.TransactionStatusSuccess, completed.Status)
+	}
+
+	if types := paymentRepo.EventTypes(); !reflect.DeepEqual(types, []event.Type{event.TypePaymentSucceeded}) {
+		t.Errorf("expected a single %s event, got: %v", event.TypePaymentSucceeded, types)
+	}
+
+	if balance, _ := ledgerRepo.Balance(ledger.MerchantAccountID(completed.Url)); balance.Current != 25 {
+		t.Errorf("expected merchant balance: 25, got: %v", balance.Current)
+	}
+}
+
+// TestPaymentUseCase_HandleProviderResult_Concurrent tests that a result delivered many times at once (e.g. a
+// callback and a poll) completes the payment once.
+func TestPaymentUseCase_HandleProviderResult_Concurrent(t *testing.T) {
+	// Arrange
+	paymentRepo := mocks.NewMockPaymentRepository()
+	paymentUseCase := pkg.NewPaymentUseCase(paymentRepo, ledgerMocks.NewMockLedgerRepository(), &mocks.MockURLGuard{}, provider.NewSimulator(provider.SimulatorConfig{}))
+	_ = paymentRepo.Save(&payment.Transaction{ID: "tx_1", Amount: 25, Status: payment.TransactionStatusPending, Url: "https://shop.example.com/hook"})
+
+	completions := 0
+	paymentUseCase.OnCompleted(func(*payment.Transaction) { completions++ })
+
+	// Act
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = paymentUseCase.HandleProviderResult(&payment.ProviderResult{Reference: "tx_1", ProviderReference: "ref_1", Status: payment.ProviderStatusCaptured, CapturedAmount: 25})
+		}()
+	}
+	wg.Wait()
+
+	// Assert
+	if types := paymentRepo.EventTypes(); !reflect.DeepEqual(types, []event.Type{event.TypePaymentSucceeded}) {
+		t.Errorf("expected a single %s event, got: %v", event.TypePaymentSucceeded, types)
+	}
+
+	if completions != 1 {
+		t.Errorf("expected 1 completion, got: %d", completions)
+	}
+
+	if transaction, _ := paymentRepo.Find("tx_1"); transaction.Status != payment.TransactionStatusSuccess {
+		t.Errorf("expected status: %s, got: %s", payment.TransactionStatusSuccess, transaction.Status)
+	}
+}
+
 // TestPaymentUseCase_SubscribeToWebhook tests the subscribe to webhook method of the payment use case.
 func TestPaymentUseCase_SubscribeToWebhook(t *testing.T) {
 	testCases := []testCase{
@@ -86,16 +321,16 @@ func TestPaymentUseCase_SubscribeToWebhook(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			// Arrange
 			paymentRepo := &mocks.MockPaymentRepository{
-				SubscribeFn: func(url string, queue chan *payment.WebhookPayload) error {
+				SubscribeFn: func(ctx context.Context, url string, queue chan *payment.WebhookPayload) error {
 					return nil
 				},
 			}
 
-			paymentUseCase := pkg.NewPaymentUseCase(paymentRepo)
+			paymentUseCase := pkg.NewPaymentUseCase(paymentRepo, ledgerMocks.NewMockLedgerRepository(), &mocks.MockURLGuard{}, provider.NewSimulator(provider.SimulatorConfig{}))
 
 			// Act
 			queueChan := make(chan *payment.WebhookPayload, 10)
-			err := paymentUseCase.Subscribe(tc.url, queueChan)
+			err := paymentUseCase.Subscribe(context.Background(), tc.url, queueChan)
 
 			// Listen to the queue channel
 			go func() {
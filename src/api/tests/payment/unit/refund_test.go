@@ -0,0 +1,209 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"github.com/quabynah-bilson/quantia/adapters/payment/provider"
+	"github.com/quabynah-bilson/quantia/pkg"
+	"github.com/quabynah-bilson/quantia/pkg/event"
+	"github.com/quabynah-bilson/quantia/pkg/ledger"
+	"github.com/quabynah-bilson/quantia/pkg/payment"
+	ledgerMocks "github.com/quabynah-bilson/quantia/tests/ledger/mocks"
+	"github.com/quabynah-bilson/quantia/tests/payment/mocks"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// pendingRefundProvider is a simulator whose refunds are confirmed later, like a mobile money disbursement.
+type pendingRefundProvider struct {
+	*provider.Simulator
+}
+
+// Refund accepts the refund without completing it
+func (p *pendingRefundProvider) Refund(_ context.Context, req *payment.RefundRequest) (*payment.ProviderResult, error) {
+	return &payment.ProviderResult{Reference: req.Reference, ProviderReference: "sim_refund", Status: payment.ProviderStatusPending}, nil
+}
+
+// newPaidTransaction makes a successful payment of 100 through the use case.
+func newPaidTransaction(t *testing.T, paymentUseCase *pkg.PaymentUseCase) *payment.Transaction {
+	transaction, err := paymentUseCase.MakePayment(100, "https://quantia-webhooks.com/hooks", "", nil)
+	if err != nil || transaction.Status != payment.TransactionStatusSuccess {
+		t.Fatalf("expected a successful payment, got: %v %v", transaction, err)
+	}
+
+	return transaction
+}
+
+// TestPaymentUseCase_RefundPayment tests full and partial refunds of a payment.
+func TestPaymentUseCase_RefundPayment(t *testing.T) {
+	testCases := []struct {
+		name           string
+		amounts        []float32
+		expectedErr    error
+		expectedStatus payment.TransactionStatus
+		expectedAmount float32
+	}{
+		{
+			name:           "full refund",
+			amounts:        []float32{100},
+			expectedStatus: payment.TransactionStatusRefunded,
+			expectedAmount: 100,
+		},
+		{
+			name:           "partial refunds",
+			amounts:        []float32{30, 20},
+			expectedStatus: payment.TransactionStatusPartiallyRefunded,
+			expectedAmount: 50,
+		},
+		{
+			name:           "refunds exceeding the payment",
+			amounts:        []float32{60, 50},
+			expectedErr:    payment.ErrRefundExceedsAmount,
+			expectedStatus: payment.TransactionStatusPartiallyRefunded,
+			expectedAmount: 60,
+		},
+		{
+			name:           "refund after a full refund",
+			amounts:        []float32{100, 1},
+			expectedErr:    pkg.ErrPaymentNotRefundable,
+			expectedStatus: payment.TransactionStatusRefunded,
+			expectedAmount: 100,
+		},
+		{
+			name:           "invalid amount",
+			amounts:        []float32{0},
+			expectedErr:    pkg.ErrInvalidAmount,
+			expectedStatus: payment.TransactionStatusSuccess,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			paymentRepo := mocks.NewMockPaymentRepository()
+			ledgerRepo := ledgerMocks.NewMockLedgerRepository()
+			paymentUseCase := pkg.NewPaymentUseCase(paymentRepo, ledgerRepo, &mocks.MockURLGuard{}, provider.NewSimulator(provider.SimulatorConfig{}))
+			transaction := newPaidTransaction(t, paymentUseCase)
+
+			// Act
+			var err error
+			for _, amount := range tc.amounts {
+				if _, err = paymentUseCase.RefundPayment(transaction.ID, amount, "customer request"); err != nil {
+					break
+				}
+			}
+
+			// Assert
+			if !errors.Is(err, tc.expectedErr) {
+				t.Errorf("expected error: %v, got: %v", tc.expectedErr, err)
+			}
+
+			stored, _ := paymentRepo.Find(transaction.ID)
+			if stored.Status != tc.expectedStatus || stored.RefundedAmount != tc.expectedAmount {
+				t.Errorf("expected %s with %.2f refunded, got: %s with %.2f", tc.expectedStatus, tc.expectedAmount, stored.Status, stored.RefundedAmount)
+			}
+
+			// the reversals bring the merchant's balance down by the refunded amount
+			balance, _ := ledgerRepo.Balance(ledger.MerchantAccountID(transaction.Url))
+			if balance.Current != transaction.Amount-tc.expectedAmount {
+				t.Errorf("expected merchant balance: %.2f, got: %.2f", transaction.Amount-tc.expectedAmount, balance.Current)
+			}
+		})
+	}
+}
+
+// TestPaymentUseCase_RefundPaymentConcurrently tests that concurrent refunds never exceed the payment.
+func TestPaymentUseCase_RefundPaymentConcurrently(t *testing.T) {
+	// Arrange
+	paymentRepo := mocks.NewMockPaymentRepository()
+	paymentUseCase := pkg.NewPaymentUseCase(paymentRepo, ledgerMocks.NewMockLedgerRepository(), &mocks.MockURLGuard{}, provider.NewSimulator(provider.SimulatorConfig{}))
+	transaction := newPaidTransaction(t, paymentUseCase)
+
+	// Act
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		succeeded int
+	)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := paymentUseCase.RefundPayment(transaction.ID, 30, ""); err == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Assert
+	stored, _ := paymentRepo.Find(transaction.ID)
+	if succeeded != 3 || stored.RefundedAmount != 90 {
+		t.Errorf("expected 3 refunds totalling 90, got: %d totalling %.2f", succeeded, stored.RefundedAmount)
+	}
+}
+
+// TestPaymentUseCase_RefundPaymentAsync tests refunds that the provider confirms later.
+func TestPaymentUseCase_RefundPaymentAsync(t *testing.T) {
+	testCases := []struct {
+		name           string
+		result         payment.ProviderStatus
+		expectedStatus payment.RefundStatus
+		expectedEvents []event.Type
+	}{
+		{
+			name:           "confirmed",
+			result:         payment.ProviderStatusDisbursed,
+			expectedStatus: payment.RefundStatusSucceeded,
+			expectedEvents: []event.Type{event.TypePaymentSucceeded, event.TypeRefundCreated, event.TypeRefundSucceeded},
+		},
+		{
+			name:           "declined",
+			result:         payment.ProviderStatusDeclined,
+			expectedStatus: payment.RefundStatusFailed,
+			expectedEvents: []event.Type{event.TypePaymentSucceeded, event.TypeRefundCreated, event.TypeRefundFailed},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			paymentRepo := mocks.NewMockPaymentRepository()
+			paymentProvider := &pendingRefundProvider{provider.NewSimulator(provider.SimulatorConfig{})}
+			paymentUseCase := pkg.NewPaymentUseCase(paymentRepo, ledgerMocks.NewMockLedgerRepository(), &mocks.MockURLGuard{}, paymentProvider)
+			transaction := newPaidTransaction(t, paymentUseCase)
+
+			refund, err := paymentUseCase.RefundPayment(transaction.ID, 100, "")
+			if err != nil || refund.Status != payment.RefundStatusPending {
+				t.Fatalf("expected a pending refund, got: %v %v", refund, err)
+			}
+
+			// Act
+			_, err = paymentUseCase.HandleProviderResult(&payment.ProviderResult{Reference: refund.ID, Status: tc.result})
+
+			// Assert
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			refunds, _ := paymentUseCase.GetRefunds(transaction.ID)
+			if len(refunds) != 1 || refunds[0].Status != tc.expectedStatus {
+				t.Errorf("expected one %s refund, got: %v", tc.expectedStatus, refunds)
+			}
+
+			if events := paymentRepo.EventTypes(); !reflect.DeepEqual(events, tc.expectedEvents) {
+				t.Errorf("expected events: %v, got: %v", tc.expectedEvents, events)
+			}
+
+			// a declined refund gives its amount back
+			if tc.expectedStatus == payment.RefundStatusFailed {
+				if _, err := paymentUseCase.RefundPayment(transaction.ID, 100, ""); err != nil {
+					t.Errorf("expected the amount to be refundable again, got: %v", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package unit
+
+import (
+	"errors"
+	"github.com/quabynah-bilson/quantia/internal/resilience"
+	pkg "github.com/quabynah-bilson/quantia/pkg/payment"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// TestRetryPolicy_NextDelay tests the retry decisions of the retry policy.
+func TestRetryPolicy_NextDelay(t *testing.T) {
+	// always pick the upper bound of the jitter window
+	policy := resilience.RetryPolicy{
+		InitialBackoff: time.Second,
+		MaxBackoff:     8 * time.Second,
+		MaxAttempts:    5,
+		MaxRetryAfter:  time.Minute,
+	}.WithRandom(func() float64 { return 0.999999999 })
+
+	testCases := []struct {
+		name          string
+		attempts      int
+		err           error
+		expectedDelay time.Duration
+		expectedRetry bool
+	}{
+		{
+			name:          "network error is retried",
+			attempts:      1,
+			err:           pkg.ErrWebhookDeliveryFailed,
+			expectedDelay: time.Second,
+			expectedRetry: true,
+		},
+		{
+			name:          "backoff grows exponentially",
+			attempts:      3,
+			err:           &pkg.DeliveryError{Status: http.StatusServiceUnavailable},
+			expectedDelay: 4 * time.Second,
+			expectedRetry: true,
+		},
+		{
+			name:          "backoff is capped",
+			attempts:      4,
+			err:           &pkg.DeliveryError{Status: http.StatusBadGateway},
+			expectedDelay: 8 * time.Second,
+			expectedRetry: true,
+		},
+		{
+			name:          "retry-after header is respected",
+			attempts:      1,
+			err:           &pkg.DeliveryError{Status: http.StatusTooManyRequests, Delay: 30 * time.Second},
+			expectedDelay: 30 * time.Second,
+			expectedRetry: true,
+		},
+		{
+			name:          "retry-after beyond the limit stops retrying",
+			attempts:      1,
+			err:           &pkg.DeliveryError{Status: http.StatusTooManyRequests, Delay: time.Hour},
+			expectedRetry: false,
+		},
+		{
+			name:          "client error is not retried",
+			attempts:      1,
+			err:           &pkg.DeliveryError{Status: http.StatusBadRequest},
+			expectedRetry: false,
+		},
+		{
+			name:          "max attempts reached",
+			attempts:      5,
+			err:           pkg.ErrWebhookDeliveryFailed,
+			expectedRetry: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			delay, retry := policy.NextDelay(tc.attempts, tc.err)
+			if retry != tc.expectedRetry {
+				t.Fatalf("expected retry: %v, got: %v", tc.expectedRetry, retry)
+			}
+
+			if retry && delay.Round(time.Millisecond) != tc.expectedDelay {
+				t.Errorf("expected delay: %s, got: %s", tc.expectedDelay, delay)
+			}
+		})
+	}
+}
+
+// TestRetryPolicy_FullJitter tests that backoff delays are spread over the whole jitter window.
+func TestRetryPolicy_FullJitter(t *testing.T) {
+	policy := resilience.RetryPolicy{InitialBackoff: time.Second, MaxBackoff: time.Minute, MaxAttempts: 5}.
+		WithRandom(func() float64 { return 0 })
+
+	if delay := policy.Backoff(3); delay != 0 {
+		t.Errorf("expected delay: 0s, got: %s", delay)
+	}
+}
+
+// TestCircuitBreaker tests the state transitions of the circuit breaker.
+func TestCircuitBreaker(t *testing.T) {
+	breaker := resilience.NewCircuitBreaker(2, 20*time.Millisecond)
+
+	// two consecutive failures open the circuit
+	breaker.Failure()
+	if _, ok := breaker.Allow(); !ok {
+		t.Fatalf("expected the circuit to allow calls after a single failure")
+	}
+	breaker.Failure()
+	if _, ok := breaker.Allow(); ok {
+		t.Fatalf("expected the circuit to be open, got: %s", breaker.State())
+	}
+
+	// after the cooldown a single probe is allowed
+	time.Sleep(25 * time.Millisecond)
+	if _, ok := breaker.Allow(); !ok {
+		t.Fatalf("expected a probe to be allowed after the cooldown")
+	}
+	if _, ok := breaker.Allow(); ok {
+		t.Fatalf("expected only one probe while half-open")
+	}
+
+	// a failed probe opens the circuit again, a successful one closes it
+	breaker.Failure()
+	if breaker.State() != resilience.CircuitOpen {
+		t.Fatalf("expected state: %s, got: %s", resilience.CircuitOpen, breaker.State())
+	}
+	time.Sleep(25 * time.Millisecond)
+	breaker.Allow()
+	breaker.Success()
+	if breaker.State() != resilience.CircuitClosed {
+		t.Errorf("expected state: %s, got: %s", resilience.CircuitClosed, breaker.State())
+	}
+}
+
+// TestIsRetryable tests the classification of delivery errors.
+func TestIsRetryable(t *testing.T) {
+	if resilience.IsRetryable(errors.New("connection refused")) != true {
+		t.Errorf("expected errors without a status to be retryable")
+	}
+	if resilience.IsRetryable(&pkg.DeliveryError{Status: http.StatusNotFound}) != false {
+		t.Errorf("expected 404 to be permanent")
+	}
+}
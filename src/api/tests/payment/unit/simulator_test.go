@@ -0,0 +1,85 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"github.com/quabynah-bilson/quantia/adapters/payment/provider"
+	"github.com/quabynah-bilson/quantia/pkg/payment"
+	"testing"
+)
+
+// TestSimulator_Lifecycle tests the state transitions allowed by the payment provider simulator.
+func TestSimulator_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	simulator := provider.NewSimulator(provider.SimulatorConfig{
+		Sources: map[string]provider.Behaviour{"card_declined": provider.BehaviourDecline},
+	})
+
+	// a declining source overrides the default behaviour
+	if _, err := simulator.Authorize(ctx, &payment.AuthorizeRequest{Reference: "tx_0", Amount: 10, Source: "card_declined"}); !errors.Is(err, payment.ErrPaymentDeclined) {
+		t.Fatalf("expected error: %v, got: %v", payment.ErrPaymentDeclined, err)
+	}
+
+	authorized, err := simulator.Authorize(ctx, &payment.AuthorizeRequest{Reference: "tx_1", Amount: 100})
+	if err != nil || authorized.Status != payment.ProviderStatusAuthorized {
+		t.Fatalf("expected an authorized payment, got: %v %v", authorized, err)
+	}
+	ref := authorized.ProviderReference
+
+	steps := []struct {
+		name           string
+		call           func() (*payment.ProviderResult, error)
+		expectedStatus payment.ProviderStatus
+		expectedErr    error
+	}{
+		{
+			name:        "capture more than authorized",
+			call:        func() (*payment.ProviderResult, error) { return simulator.Capture(ctx, ref, 150) },
+			expectedErr: payment.ErrInvalidProviderOperation,
+		},
+		{
+			name:           "partial capture",
+			call:           func() (*payment.ProviderResult, error) { return simulator.Capture(ctx, ref, 80) },
+			expectedStatus: payment.ProviderStatusCaptured,
+		},
+		{
+			name:        "void after capture",
+			call:        func() (*payment.ProviderResult, error) { return simulator.Void(ctx, ref) },
+			expectedErr: payment.ErrInvalidProviderOperation,
+		},
+		{
+			name: "partial refund",
+			call: func() (*payment.ProviderResult, error) {
+				return simulator.Refund(ctx, &payment.RefundRequest{Reference: "rfd_1", ProviderReference: ref, Amount: 50})
+			},
+			expectedStatus: payment.ProviderStatusRefunded,
+		},
+		{
+			name: "refund more than captured",
+			call: func() (*payment.ProviderResult, error) {
+				return simulator.Refund(ctx, &payment.RefundRequest{Reference: "rfd_2", ProviderReference: ref, Amount: 40})
+			},
+			expectedErr: payment.ErrInvalidProviderOperation,
+		},
+		{
+			name:           "status",
+			call:           func() (*payment.ProviderResult, error) { return simulator.Status(ctx, ref) },
+			expectedStatus: payment.ProviderStatusRefunded,
+		},
+		{
+			name:        "unknown reference",
+			call:        func() (*payment.ProviderResult, error) { return simulator.Status(ctx, "sim_unknown") },
+			expectedErr: payment.ErrProviderReferenceNotFound,
+		},
+	}
+
+	for _, step := range steps {
+		result, err := step.call()
+		if !errors.Is(err, step.expectedErr) {
+			t.Fatalf("%s: expected error: %v, got: %v", step.name, step.expectedErr, err)
+		}
+		if err == nil && result.Status != step.expectedStatus {
+			t.Fatalf("%s: expected status: %s, got: %s", step.name, step.expectedStatus, result.Status)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"github.com/quabynah-bilson/quantia/internal/netguard"
+	pkg "github.com/quabynah-bilson/quantia/pkg/payment"
+	"testing"
+)
+
+// TestGuard_CheckURL tests the SSRF checks made on merchant URLs.
+func TestGuard_CheckURL(t *testing.T) {
+	testCases := []struct {
+		name        string
+		config      netguard.Config
+		url         string
+		expectedErr error
+	}{
+		{
+			name: "public address",
+			url:  "https://8.8.8.8/webhooks",
+		},
+		{
+			name:        "cloud metadata endpoint",
+			url:         "http://169.254.169.254/latest/meta-data",
+			expectedErr: pkg.ErrUnsafeURL,
+		},
+		{
+			name:        "loopback redis",
+			url:         "http://127.0.0.1:6379",
+			expectedErr: pkg.ErrUnsafeURL,
+		},
+		{
+			name:        "ipv6 loopback",
+			url:         "http://[::1]:8080",
+			expectedErr: pkg.ErrUnsafeURL,
+		},
+		{
+			name:        "ipv4-mapped ipv6 loopback",
+			url:         "http://[::ffff:127.0.0.1]/",
+			expectedErr: pkg.ErrUnsafeURL,
+		},
+		{
+			name:        "private network",
+			url:         "https://10.0.0.12/hooks",
+			expectedErr: pkg.ErrUnsafeURL,
+		},
+		{
+			name:        "carrier-grade nat",
+			url:         "https://100.64.0.1/hooks",
+			expectedErr: pkg.ErrUnsafeURL,
+		},
+		{
+			name:        "unsupported scheme",
+			url:         "gopher://8.8.8.8/",
+			expectedErr: pkg.ErrUnsafeURL,
+		},
+		{
+			name:        "plain http in production",
+			config:      netguard.Config{RequireHTTPS: true},
+			url:         "http://8.8.8.8/webhooks",
+			expectedErr: pkg.ErrInsecureURL,
+		},
+		{
+			name:   "allowlisted development host",
+			config: netguard.Config{AllowedHosts: []string{"127.0.0.1"}},
+			url:    "http://127.0.0.1:3000/webhooks",
+		},
+		{
+			name:   "allowlisted development range",
+			config: netguard.Config{AllowedHosts: []string{"192.168.0.0/16"}},
+			url:    "http://192.168.1.20/webhooks",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			guard := netguard.NewGuard(tc.config)
+			if err := guard.CheckURL(context.Background(), tc.url); !errors.Is(err, tc.expectedErr) {
+				t.Errorf("expected error: %v, got: %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
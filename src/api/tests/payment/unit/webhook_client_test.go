@@ -0,0 +1,80 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"github.com/quabynah-bilson/quantia/internal/netguard"
+	"github.com/quabynah-bilson/quantia/internal/payment"
+	"github.com/quabynah-bilson/quantia/pkg/event"
+	pkg "github.com/quabynah-bilson/quantia/pkg/payment"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestHTTPWebhookClient_Deliver tests the deliver method of the http webhook client.
+func TestHTTPWebhookClient_Deliver(t *testing.T) {
+	testCases := []struct {
+		name         string
+		handler      http.HandlerFunc
+		allowedHosts []string
+		expectedErr  error
+	}{
+		{
+			name: "merchant accepts the webhook",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			},
+			allowedHosts: []string{"127.0.0.1"},
+		},
+		{
+			name: "loopback destination is blocked",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			},
+			expectedErr: pkg.ErrUnsafeURL,
+		},
+		{
+			name: "merchant returns a server error",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+			allowedHosts: []string{"127.0.0.1"},
+			expectedErr:  pkg.ErrWebhookDeliveryFailed,
+		},
+		{
+			name: "merchant is too slow to respond",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(200 * time.Millisecond)
+				w.WriteHeader(http.StatusOK)
+			},
+			allowedHosts: []string{"127.0.0.1"},
+			expectedErr:  pkg.ErrWebhookDeliveryFailed,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			server := httptest.NewServer(tc.handler)
+			defer server.Close()
+
+			guard := netguard.NewGuard(netguard.Config{AllowedHosts: tc.allowedHosts})
+			client := payment.NewHTTPWebhookClient(50*time.Millisecond, guard)
+
+			// Act
+			envelope, _ := event.New(event.TypePaymentCreated, &event.PaymentData{
+				TransactionID: "123e4567-e89b-12d3-a456-426614174000",
+				Amount:        100,
+				Status:        string(pkg.TransactionStatusPending),
+			})
+			err := client.Deliver(context.Background(), pkg.NewWebhookPayload(server.URL, envelope))
+
+			// Assert
+			if !errors.Is(err, tc.expectedErr) {
+				t.Errorf("expected error: %v, got: %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
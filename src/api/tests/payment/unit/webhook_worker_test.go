@@ -0,0 +1,114 @@
+package unit
+
+import (
+	"context"
+	"fmt"
+	"github.com/quabynah-bilson/quantia/internal/payment"
+	"github.com/quabynah-bilson/quantia/internal/resilience"
+	pkg "github.com/quabynah-bilson/quantia/pkg/payment"
+	"github.com/quabynah-bilson/quantia/tests/payment/mocks"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestProcessWebhooks_BoundedPool tests that no more than the configured number of deliveries run at once.
+func TestProcessWebhooks_BoundedPool(t *testing.T) {
+	// Arrange
+	const workers, payloads = 3, 12
+	var inFlight, maxInFlight, delivered int32
+	client := &mocks.MockWebhookClient{
+		DeliverFn: func(ctx context.Context, payload *pkg.WebhookPayload) error {
+			current := atomic.AddInt32(&inFlight, 1)
+			for {
+				seen := atomic.LoadInt32(&maxInFlight)
+				if current <= seen || atomic.CompareAndSwapInt32(&maxInFlight, seen, current) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			atomic.AddInt32(&inFlight, -1)
+			atomic.AddInt32(&delivered, 1)
+			return nil
+		},
+	}
+
+	queue := make(chan *pkg.WebhookPayload, payloads)
+	for i := 0; i < payloads; i++ {
+		queue <- &pkg.WebhookPayload{ID: fmt.Sprintf("transaction-%d", i)}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	// Act
+	go func() {
+		defer close(done)
+		payment.ProcessWebhooks(ctx, &mocks.MockPaymentRepository{}, client, queue, payment.WorkerConfig{
+			Workers:     workers,
+			RetryPolicy: resilience.DefaultRetryPolicy(),
+		})
+	}()
+	for atomic.LoadInt32(&delivered) < payloads {
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	// Assert
+	if maxInFlight > workers {
+		t.Errorf("expected at most %d concurrent deliveries, got: %d", workers, maxInFlight)
+	}
+}
+
+// TestProcessWebhooks_CheckpointsOnShutdown tests that undelivered webhooks are checkpointed when the worker stops.
+func TestProcessWebhooks_CheckpointsOnShutdown(t *testing.T) {
+	// Arrange
+	var mu sync.Mutex
+	checkpointed := make(map[string]bool)
+	repo := &mocks.MockPaymentRepository{
+		CheckpointFn: func(payload *pkg.WebhookPayload) error {
+			mu.Lock()
+			defer mu.Unlock()
+			checkpointed[payload.ID] = true
+			return nil
+		},
+	}
+
+	started := make(chan struct{}, 1)
+	client := &mocks.MockWebhookClient{
+		DeliverFn: func(ctx context.Context, payload *pkg.WebhookPayload) error {
+			select {
+			case started <- struct{}{}:
+			default:
+			}
+			return pkg.ErrWebhookDeliveryFailed
+		},
+	}
+
+	queue := make(chan *pkg.WebhookPayload, 3)
+	for _, id := range []string{"first", "second", "third"} {
+		queue <- &pkg.WebhookPayload{ID: id}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	// Act
+	go func() {
+		defer close(done)
+		payment.ProcessWebhooks(ctx, repo, client, queue, payment.WorkerConfig{
+			Workers:     1,
+			RetryPolicy: resilience.DefaultRetryPolicy(),
+		})
+	}()
+	<-started
+	cancel()
+	<-done
+
+	// Assert
+	if len(checkpointed) != 3 {
+		t.Errorf("expected 3 checkpointed webhooks, got: %d", len(checkpointed))
+	}
+}
@@ -0,0 +1,126 @@
+package mocks
+
+import (
+	"github.com/quabynah-bilson/quantia/pkg/payout"
+	"sort"
+	"sync"
+)
+
+// MockPayoutRepository is an in-memory payout repository
+type MockPayoutRepository struct {
+	mu         sync.Mutex
+	Batches    map[string]*payout.Batch
+	BatchItems map[string][]string
+	ItemsByID  map[string]*payout.Item
+	Claims     map[string]bool
+}
+
+// NewMockPayoutRepository creates an empty in-memory payout repository
+func NewMockPayoutRepository() *MockPayoutRepository {
+	return &MockPayoutRepository{
+		Batches:    make(map[string]*payout.Batch),
+		BatchItems: make(map[string][]string),
+		ItemsByID:  make(map[string]*payout.Item),
+		Claims:     make(map[string]bool),
+	}
+}
+
+// Save saves a copy of the batch
+func (m *MockPayoutRepository) Save(batch *payout.Batch) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	copied := *batch
+	m.Batches[batch.ID] = &copied
+	return nil
+}
+
+// Find returns a copy of the batch
+func (m *MockPayoutRepository) Find(id string) (*payout.Batch, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	batch, ok := m.Batches[id]
+	if !ok {
+		return nil, payout.ErrBatchNotFound
+	}
+	copied := *batch
+	return &copied, nil
+}
+
+// Queued returns the approved and processing batches, oldest approval first
+func (m *MockPayoutRepository) Queued(limit int) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var queued []*payout.Batch
+	for _, batch := range m.Batches {
+		if batch.Status == payout.BatchStatusApproved || batch.Status == payout.BatchStatusProcessing {
+			queued = append(queued, batch)
+		}
+	}
+	sort.Slice(queued, func(i, j int) bool { return queued[i].ApprovedAt.Before(*queued[j].ApprovedAt) })
+
+	var ids []string
+	for _, batch := range queued {
+		if len(ids) == limit {
+			break
+		}
+		ids = append(ids, batch.ID)
+	}
+	return ids, nil
+}
+
+// CreateItems saves copies of the items in order
+func (m *MockPayoutRepository) CreateItems(items []*payout.Item) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, item := range items {
+		copied := *item
+		m.ItemsByID[item.ID] = &copied
+		m.BatchItems[item.BatchID] = append(m.BatchItems[item.BatchID], item.ID)
+	}
+	return nil
+}
+
+// SaveItem saves a copy of the item
+func (m *MockPayoutRepository) SaveItem(item *payout.Item) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	copied := *item
+	m.ItemsByID[item.ID] = &copied
+	return nil
+}
+
+// FindItem returns a copy of the item
+func (m *MockPayoutRepository) FindItem(id string) (*payout.Item, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	item, ok := m.ItemsByID[id]
+	if !ok {
+		return nil, payout.ErrItemNotFound
+	}
+	copied := *item
+	return &copied, nil
+}
+
+// Items returns copies of the batch's items in order
+func (m *MockPayoutRepository) Items(batchID string) ([]*payout.Item, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	items := make([]*payout.Item, 0, len(m.BatchItems[batchID]))
+	for _, id := range m.BatchItems[batchID] {
+		copied := *m.ItemsByID[id]
+		items = append(items, &copied)
+	}
+	return items, nil
+}
+
+// Claim claims an item once
+func (m *MockPayoutRepository) Claim(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Claims[id] {
+		return payout.ErrItemAlreadyClaimed
+	}
+	m.Claims[id] = true
+	return nil
+}
@@ -0,0 +1,260 @@
+package unit
+
+import (
+	"errors"
+	"github.com/quabynah-bilson/quantia/adapters/payment/provider"
+	"github.com/quabynah-bilson/quantia/pkg"
+	"github.com/quabynah-bilson/quantia/pkg/beneficiary"
+	"github.com/quabynah-bilson/quantia/pkg/ledger"
+	"github.com/quabynah-bilson/quantia/pkg/payout"
+	ledgerMocks "github.com/quabynah-bilson/quantia/tests/ledger/mocks"
+	paymentMocks "github.com/quabynah-bilson/quantia/tests/payment/mocks"
+	"github.com/quabynah-bilson/quantia/tests/payout/mocks"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// testCase is a struct that represents a test case.
+type testCase struct {
+	name           string
+	instructions   []payout.Instruction
+	amount         float32
+	approvedBy     string
+	expectedReport int
+	expectedErr    error
+}
+
+// approvedBatch creates and approves a batch paying the instructions from acc_1.
+func approvedBatch(t *testing.T, payoutUseCase *pkg.PayoutUseCase, instructions []payout.Instruction) *payout.Batch {
+	t.Helper()
+
+	batch, report, err := payoutUseCase.CreateBatch("acc_1", "maker@example.com", instructions)
+	if err != nil {
+		t.Fatalf("unexpected error: %v (%+v)", err, report)
+	}
+
+	if batch, err = payoutUseCase.Approve(batch.ID, "checker@example.com"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return batch
+}
+
+// TestPayoutUseCase_CreateBatch tests that batches are only stored when every row is valid.
+func TestPayoutUseCase_CreateBatch(t *testing.T) {
+	valid := payout.Instruction{DestinationType: beneficiary.DestinationMobileWallet, Destination: paymentMocks.Wallet, Amount: 10}
+	testCases := []testCase{
+		{
+			name:         "valid",
+			instructions: []payout.Instruction{valid, valid},
+		},
+		{
+			name:           "invalid rows",
+			instructions:   []payout.Instruction{valid, {DestinationType: beneficiary.DestinationBankAccount, Destination: "0123", Amount: -1}},
+			expectedReport: 2,
+			expectedErr:    pkg.ErrInvalidBatch,
+		},
+		{
+			name:           "too many rows",
+			instructions:   []payout.Instruction{valid, valid, valid, valid},
+			expectedReport: 1,
+			expectedErr:    pkg.ErrInvalidBatch,
+		},
+		{
+			name:        "no rows",
+			expectedErr: pkg.ErrInvalidBatch,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			ledgerRepo := ledgerMocks.NewMockLedgerRepository()
+			ledgerRepo.Fund("acc_1", 100)
+			simulator := paymentMocks.NewSimulator(provider.SimulatorConfig{})
+			payoutRepo := mocks.NewMockPayoutRepository()
+			payoutUseCase := pkg.NewPayoutUseCase(payoutRepo, ledgerRepo, simulator, pkg.PayoutConfig{MaxItems: 3})
+			paymentMocks.RouteResults(ledgerRepo, simulator, payout.IsItemReference, payoutUseCase.HandleProviderResult)
+
+			// Act
+			batch, report, err := payoutUseCase.CreateBatch("acc_1", "maker@example.com", tc.instructions)
+
+			// Assert
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error: %v, got: %v", tc.expectedErr, err)
+			}
+
+			if len(report) != tc.expectedReport {
+				t.Errorf("expected %d row errors, got: %+v", tc.expectedReport, report)
+			}
+
+			if err != nil {
+				if len(payoutRepo.Batches) != 0 || len(payoutRepo.ItemsByID) != 0 {
+					t.Errorf("expected nothing to be stored")
+				}
+				return
+			}
+
+			if batch.Status != payout.BatchStatusAwaitingApproval || batch.Items != 2 || batch.Total != 20 {
+				t.Errorf("expected a batch of 2 items totalling 20 awaiting approval, got: %+v", batch)
+			}
+		})
+	}
+}
+
+// TestPayoutUseCase_Approve tests that batches are approved by a second person and only when they can be paid.
+func TestPayoutUseCase_Approve(t *testing.T) {
+	testCases := []testCase{
+		{
+			name:       "approved by a second person",
+			amount:     60,
+			approvedBy: "checker@example.com",
+		},
+		{
+			name:        "approved by the creator",
+			amount:      60,
+			approvedBy:  "Maker@example.com",
+			expectedErr: pkg.ErrInvalidApproval,
+		},
+		{
+			name:        "approved anonymously",
+			amount:      60,
+			expectedErr: pkg.ErrInvalidApproval,
+		},
+		{
+			name:        "more than the balance",
+			amount:      120,
+			approvedBy:  "checker@example.com",
+			expectedErr: ledger.ErrInsufficientFunds,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			ledgerRepo := ledgerMocks.NewMockLedgerRepository()
+			ledgerRepo.Fund("acc_1", 100)
+			simulator := paymentMocks.NewSimulator(provider.SimulatorConfig{})
+			payoutUseCase := pkg.NewPayoutUseCase(mocks.NewMockPayoutRepository(), ledgerRepo, simulator, pkg.PayoutConfig{MaxItems: 3})
+			paymentMocks.RouteResults(ledgerRepo, simulator, payout.IsItemReference, payoutUseCase.HandleProviderResult)
+			batch, _, err := payoutUseCase.CreateBatch("acc_1", "maker@example.com", []payout.Instruction{
+				{DestinationType: beneficiary.DestinationMobileWallet, Destination: paymentMocks.Wallet, Amount: tc.amount},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// Act
+			approved, err := payoutUseCase.Approve(batch.ID, tc.approvedBy)
+
+			// Assert
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error: %v, got: %v", tc.expectedErr, err)
+			}
+
+			if err == nil && (approved.Status != payout.BatchStatusApproved || approved.ApprovedAt == nil) {
+				t.Errorf("expected an approved batch, got: %+v", approved)
+			}
+
+			if err != nil {
+				if stored, _ := payoutUseCase.GetBatch(batch.ID); stored.Status != payout.BatchStatusAwaitingApproval {
+					t.Errorf("expected the batch to await approval, got: %s", stored.Status)
+				}
+			}
+		})
+	}
+}
+
+// TestPayoutUseCase_RunQueued tests that approved batches are paid item by item, that declined and unaffordable
+// items are refunded or left unpaid, and that the batch completes with its counts.
+func TestPayoutUseCase_RunQueued(t *testing.T) {
+	// Arrange
+	ledgerRepo := ledgerMocks.NewMockLedgerRepository()
+	ledgerRepo.Fund("acc_1", 100)
+	simulator := paymentMocks.NewSimulator(provider.SimulatorConfig{})
+	payoutUseCase := pkg.NewPayoutUseCase(mocks.NewMockPayoutRepository(), ledgerRepo, simulator, pkg.PayoutConfig{MaxItems: 3})
+	paymentMocks.RouteResults(ledgerRepo, simulator, payout.IsItemReference, payoutUseCase.HandleProviderResult)
+	batch := approvedBatch(t, payoutUseCase, []payout.Instruction{
+		{DestinationType: beneficiary.DestinationMobileWallet, Destination: paymentMocks.Wallet, Amount: 30},
+		{DestinationType: beneficiary.DestinationMobileWallet, Destination: paymentMocks.DeclinedWallet, Amount: 20},
+		{DestinationType: beneficiary.DestinationInternalAccount, Destination: "acc_2", Amount: 45},
+	})
+
+	// spend part of the balance after the approval, so that the last item cannot be paid
+	if err := ledgerRepo.Post(ledger.NewTransfer("spend", "card payment", "acc_1", "system:cards", 40)...); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Act
+	processed := payoutUseCase.RunQueued()
+
+	// Assert
+	if processed != 1 {
+		t.Fatalf("expected 1 batch to be processed, got: %d", processed)
+	}
+
+	items, _ := payoutUseCase.GetItems(batch.ID)
+	var statuses []payout.ItemStatus
+	for _, item := range items {
+		statuses = append(statuses, item.Status)
+	}
+	expected := []payout.ItemStatus{payout.ItemStatusSucceeded, payout.ItemStatusFailed, payout.ItemStatusFailed}
+	if !reflect.DeepEqual(statuses, expected) {
+		t.Errorf("expected item statuses: %v, got: %v", expected, statuses)
+	}
+
+	if items[1].FailureReason != "payee_not_found" || items[2].FailureReason != ledger.ErrInsufficientFunds.Error() {
+		t.Errorf("expected the failure reasons to be kept, got: %q and %q", items[1].FailureReason, items[2].FailureReason)
+	}
+
+	if funds := ledgerRepo.Current("acc_1"); funds != 30 {
+		t.Errorf("expected acc_1 to keep 30, got: %v", funds)
+	}
+
+	batch, _ = payoutUseCase.GetBatch(batch.ID)
+	if batch.Status != payout.BatchStatusCompleted || batch.Succeeded != 1 || batch.Failed != 2 {
+		t.Errorf("expected a completed batch with 1 success and 2 failures, got: %+v", batch)
+	}
+
+	// a completed batch leaves the queue and its items are never paid again
+	if processed = payoutUseCase.RunQueued(); processed != 0 {
+		t.Errorf("expected no batch to be processed, got: %d", processed)
+	}
+}
+
+// TestPayoutUseCase_AsyncDisbursement tests that a batch completes when the provider reports its last item.
+func TestPayoutUseCase_AsyncDisbursement(t *testing.T) {
+	// Arrange
+	ledgerRepo := ledgerMocks.NewMockLedgerRepository()
+	ledgerRepo.Fund("acc_1", 100)
+	simulator := paymentMocks.NewSimulator(provider.SimulatorConfig{Behaviour: provider.BehaviourAsync, AsyncDelay: 10 * time.Millisecond})
+	payoutUseCase := pkg.NewPayoutUseCase(mocks.NewMockPayoutRepository(), ledgerRepo, simulator, pkg.PayoutConfig{MaxItems: 3})
+	paymentMocks.RouteResults(ledgerRepo, simulator, payout.IsItemReference, payoutUseCase.HandleProviderResult)
+	batch := approvedBatch(t, payoutUseCase, []payout.Instruction{
+		{DestinationType: beneficiary.DestinationMobileWallet, Destination: paymentMocks.Wallet, Amount: 30},
+		{DestinationType: beneficiary.DestinationInternalAccount, Destination: "acc_2", Amount: 20},
+	})
+
+	// Act
+	payoutUseCase.RunQueued()
+
+	// Assert
+	if batch, _ = payoutUseCase.GetBatch(batch.ID); batch.Status != payout.BatchStatusProcessing {
+		t.Fatalf("expected a processing batch, got: %s", batch.Status)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for batch.Status == payout.BatchStatusProcessing && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+		batch, _ = payoutUseCase.GetBatch(batch.ID)
+	}
+
+	if batch.Status != payout.BatchStatusCompleted || batch.Succeeded != 2 {
+		t.Errorf("expected a completed batch with 2 successes, got: %+v", batch)
+	}
+
+	if funds := ledgerRepo.Current("acc_1"); funds != 50 {
+		t.Errorf("expected acc_1 to keep 50, got: %v", funds)
+	}
+}
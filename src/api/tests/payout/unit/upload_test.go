@@ -0,0 +1,124 @@
+package unit
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/quabynah-bilson/quantia/pkg/beneficiary"
+	"github.com/quabynah-bilson/quantia/pkg/payout"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestParseCSV tests that payout files are read by column name and that unreadable amounts are reported.
+func TestParseCSV(t *testing.T) {
+	testCases := []struct {
+		name           string
+		file           string
+		expected       []payout.Instruction
+		expectedReport []payout.RowError
+		expectedErr    error
+	}{
+		{
+			name: "columns in any order",
+			file: "amount,destination,destination_type,reference,extra\n12.5,233240000001,mobile_wallet,INV-1,x\n 3 , acc_2 ,internal_account,,\n",
+			expected: []payout.Instruction{
+				{DestinationType: beneficiary.DestinationMobileWallet, Destination: "233240000001", Amount: 12.5, Reference: "INV-1"},
+				{DestinationType: beneficiary.DestinationInternalAccount, Destination: "acc_2", Amount: 3},
+			},
+		},
+		{
+			name: "amount that is not a number",
+			file: "destination_type,destination,bank_code,amount\nbank_account,0123456789,GCB,ten\n",
+			expected: []payout.Instruction{
+				{DestinationType: beneficiary.DestinationBankAccount, Destination: "0123456789", BankCode: "GCB"},
+			},
+			expectedReport: []payout.RowError{{Row: 1, Field: "amount", Message: "amount is not a number"}},
+		},
+		{name: "missing amount column", file: "destination_type,destination\nmobile_wallet,233240000001\n", expectedErr: payout.ErrInvalidFile},
+		{name: "empty file", file: "", expectedErr: payout.ErrInvalidFile},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			instructions, report, err := payout.ParseCSV(strings.NewReader(tc.file))
+
+			// Assert
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error: %v, got: %v", tc.expectedErr, err)
+			}
+
+			if !reflect.DeepEqual(instructions, tc.expected) {
+				t.Errorf("expected instructions: %+v, got: %+v", tc.expected, instructions)
+			}
+
+			if !reflect.DeepEqual(report, tc.expectedReport) {
+				t.Errorf("expected report: %+v, got: %+v", tc.expectedReport, report)
+			}
+		})
+	}
+}
+
+// TestValidate tests that every problem of every row is reported.
+func TestValidate(t *testing.T) {
+	// Arrange
+	instructions := []payout.Instruction{
+		{DestinationType: beneficiary.DestinationMobileWallet, Destination: "233240000001", Amount: 10, Reference: "INV-1"},
+		{DestinationType: "cheque", Destination: "", Amount: 0},
+		{DestinationType: beneficiary.DestinationBankAccount, Destination: "0123456789", Amount: 1.005, Reference: "INV-1"},
+		{DestinationType: beneficiary.DestinationInternalAccount, Destination: "acc_1", Amount: 5, BankCode: "GCB"},
+	}
+
+	// Act
+	report := payout.Validate("acc_1", instructions)
+
+	// Assert
+	var fields []string
+	for _, rowErr := range report {
+		fields = append(fields, fmt.Sprintf("%d:%s", rowErr.Row, rowErr.Field))
+	}
+	expected := []string{"2:destination_type", "2:destination", "2:amount", "3:bank_code", "3:amount", "3:reference", "4:destination"}
+	if !reflect.DeepEqual(fields, expected) {
+		t.Errorf("expected errors: %v, got: %v (%+v)", expected, fields, report)
+	}
+
+	if instructions[3].BankCode != "" {
+		t.Errorf("expected the bank code of a non-bank destination to be dropped, got: %q", instructions[3].BankCode)
+	}
+}
+
+// TestWriteResults tests that results files list each item with its status, in upload order.
+func TestWriteResults(t *testing.T) {
+	// Arrange
+	_, items := payout.NewBatch("acc_1", "ops@example.com", []payout.Instruction{
+		{DestinationType: beneficiary.DestinationMobileWallet, Destination: "233240000001", Amount: 12.5, Reference: "INV-1"},
+		{DestinationType: beneficiary.DestinationBankAccount, Destination: "0123456789", BankCode: "GCB", Amount: 3, Note: "March, rent"},
+	})
+	items[1].Status, items[1].FailureReason = payout.ItemStatusFailed, "payee_not_found"
+
+	// Act
+	var buf bytes.Buffer
+	err := payout.WriteResults(&buf, items)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := [][]string{
+		{"row", "destination_type", "destination", "bank_code", "amount", "reference", "note", "status", "provider_reference", "failure_reason"},
+		{"1", "mobile_wallet", "233240000001", "", "12.50", "INV-1", "", "pending", "", ""},
+		{"2", "bank_account", "0123456789", "GCB", "3.00", "", "March, rent", "failed", "", "payee_not_found"},
+	}
+	if !reflect.DeepEqual(records, expected) {
+		t.Errorf("expected results: %v, got: %v", expected, records)
+	}
+}
@@ -0,0 +1,174 @@
+package mocks
+
+import (
+	"github.com/quabynah-bilson/quantia/pkg/product"
+	"sort"
+	"sync"
+)
+
+// MockProductRepository is an in-memory product repository
+type MockProductRepository struct {
+	mu              sync.Mutex
+	Products        map[string]*product.Product
+	AccountProducts map[string]*product.Assignment
+	AccrualRecords  map[string]*product.Accrual
+	BusinessDays    map[string]*product.BusinessDay
+	lastCompleted   string
+}
+
+// NewMockProductRepository creates an empty in-memory product repository
+func NewMockProductRepository() *MockProductRepository {
+	return &MockProductRepository{
+		Products:        make(map[string]*product.Product),
+		AccountProducts: make(map[string]*product.Assignment),
+		AccrualRecords:  make(map[string]*product.Accrual),
+		BusinessDays:    make(map[string]*product.BusinessDay),
+	}
+}
+
+// Save stores a copy of a product
+func (m *MockProductRepository) Save(p *product.Product) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	copied := *p
+	copied.InterestTiers = append([]product.RateTier{}, p.InterestTiers...)
+	m.Products[p.ID] = &copied
+	return nil
+}
+
+// Find returns a copy of a product
+func (m *MockProductRepository) Find(id string) (*product.Product, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, ok := m.Products[id]
+	if !ok {
+		return nil, product.ErrProductNotFound
+	}
+
+	copied := *p
+	copied.InterestTiers = append([]product.RateTier{}, p.InterestTiers...)
+	return &copied, nil
+}
+
+// FindAll returns copies of every product, oldest first
+func (m *MockProductRepository) FindAll() ([]*product.Product, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	products := []*product.Product{}
+	for _, p := range m.Products {
+		copied := *p
+		products = append(products, &copied)
+	}
+	sort.Slice(products, func(i, j int) bool { return products[i].CreatedAt.Before(products[j].CreatedAt) })
+	return products, nil
+}
+
+// Assign stores a copy of the product of an account
+func (m *MockProductRepository) Assign(assignment *product.Assignment) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	copied := *assignment
+	m.AccountProducts[assignment.AccountID] = &copied
+	return nil
+}
+
+// FindAssignment returns a copy of the product of an account
+func (m *MockProductRepository) FindAssignment(accountID string) (*product.Assignment, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	assignment, ok := m.AccountProducts[accountID]
+	if !ok {
+		return nil, product.ErrAssignmentNotFound
+	}
+
+	copied := *assignment
+	return &copied, nil
+}
+
+// Assignments returns copies of the products of every account, by account ID
+func (m *MockProductRepository) Assignments() ([]*product.Assignment, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	assignments := []*product.Assignment{}
+	for _, assignment := range m.AccountProducts {
+		copied := *assignment
+		assignments = append(assignments, &copied)
+	}
+	sort.Slice(assignments, func(i, j int) bool { return assignments[i].AccountID < assignments[j].AccountID })
+	return assignments, nil
+}
+
+// SaveAccrual stores a copy of an accrual, once per account and business date
+func (m *MockProductRepository) SaveAccrual(accrual *product.Accrual) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := accrual.AccountID + ":" + accrual.Date
+	if _, ok := m.AccrualRecords[key]; ok {
+		return product.ErrAccrualAlreadyRecorded
+	}
+
+	copied := *accrual
+	m.AccrualRecords[key] = &copied
+	return nil
+}
+
+// Accruals returns copies of the accruals of an account between two business dates, oldest first
+func (m *MockProductRepository) Accruals(accountID, from, to string) ([]*product.Accrual, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	accruals := []*product.Accrual{}
+	for _, accrual := range m.AccrualRecords {
+		if accrual.AccountID == accountID && accrual.Date >= from && accrual.Date <= to {
+			copied := *accrual
+			accruals = append(accruals, &copied)
+		}
+	}
+	sort.Slice(accruals, func(i, j int) bool { return accruals[i].Date < accruals[j].Date })
+	return accruals, nil
+}
+
+// SaveBusinessDay stores a copy of the outcome of a business date
+func (m *MockProductRepository) SaveBusinessDay(day *product.BusinessDay) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	copied := *day
+	m.BusinessDays[day.Date] = &copied
+	if day.Status == product.BusinessDayCompleted && day.Date > m.lastCompleted {
+		m.lastCompleted = day.Date
+	}
+	return nil
+}
+
+// FindBusinessDay returns a copy of the outcome of a business date
+func (m *MockProductRepository) FindBusinessDay(date string) (*product.BusinessDay, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	day, ok := m.BusinessDays[date]
+	if !ok {
+		return nil, product.ErrBusinessDayNotFound
+	}
+
+	copied := *day
+	return &copied, nil
+}
+
+// LastCompletedDay returns the latest completed business date
+func (m *MockProductRepository) LastCompletedDay() (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.lastCompleted == "" {
+		return "", product.ErrBusinessDayNotFound
+	}
+	return m.lastCompleted, nil
+}
@@ -0,0 +1,44 @@
+package mocks
+
+import (
+	"github.com/quabynah-bilson/quantia/pkg"
+	"github.com/quabynah-bilson/quantia/pkg/product"
+	ledgerMocks "github.com/quabynah-bilson/quantia/tests/ledger/mocks"
+	"time"
+)
+
+// September is the first business date the accounts of SetUpAccounts hold their products
+var September = time.Date(2026, time.September, 1, 0, 0, 0, 0, time.UTC)
+
+// SetUpAccounts creates a savings product earning 3.65% a year held by acc_savings with 1000, and a current
+// product charging a maintenance fee of 5 and transfer fees of 1 + 1% up to 3 held by acc_current with 200,
+// both since September
+func SetUpAccounts(useCase *pkg.ProductUseCase, productRepo *MockProductRepository, ledgerRepo *ledgerMocks.MockLedgerRepository) (savings, current *product.Product, err error) {
+	if savings, err = useCase.CreateProduct(product.Product{
+		Name:          "Savings",
+		Type:          product.TypeSavings,
+		InterestTiers: []product.RateTier{{From: 0, Rate: 3.65}},
+	}); err != nil {
+		return nil, nil, err
+	}
+	if current, err = useCase.CreateProduct(product.Product{
+		Name: "Current",
+		Type: product.TypeCurrent,
+		Fees: product.FeeSchedule{Maintenance: 5, Transfer: product.FeeRule{Fixed: 1, Percent: 1, Max: 3}},
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	for accountID, productID := range map[string]string{"acc_savings": savings.ID, "acc_current": current.ID} {
+		if err = productRepo.Assign(product.NewAssignment(accountID, productID, September.Format(product.DateLayout))); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for accountID, amount := range map[string]float32{"acc_savings": 1000, "acc_current": 200} {
+		if err = ledgerRepo.FundAt(accountID, amount, September.AddDate(0, 0, -1)); err != nil {
+			return nil, nil, err
+		}
+	}
+	return savings, current, nil
+}
@@ -0,0 +1,183 @@
+package unit
+
+import (
+	"github.com/quabynah-bilson/quantia/pkg/product"
+	"math"
+	"testing"
+	"time"
+)
+
+// TestAnnualInterest tests that each band of a balance earns the rate of its tier.
+func TestAnnualInterest(t *testing.T) {
+	// Arrange
+	p := product.NewProduct(product.Product{
+		Name: "Tiered savings",
+		Type: product.TypeSavings,
+		InterestTiers: []product.RateTier{
+			{From: 5000, Rate: 3},
+			{From: 0, Rate: 1},
+			{From: 1000, Rate: 2},
+		},
+	})
+
+	testCases := []struct {
+		name     string
+		balance  float64
+		expected float64
+	}{
+		{name: "first tier", balance: 500, expected: 5},
+		{name: "second tier", balance: 3000, expected: 10 + 40},
+		{name: "every tier", balance: 10000, expected: 10 + 80 + 150},
+		{name: "empty", balance: 0, expected: 0},
+		{name: "overdrawn", balance: -200, expected: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			interest := p.AnnualInterest(tc.balance)
+
+			// Assert
+			if math.Abs(interest-tc.expected) > 1e-9 {
+				t.Errorf("expected interest: %v, got: %v", tc.expected, interest)
+			}
+		})
+	}
+
+	if p.DayCount != product.DayCountActual365 || p.InterestTiers[0].From != 0 {
+		t.Errorf("expected sorted tiers counted as actual/365, got: %+v", p)
+	}
+}
+
+// TestDailyOverdraftInterest tests that only closing balances below zero are charged overdraft interest.
+func TestDailyOverdraftInterest(t *testing.T) {
+	// Arrange
+	p := product.NewProduct(product.Product{Name: "Current", Type: product.TypeCurrent, Overdraft: product.OverdraftTerms{MaxLimit: 500, Rate: 36.5}})
+	date := time.Date(2026, time.September, 15, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		balance  float64
+		expected float64
+	}{
+		{name: "overdrawn", balance: -100, expected: 0.1},
+		{name: "empty", balance: 0, expected: 0},
+		{name: "in credit", balance: 100, expected: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			interest := p.DailyOverdraftInterest(tc.balance, date)
+
+			// Assert
+			if math.Abs(interest-tc.expected) > 1e-9 {
+				t.Errorf("expected interest: %v, got: %v", tc.expected, interest)
+			}
+		})
+	}
+
+	if !p.AccruesInterest() || p.DailyInterest(-100, date) != 0 {
+		t.Errorf("expected only overdraft interest to accrue, got: %+v", p)
+	}
+}
+
+// TestDayCountFraction tests the fraction of a year each day-count convention accrues on a date.
+func TestDayCountFraction(t *testing.T) {
+	testCases := []struct {
+		name     string
+		dayCount product.DayCount
+		date     string
+		expected float64
+	}{
+		{name: "actual/365 in a leap year", dayCount: product.DayCountActual365, date: "2028-03-01", expected: 1.0 / 365},
+		{name: "actual/360", dayCount: product.DayCountActual360, date: "2026-09-15", expected: 1.0 / 360},
+		{name: "actual/actual", dayCount: product.DayCountActualActual, date: "2026-09-15", expected: 1.0 / 365},
+		{name: "actual/actual in a leap year", dayCount: product.DayCountActualActual, date: "2028-03-01", expected: 1.0 / 366},
+		{name: "30/360", dayCount: product.DayCount30360, date: "2026-09-15", expected: 1.0 / 360},
+		{name: "30/360 on the 31st", dayCount: product.DayCount30360, date: "2026-10-31", expected: 0},
+		{name: "30/360 at the end of February", dayCount: product.DayCount30360, date: "2026-02-28", expected: 3.0 / 360},
+		{name: "30/360 at the end of a leap February", dayCount: product.DayCount30360, date: "2028-02-29", expected: 2.0 / 360},
+		{name: "30/360 before the end of a leap February", dayCount: product.DayCount30360, date: "2028-02-28", expected: 1.0 / 360},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			date, err := time.Parse(product.DateLayout, tc.date)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// Act
+			fraction := tc.dayCount.Fraction(date)
+
+			// Assert
+			if math.Abs(fraction-tc.expected) > 1e-12 {
+				t.Errorf("expected fraction: %v, got: %v", tc.expected, fraction)
+			}
+		})
+	}
+}
+
+// TestFeeRule tests fees of a fixed amount plus a percentage, kept between a minimum and a maximum.
+func TestFeeRule(t *testing.T) {
+	testCases := []struct {
+		name     string
+		rule     product.FeeRule
+		amount   float32
+		expected float32
+	}{
+		{name: "fixed and percent", rule: product.FeeRule{Fixed: 1, Percent: 1}, amount: 50, expected: 1.5},
+		{name: "minimum", rule: product.FeeRule{Percent: 1, Min: 2}, amount: 50, expected: 2},
+		{name: "maximum", rule: product.FeeRule{Fixed: 1, Percent: 1, Max: 3}, amount: 1000, expected: 3},
+		{name: "rounded to cents", rule: product.FeeRule{Percent: 0.75}, amount: 33.33, expected: 0.25},
+		{name: "free", rule: product.FeeRule{}, amount: 1000, expected: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			fee := tc.rule.Fee(tc.amount)
+
+			// Assert
+			if fee != tc.expected {
+				t.Errorf("expected fee: %v, got: %v", tc.expected, fee)
+			}
+		})
+	}
+}
+
+// TestProductIsValid tests the validation of product configurations.
+func TestProductIsValid(t *testing.T) {
+	testCases := []struct {
+		name     string
+		config   product.Product
+		expected bool
+	}{
+		{name: "savings", config: product.Product{Name: "Savings", Type: product.TypeSavings, InterestTiers: []product.RateTier{{Rate: 2.5}}}, expected: true},
+		{name: "current", config: product.Product{Name: "Current", Type: product.TypeCurrent, Fees: product.FeeSchedule{Maintenance: 5, Transfer: product.FeeRule{Fixed: 1}}}, expected: true},
+		{name: "no name", config: product.Product{Name: "  ", Type: product.TypeSavings}, expected: false},
+		{name: "unknown type", config: product.Product{Name: "Fixed", Type: "fixed"}, expected: false},
+		{name: "negative rate", config: product.Product{Name: "Savings", Type: product.TypeSavings, InterestTiers: []product.RateTier{{Rate: -1}}}, expected: false},
+		{name: "duplicate tiers", config: product.Product{Name: "Savings", Type: product.TypeSavings, InterestTiers: []product.RateTier{{Rate: 1}, {Rate: 2}}}, expected: false},
+		{name: "unknown day count", config: product.Product{Name: "Savings", Type: product.TypeSavings, InterestTiers: []product.RateTier{{Rate: 1}}, DayCount: "actual/364"}, expected: false},
+		{name: "maximum below minimum", config: product.Product{Name: "Current", Type: product.TypeCurrent, Fees: product.FeeSchedule{Transfer: product.FeeRule{Min: 5, Max: 2}}}, expected: false},
+		{name: "negative maintenance", config: product.Product{Name: "Current", Type: product.TypeCurrent, Fees: product.FeeSchedule{Maintenance: -5}}, expected: false},
+		{name: "overdraft", config: product.Product{Name: "Current", Type: product.TypeCurrent, Overdraft: product.OverdraftTerms{MaxLimit: 500, Rate: 18}}, expected: true},
+		{name: "negative overdraft", config: product.Product{Name: "Current", Type: product.TypeCurrent, Overdraft: product.OverdraftTerms{MaxLimit: -500}}, expected: false},
+		{name: "overdraft rate above 100%", config: product.Product{Name: "Current", Type: product.TypeCurrent, Overdraft: product.OverdraftTerms{MaxLimit: 500, Rate: 120}}, expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			valid := product.NewProduct(tc.config).IsValid()
+
+			// Assert
+			if valid != tc.expected {
+				t.Errorf("expected valid: %v, got: %v", tc.expected, valid)
+			}
+		})
+	}
+}
@@ -0,0 +1,356 @@
+package unit
+
+import (
+	"errors"
+	"github.com/quabynah-bilson/quantia/pkg"
+	"github.com/quabynah-bilson/quantia/pkg/ledger"
+	"github.com/quabynah-bilson/quantia/pkg/product"
+	"github.com/quabynah-bilson/quantia/pkg/transfer"
+	ledgerMocks "github.com/quabynah-bilson/quantia/tests/ledger/mocks"
+	"github.com/quabynah-bilson/quantia/tests/product/mocks"
+	transferMocks "github.com/quabynah-bilson/quantia/tests/transfer/mocks"
+	"testing"
+	"time"
+)
+
+// testCase is a struct that represents a test case.
+type testCase struct {
+	name string
+	date string
+}
+
+// TestProductUseCase_CreateProduct tests that invalid products are refused.
+func TestProductUseCase_CreateProduct(t *testing.T) {
+	// Arrange
+	productUseCase := pkg.NewProductUseCase(mocks.NewMockProductRepository(), ledgerMocks.NewMockLedgerRepository(), transferMocks.NewMockTransferRepository(), pkg.ProductConfig{})
+
+	// Act
+	_, err := productUseCase.CreateProduct(product.Product{Name: "Savings", Type: product.TypeSavings, InterestTiers: []product.RateTier{{Rate: 120}}})
+
+	// Assert
+	if !errors.Is(err, pkg.ErrInvalidProduct) {
+		t.Fatalf("expected error: %v, got: %v", pkg.ErrInvalidProduct, err)
+	}
+}
+
+// TestProductUseCase_RunEndOfDay_InvalidDate tests that only business dates that ended can be run.
+func TestProductUseCase_RunEndOfDay_InvalidDate(t *testing.T) {
+	testCases := []testCase{
+		{
+			name: "today",
+			date: time.Now().UTC().Format(product.DateLayout),
+		},
+		{
+			name: "tomorrow",
+			date: time.Now().UTC().AddDate(0, 0, 1).Format(product.DateLayout),
+		},
+		{
+			name: "unreadable",
+			date: "15/09/2026",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			productRepo := mocks.NewMockProductRepository()
+			ledgerRepo := ledgerMocks.NewMockLedgerRepository()
+			productUseCase := pkg.NewProductUseCase(productRepo, ledgerRepo, transferMocks.NewMockTransferRepository(), pkg.ProductConfig{})
+			if _, _, err := mocks.SetUpAccounts(productUseCase, productRepo, ledgerRepo); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// Act
+			_, err := productUseCase.RunEndOfDay(tc.date)
+
+			// Assert
+			if !errors.Is(err, pkg.ErrInvalidBusinessDate) {
+				t.Fatalf("expected error: %v, got: %v", pkg.ErrInvalidBusinessDate, err)
+			}
+		})
+	}
+}
+
+// TestProductUseCase_RunEndOfDay_Accrual tests that the interest of the closing balance is accrued, but not
+// posted, before the end of the month.
+func TestProductUseCase_RunEndOfDay_Accrual(t *testing.T) {
+	// Arrange
+	productRepo := mocks.NewMockProductRepository()
+	ledgerRepo := ledgerMocks.NewMockLedgerRepository()
+	productUseCase := pkg.NewProductUseCase(productRepo, ledgerRepo, transferMocks.NewMockTransferRepository(), pkg.ProductConfig{})
+	if _, _, err := mocks.SetUpAccounts(productUseCase, productRepo, ledgerRepo); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := ledgerRepo.FundAt("acc_savings", 825, mocks.September.AddDate(0, 0, 14).Add(12*time.Hour)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := ledgerRepo.FundAt("acc_savings", 500, mocks.September.AddDate(0, 0, 15).Add(time.Hour)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Act
+	day, err := productUseCase.RunEndOfDay("2026-09-15")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if day.Status != product.BusinessDayCompleted || day.Accounts != 2 || day.Interest != 0 || day.Fees != 0 {
+		t.Errorf("expected a completed date for 2 accounts without postings, got: %+v", day)
+	}
+
+	accruals, err := productUseCase.GetAccruals("acc_savings", "2026-09-01", "2026-09-30")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 1825 at 3.65% a year is 0.1825 a day; the credit of the 16th is not in the closing balance
+	if len(accruals) != 1 || accruals[0].Balance != 1825 || !approximately(accruals[0].Amount, 0.1825) {
+		t.Errorf("expected one accrual of 0.1825 on 1825, got: %+v", accruals)
+	}
+
+	if balance := ledgerRepo.Current("acc_savings"); balance != 2325 {
+		t.Errorf("expected no interest posted, got balance: %v", balance)
+	}
+}
+
+// TestProductUseCase_RunEndOfDay_TransferFees tests that the transfers of a business date that did not fail
+// pay the transfer fee of the account's product.
+func TestProductUseCase_RunEndOfDay_TransferFees(t *testing.T) {
+	// Arrange
+	productRepo := mocks.NewMockProductRepository()
+	ledgerRepo := ledgerMocks.NewMockLedgerRepository()
+	transferRepo := transferMocks.NewMockTransferRepository()
+	productUseCase := pkg.NewProductUseCase(productRepo, ledgerRepo, transferRepo, pkg.ProductConfig{})
+	if _, _, err := mocks.SetUpAccounts(productUseCase, productRepo, ledgerRepo); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	at := mocks.September.AddDate(0, 0, 14).Add(9 * time.Hour)
+	for _, tr := range []*transfer.Transfer{
+		{ID: "trf_1", AccountID: "acc_current", Amount: 50, Status: transfer.StatusCompleted, CreatedAt: at},
+		{ID: "trf_2", AccountID: "acc_current", Amount: 500, Status: transfer.StatusPending, CreatedAt: at.Add(time.Hour)},
+		{ID: "trf_3", AccountID: "acc_current", Amount: 80, Status: transfer.StatusFailed, CreatedAt: at.Add(2 * time.Hour)},
+		{ID: "trf_4", AccountID: "acc_savings", Amount: 10, Status: transfer.StatusCompleted, CreatedAt: at},
+		{ID: "trf_5", AccountID: "acc_current", Amount: 10, Status: transfer.StatusCompleted, CreatedAt: at.AddDate(0, 0, 1)},
+	} {
+		_ = transferRepo.Save(tr)
+	}
+
+	// Act
+	day, err := productUseCase.RunEndOfDay("2026-09-15")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 1 + 1% of 50, then 1 + 1% of 500 capped at 3; the savings product is free
+	if day.Fees != 4.5 {
+		t.Errorf("expected fees: 4.5, got: %v", day.Fees)
+	}
+
+	if balance := ledgerRepo.Current("acc_current"); balance != 195.5 {
+		t.Errorf("expected balance: 195.5, got: %v", balance)
+	}
+
+	if income := ledgerRepo.Current(ledger.FeeIncomeAccountID); income != 4.5 {
+		t.Errorf("expected fee income: 4.5, got: %v", income)
+	}
+
+	entries, _ := ledgerRepo.Entries("acc_current")
+	if last := entries[len(entries)-1]; last.CreatedAt.Format(product.DateLayout) != "2026-09-15" {
+		t.Errorf("expected the fees to be posted on the business date, got: %v", last.CreatedAt)
+	}
+}
+
+// TestProductUseCase_RunDue_MonthEnd tests that a month of business dates accrues interest daily, then posts
+// it with the maintenance fee on the last day.
+func TestProductUseCase_RunDue_MonthEnd(t *testing.T) {
+	// Arrange
+	productRepo := mocks.NewMockProductRepository()
+	ledgerRepo := ledgerMocks.NewMockLedgerRepository()
+	productUseCase := pkg.NewProductUseCase(productRepo, ledgerRepo, transferMocks.NewMockTransferRepository(), pkg.ProductConfig{})
+	if _, _, err := mocks.SetUpAccounts(productUseCase, productRepo, ledgerRepo); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := productUseCase.RunEndOfDay("2026-08-31"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Act
+	completed := productUseCase.RunDue(mocks.September.AddDate(0, 1, 0).Add(6 * time.Hour))
+
+	// Assert
+	if completed != 30 {
+		t.Fatalf("expected 30 business dates completed, got: %d", completed)
+	}
+
+	// 1000 at 3.65% a year is 0.10 a day
+	if balance := ledgerRepo.Current("acc_savings"); balance != 1003 {
+		t.Errorf("expected balance: 1003, got: %v", balance)
+	}
+
+	if balance := ledgerRepo.Current("acc_current"); balance != 195 {
+		t.Errorf("expected balance: 195, got: %v", balance)
+	}
+
+	if expense := ledgerRepo.Current(ledger.InterestExpenseAccountID); expense != -3 {
+		t.Errorf("expected interest expense: -3, got: %v", expense)
+	}
+
+	day, err := productUseCase.GetBusinessDay("2026-09-30")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if day.Interest != 3 || day.Fees != 5 || day.Status != product.BusinessDayCompleted {
+		t.Errorf("expected interest of 3 and fees of 5 on the last day, got: %+v", day)
+	}
+}
+
+// TestProductUseCase_RunDue_OverdraftInterest tests that an account below zero accrues overdraft interest
+// daily, and is charged it on the last day of the month.
+func TestProductUseCase_RunDue_OverdraftInterest(t *testing.T) {
+	// Arrange
+	productRepo := mocks.NewMockProductRepository()
+	ledgerRepo := ledgerMocks.NewMockLedgerRepository()
+	productUseCase := pkg.NewProductUseCase(productRepo, ledgerRepo, transferMocks.NewMockTransferRepository(), pkg.ProductConfig{})
+	if _, _, err := mocks.SetUpAccounts(productUseCase, productRepo, ledgerRepo); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	overdraft, err := productUseCase.CreateProduct(product.Product{
+		Name:      "Current with overdraft",
+		Type:      product.TypeCurrent,
+		Overdraft: product.OverdraftTerms{MaxLimit: 500, Rate: 36.5},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err = productRepo.Assign(product.NewAssignment("acc_overdrawn", overdraft.ID, mocks.September.Format(product.DateLayout))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	entries := ledger.NewTransfer("spend:acc_overdrawn", "card", "acc_overdrawn", ledger.CardSettlementAccountID, 100)
+	entries[0].CreatedAt, entries[1].CreatedAt = mocks.September.AddDate(0, 0, -1), mocks.September.AddDate(0, 0, -1)
+	if err = ledgerRepo.Post(entries...); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err = productUseCase.RunEndOfDay("2026-08-31"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Act
+	productUseCase.RunDue(mocks.September.AddDate(0, 1, 0))
+
+	// Assert
+	// 100 overdrawn at 36.5% a year is 0.10 a day
+	accruals, _ := productUseCase.GetAccruals("acc_overdrawn", "2026-09-01", "2026-09-30")
+	if len(accruals) != 30 || !approximately(accruals[0].Overdraft, 0.1) || accruals[0].Amount != 0 {
+		t.Errorf("expected 30 overdraft accruals of 0.10, got: %+v", accruals)
+	}
+
+	if balance := ledgerRepo.Current("acc_overdrawn"); balance != -103 {
+		t.Errorf("expected balance: -103, got: %v", balance)
+	}
+
+	if income := ledgerRepo.Current(ledger.InterestIncomeAccountID); income != 3 {
+		t.Errorf("expected interest income: 3, got: %v", income)
+	}
+
+	day, _ := productUseCase.GetBusinessDay("2026-09-30")
+	if day.OverdraftInterest != 3 || day.Interest != 3 {
+		t.Errorf("expected overdraft interest of 3 besides the savings interest of 3, got: %+v", day)
+	}
+}
+
+// TestProductUseCase_RunEndOfDay_Idempotent tests that running a business date again posts nothing twice.
+func TestProductUseCase_RunEndOfDay_Idempotent(t *testing.T) {
+	// Arrange
+	productRepo := mocks.NewMockProductRepository()
+	ledgerRepo := ledgerMocks.NewMockLedgerRepository()
+	transferRepo := transferMocks.NewMockTransferRepository()
+	productUseCase := pkg.NewProductUseCase(productRepo, ledgerRepo, transferRepo, pkg.ProductConfig{})
+	if _, _, err := mocks.SetUpAccounts(productUseCase, productRepo, ledgerRepo); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = transferRepo.Save(&transfer.Transfer{ID: "trf_1", AccountID: "acc_current", Amount: 50, Status: transfer.StatusCompleted, CreatedAt: mocks.September.AddDate(0, 0, 29).Add(time.Hour)})
+	for date := mocks.September; date.Month() == time.September; date = date.AddDate(0, 0, 1) {
+		if _, err := productUseCase.RunEndOfDay(date.Format(product.DateLayout)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// Act
+	completed, err := productUseCase.RunEndOfDay("2026-09-30")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the outcome is forgotten, as if the batch had stopped before saving it
+	delete(productRepo.BusinessDays, "2026-09-30")
+	rerun, err := productUseCase.RunEndOfDay("2026-09-30")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if completed.Interest != 3 || completed.Fees != 6.5 {
+		t.Errorf("expected the completed date to be returned as is, got: %+v", completed)
+	}
+
+	if rerun.Status != product.BusinessDayCompleted || rerun.Accrued != 0 || rerun.Interest != 0 || rerun.Fees != 0 {
+		t.Errorf("expected nothing posted again, got: %+v", rerun)
+	}
+
+	if savings, current := ledgerRepo.Current("acc_savings"), ledgerRepo.Current("acc_current"); savings != 1003 || current != 193.5 {
+		t.Errorf("expected balances of 1003 and 193.5, got: %v and %v", savings, current)
+	}
+}
+
+// TestProductUseCase_RunDue_CatchUp tests that missed business dates are run oldest first, at most
+// MaxCatchUpDays at a time, and that accounts are left out before they hold their product.
+func TestProductUseCase_RunDue_CatchUp(t *testing.T) {
+	// Arrange
+	productRepo := mocks.NewMockProductRepository()
+	ledgerRepo := ledgerMocks.NewMockLedgerRepository()
+	productUseCase := pkg.NewProductUseCase(productRepo, ledgerRepo, transferMocks.NewMockTransferRepository(), pkg.ProductConfig{MaxCatchUpDays: 5})
+	_, current, err := mocks.SetUpAccounts(productUseCase, productRepo, ledgerRepo)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err = productRepo.Assign(product.NewAssignment("acc_current", current.ID, "2026-08-28")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err = productUseCase.RunEndOfDay("2026-08-25"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Act
+	completed := productUseCase.RunDue(mocks.September.AddDate(0, 1, 0))
+
+	// Assert
+	if completed != 5 {
+		t.Fatalf("expected 5 business dates completed, got: %d", completed)
+	}
+
+	last, err := productRepo.LastCompletedDay()
+	if err != nil || last != "2026-08-30" {
+		t.Errorf("expected 2026-08-30 to be the last completed date, got: %v (%v)", last, err)
+	}
+
+	for date, accounts := range map[string]int{"2026-08-27": 0, "2026-08-28": 1, "2026-08-30": 1} {
+		day, err := productUseCase.GetBusinessDay(date)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if day.Accounts != accounts {
+			t.Errorf("expected %d accounts on %s, got: %d", accounts, date, day.Accounts)
+		}
+	}
+}
+
+// approximately reports whether two amounts are equal to a millionth
+func approximately(a, b float64) bool {
+	return a-b < 1e-6 && b-a < 1e-6
+}
@@ -0,0 +1,79 @@
+package mocks
+
+import (
+	"github.com/quabynah-bilson/quantia/pkg/reconciliation"
+	"sync"
+)
+
+// MockReconciliationRepository is an in-memory reconciliation repository
+type MockReconciliationRepository struct {
+	mu             sync.Mutex
+	Statements     map[string]*reconciliation.Statement
+	Checksums      map[string]string
+	StatementItems map[string][]reconciliation.Item
+	DateItemsByDay map[string][]reconciliation.Item
+}
+
+// NewMockReconciliationRepository creates an empty in-memory reconciliation repository
+func NewMockReconciliationRepository() *MockReconciliationRepository {
+	return &MockReconciliationRepository{
+		Statements:     make(map[string]*reconciliation.Statement),
+		Checksums:      make(map[string]string),
+		StatementItems: make(map[string][]reconciliation.Item),
+		DateItemsByDay: make(map[string][]reconciliation.Item),
+	}
+}
+
+// Save saves copies of the statement and its items, refusing a checksum seen before
+func (m *MockReconciliationRepository) Save(statement *reconciliation.Statement, items []*reconciliation.Item) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.Checksums[statement.Checksum]; ok {
+		return reconciliation.ErrStatementAlreadyImported
+	}
+	m.Checksums[statement.Checksum] = statement.ID
+
+	copied := *statement
+	m.Statements[statement.ID] = &copied
+	for _, item := range items {
+		m.StatementItems[statement.ID] = append(m.StatementItems[statement.ID], *item)
+		m.DateItemsByDay[item.Date] = append(m.DateItemsByDay[item.Date], *item)
+	}
+	return nil
+}
+
+// Find returns a copy of the statement
+func (m *MockReconciliationRepository) Find(id string) (*reconciliation.Statement, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	statement, ok := m.Statements[id]
+	if !ok {
+		return nil, reconciliation.ErrStatementNotFound
+	}
+	copied := *statement
+	return &copied, nil
+}
+
+// Items returns copies of the items of a statement
+func (m *MockReconciliationRepository) Items(statementID string) ([]*reconciliation.Item, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return copyItems(m.StatementItems[statementID]), nil
+}
+
+// DateItems returns copies of the items falling on a business date
+func (m *MockReconciliationRepository) DateItems(date string) ([]*reconciliation.Item, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return copyItems(m.DateItemsByDay[date]), nil
+}
+
+// copyItems returns pointers to copies of the items
+func copyItems(items []reconciliation.Item) []*reconciliation.Item {
+	copied := make([]*reconciliation.Item, 0, len(items))
+	for i := range items {
+		item := items[i]
+		copied = append(copied, &item)
+	}
+	return copied
+}
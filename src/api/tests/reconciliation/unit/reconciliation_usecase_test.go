@@ -0,0 +1,199 @@
+package unit
+
+import (
+	"bytes"
+	"errors"
+	"github.com/quabynah-bilson/quantia/pkg"
+	"github.com/quabynah-bilson/quantia/pkg/iso20022"
+	"github.com/quabynah-bilson/quantia/pkg/payment"
+	"github.com/quabynah-bilson/quantia/pkg/reconciliation"
+	paymentMocks "github.com/quabynah-bilson/quantia/tests/payment/mocks"
+	"github.com/quabynah-bilson/quantia/tests/reconciliation/mocks"
+	"os"
+	"testing"
+)
+
+// statement is a provider statement for 18 October covering every outcome of matching
+const statement = `reference,amount,date
+tx_ok,100.00,2026-10-18
+tx_close,49.80,2026-10-18
+tx_short,40.00,2026-10-18
+tx_failed,20.00,2026-10-18
+rfd_ok,-10.00,2026-10-18T16:00:00Z
+tx_ok,100.00,2026-10-18
+tx_unknown,5.00,2026-10-18
+tx_other,30.00,2026-10-18
+`
+
+// newReconciliationUseCase creates a reconciliation use case, with a tolerance of 0.25, over transactions of
+// shop.example.com and other.example.com
+func newReconciliationUseCase() (*pkg.ReconciliationUseCase, *mocks.MockReconciliationRepository) {
+	paymentRepo := paymentMocks.NewMockPaymentRepository()
+	for _, transaction := range []*payment.Transaction{
+		{ID: "tx_ok", Amount: 100, Status: payment.TransactionStatusPartiallyRefunded, Url: "https://shop.example.com/hook"},
+		{ID: "tx_close", Amount: 50, Status: payment.TransactionStatusSuccess, Url: "https://shop.example.com/hook"},
+		{ID: "tx_short", Amount: 50, Status: payment.TransactionStatusSuccess, Url: "https://shop.example.com/hook"},
+		{ID: "tx_failed", Amount: 20, Status: payment.TransactionStatusFailed, Url: "https://shop.example.com/hook"},
+		{ID: "tx_other", Amount: 30, Status: payment.TransactionStatusSuccess, Url: "https://other.example.com/hook"},
+	} {
+		_ = paymentRepo.Save(transaction)
+	}
+	_ = paymentRepo.SaveRefund(&payment.Refund{ID: "rfd_ok", TransactionID: "tx_ok", Amount: 10, Status: payment.RefundStatusSucceeded})
+
+	reconciliationRepo := mocks.NewMockReconciliationRepository()
+	useCase := pkg.NewReconciliationUseCase(reconciliationRepo, paymentRepo, pkg.ReconciliationConfig{
+		Tolerance: reconciliation.Tolerance{Amount: 0.25},
+	})
+
+	return useCase, reconciliationRepo
+}
+
+// TestImportCSV tests that statement entries are matched by reference and amount, and mismatches are explained.
+func TestImportCSV(t *testing.T) {
+	// Arrange
+	useCase, _ := newReconciliationUseCase()
+
+	// Act
+	stmt, err := useCase.ImportCSV("momo", []byte(statement))
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if stmt.Lines != 8 || stmt.Matched != 4 || stmt.Mismatched != 3 || stmt.Unmatched != 1 {
+		t.Errorf("unexpected totals: %+v", stmt)
+	}
+
+	items, err := useCase.GetItems(stmt.ID, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []struct {
+		status   reconciliation.ItemStatus
+		itemType reconciliation.ItemType
+		reason   string
+	}{
+		{reconciliation.ItemStatusMatched, reconciliation.ItemTypePayment, ""},
+		{reconciliation.ItemStatusMatched, reconciliation.ItemTypePayment, ""},
+		{reconciliation.ItemStatusMismatched, reconciliation.ItemTypePayment, "the settled amount differs by -10.00"},
+		{reconciliation.ItemStatusMismatched, reconciliation.ItemTypePayment, "the transaction is failed"},
+		{reconciliation.ItemStatusMatched, reconciliation.ItemTypeRefund, ""},
+		{reconciliation.ItemStatusMismatched, reconciliation.ItemTypePayment, "the reference appears more than once in the statement"},
+		{reconciliation.ItemStatusUnmatched, "", "no transaction or refund has this reference"},
+		{reconciliation.ItemStatusMatched, reconciliation.ItemTypePayment, ""},
+	}
+	for i, item := range items {
+		if item.Row != i+1 || item.Status != expected[i].status || item.Type != expected[i].itemType || item.Reason != expected[i].reason {
+			t.Errorf("row %d: expected %+v, got: %+v", i+1, expected[i], item)
+		}
+	}
+
+	if items[4].TransactionID != "tx_ok" || items[4].Merchant != "merchant:shop.example.com" {
+		t.Errorf("expected the refund to be matched to tx_ok of shop.example.com, got: %+v", items[4])
+	}
+
+	unmatched, _ := useCase.GetItems(stmt.ID, reconciliation.ItemStatusUnmatched)
+	if len(unmatched) != 1 || unmatched[0].Line.Reference != "tx_unknown" {
+		t.Errorf("expected tx_unknown to be the only unmatched item, got: %+v", unmatched)
+	}
+}
+
+// TestImportCSV_Rejections tests that statements without a source, without entries or imported twice are refused.
+func TestImportCSV_Rejections(t *testing.T) {
+	// Arrange
+	useCase, reconciliationRepo := newReconciliationUseCase()
+	if _, err := useCase.ImportCSV("momo", []byte(statement)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	testCases := []struct {
+		name        string
+		source      string
+		file        string
+		expectedErr error
+	}{
+		{name: "same file again", source: "bank", file: statement, expectedErr: reconciliation.ErrStatementAlreadyImported},
+		{name: "no source", source: " ", file: "reference,amount,date\ntx_ok,100,2026-10-19\n", expectedErr: pkg.ErrInvalidStatementSource},
+		{name: "no entries", source: "momo", file: "reference,amount,date\n", expectedErr: reconciliation.ErrInvalidStatement},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			_, err := useCase.ImportCSV(tc.source, []byte(tc.file))
+
+			// Assert
+			if !errors.Is(err, tc.expectedErr) {
+				t.Errorf("expected error: %v, got: %v", tc.expectedErr, err)
+			}
+		})
+	}
+
+	if len(reconciliationRepo.Statements) != 1 {
+		t.Errorf("expected one statement to be stored, got: %d", len(reconciliationRepo.Statements))
+	}
+}
+
+// TestSettlementReports tests that the daily report of each merchant sums its reconciled entries.
+func TestSettlementReports(t *testing.T) {
+	// Arrange
+	useCase, _ := newReconciliationUseCase()
+	if _, err := useCase.ImportCSV("momo", []byte(statement)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Act
+	reports, err := useCase.SettlementReports("2026-10-18", "merchant:shop.example.com")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(reports) != 1 {
+		t.Fatalf("expected one report, got: %d", len(reports))
+	}
+
+	report := reports[0]
+	if report.Payments != 2 || report.Gross != 149.8 || report.Refunds != 1 || report.Refunded != 10 || report.Net != 139.8 || report.Mismatched != 3 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+
+	if all, _ := useCase.SettlementReports("2026-10-18", ""); len(all) != 2 {
+		t.Errorf("expected reports for two merchants, got: %d", len(all))
+	}
+
+	if _, err = useCase.SettlementReports("18/10/2026", ""); !errors.Is(err, pkg.ErrInvalidSettlementDate) {
+		t.Errorf("expected error: %v, got: %v", pkg.ErrInvalidSettlementDate, err)
+	}
+}
+
+// TestImportCamt053 tests that the booked entries of a bank's camt.053 statement are reconciled like a CSV statement.
+func TestImportCamt053(t *testing.T) {
+	// Arrange
+	useCase, _ := newReconciliationUseCase()
+	sample, err := os.ReadFile("../../iso20022/unit/testdata/camt053.xml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sample = bytes.ReplaceAll(sample, []byte("tx_1"), []byte("tx_ok"))
+
+	// Act
+	stmt, err := useCase.ImportCamt053("bank", sample)
+	_, invalidErr := useCase.ImportCamt053("bank", []byte(statement))
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if stmt.Lines != 3 || stmt.Matched != 1 || stmt.Mismatched != 0 || stmt.Unmatched != 2 {
+		t.Errorf("unexpected totals: %+v", stmt)
+	}
+
+	if !errors.Is(invalidErr, iso20022.ErrInvalidDocument) {
+		t.Errorf("expected error: %v, got: %v", iso20022.ErrInvalidDocument, invalidErr)
+	}
+}
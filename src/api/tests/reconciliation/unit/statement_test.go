@@ -0,0 +1,116 @@
+package unit
+
+import (
+	"bytes"
+	"errors"
+	"github.com/quabynah-bilson/quantia/pkg/reconciliation"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestParseCSV tests that statement files are read by column name and that unreadable entries fail the file.
+func TestParseCSV(t *testing.T) {
+	testCases := []struct {
+		name        string
+		file        string
+		expected    []reconciliation.Line
+		expectedErr error
+	}{
+		{
+			name: "columns in any order",
+			file: "date,amount,reference,description,extra\n2026-10-18,12.50,tx_1,wallet payment,x\n2026-10-18T23:10:00Z, -3 , rfd_1 ,,\n",
+			expected: []reconciliation.Line{
+				{Reference: "tx_1", Amount: 12.5, Date: time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC), Description: "wallet payment"},
+				{Reference: "rfd_1", Amount: -3, Date: time.Date(2026, 10, 18, 23, 10, 0, 0, time.UTC)},
+			},
+		},
+		{name: "amount that is not a number", file: "reference,amount,date\ntx_1,ten,2026-10-18\n", expectedErr: reconciliation.ErrInvalidStatement},
+		{name: "date that cannot be read", file: "reference,amount,date\ntx_1,10,18/10/2026\n", expectedErr: reconciliation.ErrInvalidStatement},
+		{name: "missing date column", file: "reference,amount\ntx_1,10\n", expectedErr: reconciliation.ErrInvalidStatement},
+		{name: "no entries", file: "reference,amount,date\n", expectedErr: reconciliation.ErrInvalidStatement},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			lines, err := reconciliation.ParseCSV(strings.NewReader(tc.file))
+
+			// Assert
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error: %v, got: %v", tc.expectedErr, err)
+			}
+
+			if !reflect.DeepEqual(lines, tc.expected) {
+				t.Errorf("expected lines: %+v, got: %+v", tc.expected, lines)
+			}
+		})
+	}
+}
+
+// TestToleranceAllows tests that the larger of the absolute and percentage tolerances applies.
+func TestToleranceAllows(t *testing.T) {
+	testCases := []struct {
+		name      string
+		tolerance reconciliation.Tolerance
+		settled   float32
+		expected  bool
+	}{
+		{name: "exact amount without tolerance", settled: 100, expected: true},
+		{name: "one cent off without tolerance", settled: 99.99, expected: false},
+		{name: "within the absolute tolerance", tolerance: reconciliation.Tolerance{Amount: 0.5}, settled: 100.5, expected: true},
+		{name: "beyond the absolute tolerance", tolerance: reconciliation.Tolerance{Amount: 0.5}, settled: 99.49, expected: false},
+		{name: "within the percentage tolerance", tolerance: reconciliation.Tolerance{Amount: 0.5, Percent: 1}, settled: 99, expected: true},
+		{name: "beyond the percentage tolerance", tolerance: reconciliation.Tolerance{Percent: 1}, settled: 101.01, expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			allowed := tc.tolerance.Allows(100, tc.settled)
+
+			// Assert
+			if allowed != tc.expected {
+				t.Errorf("expected allowed: %v, got: %v", tc.expected, allowed)
+			}
+		})
+	}
+}
+
+// TestBuildReports tests that the items of a date are summed per merchant, leaving out unmatched items and other dates.
+func TestBuildReports(t *testing.T) {
+	// Arrange
+	items := []*reconciliation.Item{
+		{Date: "2026-10-18", Merchant: "merchant:b.example.com", Status: reconciliation.ItemStatusMatched, Type: reconciliation.ItemTypePayment, Line: reconciliation.Line{Amount: 10}},
+		{Date: "2026-10-18", Merchant: "merchant:a.example.com", Status: reconciliation.ItemStatusMatched, Type: reconciliation.ItemTypePayment, Line: reconciliation.Line{Amount: 20.1}, Difference: 0.1},
+		{Date: "2026-10-18", Merchant: "merchant:a.example.com", Status: reconciliation.ItemStatusMatched, Type: reconciliation.ItemTypePayment, Line: reconciliation.Line{Amount: 5}},
+		{Date: "2026-10-18", Merchant: "merchant:a.example.com", Status: reconciliation.ItemStatusMatched, Type: reconciliation.ItemTypeRefund, Line: reconciliation.Line{Amount: -2.5}},
+		{Date: "2026-10-18", Merchant: "merchant:a.example.com", Status: reconciliation.ItemStatusMismatched, Type: reconciliation.ItemTypePayment, Line: reconciliation.Line{Amount: 7}, Difference: -3},
+		{Date: "2026-10-18", Status: reconciliation.ItemStatusUnmatched, Line: reconciliation.Line{Amount: 50}},
+		{Date: "2026-10-17", Merchant: "merchant:a.example.com", Status: reconciliation.ItemStatusMatched, Type: reconciliation.ItemTypePayment, Line: reconciliation.Line{Amount: 40}},
+	}
+
+	// Act
+	reports := reconciliation.BuildReports("2026-10-18", items)
+
+	// Assert
+	expected := []*reconciliation.SettlementReport{
+		{Date: "2026-10-18", Merchant: "merchant:a.example.com", Payments: 2, Gross: 25.1, Refunds: 1, Refunded: 2.5, Net: 22.6, Mismatched: 1, Difference: -2.9},
+		{Date: "2026-10-18", Merchant: "merchant:b.example.com", Payments: 1, Gross: 10, Net: 10},
+	}
+	if !reflect.DeepEqual(reports, expected) {
+		for _, report := range reports {
+			t.Logf("%+v", report)
+		}
+		t.Fatalf("unexpected reports")
+	}
+
+	var buf bytes.Buffer
+	if err := reconciliation.WriteReports(&buf, reports); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(buf.String()), "\n"); len(lines) != 3 || lines[1] != "2026-10-18,merchant:a.example.com,2,25.10,1,2.50,22.60,1,-2.90" {
+		t.Errorf("unexpected CSV: %q", buf.String())
+	}
+}
@@ -0,0 +1,105 @@
+package mocks
+
+import (
+	"github.com/quabynah-bilson/quantia/pkg/schedule"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MockScheduleRepository is an in-memory scheduled payment repository
+type MockScheduleRepository struct {
+	mu        sync.Mutex
+	Schedules map[string]*schedule.ScheduledPayment
+	Claims    map[string]map[int]bool
+	RunsByID  map[string][]*schedule.Run
+
+	// ClaimErr, when set, is returned by Claim instead of taking the occurrence
+	ClaimErr error
+}
+
+// NewMockScheduleRepository creates an empty in-memory schedule repository
+func NewMockScheduleRepository() *MockScheduleRepository {
+	return &MockScheduleRepository{
+		Schedules: make(map[string]*schedule.ScheduledPayment),
+		Claims:    make(map[string]map[int]bool),
+		RunsByID:  make(map[string][]*schedule.Run),
+	}
+}
+
+// Save stores a copy of the schedule
+func (m *MockScheduleRepository) Save(s *schedule.ScheduledPayment) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	copied := *s
+	m.Schedules[s.ID] = &copied
+	return nil
+}
+
+// Find returns a copy of the schedule
+func (m *MockScheduleRepository) Find(id string) (*schedule.ScheduledPayment, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.Schedules[id]
+	if !ok {
+		return nil, schedule.ErrScheduleNotFound
+	}
+	copied := *s
+	return &copied, nil
+}
+
+// Due returns the active schedules whose next run is at or before now, earliest first
+func (m *MockScheduleRepository) Due(now time.Time, limit int) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var due []*schedule.ScheduledPayment
+	for _, s := range m.Schedules {
+		if s.Status == schedule.StatusActive && !s.NextRunAt.After(now) {
+			due = append(due, s)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].NextRunAt.Before(due[j].NextRunAt) })
+
+	var ids []string
+	for _, s := range due {
+		if len(ids) == limit {
+			break
+		}
+		ids = append(ids, s.ID)
+	}
+
+	return ids, nil
+}
+
+// Claim takes an occurrence once
+func (m *MockScheduleRepository) Claim(id string, occurrence int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.ClaimErr != nil {
+		return m.ClaimErr
+	}
+	if m.Claims[id] == nil {
+		m.Claims[id] = make(map[int]bool)
+	}
+	if m.Claims[id][occurrence] {
+		return schedule.ErrOccurrenceAlreadyClaimed
+	}
+	m.Claims[id][occurrence] = true
+	return nil
+}
+
+// Record appends the outcome of an occurrence
+func (m *MockScheduleRepository) Record(run *schedule.Run) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.RunsByID[run.ScheduleID] = append(m.RunsByID[run.ScheduleID], run)
+	return nil
+}
+
+// Runs returns the outcomes of a schedule's occurrences
+func (m *MockScheduleRepository) Runs(id string) ([]*schedule.Run, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*schedule.Run(nil), m.RunsByID[id]...), nil
+}
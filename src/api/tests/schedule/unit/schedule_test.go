@@ -0,0 +1,68 @@
+package unit
+
+import (
+	"github.com/quabynah-bilson/quantia/pkg/schedule"
+	"testing"
+	"time"
+)
+
+// TestScheduledPayment_OccurrenceAt tests the computation of occurrence dates.
+func TestScheduledPayment_OccurrenceAt(t *testing.T) {
+	start := time.Date(2026, time.January, 31, 9, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name      string
+		frequency schedule.Frequency
+		n         int
+		expected  time.Time
+	}{
+		{name: "daily", frequency: schedule.FrequencyDaily, n: 3, expected: time.Date(2026, time.February, 3, 9, 0, 0, 0, time.UTC)},
+		{name: "weekly", frequency: schedule.FrequencyWeekly, n: 2, expected: time.Date(2026, time.February, 14, 9, 0, 0, 0, time.UTC)},
+		{name: "monthly in a short month", frequency: schedule.FrequencyMonthly, n: 1, expected: time.Date(2026, time.February, 28, 9, 0, 0, 0, time.UTC)},
+		{name: "monthly after a short month", frequency: schedule.FrequencyMonthly, n: 2, expected: time.Date(2026, time.March, 31, 9, 0, 0, 0, time.UTC)},
+		{name: "monthly across a year", frequency: schedule.FrequencyMonthly, n: 13, expected: time.Date(2027, time.February, 28, 9, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := schedule.NewScheduledPayment("acc_1", 10, "https://quantia-webhooks.com", "", tc.frequency, start, nil, 0)
+			if got := s.OccurrenceAt(tc.n); !got.Equal(tc.expected) {
+				t.Errorf("expected: %v, got: %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+// TestScheduledPayment_Advance tests that schedules complete at their end date or count.
+func TestScheduledPayment_Advance(t *testing.T) {
+	start := time.Date(2026, time.January, 1, 9, 0, 0, 0, time.UTC)
+	endAt := start.AddDate(0, 0, 2)
+
+	testCases := []struct {
+		name        string
+		frequency   schedule.Frequency
+		endAt       *time.Time
+		count       int
+		occurrences int
+	}{
+		{name: "once", frequency: schedule.FrequencyOnce, occurrences: 1},
+		{name: "count", frequency: schedule.FrequencyWeekly, count: 4, occurrences: 4},
+		{name: "end date", frequency: schedule.FrequencyDaily, endAt: &endAt, occurrences: 3},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := schedule.NewScheduledPayment("acc_1", 10, "https://quantia-webhooks.com", "", tc.frequency, start, tc.endAt, tc.count)
+
+			occurrences := 0
+			for s.Status == schedule.StatusActive && occurrences < 100 {
+				s.Advance(s.NextOccurrence)
+				occurrences++
+			}
+
+			if occurrences != tc.occurrences || s.Status != schedule.StatusCompleted {
+				t.Errorf("expected %d occurrences and completion, got: %d %s", tc.occurrences, occurrences, s.Status)
+			}
+		})
+	}
+}
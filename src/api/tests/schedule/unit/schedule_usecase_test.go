@@ -0,0 +1,272 @@
+package unit
+
+import (
+	"errors"
+	"github.com/quabynah-bilson/quantia/adapters/payment/provider"
+	"github.com/quabynah-bilson/quantia/pkg"
+	"github.com/quabynah-bilson/quantia/pkg/event"
+	"github.com/quabynah-bilson/quantia/pkg/schedule"
+	ledgerMocks "github.com/quabynah-bilson/quantia/tests/ledger/mocks"
+	paymentMocks "github.com/quabynah-bilson/quantia/tests/payment/mocks"
+	"github.com/quabynah-bilson/quantia/tests/schedule/mocks"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newScheduleUseCase creates a schedule use case whose payments are made by a simulator with the given behaviour.
+func newScheduleUseCase(scheduleRepo *mocks.MockScheduleRepository, behaviour provider.Behaviour) (*pkg.ScheduleUseCase, *paymentMocks.MockPaymentRepository) {
+	paymentRepo := paymentMocks.NewMockPaymentRepository()
+	paymentUseCase := pkg.NewPaymentUseCase(paymentRepo, ledgerMocks.NewMockLedgerRepository(), &paymentMocks.MockURLGuard{}, provider.NewSimulator(provider.SimulatorConfig{Behaviour: behaviour}))
+	return pkg.NewScheduleUseCase(scheduleRepo, paymentRepo, paymentUseCase), paymentRepo
+}
+
+// runStatuses returns the statuses of a schedule's runs, in order.
+func runStatuses(t *testing.T, scheduleUseCase *pkg.ScheduleUseCase, id string) []schedule.RunStatus {
+	t.Helper()
+
+	runs, err := scheduleUseCase.GetRuns(id)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var statuses []schedule.RunStatus
+	for _, run := range runs {
+		statuses = append(statuses, run.Status)
+	}
+
+	return statuses
+}
+
+// TestScheduleUseCase_CreateSchedule tests the validation of schedule definitions.
+func TestScheduleUseCase_CreateSchedule(t *testing.T) {
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+
+	testCases := []struct {
+		name        string
+		accountID   string
+		frequency   schedule.Frequency
+		startAt     time.Time
+		endAt       *time.Time
+		count       int
+		expectedErr error
+	}{
+		{name: "monthly", accountID: "acc_1", frequency: schedule.FrequencyMonthly, startAt: future, count: 12},
+		{name: "unknown frequency", accountID: "acc_1", frequency: "hourly", startAt: future, expectedErr: pkg.ErrInvalidSchedule},
+		{name: "start in the past", accountID: "acc_1", frequency: schedule.FrequencyOnce, startAt: past, expectedErr: pkg.ErrInvalidSchedule},
+		{name: "end before start", accountID: "acc_1", frequency: schedule.FrequencyDaily, startAt: future, endAt: &past, expectedErr: pkg.ErrInvalidSchedule},
+		{name: "negative count", accountID: "acc_1", frequency: schedule.FrequencyDaily, startAt: future, count: -1, expectedErr: pkg.ErrInvalidSchedule},
+		{name: "no payer account", frequency: schedule.FrequencyDaily, startAt: future, expectedErr: pkg.ErrInvalidAccount},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			scheduleUseCase, _ := newScheduleUseCase(mocks.NewMockScheduleRepository(), provider.BehaviourSucceed)
+
+			// Act
+			_, err := scheduleUseCase.CreateSchedule(tc.accountID, 50, "https://quantia-webhooks.com", "", tc.frequency, tc.startAt, tc.endAt, tc.count)
+
+			// Assert
+			if !errors.Is(err, tc.expectedErr) {
+				t.Errorf("expected error: %v, got: %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+// TestScheduleUseCase_RunDue tests that due occurrences are paid and the schedule moves on.
+func TestScheduleUseCase_RunDue(t *testing.T) {
+	// Arrange
+	scheduleRepo := mocks.NewMockScheduleRepository()
+	scheduleUseCase, paymentRepo := newScheduleUseCase(scheduleRepo, provider.BehaviourSucceed)
+	start := time.Now().Add(time.Hour)
+	s, _ := scheduleUseCase.CreateSchedule("acc_1", 50, "https://quantia-webhooks.com", "", schedule.FrequencyDaily, start, nil, 2)
+
+	// Act
+	notYet := scheduleUseCase.RunDue(start.Add(-time.Minute))
+	first := scheduleUseCase.RunDue(start.Add(time.Minute))
+	second := scheduleUseCase.RunDue(start.AddDate(0, 0, 1).Add(time.Minute))
+
+	// Assert
+	if notYet != 0 || first != 1 || second != 1 {
+		t.Errorf("expected runs 0, 1, 1, got: %d, %d, %d", notYet, first, second)
+	}
+
+	if statuses := runStatuses(t, scheduleUseCase, s.ID); !reflect.DeepEqual(statuses, []schedule.RunStatus{schedule.RunStatusExecuted, schedule.RunStatusExecuted}) {
+		t.Errorf("expected two executed runs, got: %v", statuses)
+	}
+
+	if stored, _ := scheduleUseCase.GetSchedule(s.ID); stored.Status != schedule.StatusCompleted {
+		t.Errorf("expected status: %s, got: %s", schedule.StatusCompleted, stored.Status)
+	}
+
+	if len(paymentRepo.Transactions) != 2 {
+		t.Errorf("expected 2 payments, got: %d", len(paymentRepo.Transactions))
+	}
+}
+
+// TestScheduleUseCase_RunDueOnce tests that concurrent schedulers sharing a store run each occurrence once.
+func TestScheduleUseCase_RunDueOnce(t *testing.T) {
+	// Arrange
+	scheduleRepo := mocks.NewMockScheduleRepository()
+	start := time.Now().Add(time.Hour)
+	creator, _ := newScheduleUseCase(scheduleRepo, provider.BehaviourSucceed)
+	s, _ := creator.CreateSchedule("acc_1", 50, "https://quantia-webhooks.com", "", schedule.FrequencyMonthly, start, nil, 0)
+
+	// Act
+	var (
+		wg           sync.WaitGroup
+		mu           sync.Mutex
+		transactions int
+	)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			scheduler, paymentRepo := newScheduleUseCase(scheduleRepo, provider.BehaviourSucceed)
+			scheduler.RunDue(start.Add(time.Minute))
+
+			mu.Lock()
+			transactions += len(paymentRepo.Transactions)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	// Assert
+	if transactions != 1 {
+		t.Errorf("expected 1 payment, got: %d", transactions)
+	}
+
+	if stored, _ := creator.GetSchedule(s.ID); stored.NextOccurrence != 1 || stored.Status != schedule.StatusActive {
+		t.Errorf("expected the schedule to wait for occurrence 1, got: %d %s", stored.NextOccurrence, stored.Status)
+	}
+}
+
+// TestScheduleUseCase_RunDue_ClaimError tests that an occurrence that could not be claimed is not skipped, but
+// run at the next tick.
+func TestScheduleUseCase_RunDue_ClaimError(t *testing.T) {
+	// Arrange
+	scheduleRepo := mocks.NewMockScheduleRepository()
+	scheduleUseCase, paymentRepo := newScheduleUseCase(scheduleRepo, provider.BehaviourSucceed)
+	start := time.Now().Add(time.Hour)
+	s, _ := scheduleUseCase.CreateSchedule("acc_1", 50, "https://quantia-webhooks.com", "", schedule.FrequencyDaily, start, nil, 0)
+
+	// Act
+	scheduleRepo.ClaimErr = errors.New("connection refused")
+	scheduleUseCase.RunDue(start.Add(time.Minute))
+	unclaimed, _ := scheduleUseCase.GetSchedule(s.ID)
+
+	scheduleRepo.ClaimErr = nil
+	scheduleUseCase.RunDue(start.Add(2 * time.Minute))
+
+	// Assert
+	if unclaimed.NextOccurrence != 0 {
+		t.Errorf("expected the schedule to wait for occurrence 0, got: %d", unclaimed.NextOccurrence)
+	}
+
+	if statuses := runStatuses(t, scheduleUseCase, s.ID); !reflect.DeepEqual(statuses, []schedule.RunStatus{schedule.RunStatusExecuted}) {
+		t.Errorf("expected one executed run, got: %v", statuses)
+	}
+
+	if len(paymentRepo.Transactions) != 1 {
+		t.Errorf("expected 1 payment, got: %d", len(paymentRepo.Transactions))
+	}
+}
+
+// TestScheduleUseCase_Failures tests that declined and missed occurrences notify the merchant.
+func TestScheduleUseCase_Failures(t *testing.T) {
+	testCases := []struct {
+		name           string
+		behaviour      provider.Behaviour
+		lateBy         time.Duration
+		expectedStatus schedule.RunStatus
+		expectedEvents []event.Type
+	}{
+		{
+			name:           "declined",
+			behaviour:      provider.BehaviourDecline,
+			lateBy:         time.Minute,
+			expectedStatus: schedule.RunStatusFailed,
+			expectedEvents: []event.Type{event.TypePaymentFailed, event.TypeScheduledPaymentFailed},
+		},
+		{
+			name:           "missed",
+			behaviour:      provider.BehaviourSucceed,
+			lateBy:         48 * time.Hour,
+			expectedStatus: schedule.RunStatusMissed,
+			expectedEvents: []event.Type{event.TypeScheduledPaymentFailed},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			scheduleUseCase, paymentRepo := newScheduleUseCase(mocks.NewMockScheduleRepository(), tc.behaviour)
+			start := time.Now().Add(time.Hour)
+			s, _ := scheduleUseCase.CreateSchedule("acc_1", 50, "https://quantia-webhooks.com", "", schedule.FrequencyOnce, start, nil, 0)
+
+			// Act
+			scheduleUseCase.RunDue(start.Add(tc.lateBy))
+
+			// Assert
+			if statuses := runStatuses(t, scheduleUseCase, s.ID); !reflect.DeepEqual(statuses, []schedule.RunStatus{tc.expectedStatus}) {
+				t.Errorf("expected runs: %v, got: %v", []schedule.RunStatus{tc.expectedStatus}, statuses)
+			}
+
+			if events := paymentRepo.EventTypes(); !reflect.DeepEqual(events, tc.expectedEvents) {
+				t.Errorf("expected events: %v, got: %v", tc.expectedEvents, events)
+			}
+		})
+	}
+}
+
+// TestScheduleUseCase_Controls tests skipping, pausing, resuming and cancelling schedules.
+func TestScheduleUseCase_Controls(t *testing.T) {
+	// Arrange
+	scheduleUseCase, paymentRepo := newScheduleUseCase(mocks.NewMockScheduleRepository(), provider.BehaviourSucceed)
+	start := time.Now().Add(time.Hour)
+	s, _ := scheduleUseCase.CreateSchedule("acc_1", 50, "https://quantia-webhooks.com", "", schedule.FrequencyWeekly, start, nil, 0)
+
+	// Act & Assert
+	skipped, err := scheduleUseCase.Skip(s.ID)
+	if err != nil || skipped.NextOccurrence != 1 {
+		t.Fatalf("expected the next occurrence to be 1, got: %v %v", skipped, err)
+	}
+	if ran := scheduleUseCase.RunDue(start.Add(time.Minute)); ran != 0 {
+		t.Errorf("expected the skipped occurrence not to run, got: %d", ran)
+	}
+
+	if _, err := scheduleUseCase.Pause(s.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ran := scheduleUseCase.RunDue(start.AddDate(0, 0, 7).Add(time.Minute)); ran != 0 {
+		t.Errorf("expected a paused schedule not to run, got: %d", ran)
+	}
+	if _, err := scheduleUseCase.Pause(s.ID); !errors.Is(err, pkg.ErrInvalidScheduleState) {
+		t.Errorf("expected error: %v, got: %v", pkg.ErrInvalidScheduleState, err)
+	}
+
+	resumed, err := scheduleUseCase.Resume(s.ID)
+	if err != nil || resumed.Status != schedule.StatusActive || resumed.NextRunAt.Before(time.Now()) {
+		t.Errorf("expected an active schedule in the future, got: %v %v", resumed, err)
+	}
+
+	cancelled, err := scheduleUseCase.Cancel(s.ID)
+	if err != nil || cancelled.Status != schedule.StatusCancelled {
+		t.Errorf("expected status: %s, got: %v %v", schedule.StatusCancelled, cancelled, err)
+	}
+	if _, err := scheduleUseCase.Resume(s.ID); !errors.Is(err, pkg.ErrInvalidScheduleState) {
+		t.Errorf("expected error: %v, got: %v", pkg.ErrInvalidScheduleState, err)
+	}
+
+	if statuses := runStatuses(t, scheduleUseCase, s.ID); !reflect.DeepEqual(statuses, []schedule.RunStatus{schedule.RunStatusSkipped}) {
+		t.Errorf("expected one skipped run, got: %v", statuses)
+	}
+	if len(paymentRepo.Transactions) != 0 {
+		t.Errorf("expected no payments, got: %d", len(paymentRepo.Transactions))
+	}
+}
@@ -0,0 +1,79 @@
+package mocks
+
+import (
+	"github.com/quabynah-bilson/quantia/pkg/screening"
+	"sort"
+	"sync"
+)
+
+// MockScreeningRepository is an in-memory screening repository
+type MockScreeningRepository struct {
+	mu         sync.Mutex
+	Screenings map[string]*screening.Screening
+	parties    map[string]string
+}
+
+// NewMockScreeningRepository creates an empty in-memory screening repository
+func NewMockScreeningRepository() *MockScreeningRepository {
+	return &MockScreeningRepository{
+		Screenings: make(map[string]*screening.Screening),
+		parties:    make(map[string]string),
+	}
+}
+
+// Save saves a copy of the screening as the latest of its party
+func (m *MockScreeningRepository) Save(s *screening.Screening) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	copied := *s
+	m.Screenings[s.ID] = &copied
+	m.parties[string(s.PartyType)+":"+s.PartyID] = s.ID
+	return nil
+}
+
+// Find returns a copy of the screening
+func (m *MockScreeningRepository) Find(id string) (*screening.Screening, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.find(id)
+}
+
+// FindByParty returns a copy of the latest screening of the party
+func (m *MockScreeningRepository) FindByParty(partyType screening.PartyType, partyID string) (*screening.Screening, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	id, ok := m.parties[string(partyType)+":"+partyID]
+	if !ok {
+		return nil, screening.ErrScreeningNotFound
+	}
+	return m.find(id)
+}
+
+// ReviewQueue returns the screenings awaiting review, oldest first
+func (m *MockScreeningRepository) ReviewQueue() ([]*screening.Screening, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var queue []*screening.Screening
+	for _, s := range m.Screenings {
+		if s.IsAwaitingReview() {
+			copied := *s
+			queue = append(queue, &copied)
+		}
+	}
+	sort.Slice(queue, func(i, j int) bool { return queue[i].CreatedAt.Before(queue[j].CreatedAt) })
+
+	return queue, nil
+}
+
+// find returns a copy of the screening
+func (m *MockScreeningRepository) find(id string) (*screening.Screening, error) {
+	s, ok := m.Screenings[id]
+	if !ok {
+		return nil, screening.ErrScreeningNotFound
+	}
+
+	copied := *s
+	return &copied, nil
+}
@@ -0,0 +1,123 @@
+package unit
+
+import (
+	"errors"
+	"github.com/quabynah-bilson/quantia/adapters/screening/lists"
+	"github.com/quabynah-bilson/quantia/pkg/screening"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const (
+	// csvList is a list in the CSV layout
+	csvList = `id,name,aliases,type,program
+1001,Viktor Bout,Victor Butt; Vadim Aminov,Individual,SDGT
+1002,Tidewater Middle East Co.,,Entity,
+`
+
+	// sdnList is an excerpt of the OFAC SDN list layout
+	sdnList = `<?xml version="1.0" standalone="yes"?>
+<sdnList xmlns="https://sanctionslistservice.ofac.treas.gov/api/PublicationPreview/exports/XML">
+  <sdnEntry>
+    <uid>7427</uid>
+    <firstName>Viktor</firstName>
+    <lastName>BOUT</lastName>
+    <sdnType>Individual</sdnType>
+    <programList><program>SDGT</program></programList>
+    <akaList>
+      <aka><uid>7428</uid><firstName>Victor</firstName><lastName>BUTT</lastName></aka>
+    </akaList>
+  </sdnEntry>
+</sdnList>`
+
+	// unList is an excerpt of the UN Security Council consolidated list layout
+	unList = `<?xml version="1.0" encoding="UTF-8"?>
+<CONSOLIDATED_LIST>
+  <INDIVIDUALS>
+    <INDIVIDUAL>
+      <DATAID>6908555</DATAID>
+      <FIRST_NAME>AYMAN</FIRST_NAME>
+      <SECOND_NAME>MUHAMMED RABI</SECOND_NAME>
+      <THIRD_NAME>AL-ZAWAHIRI</THIRD_NAME>
+      <UN_LIST_TYPE>Al-Qaida</UN_LIST_TYPE>
+      <REFERENCE_NUMBER>QDi.006</REFERENCE_NUMBER>
+      <INDIVIDUAL_ALIAS><ALIAS_NAME>Abu Muhammad</ALIAS_NAME></INDIVIDUAL_ALIAS>
+    </INDIVIDUAL>
+  </INDIVIDUALS>
+  <ENTITIES>
+    <ENTITY>
+      <DATAID>110395</DATAID>
+      <FIRST_NAME>AL-RASHID TRUST</FIRST_NAME>
+      <UN_LIST_TYPE>Al-Qaida</UN_LIST_TYPE>
+      <REFERENCE_NUMBER>QDe.005</REFERENCE_NUMBER>
+      <ENTITY_ALIAS><ALIAS_NAME>Al Rasheed Trust</ALIAS_NAME></ENTITY_ALIAS>
+    </ENTITY>
+  </ENTITIES>
+</CONSOLIDATED_LIST>`
+)
+
+// TestLoad tests the reading of list files in the supported layouts.
+func TestLoad(t *testing.T) {
+	testCases := []struct {
+		name            string
+		file            string
+		content         string
+		listName        string
+		expectedEntries []screening.Entry
+		expectedErr     error
+	}{
+		{
+			name:    "CSV named after its file",
+			file:    "internal.csv",
+			content: csvList,
+			expectedEntries: []screening.Entry{
+				{ID: "1001", List: "internal", Name: "Viktor Bout", Aliases: []string{"Victor Butt", "Vadim Aminov"}, Type: "Individual", Program: "SDGT"},
+				{ID: "1002", List: "internal", Name: "Tidewater Middle East Co.", Type: "Entity"},
+			},
+		},
+		{
+			name:     "OFAC SDN XML",
+			file:     "sdn.xml",
+			content:  sdnList,
+			listName: "ofac",
+			expectedEntries: []screening.Entry{
+				{ID: "7427", List: "ofac", Name: "Viktor BOUT", Aliases: []string{"Victor BUTT"}, Type: "Individual", Program: "SDGT"},
+			},
+		},
+		{
+			name:     "UN consolidated XML",
+			file:     "consolidated.xml",
+			content:  unList,
+			listName: "un",
+			expectedEntries: []screening.Entry{
+				{ID: "QDi.006", List: "un", Name: "AYMAN MUHAMMED RABI AL-ZAWAHIRI", Aliases: []string{"Abu Muhammad"}, Type: "Individual", Program: "Al-Qaida"},
+				{ID: "QDe.005", List: "un", Name: "AL-RASHID TRUST", Aliases: []string{"Al Rasheed Trust"}, Type: "Entity", Program: "Al-Qaida"},
+			},
+		},
+		{name: "unsupported format", file: "list.json", content: "[]", expectedErr: lists.ErrUnsupportedFormat},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			path := filepath.Join(t.TempDir(), tc.file)
+			if err := os.WriteFile(path, []byte(tc.content), 0o600); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// Act
+			entries, err := lists.Load(path, tc.listName)
+
+			// Assert
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error: %v, got: %v", tc.expectedErr, err)
+			}
+
+			if !reflect.DeepEqual(entries, tc.expectedEntries) {
+				t.Errorf("expected entries: %+v, got: %+v", tc.expectedEntries, entries)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package unit
+
+import (
+	"github.com/quabynah-bilson/quantia/pkg/screening"
+	"testing"
+)
+
+// TestNameScore tests the similarity of names that differ in spelling, order and punctuation.
+func TestNameScore(t *testing.T) {
+	testCases := []struct {
+		name     string
+		a, b     string
+		minScore float64
+		maxScore float64
+	}{
+		{name: "same name", a: "Viktor Bout", b: "Viktor Bout", minScore: 1, maxScore: 1},
+		{name: "case and punctuation", a: "VIKTOR  BOUT.", b: "viktor bout", minScore: 1, maxScore: 1},
+		{name: "reversed order", a: "Bout, Viktor", b: "Viktor Bout", minScore: 1, maxScore: 1},
+		{name: "transliteration", a: "Victor Bout", b: "Viktor Bout", minScore: 0.9, maxScore: 0.99},
+		{name: "middle name left out", a: "Viktor Bout", b: "Viktor Anatolyevich Bout", minScore: 0.95, maxScore: 1},
+		{name: "different person", a: "Ama Owusu", b: "Viktor Bout", maxScore: 0.6},
+		{name: "single word against a full name", a: "Bout", b: "Viktor Bout", maxScore: screening.DefaultThreshold - 0.01},
+		{name: "empty name", a: "", b: "Viktor Bout", maxScore: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			score := screening.NameScore(tc.a, tc.b)
+
+			// Assert
+			if score < tc.minScore || score > tc.maxScore {
+				t.Errorf("expected a score between %.2f and %.2f, got: %.3f", tc.minScore, tc.maxScore, score)
+			}
+		})
+	}
+}
+
+// TestWatchlist_Screen tests that hits are found on names and aliases with per-list thresholds.
+func TestWatchlist_Screen(t *testing.T) {
+	ofac := []screening.Entry{
+		{ID: "7427", List: "ofac", Name: "Viktor Bout", Aliases: []string{"Victor Butt", "Vadim Aminov"}, Program: "SDGT"},
+		{ID: "9640", List: "ofac", Name: "Tidewater Middle East Co."},
+	}
+	un := []screening.Entry{{ID: "QDi.001", List: "un", Name: "Ayman al-Zawahiri"}}
+
+	testCases := []struct {
+		name            string
+		config          screening.Config
+		screened        string
+		expectedEntries []string
+	}{
+		{name: "no match", screened: "Ama Owusu"},
+		{name: "name", screened: "Victor Bout", expectedEntries: []string{"7427"}},
+		{name: "alias", screened: "Aminov Vadim", expectedEntries: []string{"7427"}},
+		{name: "organisation", screened: "Tidewater Middle East Company", expectedEntries: []string{"9640"}},
+		{name: "spelling", screened: "Aiman Zawahri", expectedEntries: []string{"QDi.001"}},
+		{name: "spelling below a stricter list threshold", config: screening.Config{ListThresholds: map[string]float64{"un": 0.95}}, screened: "Aiman Zawahri"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			watchlist := screening.NewWatchlist(tc.config, ofac, un)
+
+			// Act
+			hits := watchlist.Screen(tc.screened)
+
+			// Assert
+			var entries []string
+			for _, hit := range hits {
+				entries = append(entries, hit.EntryID)
+			}
+			if len(entries) != len(tc.expectedEntries) || (len(entries) > 0 && entries[0] != tc.expectedEntries[0]) {
+				t.Errorf("expected hits: %v, got: %+v", tc.expectedEntries, hits)
+			}
+		})
+	}
+}
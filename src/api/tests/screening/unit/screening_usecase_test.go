@@ -0,0 +1,100 @@
+package unit
+
+import (
+	"errors"
+	"github.com/quabynah-bilson/quantia/pkg"
+	"github.com/quabynah-bilson/quantia/pkg/beneficiary"
+	"github.com/quabynah-bilson/quantia/pkg/screening"
+	"github.com/quabynah-bilson/quantia/tests/screening/mocks"
+	"testing"
+)
+
+// newScreeningUseCase creates a screening use case whose list holds a single individual.
+func newScreeningUseCase() *pkg.ScreeningUseCase {
+	watchlist := screening.NewWatchlist(screening.Config{}, []screening.Entry{{ID: "7427", List: "ofac", Name: "Viktor Bout"}})
+	return pkg.NewScreeningUseCase(mocks.NewMockScreeningRepository(), watchlist)
+}
+
+// TestScreeningUseCase_Screen tests that parties with hits are held until an analyst reviews them.
+func TestScreeningUseCase_Screen(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		screenedName         string
+		confirm              *bool
+		expectedStatus       screening.Status
+		expectedAuthorizeErr error
+	}{
+		{name: "no hits", screenedName: "Ama Owusu", expectedStatus: screening.StatusClear},
+		{name: "hits awaiting review", screenedName: "Victor Bout", expectedStatus: screening.StatusPendingReview, expectedAuthorizeErr: screening.ErrScreeningPending},
+		{name: "hits dismissed", screenedName: "Victor Bout", confirm: new(bool), expectedStatus: screening.StatusDismissed},
+		{
+			name:                 "match confirmed",
+			screenedName:         "Victor Bout",
+			confirm:              func() *bool { confirm := true; return &confirm }(),
+			expectedStatus:       screening.StatusConfirmed,
+			expectedAuthorizeErr: screening.ErrSanctionsMatch,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			screeningUseCase := newScreeningUseCase()
+			s, err := screeningUseCase.ScreenAccount("acc_1", tc.screenedName)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// Act
+			if tc.confirm != nil {
+				if _, err = screeningUseCase.Review(s.ID, *tc.confirm, "compliance@quantia.com", "checked the date of birth"); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+			authorizeErr := screeningUseCase.Authorize(screening.PartyAccount, "acc_1")
+
+			// Assert
+			stored, _ := screeningUseCase.GetPartyScreening(screening.PartyAccount, "acc_1")
+			if stored.Status != tc.expectedStatus {
+				t.Errorf("expected status: %s, got: %s", tc.expectedStatus, stored.Status)
+			}
+
+			if !errors.Is(authorizeErr, tc.expectedAuthorizeErr) {
+				t.Errorf("expected error: %v, got: %v", tc.expectedAuthorizeErr, authorizeErr)
+			}
+
+			queue, _ := screeningUseCase.GetReviewQueue()
+			if awaiting := tc.expectedStatus == screening.StatusPendingReview; awaiting != (len(queue) == 1) {
+				t.Errorf("expected the screening in the review queue: %v, got: %v", awaiting, queue)
+			}
+		})
+	}
+}
+
+// TestScreeningUseCase_Review tests the reviews that cannot be recorded.
+func TestScreeningUseCase_Review(t *testing.T) {
+	// Arrange
+	screeningUseCase := newScreeningUseCase()
+	clear, _ := screeningUseCase.ScreenBeneficiary(&beneficiary.Beneficiary{ID: "ben_1", AccountID: "acc_1", Name: "Ama Owusu"})
+	held, _ := screeningUseCase.ScreenBeneficiary(&beneficiary.Beneficiary{ID: "ben_2", AccountID: "acc_1", Name: "Viktor Bout"})
+
+	// Act
+	_, clearErr := screeningUseCase.Review(clear.ID, false, "compliance@quantia.com", "")
+	_, reviewerErr := screeningUseCase.Review(held.ID, false, "", "")
+	_, missingErr := screeningUseCase.Review("scr_missing", false, "compliance@quantia.com", "")
+
+	// Assert
+	if !errors.Is(clearErr, pkg.ErrScreeningNotAwaitingReview) {
+		t.Errorf("expected error: %v, got: %v", pkg.ErrScreeningNotAwaitingReview, clearErr)
+	}
+	if !errors.Is(reviewerErr, pkg.ErrInvalidReview) {
+		t.Errorf("expected error: %v, got: %v", pkg.ErrInvalidReview, reviewerErr)
+	}
+	if !errors.Is(missingErr, screening.ErrScreeningNotFound) {
+		t.Errorf("expected error: %v, got: %v", screening.ErrScreeningNotFound, missingErr)
+	}
+
+	if held.AccountID != "acc_1" || held.PartyType != screening.PartyBeneficiary || len(held.Hits) != 1 || held.Hits[0].EntryID != "7427" {
+		t.Errorf("expected a hit on entry 7427 for acc_1's beneficiary, got: %+v", held)
+	}
+}
@@ -0,0 +1,80 @@
+package mocks
+
+import (
+	"github.com/quabynah-bilson/quantia/pkg/statement"
+	"sort"
+	"sync"
+)
+
+// MockStatementRepository is an in-memory statement repository
+type MockStatementRepository struct {
+	mu         sync.Mutex
+	Statements map[string]*statement.Statement
+}
+
+// NewMockStatementRepository creates an empty in-memory statement repository
+func NewMockStatementRepository() *MockStatementRepository {
+	return &MockStatementRepository{Statements: make(map[string]*statement.Statement)}
+}
+
+// Save stores a copy of a statement, one per account and period
+func (m *MockStatementRepository) Save(s *statement.Statement) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, stored := range m.Statements {
+		if stored.AccountID == s.AccountID && stored.Period == s.Period {
+			return statement.ErrStatementAlreadyGenerated
+		}
+	}
+
+	copied := *s
+	m.Statements[s.ID] = &copied
+	return nil
+}
+
+// Find returns a copy of a statement
+func (m *MockStatementRepository) Find(id string) (*statement.Statement, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, ok := m.Statements[id]
+	if !ok {
+		return nil, statement.ErrStatementNotFound
+	}
+
+	copied := *s
+	return &copied, nil
+}
+
+// FindByPeriod returns a copy of the statement of an account for a period
+func (m *MockStatementRepository) FindByPeriod(accountID, period string) (*statement.Statement, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, s := range m.Statements {
+		if s.AccountID == accountID && s.Period == period {
+			copied := *s
+			return &copied, nil
+		}
+	}
+
+	return nil, statement.ErrStatementNotFound
+}
+
+// FindByAccount returns copies of the statements of an account, oldest period first
+func (m *MockStatementRepository) FindByAccount(accountID string) ([]*statement.Statement, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	statements := []*statement.Statement{}
+	for _, s := range m.Statements {
+		if s.AccountID == accountID {
+			copied := *s
+			statements = append(statements, &copied)
+		}
+	}
+	sort.Slice(statements, func(i, j int) bool { return statements[i].From.Before(statements[j].From) })
+
+	return statements, nil
+}
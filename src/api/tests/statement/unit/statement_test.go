@@ -0,0 +1,262 @@
+package unit
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/quabynah-bilson/quantia/pkg/ledger"
+	"github.com/quabynah-bilson/quantia/pkg/statement"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// october is the period of the statements of the tests
+var october = time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+// entry creates an entry of acc_1 posted on a day of October at noon
+func entry(reference string, entryType ledger.EntryType, amount float32, day int) *ledger.Entry {
+	return &ledger.Entry{
+		ID:          "ent_" + reference,
+		AccountID:   "acc_1",
+		Reference:   reference,
+		Type:        entryType,
+		Amount:      amount,
+		Description: "payment " + reference,
+		CreatedAt:   october.AddDate(0, 0, day-1).Add(12 * time.Hour),
+	}
+}
+
+// entries are the entries of acc_1: one in September, three in October and one in November
+func entries() []*ledger.Entry {
+	return []*ledger.Entry{
+		entry("tx_0", ledger.EntryTypeCredit, 100, 0),
+		entry("tx_1", ledger.EntryTypeCredit, 0.1, 2),
+		entry("trf_1", ledger.EntryTypeDebit, 40.2, 15),
+		entry("tx_2", ledger.EntryTypeCredit, 0.2, 31),
+		entry("tx_3", ledger.EntryTypeCredit, 500, 32),
+	}
+}
+
+// TestNew tests that a statement opens with the entries before its period and lists those within it.
+func TestNew(t *testing.T) {
+	// Act
+	s := statement.New("acc_1", "GHS", october, october.AddDate(0, 1, 0), entries())
+
+	// Assert
+	if s.OpeningBalance != 100 || s.Credits != 0.3 || s.Debits != 40.2 || s.ClosingBalance != 60.1 {
+		t.Errorf("unexpected balances: %+v", s)
+	}
+
+	expected := []struct {
+		reference string
+		amount    float32
+		balance   float32
+	}{
+		{"tx_1", 0.1, 100.1},
+		{"trf_1", -40.2, 59.9},
+		{"tx_2", 0.2, 60.1},
+	}
+	if len(s.Lines) != len(expected) {
+		t.Fatalf("expected %d lines, got: %+v", len(expected), s.Lines)
+	}
+	for i, line := range s.Lines {
+		if line.Reference != expected[i].reference || line.Amount != expected[i].amount || line.Balance != expected[i].balance {
+			t.Errorf("line %d: expected %+v, got: %+v", i+1, expected[i], line)
+		}
+	}
+
+	if s.FileName(statement.FormatPDF) != "statement-2026-10-01-2026-10-31.pdf" {
+		t.Errorf("unexpected file name: %s", s.FileName(statement.FormatPDF))
+	}
+}
+
+// TestParsePeriod tests the bounds of monthly periods and the period before a date.
+func TestParsePeriod(t *testing.T) {
+	// Act
+	from, to, err := statement.ParsePeriod("2026-12")
+	_, _, invalidErr := statement.ParsePeriod("12/2026")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !from.Equal(time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)) || !to.Equal(time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected period: %v to %v", from, to)
+	}
+
+	if invalidErr == nil {
+		t.Error("expected an error for a period that cannot be read")
+	}
+
+	if period := statement.PreviousPeriod(time.Date(2027, 1, 1, 0, 30, 0, 0, time.UTC)); period != "2026-12" {
+		t.Errorf("expected period: 2026-12, got: %s", period)
+	}
+}
+
+// TestParseFormat tests that only the formats statements can be written in are accepted.
+func TestParseFormat(t *testing.T) {
+	testCases := []struct {
+		name        string
+		expected    statement.Format
+		expectedErr error
+	}{
+		{name: "csv", expected: statement.FormatCSV},
+		{name: " OFX ", expected: statement.FormatOFX},
+		{name: "Pdf", expected: statement.FormatPDF},
+		{name: "xlsx", expectedErr: statement.ErrUnsupportedFormat},
+		{name: "", expectedErr: statement.ErrUnsupportedFormat},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			format, err := statement.ParseFormat(tc.name)
+
+			// Assert
+			if !errors.Is(err, tc.expectedErr) || format != tc.expected {
+				t.Errorf("expected %q (%v), got: %q (%v)", tc.expected, tc.expectedErr, format, err)
+			}
+		})
+	}
+}
+
+// TestWriteCSV tests that the entries are written between the opening and closing balances.
+func TestWriteCSV(t *testing.T) {
+	// Arrange
+	s := statement.New("acc_1", "GHS", october, october.AddDate(0, 1, 0), entries())
+	var buf bytes.Buffer
+
+	// Act
+	err := statement.WriteCSV(&buf, s)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `date,reference,description,debit,credit,balance
+2026-10-01,,Opening balance,,,100.00
+2026-10-02T12:00:00Z,tx_1,payment tx_1,,0.10,100.10
+2026-10-15T12:00:00Z,trf_1,payment trf_1,40.20,,59.90
+2026-10-31T12:00:00Z,tx_2,payment tx_2,,0.20,60.10
+2026-10-31,,Closing balance,40.20,0.30,60.10
+`
+	if buf.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
+
+// TestWriteOFX tests that statements are written as OFX 2.2 bank statements.
+func TestWriteOFX(t *testing.T) {
+	// Arrange
+	s := statement.New("acc_1", "GHS", october, october.AddDate(0, 1, 0), entries())
+	var buf bytes.Buffer
+
+	// Act
+	err := statement.WriteOFX(&buf, s, "Quantia")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(buf.String(), `<?OFX OFXHEADER="200" VERSION="220"`) {
+		t.Errorf("expected the OFX 2.2 header, got:\n%s", buf.String())
+	}
+
+	var doc struct {
+		Statement struct {
+			Currency     string `xml:"CURDEF"`
+			AccountID    string `xml:"BANKACCTFROM>ACCTID"`
+			Transactions []struct {
+				Type   string `xml:"TRNTYPE"`
+				Posted string `xml:"DTPOSTED"`
+				Amount string `xml:"TRNAMT"`
+				ID     string `xml:"FITID"`
+			} `xml:"BANKTRANLIST>STMTTRN"`
+			Balance string `xml:"LEDGERBAL>BALAMT"`
+		} `xml:"BANKMSGSRSV1>STMTTRNRS>STMTRS"`
+	}
+	if err = xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stmt := doc.Statement
+	if stmt.Currency != "GHS" || stmt.AccountID != "acc_1" || stmt.Balance != "60.10" || len(stmt.Transactions) != 3 {
+		t.Fatalf("unexpected statement: %+v", stmt)
+	}
+
+	debit := stmt.Transactions[1]
+	if debit.Type != "DEBIT" || debit.Amount != "-40.20" || debit.Posted != "20261015120000" || debit.ID != "ent_trf_1" {
+		t.Errorf("unexpected transaction: %+v", debit)
+	}
+}
+
+// TestWritePDF tests that statements are written as PDF documents whose cross-reference table points at
+// their objects, with the entries continued over as many pages as needed.
+func TestWritePDF(t *testing.T) {
+	testCases := []struct {
+		name          string
+		lines         int
+		expectedPages int
+	}{
+		{name: "one page", lines: 3, expectedPages: 1},
+		{name: "several pages", lines: 150, expectedPages: 3},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			var posted []*ledger.Entry
+			for i := 0; i < tc.lines; i++ {
+				posted = append(posted, entry(fmt.Sprintf("tx_%d", i), ledger.EntryTypeCredit, 1, 1+i%30))
+			}
+			posted[0].Description = "refund (partial) \\ café"
+			s := statement.New("acc_1", "GHS", october, october.AddDate(0, 1, 0), posted)
+			var buf bytes.Buffer
+
+			// Act
+			err := statement.WritePDF(&buf, s, "Quantia")
+
+			// Assert
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			data := buf.String()
+			if !strings.HasPrefix(data, "%PDF-1.4\n") || !strings.HasSuffix(data, "%%EOF\n") {
+				t.Fatalf("expected a PDF document, got:\n%s", data)
+			}
+
+			if pages := regexp.MustCompile(`/Count (\d+)`).FindStringSubmatch(data); pages == nil || pages[1] != strconv.Itoa(tc.expectedPages) {
+				t.Errorf("expected %d pages, got: %v", tc.expectedPages, pages)
+			}
+
+			if !strings.Contains(data, `(Quantia account statement)`) || !strings.Contains(data, `(refund \(partial\) \\ caf?)`) {
+				t.Errorf("expected the title and the escaped description, got:\n%s", data)
+			}
+
+			// every object listed in the cross-reference table starts at its offset
+			startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindStringSubmatch(data)
+			if startxref == nil {
+				t.Fatal("expected a startxref")
+			}
+			xref, _ := strconv.Atoi(startxref[1])
+			offsets := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllStringSubmatch(data[xref:], -1)
+			if len(offsets) != 4+2*tc.expectedPages {
+				t.Fatalf("expected %d objects, got: %d", 4+2*tc.expectedPages, len(offsets))
+			}
+			for i, offset := range offsets {
+				at, _ := strconv.Atoi(offset[1])
+				if header := fmt.Sprintf("%d 0 obj", i+1); !strings.HasPrefix(data[at:], header) {
+					t.Errorf("expected %q at offset %d", header, at)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package unit
+
+import (
+	"errors"
+	"github.com/quabynah-bilson/quantia/pkg"
+	"github.com/quabynah-bilson/quantia/pkg/ledger"
+	"github.com/quabynah-bilson/quantia/pkg/statement"
+	ledgerMocks "github.com/quabynah-bilson/quantia/tests/ledger/mocks"
+	"github.com/quabynah-bilson/quantia/tests/statement/mocks"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newStatementUseCase creates a statement use case over a ledger holding the entries of acc_1, a transfer of
+// acc_2 in November, and an escrow account
+func newStatementUseCase(t *testing.T) (*pkg.StatementUseCase, *mocks.MockStatementRepository) {
+	t.Helper()
+
+	ledgerRepo := ledgerMocks.NewMockLedgerRepository()
+	for _, e := range entries() {
+		counterpart := *e
+		counterpart.ID, counterpart.AccountID = e.ID+"_system", "system:funding"
+		counterpart.Type = ledger.EntryTypeDebit
+		if e.Type == ledger.EntryTypeDebit {
+			counterpart.Type = ledger.EntryTypeCredit
+		}
+		if err := ledgerRepo.Post(e, &counterpart); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	late := ledger.NewTransfer("trf_2", "transfer", "acc_1", "acc_2", 5)
+	late[0].CreatedAt, late[1].CreatedAt = october.AddDate(0, 1, 3), october.AddDate(0, 1, 3)
+	escrow := ledger.NewTransfer("esc_1", "escrow", "acc_1", ledger.EscrowAccountID("esc_1"), 1)
+	escrow[0].CreatedAt, escrow[1].CreatedAt = october.AddDate(0, 0, 20), october.AddDate(0, 0, 20)
+	for _, posting := range [][]*ledger.Entry{late, escrow} {
+		if err := ledgerRepo.Post(posting...); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	statementRepo := mocks.NewMockStatementRepository()
+	return pkg.NewStatementUseCase(statementRepo, ledgerRepo, pkg.StatementConfig{Currency: "GHS", MaxPeriod: 31 * 24 * time.Hour}), statementRepo
+}
+
+// TestGenerate tests statements generated on demand, from one day to another, both included.
+func TestGenerate(t *testing.T) {
+	testCases := []struct {
+		name            string
+		from            string
+		to              string
+		expectedErr     error
+		expectedLines   int
+		expectedClosing float32
+	}{
+		{name: "whole month", from: "2026-10-01", to: "2026-10-31", expectedLines: 4, expectedClosing: 59.1},
+		{name: "one day", from: "2026-10-15", to: "2026-10-15", expectedLines: 1, expectedClosing: 59.9},
+		{name: "month of to", to: "2026-10-10", expectedLines: 1, expectedClosing: 100.1},
+		{name: "from after to", from: "2026-10-16", to: "2026-10-15", expectedErr: pkg.ErrInvalidStatementPeriod},
+		{name: "unreadable date", from: "01/10/2026", to: "2026-10-31", expectedErr: pkg.ErrInvalidStatementPeriod},
+		{name: "too long", from: "2026-09-01", to: "2026-10-31", expectedErr: pkg.ErrStatementPeriodTooLong},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			useCase, _ := newStatementUseCase(t)
+
+			// Act
+			s, err := useCase.Generate("acc_1", tc.from, tc.to)
+
+			// Assert
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error: %v, got: %v", tc.expectedErr, err)
+			}
+
+			if tc.expectedErr == nil && (len(s.Lines) != tc.expectedLines || s.ClosingBalance != tc.expectedClosing || s.Currency != "GHS") {
+				t.Errorf("expected %d lines closing at %v, got: %+v", tc.expectedLines, tc.expectedClosing, s)
+			}
+		})
+	}
+}
+
+// TestRunMonthly tests that the statements of the month that ended are stored once for each customer account
+// that existed by its end, and can be downloaded later.
+func TestRunMonthly(t *testing.T) {
+	// Arrange
+	useCase, statementRepo := newStatementUseCase(t)
+	now := time.Date(2026, 11, 1, 2, 0, 0, 0, time.UTC)
+
+	// Act
+	generated := useCase.RunMonthly(now)
+	again := useCase.RunMonthly(now.Add(time.Hour))
+
+	// Assert
+	if generated != 1 || again != 0 || len(statementRepo.Statements) != 1 {
+		t.Fatalf("expected one statement to be generated once, got: %d then %d (%d stored)", generated, again, len(statementRepo.Statements))
+	}
+
+	summaries, err := useCase.GetMonthlyStatements("acc_1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(summaries) != 1 || summaries[0].Period != "2026-10" || summaries[0].Lines != nil || summaries[0].ClosingBalance != 59.1 {
+		t.Errorf("unexpected summaries: %+v", summaries)
+	}
+
+	stored, err := useCase.GetMonthlyStatement("acc_1", "2026-10")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := useCase.Export(stored, statement.FormatCSV)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(data), "2026-10-21T00:00:00Z,esc_1,escrow,1.00,,58.90\n2026-10-31T12:00:00Z,tx_2,payment tx_2,,0.20,59.10\n2026-10-31,,Closing balance,41.20,0.30,59.10") {
+		t.Errorf("unexpected file:\n%s", data)
+	}
+
+	if _, err = useCase.GetMonthlyStatement("acc_2", "2026-10"); !errors.Is(err, statement.ErrStatementNotFound) {
+		t.Errorf("expected error: %v, got: %v", statement.ErrStatementNotFound, err)
+	}
+
+	if generated = useCase.RunMonthly(time.Date(2026, 12, 5, 0, 0, 0, 0, time.UTC)); generated != 2 {
+		t.Errorf("expected November statements for acc_1 and acc_2, got: %d", generated)
+	}
+}
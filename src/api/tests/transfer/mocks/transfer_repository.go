@@ -0,0 +1,61 @@
+package mocks
+
+import (
+	"github.com/quabynah-bilson/quantia/pkg/transfer"
+	"sort"
+	"sync"
+)
+
+// MockTransferRepository is an in-memory transfer repository
+type MockTransferRepository struct {
+	mu        sync.Mutex
+	Transfers map[string]*transfer.Transfer
+
+	// SaveErr, when set, is returned by Save instead of storing the transfer
+	SaveErr error
+}
+
+// NewMockTransferRepository creates an empty in-memory transfer repository
+func NewMockTransferRepository() *MockTransferRepository {
+	return &MockTransferRepository{Transfers: make(map[string]*transfer.Transfer)}
+}
+
+// Save stores a copy of the transfer
+func (m *MockTransferRepository) Save(t *transfer.Transfer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.SaveErr != nil {
+		return m.SaveErr
+	}
+	copied := *t
+	m.Transfers[t.ID] = &copied
+	return nil
+}
+
+// Find returns a copy of the transfer
+func (m *MockTransferRepository) Find(id string) (*transfer.Transfer, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t, ok := m.Transfers[id]
+	if !ok {
+		return nil, transfer.ErrTransferNotFound
+	}
+	copied := *t
+	return &copied, nil
+}
+
+// FindByDate returns copies of the transfers made on the business date, oldest first
+func (m *MockTransferRepository) FindByDate(date string) ([]*transfer.Transfer, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	transfers := []*transfer.Transfer{}
+	for _, t := range m.Transfers {
+		if t.CreatedAt.UTC().Format("2006-01-02") == date {
+			copied := *t
+			transfers = append(transfers, &copied)
+		}
+	}
+	sort.Slice(transfers, func(i, j int) bool { return transfers[i].CreatedAt.Before(transfers[j].CreatedAt) })
+	return transfers, nil
+}
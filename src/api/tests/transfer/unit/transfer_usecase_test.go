@@ -0,0 +1,480 @@
+package unit
+
+import (
+	"errors"
+	"github.com/quabynah-bilson/quantia/adapters/payment/provider"
+	"github.com/quabynah-bilson/quantia/pkg"
+	"github.com/quabynah-bilson/quantia/pkg/beneficiary"
+	"github.com/quabynah-bilson/quantia/pkg/event"
+	"github.com/quabynah-bilson/quantia/pkg/iso20022"
+	"github.com/quabynah-bilson/quantia/pkg/ledger"
+	"github.com/quabynah-bilson/quantia/pkg/limit"
+	"github.com/quabynah-bilson/quantia/pkg/overdraft"
+	"github.com/quabynah-bilson/quantia/pkg/payment"
+	"github.com/quabynah-bilson/quantia/pkg/screening"
+	"github.com/quabynah-bilson/quantia/pkg/transfer"
+	beneficiaryMocks "github.com/quabynah-bilson/quantia/tests/beneficiary/mocks"
+	ledgerMocks "github.com/quabynah-bilson/quantia/tests/ledger/mocks"
+	limitMocks "github.com/quabynah-bilson/quantia/tests/limit/mocks"
+	overdraftMocks "github.com/quabynah-bilson/quantia/tests/overdraft/mocks"
+	paymentMocks "github.com/quabynah-bilson/quantia/tests/payment/mocks"
+	productMocks "github.com/quabynah-bilson/quantia/tests/product/mocks"
+	screeningMocks "github.com/quabynah-bilson/quantia/tests/screening/mocks"
+	"github.com/quabynah-bilson/quantia/tests/transfer/mocks"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// names are the holders of the destinations the beneficiaries of the tests are added for
+var names = map[string]string{
+	"acc_2":                     "Kofi Mensah",
+	paymentMocks.Wallet:         "Ama Owusu",
+	paymentMocks.DeclinedWallet: "Yaw Asante",
+}
+
+// testCase is a struct that represents a test case.
+type testCase struct {
+	name             string
+	destinationType  beneficiary.DestinationType
+	destination      string
+	amount           float32
+	coolingOff       time.Duration
+	expectedErr      error
+	expectedStatus   transfer.Status
+	expectedBalances [2]float32
+	expectedEvents   []event.Type
+}
+
+// addBeneficiary saves a beneficiary of acc_1 for the destination.
+func addBeneficiary(t *testing.T, beneficiaries *pkg.BeneficiaryUseCase, destinationType beneficiary.DestinationType, destination string) *beneficiary.Beneficiary {
+	t.Helper()
+
+	b, err := beneficiaries.AddBeneficiary("acc_1", names[destination], destinationType, destination, "", names[destination])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return b
+}
+
+// TestTransferUseCase_Internal tests transfers between accounts of the ledger.
+func TestTransferUseCase_Internal(t *testing.T) {
+	testCases := []testCase{
+		{
+			name:             "completed",
+			amount:           40,
+			expectedBalances: [2]float32{60, 40},
+			expectedEvents:   []event.Type{event.TypeTransferCompleted},
+		},
+		{
+			name:             "insufficient funds",
+			amount:           150,
+			expectedErr:      ledger.ErrInsufficientFunds,
+			expectedBalances: [2]float32{100, 0},
+		},
+		{
+			name:             "large amount to a new beneficiary",
+			amount:           50,
+			coolingOff:       time.Hour,
+			expectedErr:      pkg.ErrBeneficiaryCoolingOff,
+			expectedBalances: [2]float32{100, 0},
+		},
+		{
+			name:             "invalid amount",
+			amount:           -5,
+			expectedErr:      pkg.ErrInvalidAmount,
+			expectedBalances: [2]float32{100, 0},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			ledgerRepo := ledgerMocks.NewMockLedgerRepository()
+			ledgerRepo.Fund("acc_1", 100)
+			beneficiaries := beneficiaryMocks.NewBeneficiaryUseCase(names, tc.coolingOff)
+			transferUseCase := pkg.NewTransferUseCase(mocks.NewMockTransferRepository(), ledgerRepo, beneficiaries, nil)
+			b := addBeneficiary(t, beneficiaries, beneficiary.DestinationInternalAccount, "acc_2")
+
+			paymentRepo := paymentMocks.NewMockPaymentRepository()
+			transferUseCase.SetNotifications(pkg.NewPaymentUseCase(paymentRepo, ledgerRepo, &paymentMocks.MockURLGuard{}, provider.NewSimulator(provider.SimulatorConfig{})))
+
+			// Act
+			tr, err := transferUseCase.Transfer("acc_1", b.ID, tc.amount, "rent", "https://quantia-webhooks.com")
+
+			// Assert
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error: %v, got: %v", tc.expectedErr, err)
+			}
+
+			if tc.expectedErr == nil && tr.Status != transfer.StatusCompleted {
+				t.Errorf("expected status: %s, got: %s", transfer.StatusCompleted, tr.Status)
+			}
+
+			balances := [2]float32{ledgerRepo.Available("acc_1"), ledgerRepo.Available("acc_2")}
+			if balances != tc.expectedBalances {
+				t.Errorf("expected balances: %v, got: %v", tc.expectedBalances, balances)
+			}
+
+			if types := paymentRepo.EventTypes(); !reflect.DeepEqual(types, tc.expectedEvents) {
+				t.Errorf("expected events: %v, got: %v", tc.expectedEvents, types)
+			}
+		})
+	}
+}
+
+// TestTransferUseCase_Overdraft tests that transfers may take an account into its overdraft, which it is
+// notified of, but not beyond its limit.
+func TestTransferUseCase_Overdraft(t *testing.T) {
+	testCases := []testCase{
+		{
+			name:             "within the balance",
+			amount:           80,
+			expectedBalances: [2]float32{20, 80},
+		},
+		{
+			name:             "into the overdraft",
+			amount:           150,
+			expectedBalances: [2]float32{-50, 150},
+			expectedEvents:   []event.Type{event.TypeAccountOverdrawn},
+		},
+		{
+			name:             "beyond the overdraft",
+			amount:           250,
+			expectedErr:      ledger.ErrInsufficientFunds,
+			expectedBalances: [2]float32{100, 0},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			ledgerRepo := ledgerMocks.NewMockLedgerRepository()
+			ledgerRepo.Fund("acc_1", 100)
+			beneficiaries := beneficiaryMocks.NewBeneficiaryUseCase(names, 0)
+			transferUseCase := pkg.NewTransferUseCase(mocks.NewMockTransferRepository(), ledgerRepo, beneficiaries, nil)
+			b := addBeneficiary(t, beneficiaries, beneficiary.DestinationInternalAccount, "acc_2")
+
+			overdraftRepo, paymentRepo := overdraftMocks.NewMockOverdraftRepository(), paymentMocks.NewMockPaymentRepository()
+			_ = overdraftRepo.Save(overdraft.NewFacility("acc_1", 100, "https://bank.example.com/overdrafts"))
+			_ = ledgerRepo.SetOverdraftLimit("acc_1", 100)
+			transferUseCase.SetOverdrafts(pkg.NewOverdraftUseCase(overdraftRepo, productMocks.NewMockProductRepository(), ledgerRepo, paymentRepo))
+
+			// Act
+			_, err := transferUseCase.Transfer("acc_1", b.ID, tc.amount, "rent", "")
+
+			// Assert
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error: %v, got: %v", tc.expectedErr, err)
+			}
+
+			balances := [2]float32{ledgerRepo.Available("acc_1"), ledgerRepo.Available("acc_2")}
+			if balances != tc.expectedBalances {
+				t.Errorf("expected balances: %v, got: %v", tc.expectedBalances, balances)
+			}
+
+			if types := paymentRepo.EventTypes(); !reflect.DeepEqual(types, tc.expectedEvents) {
+				t.Errorf("expected events: %v, got: %v", tc.expectedEvents, types)
+			}
+		})
+	}
+}
+
+// TestTransferUseCase_External tests transfers paid out through the provider.
+func TestTransferUseCase_External(t *testing.T) {
+	testCases := []testCase{
+		{
+			name:             "disbursed",
+			destination:      paymentMocks.Wallet,
+			expectedStatus:   transfer.StatusCompleted,
+			expectedBalances: [2]float32{70, 0},
+			expectedEvents:   []event.Type{event.TypeTransferCompleted},
+		},
+		{
+			name:             "declined and refunded",
+			destination:      paymentMocks.DeclinedWallet,
+			expectedErr:      payment.ErrDisbursementDeclined,
+			expectedStatus:   transfer.StatusFailed,
+			expectedBalances: [2]float32{100, 0},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			ledgerRepo := ledgerMocks.NewMockLedgerRepository()
+			ledgerRepo.Fund("acc_1", 100)
+			beneficiaries := beneficiaryMocks.NewBeneficiaryUseCase(names, 0)
+			simulator := paymentMocks.NewSimulator(provider.SimulatorConfig{})
+			transferUseCase := pkg.NewTransferUseCase(mocks.NewMockTransferRepository(), ledgerRepo, beneficiaries, simulator)
+			paymentMocks.RouteResults(ledgerRepo, simulator, transfer.IsTransferReference, transferUseCase.HandleProviderResult)
+			b := addBeneficiary(t, beneficiaries, beneficiary.DestinationMobileWallet, tc.destination)
+
+			paymentRepo := paymentMocks.NewMockPaymentRepository()
+			transferUseCase.SetNotifications(pkg.NewPaymentUseCase(paymentRepo, ledgerRepo, &paymentMocks.MockURLGuard{}, simulator))
+
+			// Act
+			tr, err := transferUseCase.Transfer("acc_1", b.ID, 30, "", "https://quantia-webhooks.com")
+
+			// Assert
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error: %v, got: %v", tc.expectedErr, err)
+			}
+
+			if tr.Status != tc.expectedStatus {
+				t.Errorf("expected status: %s, got: %s", tc.expectedStatus, tr.Status)
+			}
+
+			balances := [2]float32{ledgerRepo.Available("acc_1"), ledgerRepo.Available("acc_2")}
+			if balances != tc.expectedBalances {
+				t.Errorf("expected balances: %v, got: %v", tc.expectedBalances, balances)
+			}
+
+			if types := paymentRepo.EventTypes(); !reflect.DeepEqual(types, tc.expectedEvents) {
+				t.Errorf("expected events: %v, got: %v", tc.expectedEvents, types)
+			}
+		})
+	}
+}
+
+// TestTransferUseCase_AsyncResult tests that pending transfers are completed by the provider's result.
+func TestTransferUseCase_AsyncResult(t *testing.T) {
+	// Arrange
+	ledgerRepo := ledgerMocks.NewMockLedgerRepository()
+	ledgerRepo.Fund("acc_1", 100)
+	beneficiaries := beneficiaryMocks.NewBeneficiaryUseCase(names, 0)
+	simulator := paymentMocks.NewSimulator(provider.SimulatorConfig{
+		Behaviour:    provider.BehaviourAsync,
+		AsyncDelay:   10 * time.Millisecond,
+		AsyncOutcome: provider.BehaviourDecline,
+	})
+	transferUseCase := pkg.NewTransferUseCase(mocks.NewMockTransferRepository(), ledgerRepo, beneficiaries, simulator)
+	paymentMocks.RouteResults(ledgerRepo, simulator, transfer.IsTransferReference, transferUseCase.HandleProviderResult)
+	b := addBeneficiary(t, beneficiaries, beneficiary.DestinationMobileWallet, paymentMocks.Wallet)
+
+	// Act
+	tr, err := transferUseCase.Transfer("acc_1", b.ID, 30, "", "")
+	if err != nil || tr.Status != transfer.StatusPending {
+		t.Fatalf("expected a pending transfer, got: %v %v", tr, err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if tr, _ = transferUseCase.GetTransfer(tr.ID); tr.Status != transfer.StatusPending {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// Assert
+	if tr.Status != transfer.StatusFailed {
+		t.Errorf("expected status: %s, got: %s", transfer.StatusFailed, tr.Status)
+	}
+
+	if funds := ledgerRepo.Available("acc_1"); funds != 100 {
+		t.Errorf("expected the declined transfer to be refunded, got available balance: %v", funds)
+	}
+}
+
+// TestTransferUseCase_SaveFailure tests that a transfer that cannot be saved gives the amount back to the account.
+func TestTransferUseCase_SaveFailure(t *testing.T) {
+	testCases := []testCase{
+		{
+			name:            "internal",
+			destinationType: beneficiary.DestinationInternalAccount,
+			destination:     "acc_2",
+		},
+		{
+			name:            "external",
+			destinationType: beneficiary.DestinationMobileWallet,
+			destination:     paymentMocks.Wallet,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			ledgerRepo := ledgerMocks.NewMockLedgerRepository()
+			ledgerRepo.Fund("acc_1", 100)
+			beneficiaries := beneficiaryMocks.NewBeneficiaryUseCase(names, 0)
+			simulator := paymentMocks.NewSimulator(provider.SimulatorConfig{})
+			transferRepo := mocks.NewMockTransferRepository()
+			transferUseCase := pkg.NewTransferUseCase(transferRepo, ledgerRepo, beneficiaries, simulator)
+			paymentMocks.RouteResults(ledgerRepo, simulator, transfer.IsTransferReference, transferUseCase.HandleProviderResult)
+			b := addBeneficiary(t, beneficiaries, tc.destinationType, tc.destination)
+			saveErr := errors.New("connection refused")
+			transferRepo.SaveErr = saveErr
+
+			// Act
+			_, err := transferUseCase.Transfer("acc_1", b.ID, 40, "rent", "")
+
+			// Assert
+			if !errors.Is(err, saveErr) {
+				t.Fatalf("expected error: %v, got: %v", saveErr, err)
+			}
+
+			balances := [3]float32{ledgerRepo.Available("acc_1"), ledgerRepo.Available("acc_2"), ledgerRepo.Available(ledger.PayoutsClearingAccountID)}
+			if balances != [3]float32{100, 0, 0} {
+				t.Errorf("expected the amount to be given back, got balances: %v", balances)
+			}
+		})
+	}
+}
+
+// TestTransferUseCase_PayoutsNotSupported tests that external transfers need a payout provider.
+func TestTransferUseCase_PayoutsNotSupported(t *testing.T) {
+	// Arrange
+	ledgerRepo := ledgerMocks.NewMockLedgerRepository()
+	ledgerRepo.Fund("acc_1", 100)
+	beneficiaries := beneficiaryMocks.NewBeneficiaryUseCase(names, 0)
+	transferUseCase := pkg.NewTransferUseCase(mocks.NewMockTransferRepository(), ledgerRepo, beneficiaries, nil)
+	b := addBeneficiary(t, beneficiaries, beneficiary.DestinationMobileWallet, paymentMocks.Wallet)
+
+	// Act
+	_, err := transferUseCase.Transfer("acc_1", b.ID, 30, "", "")
+
+	// Assert
+	if !errors.Is(err, pkg.ErrPayoutsNotSupported) {
+		t.Errorf("expected error: %v, got: %v", pkg.ErrPayoutsNotSupported, err)
+	}
+
+	if funds := ledgerRepo.Available("acc_1"); funds != 100 {
+		t.Errorf("expected the account not to be debited, got available balance: %v", funds)
+	}
+}
+
+// TestTransferUseCase_Limits tests that transfers count against the limits of their account.
+func TestTransferUseCase_Limits(t *testing.T) {
+	// Arrange
+	ledgerRepo := ledgerMocks.NewMockLedgerRepository()
+	ledgerRepo.Fund("acc_1", 100)
+	beneficiaries := beneficiaryMocks.NewBeneficiaryUseCase(names, 0)
+	simulator := paymentMocks.NewSimulator(provider.SimulatorConfig{})
+	transferUseCase := pkg.NewTransferUseCase(mocks.NewMockTransferRepository(), ledgerRepo, beneficiaries, simulator)
+	paymentMocks.RouteResults(ledgerRepo, simulator, transfer.IsTransferReference, transferUseCase.HandleProviderResult)
+	transferUseCase.SetLimits(pkg.NewLimitUseCase(limitMocks.NewMockLimitRepository(), &limit.Config{
+		DefaultTier: "standard",
+		Tiers:       map[limit.Tier]limit.Limits{"standard": {PerTransaction: 40, DailyCount: 2}},
+	}))
+	internal := addBeneficiary(t, beneficiaries, beneficiary.DestinationInternalAccount, "acc_2")
+	declined := addBeneficiary(t, beneficiaries, beneficiary.DestinationMobileWallet, paymentMocks.DeclinedWallet)
+
+	// Act
+	_, perTransactionErr := transferUseCase.Transfer("acc_1", internal.ID, 45, "", "")
+	_, declinedErr := transferUseCase.Transfer("acc_1", declined.ID, 10, "", "")
+	_, firstErr := transferUseCase.Transfer("acc_1", internal.ID, 10, "", "")
+	_, secondErr := transferUseCase.Transfer("acc_1", internal.ID, 10, "", "")
+	_, countErr := transferUseCase.Transfer("acc_1", internal.ID, 10, "", "")
+
+	// Assert
+	var exceeded *limit.ExceededError
+	if !errors.As(perTransactionErr, &exceeded) || exceeded.Kind != limit.KindPerTransaction {
+		t.Errorf("expected the per-transaction limit to be exceeded, got: %v", perTransactionErr)
+	}
+
+	if !errors.Is(declinedErr, payment.ErrDisbursementDeclined) || firstErr != nil || secondErr != nil {
+		t.Errorf("expected the declined transfer not to count, got: %v %v %v", declinedErr, firstErr, secondErr)
+	}
+
+	if !errors.As(countErr, &exceeded) || exceeded.Kind != limit.KindDailyCount || exceeded.ResetAt == nil {
+		t.Errorf("expected the daily count to be exceeded, got: %v", countErr)
+	}
+
+	if funds := ledgerRepo.Available("acc_1"); funds != 80 {
+		t.Errorf("expected available balance: 80, got: %v", funds)
+	}
+}
+
+// TestTransferUseCase_Screening tests that transfers wait for the review of beneficiaries matching a sanctions list.
+func TestTransferUseCase_Screening(t *testing.T) {
+	// Arrange
+	ledgerRepo := ledgerMocks.NewMockLedgerRepository()
+	ledgerRepo.Fund("acc_1", 100)
+	beneficiaries := beneficiaryMocks.NewBeneficiaryUseCase(names, 0)
+	transferUseCase := pkg.NewTransferUseCase(mocks.NewMockTransferRepository(), ledgerRepo, beneficiaries, nil)
+	watchlist := screening.NewWatchlist(screening.Config{}, []screening.Entry{{ID: "1001", List: "internal", Name: "Kofi Mensah"}})
+	screeningUseCase := pkg.NewScreeningUseCase(screeningMocks.NewMockScreeningRepository(), watchlist)
+	beneficiaries.SetScreening(screeningUseCase)
+	transferUseCase.SetScreening(screeningUseCase)
+	if _, err := screeningUseCase.ScreenAccount("acc_1", "Ama Owusu"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b := addBeneficiary(t, beneficiaries, beneficiary.DestinationInternalAccount, "acc_2")
+
+	// Act
+	_, pendingErr := transferUseCase.Transfer("acc_1", b.ID, 10, "", "")
+	held, _ := screeningUseCase.GetPartyScreening(screening.PartyBeneficiary, b.ID)
+	if _, err := screeningUseCase.Review(held.ID, false, "compliance@quantia.com", "different person"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, dismissedErr := transferUseCase.Transfer("acc_1", b.ID, 10, "", "")
+
+	// Assert
+	if !errors.Is(pendingErr, screening.ErrScreeningPending) {
+		t.Errorf("expected error: %v, got: %v", screening.ErrScreeningPending, pendingErr)
+	}
+
+	if dismissedErr != nil {
+		t.Errorf("expected the transfer after the dismissal to go through, got: %v", dismissedErr)
+	}
+
+	if funds := ledgerRepo.Available("acc_1"); funds != 90 {
+		t.Errorf("expected available balance: 90, got: %v", funds)
+	}
+}
+
+// TestTransferUseCase_ExportPain001 tests that the day's transfers to bank accounts are exported for the partner bank.
+func TestTransferUseCase_ExportPain001(t *testing.T) {
+	// Arrange
+	transferRepo := mocks.NewMockTransferRepository()
+	transferUseCase := pkg.NewTransferUseCase(transferRepo, ledgerMocks.NewMockLedgerRepository(), beneficiaryMocks.NewBeneficiaryUseCase(names, 0), nil)
+	day := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	for i, tr := range []*transfer.Transfer{
+		{ID: "trf_1", DestinationType: beneficiary.DestinationBankAccount, Destination: "1441000123456", BankCode: "030100", BeneficiaryName: "Kofi Mensah", Amount: 40, Note: "rent", Status: transfer.StatusPending},
+		{ID: "trf_2", DestinationType: beneficiary.DestinationBankAccount, Destination: "1441000654321", BankCode: "030100", BeneficiaryName: "Yaw Asante", Amount: 15, Status: transfer.StatusFailed},
+		{ID: "trf_3", DestinationType: beneficiary.DestinationMobileWallet, Destination: paymentMocks.Wallet, BeneficiaryName: "Ama Owusu", Amount: 20, Status: transfer.StatusCompleted},
+		{ID: "trf_4", DestinationType: beneficiary.DestinationBankAccount, Destination: "DE89370400440532013000", BankCode: "DEUTDEFF", BeneficiaryName: "Ama Owusu", Amount: 12.5, Status: transfer.StatusCompleted},
+	} {
+		tr.CreatedAt = day.Add(time.Duration(i) * time.Minute)
+		_ = transferRepo.Save(tr)
+	}
+
+	// Act
+	_, notConfiguredErr := transferUseCase.ExportPain001("2026-10-19")
+	transferUseCase.SetBankExport(&pkg.BankExportConfig{
+		Debtor:   iso20022.Debtor{Name: "Quantia Ltd", Account: "GB29NWBK60161331926819", Agent: "NWBKGB2L"},
+		Currency: "GHS",
+	})
+	data, err := transferUseCase.ExportPain001("2026-10-19")
+	_, emptyErr := transferUseCase.ExportPain001("2026-10-20")
+	_, dateErr := transferUseCase.ExportPain001("19/10/2026")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	doc, err := iso20022.ParsePain001(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []iso20022.CreditTransfer{
+		{EndToEndID: "trf1", Amount: 40, Currency: "GHS", CreditorName: "Kofi Mensah", CreditorAccount: "1441000123456", CreditorAgent: "030100", Remittance: "rent"},
+		{EndToEndID: "trf4", Amount: 12.5, Currency: "GHS", CreditorName: "Ama Owusu", CreditorAccount: "DE89370400440532013000", CreditorAgent: "DEUTDEFF"},
+	}
+	if transfers := doc.Transfers(); !reflect.DeepEqual(transfers, expected) {
+		t.Errorf("expected transfers: %+v, got: %+v", expected, transfers)
+	}
+
+	for _, tc := range []struct{ err, expected error }{
+		{notConfiguredErr, pkg.ErrBankExportNotConfigured},
+		{emptyErr, pkg.ErrNoTransfersToExport},
+		{dateErr, pkg.ErrInvalidExportDate},
+	} {
+		if !errors.Is(tc.err, tc.expected) {
+			t.Errorf("expected error: %v, got: %v", tc.expected, tc.err)
+		}
+	}
+}